@@ -0,0 +1,212 @@
+// Package actions keeps an append-only log of the agent sessions gt
+// spawns, resumes, and kills, with the exact command each was launched
+// with, so a launch can be looked up and replayed later.
+package actions
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/state"
+)
+
+// Filename is the action log in the per-machine state directory.
+const Filename = "actions.jsonl"
+
+// Action kinds.
+const (
+	KindSpawn  = "spawn"
+	KindResume = "resume"
+	KindKill   = "kill"
+)
+
+// Operations an action was carried out with.
+const (
+	OpNewSession  = "new-session"  // tmux new-session running Command
+	OpRespawnPane = "respawn-pane" // tmux respawn-pane -k running Command
+	OpKillSession = "kill-session" // tmux kill-session
+	OpExec        = "exec"         // Command run in the foreground
+)
+
+// Outcomes.
+const (
+	OutcomeOK     = "ok"
+	OutcomeFailed = "failed"
+)
+
+// Action is one recorded spawn, resume, or kill.
+type Action struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"`
+	Op      string    `json:"op"`
+	Target  string    `json:"target"` // tmux session or pane
+	WorkDir string    `json:"work_dir,omitempty"`
+	Command string    `json:"command,omitempty"` // including the startup prompt
+
+	// Invocation is the gt command line that took the action, and Actor
+	// the agent that ran it (empty for a human).
+	Invocation string `json:"invocation,omitempty"`
+	Actor      string `json:"actor,omitempty"`
+
+	Outcome  string        `json:"outcome"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+var enabled atomic.Bool
+
+// Enable turns on recording for this process. The gt binary enables it;
+// library callers and tests don't write to the user's log.
+func Enable() {
+	enabled.Store(true)
+}
+
+// Path returns the action log path.
+func Path() string {
+	return filepath.Join(state.StateDir(), Filename)
+}
+
+// KindOf classifies a launch command: resume when it continues a Claude
+// session, spawn otherwise.
+func KindOf(command string) string {
+	if strings.Contains(command, "--resume") || strings.Contains(command, "--continue") {
+		return KindResume
+	}
+	return KindSpawn
+}
+
+// Track starts timing an action and returns the function that records it
+// with err's outcome. It does nothing unless recording is enabled.
+func Track(kind, op, target, workDir, command string) func(err error) {
+	if !enabled.Load() {
+		return func(error) {}
+	}
+	start := time.Now()
+	return func(err error) {
+		a := Action{
+			Time:     start,
+			Kind:     kind,
+			Op:       op,
+			Target:   target,
+			WorkDir:  workDir,
+			Command:  command,
+			Outcome:  OutcomeOK,
+			Duration: time.Since(start),
+		}
+		if err != nil {
+			a.Outcome, a.Error = OutcomeFailed, err.Error()
+		}
+		_ = Record(a) // the log is best-effort; the action already happened
+	}
+}
+
+// Record appends a to the log, filling in its ID, time, invocation, and
+// actor when unset. Writers in parallel processes are serialized with a
+// file lock, so lines never interleave.
+func Record(a Action) error {
+	if a.Time.IsZero() {
+		a.Time = time.Now()
+	}
+	if a.ID == "" {
+		a.ID = newID()
+	}
+	if a.Invocation == "" && len(os.Args) > 0 {
+		a.Invocation = strings.Join(append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...), " ")
+	}
+	if a.Actor == "" {
+		a.Actor = os.Getenv("BD_ACTOR")
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking action log: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600) //nolint:gosec // G302: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening action log: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// newID returns a random 8-character hex action ID.
+func newID() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Load returns the actions taken at or after since, oldest first. A
+// missing log yields none.
+func Load(since time.Time) ([]Action, error) {
+	f, err := os.Open(Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening action log: %w", err)
+	}
+	defer f.Close()
+
+	var out []Action
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024) // startup prompts can be long
+	for scanner.Scan() {
+		var a Action
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil || a.ID == "" {
+			continue
+		}
+		if a.Time.Before(since) {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out, scanner.Err()
+}
+
+// Find returns the action with the given ID or unique ID prefix.
+func Find(id string) (*Action, error) {
+	all, err := Load(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	var match *Action
+	for i := range all {
+		if all[i].ID == id {
+			return &all[i], nil
+		}
+	}
+	for i := range all {
+		if strings.HasPrefix(all[i].ID, id) {
+			if match != nil {
+				return nil, fmt.Errorf("action ID %s is ambiguous", id)
+			}
+			match = &all[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("action %s not found", id)
+	}
+	return match, nil
+}
@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKindOf(t *testing.T) {
+	tests := map[string]string{
+		"claude --dangerously-skip-permissions 'beacon'": KindSpawn,
+		"claude --resume abc123":                         KindResume,
+		"exec env GT_ROLE=crew claude --continue":        KindResume,
+	}
+	for command, want := range tests {
+		if got := KindOf(command); got != want {
+			t.Errorf("KindOf(%q) = %s, want %s", command, got, want)
+		}
+	}
+}
+
+func TestTrackRecordsWhenEnabled(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	defer enabled.Store(false)
+
+	Track(KindSpawn, OpNewSession, "gt-gastown-crew-max", "/town/gastown/crew/max", "claude")(nil)
+	if all, _ := Load(time.Time{}); len(all) != 0 {
+		t.Fatalf("recorded %d actions while disabled", len(all))
+	}
+
+	Enable()
+	t.Setenv("BD_ACTOR", "gastown/witness")
+	Track(KindSpawn, OpNewSession, "gt-gastown-crew-max", "/town/gastown/crew/max", "claude 'do the thing'")(nil)
+	Track(KindKill, OpKillSession, "gt-gastown-crew-max", "", "")(errors.New("session not found"))
+
+	all, err := Load(time.Time{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("Load = %d actions, want 2", len(all))
+	}
+	spawn, kill := all[0], all[1]
+	if spawn.Kind != KindSpawn || spawn.Command != "claude 'do the thing'" || spawn.WorkDir != "/town/gastown/crew/max" ||
+		spawn.Outcome != OutcomeOK || spawn.Actor != "gastown/witness" || spawn.Invocation == "" || len(spawn.ID) != 8 {
+		t.Errorf("spawn = %+v", spawn)
+	}
+	if kill.Outcome != OutcomeFailed || kill.Error != "session not found" {
+		t.Errorf("kill = %+v", kill)
+	}
+
+	if a, err := Find(spawn.ID[:6]); err != nil || a.ID != spawn.ID {
+		t.Errorf("Find(prefix) = %+v, %v", a, err)
+	}
+	if _, err := Find("zzzz"); err == nil {
+		t.Error("Find of an unknown ID should fail")
+	}
+	if later, _ := Load(time.Now().Add(time.Hour)); len(later) != 0 {
+		t.Errorf("Load(future) = %d actions", len(later))
+	}
+}
+
+func TestRecordParallel(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Record(Action{Kind: KindSpawn, Op: OpNewSession, Target: "s", Outcome: OutcomeOK}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	all, err := Load(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != n {
+		t.Errorf("Load = %d actions, want %d intact lines", len(all), n)
+	}
+}
@@ -884,6 +884,7 @@ func TestAttachmentFieldsRoundTrip(t *testing.T) {
 	original := &AttachmentFields{
 		AttachedMolecule: "mol-roundtrip",
 		AttachedAt:       "2025-12-21T15:30:00Z",
+		AttachedTask:     "/town/tasks/retry.task.yaml",
 	}
 
 	// Format to string
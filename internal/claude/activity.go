@@ -0,0 +1,225 @@
+package claude
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// readTools are the tools that read files, mapped to their path input.
+var readTools = map[string]string{
+	"Read":         "file_path",
+	"NotebookRead": "notebook_path",
+}
+
+// Activity is what a session did through its tools: the files it read
+// and wrote, and the shell commands it ran.
+type Activity struct {
+	Session      *SessionInfo `json:"session"`
+	FilesRead    []FileTouch  `json:"files_read"`
+	FilesWritten []FileTouch  `json:"files_written"`
+	Commands     []Command    `json:"commands"`
+}
+
+// FileTouch is one file a session read or wrote, however many times.
+type FileTouch struct {
+	Path    string    `json:"path"`     // path as given to the tool
+	RelPath string    `json:"rel_path"` // path relative to the session's cwd
+	Count   int       `json:"count"`    // number of tool calls
+	First   time.Time `json:"first"`
+	Last    time.Time `json:"last"`
+}
+
+// Command is one Bash tool call.
+type Command struct {
+	Command     string    `json:"command"`
+	Description string    `json:"description,omitempty"`
+	Cwd         string    `json:"cwd,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+	Failed      bool      `json:"failed,omitempty"` // the tool result was an error
+	Sidechain   bool      `json:"sidechain,omitempty"`
+}
+
+// SessionActivity extracts the files a session read and wrote and the
+// commands it ran. ref is anything LoadTranscript accepts.
+func SessionActivity(ref string) (*Activity, error) {
+	t, err := LoadTranscript(ref)
+	if err != nil {
+		return nil, err
+	}
+	a := t.Activity()
+	a.Session = t.Session
+	return a, nil
+}
+
+// Activity extracts the transcript's tool activity. Files are listed in
+// the order first touched; commands in the order run.
+func (t *Transcript) Activity() *Activity {
+	a := &Activity{FilesRead: []FileTouch{}, FilesWritten: []FileTouch{}, Commands: []Command{}}
+	read := make(map[string]int)
+	written := make(map[string]int)
+
+	failed := make(map[string]bool)
+	for _, m := range t.Messages {
+		for _, r := range m.ToolResults {
+			if r.IsError {
+				failed[r.ToolUseID] = true
+			}
+		}
+	}
+
+	for _, m := range t.Messages {
+		for _, use := range m.ToolUses {
+			var input map[string]json.RawMessage
+			if err := json.Unmarshal(use.Input, &input); err != nil {
+				continue
+			}
+			str := func(key string) string {
+				var s string
+				_ = json.Unmarshal(input[key], &s)
+				return s
+			}
+
+			if key, ok := editTools[use.Name]; ok {
+				a.FilesWritten = touch(a.FilesWritten, written, str(key), m)
+			} else if key, ok := readTools[use.Name]; ok {
+				a.FilesRead = touch(a.FilesRead, read, str(key), m)
+			} else if use.Name == "Bash" {
+				if cmd := str("command"); cmd != "" {
+					a.Commands = append(a.Commands, Command{
+						Command:     cmd,
+						Description: str("description"),
+						Cwd:         m.Cwd,
+						Timestamp:   m.Timestamp,
+						Failed:      failed[use.ID],
+						Sidechain:   m.IsSidechain,
+					})
+				}
+			}
+		}
+	}
+	return a
+}
+
+// testCommands are the shell commands that run a test suite, matched as
+// whole words at the start of a command or after && ; |.
+var testCommands = []string{
+	"go test", "gotestsum", "pytest", "python -m pytest", "cargo test",
+	"npm test", "npm run test", "yarn test", "pnpm test", "bun test",
+	"jest", "vitest", "mocha", "rspec", "make test", "mvn test", "gradle test",
+}
+
+// IsTest reports whether the command runs a test suite.
+func (c Command) IsTest() bool {
+	for _, part := range splitCommand(c.Command) {
+		for _, tc := range testCommands {
+			if part == tc || strings.HasPrefix(part, tc+" ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsCommit reports whether the command makes a git commit.
+func (c Command) IsCommit() bool {
+	for _, part := range splitCommand(c.Command) {
+		if part == "git commit" || strings.HasPrefix(part, "git commit ") {
+			return true
+		}
+	}
+	return false
+}
+
+// splitCommand splits a shell command line at &&, ||, ;, |, and newlines
+// into its commands, with whitespace collapsed and leading environment
+// assignments (FOO=bar go test) dropped.
+func splitCommand(cmd string) []string {
+	fields := strings.FieldsFunc(cmd, func(r rune) bool {
+		return r == '&' || r == ';' || r == '|' || r == '\n'
+	})
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		words := strings.Fields(f)
+		for len(words) > 0 && strings.Contains(words[0], "=") {
+			words = words[1:] // FOO=bar go test
+		}
+		if len(words) > 0 {
+			parts = append(parts, strings.Join(words, " "))
+		}
+	}
+	return parts
+}
+
+// touch records a tool call on path, merging calls on the same file.
+func touch(files []FileTouch, index map[string]int, path string, m Message) []FileTouch {
+	if path == "" {
+		return files
+	}
+	if i, ok := index[path]; ok {
+		files[i].Count++
+		if m.Timestamp.After(files[i].Last) {
+			files[i].Last = m.Timestamp
+		}
+		return files
+	}
+	index[path] = len(files)
+	return append(files, FileTouch{
+		Path:    path,
+		RelPath: relativeTo(m.Cwd, path),
+		Count:   1,
+		First:   m.Timestamp,
+		Last:    m.Timestamp,
+	})
+}
+
+// Touched reports whether the session read or wrote a file matching path.
+// An absolute path must match exactly; a relative one matches any file
+// ending in its path elements, so "internal/cmd/seance.go" matches the
+// file in every checkout.
+func (a *Activity) Touched(path string) bool {
+	return a.Wrote(path) || matchTouch(a.FilesRead, path)
+}
+
+// Wrote reports whether the session modified a file matching path (see
+// Touched).
+func (a *Activity) Wrote(path string) bool {
+	return matchTouch(a.FilesWritten, path)
+}
+
+// Files returns every file the session read or wrote, by relative path,
+// sorted.
+func (a *Activity) Files() []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, list := range [][]FileTouch{a.FilesRead, a.FilesWritten} {
+		for _, f := range list {
+			if !seen[f.RelPath] {
+				seen[f.RelPath] = true
+				files = append(files, f.RelPath)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// matchTouch reports whether any file in files matches path.
+func matchTouch(files []FileTouch, path string) bool {
+	for _, f := range files {
+		if touchMatches(f.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// touchMatches reports whether a file a tool touched matches path (see
+// Touched).
+func touchMatches(file, path string) bool {
+	want := filepath.ToSlash(filepath.Clean(path))
+	got := filepath.ToSlash(file)
+	return got == want || (!filepath.IsAbs(path) && strings.HasSuffix(got, "/"+want))
+}
@@ -0,0 +1,78 @@
+package claude
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSessionActivity(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cwd := `"cwd":"/town/gastown/polecats/toast"`
+	writeSession(t, home, "-town-gastown-polecats-toast", "act12345.jsonl",
+		`{"type":"assistant","timestamp":"2025-01-01T00:00:00Z",`+cwd+`,"message":{"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/town/gastown/polecats/toast/internal/cmd/seance.go"}},{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test ./...","description":"Run tests"}}]}}`,
+		`{"type":"user","timestamp":"2025-01-01T00:00:01Z",`+cwd+`,"message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"FAIL","is_error":true}]}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:01:00Z",`+cwd+`,"message":{"content":[{"type":"tool_use","id":"t3","name":"Edit","input":{"file_path":"/town/gastown/polecats/toast/internal/cmd/seance.go"}},{"type":"tool_use","id":"t4","name":"Read","input":{"file_path":"/town/gastown/polecats/toast/internal/cmd/seance.go"}},{"type":"tool_use","id":"t5","name":"Grep","input":{"pattern":"x"}}]}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:02:00Z",`+cwd+`,"message":{"content":[{"type":"tool_use","id":"t6","name":"Bash","input":{"command":"go build ./..."}}]}}`,
+	)
+
+	a, err := SessionActivity("act1")
+	if err != nil {
+		t.Fatalf("SessionActivity: %v", err)
+	}
+	if a.Session == nil || a.Session.ID != "act12345" {
+		t.Errorf("session = %+v", a.Session)
+	}
+	if len(a.FilesRead) != 1 || a.FilesRead[0].Count != 2 || a.FilesRead[0].RelPath != "internal/cmd/seance.go" {
+		t.Errorf("files read = %+v", a.FilesRead)
+	}
+	if len(a.FilesWritten) != 1 || a.FilesWritten[0].Count != 1 {
+		t.Errorf("files written = %+v", a.FilesWritten)
+	}
+	if len(a.Commands) != 2 || !a.Commands[0].Failed || a.Commands[0].Description != "Run tests" || a.Commands[1].Failed {
+		t.Errorf("commands = %+v", a.Commands)
+	}
+
+	for path, want := range map[string]bool{
+		"internal/cmd/seance.go": true,
+		"cmd/seance.go":          true,
+		"seance.go":              true,
+		"ance.go":                false,
+		"/town/gastown/polecats/toast/internal/cmd/seance.go": true,
+		"/internal/cmd/seance.go":                             false,
+	} {
+		if got := a.Touched(path); got != want {
+			t.Errorf("Touched(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if a.Wrote("internal/cmd/other.go") {
+		t.Error("Wrote matched an untouched file")
+	}
+	if got := a.Files(); !reflect.DeepEqual(got, []string{"internal/cmd/seance.go"}) {
+		t.Errorf("Files() = %v", got)
+	}
+}
+
+func TestCommandIsTestAndCommit(t *testing.T) {
+	tests := []struct {
+		cmd          string
+		test, commit bool
+	}{
+		{"go test ./...", true, false},
+		{"cd internal && CGO_ENABLED=0 go test -run X", true, false},
+		{"go build ./... && go vet ./...", false, false},
+		{"npm run test -- --watch=false", true, false},
+		{"echo go test", false, false},
+		{"git add -A && git commit -m 'fix'", false, true},
+		{"git commit-tree HEAD", false, false},
+	}
+	for _, tt := range tests {
+		c := Command{Command: tt.cmd}
+		if got := c.IsTest(); got != tt.test {
+			t.Errorf("IsTest(%q) = %v, want %v", tt.cmd, got, tt.test)
+		}
+		if got := c.IsCommit(); got != tt.commit {
+			t.Errorf("IsCommit(%q) = %v, want %v", tt.cmd, got, tt.commit)
+		}
+	}
+}
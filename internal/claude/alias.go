@@ -0,0 +1,116 @@
+package claude
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Session aliases are human-friendly names ("auth-refactor-v2") accepted
+// anywhere a session ID is. They are kept in the annotations file with
+// hand-added tags and notes, so they survive index rebuilds and version
+// changes.
+
+// ValidateAlias reports whether alias can name a session. Aliases use
+// letters, digits, '-', '_', and '.', and must not look like a session
+// ID, so an alias never shadows an ID prefix.
+func ValidateAlias(alias string) error {
+	if alias == "" {
+		return fmt.Errorf("empty alias")
+	}
+	for _, r := range alias {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return fmt.Errorf("alias %q may only contain letters, digits, '-', '_', and '.'", alias)
+		}
+	}
+	if looksLikeSessionID(alias) {
+		return fmt.Errorf("alias %q looks like a session ID; use a name with a letter past 'f'", alias)
+	}
+	return nil
+}
+
+// looksLikeSessionID reports whether s could be a session ID or prefix:
+// hex digits and dashes, optionally after "agent-".
+func looksLikeSessionID(s string) bool {
+	s = strings.TrimPrefix(s, "agent-")
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// SetAlias makes alias name the session with the given full ID, replacing
+// whatever it named before. A session can have several aliases.
+func SetAlias(alias, id string) error {
+	if err := ValidateAlias(alias); err != nil {
+		return err
+	}
+	var changes []OverlayChange
+	err := updateAnnotations(func(a *sessionAnnotations) bool {
+		prev, had := a.Aliases[alias]
+		if had && prev == id {
+			return false
+		}
+		if a.Aliases == nil {
+			a.Aliases = make(map[string]string)
+		}
+		a.Aliases[alias] = id
+		changes = []OverlayChange{{Session: id, Kind: OverlayAlias, Op: OverlayAdd, Value: alias}}
+		if had {
+			changes = append(changes, OverlayChange{Session: prev, Kind: OverlayAlias, Op: OverlayRemove, Old: alias})
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return recordOverlayChanges(changes...)
+}
+
+// RemoveAlias deletes alias.
+func RemoveAlias(alias string) error {
+	var id string
+	err := updateAnnotations(func(a *sessionAnnotations) bool {
+		var ok bool
+		if id, ok = a.Aliases[alias]; ok {
+			delete(a.Aliases, alias)
+		}
+		return ok
+	})
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return fmt.Errorf("no session alias %q", alias)
+	}
+	return recordOverlayChanges(OverlayChange{Session: id, Kind: OverlayAlias, Op: OverlayRemove, Old: alias})
+}
+
+// Aliases returns every session alias, mapped to the session ID it names.
+func Aliases() map[string]string {
+	aliases := loadAnnotations().Aliases
+	if aliases == nil {
+		aliases = make(map[string]string)
+	}
+	return aliases
+}
+
+// ResolveAlias returns the session ID alias names, if it is an alias.
+func ResolveAlias(alias string) (string, bool) {
+	id, ok := loadAnnotations().Aliases[alias]
+	return id, ok
+}
+
+// AliasesOf returns the aliases naming the session id, sorted.
+func AliasesOf(id string) []string {
+	var out []string
+	for alias, target := range Aliases() {
+		if target == id {
+			out = append(out, alias)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,80 @@
+package claude
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestValidateAlias(t *testing.T) {
+	for _, alias := range []string{"auth-refactor-v2", "spike_1", "v1.2"} {
+		if err := ValidateAlias(alias); err != nil {
+			t.Errorf("ValidateAlias(%q) = %v, want ok", alias, err)
+		}
+	}
+	for _, alias := range []string{"", "has space", "a/b", "abc123", "dead-beef", "agent-a1b2"} {
+		if err := ValidateAlias(alias); err == nil {
+			t.Errorf("ValidateAlias(%q) accepted", alias)
+		}
+	}
+}
+
+func TestSessionAliases(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	writeSession(t, home, "-home-u-proj", "aaaa1111-2222.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":"hello"}}`,
+	)
+	if err := SetAlias("auth-refactor", "aaaa1111-2222"); err != nil {
+		t.Fatalf("SetAlias: %v", err)
+	}
+	if err := SetAlias("login", "aaaa1111-2222"); err != nil {
+		t.Fatalf("SetAlias: %v", err)
+	}
+
+	s, err := FindSession("auth-refactor")
+	if err != nil || s.ID != "aaaa1111-2222" {
+		t.Fatalf("FindSession(alias) = %+v, %v", s, err)
+	}
+	if got := AliasesOf("aaaa1111-2222"); len(got) != 2 || got[0] != "auth-refactor" || got[1] != "login" {
+		t.Errorf("AliasesOf = %v", got)
+	}
+
+	// Aliases outlive the cached parses.
+	if _, err := RebuildSessionIndex(nil); err != nil {
+		t.Fatalf("RebuildSessionIndex: %v", err)
+	}
+	if id, ok := ResolveAlias("auth-refactor"); !ok || id != "aaaa1111-2222" {
+		t.Errorf("after rebuild ResolveAlias = %q, %v", id, ok)
+	}
+
+	// An index from another version is discarded, but not its aliases.
+	data, err := os.ReadFile(SessionIndexPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	raw["version"] = sessionIndexVersion - 1
+	data, _ = json.Marshal(raw)
+	if err := os.WriteFile(SessionIndexPath(), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ResolveAlias("login"); !ok {
+		t.Error("alias lost when the index version changed")
+	}
+
+	if err := RemoveAlias("login"); err != nil {
+		t.Fatalf("RemoveAlias: %v", err)
+	}
+	if _, ok := ResolveAlias("login"); ok {
+		t.Error("removed alias still resolves")
+	}
+	if err := RemoveAlias("login"); err == nil {
+		t.Error("removing a missing alias should fail")
+	}
+}
@@ -0,0 +1,144 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// What's layered on sessions beyond their parse (aliases, tags, and notes
+// added by hand, and generated summaries, which cost a model call each)
+// isn't a cache, so it isn't kept in the session index, which discovery
+// rewrites without a lock whenever it parses a transcript. It lives in an
+// annotations file in the per-machine state directory instead. Writers
+// serialize on a file lock and read, change, and replace the file under
+// it; discovery only reads it, and since the file is replaced atomically,
+// never sees a partial one.
+
+// SessionAnnotationsFile is the annotations file in the state directory.
+const SessionAnnotationsFile = "session-annotations.json"
+
+// annotationsVersion is bumped when more moves from the session index to
+// the annotations file, so the next write to an older file moves it too.
+// Version 1 added generated summaries.
+const annotationsVersion = 1
+
+// sessionAnnotations is what's been layered on sessions.
+type sessionAnnotations struct {
+	Version int `json:"version,omitempty"`
+
+	// Aliases maps user-assigned session aliases to session IDs (see
+	// SetAlias).
+	Aliases map[string]string `json:"aliases,omitempty"`
+
+	// Tags maps session IDs to tags added by hand (see TagSession).
+	Tags map[string][]string `json:"tags,omitempty"`
+
+	// Notes maps session IDs to notes added by hand (see SetSessionNote).
+	Notes map[string]string `json:"notes,omitempty"`
+
+	// Summaries maps session IDs to summaries generated by
+	// GenerateSummary.
+	Summaries map[string]string `json:"summaries,omitempty"`
+}
+
+// SessionAnnotationsPath returns the annotations file.
+func SessionAnnotationsPath() string {
+	return filepath.Join(state.StateDir(), SessionAnnotationsFile)
+}
+
+// loadAnnotations reads the annotations file. Until a write brings it up
+// to date, what it lacks is read from the session index, where older
+// builds kept it.
+func loadAnnotations() *sessionAnnotations {
+	a, _ := readAnnotations()
+	return a
+}
+
+// readAnnotations reads the annotations file, filling in what an older
+// (or missing) file lacks from the session index, and reports whether the
+// file is up to date. An unreadable file yields no annotations.
+func readAnnotations() (*sessionAnnotations, bool) {
+	var a sessionAnnotations
+	data, err := os.ReadFile(SessionAnnotationsPath())
+	exists := err == nil
+	if exists {
+		_ = json.Unmarshal(data, &a)
+	} else if !os.IsNotExist(err) {
+		return &a, false
+	}
+	if a.Version >= annotationsVersion {
+		return &a, true
+	}
+
+	var legacy sessionAnnotations
+	if data, err := os.ReadFile(SessionIndexPath()); err == nil {
+		_ = json.Unmarshal(data, &legacy)
+	}
+	if !exists {
+		a = legacy
+	}
+	if a.Version < 1 {
+		a.Summaries = legacy.Summaries
+	}
+	a.Version = 0
+	return &a, false
+}
+
+// annotationsCurrent reports whether the annotations file is up to date,
+// so the session index no longer needs to carry its legacy annotations.
+func annotationsCurrent() bool {
+	var v struct {
+		Version int `json:"version"`
+	}
+	data, err := os.ReadFile(SessionAnnotationsPath())
+	return err == nil && json.Unmarshal(data, &v) == nil && v.Version >= annotationsVersion
+}
+
+// updateAnnotations applies change to the annotations under the file
+// lock, writing them back if change reports it changed them. An update to
+// an out-of-date file also moves the session index's legacy annotations
+// into it. A nil change only does that.
+func updateAnnotations(change func(a *sessionAnnotations) bool) error {
+	return withFileLock(SessionAnnotationsPath(), "session annotations", func() error {
+		a, current := readAnnotations()
+		changed := change != nil && change(a)
+		if current && !changed {
+			return nil
+		}
+		a.Version = annotationsVersion
+		if err := util.AtomicWriteJSON(SessionAnnotationsPath(), a); err != nil {
+			return fmt.Errorf("writing session annotations: %w", err)
+		}
+		return nil
+	})
+}
+
+// migrateAnnotations moves the session index's legacy annotations into
+// the annotations file, if that hasn't happened yet.
+func migrateAnnotations() error {
+	if annotationsCurrent() {
+		return nil
+	}
+	return updateAnnotations(nil)
+}
+
+// withFileLock runs fn holding path's lock file, so read-modify-write
+// updates of path from parallel processes don't lose each other's
+// changes. what names the file in errors.
+func withFileLock(path, what string, fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating %s directory: %w", what, err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking %s: %w", what, err)
+	}
+	defer func() { _ = lock.Unlock() }()
+	return fn()
+}
@@ -0,0 +1,150 @@
+package claude
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestTagDuringDiscovery(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	path := writeSession(t, home, "-home-u-proj", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":"hello"}}`,
+	)
+
+	const n = 20
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// Each pass finds the transcript changed, so discovery rewrites
+		// the index while tags are being added
+		for i := 0; i < n; i++ {
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+			if err == nil {
+				_, _ = fmt.Fprintf(f, `{"type":"assistant","timestamp":"2025-12-31T09:00:%02dZ"}`+"\n", i)
+				f.Close()
+			}
+			_, _ = DiscoverSessions(SessionFilter{})
+		}
+	}()
+	errs := make(chan error, 2*n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- TagSession("aaaa1111", fmt.Sprintf("t%02d", i))
+			errs <- SetAlias(fmt.Sprintf("run-%02d", i), "aaaa1111")
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("annotating: %v", err)
+		}
+	}
+
+	var want []string
+	for i := 0; i < n; i++ {
+		want = append(want, fmt.Sprintf("t%02d", i))
+	}
+	if got := TagsOf("aaaa1111"); !slices.Equal(got, want) {
+		t.Errorf("TagsOf = %v, want all %d tags", got, n)
+	}
+	if got := AliasesOf("aaaa1111"); len(got) != n {
+		t.Errorf("AliasesOf = %v, want all %d aliases", got, n)
+	}
+	sessions, err := DiscoverSessions(SessionFilter{})
+	if err != nil || len(sessions) != 1 || !slices.Equal(sessions[0].Tags, want) {
+		t.Errorf("DiscoverSessions = %+v, %v", sessions, err)
+	}
+}
+
+func TestAnnotationsMigrateFromIndex(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	// An index written by an older build, holding hand-added annotations
+	legacy := `{"version":1,"projects_dir":"x","aliases":{"login":"aaaa1111"},"tags":{"aaaa1111":["review"]},"notes":{"aaaa1111":"resume here"}}`
+	if err := os.MkdirAll(filepath.Dir(SessionIndexPath()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(SessionIndexPath(), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := TagsOf("aaaa1111"); !slices.Equal(got, []string{"review"}) {
+		t.Errorf("TagsOf before migration = %v", got)
+	}
+
+	// A reset moves them to the annotations file instead of dropping them
+	if err := InvalidateSessionIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if !annotationsCurrent() {
+		t.Fatal("annotations file not written")
+	}
+	if err := TagSession("aaaa1111", "incident:42"); err != nil {
+		t.Fatal(err)
+	}
+	if got := TagsOf("aaaa1111"); !slices.Equal(got, []string{"incident:42", "review"}) {
+		t.Errorf("TagsOf = %v", got)
+	}
+	if got := NoteOf("aaaa1111"); got != "resume here" {
+		t.Errorf("NoteOf = %q", got)
+	}
+	if id, ok := ResolveAlias("login"); !ok || id != "aaaa1111" {
+		t.Errorf("ResolveAlias = %q, %v", id, ok)
+	}
+}
+
+func TestSummariesMigrateFromIndex(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	// Tags already moved by an older build, summaries still in the index
+	for path, data := range map[string]string{
+		SessionIndexPath():       `{"version":1,"projects_dir":"x","summaries":{"aaaa1111":"fixed the login bug"}}`,
+		SessionAnnotationsPath(): `{"tags":{"aaaa1111":["review"]}}`,
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if annotationsCurrent() {
+		t.Fatal("version 0 annotations file reported current")
+	}
+	if got := loadAnnotations().Summaries["aaaa1111"]; got != "fixed the login bug" {
+		t.Errorf("summary before migration = %q", got)
+	}
+
+	// The next write moves the summary over, so a reset can't drop it
+	if err := TagSession("aaaa1111", "incident:42"); err != nil {
+		t.Fatal(err)
+	}
+	if err := InvalidateSessionIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if !annotationsCurrent() {
+		t.Fatal("annotations file not brought up to date")
+	}
+	a := loadAnnotations()
+	if got := a.Summaries["aaaa1111"]; got != "fixed the login bug" {
+		t.Errorf("summary after migration = %q", got)
+	}
+	if got := a.Tags["aaaa1111"]; !slices.Equal(got, []string{"incident:42", "review"}) {
+		t.Errorf("tags after migration = %v", got)
+	}
+}
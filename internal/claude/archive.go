@@ -0,0 +1,448 @@
+package claude
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchiveManifestName is the manifest entry inside every session archive.
+const ArchiveManifestName = "manifest.json"
+
+// CurrentArchiveManifestVersion is the schema version for ArchiveManifest.
+const CurrentArchiveManifestVersion = 1
+
+// ArchiveManifest lists the transcripts in a session archive with checksums.
+type ArchiveManifest struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Files     []ArchiveEntry `json:"files"`
+}
+
+// ArchiveEntry records one transcript stored in an archive.
+type ArchiveEntry struct {
+	Name      string `json:"name"`       // path inside the archive (<project>/<id>.jsonl)
+	SessionID string `json:"session_id"` // session UUID
+	Size      int64  `json:"size"`       // bytes
+	SHA256    string `json:"sha256"`     // hex digest of the transcript
+
+	// Root is the Claude data directory the transcript was found in, and
+	// is restored to; empty in archives written before it was recorded.
+	Root string `json:"root,omitempty"`
+}
+
+// archiveState is WriteArchive's resumable state.
+type archiveState struct {
+	Manifest ArchiveManifest `json:"manifest"` // files hashed so far
+	Written  int             `json:"written"`  // transcripts in the partial archive
+	Offset   int64           `json:"offset"`   // partial archive size after them
+}
+
+// WriteArchive bundles session transcripts into a gzipped tarball at dest.
+// A manifest with SHA-256 checksums is stored as the first entry, and a
+// <dest>.sha256 sidecar records the digest of the archive itself so that
+// edits to the manifest are detectable too.
+//
+// The archive is built in <dest>.partial, one gzip member per transcript,
+// with progress checkpointed in <dest>.checkpoint.json. If writing is
+// interrupted, running it again with the same sessions truncates the
+// partial archive to the last checkpoint and carries on from there.
+// Transcripts are archived as they were when hashed; lines appended
+// since are left out.
+func WriteArchive(dest string, sessions []SessionInfo, progress Progress) (*ArchiveManifest, error) {
+	ids := make([]string, len(sessions))
+	roots := make(map[string]string, len(sessions))
+	for i, s := range sessions {
+		ids[i] = s.Path
+		// Entry names leave out the root, so they must not collide
+		name := archiveEntryName(s)
+		if root, dup := roots[name]; dup && root != s.Root {
+			return nil, fmt.Errorf("%s is in both %s and %s; archive each root's copy separately", name, root, s.Root)
+		}
+		roots[name] = s.Root
+	}
+	cp, resumed := openCheckpoint(dest+".checkpoint.json", "archive", checkpointKey(ids...))
+	var st archiveState
+	if resumed {
+		if err := cp.state(&st); err != nil {
+			resumed = false
+			st = archiveState{}
+		}
+	}
+	if !resumed {
+		st.Manifest = ArchiveManifest{Version: CurrentArchiveManifestVersion, CreatedAt: time.Now().UTC()}
+	}
+	checkpoint := func(force bool) error {
+		if !force && !cp.saveDue() {
+			return nil
+		}
+		if err := cp.setState(st); err != nil {
+			return err
+		}
+		if err := cp.save(); err != nil {
+			return fmt.Errorf("writing archive checkpoint: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return nil, fmt.Errorf("creating archive directory: %w", err)
+	}
+
+	// Hash first so the manifest can lead the archive
+	for i := len(st.Manifest.Files); i < len(sessions); i++ {
+		s := sessions[i]
+		sum, size, err := hashFile(s.Path)
+		if err != nil {
+			_ = checkpoint(true)
+			return nil, fmt.Errorf("hashing %s: %w", s.ID, err)
+		}
+		st.Manifest.Files = append(st.Manifest.Files, ArchiveEntry{
+			Name:      archiveEntryName(s),
+			SessionID: s.ID,
+			Size:      size,
+			SHA256:    sum,
+			Root:      s.Root,
+		})
+		if err := checkpoint(false); err != nil {
+			return nil, err
+		}
+		progress.report("hashing", i+1, len(sessions))
+	}
+
+	partial := dest + ".partial"
+	out, hasher, err := openPartialArchive(partial, st.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	writeErr := func() error {
+		if st.Offset == 0 {
+			st.Written = 0
+			if err := writeArchiveManifest(out, hasher, &st.Manifest); err != nil {
+				return err
+			}
+			if st.Offset, err = out.Seek(0, io.SeekCurrent); err != nil {
+				return err
+			}
+		}
+		for i := st.Written; i < len(sessions); i++ {
+			entry := st.Manifest.Files[i]
+			start, err := out.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			if err := writeArchiveMember(out, hasher, func(tw *tar.Writer) error {
+				return addFileToTar(tw, sessions[i].Path, entry)
+			}); err != nil {
+				// Keep the transcripts before this one for the next run
+				if out.Sync() == nil {
+					st.Written, st.Offset = i, start
+					_ = checkpoint(true)
+				}
+				return fmt.Errorf("adding %s: %w", sessions[i].ID, err)
+			}
+			if cp.saveDue() {
+				// Only checkpoint what is safely on disk
+				if err := out.Sync(); err != nil {
+					return err
+				}
+				st.Written = i + 1
+				if st.Offset, err = out.Seek(0, io.SeekCurrent); err != nil {
+					return err
+				}
+				if err := checkpoint(true); err != nil {
+					return err
+				}
+			}
+			progress.report("writing", i+1, len(sessions))
+		}
+		return writeArchiveEnd(out, hasher)
+	}()
+	closeErr := out.Close()
+	if writeErr != nil {
+		return nil, writeErr
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+	if err := os.Rename(partial, dest); err != nil {
+		return nil, err
+	}
+	cp.remove()
+
+	sidecar := fmt.Sprintf("%s  %s\n", hex.EncodeToString(hasher.Sum(nil)), filepath.Base(dest))
+	if err := os.WriteFile(dest+".sha256", []byte(sidecar), 0644); err != nil { //nolint:gosec // G306: checksums are not secret
+		return nil, fmt.Errorf("writing checksum sidecar: %w", err)
+	}
+
+	manifest := st.Manifest
+	return &manifest, nil
+}
+
+// archiveEntryName returns the path a transcript is stored under in an
+// archive: <project>/<id>.jsonl, or <project>/<parent-id>/subagents/<id>.jsonl
+// for a subagent's, mirroring the projects directory.
+func archiveEntryName(s SessionInfo) string {
+	name := filepath.Base(s.Path)
+	if dir := filepath.Dir(s.Path); filepath.Base(dir) == "subagents" {
+		name = filepath.Join(filepath.Base(filepath.Dir(dir)), "subagents", name)
+	}
+	return filepath.ToSlash(filepath.Join(s.Project, name))
+}
+
+// openPartialArchive opens a partial archive to continue writing at
+// offset, truncating anything written after it, and returns a hasher
+// that has already digested the bytes before it. Offset 0 starts afresh.
+func openPartialArchive(path string, offset int64) (*os.File, hash.Hash, error) {
+	flags := os.O_RDWR | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0644) //nolint:gosec // G304: path is next to the user-specified archive
+	if err != nil {
+		return nil, nil, err
+	}
+	hasher := sha256.New()
+	if offset > 0 {
+		if _, err := io.CopyN(hasher, f, offset); err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("reading partial archive: %w", err)
+		}
+		if err := f.Truncate(offset); err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+	}
+	return f, hasher, nil
+}
+
+// writeArchiveManifest writes the manifest as the archive's first member.
+func writeArchiveManifest(w io.Writer, hasher hash.Hash, manifest *ArchiveManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeArchiveMember(w, hasher, func(tw *tar.Writer) error {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    ArchiveManifestName,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: manifest.CreatedAt,
+		}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	})
+}
+
+// writeArchiveMember writes one gzip member holding the tar entries write
+// adds. Gzip readers read concatenated members as one stream, so the tar
+// stream continues across members, and the archive can be cut back to
+// any member boundary and resumed.
+func writeArchiveMember(w io.Writer, hasher hash.Hash, write func(tw *tar.Writer) error) error {
+	gz := gzip.NewWriter(io.MultiWriter(w, hasher))
+	tw := tar.NewWriter(gz)
+	if err := write(tw); err != nil {
+		return err
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// writeArchiveEnd writes the last gzip member: the tar end-of-archive
+// marker.
+func writeArchiveEnd(w io.Writer, hasher hash.Hash) error {
+	gz := gzip.NewWriter(io.MultiWriter(w, hasher))
+	if err := tar.NewWriter(gz).Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ArchiveProblem describes one integrity failure found by VerifyArchive.
+type ArchiveProblem struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// ArchiveVerification is the result of checking an archive.
+type ArchiveVerification struct {
+	Path           string           `json:"path"`
+	Manifest       *ArchiveManifest `json:"manifest,omitempty"`
+	SidecarChecked bool             `json:"sidecar_checked"`
+	FilesVerified  int              `json:"files_verified"`
+	Problems       []ArchiveProblem `json:"problems,omitempty"`
+}
+
+// OK reports whether the archive passed every check.
+func (v *ArchiveVerification) OK() bool {
+	return len(v.Problems) == 0
+}
+
+// VerifyArchive checks an archive against its manifest and sidecar checksum.
+// Corruption or tampering is reported as problems rather than an error;
+// the error return is reserved for archives that can't be read at all.
+func VerifyArchive(path string) (*ArchiveVerification, error) {
+	v := &ArchiveVerification{Path: path}
+
+	// Whole-archive digest from the sidecar, if present
+	if sidecar, err := os.ReadFile(path + ".sha256"); err == nil { //nolint:gosec // G304: sidecar next to user-specified archive
+		fields := strings.Fields(string(sidecar))
+		sum, _, err := hashFile(path)
+		if err != nil {
+			return nil, err
+		}
+		v.SidecarChecked = true
+		if len(fields) == 0 || fields[0] != sum {
+			v.Problems = append(v.Problems, ArchiveProblem{Name: filepath.Base(path), Reason: "archive checksum does not match .sha256 sidecar"})
+		}
+	}
+
+	f, err := os.Open(path) //nolint:gosec // G304: user-specified archive
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		v.Problems = append(v.Problems, ArchiveProblem{Name: filepath.Base(path), Reason: "not a gzip archive: " + err.Error()})
+		return v, nil
+	}
+	defer gz.Close()
+
+	seen := make(map[string]string) // name -> sha256
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			v.Problems = append(v.Problems, ArchiveProblem{Name: filepath.Base(path), Reason: "corrupt archive: " + err.Error()})
+			break
+		}
+
+		if hdr.Name == ArchiveManifestName {
+			var m ArchiveManifest
+			if err := json.NewDecoder(tr).Decode(&m); err != nil {
+				v.Problems = append(v.Problems, ArchiveProblem{Name: ArchiveManifestName, Reason: "unreadable manifest: " + err.Error()})
+				continue
+			}
+			v.Manifest = &m
+			continue
+		}
+
+		h := sha256.New()
+		if _, err := io.Copy(h, tr); err != nil {
+			v.Problems = append(v.Problems, ArchiveProblem{Name: hdr.Name, Reason: "unreadable entry: " + err.Error()})
+			continue
+		}
+		seen[hdr.Name] = hex.EncodeToString(h.Sum(nil))
+	}
+
+	if v.Manifest == nil {
+		v.Problems = append(v.Problems, ArchiveProblem{Name: ArchiveManifestName, Reason: "missing manifest"})
+		return v, nil
+	}
+
+	for _, entry := range v.Manifest.Files {
+		sum, ok := seen[entry.Name]
+		switch {
+		case !ok:
+			v.Problems = append(v.Problems, ArchiveProblem{Name: entry.Name, Reason: "listed in manifest but missing"})
+		case sum != entry.SHA256:
+			v.Problems = append(v.Problems, ArchiveProblem{Name: entry.Name, Reason: "checksum mismatch"})
+		default:
+			v.FilesVerified++
+		}
+		delete(seen, entry.Name)
+	}
+
+	extras := make([]string, 0, len(seen))
+	for name := range seen {
+		extras = append(extras, name)
+	}
+	sort.Strings(extras)
+	for _, name := range extras {
+		v.Problems = append(v.Problems, ArchiveProblem{Name: name, Reason: "not listed in manifest"})
+	}
+
+	return v, nil
+}
+
+// hashFile returns the hex SHA-256 digest and size of a file.
+func hashFile(path string) (string, int64, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: internal path
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	// Fix the size first: a live transcript can grow while it is read
+	stat, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	size := stat.Size()
+	h := sha256.New()
+	n, err := io.Copy(h, io.LimitReader(f, size))
+	if err != nil {
+		return "", 0, err
+	}
+	if n != size {
+		return "", 0, fmt.Errorf("file shrank from %d to %d bytes while hashing", size, n)
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// addFileToTar copies the first entry.Size bytes of a file into the tar
+// stream under entry.Name. Transcripts only grow, so those bytes should be
+// what was hashed; they are hashed again on the way through, and a file
+// rewritten since then is an error rather than an archive whose manifest
+// doesn't match its contents.
+func addFileToTar(tw *tar.Writer, path string, entry ArchiveEntry) error {
+	f, err := os.Open(path) //nolint:gosec // G304: internal path
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if stat.Size() < entry.Size {
+		return fmt.Errorf("file shrank from %d to %d bytes since it was hashed", entry.Size, stat.Size())
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    entry.Name,
+		Mode:    0600,
+		Size:    entry.Size,
+		ModTime: stat.ModTime(),
+	}); err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(tw, io.TeeReader(io.LimitReader(f, entry.Size), h)); err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != entry.SHA256 {
+		return fmt.Errorf("file changed since it was hashed (sha256 %s, manifest %s)", sum[:12], entry.SHA256[:12])
+	}
+	return nil
+}
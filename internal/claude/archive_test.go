@@ -0,0 +1,122 @@
+package claude
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteAndVerifyArchive(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	p1 := writeSession(t, home, "-home-u-proj", "s1.jsonl", `{"type":"user","timestamp":"2025-01-01T00:00:00Z"}`)
+	p2 := writeSession(t, home, "-home-u-proj", "s2.jsonl", `{"type":"user","timestamp":"2025-01-02T00:00:00Z"}`)
+
+	sessions := []SessionInfo{
+		{ID: "s1", Path: p1, Project: "-home-u-proj"},
+		{ID: "s2", Path: p2, Project: "-home-u-proj"},
+	}
+
+	dest := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	manifest, err := WriteArchive(dest, sessions, nil)
+	if err != nil {
+		t.Fatalf("WriteArchive: %v", err)
+	}
+	if len(manifest.Files) != 2 || manifest.Files[0].SHA256 == "" {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+
+	v, err := VerifyArchive(dest)
+	if err != nil {
+		t.Fatalf("VerifyArchive: %v", err)
+	}
+	if !v.OK() || v.FilesVerified != 2 || !v.SidecarChecked {
+		t.Errorf("expected clean verification, got %+v", v)
+	}
+
+	// Tamper with the archive bytes: sidecar check must fail
+	data, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(dest, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	v, err = VerifyArchive(dest)
+	if err != nil {
+		t.Fatalf("VerifyArchive: %v", err)
+	}
+	if v.OK() {
+		t.Error("expected tampered archive to fail verification")
+	}
+}
+
+func TestVerifyArchiveNotGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "junk.tar.gz")
+	if err := os.WriteFile(path, []byte("not an archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	v, err := VerifyArchive(path)
+	if err != nil {
+		t.Fatalf("VerifyArchive: %v", err)
+	}
+	if v.OK() {
+		t.Error("expected junk file to fail verification")
+	}
+}
+
+func TestAddFileToTarLiveTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s1.jsonl")
+	first := `{"type":"user","timestamp":"2025-01-01T00:00:00Z"}` + "\n"
+	if err := os.WriteFile(path, []byte(first), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sum, size, err := hashFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := ArchiveEntry{Name: "p/s1.jsonl", Size: size, SHA256: sum}
+
+	// Lines appended after hashing are left out
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"type":"assistant"}` + "\n")
+	f.Close()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := addFileToTar(tw, path, entry); err != nil {
+		t.Fatalf("grown transcript: %v", err)
+	}
+	tw.Close()
+	tr := tar.NewReader(&buf)
+	if _, err := tr.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := io.ReadAll(tr); string(got) != first {
+		t.Errorf("archived %q, want only the hashed bytes %q", got, first)
+	}
+
+	// A transcript rewritten since hashing is refused
+	if err := os.WriteFile(path, []byte(strings.ToUpper(first)+"more\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	err = addFileToTar(tar.NewWriter(io.Discard), path, entry)
+	if err == nil || !strings.Contains(err.Error(), "changed since it was hashed") {
+		t.Errorf("rewritten transcript = %v, want a checksum error", err)
+	}
+	if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := addFileToTar(tar.NewWriter(io.Discard), path, entry); err == nil {
+		t.Error("truncated transcript archived")
+	}
+}
@@ -0,0 +1,163 @@
+package claude
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// beacon is the metadata a Gas Town startup beacon carries. Two formats
+// are recognized:
+//
+//	v1: [GAS TOWN] <recipient> <- <sender> • <timestamp> • <topic[:bead]>
+//	v2: [GAS TOWN] role=<recipient> from=<sender> rig=<rig> agent=<name> topic=<topic> bead=<bead> ts=<timestamp>
+//
+// v2 pairs may come in any order, and values with spaces are quoted.
+// Unknown keys are ignored, so fields can be added without breaking
+// older parsers.
+type beacon struct {
+	Role  string
+	Topic string // topic[:bead], as v1 writes it
+	Rig   string
+	Agent string
+	Bead  string
+}
+
+// parseBeacon extracts the metadata of the beacon in text, in either
+// format.
+func parseBeacon(text string) (beacon, bool) {
+	idx := strings.Index(text, beaconPrefix)
+	if idx < 0 {
+		return beacon{}, false
+	}
+	line := text[idx+len(beaconPrefix):]
+	if nl := strings.IndexByte(line, '\n'); nl >= 0 {
+		line = line[:nl]
+	}
+
+	var b beacon
+	if pairs, ok := parseBeaconPairs(line); ok {
+		b = beacon{Role: pairs["role"], Topic: pairs["topic"], Rig: pairs["rig"], Agent: pairs["agent"], Bead: pairs["bead"]}
+		if b.Bead != "" {
+			if b.Topic == "" {
+				b.Topic = b.Bead
+			} else {
+				b.Topic += ":" + b.Bead
+			}
+		}
+	} else {
+		b = parseBeaconV1(line)
+	}
+
+	rig, agent := BeaconRoleParts(b.Role)
+	if b.Rig == "" {
+		b.Rig = rig
+	}
+	if b.Agent == "" {
+		b.Agent = agent
+	}
+	return b, true
+}
+
+// parseBeaconV1 parses the bullet-delimited format.
+func parseBeaconV1(line string) beacon {
+	var b beacon
+	parts := strings.Split(line, "•")
+	head := strings.TrimSpace(parts[0])
+	if recipient, _, found := strings.Cut(head, "<-"); found {
+		b.Role = strings.TrimSpace(recipient)
+	} else {
+		b.Role = head
+	}
+	if len(parts) >= 3 {
+		b.Topic = strings.TrimSpace(parts[len(parts)-1])
+		if _, bead, found := strings.Cut(b.Topic, ":"); found {
+			b.Bead = bead
+		} else if looksLikeBead(b.Topic) {
+			b.Bead = b.Topic
+		}
+	}
+	return b
+}
+
+// parseBeaconPairs parses a v2 beacon line into its key=value pairs. It
+// reports false unless the line starts with a pair, as v1 lines don't.
+func parseBeaconPairs(line string) (map[string]string, bool) {
+	pairs := make(map[string]string)
+	rest := strings.TrimSpace(line)
+	for rest != "" {
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 || strings.IndexFunc(rest[:eq], unicode.IsSpace) >= 0 {
+			break
+		}
+		key := rest[:eq]
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				break
+			}
+			value, _ = strconv.Unquote(quoted)
+			rest = rest[len(quoted):]
+		} else if sp := strings.IndexFunc(rest, unicode.IsSpace); sp >= 0 {
+			value, rest = rest[:sp], rest[sp:]
+		} else {
+			value, rest = rest, ""
+		}
+		pairs[key] = value
+		rest = strings.TrimSpace(rest)
+	}
+	return pairs, len(pairs) > 0 && pairs["role"] != ""
+}
+
+// BeaconRoleParts derives the rig and agent name from a role address:
+// "gastown/crew/joe" is rig gastown, agent joe; "gastown/witness" is rig
+// gastown, agent witness; "mayor" has no rig. Beacons carry these as their
+// rig= and agent= fields, so writers and the parser share this.
+func BeaconRoleParts(role string) (rig, agent string) {
+	if role == "" {
+		return "", ""
+	}
+	parts := strings.Split(role, "/")
+	if len(parts) == 1 {
+		return "", parts[0]
+	}
+	return parts[0], parts[len(parts)-1]
+}
+
+// looksLikeBead reports whether a bare v1 topic is a bead ID ("gt-abc12"):
+// a short lowercase prefix, a dash, and an alphanumeric suffix with a
+// digit in it, which tells it apart from topics like "cold-start".
+func looksLikeBead(s string) bool {
+	prefix, suffix, ok := strings.Cut(s, "-")
+	if !ok || prefix == "" || len(prefix) > 4 || suffix == "" {
+		return false
+	}
+	for _, r := range prefix {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	digit := false
+	for _, r := range suffix {
+		switch {
+		case r >= '0' && r <= '9':
+			digit = true
+		case r >= 'a' && r <= 'z' || r == '.':
+		default:
+			return false
+		}
+	}
+	return digit
+}
+
+// apply copies the beacon's metadata onto s.
+func (b beacon) apply(s *SessionInfo) {
+	s.Role = b.Role
+	s.Topic = b.Topic
+	s.Rig = b.Rig
+	s.AgentName = b.Agent
+	s.Bead = b.Bead
+}
@@ -0,0 +1,219 @@
+package claude
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CastOptions control how ExportCast lays out a recording.
+type CastOptions struct {
+	Width  int // terminal columns (default 100)
+	Height int // terminal rows (default 30)
+
+	// IdleLimit caps the pause between turns; real gaps can be hours.
+	// Default 2s.
+	IdleLimit time.Duration
+
+	// LineDelay is the pause between printed lines, so long replies
+	// scroll rather than appear at once. Default 40ms.
+	LineDelay time.Duration
+
+	// ResultLines is how many lines of each tool's output are shown
+	// before the rest is elided. Default 8; negative shows all.
+	ResultLines int
+}
+
+func (o CastOptions) withDefaults() CastOptions {
+	if o.Width <= 0 {
+		o.Width = 100
+	}
+	if o.Height <= 0 {
+		o.Height = 30
+	}
+	if o.IdleLimit <= 0 {
+		o.IdleLimit = 2 * time.Second
+	}
+	if o.LineDelay <= 0 {
+		o.LineDelay = 40 * time.Millisecond
+	}
+	if o.ResultLines == 0 {
+		o.ResultLines = 8
+	}
+	return o
+}
+
+// ANSI styles for the recording.
+const (
+	castReset  = "\x1b[0m"
+	castBold   = "\x1b[1m"
+	castDim    = "\x1b[2m"
+	castRed    = "\x1b[31m"
+	castGreen  = "\x1b[32m"
+	castYellow = "\x1b[33m"
+	castCyan   = "\x1b[36m"
+)
+
+// castHeader is the first line of an asciicast v2 file.
+type castHeader struct {
+	Version       int     `json:"version"`
+	Width         int     `json:"width"`
+	Height        int     `json:"height"`
+	Timestamp     int64   `json:"timestamp,omitempty"`
+	IdleTimeLimit float64 `json:"idle_time_limit,omitempty"`
+	Title         string  `json:"title,omitempty"`
+}
+
+// ExportCast renders a session as an asciicast v2 recording, playable in
+// asciinema players: prompts, replies, and each tool call with the head
+// of its output, styled like a terminal session. Each prompt is also a
+// marker, so players can jump between them. Pauses follow the
+// transcript's own timing, capped at opts.IdleLimit. id is a session ID,
+// alias, or unique prefix (see FindSession).
+func ExportCast(id string, opts CastOptions) ([]byte, error) {
+	session, err := FindSession(id)
+	if err != nil {
+		return nil, err
+	}
+	turns, err := ReadTranscriptTurns(session.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading transcript: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := writeCast(&buf, session, turns, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// castWriter emits asciicast output events on a running clock.
+type castWriter struct {
+	enc   *json.Encoder
+	clock time.Duration
+	delay time.Duration
+}
+
+// mark emits a marker event, which players list as a chapter.
+func (c *castWriter) mark(label string) error {
+	return c.enc.Encode([]interface{}{c.clock.Seconds(), "m", label})
+}
+
+// print emits text as one output event after the line delay.
+func (c *castWriter) print(text string) error {
+	c.clock += c.delay
+	return c.enc.Encode([]interface{}{c.clock.Seconds(), "o", text})
+}
+
+// println prints each line of text in style, the first after prefix and
+// the rest after indent.
+func (c *castWriter) println(prefix, indent, style, text string) error {
+	for i, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if i > 0 {
+			prefix = indent
+		}
+		if err := c.print(prefix + style + line + castReset + "\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCast(w io.Writer, session *SessionInfo, turns []TranscriptTurn, opts CastOptions) error {
+	opts = opts.withDefaults()
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	header := castHeader{
+		Version:       2,
+		Width:         opts.Width,
+		Height:        opts.Height,
+		IdleTimeLimit: opts.IdleLimit.Seconds(),
+		Title:         exportTitle(session),
+	}
+	if !session.StartTime.IsZero() {
+		header.Timestamp = session.StartTime.Unix()
+	}
+	if err := enc.Encode(header); err != nil {
+		return err
+	}
+
+	c := &castWriter{enc: enc, delay: opts.LineDelay}
+	if err := c.println("", "", castDim, exportTitle(session)); err != nil {
+		return err
+	}
+
+	var last time.Time
+	for _, turn := range turns {
+		if !last.IsZero() && !turn.Timestamp.IsZero() {
+			c.clock += min(max(turn.Timestamp.Sub(last), 0), opts.IdleLimit)
+		}
+		if !turn.Timestamp.IsZero() {
+			last = turn.Timestamp
+		}
+		if err := writeCastTurn(c, turn, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCastTurn(c *castWriter, turn TranscriptTurn, opts CastOptions) error {
+	if err := c.print("\r\n"); err != nil {
+		return err
+	}
+	if text := strings.TrimSpace(turn.Text); text != "" {
+		if turn.Role == "user" {
+			if err := c.mark(firstLine(text)); err != nil {
+				return err
+			}
+			if err := c.println(castCyan+castBold+"❯ "+castReset, "  ", castBold, text); err != nil {
+				return err
+			}
+		} else if err := c.println("  ", "  ", "", text); err != nil {
+			return err
+		}
+	}
+
+	for _, call := range turn.ToolCalls {
+		label := call.Name
+		if target := toolCallTarget(call); target != "" && target != "{}" {
+			label += "(" + firstLine(target) + ")"
+		}
+		mark := castGreen + "● " + castReset
+		if call.IsError {
+			mark = castRed + "● " + castReset
+		}
+		if err := c.println(mark, "  ", castYellow, label); err != nil {
+			return err
+		}
+
+		result := strings.TrimRight(call.Result, "\n")
+		if call.Pending {
+			result = "(no result)"
+		}
+		if result == "" {
+			continue
+		}
+		lines := strings.Split(result, "\n")
+		hidden := 0
+		if opts.ResultLines > 0 && len(lines) > opts.ResultLines {
+			hidden = len(lines) - opts.ResultLines
+			lines = lines[:opts.ResultLines]
+		}
+		style := castDim
+		if call.IsError {
+			style = castRed
+		}
+		if err := c.println("  ⎿ ", "    ", style, strings.Join(lines, "\n")); err != nil {
+			return err
+		}
+		if hidden > 0 {
+			if err := c.println("    ", "    ", castDim, fmt.Sprintf("… %d more lines", hidden)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
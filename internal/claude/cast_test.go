@@ -0,0 +1,61 @@
+package claude
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportCast(t *testing.T) {
+	writeExportSession(t)
+
+	out, err := ExportCast("abcd", CastOptions{Width: 80, IdleLimit: time.Second, LineDelay: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("ExportCast: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+
+	var header castHeader
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("bad header %q: %v", lines[0], err)
+	}
+	if header.Version != 2 || header.Width != 80 || header.Height != 30 || header.IdleTimeLimit != 1 {
+		t.Errorf("header = %+v", header)
+	}
+
+	var output strings.Builder
+	var markers []string
+	prev := -1.0
+	for _, line := range lines[1:] {
+		var event []interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil || len(event) != 3 {
+			t.Fatalf("bad event %q: %v", line, err)
+		}
+		ts := event[0].(float64)
+		if ts < prev {
+			t.Errorf("event times go backwards: %v after %v", ts, prev)
+		}
+		prev = ts
+		switch event[1] {
+		case "o":
+			output.WriteString(event[2].(string))
+		case "m":
+			markers = append(markers, event[2].(string))
+		}
+	}
+
+	text := output.String()
+	for _, want := range []string{"fix <the> auth bug", "Looking.", "Bash(cat README.md)", "x := 1"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("cast output missing %q:\n%s", want, text)
+		}
+	}
+	if len(markers) != 1 || markers[0] != "fix <the> auth bug" {
+		t.Errorf("markers = %q", markers)
+	}
+	// The 5s gap between the turns is capped at the idle limit
+	if prev > 3 {
+		t.Errorf("recording runs %vs; idle gaps not capped", prev)
+	}
+}
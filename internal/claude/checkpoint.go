@@ -0,0 +1,108 @@
+package claude
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Progress reports how far a long operation has got: done of total items
+// in its current stage (e.g. "hashing", "writing"). Operations resumed
+// from a checkpoint start from the items already done.
+type Progress func(stage string, done, total int)
+
+// report calls p if it is set.
+func (p Progress) report(stage string, done, total int) {
+	if p != nil {
+		p(stage, done, total)
+	}
+}
+
+// checkpointInterval is how often a checkpoint is saved while an
+// operation runs. An interruption repeats at most this much work.
+const checkpointInterval = 2 * time.Second
+
+// checkpoint records the progress of a long operation (index rebuild,
+// bulk export, archive) on disk, so that running it again after an
+// interruption picks up where it left off.
+type checkpoint struct {
+	Op        string    `json:"op"`
+	Key       string    `json:"key"` // identifies the operation's inputs
+	StartedAt time.Time `json:"started_at"`
+
+	// Done maps finished items to what the operation needs to trust
+	// them, e.g. the size of the transcript that was exported.
+	Done map[string]string `json:"done,omitempty"`
+
+	// State is the operation's own resumable state.
+	State json.RawMessage `json:"state,omitempty"`
+
+	path  string
+	saved time.Time
+}
+
+// openCheckpoint loads the checkpoint at path if it belongs to the same
+// operation and key, or starts a new one. resumed reports whether an
+// earlier run's progress was found.
+func openCheckpoint(path, op, key string) (c *checkpoint, resumed bool) {
+	c = &checkpoint{Op: op, Key: key, StartedAt: time.Now().UTC(), Done: make(map[string]string), path: path}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: checkpoint next to the operation's output
+	if err != nil {
+		return c, false
+	}
+	var prev checkpoint
+	if json.Unmarshal(data, &prev) != nil || prev.Op != op || prev.Key != key {
+		return c, false
+	}
+	prev.path = path
+	if prev.Done == nil {
+		prev.Done = make(map[string]string)
+	}
+	return &prev, true
+}
+
+// checkpointKey identifies an operation's inputs.
+func checkpointKey(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// setState replaces the operation's resumable state.
+func (c *checkpoint) setState(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.State = data
+	return nil
+}
+
+// state decodes the operation's resumable state into v.
+func (c *checkpoint) state(v any) error {
+	if len(c.State) == 0 {
+		return nil
+	}
+	return json.Unmarshal(c.State, v)
+}
+
+// save writes the checkpoint.
+func (c *checkpoint) save() error {
+	c.saved = time.Now()
+	return util.AtomicWriteJSON(c.path, c)
+}
+
+// saveDue reports whether checkpointInterval has passed since the last
+// save.
+func (c *checkpoint) saveDue() bool {
+	return time.Since(c.saved) >= checkpointInterval
+}
+
+// remove deletes the checkpoint once the operation has finished.
+func (c *checkpoint) remove() {
+	_ = os.Remove(c.path)
+}
@@ -0,0 +1,171 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteArchiveResumes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	var sessions []SessionInfo
+	for _, id := range []string{"s1", "s2", "s3"} {
+		path := writeSession(t, home, "-home-u-proj", id+".jsonl", `{"type":"user","timestamp":"2025-01-01T00:00:00Z"}`)
+		sessions = append(sessions, SessionInfo{ID: id, Path: path, Project: "-home-u-proj"})
+	}
+	s3, _ := os.ReadFile(sessions[2].Path)
+
+	// Interrupt the write: s3 vanishes once everything is hashed
+	dest := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	_, err := WriteArchive(dest, sessions, func(stage string, done, total int) {
+		if stage == "hashing" && done == total {
+			_ = os.Remove(sessions[2].Path)
+		}
+	})
+	if err == nil {
+		t.Fatal("WriteArchive succeeded without s3")
+	}
+	if _, err := os.Stat(dest + ".checkpoint.json"); err != nil {
+		t.Fatalf("no checkpoint after interruption: %v", err)
+	}
+
+	if err := os.WriteFile(sessions[2].Path, s3, 0644); err != nil {
+		t.Fatal(err)
+	}
+	var written []int
+	if _, err := WriteArchive(dest, sessions, func(stage string, done, total int) {
+		if stage == "hashing" {
+			t.Errorf("resumed archive hashed again (%d/%d)", done, total)
+		}
+		written = append(written, done)
+	}); err != nil {
+		t.Fatalf("resumed WriteArchive: %v", err)
+	}
+	if len(written) != 1 || written[0] != 3 {
+		t.Errorf("resumed write reported %v, want only s3 written", written)
+	}
+
+	v, err := VerifyArchive(dest)
+	if err != nil || !v.OK() || v.FilesVerified != 3 {
+		t.Errorf("VerifyArchive = %+v, %v", v, err)
+	}
+	for _, leftover := range []string{dest + ".partial", dest + ".checkpoint.json"} {
+		if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+			t.Errorf("%s left behind", filepath.Base(leftover))
+		}
+	}
+}
+
+func TestExportSessionsResumes(t *testing.T) {
+	writeExportSession(t)
+	sessions, err := DiscoverSessions(SessionFilter{})
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("DiscoverSessions = %v, %v", sessions, err)
+	}
+	missing := sessions[0]
+	missing.ID, missing.Path = "gone", filepath.Join(t.TempDir(), "gone.jsonl")
+	batch := []SessionInfo{sessions[0], missing}
+
+	dir := t.TempDir()
+	if _, err := ExportSessions(batch, dir, ExportMarkdown, nil); err == nil {
+		t.Fatal("ExportSessions succeeded with a missing transcript")
+	}
+	// Mark the finished export, to see whether the next run rewrites it
+	done := filepath.Join(dir, "2025-01-01-abcd1234.md")
+	if err := os.WriteFile(done, []byte("kept"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ExportSessions(batch[:1], dir, ExportMarkdown, nil); err != nil {
+		t.Fatalf("resumed ExportSessions: %v", err)
+	}
+	if data, _ := os.ReadFile(done); string(data) != "kept" {
+		t.Error("resumed export rewrote a session it had already exported")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ExportCheckpointName)); !os.IsNotExist(err) {
+		t.Error("export checkpoint left behind")
+	}
+}
+
+func TestRebuildSessionIndexResumes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-home-u-proj", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":"hello"}}`,
+	)
+	if _, err := DiscoverSessions(SessionFilter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// An interrupted rebuild left its marker and an entry it had parsed
+	idx := loadSessionIndex(ProjectsDir())
+	for dir, p := range idx.Projects {
+		s := idx.shard(p.Shard)
+		for path, entry := range s.Entries {
+			if projectDirOf(path) == dir {
+				entry.Info.Summary = "rebuilt before the interruption"
+				s.Entries[path] = entry
+			}
+		}
+	}
+	if err := writeSessionIndex(idx); err != nil {
+		t.Fatal(err)
+	}
+	cp, _ := openCheckpoint(SessionIndexPath()+".rebuild", "index-rebuild", ProjectsDir())
+	if err := cp.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	var reports int
+	if _, err := RebuildSessionIndex(func(stage string, done, total int) { reports++ }); err != nil {
+		t.Fatalf("RebuildSessionIndex: %v", err)
+	}
+	if reports == 0 {
+		t.Error("no progress reported")
+	}
+	sessions, _ := DiscoverSessions(SessionFilter{})
+	if len(sessions) != 1 || sessions[0].Summary != "rebuilt before the interruption" {
+		t.Errorf("resumed rebuild discarded its earlier work: %+v", sessions)
+	}
+	if _, err := os.Stat(cp.path); !os.IsNotExist(err) {
+		t.Error("rebuild marker left behind")
+	}
+
+	// Without a marker, a rebuild starts over
+	if _, err := RebuildSessionIndex(nil); err != nil {
+		t.Fatal(err)
+	}
+	sessions, _ = DiscoverSessions(SessionFilter{})
+	if len(sessions) != 1 || sessions[0].Summary != "" {
+		t.Errorf("fresh rebuild kept stale entries: %+v", sessions)
+	}
+}
+
+func TestOpenCheckpointKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cp.json")
+	cp, resumed := openCheckpoint(path, "op", "k1")
+	if resumed {
+		t.Fatal("resumed a checkpoint that doesn't exist")
+	}
+	cp.Done["a"] = "1"
+	if err := cp.setState(map[string]int{"n": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	cp, resumed = openCheckpoint(path, "op", "k1")
+	var st map[string]int
+	if !resumed || cp.Done["a"] != "1" || cp.state(&st) != nil || st["n"] != 2 {
+		t.Errorf("reopened checkpoint = %+v, resumed %v", cp, resumed)
+	}
+	if _, resumed := openCheckpoint(path, "op", "k2"); resumed {
+		t.Error("resumed a checkpoint for other inputs")
+	}
+	if _, resumed := openCheckpoint(path, "other", "k1"); resumed {
+		t.Error("resumed another operation's checkpoint")
+	}
+}
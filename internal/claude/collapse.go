@@ -0,0 +1,92 @@
+package claude
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// collapseResumes replaces each chain of resumed sessions with its latest
+// segment, recording the chain on it (see SessionInfo.Chain). Claude Code
+// writes a new transcript on resume, often with the earlier history copied
+// in, so a chain otherwise lists as near-identical sessions. Forks, which
+// branch from an earlier message, stay separate. Only the given sessions
+// are linked: a chain whose middle segment was filtered out splits there.
+func collapseResumes(sessions []*SessionInfo) []*SessionInfo {
+	byDir := make(map[string][]*SessionInfo)
+	for _, s := range sessions {
+		if s.ParentID == "" {
+			dir := filepath.Dir(s.Path)
+			byDir[dir] = append(byDir[dir], s)
+		}
+	}
+
+	dropped := make(map[*SessionInfo]bool)
+	for _, group := range byDir {
+		if len(group) < 2 {
+			continue
+		}
+		for _, chain := range resumeChains(group) {
+			latest := chain[len(chain)-1]
+			latest.Chain = make([]string, len(chain))
+			for i, s := range chain {
+				latest.Chain[i] = s.ID
+				if s != latest {
+					dropped[s] = true
+				}
+			}
+			latest.ChainStart = chain[0].StartTime
+		}
+	}
+
+	out := sessions[:0]
+	for _, s := range sessions {
+		if !dropped[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// resumeChains returns the chains of two or more sessions in group, all
+// from one project directory, linked by resumes. Each chain is ordered by
+// start time, oldest first.
+func resumeChains(group []*SessionInfo) [][]*SessionInfo {
+	scans := make(map[string]*lineageScan, len(group))
+	byID := make(map[string]*SessionInfo, len(group))
+	for _, s := range group {
+		scan, err := scanLineage(s.Path)
+		if err != nil {
+			continue
+		}
+		scan.info = s
+		scans[s.ID] = scan
+		byID[s.ID] = s
+	}
+	nodes, parents := linkLineage(scans)
+
+	// A chain is named by its first session: the one reached by walking
+	// resumes back until a fork or the original
+	members := make(map[string][]*SessionInfo)
+	for id, s := range byID {
+		head := id
+		for nodes[head].Kind == LineageResume {
+			head = parents[head]
+		}
+		members[head] = append(members[head], s)
+	}
+
+	var chains [][]*SessionInfo
+	for _, chain := range members {
+		if len(chain) < 2 {
+			continue
+		}
+		sort.Slice(chain, func(i, j int) bool {
+			if !chain[i].StartTime.Equal(chain[j].StartTime) {
+				return chain[i].StartTime.Before(chain[j].StartTime)
+			}
+			return chain[i].ID < chain[j].ID
+		})
+		chains = append(chains, chain)
+	}
+	return chains
+}
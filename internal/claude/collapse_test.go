@@ -0,0 +1,78 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollapseResumes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	project := "-town-gastown-crew-joe"
+	line := func(session, uuid, parent, ts string) string {
+		return fmt.Sprintf(`{"type":"user","uuid":%q,"parentUuid":%q,"sessionId":%q,"timestamp":"2025-01-01T%s:00Z","message":{"role":"user","content":"hi"}}`,
+			uuid, parent, session, ts)
+	}
+	writeSession(t, home, project, "aaaa0001.jsonl",
+		line("aaaa0001", "a1", "", "00:00"),
+		line("aaaa0001", "a2", "a1", "00:30"),
+	)
+	// Resumed with the history copied in, then resumed again
+	writeSession(t, home, project, "bbbb0002.jsonl",
+		line("aaaa0001", "a1", "", "01:00"),
+		line("aaaa0001", "a2", "a1", "01:00"),
+		line("bbbb0002", "b1", "a2", "01:10"),
+	)
+	writeSession(t, home, project, "cccc0003.jsonl",
+		line("cccc0003", "c1", "b1", "02:00"),
+		line("cccc0003", "c2", "c1", "02:45"),
+	)
+	// A fork from the first message stays its own session
+	writeSession(t, home, project, "dddd0004.jsonl",
+		line("dddd0004", "d1", "a1", "03:00"),
+	)
+
+	sessions, err := DiscoverSessions(SessionFilter{CollapseResumes: true})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	var ids []string
+	for _, s := range sessions {
+		ids = append(ids, s.ID)
+	}
+	if got := strings.Join(ids, " "); got != "dddd0004 cccc0003" {
+		t.Fatalf("collapsed sessions = %s, want dddd0004 cccc0003", got)
+	}
+	latest := sessions[1]
+	if got := strings.Join(latest.Chain, " "); got != "aaaa0001 bbbb0002 cccc0003" {
+		t.Errorf("chain = %s", got)
+	}
+	if want := 165 * time.Minute; latest.Span() != want {
+		t.Errorf("span = %s, want %s", latest.Span(), want)
+	}
+	if fork := sessions[0]; len(fork.Chain) != 0 || fork.Span() != fork.Duration {
+		t.Errorf("fork = chain %v, span %s", fork.Chain, fork.Span())
+	}
+
+	// Off by default
+	all, err := DiscoverSessions(SessionFilter{})
+	if err != nil || len(all) != 4 || len(all[0].Chain) != 0 {
+		t.Errorf("uncollapsed = %d sessions, %v", len(all), err)
+	}
+
+	// IterSessions collapses each directory as it goes
+	ids = nil
+	for s, err := range IterSessions(context.Background(), SessionFilter{CollapseResumes: true}) {
+		if err != nil {
+			t.Fatalf("IterSessions: %v", err)
+		}
+		ids = append(ids, s.ID)
+	}
+	if got := strings.Join(ids, " "); got != "dddd0004 cccc0003" {
+		t.Errorf("iterated sessions = %s", got)
+	}
+}
@@ -0,0 +1,98 @@
+package claude
+
+import (
+	"bufio"
+	"os"
+	"time"
+)
+
+// Compaction is one context compaction in a session: Claude Code replaced
+// the conversation so far with a summary.
+type Compaction struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Trigger    string    `json:"trigger,omitempty"` // "auto" or "manual"
+	PreTokens  int64     `json:"pre_tokens"`        // context size before compacting
+	PostTokens int64     `json:"post_tokens"`       // context size of the first request after
+	Turns      int       `json:"turns"`             // turns summarized since the previous compaction
+	Summary    string    `json:"summary,omitempty"`
+}
+
+// Dropped returns how many tokens of context the compaction removed, or 0
+// if the post-compaction size isn't known yet.
+func (c Compaction) Dropped() int64 {
+	if c.PostTokens == 0 || c.PostTokens > c.PreTokens {
+		return 0
+	}
+	return c.PreTokens - c.PostTokens
+}
+
+// contextTokens returns the prompt size of an assistant entry's request.
+func contextTokens(line []byte) (int64, bool) {
+	entry, err := decodeEntry(line)
+	if err != nil || entry.Type != "assistant" {
+		return 0, false
+	}
+	u := entry.body().Usage
+	if u == nil {
+		return 0, false
+	}
+	return u.contextTokens(), true
+}
+
+// ReadCompactions returns the compactions in a transcript in order.
+// Claude Code marks each with a compact_boundary system entry followed by a
+// user entry holding the summary. When the boundary carries no token count,
+// the context size of the last request before it is used.
+func ReadCompactions(path string) ([]Compaction, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var compactions []Compaction
+	var lastContext int64
+	turns := 0
+	awaitingPost := false
+
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		entry, err := decodeEntry(line)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case entry.Type == "system" && entry.Subtype == "compact_boundary":
+			c := Compaction{Timestamp: entry.Timestamp, PreTokens: lastContext, Turns: turns}
+			if md := entry.CompactMetadata; md != nil {
+				c.Trigger = md.Trigger
+				if md.PreTokens > 0 {
+					c.PreTokens = md.PreTokens
+				}
+			}
+			compactions = append(compactions, c)
+			turns = 0
+			awaitingPost = true
+
+		case entry.IsCompactSummary && len(compactions) > 0:
+			compactions[len(compactions)-1].Summary = messageText(entry.Message)
+
+		case entry.Type == "user" || entry.Type == "assistant":
+			if tokens, ok := contextTokens(line); ok {
+				lastContext = tokens
+				if awaitingPost {
+					compactions[len(compactions)-1].PostTokens = tokens
+					awaitingPost = false
+				}
+			}
+			if _, ok := ParseTurn(line); ok {
+				turns++
+			}
+		}
+	}
+	return compactions, scanner.Err()
+}
@@ -0,0 +1,78 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCompactions(t *testing.T) {
+	lines := []string{
+		`{"type":"user","timestamp":"2025-01-01T10:00:00Z","message":{"role":"user","content":"Build the ledger"}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T10:01:00Z","message":{"id":"m1","role":"assistant","content":[{"type":"text","text":"On it"}],"usage":{"input_tokens":10,"cache_read_input_tokens":150000,"cache_creation_input_tokens":5000,"output_tokens":100}}}`,
+		`{"type":"system","subtype":"compact_boundary","timestamp":"2025-01-01T10:02:00Z","compactMetadata":{"trigger":"auto","preTokens":160000}}`,
+		`{"type":"user","isCompactSummary":true,"timestamp":"2025-01-01T10:02:01Z","message":{"role":"user","content":"Summary: building the ledger, tests pass."}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T10:03:00Z","message":{"id":"m2","role":"assistant","content":[{"type":"text","text":"Continuing"}],"usage":{"input_tokens":20,"cache_read_input_tokens":0,"cache_creation_input_tokens":19980,"output_tokens":50}}}`,
+		// Manual compaction without a token count falls back to the last request
+		`{"type":"system","subtype":"compact_boundary","timestamp":"2025-01-01T11:00:00Z","compactMetadata":{"trigger":"manual"}}`,
+	}
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	compactions, err := ReadCompactions(path)
+	if err != nil {
+		t.Fatalf("ReadCompactions: %v", err)
+	}
+	if len(compactions) != 2 {
+		t.Fatalf("got %d compactions, want 2", len(compactions))
+	}
+
+	first := compactions[0]
+	if first.Trigger != "auto" || first.PreTokens != 160000 || first.PostTokens != 20000 {
+		t.Errorf("first = %+v", first)
+	}
+	if first.Dropped() != 140000 {
+		t.Errorf("Dropped = %d, want 140000", first.Dropped())
+	}
+	if first.Turns != 2 {
+		t.Errorf("Turns = %d, want 2", first.Turns)
+	}
+	if !strings.Contains(first.Summary, "tests pass") {
+		t.Errorf("Summary = %q", first.Summary)
+	}
+
+	second := compactions[1]
+	if second.Trigger != "manual" || second.PreTokens != 20000 || second.PostTokens != 0 || second.Dropped() != 0 {
+		t.Errorf("second = %+v", second)
+	}
+}
+
+func TestSessionCompactionCount(t *testing.T) {
+	home := t.TempDir()
+	path := writeSession(t, home, "-home-u-proj", "s1.jsonl",
+		`{"type":"user","timestamp":"2025-01-01T10:00:00Z","message":{"role":"user","content":"Build the ledger"}}`,
+		`{"type":"system","subtype":"compact_boundary","timestamp":"2025-01-01T10:02:00Z","compactMetadata":{"trigger":"auto","preTokens":160000}}`,
+		`{"type":"user","isCompactSummary":true,"timestamp":"2025-01-01T10:02:01Z","message":{"role":"user","content":"Summary: building the ledger."}}`,
+		// Older format: a summary with no boundary before it
+		`{"type":"user","isCompactSummary":true,"timestamp":"2025-01-01T11:30:00Z","message":{"role":"user","content":"Summary: still building."}}`,
+		`{"type":"user","timestamp":"2025-01-01T11:31:00Z","message":{"role":"user","content":"keep going"}}`,
+	)
+
+	info, err := parseSession(path, "-home-u-proj")
+	if err != nil {
+		t.Fatalf("parseSession: %v", err)
+	}
+	if info.CompactionCount != 2 {
+		t.Errorf("CompactionCount = %d, want 2", info.CompactionCount)
+	}
+	if want := "2025-01-01T11:30:00Z"; info.LastCompaction.Format(time.RFC3339) != want {
+		t.Errorf("LastCompaction = %v, want %s", info.LastCompaction, want)
+	}
+	if info.UserMessages != 2 {
+		t.Errorf("UserMessages = %d, want 2 (summaries aren't prompts)", info.UserMessages)
+	}
+}
@@ -0,0 +1,241 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// SessionProfile condenses a session into what two attempts at the same
+// task are compared on.
+type SessionProfile struct {
+	Session        *SessionInfo   `json:"session"`
+	Start          time.Time      `json:"start"`
+	End            time.Time      `json:"end"`
+	Turns          int            `json:"turns"` // user and assistant messages with text
+	ToolCalls      map[string]int `json:"tool_calls"`
+	FilesWritten   []string       `json:"files_written"` // relative paths, in first-written order
+	Commands       int            `json:"commands"`
+	FailedCommands int            `json:"failed_commands"`
+}
+
+// Duration returns the time between the first and last message.
+func (p *SessionProfile) Duration() time.Duration {
+	if p.Start.IsZero() || p.End.Before(p.Start) {
+		return 0
+	}
+	return p.End.Sub(p.Start)
+}
+
+// Profile condenses the transcript for comparison. Subagent traffic is
+// included, since delegated work is part of the session's approach.
+func (t *Transcript) Profile() *SessionProfile {
+	p := &SessionProfile{Session: t.Session, ToolCalls: make(map[string]int), FilesWritten: []string{}}
+	for _, m := range t.Messages {
+		if !m.Timestamp.IsZero() {
+			if p.Start.IsZero() {
+				p.Start = m.Timestamp
+			}
+			p.End = m.Timestamp
+		}
+		if (m.Type == "user" || m.Type == "assistant") && strings.TrimSpace(m.Text) != "" {
+			p.Turns++
+		}
+		for _, u := range m.ToolUses {
+			p.ToolCalls[u.Name]++
+		}
+	}
+
+	a := t.Activity()
+	for _, f := range a.FilesWritten {
+		p.FilesWritten = append(p.FilesWritten, f.RelPath)
+	}
+	p.Commands = len(a.Commands)
+	for _, c := range a.Commands {
+		if c.Failed {
+			p.FailedCommands++
+		}
+	}
+	return p
+}
+
+// SessionDiff compares two session profiles.
+type SessionDiff struct {
+	A          *SessionProfile `json:"a"`
+	B          *SessionProfile `json:"b"`
+	FilesBoth  []string        `json:"files_both"`
+	FilesOnlyA []string        `json:"files_only_a"`
+	FilesOnlyB []string        `json:"files_only_b"`
+}
+
+// DiffSessions compares two profiles: which files each wrote that the
+// other didn't. Counts are compared by the caller from A and B.
+func DiffSessions(a, b *SessionProfile) *SessionDiff {
+	d := &SessionDiff{A: a, B: b, FilesBoth: []string{}, FilesOnlyA: []string{}, FilesOnlyB: []string{}}
+	inB := make(map[string]bool, len(b.FilesWritten))
+	for _, f := range b.FilesWritten {
+		inB[f] = true
+	}
+	inA := make(map[string]bool, len(a.FilesWritten))
+	for _, f := range a.FilesWritten {
+		inA[f] = true
+		if inB[f] {
+			d.FilesBoth = append(d.FilesBoth, f)
+		} else {
+			d.FilesOnlyA = append(d.FilesOnlyA, f)
+		}
+	}
+	for _, f := range b.FilesWritten {
+		if !inA[f] {
+			d.FilesOnlyB = append(d.FilesOnlyB, f)
+		}
+	}
+	sort.Strings(d.FilesBoth)
+	sort.Strings(d.FilesOnlyA)
+	sort.Strings(d.FilesOnlyB)
+	return d
+}
+
+// ConversationalDiff is a model-written comparison of how two sessions
+// approached the same task.
+type ConversationalDiff struct {
+	Summary     string      `json:"summary"`
+	Differences []DiffPoint `json:"differences"`
+	Verdict     string      `json:"verdict,omitempty"`
+	Model       string      `json:"model,omitempty"`
+	GeneratedAt time.Time   `json:"generated_at"`
+}
+
+// DiffPoint is one way the two approaches differed.
+type DiffPoint struct {
+	Aspect string `json:"aspect"` // e.g. "testing", "migrations"
+	A      string `json:"a"`
+	B      string `json:"b"`
+}
+
+// ParseConversationalDiff reads a ConversationalDiff from model output:
+// a JSON object, possibly wrapped in a code fence or surrounded by prose.
+func ParseConversationalDiff(output string) (*ConversationalDiff, error) {
+	start := strings.Index(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in output")
+	}
+	var d ConversationalDiff
+	if err := json.Unmarshal([]byte(output[start:end+1]), &d); err != nil {
+		return nil, fmt.Errorf("parsing comparison: %w", err)
+	}
+	if d.Summary == "" && len(d.Differences) == 0 {
+		return nil, fmt.Errorf("comparison is empty")
+	}
+	return &d, nil
+}
+
+// comparisonKey keys a cached comparison by both transcript paths. Order
+// matters: A and B are reported as given.
+func comparisonKey(a, b string) string {
+	return a + "\x00" + b
+}
+
+// splitComparisonKey returns the transcript paths in a comparisonKey.
+func splitComparisonKey(key string) (string, string) {
+	a, b, _ := strings.Cut(key, "\x00")
+	return a, b
+}
+
+// Comparisons cost a model call each, so they're cached, but not in the
+// session index, which discovery rewrites without a lock: one stored
+// while another process saved the index would be lost. They're kept in a
+// file of their own next to it, changed only under its lock.
+
+// comparisonEntry is a cached conversational diff. It is valid while
+// both transcripts are unchanged.
+type comparisonEntry struct {
+	ModTimeA int64              `json:"mtime_a"`
+	SizeA    int64              `json:"size_a"`
+	ModTimeB int64              `json:"mtime_b"`
+	SizeB    int64              `json:"size_b"`
+	Diff     ConversationalDiff `json:"diff"`
+}
+
+// sessionComparisonsPath returns the comparison cache.
+func sessionComparisonsPath() string {
+	return filepath.Join(state.CacheDir(), "session-comparisons.json")
+}
+
+// loadComparisons reads the comparison cache. A missing or unreadable
+// cache is empty.
+func loadComparisons() map[string]comparisonEntry {
+	comparisons := make(map[string]comparisonEntry)
+	if data, err := os.ReadFile(sessionComparisonsPath()); err == nil {
+		_ = json.Unmarshal(data, &comparisons)
+	}
+	return comparisons
+}
+
+// CachedConversationalDiff returns the cached comparison of a and b, if
+// there is one and neither transcript has changed since it was made.
+func CachedConversationalDiff(a, b *SessionInfo) (*ConversationalDiff, bool) {
+	if !sessionIndexEnabled() {
+		return nil, false
+	}
+	statA, errA := os.Stat(a.Path)
+	statB, errB := os.Stat(b.Path)
+	if errA != nil || errB != nil {
+		return nil, false
+	}
+	entry, ok := loadComparisons()[comparisonKey(a.Path, b.Path)]
+	if !ok || entry.SizeA != statA.Size() || entry.ModTimeA != statA.ModTime().UnixNano() ||
+		entry.SizeB != statB.Size() || entry.ModTimeB != statB.ModTime().UnixNano() {
+		return nil, false
+	}
+	d := entry.Diff
+	return &d, true
+}
+
+// StoreConversationalDiff caches a comparison of a and b, dropping cached
+// comparisons of transcripts that no longer exist. It is a no-op when the
+// session index is disabled.
+func StoreConversationalDiff(a, b *SessionInfo, d *ConversationalDiff) error {
+	if !sessionIndexEnabled() {
+		return nil
+	}
+	statA, err := os.Stat(a.Path)
+	if err != nil {
+		return err
+	}
+	statB, err := os.Stat(b.Path)
+	if err != nil {
+		return err
+	}
+	path := sessionComparisonsPath()
+	return withFileLock(path, "session comparisons", func() error {
+		comparisons := loadComparisons()
+		for key := range comparisons {
+			pathA, pathB := splitComparisonKey(key)
+			_, errA := os.Stat(pathA)
+			_, errB := os.Stat(pathB)
+			if os.IsNotExist(errA) || os.IsNotExist(errB) {
+				delete(comparisons, key)
+			}
+		}
+		comparisons[comparisonKey(a.Path, b.Path)] = comparisonEntry{
+			ModTimeA: statA.ModTime().UnixNano(),
+			SizeA:    statA.Size(),
+			ModTimeB: statB.ModTime().UnixNano(),
+			SizeB:    statB.Size(),
+			Diff:     *d,
+		}
+		if err := util.AtomicWriteJSON(path, comparisons); err != nil {
+			return fmt.Errorf("writing session comparisons: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,128 @@
+package claude
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffSessions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cwd := `"cwd":"/town/gastown/polecats/toast"`
+	edit := func(id, file string) string {
+		return `{"type":"assistant","timestamp":"2025-01-01T00:01:00Z",` + cwd + `,"message":{"content":[{"type":"tool_use","id":"` + id + `","name":"Edit","input":{"file_path":"/town/gastown/polecats/toast/` + file + `"}}]}}`
+	}
+	writeSession(t, home, "-town-gastown-polecats-toast", "aaaa0001.jsonl",
+		`{"type":"user","timestamp":"2025-01-01T00:00:00Z",`+cwd+`,"message":{"role":"user","content":"fix the widget"}}`,
+		edit("t1", "widget_test.go"),
+		edit("t2", "widget.go"),
+		`{"type":"assistant","timestamp":"2025-01-01T00:10:00Z",`+cwd+`,"message":{"content":[{"type":"text","text":"done"},{"type":"tool_use","id":"t3","name":"Bash","input":{"command":"go test ./..."}}]}}`,
+		`{"type":"user","timestamp":"2025-01-01T00:10:01Z",`+cwd+`,"message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t3","content":"FAIL","is_error":true}]}}`,
+	)
+	writeSession(t, home, "-town-gastown-polecats-toast", "bbbb0002.jsonl",
+		`{"type":"user","timestamp":"2025-01-02T00:00:00Z",`+cwd+`,"message":{"role":"user","content":"fix the widget"}}`,
+		edit("t1", "widget.go"),
+		edit("t2", "migrate.sql"),
+	)
+
+	ta, err := LoadTranscript("aaaa")
+	if err != nil {
+		t.Fatalf("LoadTranscript: %v", err)
+	}
+	tb, err := LoadTranscript("bbbb")
+	if err != nil {
+		t.Fatalf("LoadTranscript: %v", err)
+	}
+	a, b := ta.Profile(), tb.Profile()
+	if a.Turns != 2 || a.Duration() != 10*time.Minute+time.Second {
+		t.Errorf("A turns = %d, duration = %v", a.Turns, a.Duration())
+	}
+	if a.ToolCalls["Edit"] != 2 || a.Commands != 1 || a.FailedCommands != 1 {
+		t.Errorf("A tools = %v, commands = %d (%d failed)", a.ToolCalls, a.Commands, a.FailedCommands)
+	}
+
+	d := DiffSessions(a, b)
+	if !reflect.DeepEqual(d.FilesBoth, []string{"widget.go"}) ||
+		!reflect.DeepEqual(d.FilesOnlyA, []string{"widget_test.go"}) ||
+		!reflect.DeepEqual(d.FilesOnlyB, []string{"migrate.sql"}) {
+		t.Errorf("files: both %v, only A %v, only B %v", d.FilesBoth, d.FilesOnlyA, d.FilesOnlyB)
+	}
+}
+
+func TestParseConversationalDiff(t *testing.T) {
+	out := "Here is the comparison:\n```json\n" +
+		`{"summary":"A tested first","differences":[{"aspect":"testing","a":"wrote tests first","b":"no tests"}],"verdict":"A"}` +
+		"\n```\n"
+	d, err := ParseConversationalDiff(out)
+	if err != nil {
+		t.Fatalf("ParseConversationalDiff: %v", err)
+	}
+	if d.Summary != "A tested first" || len(d.Differences) != 1 || d.Differences[0].Aspect != "testing" || d.Verdict != "A" {
+		t.Errorf("parsed = %+v", d)
+	}
+
+	for _, bad := range []string{"no json here", `{"summary":""}`, `{"summary":`} {
+		if _, err := ParseConversationalDiff(bad); err == nil {
+			t.Errorf("ParseConversationalDiff(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestConversationalDiffCache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	pathA := writeSession(t, home, "-town", "aaaa0001.jsonl", `{"type":"user","message":{"role":"user","content":"a"}}`)
+	pathB := writeSession(t, home, "-town", "bbbb0002.jsonl", `{"type":"user","message":{"role":"user","content":"b"}}`)
+	a, b := &SessionInfo{Path: pathA}, &SessionInfo{Path: pathB}
+
+	if _, ok := CachedConversationalDiff(a, b); ok {
+		t.Fatal("cache hit before store")
+	}
+	if err := StoreConversationalDiff(a, b, &ConversationalDiff{Summary: "differ"}); err != nil {
+		t.Fatalf("StoreConversationalDiff: %v", err)
+	}
+	if d, ok := CachedConversationalDiff(a, b); !ok || d.Summary != "differ" {
+		t.Errorf("cached = %+v, %v", d, ok)
+	}
+	if _, ok := CachedConversationalDiff(b, a); ok {
+		t.Error("cache hit with sessions swapped")
+	}
+
+	// Discovery keeps comparisons of live sessions
+	if _, err := DiscoverSessions(SessionFilter{}); err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if _, ok := CachedConversationalDiff(a, b); !ok {
+		t.Error("discovery dropped a live comparison")
+	}
+
+	// Resetting the index keeps them too; they're cached apart from it
+	if err := InvalidateSessionIndex(); err != nil {
+		t.Fatalf("InvalidateSessionIndex: %v", err)
+	}
+	if _, ok := CachedConversationalDiff(a, b); !ok {
+		t.Error("index reset dropped a comparison")
+	}
+
+	// A transcript that grew invalidates the comparison
+	if err := os.WriteFile(pathB, []byte(`{"type":"user","message":{"role":"user","content":"b, continued"}}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := CachedConversationalDiff(a, b); ok {
+		t.Error("cache hit after transcript changed")
+	}
+
+	// Storing a comparison drops those of deleted transcripts
+	if err := os.Remove(pathA); err != nil {
+		t.Fatal(err)
+	}
+	if err := StoreConversationalDiff(b, b, &ConversationalDiff{Summary: "same"}); err != nil {
+		t.Fatalf("StoreConversationalDiff: %v", err)
+	}
+	if _, ok := loadComparisons()[comparisonKey(pathA, pathB)]; ok {
+		t.Error("comparison of a deleted transcript kept")
+	}
+}
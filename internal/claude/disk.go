@@ -0,0 +1,106 @@
+package claude
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// ProjectDiskUsage is the space one project directory takes up.
+type ProjectDiskUsage struct {
+	Project     string   `json:"project"`                // encoded project directory name
+	ProjectPath string   `json:"project_path,omitempty"` // working directory its sessions ran in
+	Root        string   `json:"root"`                   // Claude data directory it is in
+	Rigs        []string `json:"rigs,omitempty"`         // rigs of its Gas Town sessions
+	Sessions    int      `json:"sessions"`
+	Bytes       int64    `json:"bytes"`
+}
+
+// DiskUsageReport is the space session data takes up across the Claude
+// data directories.
+type DiskUsageReport struct {
+	Bytes    int64              `json:"bytes"`
+	Projects []ProjectDiskUsage `json:"projects"` // largest first
+
+	// Sessions are the discovered sessions, whose DiskSize attributes the
+	// space to them.
+	Sessions []SessionInfo `json:"-"`
+}
+
+// DiskUsage measures every project directory in the Claude data
+// directories, counting all files in them: transcripts, subagent
+// transcripts, and tool output. Projects are attributed a working
+// directory and rigs from their sessions. Like DiscoverSessions, it
+// returns what it found along with ErrIncomplete when discovery timed out.
+func DiskUsage() (*DiskUsageReport, error) {
+	sessions, err := DiscoverSessions(SessionFilter{})
+	if err != nil && len(sessions) == 0 {
+		return nil, err
+	}
+
+	type key struct{ root, project string }
+	byProject := make(map[key]*ProjectDiskUsage)
+	for _, root := range resolveRoots(Roots()) {
+		projects, readErr := os.ReadDir(filepath.Join(root, "projects"))
+		if readErr != nil {
+			continue
+		}
+		for _, p := range projects {
+			if !p.IsDir() {
+				continue
+			}
+			byProject[key{root, p.Name()}] = &ProjectDiskUsage{
+				Project: p.Name(),
+				Root:    root,
+				Bytes:   dirSize(filepath.Join(root, "projects", p.Name())),
+			}
+		}
+	}
+
+	for _, s := range sessions {
+		p := byProject[key{s.Root, s.Project}]
+		if p == nil {
+			continue
+		}
+		p.Sessions++
+		if p.ProjectPath == "" {
+			p.ProjectPath = s.ProjectPath
+		}
+		if s.Rig != "" && !slices.Contains(p.Rigs, s.Rig) {
+			p.Rigs = append(p.Rigs, s.Rig)
+		}
+	}
+
+	report := &DiskUsageReport{Projects: make([]ProjectDiskUsage, 0, len(byProject)), Sessions: sessions}
+	for _, p := range byProject {
+		sort.Strings(p.Rigs)
+		report.Bytes += p.Bytes
+		report.Projects = append(report.Projects, *p)
+	}
+	sort.Slice(report.Projects, func(i, j int) bool {
+		a, b := report.Projects[i], report.Projects[j]
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		return a.Project < b.Project
+	})
+	return report, err
+}
+
+// dirSize returns the total size of the files under dir, or 0 if it
+// doesn't exist.
+func dirSize(dir string) int64 {
+	var total int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
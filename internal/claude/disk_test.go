@@ -0,0 +1,50 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiskUsage(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	beacon := `{"type":"user","timestamp":"2025-12-30T15:42:00Z","cwd":"/home/u/gt/gastown/polecats/toast","message":{"role":"user","content":"[GAS TOWN] gastown/polecats/toast <- witness • 2025-12-30T15:42 • work"}}`
+	big := writeSession(t, home, "-home-u-gt-gastown", "aaaa1111.jsonl", beacon)
+	toolOutput := filepath.Join(filepath.Dir(big), "aaaa1111", "tool-results")
+	if err := os.MkdirAll(toolOutput, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(toolOutput, "out.txt"), make([]byte, 4096), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeSession(t, home, "-home-u-other", "bbbb2222.jsonl",
+		`{"type":"user","timestamp":"2025-12-30T15:42:00Z","cwd":"/home/u/other","message":{"role":"user","content":"hi"}}`,
+	)
+
+	report, err := DiskUsage()
+	if err != nil {
+		t.Fatalf("DiskUsage: %v", err)
+	}
+	if len(report.Projects) != 2 {
+		t.Fatalf("projects = %+v", report.Projects)
+	}
+	top := report.Projects[0]
+	stat, _ := os.Stat(big)
+	if top.Project != "-home-u-gt-gastown" || top.Bytes != stat.Size()+4096 || top.Sessions != 1 {
+		t.Errorf("largest project = %+v", top)
+	}
+	if len(top.Rigs) != 1 || top.Rigs[0] != "gastown" || top.ProjectPath != "/home/u/gt/gastown/polecats/toast" {
+		t.Errorf("largest project attribution = %+v", top)
+	}
+	if report.Bytes != top.Bytes+report.Projects[1].Bytes {
+		t.Errorf("total = %d", report.Bytes)
+	}
+
+	for _, s := range report.Sessions {
+		if s.ID == "aaaa1111" && s.DiskSize != stat.Size()+4096 {
+			t.Errorf("session DiskSize = %d, want transcript plus tool output", s.DiskSize)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// editTools are the tools that modify files, mapped to their path input.
+var editTools = map[string]string{
+	"Edit":         "file_path",
+	"MultiEdit":    "file_path",
+	"Write":        "file_path",
+	"NotebookEdit": "notebook_path",
+}
+
+// FileEdit is one file modification made by an agent.
+type FileEdit struct {
+	Path      string    `json:"path"`     // absolute path as given to the tool
+	RelPath   string    `json:"rel_path"` // path relative to the session's cwd
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ReadFileEdits returns the files a session modified at or after since.
+// RelPath is relative to the working directory recorded with each entry,
+// so the same file edited in two worktrees of one repo compares equal.
+func ReadFileEdits(path string, since time.Time) ([]FileEdit, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var edits []FileEdit
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if !strings.Contains(string(line), `"tool_use"`) {
+			continue
+		}
+
+		entry, err := decodeEntry(line)
+		if err != nil || entry.Type != "assistant" {
+			continue
+		}
+		ts := entry.Timestamp
+		if ts.IsZero() || ts.Before(since) {
+			continue
+		}
+
+		var blocks []struct {
+			Type  string                     `json:"type"`
+			Name  string                     `json:"name"`
+			Input map[string]json.RawMessage `json:"input"`
+		}
+		if err := json.Unmarshal(entry.body().Content, &blocks); err != nil {
+			continue
+		}
+		for _, b := range blocks {
+			key, ok := editTools[b.Name]
+			if b.Type != "tool_use" || !ok {
+				continue
+			}
+			var target string
+			if err := json.Unmarshal(b.Input[key], &target); err != nil || target == "" {
+				continue
+			}
+			edits = append(edits, FileEdit{
+				Path:      target,
+				RelPath:   relativeTo(entry.Cwd, target),
+				Timestamp: ts,
+			})
+		}
+	}
+	return edits, scanner.Err()
+}
+
+// relativeTo returns target relative to dir, or target unchanged when it
+// lies outside dir.
+func relativeTo(dir, target string) string {
+	if dir == "" || !filepath.IsAbs(target) {
+		return filepath.ToSlash(target)
+	}
+	rel, err := filepath.Rel(dir, target)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(target)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,30 @@
+package claude
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadFileEdits(t *testing.T) {
+	home := t.TempDir()
+	path := writeSession(t, home, "-town-gastown-polecats-toast", "s1.jsonl",
+		`{"type":"assistant","timestamp":"2025-01-01T00:00:00Z","cwd":"/town/gastown/polecats/toast","message":{"content":[{"type":"tool_use","name":"Edit","input":{"file_path":"/town/gastown/polecats/toast/old.go"}}]}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T01:00:00Z","cwd":"/town/gastown/polecats/toast","message":{"content":[{"type":"text","text":"editing"},{"type":"tool_use","name":"Write","input":{"file_path":"/town/gastown/polecats/toast/internal/a.go"}},{"type":"tool_use","name":"Read","input":{"file_path":"/town/gastown/polecats/toast/internal/b.go"}}]}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T01:05:00Z","cwd":"/town/gastown/polecats/toast","message":{"content":[{"type":"tool_use","name":"Edit","input":{"file_path":"/etc/hosts"}}]}}`,
+	)
+
+	since := time.Date(2025, 1, 1, 0, 30, 0, 0, time.UTC)
+	edits, err := ReadFileEdits(path, since)
+	if err != nil {
+		t.Fatalf("ReadFileEdits: %v", err)
+	}
+	if len(edits) != 2 {
+		t.Fatalf("got %d edits, want 2: %+v", len(edits), edits)
+	}
+	if edits[0].RelPath != "internal/a.go" {
+		t.Errorf("edit 0 RelPath = %q, want internal/a.go", edits[0].RelPath)
+	}
+	if edits[1].RelPath != "/etc/hosts" {
+		t.Errorf("edit 1 RelPath = %q, want /etc/hosts (outside cwd)", edits[1].RelPath)
+	}
+}
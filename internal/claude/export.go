@@ -0,0 +1,320 @@
+package claude
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// Export formats.
+const (
+	ExportMarkdown = "markdown"
+	ExportHTML     = "html"
+)
+
+// ExportFormats lists the formats ExportTranscript accepts.
+func ExportFormats() []string {
+	return []string{ExportMarkdown, ExportHTML}
+}
+
+// ExportTranscript renders a session as a readable document: Markdown, or
+// a standalone HTML page with no external assets. User and assistant turns
+// are shown in order with their timestamps, and each tool call is folded
+// into a collapsed <details> block holding its input and output. Entries
+// of types this gt doesn't recognize are appended verbatim, so nothing a
+// newer Claude Code wrote is lost. id is a session ID, alias, or unique
+// prefix (see FindSession).
+func ExportTranscript(id, format string) ([]byte, error) {
+	session, err := FindSession(id)
+	if err != nil {
+		return nil, err
+	}
+	return exportSession(session, format)
+}
+
+// exportSession renders session's transcript in format.
+func exportSession(session *SessionInfo, format string) ([]byte, error) {
+	turns, err := ReadTranscriptTurns(session.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading transcript: %w", err)
+	}
+	schema, err := ReadTranscriptSchema(session.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading transcript: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := writeExport(&buf, session, turns, schema.Unknown, format); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeExport renders turns of session, then its unrecognized entries,
+// to w in format.
+func writeExport(w io.Writer, session *SessionInfo, turns []TranscriptTurn, unknown []RawEntry, format string) error {
+	switch format {
+	case ExportMarkdown, "md":
+		return writeMarkdownExport(w, session, turns, unknown)
+	case ExportHTML:
+		return writeHTMLExport(w, session, turns, unknown)
+	}
+	return fmt.Errorf("unknown export format %q (want %s)", format, strings.Join(ExportFormats(), " or "))
+}
+
+// exportTitle is the document title: the session's role or directory and
+// its short ID.
+func exportTitle(session *SessionInfo) string {
+	label := session.Role
+	if label == "" {
+		label = session.ProjectPath
+	}
+	return fmt.Sprintf("Session %s — %s", session.ShortID(), label)
+}
+
+// exportTime formats a turn timestamp, or "" when unknown.
+func exportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
+
+// exportInput pretty-prints a tool call's JSON input.
+func exportInput(raw json.RawMessage) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, raw, "", "  "); err != nil {
+		return string(raw)
+	}
+	return out.String()
+}
+
+// exportCallSummary is the one-line label a collapsed tool call shows.
+func exportCallSummary(call ToolCall) string {
+	summary := call.Name
+	if target := toolCallTarget(call); target != "" && target != "{}" {
+		summary += ": " + firstLine(target)
+	}
+	switch {
+	case call.Pending:
+		summary += " (no result)"
+	case call.IsError:
+		summary += " (error)"
+	}
+	return summary
+}
+
+// exportRawSummary is the label of a collapsed unrecognized entry.
+func exportRawSummary(e RawEntry) string {
+	if e.Type == "" {
+		return fmt.Sprintf("line %d", e.Line)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Type)
+}
+
+// mdFence returns a code fence longer than any backtick run in s, so tool
+// output containing fences can't end the block early.
+func mdFence(s string) string {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+func writeMarkdownExport(w io.Writer, session *SessionInfo, turns []TranscriptTurn, unknown []RawEntry) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", exportTitle(session))
+	fmt.Fprintf(&b, "- Session: `%s`\n", session.ID)
+	if session.ProjectPath != "" {
+		fmt.Fprintf(&b, "- Directory: `%s`\n", session.ProjectPath)
+	}
+	if !session.StartTime.IsZero() {
+		fmt.Fprintf(&b, "- Started: %s\n", exportTime(session.StartTime))
+	}
+	if session.Summary != "" {
+		fmt.Fprintf(&b, "- Summary: %s\n", session.Summary)
+	}
+
+	for _, turn := range turns {
+		heading := "User"
+		if turn.Role == "assistant" {
+			heading = "Assistant"
+		}
+		fmt.Fprintf(&b, "\n## %s", heading)
+		if ts := exportTime(turn.Timestamp); ts != "" {
+			fmt.Fprintf(&b, " · %s", ts)
+		}
+		b.WriteString("\n\n")
+		if turn.Text != "" {
+			b.WriteString(strings.TrimSpace(turn.Text))
+			b.WriteString("\n\n")
+		}
+		for _, call := range turn.ToolCalls {
+			fmt.Fprintf(&b, "<details>\n<summary>%s</summary>\n\n", template.HTMLEscapeString(exportCallSummary(call)))
+			input := exportInput(call.Input)
+			fence := mdFence(input)
+			fmt.Fprintf(&b, "%sjson\n%s\n%s\n\n", fence, input, fence)
+			if call.Result != "" {
+				fence = mdFence(call.Result)
+				fmt.Fprintf(&b, "%s\n%s\n%s\n\n", fence, strings.TrimRight(call.Result, "\n"), fence)
+			}
+			b.WriteString("</details>\n\n")
+		}
+	}
+
+	if len(unknown) > 0 {
+		b.WriteString("\n## Unrecognized entries\n\n")
+		for _, e := range unknown {
+			fmt.Fprintf(&b, "<details>\n<summary>%s</summary>\n\n", template.HTMLEscapeString(exportRawSummary(e)))
+			raw := exportInput(e.Raw)
+			fence := mdFence(raw)
+			fmt.Fprintf(&b, "%sjson\n%s\n%s\n\n</details>\n\n", fence, raw, fence)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// htmlExportTemplate is a self-contained page: inline styles, no scripts.
+var htmlExportTemplate = template.Must(template.New("export").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #1f2328; }
+header dl { display: grid; grid-template-columns: max-content 1fr; gap: 0.2rem 1rem; color: #57606a; }
+header dd { margin: 0; }
+.turn { border-left: 4px solid #d0d7de; margin: 1.5rem 0; padding: 0.25rem 1rem; }
+.turn.user { border-color: #0969da; }
+.turn.assistant { border-color: #8250df; }
+.turn.unknown { border-color: #bf8700; }
+.turn h2 { font-size: 1rem; margin: 0.25rem 0; }
+.turn time { color: #57606a; font-weight: normal; font-size: 0.85rem; margin-left: 0.5rem; }
+.text { white-space: pre-wrap; }
+details { margin: 0.5rem 0; background: #f6f8fa; border-radius: 6px; padding: 0.25rem 0.75rem; }
+details.error summary { color: #cf222e; }
+summary { cursor: pointer; font-family: ui-monospace, monospace; font-size: 0.85rem; }
+pre { overflow-x: auto; font-size: 0.8rem; background: #fff; padding: 0.5rem; border: 1px solid #d0d7de; border-radius: 4px; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<dl>
+<dt>Session</dt><dd><code>{{.Session.ID}}</code></dd>
+{{- if .Session.ProjectPath}}
+<dt>Directory</dt><dd><code>{{.Session.ProjectPath}}</code></dd>
+{{- end}}
+{{- if .Started}}
+<dt>Started</dt><dd>{{.Started}}</dd>
+{{- end}}
+{{- if .Session.Summary}}
+<dt>Summary</dt><dd>{{.Session.Summary}}</dd>
+{{- end}}
+</dl>
+</header>
+<main>
+{{- range .Turns}}
+<section class="turn {{.Role}}">
+<h2>{{.Heading}}{{if .Time}}<time datetime="{{.ISOTime}}">{{.Time}}</time>{{end}}</h2>
+{{- if .Text}}
+<div class="text">{{.Text}}</div>
+{{- end}}
+{{- range .Calls}}
+<details{{if .IsError}} class="error"{{end}}>
+<summary>{{.Summary}}</summary>
+<pre>{{.Input}}</pre>
+{{- if .Result}}
+<pre>{{.Result}}</pre>
+{{- end}}
+</details>
+{{- end}}
+</section>
+{{- end}}
+{{- if .Unknown}}
+<section class="turn unknown">
+<h2>Unrecognized entries</h2>
+{{- range .Unknown}}
+<details>
+<summary>{{.Summary}}</summary>
+<pre>{{.Raw}}</pre>
+</details>
+{{- end}}
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+`))
+
+type htmlExportCall struct {
+	Summary string
+	Input   string
+	Result  string
+	IsError bool
+}
+
+type htmlExportTurn struct {
+	Role    string
+	Heading string
+	Time    string
+	ISOTime string
+	Text    string
+	Calls   []htmlExportCall
+}
+
+type htmlExportRaw struct {
+	Summary string
+	Raw     string
+}
+
+func writeHTMLExport(w io.Writer, session *SessionInfo, turns []TranscriptTurn, unknown []RawEntry) error {
+	data := struct {
+		Title   string
+		Session *SessionInfo
+		Started string
+		Turns   []htmlExportTurn
+		Unknown []htmlExportRaw
+	}{
+		Title:   exportTitle(session),
+		Session: session,
+		Started: exportTime(session.StartTime),
+	}
+	for _, turn := range turns {
+		t := htmlExportTurn{
+			Role:    turn.Role,
+			Heading: "User",
+			Time:    exportTime(turn.Timestamp),
+			Text:    strings.TrimSpace(turn.Text),
+		}
+		if turn.Role == "assistant" {
+			t.Heading = "Assistant"
+		}
+		if !turn.Timestamp.IsZero() {
+			t.ISOTime = turn.Timestamp.UTC().Format(time.RFC3339)
+		}
+		for _, call := range turn.ToolCalls {
+			t.Calls = append(t.Calls, htmlExportCall{
+				Summary: exportCallSummary(call),
+				Input:   exportInput(call.Input),
+				Result:  strings.TrimRight(call.Result, "\n"),
+				IsError: call.IsError,
+			})
+		}
+		data.Turns = append(data.Turns, t)
+	}
+	for _, e := range unknown {
+		data.Unknown = append(data.Unknown, htmlExportRaw{Summary: exportRawSummary(e), Raw: exportInput(e.Raw)})
+	}
+	return htmlExportTemplate.Execute(w, data)
+}
@@ -0,0 +1,148 @@
+package claude
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// ExportManifestName is the index file ExportSessions writes.
+const ExportManifestName = "index.md"
+
+// ExportedSession is one session written by ExportSessions.
+type ExportedSession struct {
+	Session SessionInfo
+	File    string // file name within the export directory
+}
+
+// ExportCheckpointName is the file in an export directory that records
+// which sessions ExportSessions has exported, until it finishes.
+const ExportCheckpointName = ".export-checkpoint.json"
+
+// ExportSessions exports each session to its own file in dir, named by
+// start date and short ID, and writes an index.md manifest listing them
+// with their role, topic, start time, duration, and a link to each file.
+// The manifest is Markdown whatever the format. Sessions are listed in
+// the order given. dir is created if needed.
+//
+// Progress is checkpointed in dir: if an export is interrupted, running
+// it again skips the sessions already exported whose transcripts haven't
+// grown since.
+func ExportSessions(sessions []SessionInfo, dir, format string, progress Progress) ([]ExportedSession, error) {
+	ext, err := exportExt(format)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating export directory: %w", err)
+	}
+	cp, _ := openCheckpoint(filepath.Join(dir, ExportCheckpointName), "export", format)
+
+	var exported []ExportedSession
+	for i := range sessions {
+		s := &sessions[i]
+		name := exportFileName(s, ext)
+		if exportDone(cp, dir, s, name) {
+			exported = append(exported, ExportedSession{Session: *s, File: name})
+			progress.report("exporting", i+1, len(sessions))
+			continue
+		}
+		out, err := exportSession(s, format)
+		if err != nil {
+			_ = cp.save()
+			return exported, fmt.Errorf("exporting %s: %w", s.ShortID(), err)
+		}
+		if err := util.AtomicWriteFile(filepath.Join(dir, name), out, 0644); err != nil {
+			_ = cp.save()
+			return exported, fmt.Errorf("writing %s: %w", name, err)
+		}
+		exported = append(exported, ExportedSession{Session: *s, File: name})
+		cp.Done[s.Path] = exportDoneValue(s, name)
+		if cp.saveDue() {
+			if err := cp.save(); err != nil {
+				return exported, fmt.Errorf("writing export checkpoint: %w", err)
+			}
+		}
+		progress.report("exporting", i+1, len(sessions))
+	}
+
+	manifest := exportManifest(exported, time.Now())
+	if err := os.WriteFile(filepath.Join(dir, ExportManifestName), []byte(manifest), 0644); err != nil { //nolint:gosec // G306: exports are meant to be shared
+		_ = cp.save()
+		return exported, fmt.Errorf("writing manifest: %w", err)
+	}
+	cp.remove()
+	return exported, nil
+}
+
+// exportDone reports whether an earlier, interrupted export already wrote
+// s to name from a transcript of the same size.
+func exportDone(cp *checkpoint, dir string, s *SessionInfo, name string) bool {
+	if cp.Done[s.Path] != exportDoneValue(s, name) {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(dir, name))
+	return err == nil
+}
+
+// exportDoneValue is what the export checkpoint records for s.
+func exportDoneValue(s *SessionInfo, name string) string {
+	return fmt.Sprintf("%s %d", name, s.FileSize)
+}
+
+// exportExt returns the file extension for format.
+func exportExt(format string) (string, error) {
+	switch format {
+	case ExportMarkdown, "md":
+		return ".md", nil
+	case ExportHTML:
+		return ".html", nil
+	}
+	return "", fmt.Errorf("unknown export format %q (want %s)", format, strings.Join(ExportFormats(), " or "))
+}
+
+// exportFileName names a session's export, e.g. "2025-03-01-abcd1234.md",
+// so a directory listing sorts by date.
+func exportFileName(s *SessionInfo, ext string) string {
+	if s.StartTime.IsZero() {
+		return s.ShortID() + ext
+	}
+	return s.StartTime.UTC().Format("2006-01-02") + "-" + s.ShortID() + ext
+}
+
+// exportManifest renders the index.md listing exported sessions.
+func exportManifest(exported []ExportedSession, generated time.Time) string {
+	var b strings.Builder
+	b.WriteString("# Session Export\n\n")
+	fmt.Fprintf(&b, "%d session(s), exported %s.\n\n", len(exported), exportTime(generated))
+	if len(exported) == 0 {
+		return b.String()
+	}
+
+	var total time.Duration
+	b.WriteString("| Session | Role | Topic | Started | Duration | Summary |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, e := range exported {
+		s := e.Session
+		total += s.Duration
+		fmt.Fprintf(&b, "| [%s](%s) | %s | %s | %s | %s | %s |\n",
+			s.ShortID(), e.File,
+			mdCell(s.Role), mdCell(s.Topic), exportTime(s.StartTime),
+			s.Duration.Round(time.Second), mdCell(s.Summary))
+	}
+	fmt.Fprintf(&b, "\nTotal duration: %s.\n", total.Round(time.Second))
+	return b.String()
+}
+
+// mdCell escapes text for a Markdown table cell, or returns "-" for none.
+func mdCell(s string) string {
+	if s == "" {
+		return "-"
+	}
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
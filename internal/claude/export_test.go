@@ -0,0 +1,114 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeExportSession(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	writeSession(t, home, "-home-u-proj", "abcd1234-5678.jsonl",
+		`{"type":"user","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"fix <the> auth bug"}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:00:05Z","message":{"id":"m1","role":"assistant","content":[{"type":"text","text":"Looking."},{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"cat README.md"}}]}}`,
+		"{\"type\":\"user\",\"timestamp\":\"2025-01-01T00:00:06Z\",\"message\":{\"role\":\"user\",\"content\":[{\"type\":\"tool_result\",\"tool_use_id\":\"t1\",\"content\":\"```go\\nx := 1\\n```\",\"is_error\":true}]}}",
+	)
+}
+
+func TestExportTranscriptMarkdown(t *testing.T) {
+	writeExportSession(t)
+
+	out, err := ExportTranscript("abcd", ExportMarkdown)
+	if err != nil {
+		t.Fatalf("ExportTranscript: %v", err)
+	}
+	md := string(out)
+	for _, want := range []string{
+		"# Session abcd1234",
+		"## User · 2025-01-01 00:00:00 UTC\n\nfix <the> auth bug",
+		"## Assistant · 2025-01-01 00:00:05 UTC\n\nLooking.",
+		"<details>\n<summary>Bash: cat README.md (error)</summary>",
+		"````\n```go\nx := 1\n```\n````", // fence outlasts the output's own
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestExportTranscriptHTML(t *testing.T) {
+	writeExportSession(t)
+
+	out, err := ExportTranscript("abcd1234-5678", ExportHTML)
+	if err != nil {
+		t.Fatalf("ExportTranscript: %v", err)
+	}
+	page := string(out)
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`<section class="turn user">`,
+		"fix &lt;the&gt; auth bug",
+		`<time datetime="2025-01-01T00:00:05Z">`,
+		`<details class="error">`,
+		"<summary>Bash: cat README.md (error)</summary>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("html missing %q:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, "<script") || strings.Contains(page, "<link") {
+		t.Error("html export should be standalone")
+	}
+}
+
+func TestExportTranscriptUnknownFormat(t *testing.T) {
+	writeExportSession(t)
+	if _, err := ExportTranscript("abcd", "pdf"); err == nil {
+		t.Error("ExportTranscript accepted an unknown format")
+	}
+}
+
+func TestExportSessions(t *testing.T) {
+	writeExportSession(t)
+	sessions, err := DiscoverSessions(SessionFilter{})
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("DiscoverSessions = %v, %v", sessions, err)
+	}
+	sessions[0].Role = "gastown/crew/joe"
+	sessions[0].Topic = "fix | auth"
+
+	dir := filepath.Join(t.TempDir(), "audit")
+	exported, err := ExportSessions(sessions, dir, ExportMarkdown, nil)
+	if err != nil {
+		t.Fatalf("ExportSessions: %v", err)
+	}
+	if len(exported) != 1 || exported[0].File != "2025-01-01-abcd1234.md" {
+		t.Fatalf("exported = %+v", exported)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, exported[0].File)); err != nil || !strings.Contains(string(data), "fix <the> auth bug") {
+		t.Errorf("session file = %q, %v", data, err)
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(dir, ExportManifestName))
+	if err != nil {
+		t.Fatalf("reading manifest: %v", err)
+	}
+	for _, want := range []string{
+		"1 session(s)",
+		"| [abcd1234](2025-01-01-abcd1234.md) | gastown/crew/joe | fix \\| auth | 2025-01-01 00:00:00 UTC | 6s |",
+		"Total duration: 6s.",
+	} {
+		if !strings.Contains(string(manifest), want) {
+			t.Errorf("manifest missing %q:\n%s", want, manifest)
+		}
+	}
+
+	if _, err := ExportSessions(sessions, dir, "pdf", nil); err == nil {
+		t.Error("want error for an unknown format")
+	}
+}
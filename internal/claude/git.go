@@ -0,0 +1,61 @@
+package claude
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"slices"
+)
+
+// gitCommitLine matches the first line git commit prints, e.g.
+// "[main 1a2b3c4] Fix the widget" or "[detached HEAD 1a2b3c4] ...".
+var gitCommitLine = regexp.MustCompile(`(?m)^\[(?:detached HEAD|[^\s\]]+)(?: \(root-commit\))? ([0-9a-f]{7,40})\] `)
+
+// gitCommitIn returns the commit reported by the last git commit output
+// in a message's tool results, or "".
+func gitCommitIn(raw json.RawMessage) string {
+	var msg entryMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &msg) != nil {
+		return ""
+	}
+	var blocks []subagentBlock
+	if json.Unmarshal(msg.Content, &blocks) != nil {
+		return ""
+	}
+	commit := ""
+	for _, b := range blocks {
+		if b.Type != "tool_result" {
+			continue
+		}
+		if m := gitCommitLine.FindAllStringSubmatch(toolResultText(b.Content), -1); len(m) > 0 {
+			commit = m[len(m)-1][1]
+		}
+	}
+	return commit
+}
+
+// addBranch records branch as checked out in the session.
+func (s *SessionInfo) addBranch(branch string) {
+	if branch == "" {
+		return
+	}
+	s.GitBranch = branch
+	if !slices.Contains(s.GitBranches, branch) {
+		s.GitBranches = append(s.GitBranches, branch)
+	}
+}
+
+// OnBranch reports whether the session had a branch matching pattern
+// checked out at any point. The pattern is a branch name, or a
+// path.Match pattern such as "polecat/*".
+func (s SessionInfo) OnBranch(pattern string) bool {
+	for _, b := range s.GitBranches {
+		if b == pattern {
+			return true
+		}
+		if ok, err := path.Match(pattern, b); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/state"
+)
+
+// Transcripts are never modified, but the metadata layered on them is:
+// aliases, hand-added tags and notes, generated summaries, and the
+// outcomes and takeovers recorded against sessions. Every change to it is
+// appended to an overlay history log in the per-machine state directory,
+// so who set what, and when, can be checked later. The log outlives the
+// index; clearing or rebuilding the index doesn't touch it.
+
+// OverlayHistoryFile is the overlay history log in the state directory.
+const OverlayHistoryFile = "session-overlay-history.jsonl"
+
+// Overlay kinds.
+const (
+	OverlayAlias    = "alias"
+	OverlayTag      = "tag"
+	OverlayNote     = "note"
+	OverlaySummary  = "summary"
+	OverlayOutcome  = "outcome"  // gt outcome set, gt check run
+	OverlayTakeover = "takeover" // gt takeover
+)
+
+// Overlay operations.
+const (
+	OverlayAdd    = "add"    // a tag, alias, or takeover was added
+	OverlayRemove = "remove" // a tag or alias was removed
+	OverlaySet    = "set"    // a note, summary, or outcome was set or replaced
+	OverlayClear  = "clear"  // a note was removed, or takeovers withdrawn
+)
+
+// OverlayChange is one change to a session's overlay metadata.
+type OverlayChange struct {
+	Time       time.Time `json:"time"`
+	Session    string    `json:"session"` // full session ID
+	Kind       string    `json:"kind"`    // alias, tag, note, summary, outcome, or takeover
+	Op         string    `json:"op"`      // add, remove, set, or clear
+	Value      string    `json:"value,omitempty"`
+	Old        string    `json:"old,omitempty"`   // value replaced or removed
+	Actor      string    `json:"actor,omitempty"` // BD_ACTOR, or the OS user
+	Invocation string    `json:"invocation,omitempty"`
+}
+
+// OverlayHistoryPath returns the overlay history log.
+func OverlayHistoryPath() string {
+	return filepath.Join(state.StateDir(), OverlayHistoryFile)
+}
+
+// RecordOverlayChange logs a change made outside this package, such as a
+// recorded outcome or takeover, in the overlay history.
+func RecordOverlayChange(c OverlayChange) error {
+	return recordOverlayChanges(c)
+}
+
+// recordOverlayChanges appends changes to the overlay history log, filling
+// in their time, actor, and invocation. Writers in parallel processes are
+// serialized with a file lock.
+func recordOverlayChanges(changes ...OverlayChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	now := time.Now()
+	actor := overlayActor()
+	invocation := ""
+	if len(os.Args) > 0 {
+		invocation = strings.Join(append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...), " ")
+	}
+
+	var data []byte
+	for _, c := range changes {
+		if c.Time.IsZero() {
+			c.Time = now
+		}
+		if c.Actor == "" {
+			c.Actor = actor
+		}
+		if c.Invocation == "" {
+			c.Invocation = invocation
+		}
+		line, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	path := OverlayHistoryPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking overlay history: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600) //nolint:gosec // G302: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening overlay history: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing overlay history: %w", err)
+	}
+	return nil
+}
+
+// overlayActor names who is making a change: the Gas Town agent from
+// BD_ACTOR, else the OS user.
+func overlayActor() string {
+	if actor := os.Getenv("BD_ACTOR"); actor != "" {
+		return actor
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// OverlayHistory returns the recorded overlay changes, oldest first. With
+// a session ID, only that session's changes are returned; changes before
+// since are skipped.
+func OverlayHistory(session string, since time.Time) ([]OverlayChange, error) {
+	f, err := os.Open(OverlayHistoryPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening overlay history: %w", err)
+	}
+	defer f.Close()
+
+	var out []OverlayChange
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var c OverlayChange
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil || c.Session == "" {
+			continue
+		}
+		if session != "" && c.Session != session || c.Time.Before(since) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, scanner.Err()
+}
+
+// OverlayHistorySessions returns the IDs of sessions with recorded changes
+// that start with prefix, so the history of a session whose transcript is
+// gone can still be looked up.
+func OverlayHistorySessions(prefix string) ([]string, error) {
+	all, err := OverlayHistory("", time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var ids []string
+	for _, c := range all {
+		if strings.HasPrefix(c.Session, prefix) && !seen[c.Session] {
+			seen[c.Session] = true
+			ids = append(ids, c.Session)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,80 @@
+package claude
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOverlayHistory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("BD_ACTOR", "gastown/crew/joe")
+
+	const a, b = "aaaa1111-0000", "bbbb2222-0000"
+	steps := []func() error{
+		func() error { return TagSession(a, "experiment") },
+		func() error { return TagSession(a, "experiment") }, // no change, not logged
+		func() error { return SetSessionNote(a, "first") },
+		func() error { return SetSessionNote(a, "second") },
+		func() error { return SetSessionNote(a, "second") }, // no change
+		func() error { return SetAlias("auth-work", a) },
+		func() error { return SetAlias("auth-work", b) }, // moves the alias
+		func() error { return UntagSession(a, "experiment") },
+		func() error { return SetSessionNote(a, "") },
+		func() error { return RemoveAlias("auth-work") },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	history, err := OverlayHistory(a, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []OverlayChange{
+		{Kind: OverlayTag, Op: OverlayAdd, Value: "experiment"},
+		{Kind: OverlayNote, Op: OverlaySet, Value: "first"},
+		{Kind: OverlayNote, Op: OverlaySet, Value: "second", Old: "first"},
+		{Kind: OverlayAlias, Op: OverlayAdd, Value: "auth-work"},
+		{Kind: OverlayAlias, Op: OverlayRemove, Old: "auth-work"},
+		{Kind: OverlayTag, Op: OverlayRemove, Old: "experiment"},
+		{Kind: OverlayNote, Op: OverlayClear, Old: "second"},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("history of %s = %+v, want %d changes", a, history, len(want))
+	}
+	for i, w := range want {
+		got := history[i]
+		if got.Kind != w.Kind || got.Op != w.Op || got.Value != w.Value || got.Old != w.Old {
+			t.Errorf("change %d = %s %s %q (old %q), want %s %s %q (old %q)",
+				i, got.Kind, got.Op, got.Value, got.Old, w.Kind, w.Op, w.Value, w.Old)
+		}
+		if got.Session != a || got.Actor != "gastown/crew/joe" || got.Time.IsZero() || got.Invocation == "" {
+			t.Errorf("change %d = %+v, want session, actor, time, and invocation set", i, got)
+		}
+	}
+
+	other, err := OverlayHistory(b, time.Time{})
+	if err != nil || len(other) != 2 || other[0].Op != OverlayAdd || other[1].Op != OverlayRemove {
+		t.Errorf("history of %s = %+v, %v", b, other, err)
+	}
+	if all, _ := OverlayHistory("", time.Time{}); len(all) != len(want)+2 {
+		t.Errorf("all history = %d changes, want %d", len(all), len(want)+2)
+	}
+	if later, _ := OverlayHistory("", time.Now().Add(time.Hour)); len(later) != 0 {
+		t.Errorf("history since the future = %+v", later)
+	}
+
+	// The log survives clearing the index
+	if err := InvalidateSessionIndex(); err != nil {
+		t.Fatal(err)
+	}
+	ids, err := OverlayHistorySessions("aaaa")
+	if err != nil || len(ids) != 1 || ids[0] != a {
+		t.Errorf("sessions matching aaaa = %v, %v", ids, err)
+	}
+}
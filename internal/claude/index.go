@@ -0,0 +1,692 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/perf"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// sessionIndexVersion is bumped when SessionInfo parsing changes, so
+// indexes written by older builds are discarded rather than trusted.
+const sessionIndexVersion = 16
+
+// sessionIndexEnv disables the index when set to "off" (e.g., to measure
+// discovery without it).
+const sessionIndexEnv = "GT_SESSION_INDEX"
+
+// SessionIndexPath returns the on-disk session index. It lives in the
+// per-machine cache directory, since it only mirrors ~/.claude/projects.
+// The file itself is small: the manifest of project directories. Parsed
+// sessions live in
+// per-rig shards beside it, loaded only when a lookup needs them.
+func SessionIndexPath() string {
+	return filepath.Join(state.CacheDir(), "session-index.json")
+}
+
+// sessionShardDir holds the index's shards, one file per rig.
+func sessionShardDir() string {
+	return filepath.Join(state.CacheDir(), "session-index")
+}
+
+// otherShard holds project directories no Gas Town rig has worked in.
+const otherShard = "_other"
+
+// sessionIndex caches parsed session headers, keyed by transcript path.
+// An entry is valid while the transcript's size and modification time are
+// unchanged.
+type sessionIndex struct {
+	Version     int    `json:"version"`
+	ProjectsDir string `json:"projects_dir"`
+
+	// Projects maps each project directory to the shard holding its
+	// transcripts and what they matched, so discovery scoped to one rig
+	// skips other rigs' directories without loading their shards.
+	Projects map[string]indexProject `json:"projects"`
+
+	// Aliases, Tags, Notes, and Summaries are the annotations older
+	// builds kept here. They are carried over unchanged until a write to
+	// the annotations file moves them there (see annotations.go).
+	Aliases   map[string]string   `json:"aliases,omitempty"`
+	Tags      map[string][]string `json:"tags,omitempty"`
+	Notes     map[string]string   `json:"notes,omitempty"`
+	Summaries map[string]string   `json:"summaries,omitempty"`
+
+	mu     sync.Mutex // guards Projects, shards, and dirty during parallel discovery and saves
+	dirty  bool
+	shards map[string]*indexShard // loaded so far, by name
+
+	// lag is how far an entry may trail a growing local transcript and
+	// still be used: nonzero while the daemon's ingester is indexing
+	// changes (see Ingester).
+	lag time.Duration
+}
+
+// indexProject is the manifest entry for one project directory.
+type indexProject struct {
+	Shard    string   `json:"shard"`
+	Rigs     []string `json:"rigs,omitempty"`  // rigs its sessions belong to, lowercased
+	Paths    []string `json:"paths,omitempty"` // its sessions' project paths, lowercased
+	Sessions int      `json:"sessions"`        // top-level transcripts indexed
+	ModTime  int64    `json:"mtime"`           // directory mtime when counted (UnixNano)
+}
+
+// mayMatchRig reports whether a session in the directory could match a
+// SessionFilter.Rig of rig (lowercased).
+func (p indexProject) mayMatchRig(rig string) bool {
+	if slices.Contains(p.Rigs, rig) {
+		return true
+	}
+	for _, path := range p.Paths {
+		if strings.Contains(path, rig) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexShard holds the parsed sessions and touched files of the project
+// directories assigned to it.
+type indexShard struct {
+	Version     int                          `json:"version"`
+	ProjectsDir string                       `json:"projects_dir"`
+	Entries     map[string]sessionIndexEntry `json:"entries"`
+
+	// Files caches the files each transcript touched, keyed by transcript
+	// path (see SessionsNear).
+	Files map[string]filesEntry `json:"files,omitempty"`
+
+	dirty bool
+}
+
+// sessionIndexEntry is a cached parse of one transcript.
+type sessionIndexEntry struct {
+	ModTime int64       `json:"mtime"` // UnixNano
+	Size    int64       `json:"size"`
+	Info    SessionInfo `json:"info"`
+}
+
+// SessionIndexStats describes the session index.
+type SessionIndexStats struct {
+	Path      string    `json:"path"`
+	Entries   int       `json:"entries"`
+	Shards    int       `json:"shards"`
+	SizeBytes int64     `json:"size_bytes"` // the index and its shards
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// sessionIndexEnabled reports whether discovery should use the index.
+func sessionIndexEnabled() bool {
+	return os.Getenv(sessionIndexEnv) != "off"
+}
+
+// loadSessionIndex reads the index for projectsDir; its shards are read
+// as lookups need them. A missing, unreadable, outdated, or foreign index
+// yields an empty one, keeping its legacy annotations.
+func loadSessionIndex(projectsDir string) *sessionIndex {
+	empty := &sessionIndex{
+		Version:     sessionIndexVersion,
+		ProjectsDir: projectsDir,
+		Projects:    make(map[string]indexProject),
+		shards:      make(map[string]*indexShard),
+	}
+	data, err := os.ReadFile(SessionIndexPath())
+	if err != nil {
+		return empty
+	}
+	var idx sessionIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		empty.dirty = true // replace it on save
+		return empty
+	}
+	if idx.Version != sessionIndexVersion || idx.ProjectsDir != projectsDir || idx.Projects == nil {
+		empty.Aliases = idx.Aliases
+		empty.Summaries = idx.Summaries
+		empty.Tags = idx.Tags
+		empty.Notes = idx.Notes
+		empty.dirty = true
+		return empty
+	}
+	if (idx.Aliases != nil || idx.Tags != nil || idx.Notes != nil || idx.Summaries != nil) && annotationsCurrent() {
+		// Moved to the annotations file
+		idx.Aliases, idx.Tags, idx.Notes, idx.Summaries = nil, nil, nil, nil
+	}
+	idx.shards = make(map[string]*indexShard)
+	idx.lag = ingestLag(time.Now())
+	return &idx
+}
+
+// sessionShardPath returns the file holding shard name.
+func sessionShardPath(name string) string {
+	return filepath.Join(sessionShardDir(), name+".json")
+}
+
+// shardName returns the shard for a rig's project directories.
+func shardName(rig string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '.' || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(rig))
+	if strings.Trim(name, "._") == "" {
+		return otherShard
+	}
+	return name
+}
+
+// projectDirOf returns the project directory a transcript belongs to,
+// looking past the <session-id>/subagents/ nesting.
+func projectDirOf(path string) string {
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) == "subagents" {
+		dir = filepath.Dir(filepath.Dir(dir))
+	}
+	return dir
+}
+
+// sessionRigs returns the rigs a session belongs to, lowercased: its
+// beacon rig and the rig its role names.
+func sessionRigs(info *SessionInfo) []string {
+	var rigs []string
+	if info.Rig != "" {
+		rigs = append(rigs, strings.ToLower(info.Rig))
+	}
+	if rig, _, ok := strings.Cut(info.Role, "/"); ok && rig != "" && !slices.Contains(rigs, strings.ToLower(rig)) {
+		rigs = append(rigs, strings.ToLower(rig))
+	}
+	return rigs
+}
+
+// shard returns shard name, reading it on first use. A missing,
+// unreadable, outdated, or foreign shard is empty. Callers hold idx.mu.
+func (idx *sessionIndex) shard(name string) *indexShard {
+	if s, ok := idx.shards[name]; ok {
+		return s
+	}
+	s := &indexShard{Version: sessionIndexVersion, ProjectsDir: idx.ProjectsDir}
+	if data, err := os.ReadFile(sessionShardPath(name)); err == nil {
+		var loaded indexShard
+		if json.Unmarshal(data, &loaded) == nil && loaded.Version == sessionIndexVersion && loaded.ProjectsDir == idx.ProjectsDir {
+			s = &loaded
+		} else {
+			s.dirty = true
+		}
+	}
+	if s.Entries == nil {
+		s.Entries = make(map[string]sessionIndexEntry)
+	}
+	if s.Files == nil {
+		s.Files = make(map[string]filesEntry)
+	}
+	idx.shards[name] = s
+	return s
+}
+
+// shardOf returns the shard holding path's project directory, or nil if
+// the directory isn't indexed and create is false. A new directory goes
+// to the shard of rigs' first rig. Callers hold idx.mu.
+func (idx *sessionIndex) shardOf(path string, create bool, rigs []string) *indexShard {
+	dir := projectDirOf(path)
+	p, ok := idx.Projects[dir]
+	if !ok {
+		if !create {
+			return nil
+		}
+		p = indexProject{Shard: otherShard}
+		if len(rigs) > 0 {
+			p.Shard = shardName(rigs[0])
+		}
+		idx.Projects[dir] = p
+		idx.dirty = true
+	}
+	return idx.shard(p.Shard)
+}
+
+// entry returns the cached parse of path, whatever its age.
+func (idx *sessionIndex) entry(path string) (sessionIndexEntry, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	s := idx.shardOf(path, false, nil)
+	if s == nil {
+		return sessionIndexEntry{}, false
+	}
+	entry, ok := s.Entries[path]
+	return entry, ok
+}
+
+// count returns the number of indexed transcripts, reading every shard.
+func (idx *sessionIndex) count() int {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	n := 0
+	for name := range idx.shardNames() {
+		n += len(idx.shard(name).Entries)
+	}
+	return n
+}
+
+// shardNames returns the shards the manifest refers to. Callers hold
+// idx.mu.
+func (idx *sessionIndex) shardNames() map[string]bool {
+	names := make(map[string]bool)
+	for _, p := range idx.Projects {
+		names[p.Shard] = true
+	}
+	return names
+}
+
+// lookup returns the cached SessionInfo for path if the file is unchanged,
+// or if it has only grown within the ingester's lag: the ingester will
+// index it shortly, so commands don't each re-parse it meanwhile.
+func (idx *sessionIndex) lookup(path string, stat os.FileInfo) (*SessionInfo, bool) {
+	entry, ok := idx.entry(path)
+	if !ok {
+		return nil, false
+	}
+	if entry.Size != stat.Size() || entry.ModTime != stat.ModTime().UnixNano() {
+		behind := time.Duration(stat.ModTime().UnixNano() - entry.ModTime)
+		if idx.lag == 0 || stat.Size() <= entry.Size || behind > idx.lag ||
+			!strings.HasPrefix(path, idx.ProjectsDir+string(filepath.Separator)) {
+			return nil, false
+		}
+	}
+	info := entry.Info
+	return &info, true
+}
+
+// store caches a freshly parsed SessionInfo.
+func (idx *sessionIndex) store(path string, stat os.FileInfo, info *SessionInfo) {
+	cached := *info
+	cached.Tags = nil // tags depend on the caller's rules, not the file
+	cached.Note = ""
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	s := idx.shardOf(path, true, sessionRigs(info))
+	s.Entries[path] = sessionIndexEntry{ModTime: stat.ModTime().UnixNano(), Size: stat.Size(), Info: cached}
+	s.dirty = true
+}
+
+// files returns the cached files touched by the transcript at path.
+func (idx *sessionIndex) files(path string) (filesEntry, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	s := idx.shardOf(path, false, nil)
+	if s == nil {
+		return filesEntry{}, false
+	}
+	entry, ok := s.Files[path]
+	return entry, ok
+}
+
+// storeFiles caches the files touched by the transcript at path.
+func (idx *sessionIndex) storeFiles(path string, entry filesEntry) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	s := idx.shardOf(path, true, nil)
+	s.Files[path] = entry
+	s.dirty = true
+}
+
+// skipOtherRigs drops the jobs in project directories whose sessions
+// can't match a SessionFilter.Rig of rig. seen holds every transcript
+// listed; a directory is only skipped while the manifest accounts for all
+// of its transcripts and nothing was added to it since.
+func (idx *sessionIndex) skipOtherRigs(jobs []parseJob, seen map[string]bool, rig string) []parseJob {
+	rig = strings.ToLower(rig)
+	if rig == "" || strings.Contains(rig, "/") {
+		return jobs
+	}
+	listed := make(map[string]int)
+	for path := range seen {
+		if !isSubagentTranscript(path) {
+			listed[projectDirOf(path)]++
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	skip := make(map[string]bool)
+	var kept []parseJob
+	for _, job := range jobs {
+		dir := projectDirOf(job.path)
+		skipped, ok := skip[dir]
+		if !ok {
+			p, known := idx.Projects[dir]
+			skipped = known && p.Sessions == listed[dir] && !p.mayMatchRig(rig)
+			if skipped {
+				stat, err := os.Stat(dir)
+				skipped = err == nil && stat.ModTime().UnixNano() == p.ModTime
+			}
+			skip[dir] = skipped
+		}
+		if !skipped {
+			kept = append(kept, job)
+		}
+	}
+	return kept
+}
+
+// prune drops entries and files touched for transcripts in
+// the scanned projects directories that no longer exist; other roots'
+// entries are left for the discoveries that scan them. Shards not loaded
+// are read first when one of their directories lists fewer transcripts
+// than were indexed. With keepSubagents, entries for subagent transcripts
+// are kept: nested ones are only listed when discovery includes subagents.
+func (idx *sessionIndex) prune(seen map[string]bool, scanned []string, keepSubagents bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	under := func(path string) bool {
+		for _, dir := range scanned {
+			if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+				return true
+			}
+		}
+		return false
+	}
+	gone := func(path string) bool {
+		if seen[path] || (keepSubagents && isSubagentTranscript(path)) {
+			return false
+		}
+		return under(path)
+	}
+
+	listed := make(map[string]int)
+	for path := range seen {
+		if !isSubagentTranscript(path) {
+			listed[projectDirOf(path)]++
+		}
+	}
+	for dir, p := range idx.Projects {
+		if listed[dir] < p.Sessions && under(dir) {
+			idx.shard(p.Shard)
+		}
+	}
+
+	for _, s := range idx.shards {
+		for path := range s.Entries {
+			if gone(path) {
+				delete(s.Entries, path)
+				s.dirty = true
+			}
+		}
+		for path := range s.Files {
+			if gone(path) {
+				delete(s.Files, path)
+				s.dirty = true
+			}
+		}
+	}
+}
+
+// settleProjects brings the manifest up to date with the changed shards:
+// each directory's rigs, paths, and transcript count, read from its
+// entries. A directory in otherShard moves to a rig's shard once a
+// session of that rig is indexed in it; one with nothing left indexed is
+// dropped. Callers hold idx.mu.
+func (idx *sessionIndex) settleProjects() {
+	changed := make(map[string]bool)
+	for name, s := range idx.shards {
+		if s.dirty {
+			changed[name] = true
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	idx.dirty = true
+
+	type tally struct {
+		rigs, paths []string
+		sessions    int
+	}
+	for dir, p := range idx.Projects {
+		if !changed[p.Shard] {
+			continue
+		}
+		s := idx.shards[p.Shard]
+		var t tally
+		indexed := false
+		for path, e := range s.Entries {
+			if projectDirOf(path) != dir {
+				continue
+			}
+			indexed = true
+			for _, rig := range sessionRigs(&e.Info) {
+				if !slices.Contains(t.rigs, rig) {
+					t.rigs = append(t.rigs, rig)
+				}
+			}
+			if pp := strings.ToLower(e.Info.ProjectPath); pp != "" && !slices.Contains(t.paths, pp) {
+				t.paths = append(t.paths, pp)
+			}
+			if !isSubagentTranscript(path) {
+				t.sessions++
+			}
+		}
+		hasFiles := false
+		for path := range s.Files {
+			if projectDirOf(path) == dir {
+				hasFiles = true
+				break
+			}
+		}
+		if !indexed && !hasFiles {
+			delete(idx.Projects, dir)
+			continue
+		}
+
+		slices.Sort(t.rigs)
+		slices.Sort(t.paths)
+		p.Rigs, p.Paths, p.Sessions = t.rigs, t.paths, t.sessions
+		p.ModTime = 0
+		if stat, err := os.Stat(dir); err == nil {
+			p.ModTime = stat.ModTime().UnixNano()
+		}
+		if p.Shard == otherShard && len(t.rigs) > 0 {
+			target := idx.shard(shardName(t.rigs[0]))
+			for path, e := range s.Entries {
+				if projectDirOf(path) == dir {
+					target.Entries[path] = e
+					delete(s.Entries, path)
+				}
+			}
+			for path, f := range s.Files {
+				if projectDirOf(path) == dir {
+					target.Files[path] = f
+					delete(s.Files, path)
+				}
+			}
+			target.dirty = true
+			p.Shard = shardName(t.rigs[0])
+		}
+		idx.Projects[dir] = p
+	}
+}
+
+// save writes the changed shards and the manifest. Failures are ignored:
+// the index is only a cache, and the next discovery rebuilds what's
+// missing.
+func (idx *sessionIndex) save() {
+	// Workers abandoned by a discovery timeout may still be storing
+	// entries, so take the lock before reading the index
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.settleProjects()
+	if !idx.dirty {
+		return
+	}
+	if idx.writeShards(false) == nil && writeSessionIndexFile(idx) == nil {
+		idx.dirty = false
+	}
+}
+
+// writeShards writes the loaded shards: all of them, or only the changed
+// ones. Shards left empty are removed. Callers hold idx.mu.
+func (idx *sessionIndex) writeShards(all bool) error {
+	if err := os.MkdirAll(sessionShardDir(), 0755); err != nil {
+		return err
+	}
+	for name, s := range idx.shards {
+		if !all && !s.dirty {
+			continue
+		}
+		path := sessionShardPath(name)
+		if len(s.Entries) == 0 && len(s.Files) == 0 {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		} else if err := util.AtomicWriteJSON(path, s); err != nil {
+			return err
+		}
+		s.dirty = false
+	}
+	return nil
+}
+
+// writeSessionIndex writes idx and its loaded shards, whether or not
+// discovery changed them.
+func writeSessionIndex(idx *sessionIndex) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.settleProjects()
+	if err := idx.writeShards(true); err != nil {
+		return err
+	}
+	return writeSessionIndexFile(idx)
+}
+
+// writeSessionIndexFile writes the manifest alone.
+func writeSessionIndexFile(idx *sessionIndex) error {
+	path := SessionIndexPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, idx)
+}
+
+// parseSessionIndexed returns the SessionInfo for a transcript from the
+// index when the file is unchanged, parsing and caching it otherwise.
+func parseSessionIndexed(idx *sessionIndex, path, project string) (*SessionInfo, error) {
+	if idx == nil {
+		info, err := parseSession(path, project)
+		if info != nil {
+			perf.Add(perf.FilesParsed, 1)
+		}
+		return info, err
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info, ok := idx.lookup(path, stat); ok {
+		perf.Hit(true)
+		return info, nil
+	}
+	perf.Hit(false)
+
+	info, err := parseSession(path, project)
+	if err != nil || info == nil {
+		return info, err
+	}
+	perf.Add(perf.FilesParsed, 1)
+	idx.store(path, stat, info)
+	return info, nil
+}
+
+// InvalidateSessionIndex deletes the session index. Legacy annotations
+// are moved to the annotations file first. The next discovery parses
+// every transcript again.
+func InvalidateSessionIndex() error {
+	if err := migrateAnnotations(); err != nil {
+		return err
+	}
+	if err := os.Remove(SessionIndexPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.RemoveAll(sessionShardDir())
+}
+
+// rebuildChunk is how many transcripts RebuildSessionIndex parses
+// between saves of the index.
+const rebuildChunk = 200
+
+// RebuildSessionIndex discards the session index and re-parses every
+// transcript into a new one, returning the number of sessions indexed.
+// The index is saved as it fills, and a marker next to it records that a
+// rebuild is under way: if the rebuild is interrupted, running it again
+// keeps the entries already rebuilt instead of discarding them again.
+func RebuildSessionIndex(progress Progress) (int, error) {
+	projectsDir := ProjectsDir()
+	cp, resumed := openCheckpoint(SessionIndexPath()+".rebuild", "index-rebuild", projectsDir)
+	if !resumed {
+		if err := InvalidateSessionIndex(); err != nil {
+			return 0, err
+		}
+		if err := os.MkdirAll(filepath.Dir(cp.path), 0755); err != nil {
+			return 0, err
+		}
+		if err := cp.save(); err != nil {
+			return 0, fmt.Errorf("writing rebuild checkpoint: %w", err)
+		}
+	}
+
+	roots := resolveRoots(Roots())
+	if skipRemoteRoots() {
+		roots = localRoots(roots)
+	}
+	listings, _ := listRoots(roots, SessionFilter{}, time.Time{})
+	var jobs []parseJob
+	for _, l := range listings {
+		jobs = append(jobs, l.jobs...)
+	}
+
+	idx := loadSessionIndex(projectsDir)
+	for start := 0; start < len(jobs); start += rebuildChunk {
+		end := min(start+rebuildChunk, len(jobs))
+		parseSessions(idx, jobs[start:end], 0, time.Time{})
+		idx.save()
+		progress.report("parsing", end, len(jobs))
+	}
+
+	// Prune entries for deleted transcripts and count what's left
+	sessions, err := scanSessions(SessionFilter{Timeout: -1})
+	if err != nil {
+		return len(sessions), err
+	}
+	cp.remove()
+	return len(sessions), nil
+}
+
+// SessionIndexInfo reports the session index's location and size,
+// counting the entries of every shard.
+func SessionIndexInfo() SessionIndexStats {
+	stats := SessionIndexStats{Path: SessionIndexPath()}
+	stat, err := os.Stat(stats.Path)
+	if err != nil {
+		return stats
+	}
+	stats.SizeBytes = stat.Size()
+	stats.UpdatedAt = stat.ModTime()
+	idx := loadSessionIndex(ProjectsDir())
+	stats.Entries = idx.count()
+	stats.Shards = len(idx.shards)
+	for name := range idx.shards {
+		if stat, err := os.Stat(sessionShardPath(name)); err == nil {
+			stats.SizeBytes += stat.Size()
+			if stat.ModTime().After(stats.UpdatedAt) {
+				stats.UpdatedAt = stat.ModTime()
+			}
+		}
+	}
+	return stats
+}
@@ -0,0 +1,162 @@
+package claude
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/perf"
+)
+
+func TestDiscoverSessionsUsesIndex(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	perf.Reset()
+	defer perf.Reset()
+
+	path := writeSession(t, home, "-home-u-gt-gastown-crew-joe", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-12-30T15:42:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/crew/joe <- deacon • 2025-12-30T15:42 • handoff"}}`,
+	)
+	writeSession(t, home, "-home-u-other", "bbbb2222.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":"hello"}}`,
+	)
+
+	if _, err := DiscoverSessions(SessionFilter{}); err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if got := perf.Snapshot(); got[perf.FilesParsed] != 2 || got[perf.CacheMisses] != 2 {
+		t.Fatalf("first discovery counters = %v", got)
+	}
+	if stats := SessionIndexInfo(); stats.Entries != 2 {
+		t.Fatalf("index has %d entries, want 2", stats.Entries)
+	}
+
+	// Unchanged files come from the index.
+	perf.Reset()
+	sessions, err := DiscoverSessions(SessionFilter{GasTownOnly: true})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Role != "gastown/crew/joe" || sessions[0].Topic != "handoff" {
+		t.Fatalf("indexed sessions = %+v", sessions)
+	}
+	if got := perf.Snapshot(); got[perf.FilesParsed] != 0 || got[perf.CacheHits] != 2 {
+		t.Errorf("second discovery counters = %v", got)
+	}
+
+	// A modified transcript is parsed again.
+	if err := os.WriteFile(path, []byte(`{"type":"user","timestamp":"2025-12-30T15:42:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/crew/joe <- mayor • 2025-12-30T15:42 • patrol"}}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	perf.Reset()
+	sessions, err = DiscoverSessions(SessionFilter{GasTownOnly: true})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Topic != "patrol" {
+		t.Errorf("stale index entry used: %+v", sessions)
+	}
+	if got := perf.Snapshot(); got[perf.FilesParsed] != 1 {
+		t.Errorf("modified file parsed %d times, want 1", got[perf.FilesParsed])
+	}
+
+	// Deleted transcripts are pruned.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DiscoverSessions(SessionFilter{}); err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if stats := SessionIndexInfo(); stats.Entries != 1 {
+		t.Errorf("index has %d entries after delete, want 1", stats.Entries)
+	}
+}
+
+func TestRebuildAndInvalidateSessionIndex(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	writeSession(t, home, "-home-u-other", "bbbb2222.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":"hello"}}`,
+	)
+
+	n, err := RebuildSessionIndex(nil)
+	if err != nil || n != 1 {
+		t.Fatalf("RebuildSessionIndex = %d, %v", n, err)
+	}
+	if _, err := os.Stat(SessionIndexPath()); err != nil {
+		t.Fatalf("index not written: %v", err)
+	}
+
+	if err := InvalidateSessionIndex(); err != nil {
+		t.Fatalf("InvalidateSessionIndex: %v", err)
+	}
+	if _, err := os.Stat(SessionIndexPath()); !os.IsNotExist(err) {
+		t.Errorf("index still present after invalidate: %v", err)
+	}
+	if err := InvalidateSessionIndex(); err != nil {
+		t.Errorf("InvalidateSessionIndex on missing index: %v", err)
+	}
+}
+
+func TestSessionIndexShardsByRig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	perf.Reset()
+	defer perf.Reset()
+
+	beacon := func(role string) string {
+		return `{"type":"user","timestamp":"2025-12-30T15:42:00Z","message":{"role":"user","content":"[GAS TOWN] ` + role + ` <- deacon • 2025-12-30T15:42 • work"}}`
+	}
+	writeSession(t, home, "-home-u-gt-gastown-crew-joe", "aaaa1111.jsonl", beacon("gastown/crew/joe"))
+	writeSession(t, home, "-home-u-gt-beads-crew-ann", "bbbb2222.jsonl", beacon("beads/crew/ann"))
+	other := writeSession(t, home, "-home-u-scratch", "cccc3333.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":"hello"}}`)
+
+	if _, err := DiscoverSessions(SessionFilter{}); err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	for _, name := range []string{"gastown", "beads", otherShard} {
+		if _, err := os.Stat(sessionShardPath(name)); err != nil {
+			t.Errorf("shard %s not written: %v", name, err)
+		}
+	}
+	if stats := SessionIndexInfo(); stats.Entries != 3 || stats.Shards != 3 {
+		t.Errorf("stats = %+v, want 3 entries in 3 shards", stats)
+	}
+
+	// Scoped to a rig, other rigs' directories aren't looked up at all
+	perf.Reset()
+	sessions, err := DiscoverSessions(SessionFilter{Rig: "gastown"})
+	if err != nil || len(sessions) != 1 || sessions[0].ID != "aaaa1111" {
+		t.Fatalf("rig discovery = %+v, %v", sessions, err)
+	}
+	if got := perf.Snapshot(); got[perf.CacheHits] != 1 || got[perf.FilesParsed] != 0 {
+		t.Errorf("rig discovery counters = %v, want 1 hit", got)
+	}
+
+	// A directory that gained a transcript is read again, and moves to
+	// the shard of the rig that started working there
+	writeSession(t, home, "-home-u-scratch", "dddd4444.jsonl", beacon("gastown/polecats/toast"))
+	sessions, err = DiscoverSessions(SessionFilter{Rig: "gastown"})
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("rig discovery after a new transcript = %+v, %v", sessions, err)
+	}
+	idx := loadSessionIndex(ProjectsDir())
+	if p := idx.Projects[projectDirOf(other)]; p.Shard != "gastown" || p.Sessions != 2 {
+		t.Errorf("scratch directory = %+v, want 2 sessions in the gastown shard", p)
+	}
+	if _, err := os.Stat(sessionShardPath(otherShard)); !os.IsNotExist(err) {
+		t.Errorf("emptied shard left behind: %v", err)
+	}
+	if entry, ok := idx.entry(other); !ok || entry.Info.ID != "cccc3333" {
+		t.Errorf("moved entry = %+v, %v", entry, ok)
+	}
+}
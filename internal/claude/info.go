@@ -0,0 +1,258 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Permission modes Claude Code accepts for permissions.defaultMode and
+// --permission-mode.
+const (
+	PermissionDefault     = "default"
+	PermissionAcceptEdits = "acceptEdits"
+	PermissionPlan        = "plan"
+	PermissionBypass      = "bypassPermissions"
+)
+
+// MCPServer is an MCP server configured for Claude Code.
+type MCPServer struct {
+	Name   string `json:"name"`
+	Scope  string `json:"scope"`          // "user", "local", or "project"
+	Type   string `json:"type,omitempty"` // "stdio", "http", or "sse"
+	Target string `json:"target,omitempty"`
+}
+
+// ProjectInfo is the Claude Code configuration that applies in a directory.
+type ProjectInfo struct {
+	Dir              string      `json:"dir"`
+	PermissionMode   string      `json:"permission_mode"`
+	PermissionSource string      `json:"permission_source"` // settings file, or "default"
+	Model            string      `json:"model,omitempty"`
+	MCPServers       []MCPServer `json:"mcp_servers,omitempty"` // local and project scope
+}
+
+// BinaryInfo describes an installed claude binary.
+type BinaryInfo struct {
+	Path    string    `json:"path"`
+	Version string    `json:"version"`
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+}
+
+// StatBinary locates the claude binary and records its size and
+// modification time, without running it.
+func StatBinary(name string) (*BinaryInfo, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' not in PATH", name)
+	}
+	info := &BinaryInfo{Path: path}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		if stat, err := os.Stat(resolved); err == nil {
+			info.ModTime = stat.ModTime()
+			info.Size = stat.Size()
+		}
+	}
+	return info, nil
+}
+
+// ProbeBinary locates the claude binary and reads its version.
+func ProbeBinary(name string) (*BinaryInfo, error) {
+	info, err := StatBinary(name)
+	if err != nil {
+		return nil, err
+	}
+	out, err := exec.Command(info.Path, "--version").Output() //nolint:gosec // G204: path from LookPath
+	if err != nil {
+		return nil, fmt.Errorf("running %s --version: %w", name, err)
+	}
+	info.Version = strings.TrimSpace(string(out))
+	return info, nil
+}
+
+// SameBinary returns true if other describes the same file at the same
+// path, so its version is still current.
+func (b *BinaryInfo) SameBinary(other *BinaryInfo) bool {
+	return b != nil && other != nil && b.Path == other.Path &&
+		b.Size == other.Size && b.ModTime.Equal(other.ModTime)
+}
+
+// versionPattern matches the dotted version at the start of
+// "claude --version" output, e.g. "2.0.14 (Claude Code)".
+var versionPattern = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// CompareVersions compares two version strings numerically by major,
+// minor, and patch. Text around the version is ignored. Returns -1, 0, or
+// 1, and false if either has no version number.
+func CompareVersions(a, b string) (int, bool) {
+	pa, okA := parseVersion(a)
+	pb, okB := parseVersion(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := range pa {
+		if pa[i] != pb[i] {
+			if pa[i] < pb[i] {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+func parseVersion(s string) ([3]int, bool) {
+	var v [3]int
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return v, false
+	}
+	for i := 0; i < 3; i++ {
+		v[i], _ = strconv.Atoi(m[i+1])
+	}
+	return v, true
+}
+
+// UserConfigPath returns Claude Code's user config file, which holds
+// user- and local-scope MCP servers: ~/.claude.json by default, or
+// .claude.json inside a relocated config directory.
+func UserConfigPath() string {
+	if dir := explicitClaudeDir(); dir != "" {
+		return filepath.Join(dir, ".claude.json")
+	}
+	return filepath.Join(homeDir(), ".claude.json")
+}
+
+// mcpServerConfig is an entry in an mcpServers map.
+type mcpServerConfig struct {
+	Type    string `json:"type"`
+	Command string `json:"command"`
+	URL     string `json:"url"`
+}
+
+// userConfig is the subset of ~/.claude.json read for MCP servers.
+type userConfig struct {
+	MCPServers map[string]mcpServerConfig `json:"mcpServers"`
+	Projects   map[string]struct {
+		MCPServers map[string]mcpServerConfig `json:"mcpServers"`
+	} `json:"projects"`
+}
+
+// loadUserConfig reads ~/.claude.json. A missing file is an empty config.
+func loadUserConfig() userConfig {
+	var cfg userConfig
+	if data, err := os.ReadFile(UserConfigPath()); err == nil {
+		_ = json.Unmarshal(data, &cfg)
+	}
+	return cfg
+}
+
+// UserMCPServers returns the user-scope MCP servers, available in every
+// project.
+func UserMCPServers() []MCPServer {
+	return mcpServers(loadUserConfig().MCPServers, "user")
+}
+
+// mcpServers converts an mcpServers map to a sorted list.
+func mcpServers(m map[string]mcpServerConfig, scope string) []MCPServer {
+	servers := make([]MCPServer, 0, len(m))
+	for name, cfg := range m {
+		s := MCPServer{Name: name, Scope: scope, Type: cfg.Type, Target: cfg.Command}
+		if s.Type == "" {
+			s.Type = "stdio"
+		}
+		if cfg.URL != "" {
+			s.Target = cfg.URL
+		}
+		servers = append(servers, s)
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+	return servers
+}
+
+// claudeSettings is the subset of a settings.json read for probing.
+type claudeSettings struct {
+	Model       string `json:"model"`
+	Permissions struct {
+		DefaultMode string `json:"defaultMode"`
+	} `json:"permissions"`
+}
+
+// ProbeProject reports the Claude Code configuration that applies in dir:
+// the permission mode and model from the most specific settings file
+// (.claude/settings.local.json, .claude/settings.json, then the user's
+// ~/.claude/settings.json), and the local- and project-scope MCP servers.
+func ProbeProject(dir string) ProjectInfo {
+	info := ProjectInfo{Dir: dir, PermissionMode: PermissionDefault, PermissionSource: "default"}
+
+	for _, path := range []string{
+		filepath.Join(dir, ".claude", "settings.local.json"),
+		filepath.Join(dir, ".claude", "settings.json"),
+		filepath.Join(ClaudeDir(), "settings.json"),
+	} {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: well-known settings paths
+		if err != nil {
+			continue
+		}
+		var s claudeSettings
+		if json.Unmarshal(data, &s) != nil {
+			continue
+		}
+		if info.PermissionSource == "default" && s.Permissions.DefaultMode != "" {
+			info.PermissionMode = s.Permissions.DefaultMode
+			info.PermissionSource = path
+		}
+		if info.Model == "" {
+			info.Model = s.Model
+		}
+	}
+
+	if abs, err := filepath.Abs(dir); err == nil {
+		if project, ok := loadUserConfig().Projects[abs]; ok {
+			info.MCPServers = append(info.MCPServers, mcpServers(project.MCPServers, "local")...)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, ".mcp.json")); err == nil { //nolint:gosec // G304: project MCP config
+		var project struct {
+			MCPServers map[string]mcpServerConfig `json:"mcpServers"`
+		}
+		if json.Unmarshal(data, &project) == nil {
+			info.MCPServers = append(info.MCPServers, mcpServers(project.MCPServers, "project")...)
+		}
+	}
+	return info
+}
+
+// RecentModels returns the models used by the most recent sessions,
+// most used first. At most limit sessions are scanned.
+func RecentModels(limit int) []string {
+	sessions, err := DiscoverSessions(SessionFilter{Limit: limit})
+	if err != nil {
+		return nil
+	}
+	counts := make(map[string]int)
+	for _, s := range sessions {
+		if usage, err := ReadUsage(s.Path); err == nil && usage.Model != "" {
+			counts[usage.Model]++
+		}
+	}
+	models := make([]string, 0, len(counts))
+	for m := range counts {
+		models = append(models, m)
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if counts[models[i]] != counts[models[j]] {
+			return counts[models[i]] > counts[models[j]]
+		}
+		return models[i] < models[j]
+	})
+	return models
+}
@@ -0,0 +1,67 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+		ok   bool
+	}{
+		{"2.0.14 (Claude Code)", "2.0.0", 1, true},
+		{"1.0.9", "1.0.10", -1, true},
+		{"2.1", "2.1.0", 0, true},
+		{"unknown", "2.0.0", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := CompareVersions(tt.a, tt.b)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("CompareVersions(%q, %q) = %d, %v; want %d, %v", tt.a, tt.b, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestProbeProject(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := t.TempDir()
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(home, ".claude", "settings.json"), `{"model":"opus","permissions":{"defaultMode":"plan"}}`)
+	write(filepath.Join(project, ".claude", "settings.json"), `{"permissions":{"defaultMode":"acceptEdits"}}`)
+	write(filepath.Join(project, ".mcp.json"), `{"mcpServers":{"docs":{"type":"http","url":"https://example.com/mcp"}}}`)
+	write(filepath.Join(home, ".claude.json"), `{"mcpServers":{"github":{"command":"gh-mcp"}},"projects":{"`+project+`":{"mcpServers":{"db":{"command":"db-mcp"}}}}}`)
+
+	info := ProbeProject(project)
+	if info.PermissionMode != PermissionAcceptEdits || info.PermissionSource != filepath.Join(project, ".claude", "settings.json") {
+		t.Errorf("permission = %s from %s", info.PermissionMode, info.PermissionSource)
+	}
+	if info.Model != "opus" {
+		t.Errorf("model = %q, want inherited user setting", info.Model)
+	}
+	if len(info.MCPServers) != 2 || info.MCPServers[0].Name != "db" || info.MCPServers[0].Scope != "local" ||
+		info.MCPServers[1].Name != "docs" || info.MCPServers[1].Target != "https://example.com/mcp" {
+		t.Errorf("project MCP servers = %+v", info.MCPServers)
+	}
+
+	user := UserMCPServers()
+	if len(user) != 1 || user[0].Name != "github" || user[0].Type != "stdio" {
+		t.Errorf("user MCP servers = %+v", user)
+	}
+
+	if got := ProbeProject(t.TempDir()); got.PermissionMode != "plan" {
+		t.Errorf("unconfigured project should inherit user mode, got %s", got.PermissionMode)
+	}
+}
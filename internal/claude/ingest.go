@@ -0,0 +1,431 @@
+package claude
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// ingestStatusStale is how many intervals may pass without a status
+// update before a running ingester is presumed gone.
+const ingestStatusStale = 3
+
+// IngestQueuePath returns the ingester's write-ahead queue: transcripts
+// that changed but haven't been indexed yet, one path per line.
+func IngestQueuePath() string {
+	return filepath.Join(state.CacheDir(), "session-ingest.queue")
+}
+
+// IngestStatusPath returns the file a running ingester reports to.
+func IngestStatusPath() string {
+	return filepath.Join(state.CacheDir(), "session-ingest.json")
+}
+
+// IngestOptions configures an Ingester.
+type IngestOptions struct {
+	// Interval is how often queued transcripts are indexed.
+	Interval time.Duration
+
+	// Quiet is how long a transcript must go unwritten before it is
+	// indexed, so one being written line by line is parsed once.
+	Quiet time.Duration
+
+	// MaxDelay indexes a transcript that never goes quiet at most this
+	// long after it was queued.
+	MaxDelay time.Duration
+
+	// MaxBatch caps how many transcripts one batch parses.
+	MaxBatch int
+
+	// Logf, if set, reports batches that leave a backlog and errors.
+	Logf func(format string, args ...any)
+}
+
+// IngestStatus is what a running ingester last reported.
+type IngestStatus struct {
+	PID       int           `json:"pid"`
+	StartedAt time.Time     `json:"started_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	Interval  time.Duration `json:"interval"`
+	MaxDelay  time.Duration `json:"max_delay"`
+	Queued    int           `json:"queued"`     // transcripts waiting to be indexed
+	Indexed   int           `json:"indexed"`    // transcripts indexed since it started
+	LastBatch int           `json:"last_batch"` // transcripts in the latest batch
+	LastAt    time.Time     `json:"last_at,omitempty"`
+}
+
+// Live reports whether the ingester is still updating its status.
+func (s IngestStatus) Live(now time.Time) bool {
+	return s.Interval > 0 && now.Sub(s.UpdatedAt) <= ingestStatusStale*s.Interval
+}
+
+// IngestInfo returns the status the daemon's ingester last wrote, or
+// false if it never ran.
+func IngestInfo() (IngestStatus, bool) {
+	var s IngestStatus
+	data, err := os.ReadFile(IngestStatusPath())
+	if err != nil || json.Unmarshal(data, &s) != nil {
+		return IngestStatus{}, false
+	}
+	return s, true
+}
+
+// ingestLag is how far behind a transcript the index may be while an
+// ingester is running: about MaxDelay, plus an interval for the batch
+// that picks it up. It is 0 when no ingester is live.
+func ingestLag(now time.Time) time.Duration {
+	s, ok := IngestInfo()
+	if !ok || !s.Live(now) {
+		return 0
+	}
+	return s.MaxDelay + s.Interval
+}
+
+// Ingester keeps the session index up to date as agents write
+// transcripts. Rather than every command re-parsing each active
+// transcript and rewriting the index, changes are queued, left until the
+// transcript goes quiet, and indexed in batches of at most MaxBatch each
+// Interval. While it runs, commands accept index entries up to MaxDelay
+// behind a growing transcript, so their latency stays flat however many
+// agents are writing.
+//
+// The queue is mirrored to a write-ahead file, so transcripts queued when
+// the ingester stops are indexed when it starts again.
+type Ingester struct {
+	opts    IngestOptions
+	dir     string
+	pending map[string]*queuedTranscript
+	seen    map[string]int64 // transcript -> mtime+size stamp at the last scan
+	queue   *os.File
+	status  IngestStatus
+}
+
+// queuedTranscript is a transcript waiting to be indexed.
+type queuedTranscript struct {
+	queued  time.Time // first change since it was last indexed
+	changed time.Time // latest change
+}
+
+// NewIngester creates an ingester for the local projects directory.
+func NewIngester(opts IngestOptions) *Ingester {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.MaxDelay < opts.Quiet {
+		opts.MaxDelay = opts.Quiet
+	}
+	if opts.MaxBatch <= 0 {
+		opts.MaxBatch = 50
+	}
+	return &Ingester{
+		opts:    opts,
+		dir:     ProjectsDir(),
+		pending: make(map[string]*queuedTranscript),
+		seen:    make(map[string]int64),
+	}
+}
+
+// Run ingests changes until ctx is cancelled. On Linux changes are picked
+// up from inotify; elsewhere transcripts are polled.
+func (g *Ingester) Run(ctx context.Context) error {
+	if err := g.open(time.Now()); err != nil {
+		return err
+	}
+	defer g.close()
+
+	changes, err := notifyChanges(ctx, g.dir)
+	scanInterval := watchRescanInterval
+	if err != nil {
+		changes, scanInterval = nil, watchPollInterval
+	}
+	scan := time.NewTicker(scanInterval)
+	defer scan.Stop()
+	batch := time.NewTicker(g.opts.Interval)
+	defer batch.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case path, ok := <-changes:
+			if !ok {
+				changes = nil
+				scan.Reset(watchPollInterval)
+				continue
+			}
+			if strings.HasSuffix(path, ".jsonl") {
+				g.note(path, time.Now())
+			} else {
+				g.scanDir(path, time.Now())
+			}
+		case <-scan.C:
+			g.scan(time.Now())
+		case <-batch.C:
+			g.ingest(time.Now())
+		}
+	}
+}
+
+// open recovers the write-ahead queue, queues transcripts the index is
+// behind on, and opens the queue for appending.
+func (g *Ingester) open(now time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(IngestQueuePath()), 0755); err != nil {
+		return err
+	}
+	for _, path := range readIngestQueue() {
+		// Queued before a restart: due at the first batch
+		g.pending[path] = &queuedTranscript{}
+	}
+
+	idx := loadSessionIndex(g.dir)
+	g.eachTranscript(g.dir, func(path string, stat os.FileInfo) {
+		g.seen[path] = statStamp(stat)
+		if _, ok := g.pending[path]; ok {
+			return
+		}
+		if entry, ok := idx.entry(path); !ok || entry.Size != stat.Size() || entry.ModTime != stat.ModTime().UnixNano() {
+			g.pending[path] = &queuedTranscript{}
+		}
+	})
+	if err := g.rewriteQueue(); err != nil {
+		return fmt.Errorf("writing ingest queue: %w", err)
+	}
+
+	g.status = IngestStatus{
+		PID:       os.Getpid(),
+		StartedAt: now,
+		Interval:  g.opts.Interval,
+		MaxDelay:  g.opts.MaxDelay,
+	}
+	g.writeStatus(now)
+	return nil
+}
+
+// close releases the queue and withdraws the status, so commands stop
+// relying on an ingester that is gone.
+func (g *Ingester) close() {
+	if g.queue != nil {
+		_ = g.queue.Close()
+	}
+	_ = os.Remove(IngestStatusPath())
+}
+
+// note queues a changed transcript, or pushes back its quiet period if it
+// is queued already.
+func (g *Ingester) note(path string, now time.Time) {
+	if isSubagentTranscript(path) {
+		return
+	}
+	if q, ok := g.pending[path]; ok {
+		q.changed = now
+		return
+	}
+	g.pending[path] = &queuedTranscript{queued: now, changed: now}
+	if g.queue != nil {
+		if _, err := fmt.Fprintln(g.queue, path); err != nil {
+			g.logf("Session ingest: writing queue: %v", err)
+		}
+	}
+}
+
+// scan queues every transcript that changed since the last scan, for
+// when notifications are unavailable or missed something.
+func (g *Ingester) scan(now time.Time) {
+	projects, err := os.ReadDir(g.dir)
+	if err != nil {
+		return
+	}
+	for _, p := range projects {
+		if p.IsDir() {
+			g.scanDir(filepath.Join(g.dir, p.Name()), now)
+		}
+	}
+}
+
+// scanDir queues the transcripts in one project directory that changed
+// since the last scan.
+func (g *Ingester) scanDir(dir string, now time.Time) {
+	g.eachTranscriptIn(dir, func(path string, stat os.FileInfo) {
+		stamp := statStamp(stat)
+		if g.seen[path] != stamp {
+			g.seen[path] = stamp
+			g.note(path, now)
+		}
+	})
+}
+
+// ingest indexes up to MaxBatch due transcripts, oldest queued first: the
+// ones unwritten for Quiet, or queued for MaxDelay. The index is loaded
+// just before the results are stored and saved once. Annotations,
+// summaries, and cached comparisons are kept in files of their own, so
+// saving it can't lose them.
+func (g *Ingester) ingest(now time.Time) {
+	due := g.due(now)
+	if len(due) > g.opts.MaxBatch {
+		due = due[:g.opts.MaxBatch]
+	}
+
+	if len(due) > 0 {
+		jobs := make([]parseJob, 0, len(due))
+		stats := make([]os.FileInfo, 0, len(due))
+		for _, path := range due {
+			delete(g.pending, path)
+			stat, err := os.Stat(path)
+			if err != nil {
+				delete(g.seen, path)
+				continue
+			}
+			g.seen[path] = statStamp(stat)
+			jobs = append(jobs, parseJob{path: path, project: filepath.Base(filepath.Dir(path))})
+			stats = append(stats, stat)
+		}
+		results, _ := parseSessions(nil, jobs, 0, time.Time{})
+
+		idx := loadSessionIndex(g.dir)
+		for i, info := range results {
+			if info != nil {
+				idx.store(jobs[i].path, stats[i], info)
+			}
+		}
+		idx.save()
+
+		if err := g.rewriteQueue(); err != nil {
+			g.logf("Session ingest: writing queue: %v", err)
+		}
+		g.status.Indexed += len(jobs)
+		g.status.LastBatch = len(jobs)
+		g.status.LastAt = now
+		if backlog := len(g.due(now)); backlog > 0 {
+			g.logf("Session ingest: indexed %d transcript(s), %d more due", len(jobs), backlog)
+		}
+	}
+	g.writeStatus(now)
+}
+
+// due returns the queued transcripts ready to index, oldest queued first.
+func (g *Ingester) due(now time.Time) []string {
+	var due []string
+	for path, q := range g.pending {
+		if !now.Before(q.changed.Add(g.opts.Quiet)) || !now.Before(q.queued.Add(g.opts.MaxDelay)) {
+			due = append(due, path)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		qi, qj := g.pending[due[i]], g.pending[due[j]]
+		if !qi.queued.Equal(qj.queued) {
+			return qi.queued.Before(qj.queued)
+		}
+		return due[i] < due[j]
+	})
+	return due
+}
+
+// rewriteQueue replaces the write-ahead queue with what is still pending
+// and reopens it for appending.
+func (g *Ingester) rewriteQueue() error {
+	paths := make([]string, 0, len(g.pending))
+	for path := range g.pending {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var b strings.Builder
+	for _, path := range paths {
+		b.WriteString(path)
+		b.WriteByte('\n')
+	}
+
+	if g.queue != nil {
+		_ = g.queue.Close()
+		g.queue = nil
+	}
+	path := IngestQueuePath()
+	if err := util.AtomicWriteFile(path, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	g.queue = f
+	return nil
+}
+
+// writeStatus records the ingester's progress for commands and gt daemon
+// status.
+func (g *Ingester) writeStatus(now time.Time) {
+	g.status.UpdatedAt = now
+	g.status.Queued = len(g.pending)
+	if err := util.AtomicWriteJSON(IngestStatusPath(), g.status); err != nil {
+		g.logf("Session ingest: writing status: %v", err)
+	}
+}
+
+// eachTranscript calls fn for every top-level transcript under dir.
+func (g *Ingester) eachTranscript(dir string, fn func(path string, stat os.FileInfo)) {
+	projects, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, p := range projects {
+		if p.IsDir() {
+			g.eachTranscriptIn(filepath.Join(dir, p.Name()), fn)
+		}
+	}
+}
+
+// eachTranscriptIn calls fn for each top-level transcript in a project
+// directory.
+func (g *Ingester) eachTranscriptIn(dir string, fn func(path string, stat os.FileInfo)) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".jsonl") || isSubagentTranscript(f.Name()) {
+			continue
+		}
+		if stat, err := f.Info(); err == nil {
+			fn(filepath.Join(dir, f.Name()), stat)
+		}
+	}
+}
+
+func (g *Ingester) logf(format string, args ...any) {
+	if g.opts.Logf != nil {
+		g.opts.Logf(format, args...)
+	}
+}
+
+// readIngestQueue returns the distinct paths in the write-ahead queue.
+func readIngestQueue() []string {
+	f, err := os.Open(IngestQueuePath())
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	seen := make(map[string]bool)
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		path := strings.TrimSpace(scanner.Text())
+		if path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// statStamp identifies a version of a file by its mtime and size.
+func statStamp(stat os.FileInfo) int64 {
+	return stat.ModTime().UnixNano() ^ stat.Size()
+}
@@ -0,0 +1,178 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+func ingestTestHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	return home
+}
+
+func appendLine(t *testing.T, path, line string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIngesterBatches(t *testing.T) {
+	home := ingestTestHome(t)
+	line := `{"type":"user","timestamp":"2025-12-30T15:42:00Z","message":{"role":"user","content":"hello"}}`
+	a := writeSession(t, home, "-home-u-a", "aaaa1111.jsonl", line)
+	writeSession(t, home, "-home-u-b", "bbbb2222.jsonl", line)
+	c := writeSession(t, home, "-home-u-c", "cccc3333.jsonl", line)
+
+	g := NewIngester(IngestOptions{Interval: time.Second, Quiet: 2 * time.Second, MaxDelay: 10 * time.Second, MaxBatch: 2})
+	now := time.Now()
+	if err := g.open(now); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer g.close()
+
+	// Unindexed transcripts are queued at start and due at once, a batch at a time
+	g.ingest(now)
+	if n := loadSessionIndex(ProjectsDir()).count(); n != 2 {
+		t.Errorf("after first batch: %d entries, want 2", n)
+	}
+	if queued := readIngestQueue(); len(queued) != 1 || queued[0] != c {
+		t.Errorf("queue = %v, want [%s]", queued, c)
+	}
+	status, ok := IngestInfo()
+	if !ok || status.Queued != 1 || status.Indexed != 2 || status.LastBatch != 2 || !status.Live(now) {
+		t.Errorf("status = %+v", status)
+	}
+	g.ingest(now)
+	if n := loadSessionIndex(ProjectsDir()).count(); n != 3 {
+		t.Errorf("after second batch: %d entries, want 3", n)
+	}
+	if queued := readIngestQueue(); len(queued) != 0 {
+		t.Errorf("queue = %v, want empty", queued)
+	}
+
+	// A change waits until the transcript goes quiet
+	appendLine(t, a, `{"type":"assistant","timestamp":"2025-12-30T15:43:00Z","message":{"role":"assistant","content":"hi"}}`)
+	g.note(a, now)
+	if queued := readIngestQueue(); len(queued) != 1 || queued[0] != a {
+		t.Errorf("queue after change = %v, want [%s]", queued, a)
+	}
+	g.ingest(now.Add(time.Second))
+	if entry, _ := loadSessionIndex(ProjectsDir()).entry(a); entry.Info.EndTime.Minute() != 42 {
+		t.Errorf("indexed before quiet: ends %s", entry.Info.EndTime)
+	}
+	g.ingest(now.Add(3 * time.Second))
+	if entry, _ := loadSessionIndex(ProjectsDir()).entry(a); entry.Info.EndTime.Minute() != 43 {
+		t.Errorf("not indexed once quiet: ends %s", entry.Info.EndTime)
+	}
+}
+
+func TestIngesterMaxDelay(t *testing.T) {
+	ingestTestHome(t)
+	g := NewIngester(IngestOptions{Quiet: 2 * time.Second, MaxDelay: 10 * time.Second})
+	start := time.Now()
+	path := filepath.Join(ProjectsDir(), "-home-u-a", "aaaa1111.jsonl")
+
+	// Written every second: never quiet, but due after MaxDelay
+	for i := 0; i < 10; i++ {
+		g.note(path, start.Add(time.Duration(i)*time.Second))
+		if due := g.due(start.Add(time.Duration(i) * time.Second)); len(due) != 0 {
+			t.Fatalf("due after %ds: %v", i, due)
+		}
+	}
+	if due := g.due(start.Add(10 * time.Second)); len(due) != 1 {
+		t.Errorf("due after MaxDelay = %v, want [%s]", due, path)
+	}
+
+	// Subagent transcripts are left to discovery
+	g.note(filepath.Join(ProjectsDir(), "-home-u-a", "agent-1234.jsonl"), start)
+	if len(g.pending) != 1 {
+		t.Errorf("pending = %d, want 1", len(g.pending))
+	}
+}
+
+func TestIngesterRecoversQueue(t *testing.T) {
+	home := ingestTestHome(t)
+	path := writeSession(t, home, "-home-u-a", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-12-30T15:42:00Z","message":{"role":"user","content":"hello"}}`)
+	if _, err := scanSessions(SessionFilter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Indexed already, but left in the queue by an ingester that stopped
+	if err := os.MkdirAll(filepath.Dir(IngestQueuePath()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(IngestQueuePath(), []byte(path+"\n"+path+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	g := NewIngester(IngestOptions{})
+	if err := g.open(time.Now()); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	g.close()
+	if len(g.pending) != 1 || g.pending[path] == nil {
+		t.Errorf("pending = %v, want %s", g.pending, path)
+	}
+	if _, ok := IngestInfo(); ok {
+		t.Error("status left behind after close")
+	}
+}
+
+func TestIndexLookupWhileIngesting(t *testing.T) {
+	home := ingestTestHome(t)
+	path := writeSession(t, home, "-home-u-a", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-12-30T15:42:00Z","message":{"role":"user","content":"hello"}}`)
+	if _, err := scanSessions(SessionFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	appendLine(t, path, `{"type":"assistant","timestamp":"2025-12-30T15:43:00Z","message":{"role":"assistant","content":"hi"}}`)
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := loadSessionIndex(ProjectsDir()).lookup(path, stat); ok {
+		t.Error("grown transcript used without an ingester")
+	}
+
+	status := IngestStatus{UpdatedAt: time.Now(), Interval: 5 * time.Second, MaxDelay: 30 * time.Second}
+	if err := util.AtomicWriteJSON(IngestStatusPath(), status); err != nil {
+		t.Fatal(err)
+	}
+	info, ok := loadSessionIndex(ProjectsDir()).lookup(path, stat)
+	if !ok || info.EndTime.Minute() != 42 {
+		t.Errorf("lookup while ingesting = %+v, %v; want the indexed entry", info, ok)
+	}
+
+	// A rewritten transcript is never taken from the index
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", 10)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stat, _ = os.Stat(path)
+	if _, ok := loadSessionIndex(ProjectsDir()).lookup(path, stat); ok {
+		t.Error("shrunken transcript taken from the index")
+	}
+
+	// Nor is anything once the ingester stops reporting
+	status.UpdatedAt = time.Now().Add(-time.Minute)
+	if err := util.AtomicWriteJSON(IngestStatusPath(), status); err != nil {
+		t.Fatal(err)
+	}
+	if lag := ingestLag(time.Now()); lag != 0 {
+		t.Errorf("lag with stale status = %v, want 0", lag)
+	}
+}
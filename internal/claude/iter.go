@@ -0,0 +1,155 @@
+package claude
+
+import (
+	"context"
+	"iter"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// SessionIterator is implemented by SessionStores that can stream
+// sessions. IterSessions falls back to List for stores that don't.
+type SessionIterator interface {
+	Iter(ctx context.Context, filter SessionFilter) iter.Seq2[SessionInfo, error]
+}
+
+// IterSessions yields the sessions filter matches as they are parsed,
+// rather than collecting them all first as DiscoverSessions does, so a
+// caller can stop early and huge histories are read in constant memory.
+// Sessions come one project directory at a time, most recent first
+// within each; across directories the order is unspecified.
+//
+// Errors are yielded with a zero SessionInfo and end the iteration: a
+// cancelled ctx, an unreadable Claude data directory, or ErrIncomplete
+// when a project directory takes longer than filter.Timeout to read (time
+// the caller spends between sessions doesn't count). Unlike
+// DiscoverSessions, iteration doesn't prune index entries for deleted
+// transcripts.
+func IterSessions(ctx context.Context, filter SessionFilter) iter.Seq2[SessionInfo, error] {
+	s := currentStore()
+	if it, ok := s.(SessionIterator); ok {
+		return it.Iter(ctx, filter)
+	}
+	return func(yield func(SessionInfo, error) bool) {
+		sessions, err := s.List(filter)
+		for _, info := range sessions {
+			if !yield(info, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(SessionInfo{}, err)
+		}
+	}
+}
+
+// Verify FileStore streams sessions.
+var _ SessionIterator = FileStore{}
+
+// Iter implements SessionIterator.
+func (FileStore) Iter(ctx context.Context, filter SessionFilter) iter.Seq2[SessionInfo, error] {
+	return func(yield func(SessionInfo, error) bool) {
+		iterSessionFiles(ctx, filter, yield)
+	}
+}
+
+// iterSessionFiles is FileStore.Iter. Each project directory is parsed
+// and classified as a batch, so subagents find their parents and
+// ClassifySession sees which session is newest in the directory.
+func iterSessionFiles(ctx context.Context, filter SessionFilter, yield func(SessionInfo, error) bool) {
+	roots := filter.Roots
+	if len(roots) == 0 {
+		roots = Roots()
+	}
+	roots = resolveRoots(roots)
+	if skipRemoteRoots() {
+		roots = localRoots(roots)
+	}
+
+	var idx *sessionIndex
+	if sessionIndexEnabled() {
+		idx = loadSessionIndex(ProjectsDir())
+		defer idx.save()
+	}
+
+	timeout := discoveryTimeout(filter.Timeout)
+	states := newStateClassifier(time.Now(), nil)
+	notes := loadAnnotations()
+	yielded := 0
+	for _, root := range roots {
+		projectsDir := filepath.Join(root, "projects")
+		projects, err := os.ReadDir(projectsDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			yield(SessionInfo{}, err)
+			return
+		}
+
+		for _, project := range projects {
+			if err := ctx.Err(); err != nil {
+				yield(SessionInfo{}, err)
+				return
+			}
+			if !project.IsDir() {
+				continue
+			}
+			seen := make(map[string]bool)
+			jobs := projectDirJobs(projectsDir, project.Name(), root, filter, seen)
+			if idx != nil && filter.Rig != "" {
+				jobs = idx.skipOtherRigs(jobs, seen, filter.Rig)
+			}
+			parsed, unparsed := parseSessions(idx, jobs, filter.Workers, batchDeadline(ctx, timeout))
+			if unparsed > 0 {
+				if err := ctx.Err(); err != nil {
+					yield(SessionInfo{}, err)
+				} else {
+					yield(SessionInfo{}, incompleteError(nil, unparsed, len(jobs)))
+				}
+				return
+			}
+			if filter.IncludeSubagents {
+				attributeSubagents(parsed)
+			}
+
+			states.reset(parsed)
+			var batch []*SessionInfo
+			for _, info := range parsed {
+				if info != nil && annotateSession(info, filter, idx, notes, states) {
+					batch = append(batch, info)
+				}
+			}
+			if filter.CollapseResumes {
+				batch = collapseResumes(batch)
+			}
+			sort.SliceStable(batch, func(i, j int) bool {
+				return batch[i].StartTime.After(batch[j].StartTime)
+			})
+			for _, info := range batch {
+				if !yield(*info, nil) {
+					return
+				}
+				if yielded++; filter.Limit > 0 && yielded == filter.Limit {
+					return
+				}
+			}
+		}
+	}
+}
+
+// batchDeadline returns when reading a project directory that starts now
+// times out: after timeout (none if negative), or at ctx's deadline if
+// sooner.
+func batchDeadline(ctx context.Context, timeout time.Duration) time.Time {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	return deadline
+}
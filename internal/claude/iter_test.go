@@ -0,0 +1,77 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIterSessions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-home-u-a", "a1.jsonl",
+		`{"type":"user","timestamp":"2025-03-01T12:00:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/crew/joe <- deacon • 2025-03-01T12:00 • work"}}`,
+	)
+	writeSession(t, home, "-home-u-a", "a2.jsonl",
+		`{"type":"user","timestamp":"2025-03-02T12:00:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/crew/joe <- deacon • 2025-03-02T12:00 • work"}}`,
+	)
+	writeSession(t, home, "-home-u-b", "b1.jsonl",
+		`{"type":"user","timestamp":"2025-03-03T12:00:00Z","message":{"role":"user","content":"hello"}}`,
+	)
+
+	var ids []string
+	for s, err := range IterSessions(context.Background(), SessionFilter{GasTownOnly: true}) {
+		if err != nil {
+			t.Fatalf("IterSessions: %v", err)
+		}
+		ids = append(ids, s.ID)
+	}
+	if len(ids) != 2 || ids[0] != "a2" || ids[1] != "a1" {
+		t.Errorf("got %v, want [a2 a1]", ids)
+	}
+
+	// Stopping early
+	n := 0
+	for range IterSessions(context.Background(), SessionFilter{}) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("iterated %d sessions after break", n)
+	}
+
+	ids = nil
+	for s, err := range IterSessions(context.Background(), SessionFilter{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("IterSessions: %v", err)
+		}
+		ids = append(ids, s.ID)
+	}
+	if len(ids) != 2 {
+		t.Errorf("Limit 2 yielded %v", ids)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range IterSessions(ctx, SessionFilter{}) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("cancelled iteration yielded err = %v, want context.Canceled", err)
+		}
+	}
+}
+
+func TestIterSessionsOtherStore(t *testing.T) {
+	defer SetStore(fakeStore{sessions: []SessionInfo{{ID: "x"}, {ID: "y"}}})()
+
+	var ids []string
+	for s, err := range IterSessions(context.Background(), SessionFilter{}) {
+		if err != nil {
+			t.Fatalf("IterSessions: %v", err)
+		}
+		ids = append(ids, s.ID)
+	}
+	if len(ids) != 2 || ids[0] != "x" {
+		t.Errorf("got %v, want the store's sessions", ids)
+	}
+}
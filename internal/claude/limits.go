@@ -0,0 +1,90 @@
+package claude
+
+import "fmt"
+
+// maxLineSize is the longest transcript line the session parser reads.
+// A longer line (typically a huge tool result) stops the scan, so the
+// rest of the session is never parsed and may not resume cleanly.
+const maxLineSize = 10 * 1024 * 1024
+
+// Limits are the thresholds at which a session is flagged as risky to
+// resume.
+type Limits struct {
+	// ContextWindow is the model's context size in tokens. Default 200k.
+	ContextWindow int64
+
+	// ContextFull flags sessions whose last reply used at least this
+	// fraction of ContextWindow (0-1). Default 0.9.
+	ContextFull float64
+
+	// MaxCompactions flags sessions that compacted more than this many
+	// times. Default 3.
+	MaxCompactions int
+
+	// MaxErrorRate flags sessions where more than this fraction of tool
+	// calls failed (0-1), once at least MinToolErrors have. Defaults 0.25
+	// and 5.
+	MaxErrorRate  float64
+	MinToolErrors int
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.ContextWindow <= 0 {
+		l.ContextWindow = 200_000
+	}
+	if l.ContextFull <= 0 {
+		l.ContextFull = 0.9
+	}
+	if l.MaxCompactions <= 0 {
+		l.MaxCompactions = 3
+	}
+	if l.MaxErrorRate <= 0 {
+		l.MaxErrorRate = 0.25
+	}
+	if l.MinToolErrors <= 0 {
+		l.MinToolErrors = 5
+	}
+	return l
+}
+
+// SessionWarning is a limit a session is at or near.
+type SessionWarning struct {
+	Glyph  string `json:"glyph"`
+	Reason string `json:"reason"`
+}
+
+// Warnings returns the limits s is at or near: a transcript line too long
+// to parse (✂), a context nearly full when the session ended (◕), more
+// compactions than l allows (↻), or a flaky run of failing tool calls
+// (‼). Such sessions are risky to resume.
+func (s SessionInfo) Warnings(l Limits) []SessionWarning {
+	l = l.withDefaults()
+	var out []SessionWarning
+	if s.Truncated {
+		out = append(out, SessionWarning{"✂", fmt.Sprintf("has a line over %d MB; the rest was not parsed", maxLineSize>>20)})
+	}
+	if s.ContextTokens > 0 && float64(s.ContextTokens) >= l.ContextFull*float64(l.ContextWindow) {
+		out = append(out, SessionWarning{"◕", fmt.Sprintf("context %.0f%% full at end (%dk tokens)",
+			100*float64(s.ContextTokens)/float64(l.ContextWindow), s.ContextTokens/1000)})
+	}
+	if s.CompactionCount > l.MaxCompactions {
+		out = append(out, SessionWarning{"↻", fmt.Sprintf("compacted %d times", s.CompactionCount)})
+	}
+	if s.ErrorCount >= l.MinToolErrors && s.ErrorRate() > l.MaxErrorRate {
+		reason := fmt.Sprintf("%d of %d tool calls failed (%.0f%%)", s.ErrorCount, s.ToolCalls, 100*s.ErrorRate())
+		if top := s.ToolErrorBreakdown(); len(top) > 0 {
+			reason += ", mostly " + top[0].Tool
+		}
+		out = append(out, SessionWarning{"‼", reason})
+	}
+	return out
+}
+
+// WarningGlyphs joins the glyphs of warnings, e.g. "◕↻".
+func WarningGlyphs(warnings []SessionWarning) string {
+	var glyphs string
+	for _, w := range warnings {
+		glyphs += w.Glyph
+	}
+	return glyphs
+}
@@ -0,0 +1,57 @@
+package claude
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSessionLimits(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-home-u-proj", "full.jsonl",
+		`{"type":"user","timestamp":"2025-03-01T12:00:00Z","message":{"role":"user","content":"hi"}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:00:05Z","message":{"id":"m1","usage":{"input_tokens":10,"cache_read_input_tokens":1000}}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:00:09Z","message":{"id":"m2","usage":{"input_tokens":5,"cache_creation_input_tokens":5000,"cache_read_input_tokens":180000}}}`,
+	)
+	writeSession(t, home, "-home-u-proj", "huge.jsonl",
+		`{"type":"user","timestamp":"2025-03-02T12:00:00Z","message":{"role":"user","content":"hi"}}`,
+		`{"type":"user","timestamp":"2025-03-02T12:00:01Z","message":{"role":"user","content":"`+strings.Repeat("x", maxLineSize)+`"}}`,
+		`{"type":"user","timestamp":"2025-03-02T12:00:02Z","message":{"role":"user","content":"never read"}}`,
+	)
+
+	sessions, err := DiscoverSessions(SessionFilter{})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	byID := make(map[string]SessionInfo)
+	for _, s := range sessions {
+		byID[s.ID] = s
+	}
+
+	full := byID["full"]
+	if full.ContextTokens != 185005 || full.Truncated {
+		t.Fatalf("full: context %d, truncated %v", full.ContextTokens, full.Truncated)
+	}
+	if got := WarningGlyphs(full.Warnings(Limits{})); got != "◕" {
+		t.Errorf("full warnings = %q", got)
+	}
+	if got := full.Warnings(Limits{ContextWindow: 1_000_000}); len(got) != 0 {
+		t.Errorf("full with a 1M window = %v", got)
+	}
+
+	huge := byID["huge"]
+	if !huge.Truncated || huge.UserMessages != 1 {
+		t.Fatalf("huge: truncated %v, %d user messages", huge.Truncated, huge.UserMessages)
+	}
+	if got := WarningGlyphs(huge.Warnings(Limits{})); got != "✂" {
+		t.Errorf("huge warnings = %q", got)
+	}
+
+	if got := WarningGlyphs(SessionInfo{CompactionCount: 4}.Warnings(Limits{})); got != "↻" {
+		t.Errorf("compactions warnings = %q", got)
+	}
+	if got := (SessionInfo{CompactionCount: 4}).Warnings(Limits{MaxCompactions: 5}); len(got) != 0 {
+		t.Errorf("compactions under the limit = %v", got)
+	}
+}
@@ -0,0 +1,237 @@
+package claude
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Lineage kinds: how a session relates to its parent.
+const (
+	LineageResume = "resume" // continues from the parent's last message
+	LineageFork   = "fork"   // branches from an earlier message
+)
+
+// LineageNode is a session in a lineage tree.
+type LineageNode struct {
+	Session SessionInfo `json:"session"`
+
+	// Kind is LineageResume or LineageFork; empty for the root.
+	Kind string `json:"kind,omitempty"`
+
+	// BranchPoint is the UUID of the parent's message this session picks
+	// up from.
+	BranchPoint string `json:"branch_point,omitempty"`
+
+	Children []*LineageNode `json:"children,omitempty"`
+}
+
+// Find returns the node for a session ID in the tree, or nil.
+func (n *LineageNode) Find(id string) *LineageNode {
+	if n.Session.ID == id {
+		return n
+	}
+	for _, c := range n.Children {
+		if found := c.Find(id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for each node, depth first, parents before children.
+func (n *LineageNode) Walk(fn func(node *LineageNode, depth int)) {
+	n.walk(fn, 0)
+}
+
+func (n *LineageNode) walk(fn func(*LineageNode, int), depth int) {
+	fn(n, depth)
+	for _, c := range n.Children {
+		c.walk(fn, depth+1)
+	}
+}
+
+// Size returns the number of sessions in the tree.
+func (n *LineageNode) Size() int {
+	size := 0
+	n.Walk(func(*LineageNode, int) { size++ })
+	return size
+}
+
+// lineageScan is what one transcript contributes to lineage.
+type lineageScan struct {
+	info  *SessionInfo
+	uuids []string // in file order
+	has   map[string]bool
+
+	// external is the first parentUuid that refers to a message outside
+	// the transcript: the message a resume or fork continued from.
+	external string
+
+	// foreign is a sessionId other than the transcript's own, carried by
+	// history copied in from another session.
+	foreign string
+}
+
+// Lineage returns the tree of sessions linked to id by resumes and forks,
+// rooted at the original session. Sessions are linked when one continues
+// from a message of another: through parentUuid, or through history
+// Claude Code copied into the new transcript. Only sessions in the same
+// project directory are considered, since resumes keep the working
+// directory. id may be a unique prefix.
+func Lineage(id string) (*LineageNode, error) {
+	session, err := FindSession(id)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(session.Path)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	scans := make(map[string]*lineageScan)
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".jsonl") {
+			continue
+		}
+		path := filepath.Join(dir, f.Name())
+		if isSubagentTranscript(path) {
+			continue
+		}
+		info, err := parseSession(path, filepath.Base(dir))
+		if err != nil || info == nil {
+			continue
+		}
+		scan, err := scanLineage(path)
+		if err != nil {
+			continue
+		}
+		scan.info = info
+		scans[info.ID] = scan
+	}
+	return buildLineage(scans, session.ID), nil
+}
+
+// scanLineage reads the message links of a transcript.
+func scanLineage(path string) (*lineageScan, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	own := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	scan := &lineageScan{has: make(map[string]bool)}
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+	for scanner.Scan() {
+		e, err := decodeEntry(scanner.Bytes())
+		if err != nil || e.UUID == "" {
+			continue
+		}
+		if scan.external == "" && e.ParentUUID != "" && !scan.has[e.ParentUUID] {
+			scan.external = e.ParentUUID
+		}
+		if scan.foreign == "" && e.SessionID != "" && e.SessionID != own {
+			scan.foreign = e.SessionID
+		}
+		if !scan.has[e.UUID] {
+			scan.has[e.UUID] = true
+			scan.uuids = append(scan.uuids, e.UUID)
+		}
+	}
+	return scan, scanner.Err()
+}
+
+// buildLineage links scanned sessions to their parents and returns the
+// tree containing id.
+func buildLineage(scans map[string]*lineageScan, id string) *LineageNode {
+	nodes, parents := linkLineage(scans)
+	root := id
+	for parents[root] != "" {
+		root = parents[root]
+	}
+	return nodes[root]
+}
+
+// linkLineage links scanned sessions to their parents. It returns a node
+// per session, with its children attached, and the parent of each
+// session that has one.
+func linkLineage(scans map[string]*lineageScan) (nodes map[string]*LineageNode, parents map[string]string) {
+	// Oldest first, so a message is attributed to the session that wrote
+	// it rather than to later ones that copied it
+	ids := make([]string, 0, len(scans))
+	for sid := range scans {
+		ids = append(ids, sid)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := scans[ids[i]].info, scans[ids[j]].info
+		if !a.StartTime.Equal(b.StartTime) {
+			return a.StartTime.Before(b.StartTime)
+		}
+		return a.ID < b.ID
+	})
+
+	nodes = make(map[string]*LineageNode, len(ids))
+	parents = make(map[string]string)
+	for _, sid := range ids {
+		nodes[sid] = &LineageNode{Session: *scans[sid].info}
+	}
+	for i, sid := range ids {
+		scan := scans[sid]
+		parent, point := "", ""
+
+		// A copied history: the nearest older session holding the last
+		// message this one shares with it
+		for j := i - 1; j >= 0 && parent == ""; j-- {
+			other := scans[ids[j]]
+			for k := len(scan.uuids) - 1; k >= 0; k-- {
+				if other.has[scan.uuids[k]] {
+					parent, point = ids[j], scan.uuids[k]
+					break
+				}
+			}
+		}
+		// A reference to a message that lives elsewhere, attributed to
+		// the session that wrote it rather than one that copied it
+		if parent == "" && scan.external != "" {
+			for j := 0; j < i; j++ {
+				if scans[ids[j]].has[scan.external] {
+					parent, point = ids[j], scan.external
+					break
+				}
+			}
+		}
+		// History tagged with another session's ID
+		if parent == "" && scan.foreign != "" && scans[scan.foreign] != nil && scan.foreign != sid {
+			parent = scan.foreign
+		}
+		if parent == "" || wouldCycle(parents, sid, parent) {
+			continue
+		}
+
+		node := nodes[sid]
+		node.BranchPoint = point
+		node.Kind = LineageResume
+		if p := scans[parent].uuids; point != "" && len(p) > 0 && p[len(p)-1] != point {
+			node.Kind = LineageFork
+		}
+		parents[sid] = parent
+		nodes[parent].Children = append(nodes[parent].Children, node)
+	}
+	return nodes, parents
+}
+
+// wouldCycle reports whether making parent the parent of id would loop.
+func wouldCycle(parents map[string]string, id, parent string) bool {
+	for p := parent; p != ""; p = parents[p] {
+		if p == id {
+			return true
+		}
+	}
+	return false
+}
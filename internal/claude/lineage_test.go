@@ -0,0 +1,80 @@
+package claude
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLineage(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := "-town-gastown-crew-joe"
+	line := func(session, uuid, parent, ts string) string {
+		return fmt.Sprintf(`{"type":"user","uuid":%q,"parentUuid":%q,"sessionId":%q,"timestamp":"2025-01-01T%s:00Z","message":{"role":"user","content":"hi"}}`,
+			uuid, parent, session, ts)
+	}
+
+	writeSession(t, home, project, "aaaa0001.jsonl",
+		line("aaaa0001", "a1", "", "00:00"),
+		line("aaaa0001", "a2", "a1", "00:01"),
+		line("aaaa0001", "a3", "a2", "00:02"),
+	)
+	// Resumed: history copied in, then continued
+	writeSession(t, home, project, "bbbb0002.jsonl",
+		line("aaaa0001", "a1", "", "01:00"),
+		line("aaaa0001", "a2", "a1", "01:00"),
+		line("aaaa0001", "a3", "a2", "01:00"),
+		line("bbbb0002", "b1", "a3", "01:01"),
+	)
+	// Forked from the first message, by reference
+	writeSession(t, home, project, "cccc0003.jsonl",
+		line("cccc0003", "c1", "a1", "02:00"),
+	)
+	// Resumed from the resume
+	writeSession(t, home, project, "dddd0004.jsonl",
+		line("dddd0004", "d1", "b1", "03:00"),
+	)
+	// Unrelated
+	writeSession(t, home, project, "eeee0005.jsonl",
+		line("eeee0005", "e1", "", "04:00"),
+	)
+
+	root, err := Lineage("dddd")
+	if err != nil {
+		t.Fatalf("Lineage: %v", err)
+	}
+	if root.Session.ID != "aaaa0001" || root.Kind != "" {
+		t.Fatalf("root = %s (%s), want aaaa0001", root.Session.ID, root.Kind)
+	}
+	if root.Size() != 4 {
+		t.Errorf("size = %d, want 4", root.Size())
+	}
+	for id, want := range map[string]struct{ kind, point string }{
+		"bbbb0002": {LineageResume, "a3"},
+		"cccc0003": {LineageFork, "a1"},
+		"dddd0004": {LineageResume, "b1"},
+	} {
+		n := root.Find(id)
+		if n == nil {
+			t.Errorf("%s missing from lineage", id)
+			continue
+		}
+		if n.Kind != want.kind || n.BranchPoint != want.point {
+			t.Errorf("%s: kind %q at %q, want %q at %q", id, n.Kind, n.BranchPoint, want.kind, want.point)
+		}
+	}
+	if root.Find("eeee0005") != nil {
+		t.Error("unrelated session linked")
+	}
+	if b := root.Find("bbbb0002"); len(b.Children) != 1 || b.Children[0].Session.ID != "dddd0004" {
+		t.Errorf("bbbb0002 children = %+v", b.Children)
+	}
+
+	alone, err := Lineage("eeee0005")
+	if err != nil {
+		t.Fatalf("Lineage: %v", err)
+	}
+	if alone.Session.ID != "eeee0005" || len(alone.Children) != 0 {
+		t.Errorf("unrelated lineage = %+v", alone)
+	}
+}
@@ -0,0 +1,115 @@
+package claude
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// mcpToolPrefix starts the names Claude Code gives MCP server tools:
+// mcp__<server>__<tool>, e.g. mcp__github__create_issue.
+const mcpToolPrefix = "mcp__"
+
+// ParseMCPTool splits an MCP tool name into its server and tool. It
+// reports false for built-in tools.
+func ParseMCPTool(name string) (server, tool string, ok bool) {
+	rest, ok := strings.CutPrefix(name, mcpToolPrefix)
+	if !ok {
+		return "", "", false
+	}
+	server, tool, _ = strings.Cut(rest, "__")
+	if server == "" {
+		return "", "", false
+	}
+	return server, tool, true
+}
+
+// countMCPCall records a call to an MCP tool; built-in tools are ignored.
+func (s *SessionInfo) countMCPCall(name string) {
+	server, _, ok := ParseMCPTool(name)
+	if !ok {
+		return
+	}
+	if s.MCPCalls == nil {
+		s.MCPCalls = make(map[string]int)
+	}
+	s.MCPCalls[name]++
+	if !slices.Contains(s.MCPServers, server) {
+		s.MCPServers = append(s.MCPServers, server)
+		slices.Sort(s.MCPServers)
+	}
+}
+
+// UsedMCPServer reports whether the session called a tool of server.
+// Server names compare case-insensitively.
+func (s SessionInfo) UsedMCPServer(server string) bool {
+	for _, name := range s.MCPServers {
+		if strings.EqualFold(name, server) {
+			return true
+		}
+	}
+	return false
+}
+
+// MCPServerUsage is one MCP server's use across a set of sessions.
+type MCPServerUsage struct {
+	Server   string         `json:"server"`
+	Sessions int            `json:"sessions"`         // sessions that called it
+	Calls    int            `json:"calls"`            // tool calls made to it
+	Errors   int            `json:"errors,omitempty"` // calls whose result was an error
+	Tools    map[string]int `json:"tools"`            // calls by tool, without the mcp__<server>__ prefix
+	Roles    []string       `json:"roles,omitempty"`  // Gas Town roles that called it, sorted
+	LastUsed time.Time      `json:"last_used"`        // end of the latest session that called it
+}
+
+// SummarizeMCPUsage totals the MCP servers sessions called, most widely
+// used first.
+func SummarizeMCPUsage(sessions []SessionInfo) []MCPServerUsage {
+	byServer := make(map[string]*MCPServerUsage)
+	for _, s := range sessions {
+		for name, n := range s.MCPCalls {
+			server, tool, ok := ParseMCPTool(name)
+			if !ok {
+				continue
+			}
+			u := byServer[server]
+			if u == nil {
+				u = &MCPServerUsage{Server: server, Tools: make(map[string]int)}
+				byServer[server] = u
+			}
+			u.Calls += n
+			u.Tools[tool] += n
+			u.Errors += s.ToolErrors[name]
+		}
+		for _, server := range s.MCPServers {
+			u := byServer[server]
+			if u == nil {
+				continue
+			}
+			u.Sessions++
+			if s.Role != "" && !slices.Contains(u.Roles, s.Role) {
+				u.Roles = append(u.Roles, s.Role)
+			}
+			if s.EndTime.After(u.LastUsed) {
+				u.LastUsed = s.EndTime
+			}
+		}
+	}
+
+	usage := make([]MCPServerUsage, 0, len(byServer))
+	for _, u := range byServer {
+		sort.Strings(u.Roles)
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Sessions != usage[j].Sessions {
+			return usage[i].Sessions > usage[j].Sessions
+		}
+		if usage[i].Calls != usage[j].Calls {
+			return usage[i].Calls > usage[j].Calls
+		}
+		return usage[i].Server < usage[j].Server
+	})
+	return usage
+}
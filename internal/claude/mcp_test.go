@@ -0,0 +1,70 @@
+package claude
+
+import (
+	"testing"
+)
+
+func TestParseMCPTool(t *testing.T) {
+	tests := []struct {
+		name, server, tool string
+		ok                 bool
+	}{
+		{"mcp__github__create_issue", "github", "create_issue", true},
+		{"mcp__claude_ai_Linear__list_issues", "claude_ai_Linear", "list_issues", true},
+		{"mcp__playwright", "playwright", "", true},
+		{"mcp__", "", "", false},
+		{"Bash", "", "", false},
+	}
+	for _, tt := range tests {
+		server, tool, ok := ParseMCPTool(tt.name)
+		if server != tt.server || tool != tt.tool || ok != tt.ok {
+			t.Errorf("ParseMCPTool(%q) = %q, %q, %v; want %q, %q, %v", tt.name, server, tool, ok, tt.server, tt.tool, tt.ok)
+		}
+	}
+}
+
+func TestSessionMCPServers(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(sessionIndexEnv, "off")
+	call := func(id, name string) string {
+		return `{"type":"assistant","timestamp":"2025-12-30T15:43:00Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"` + id + `","name":"` + name + `","input":{}}]}}`
+	}
+	writeSession(t, home, "-home-u-gt-gastown-crew-joe", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-12-30T15:42:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/crew/joe <- deacon • 2025-12-30T15:42 • work"}}`,
+		call("t1", "mcp__github__create_issue"),
+		call("t2", "Bash"),
+		call("t3", "mcp__github__create_issue"),
+		`{"type":"user","timestamp":"2025-12-30T15:44:00Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t3","content":"rate limited","is_error":true}]}}`,
+		call("t4", "mcp__linear__list_issues"),
+	)
+	writeSession(t, home, "-home-u-gt-beads-crew-ann", "bbbb2222.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":"[GAS TOWN] beads/crew/ann <- deacon • 2025-12-31T09:00 • work"}}`,
+		call("t1", "mcp__github__get_pr"),
+	)
+
+	sessions, err := DiscoverSessions(SessionFilter{GasTownOnly: true})
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("DiscoverSessions = %d, %v", len(sessions), err)
+	}
+	joe := sessions[1]
+	if len(joe.MCPServers) != 2 || joe.MCPServers[0] != "github" || joe.MCPServers[1] != "linear" {
+		t.Errorf("MCPServers = %v, want [github linear]", joe.MCPServers)
+	}
+	if joe.MCPCalls["mcp__github__create_issue"] != 2 || joe.ToolCalls != 4 {
+		t.Errorf("MCPCalls = %v, ToolCalls = %d", joe.MCPCalls, joe.ToolCalls)
+	}
+
+	usage := SummarizeMCPUsage(sessions)
+	if len(usage) != 2 || usage[0].Server != "github" || usage[0].Sessions != 2 || usage[0].Calls != 3 || usage[0].Errors != 1 {
+		t.Fatalf("usage = %+v", usage)
+	}
+	if usage[0].Tools["get_pr"] != 1 || len(usage[0].Roles) != 2 || usage[1].Server != "linear" {
+		t.Errorf("usage = %+v", usage)
+	}
+
+	linear, err := DiscoverSessions(SessionFilter{GasTownOnly: true, MCPServer: "Linear"})
+	if err != nil || len(linear) != 1 || linear[0].ID != "aaaa1111" {
+		t.Errorf("MCPServer filter = %+v, %v", linear, err)
+	}
+}
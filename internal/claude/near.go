@@ -0,0 +1,164 @@
+package claude
+
+import (
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileSession is a session that read or wrote a file, as found by
+// SessionsNear.
+type FileSession struct {
+	Session SessionInfo `json:"session"`
+	Path    string      `json:"path"` // path the session's tools used
+	Read    bool        `json:"read,omitempty"`
+	Wrote   bool        `json:"wrote,omitempty"`
+	Count   int         `json:"count"` // tool calls on the file
+	Last    time.Time   `json:"last"`  // most recent of them
+}
+
+// touchedFile is one file a transcript's tools read or wrote, as kept in
+// the session index's files-touched entries.
+type touchedFile struct {
+	Path  string    `json:"path"`
+	Read  bool      `json:"read,omitempty"`
+	Wrote bool      `json:"wrote,omitempty"`
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// filesEntry caches the files a transcript touched. It is valid while the
+// transcript is unchanged.
+type filesEntry struct {
+	ModTime int64         `json:"mtime"` // UnixNano
+	Size    int64         `json:"size"`
+	Files   []touchedFile `json:"files"`
+}
+
+// SessionsNear returns the sessions matching filter that read or wrote a
+// file matching path, most recently touched first. Paths match as in
+// Activity.Touched: an absolute path exactly, a relative one as a suffix,
+// so "internal/cmd/seance.go" finds sessions in every checkout.
+//
+// Each transcript's files are read once and kept in the session index,
+// so later lookups only read transcripts that changed. Like
+// DiscoverSessions, it returns what it found along with ErrIncomplete
+// when discovery timed out.
+func SessionsNear(path string, filter SessionFilter) ([]FileSession, error) {
+	sessions, err := DiscoverSessions(filter)
+	if err != nil && len(sessions) == 0 {
+		return nil, err
+	}
+
+	var idx *sessionIndex
+	if sessionIndexEnabled() {
+		idx = loadSessionIndex(ProjectsDir())
+	}
+	touched := filesTouched(idx, sessions)
+	if idx != nil {
+		idx.save()
+	}
+
+	var matches []FileSession
+	for i, s := range sessions {
+		m := FileSession{Session: s}
+		for _, f := range touched[i] {
+			if !touchMatches(f.Path, path) {
+				continue
+			}
+			if m.Path == "" || f.Last.After(m.Last) {
+				m.Path = f.Path
+			}
+			m.Read = m.Read || f.Read
+			m.Wrote = m.Wrote || f.Wrote
+			m.Count += f.Count
+			if f.Last.After(m.Last) {
+				m.Last = f.Last
+			}
+		}
+		if m.Count > 0 {
+			matches = append(matches, m)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Last.After(matches[j].Last) })
+	return matches, err
+}
+
+// filesTouched returns the files each session touched, in session order,
+// from idx where the transcript is unchanged and by reading it otherwise.
+// Unreadable transcripts touched nothing.
+func filesTouched(idx *sessionIndex, sessions []SessionInfo) [][]touchedFile {
+	touched := make([][]touchedFile, len(sessions))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := workerCount(0, len(sessions)); w > 0; w-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				touched[i] = sessionFilesIndexed(idx, sessions[i].Path)
+			}
+		}()
+	}
+	for i := range sessions {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return touched
+}
+
+// sessionFilesIndexed returns the files the transcript at path touched,
+// from idx when the file is unchanged, reading and caching them otherwise.
+func sessionFilesIndexed(idx *sessionIndex, path string) []touchedFile {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if idx != nil {
+		entry, ok := idx.files(path)
+		if ok && entry.Size == stat.Size() && entry.ModTime == stat.ModTime().UnixNano() {
+			return entry.Files
+		}
+	}
+
+	t, err := ReadTranscript(path)
+	if err != nil {
+		return nil
+	}
+	files := summarizeTouches(t.Activity())
+	if idx != nil {
+		idx.storeFiles(path, filesEntry{ModTime: stat.ModTime().UnixNano(), Size: stat.Size(), Files: files})
+	}
+	return files
+}
+
+// summarizeTouches merges an activity's reads and writes into one entry
+// per file.
+func summarizeTouches(a *Activity) []touchedFile {
+	files := []touchedFile{}
+	byPath := make(map[string]int)
+	add := func(f FileTouch, wrote bool) {
+		i, ok := byPath[f.Path]
+		if !ok {
+			i = len(files)
+			byPath[f.Path] = i
+			files = append(files, touchedFile{Path: f.Path})
+		}
+		t := &files[i]
+		t.Read = t.Read || !wrote
+		t.Wrote = t.Wrote || wrote
+		t.Count += f.Count
+		if f.Last.After(t.Last) {
+			t.Last = f.Last
+		}
+	}
+	for _, f := range a.FilesRead {
+		add(f, false)
+	}
+	for _, f := range a.FilesWritten {
+		add(f, true)
+	}
+	return files
+}
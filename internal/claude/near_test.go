@@ -0,0 +1,60 @@
+package claude
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSessionsNear(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-town-gastown", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-01-01T00:00:00Z","cwd":"/town/gastown","message":{"role":"user","content":"look"}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:01:00Z","cwd":"/town/gastown","message":{"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/town/gastown/internal/cmd/seance.go"}}]}}`,
+	)
+	newer := writeSession(t, home, "-town-gastown", "bbbb2222.jsonl",
+		`{"type":"user","timestamp":"2025-01-02T00:00:00Z","cwd":"/town/other","message":{"role":"user","content":"edit"}}`,
+		`{"type":"assistant","timestamp":"2025-01-02T00:01:00Z","cwd":"/town/other","message":{"content":[{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":"/town/other/internal/cmd/seance.go"}},{"type":"tool_use","id":"t2","name":"Read","input":{"file_path":"/town/other/internal/cmd/seance.go"}}]}}`,
+	)
+	writeSession(t, home, "-town-gastown", "cccc3333.jsonl",
+		`{"type":"assistant","timestamp":"2025-01-03T00:01:00Z","message":{"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/town/gastown/README.md"}}]}}`,
+	)
+
+	matches, err := SessionsNear("internal/cmd/seance.go", SessionFilter{})
+	if err != nil {
+		t.Fatalf("SessionsNear: %v", err)
+	}
+	if len(matches) != 2 || matches[0].Session.ID != "bbbb2222" || matches[1].Session.ID != "aaaa1111" {
+		t.Fatalf("matches = %+v", matches)
+	}
+	if m := matches[0]; !m.Read || !m.Wrote || m.Count != 2 {
+		t.Errorf("newer match = %+v", m)
+	}
+	if m := matches[1]; !m.Read || m.Wrote || m.Path != "/town/gastown/internal/cmd/seance.go" {
+		t.Errorf("older match = %+v", m)
+	}
+
+	exact, err := SessionsNear("/town/gastown/internal/cmd/seance.go", SessionFilter{})
+	if err != nil || len(exact) != 1 || exact[0].Session.ID != "aaaa1111" {
+		t.Fatalf("absolute lookup = %+v, %v", exact, err)
+	}
+
+	idx := loadSessionIndex(ProjectsDir())
+	entries := 0
+	for name := range idx.shardNames() {
+		entries += len(idx.shard(name).Files)
+	}
+	if entries != 3 {
+		t.Errorf("index caches files for %d transcripts, want 3", entries)
+	}
+	if err := os.Remove(newer); err != nil {
+		t.Fatal(err)
+	}
+	if matches, err := SessionsNear("seance.go", SessionFilter{}); err != nil || len(matches) != 1 {
+		t.Fatalf("after removal = %+v, %v", matches, err)
+	}
+	if _, ok := loadSessionIndex(ProjectsDir()).files(newer); ok {
+		t.Error("index kept files for a removed transcript")
+	}
+}
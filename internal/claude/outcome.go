@@ -0,0 +1,183 @@
+package claude
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SessionOutcome is how a session's work ended, judged from its
+// transcript: the closure commands it ran and its final reply. It is not
+// the outcome recorded by hand with gt outcome set.
+type SessionOutcome string
+
+// Session outcomes.
+const (
+	// OutcomeCompleted sessions finished their work: they ran gt done or
+	// closed their bead, or ended on a reply with no work assigned.
+	OutcomeCompleted SessionOutcome = "completed"
+
+	// OutcomeBlocked sessions stopped waiting on something: they
+	// escalated, marked their bead blocked, or said they were blocked.
+	OutcomeBlocked SessionOutcome = "blocked"
+
+	// OutcomeHandoff sessions passed their work on with gt handoff.
+	OutcomeHandoff SessionOutcome = "handoff"
+
+	// OutcomeAbandoned sessions stopped without any of those: mid-turn,
+	// or with their assigned bead still open.
+	OutcomeAbandoned SessionOutcome = "abandoned"
+)
+
+// SessionOutcomes lists the outcomes, for flag help and validation.
+var SessionOutcomes = []SessionOutcome{OutcomeCompleted, OutcomeBlocked, OutcomeHandoff, OutcomeAbandoned}
+
+// ParseSessionOutcome parses an outcome name, e.g. from an --outcome flag.
+func ParseSessionOutcome(s string) (SessionOutcome, error) {
+	for _, o := range SessionOutcomes {
+		if strings.EqualFold(s, string(o)) {
+			return o, nil
+		}
+	}
+	names := make([]string, len(SessionOutcomes))
+	for i, o := range SessionOutcomes {
+		names[i] = string(o)
+	}
+	return "", fmt.Errorf("unknown outcome %q (want one of %s)", s, strings.Join(names, ", "))
+}
+
+// Closure commands that mark how a session's work ended.
+var (
+	doneCommand     = regexp.MustCompile(`(?:^|[\s;&|(])gt\s+done\b`)
+	closeCommand    = regexp.MustCompile(`(?:^|[\s;&|(])bd\s+close\b`)
+	handoffCommand  = regexp.MustCompile(`(?:^|[\s;&|(])gt\s+handoff\b`)
+	escalateCommand = regexp.MustCompile(`(?:^|[\s;&|(])gt\s+escalate\b`)
+	blockedCommand  = regexp.MustCompile(`(?:^|[\s;&|(])bd\s+update\b.*--status[= ]blocked\b`)
+
+	// quotedText is a quoted shell string, dropped before matching so
+	// "echo 'then run gt done'" isn't taken for the command.
+	quotedText = regexp.MustCompile(`'[^']*'|"(?:[^"\\]|\\.)*"`)
+)
+
+// blockedPhrases and handoffPhrases mark a final reply that stops short of
+// finishing. They are matched against the lowercased reply.
+var (
+	blockedPhrases = []string{
+		"i'm blocked", "i am blocked", "i'm stuck", "i am stuck", "blocked on ", "blocked by ",
+		"can't proceed", "cannot proceed", "unable to proceed", "need your input", "waiting for your",
+	}
+	handoffPhrases = []string{"handing off", "hand this off", "handed off"}
+)
+
+// commandOutcome returns the outcome a shell command marks, or "". When
+// the session was assigned a bead, closing some other bead doesn't count.
+func commandOutcome(cmd, bead string) SessionOutcome {
+	full := cmd
+	cmd = quotedText.ReplaceAllString(cmd, "''")
+	switch {
+	case doneCommand.MatchString(cmd):
+		return OutcomeCompleted
+	case handoffCommand.MatchString(cmd):
+		return OutcomeHandoff
+	case escalateCommand.MatchString(cmd), blockedCommand.MatchString(cmd):
+		return OutcomeBlocked
+	}
+	if closeCommand.MatchString(cmd) && (bead == "" || strings.Contains(full, bead)) {
+		return OutcomeCompleted
+	}
+	return ""
+}
+
+// replyOutcome returns the outcome a reply's text states, or "".
+func replyOutcome(text string) SessionOutcome {
+	lower := strings.ToLower(text)
+	for _, p := range blockedPhrases {
+		if strings.Contains(lower, p) {
+			return OutcomeBlocked
+		}
+	}
+	for _, p := range handoffPhrases {
+		if strings.Contains(lower, p) {
+			return OutcomeHandoff
+		}
+	}
+	return ""
+}
+
+// outcomeMarks tracks, while a transcript is parsed, the latest closure
+// command and what the latest reply said.
+type outcomeMarks struct {
+	command     SessionOutcome
+	commandLine int
+	reply       SessionOutcome
+	replyLine   int
+}
+
+// noteAssistant records the closure commands in an assistant message and
+// what its text says; a message without text clears the latter, so only
+// a final reply counts.
+func (m *outcomeMarks) noteAssistant(line []byte, raw json.RawMessage, lineNum int, bead string) {
+	if bytes.Contains(line, []byte(`"tool_use"`)) && (bytes.Contains(line, []byte("gt ")) || bytes.Contains(line, []byte("bd "))) {
+		for _, cmd := range bashCommands(raw) {
+			if o := commandOutcome(cmd, bead); o != "" {
+				m.command, m.commandLine = o, lineNum
+			}
+		}
+	}
+	m.reply, m.replyLine = "", lineNum
+	if bytes.Contains(line, []byte(`"text"`)) {
+		m.reply = replyOutcome(messageText(raw))
+	}
+}
+
+// outcome is the transcript's own verdict: a final reply saying it is
+// blocked or handing off when that came after the last closure command,
+// or else that command's.
+func (m outcomeMarks) outcome() SessionOutcome {
+	if m.reply != "" && m.replyLine > m.commandLine {
+		return m.reply
+	}
+	return m.command
+}
+
+// bashCommands returns the shell commands an assistant message runs.
+func bashCommands(raw json.RawMessage) []string {
+	var msg entryMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &msg) != nil {
+		return nil
+	}
+	var blocks []struct {
+		Type  string `json:"type"`
+		Name  string `json:"name"`
+		Input struct {
+			Command string `json:"command"`
+		} `json:"input"`
+	}
+	if json.Unmarshal(msg.Content, &blocks) != nil {
+		return nil
+	}
+	var cmds []string
+	for _, b := range blocks {
+		if b.Type == "tool_use" && b.Name == "Bash" && b.Input.Command != "" {
+			cmds = append(cmds, b.Input.Command)
+		}
+	}
+	return cmds
+}
+
+// settleOutcome judges the outcome of a session whose transcript gave no
+// verdict, once it has stopped: abandoned if it ended mid-turn or left
+// its assigned bead open, completed otherwise. Sessions still active, or
+// waiting mid-turn with their process alive, are left undecided.
+func (s *SessionInfo) settleOutcome() {
+	if s.Outcome != "" || s.State == StateActive || (s.State == StateIdle && s.Unfinished) {
+		return
+	}
+	if s.Unfinished || s.Bead != "" {
+		s.Outcome = OutcomeAbandoned
+		return
+	}
+	s.Outcome = OutcomeCompleted
+}
@@ -0,0 +1,91 @@
+package claude
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommandOutcome(t *testing.T) {
+	tests := []struct {
+		cmd, bead string
+		want      SessionOutcome
+	}{
+		{"gt done", "", OutcomeCompleted},
+		{"cd /rig && gt done --exit", "gt-abc12", OutcomeCompleted},
+		{"bd close gt-abc12 --reason fixed", "gt-abc12", OutcomeCompleted},
+		{"bd close gt-xyz99", "gt-abc12", ""},
+		{"bd close gt-xyz99", "", OutcomeCompleted},
+		{`bd close "gt-abc12"`, "gt-abc12", OutcomeCompleted},
+		{"gt handoff -m 'context full'", "", OutcomeHandoff},
+		{"gt escalate 'tests need credentials'", "", OutcomeBlocked},
+		{"bd update gt-abc12 --status=blocked", "gt-abc12", OutcomeBlocked},
+		{"bd update gt-abc12 --status in_progress", "gt-abc12", ""},
+		{"echo 'run gt done when finished'", "", ""},
+		{"mygt done", "", ""},
+	}
+	for _, tt := range tests {
+		if got := commandOutcome(tt.cmd, tt.bead); got != tt.want {
+			t.Errorf("commandOutcome(%q, %q) = %q, want %q", tt.cmd, tt.bead, got, tt.want)
+		}
+	}
+}
+
+func TestSessionOutcome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(sessionIndexEnv, "off")
+
+	beacon := `{"type":"user","timestamp":"2025-12-30T15:42:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/polecats/toast <- witness • 2025-12-30T15:42 • assigned:gt-abc12"}}`
+	bash := func(cmd string) string {
+		return `{"type":"assistant","timestamp":"2025-12-30T15:50:00Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"` + cmd + `"}}]}}`
+	}
+	result := `{"type":"user","timestamp":"2025-12-30T15:50:01Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`
+	reply := func(text string) string {
+		return `{"type":"assistant","timestamp":"2025-12-30T15:51:00Z","message":{"role":"assistant","content":[{"type":"text","text":"` + text + `"}]}}`
+	}
+
+	tests := []struct {
+		name  string
+		lines []string
+		want  SessionOutcome
+	}{
+		{"done", []string{beacon, bash("gt done"), result, reply("Submitted to the merge queue.")}, OutcomeCompleted},
+		{"closed", []string{beacon, bash("bd close gt-abc12"), result, reply("Closed.")}, OutcomeCompleted},
+		{"handoff", []string{beacon, bash("gt handoff"), result}, OutcomeHandoff},
+		{"escalated", []string{beacon, bash("gt escalate 'need creds'"), result, reply("Escalated; waiting.")}, OutcomeBlocked},
+		{"says blocked", []string{beacon, reply("I'm blocked on the missing API key.")}, OutcomeBlocked},
+		{"blocked then done", []string{beacon, reply("I'm stuck, retrying."), bash("gt done"), result, reply("Done.")}, OutcomeCompleted},
+		{"stuck mid-session", []string{beacon, reply("I'm stuck, trying another way."), bash("go test ./..."), result, reply("Tests pass now.")}, OutcomeAbandoned},
+		{"bead left open", []string{beacon, reply("Made some progress.")}, OutcomeAbandoned},
+		{"mid-turn", []string{beacon, bash("go test ./...")}, OutcomeAbandoned},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := strings.Repeat(string(rune('a'+i)), 8)
+			path := writeSession(t, home, "-home-u-gastown", id+".jsonl", tt.lines...)
+			info, err := parseSession(path, "-home-u-gastown")
+			if err != nil {
+				t.Fatal(err)
+			}
+			info.State = ClassifySession(info, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), false)
+			info.settleOutcome()
+			if info.Outcome != tt.want {
+				t.Errorf("outcome = %q, want %q", info.Outcome, tt.want)
+			}
+		})
+	}
+
+	// Undecided while the session runs; completed without assigned work
+	s := &SessionInfo{State: StateActive}
+	if s.settleOutcome(); s.Outcome != "" {
+		t.Errorf("active session outcome = %q, want none", s.Outcome)
+	}
+	s = &SessionInfo{State: StateIdle}
+	if s.settleOutcome(); s.Outcome != OutcomeCompleted {
+		t.Errorf("idle unassigned session outcome = %q, want completed", s.Outcome)
+	}
+	if _, err := ParseSessionOutcome("finished"); err == nil {
+		t.Error("ParseSessionOutcome(finished): want error")
+	}
+}
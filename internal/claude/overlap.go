@@ -0,0 +1,154 @@
+package claude
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OverlapOptions tune FindOverlaps.
+type OverlapOptions struct {
+	// Now and Window bound which sessions count as active: those with
+	// activity in the Window before Now. Defaults: time.Now() and 24h.
+	Now    time.Time
+	Window time.Duration
+
+	// MinSharedFiles flags a pair that edited at least this many of the
+	// same files. Default 2.
+	MinSharedFiles int
+
+	// MinTopicSimilarity flags a pair whose topics share at least this
+	// fraction of their words (Jaccard similarity, 0-1). Default 0.5.
+	MinTopicSimilarity float64
+}
+
+func (o OverlapOptions) withDefaults() OverlapOptions {
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+	if o.Window <= 0 {
+		o.Window = 24 * time.Hour
+	}
+	if o.MinSharedFiles <= 0 {
+		o.MinSharedFiles = 2
+	}
+	if o.MinTopicSimilarity <= 0 {
+		o.MinTopicSimilarity = 0.5
+	}
+	return o
+}
+
+// WorkOverlap is a pair of concurrently active sessions in different rigs
+// that look like they are doing the same work.
+type WorkOverlap struct {
+	A SessionInfo `json:"a"`
+	B SessionInfo `json:"b"`
+
+	// SharedFiles are the files both edited, relative to each session's
+	// working directory, so the same library in two repos compares equal.
+	SharedFiles []string `json:"shared_files,omitempty"`
+
+	// TopicSimilarity is how alike their summaries and topics are (0-1).
+	TopicSimilarity float64 `json:"topic_similarity"`
+}
+
+// overlapIgnoredFiles are edited by so much unrelated work that sharing
+// them says nothing.
+var overlapIgnoredFiles = map[string]bool{
+	"go.mod": true, "go.sum": true, "package.json": true, "package-lock.json": true,
+	"yarn.lock": true, "pnpm-lock.yaml": true, "Cargo.lock": true,
+	"README.md": true, "CHANGELOG.md": true, ".gitignore": true,
+}
+
+// FindOverlaps flags pairs of sessions from different rigs that were
+// active at the same time and either edited the same files or have
+// similar topics, the sign of two rigs independently doing the same job.
+// Sessions without a rig are skipped. Most similar pairs come first.
+func FindOverlaps(sessions []SessionInfo, opts OverlapOptions) []WorkOverlap {
+	opts = opts.withDefaults()
+	cutoff := opts.Now.Add(-opts.Window)
+
+	type candidate struct {
+		session SessionInfo
+		files   map[string]bool
+		words   map[string]bool
+	}
+	var active []candidate
+	for _, s := range sessions {
+		if s.Rig == "" || s.EndTime.Before(cutoff) {
+			continue
+		}
+		c := candidate{session: s, files: make(map[string]bool), words: topicWords(s.Summary + " " + s.Topic)}
+		if edits, err := ReadFileEdits(s.Path, cutoff); err == nil {
+			for _, e := range edits {
+				if !overlapIgnoredFiles[path.Base(e.RelPath)] {
+					c.files[e.RelPath] = true
+				}
+			}
+		}
+		active = append(active, c)
+	}
+
+	var out []WorkOverlap
+	for i := range active {
+		for j := i + 1; j < len(active); j++ {
+			a, b := active[i], active[j]
+			if strings.EqualFold(a.session.Rig, b.session.Rig) || !concurrent(a.session, b.session) {
+				continue
+			}
+			var shared []string
+			for f := range a.files {
+				if b.files[f] {
+					shared = append(shared, f)
+				}
+			}
+			similarity := jaccard(a.words, b.words)
+			if len(shared) < opts.MinSharedFiles && similarity < opts.MinTopicSimilarity {
+				continue
+			}
+			sort.Strings(shared)
+			out = append(out, WorkOverlap{A: a.session, B: b.session, SharedFiles: shared, TopicSimilarity: similarity})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if len(out[i].SharedFiles) != len(out[j].SharedFiles) {
+			return len(out[i].SharedFiles) > len(out[j].SharedFiles)
+		}
+		return out[i].TopicSimilarity > out[j].TopicSimilarity
+	})
+	return out
+}
+
+// concurrent reports whether two sessions were running at the same time.
+func concurrent(a, b SessionInfo) bool {
+	return !a.EndTime.Before(b.StartTime) && !b.EndTime.Before(a.StartTime)
+}
+
+// topicWords returns the distinct words of text worth comparing: three
+// or more letters or digits, lowercased.
+func topicWords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}) {
+		if len(w) > 2 {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+// jaccard returns the Jaccard similarity of two word sets.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
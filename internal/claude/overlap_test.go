@@ -0,0 +1,66 @@
+package claude
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFindOverlaps(t *testing.T) {
+	home := t.TempDir()
+	edit := func(cwd, file string) string {
+		return fmt.Sprintf(`{"type":"assistant","timestamp":"2025-01-01T10:30:00Z","cwd":%q,"message":{"content":[{"type":"tool_use","name":"Edit","input":{"file_path":%q}}]}}`, cwd, cwd+"/"+file)
+	}
+	session := func(name, rig, cwd, summary string, start, end time.Time, files ...string) SessionInfo {
+		var lines []string
+		for _, f := range files {
+			lines = append(lines, edit(cwd, f))
+		}
+		if len(lines) == 0 {
+			lines = []string{`{"type":"user","message":{"role":"user","content":"hi"}}`}
+		}
+		path := writeSession(t, home, "-p-"+name, name+".jsonl", lines...)
+		return SessionInfo{ID: name, Path: path, Rig: rig, Summary: summary, StartTime: start, EndTime: end}
+	}
+	at := func(h int) time.Time { return time.Date(2025, 1, 1, h, 0, 0, 0, time.UTC) }
+
+	sessions := []SessionInfo{
+		session("a", "gastown", "/t/gastown/crew/joe", "Rewrite the retry library", at(9), at(12), "lib/retry/retry.go", "lib/retry/backoff.go", "go.mod"),
+		session("b", "beads", "/t/beads/crew/max", "Add jitter to backoff", at(10), at(13), "lib/retry/retry.go", "lib/retry/backoff.go", "go.mod"),
+		// Same rig as a: not flagged
+		session("c", "gastown", "/t/gastown/crew/sam", "Rewrite the retry library", at(9), at(12), "lib/retry/retry.go", "lib/retry/backoff.go"),
+		// Similar topic in another rig, no shared files
+		session("d", "mayor-rig", "/t/mayor-rig/crew/x", "Rewrite retry library", at(11), at(12)),
+		// Shares files with a, but ended before a started
+		session("e", "wyvern", "/t/wyvern/crew/y", "unrelated", at(6), at(8), "lib/retry/retry.go", "lib/retry/backoff.go"),
+		// Shares only ignorable files
+		session("f", "wyvern", "/t/wyvern/crew/z", "unrelated", at(9), at(12), "go.mod", "README.md"),
+		// No rig
+		session("g", "", "/t/mayor", "Rewrite the retry library", at(9), at(12), "lib/retry/retry.go", "lib/retry/backoff.go"),
+	}
+
+	got := FindOverlaps(sessions, OverlapOptions{Now: at(14), Window: 12 * time.Hour})
+	pairs := make(map[string]WorkOverlap)
+	for _, o := range got {
+		pairs[o.A.ID+o.B.ID] = o
+	}
+
+	ab, ok := pairs["ab"]
+	if !ok || len(ab.SharedFiles) != 2 || ab.SharedFiles[0] != "lib/retry/backoff.go" {
+		t.Errorf("a/b overlap = %+v, want two shared retry files", ab)
+	}
+	if _, ok := pairs["bc"]; !ok {
+		t.Error("b/c share files across rigs and should be flagged")
+	}
+	if ad, ok := pairs["ad"]; !ok || ad.TopicSimilarity < 0.5 {
+		t.Errorf("a/d overlap = %+v, want a topic match", ad)
+	}
+	for _, key := range []string{"ac", "ae", "af", "ag", "bg"} {
+		if o, ok := pairs[key]; ok {
+			t.Errorf("unexpected overlap %s: %+v", key, o)
+		}
+	}
+	if len(got) > 0 && len(got[0].SharedFiles) < 2 {
+		t.Errorf("file overlaps should sort first: %+v", got[0])
+	}
+}
@@ -0,0 +1,90 @@
+package claude
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// SessionPage is one page of a session listing.
+type SessionPage struct {
+	Sessions []SessionInfo `json:"sessions"`
+
+	// NextCursor fetches the next page when passed as
+	// SessionFilter.Cursor. Empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// DiscoverSessionsPage returns the page of up to filter.Limit sessions
+// after filter.Cursor, in DiscoverSessions order. Sessions with the same
+// sort key are ordered by start time, then ID and path, so pages neither
+// skip nor repeat sessions as new ones are written: in the default order,
+// a new session sorts before the first page. A cursor only continues the
+// sort order it came from. With no Limit, the one page holds every
+// session.
+func DiscoverSessionsPage(filter SessionFilter) (*SessionPage, error) {
+	order, err := filter.order()
+	if err != nil {
+		return nil, err
+	}
+	limit := filter.Limit
+	if limit > 0 {
+		filter.Limit = limit + 1 // one more, to learn whether there's another page
+	}
+	sessions, err := DiscoverSessions(filter)
+	if err != nil {
+		return nil, err
+	}
+	page := &SessionPage{Sessions: sessions}
+	if limit > 0 && len(sessions) > limit {
+		page.Sessions = sessions[:limit]
+		page.NextCursor = encodeCursor(page.Sessions[limit-1], order)
+	}
+	return page, nil
+}
+
+// sessionCursor is the position after which a page starts, and the
+// order it is a position in.
+type sessionCursor struct {
+	By  SessionSort `json:"b"`
+	Asc bool        `json:"a,omitempty"`
+	Pos sessionPos  `json:"p"`
+}
+
+// encodeCursor returns the opaque cursor for the position just after s
+// in o.
+func encodeCursor(s SessionInfo, o sessionOrder) string {
+	data, _ := json.Marshal(sessionCursor{By: o.by, Asc: o.asc, Pos: o.position(s)})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor from encodeCursor, which must be for
+// order o.
+func decodeCursor(cursor string, o sessionOrder) (sessionCursor, error) {
+	var c sessionCursor
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil || c.Pos.ID == "" {
+		return c, fmt.Errorf("invalid session cursor %q", cursor)
+	}
+	if c.By != o.by || c.Asc != o.asc {
+		return c, fmt.Errorf("session cursor %q is for a different sort order", cursor)
+	}
+	return c, nil
+}
+
+// sessionsAfterCursor returns the sessions, sorted by sortSessions into
+// positions, that come after cursor.
+func sessionsAfterCursor(sessions []SessionInfo, positions []sessionPos, o sessionOrder, cursor string) ([]SessionInfo, error) {
+	c, err := decodeCursor(cursor, o)
+	if err != nil {
+		return nil, err
+	}
+	i := sort.Search(len(sessions), func(i int) bool {
+		return o.before(c.Pos, positions[i])
+	})
+	return sessions[i:], nil
+}
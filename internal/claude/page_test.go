@@ -0,0 +1,56 @@
+package claude
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiscoverSessionsPage(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	user := func(ts string) string {
+		return fmt.Sprintf(`{"type":"user","timestamp":"%s","message":{"role":"user","content":"hi"}}`, ts)
+	}
+	// s2 and s3 start together, so only the ID orders them
+	writeSession(t, home, "-home-u-proj", "s1.jsonl", user("2025-03-01T12:00:00Z"))
+	writeSession(t, home, "-home-u-proj", "s3.jsonl", user("2025-03-02T12:00:00Z"))
+	writeSession(t, home, "-home-u-proj", "s2.jsonl", user("2025-03-02T12:00:00Z"))
+	writeSession(t, home, "-home-u-other", "s4.jsonl", user("2025-03-03T12:00:00Z"))
+	writeSession(t, home, "-home-u-other", "s5.jsonl", user("2025-03-04T12:00:00Z"))
+
+	var ids []string
+	filter := SessionFilter{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not end")
+		}
+		page, err := DiscoverSessionsPage(filter)
+		if err != nil {
+			t.Fatalf("DiscoverSessionsPage: %v", err)
+		}
+		for _, s := range page.Sessions {
+			ids = append(ids, s.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		if pages == 0 {
+			// A session written between pages sorts before the first
+			writeSession(t, home, "-home-u-proj", "s6.jsonl", user("2025-03-05T12:00:00Z"))
+		}
+		filter.Cursor = page.NextCursor
+	}
+	if got := strings.Join(ids, " "); got != "s5 s4 s2 s3 s1" {
+		t.Errorf("paged IDs = %s, want s5 s4 s2 s3 s1", got)
+	}
+
+	if _, err := DiscoverSessions(SessionFilter{Cursor: "not-a-cursor"}); err == nil {
+		t.Error("DiscoverSessions accepted an invalid cursor")
+	}
+	page, err := DiscoverSessionsPage(SessionFilter{})
+	if err != nil || len(page.Sessions) != 6 || page.NextCursor != "" {
+		t.Errorf("unlimited page = %d sessions, cursor %q, %v", len(page.Sessions), page.NextCursor, err)
+	}
+}
@@ -0,0 +1,129 @@
+package claude
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PermissionFinding is a settings file entry that lets Claude Code act
+// without asking.
+type PermissionFinding struct {
+	File    string `json:"file"`
+	Setting string `json:"setting"` // e.g. "permissions.defaultMode"
+	Value   string `json:"value"`
+	Reason  string `json:"reason"`
+}
+
+// dangerousCommands are Bash command prefixes that should never be
+// approved ahead of time.
+var dangerousCommands = []struct{ prefix, reason string }{
+	{"rm", "deletes files"},
+	{"sudo", "runs commands as root"},
+	{"chmod", "changes file permissions"},
+	{"chown", "changes file ownership"},
+	{"dd", "writes raw devices"},
+	{"mkfs", "formats filesystems"},
+	{"curl", "fetches and sends data over the network"},
+	{"wget", "fetches data over the network"},
+	{"ssh", "runs commands on other hosts"},
+	{"scp", "copies files to other hosts"},
+	{"git push", "publishes commits"},
+	{"git reset", "discards commits and changes"},
+	{"git clean", "deletes untracked files"},
+}
+
+// DangerousAllowRule returns why a permissions.allow rule is dangerous to
+// approve ahead of time, or "" if it isn't.
+func DangerousAllowRule(rule string) string {
+	rule = strings.TrimSpace(rule)
+	switch rule {
+	case "Bash", "Bash(*)", "Bash(:*)":
+		return "runs any shell command without asking"
+	case "WebFetch":
+		return "fetches any URL without asking"
+	}
+	inner, ok := strings.CutPrefix(rule, "Bash(")
+	if !ok {
+		return ""
+	}
+	command := strings.TrimSpace(strings.TrimSuffix(inner, ")"))
+	command = strings.TrimSuffix(strings.TrimSuffix(command, "*"), ":")
+	for _, d := range dangerousCommands {
+		if command == d.prefix || strings.HasPrefix(command, d.prefix+" ") || strings.HasPrefix(command, d.prefix+":") {
+			return d.reason + " without asking"
+		}
+	}
+	return ""
+}
+
+// permissionSettings is the subset of a settings file audited.
+type permissionSettings struct {
+	Permissions struct {
+		DefaultMode string   `json:"defaultMode"`
+		Allow       []string `json:"allow"`
+	} `json:"permissions"`
+}
+
+// AuditPermissions reports the entries in dir's project settings files
+// (.claude/settings.json and .claude/settings.local.json) that let Claude
+// Code act without asking: the bypassPermissions mode and dangerous
+// permissions.allow rules. Missing or unreadable files are skipped.
+func AuditPermissions(dir string) []PermissionFinding {
+	var findings []PermissionFinding
+	for _, path := range ProjectSettingsFiles(dir) {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: well-known settings paths
+		if err != nil {
+			continue
+		}
+		var s permissionSettings
+		if json.Unmarshal(data, &s) != nil {
+			continue
+		}
+		if s.Permissions.DefaultMode == PermissionBypass {
+			findings = append(findings, PermissionFinding{
+				File:    path,
+				Setting: "permissions.defaultMode",
+				Value:   PermissionBypass,
+				Reason:  "skips every permission prompt",
+			})
+		}
+		for _, rule := range s.Permissions.Allow {
+			if reason := DangerousAllowRule(rule); reason != "" {
+				findings = append(findings, PermissionFinding{
+					File:    path,
+					Setting: "permissions.allow",
+					Value:   rule,
+					Reason:  reason,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// ProjectSettingsFiles returns the paths of dir's project settings files
+// that exist, most specific first.
+func ProjectSettingsFiles(dir string) []string {
+	var files []string
+	for _, name := range []string{"settings.local.json", "settings.json"} {
+		path := filepath.Join(dir, ".claude", name)
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// KnownProjects returns the project directories Claude Code has been run
+// in, from ~/.claude.json, sorted.
+func KnownProjects() []string {
+	projects := make([]string, 0)
+	for dir := range loadUserConfig().Projects {
+		projects = append(projects, dir)
+	}
+	sort.Strings(projects)
+	return projects
+}
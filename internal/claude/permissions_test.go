@@ -0,0 +1,59 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDangerousAllowRule(t *testing.T) {
+	for rule, dangerous := range map[string]bool{
+		"Bash":                     true,
+		"Bash(*)":                  true,
+		"Bash(rm:*)":               true,
+		"Bash(rm -rf build)":       true,
+		"Bash(git push:*)":         true,
+		"Bash(sudo apt install:*)": true,
+		"WebFetch":                 true,
+		"Bash(go test:*)":          false,
+		"Bash(git status)":         false,
+		"Bash(rmdir:*)":            false,
+		"WebFetch(domain:go.dev)":  false,
+		"Edit":                     false,
+		"Read(**)":                 false,
+	} {
+		if got := DangerousAllowRule(rule) != ""; got != dangerous {
+			t.Errorf("DangerousAllowRule(%q) dangerous = %v, want %v", rule, got, dangerous)
+		}
+	}
+}
+
+func TestAuditPermissions(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".claude"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, ".claude", name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("settings.json", `{"permissions":{"defaultMode":"acceptEdits","allow":["Bash(go test:*)","Bash(git push:*)"]}}`)
+	write("settings.local.json", `{"permissions":{"defaultMode":"bypassPermissions"}}`)
+
+	findings := AuditPermissions(dir)
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings: %+v", len(findings), findings)
+	}
+	if f := findings[0]; f.Setting != "permissions.defaultMode" || f.Value != PermissionBypass ||
+		filepath.Base(f.File) != "settings.local.json" {
+		t.Errorf("first finding = %+v", f)
+	}
+	if f := findings[1]; f.Setting != "permissions.allow" || f.Value != "Bash(git push:*)" {
+		t.Errorf("second finding = %+v", f)
+	}
+
+	if got := AuditPermissions(t.TempDir()); len(got) != 0 {
+		t.Errorf("no settings files: got %+v", got)
+	}
+}
@@ -0,0 +1,238 @@
+package claude
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Plan item states, as Claude Code's TodoWrite and TaskUpdate tools
+// write them.
+const (
+	PlanPending    = "pending"
+	PlanInProgress = "in_progress"
+	PlanCompleted  = "completed"
+)
+
+// PlanItem is one entry of a session's task list.
+type PlanItem struct {
+	ID         string    `json:"id,omitempty"`
+	Content    string    `json:"content"`
+	ActiveForm string    `json:"active_form,omitempty"` // present-tense label shown while in progress
+	State      string    `json:"state"`                 // pending, in_progress, or completed
+	Updated    time.Time `json:"updated"`               // when the item last changed
+}
+
+// Plan is the task list a session kept with TodoWrite, or with the
+// TaskCreate and TaskUpdate tools newer Claude Code versions use instead:
+// what the agent set out to do, and how far it got.
+type Plan struct {
+	Items      []PlanItem `json:"items"`
+	Completed  int        `json:"completed"`
+	InProgress int        `json:"in_progress"`
+	Pending    int        `json:"pending"`
+	Completion float64    `json:"completion"` // completed items / all items, 0 to 1
+	Updates    int        `json:"updates"`    // tool calls that changed the plan
+	Updated    time.Time  `json:"updated"`    // when the plan last changed
+}
+
+// Done reports whether every item of a non-empty plan was completed.
+func (p *Plan) Done() bool {
+	return p != nil && len(p.Items) > 0 && p.Completed == len(p.Items)
+}
+
+// Remaining returns the items not yet completed, in plan order.
+func (p *Plan) Remaining() []PlanItem {
+	if p == nil {
+		return nil
+	}
+	var items []PlanItem
+	for _, it := range p.Items {
+		if it.State != PlanCompleted {
+			items = append(items, it)
+		}
+	}
+	return items
+}
+
+// taskCreatedID finds the ID TaskCreate reports for a new task, e.g.
+// "Task #3 created successfully".
+var taskCreatedID = regexp.MustCompile(`#(\d+)`)
+
+// todoInput is the input of a TodoWrite call: the whole list, replacing
+// the previous one.
+type todoInput struct {
+	Todos []struct {
+		ID              string `json:"id"`
+		Content         string `json:"content"`
+		Status          string `json:"status"`
+		ActiveForm      string `json:"activeForm"`
+		ActiveFormSnake string `json:"active_form"`
+	} `json:"todos"`
+}
+
+// taskInput is the input of a TaskCreate or TaskUpdate call.
+type taskInput struct {
+	TaskID      string `json:"taskId"`
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	ActiveForm  string `json:"activeForm"`
+}
+
+// ReadPlan returns the plan kept in the transcript at path, or nil if the
+// session never wrote one.
+func ReadPlan(path string) (*Plan, error) {
+	t, err := ReadTranscript(path)
+	if err != nil {
+		return nil, err
+	}
+	return t.Plan(), nil
+}
+
+// Plan replays the transcript's TodoWrite, TaskCreate and TaskUpdate calls
+// into the session's final task list, or returns nil if it never kept one.
+// Subagents' lists and calls that failed are ignored.
+func (t *Transcript) Plan() *Plan {
+	var (
+		plan   *Plan
+		nextID = 1
+	)
+	for _, m := range t.Messages {
+		if m.IsSidechain {
+			continue
+		}
+		for _, use := range m.ToolUses {
+			result := t.ToolResult(use.ID)
+			if result != nil && result.IsError {
+				continue
+			}
+			var changed bool
+			switch use.Name {
+			case "TodoWrite":
+				var in todoInput
+				if json.Unmarshal(use.Input, &in) != nil {
+					continue
+				}
+				if plan == nil {
+					plan = &Plan{}
+				}
+				prev := plan.Items
+				plan.Items = make([]PlanItem, 0, len(in.Todos))
+				for _, todo := range in.Todos {
+					it := PlanItem{
+						ID:         todo.ID,
+						Content:    todo.Content,
+						ActiveForm: firstNonEmpty(todo.ActiveForm, todo.ActiveFormSnake),
+						State:      planState(todo.Status),
+						Updated:    m.Timestamp,
+					}
+					// Keep when an unchanged item last changed
+					for _, p := range prev {
+						if p.Content == it.Content && p.State == it.State {
+							it.Updated = p.Updated
+							break
+						}
+					}
+					plan.Items = append(plan.Items, it)
+				}
+				changed = true
+
+			case "TaskCreate":
+				var in taskInput
+				if json.Unmarshal(use.Input, &in) != nil || in.Subject == "" {
+					continue
+				}
+				id := strconv.Itoa(nextID)
+				if result != nil {
+					if sub := taskCreatedID.FindStringSubmatch(result.Content); sub != nil {
+						id = sub[1]
+					}
+				}
+				if n, err := strconv.Atoi(id); err == nil && n >= nextID {
+					nextID = n + 1
+				}
+				if plan == nil {
+					plan = &Plan{}
+				}
+				plan.Items = append(plan.Items, PlanItem{
+					ID:         id,
+					Content:    in.Subject,
+					ActiveForm: in.ActiveForm,
+					State:      PlanPending,
+					Updated:    m.Timestamp,
+				})
+				changed = true
+
+			case "TaskUpdate":
+				var in taskInput
+				if json.Unmarshal(use.Input, &in) != nil || plan == nil {
+					continue
+				}
+				for i := range plan.Items {
+					it := &plan.Items[i]
+					if it.ID != in.TaskID {
+						continue
+					}
+					if in.Status == "deleted" {
+						plan.Items = append(plan.Items[:i], plan.Items[i+1:]...)
+					} else {
+						if in.Status != "" {
+							it.State = planState(in.Status)
+						}
+						if in.Subject != "" {
+							it.Content = in.Subject
+						}
+						if in.ActiveForm != "" {
+							it.ActiveForm = in.ActiveForm
+						}
+						it.Updated = m.Timestamp
+					}
+					changed = true
+					break
+				}
+			}
+			if changed {
+				plan.Updates++
+				plan.Updated = m.Timestamp
+			}
+		}
+	}
+	if plan != nil {
+		plan.count()
+	}
+	return plan
+}
+
+// count fills in the plan's totals.
+func (p *Plan) count() {
+	p.Completed, p.InProgress, p.Pending = 0, 0, 0
+	for _, it := range p.Items {
+		switch it.State {
+		case PlanCompleted:
+			p.Completed++
+		case PlanInProgress:
+			p.InProgress++
+		default:
+			p.Pending++
+		}
+	}
+	p.Completion = 0
+	if len(p.Items) > 0 {
+		p.Completion = float64(p.Completed) / float64(len(p.Items))
+	}
+}
+
+// planState normalizes a tool's status; anything unrecognized is pending.
+func planState(status string) string {
+	switch status {
+	case PlanInProgress, PlanCompleted:
+		return status
+	case "in-progress", "inProgress", "active":
+		return PlanInProgress
+	case "done", "complete":
+		return PlanCompleted
+	}
+	return PlanPending
+}
@@ -0,0 +1,86 @@
+package claude
+
+import (
+	"testing"
+)
+
+func TestTranscriptPlanTodoWrite(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(sessionIndexEnv, "off")
+	path := writeSession(t, home, "-home-u-proj", "abcd1234.jsonl",
+		`{"type":"assistant","timestamp":"2025-01-01T00:00:00Z","message":{"content":[{"type":"tool_use","id":"t1","name":"TodoWrite","input":{"todos":[{"content":"Read the code","status":"in_progress","activeForm":"Reading the code"},{"content":"Fix the bug","status":"pending"},{"content":"Run tests","status":"pending"}]}}]}}`,
+		`{"type":"user","timestamp":"2025-01-01T00:00:01Z","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"Todos have been modified successfully"}]}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:05:00Z","isSidechain":true,"message":{"content":[{"type":"tool_use","id":"s1","name":"TodoWrite","input":{"todos":[{"content":"subagent item","status":"pending"}]}}]}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:10:00Z","message":{"content":[{"type":"tool_use","id":"t2","name":"TodoWrite","input":{"todos":[{"content":"Read the code","status":"completed"},{"content":"Fix the bug","status":"in_progress"},{"content":"Run tests","status":"pending"}]}}]}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:20:00Z","message":{"content":[{"type":"tool_use","id":"t3","name":"TodoWrite","input":{"todos":[{"content":"bogus","status":"completed"}]}}]}}`,
+		`{"type":"user","timestamp":"2025-01-01T00:20:01Z","message":{"content":[{"type":"tool_result","tool_use_id":"t3","is_error":true,"content":"InputValidationError"}]}}`,
+	)
+
+	plan, err := ReadPlan(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan == nil || len(plan.Items) != 3 {
+		t.Fatalf("plan = %+v, want 3 items", plan)
+	}
+	if plan.Completed != 1 || plan.InProgress != 1 || plan.Pending != 1 || plan.Updates != 2 {
+		t.Errorf("plan counts = %d/%d/%d, %d updates", plan.Completed, plan.InProgress, plan.Pending, plan.Updates)
+	}
+	if plan.Completion < 0.33 || plan.Completion > 0.34 {
+		t.Errorf("completion = %v, want 1/3", plan.Completion)
+	}
+	if plan.Done() {
+		t.Error("unfinished plan is done")
+	}
+	if it := plan.Items[0]; it.State != PlanCompleted || it.Updated.Minute() != 10 {
+		t.Errorf("first item = %+v", it)
+	}
+	if it := plan.Items[2]; it.Updated.Minute() != 0 {
+		t.Errorf("unchanged item updated at %v, want its first appearance", it.Updated)
+	}
+	if rest := plan.Remaining(); len(rest) != 2 || rest[0].Content != "Fix the bug" {
+		t.Errorf("remaining = %+v", rest)
+	}
+}
+
+func TestTranscriptPlanTasks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(sessionIndexEnv, "off")
+	path := writeSession(t, home, "-home-u-proj", "abcd1234.jsonl",
+		`{"type":"assistant","timestamp":"2025-01-01T00:00:00Z","message":{"content":[{"type":"tool_use","id":"c1","name":"TaskCreate","input":{"subject":"Write parser","description":"...","activeForm":"Writing parser"}},{"type":"tool_use","id":"c2","name":"TaskCreate","input":{"subject":"Write tests"}},{"type":"tool_use","id":"c3","name":"TaskCreate","input":{"subject":"Scratch"}}]}}`,
+		`{"type":"user","timestamp":"2025-01-01T00:00:01Z","message":{"content":[{"type":"tool_result","tool_use_id":"c1","content":"Task #4 created successfully: Write parser"},{"type":"tool_result","tool_use_id":"c2","content":"ok"}]}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:05:00Z","message":{"content":[{"type":"tool_use","id":"u1","name":"TaskUpdate","input":{"taskId":"4","status":"completed"}},{"type":"tool_use","id":"u2","name":"TaskUpdate","input":{"taskId":"5","status":"in_progress"}},{"type":"tool_use","id":"u3","name":"TaskUpdate","input":{"taskId":"6","status":"deleted"}},{"type":"tool_use","id":"u4","name":"TaskUpdate","input":{"taskId":"99","status":"completed"}}]}}`,
+	)
+
+	plan, err := ReadPlan(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan == nil || len(plan.Items) != 2 {
+		t.Fatalf("plan = %+v, want 2 items", plan)
+	}
+	if it := plan.Items[0]; it.ID != "4" || it.State != PlanCompleted || it.ActiveForm != "Writing parser" {
+		t.Errorf("first item = %+v", it)
+	}
+	if it := plan.Items[1]; it.ID != "5" || it.State != PlanInProgress {
+		t.Errorf("second item = %+v", it)
+	}
+	if plan.Completion != 0.5 || plan.Updates != 6 {
+		t.Errorf("completion = %v, updates = %d", plan.Completion, plan.Updates)
+	}
+}
+
+func TestTranscriptPlanNone(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(sessionIndexEnv, "off")
+	path := writeSession(t, home, "-home-u-proj", "abcd1234.jsonl",
+		`{"type":"user","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"hi"}}`,
+	)
+	plan, err := ReadPlan(path)
+	if err != nil || plan != nil {
+		t.Errorf("plan = %+v, %v; want nil", plan, err)
+	}
+}
@@ -0,0 +1,232 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Timeline event kinds in a FailureReport.
+const (
+	EventStart      = "start"
+	EventPrompt     = "prompt"
+	EventError      = "error"
+	EventCommit     = "commit"
+	EventSuccess    = "success"
+	EventCompaction = "compaction"
+	EventEnd        = "end"
+)
+
+// repeatedErrorThreshold is how many identical tool errors count as the
+// agent being stuck in a retry loop.
+const repeatedErrorThreshold = 3
+
+// TimelineEvent is a notable moment in a session.
+type TimelineEvent struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Detail string    `json:"detail"`
+}
+
+// ToolFailure is a tool call that returned an error.
+type ToolFailure struct {
+	Time    time.Time `json:"time"`
+	Tool    string    `json:"tool"`
+	Input   string    `json:"input,omitempty"` // command or file the call targeted
+	Message string    `json:"message"`
+}
+
+// FailureReport is the transcript evidence for a session post-mortem.
+type FailureReport struct {
+	Session     SessionInfo     `json:"session"`
+	Start       time.Time       `json:"start"`
+	End         time.Time       `json:"end"`
+	Timeline    []TimelineEvent `json:"timeline"`
+	Errors      []ToolFailure   `json:"errors,omitempty"`
+	Compactions []Compaction    `json:"compactions,omitempty"`
+
+	// LastGood is the last point the session's work was known to be
+	// sound: its last successful commit, or failing that, the last
+	// successful tool call before its first error. Nil if the session
+	// failed from the start.
+	LastGood *TimelineEvent `json:"last_good,omitempty"`
+
+	// PendingTool names the tool the session was running when the
+	// transcript ends, if any (crash, timeout, or kill).
+	PendingTool string `json:"pending_tool,omitempty"`
+
+	Usage  *Usage `json:"usage,omitempty"`  // whole session
+	Wasted *Usage `json:"wasted,omitempty"` // after LastGood
+
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// AnalyzeFailure assembles a FailureReport from a session's transcript.
+func AnalyzeFailure(s SessionInfo) (*FailureReport, error) {
+	turns, err := ReadTranscriptTurns(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading transcript: %w", err)
+	}
+	compactions, err := ReadCompactions(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading compactions: %w", err)
+	}
+
+	r := &FailureReport{Session: s, Start: s.StartTime, Compactions: compactions}
+	if len(turns) > 0 {
+		r.Start = turns[0].Timestamp
+		r.End = turns[len(turns)-1].Timestamp
+	}
+
+	var firstError, lastSuccess, lastCommit *TimelineEvent
+	edits := 0
+	for i, turn := range turns {
+		if turn.Role == "user" && turn.Text != "" {
+			kind := EventPrompt
+			if i == 0 {
+				kind = EventStart
+			}
+			r.Timeline = append(r.Timeline, TimelineEvent{turn.Timestamp, kind, firstLine(turn.Text)})
+		}
+		for _, call := range turn.ToolCalls {
+			input := toolCallTarget(call)
+			switch {
+			case call.Pending:
+				r.PendingTool = call.Name
+			case call.IsError:
+				f := ToolFailure{Time: turn.Timestamp, Tool: call.Name, Input: input, Message: firstLine(call.Result)}
+				r.Errors = append(r.Errors, f)
+				ev := TimelineEvent{turn.Timestamp, EventError, fmt.Sprintf("%s failed: %s", call.Name, f.Message)}
+				r.Timeline = append(r.Timeline, ev)
+				if firstError == nil {
+					firstError = &ev
+				}
+			default:
+				ev := &TimelineEvent{turn.Timestamp, "", fmt.Sprintf("%s %s", call.Name, input)}
+				if firstError == nil {
+					lastSuccess = ev
+				}
+				if call.Name == "Edit" || call.Name == "Write" || call.Name == "MultiEdit" {
+					edits++
+				}
+				if call.Name == "Bash" && strings.Contains(input, "git commit") {
+					ev.Kind = EventCommit
+					ev.Detail = "Committed: " + firstLine(input)
+					r.Timeline = append(r.Timeline, *ev)
+					lastCommit = ev
+				}
+			}
+		}
+		if i == len(turns)-1 && turn.Role == "assistant" && turn.Text != "" {
+			r.Timeline = append(r.Timeline, TimelineEvent{turn.Timestamp, EventEnd, firstLine(turn.Text)})
+		}
+	}
+	for _, c := range compactions {
+		r.Timeline = append(r.Timeline, TimelineEvent{c.Timestamp, EventCompaction,
+			fmt.Sprintf("Context compacted (%s, %d turns summarized)", c.Trigger, c.Turns)})
+	}
+	sort.SliceStable(r.Timeline, func(i, j int) bool {
+		return r.Timeline[i].Time.Before(r.Timeline[j].Time)
+	})
+
+	switch {
+	case lastCommit != nil:
+		r.LastGood = lastCommit
+	case lastSuccess != nil:
+		lastSuccess.Kind = EventSuccess
+		r.LastGood = lastSuccess
+	}
+
+	if r.Usage, err = ReadUsage(s.Path); err != nil {
+		return nil, fmt.Errorf("reading usage: %w", err)
+	}
+	since := r.Start
+	if r.LastGood != nil {
+		since = r.LastGood.Time
+	}
+	if r.Wasted, err = ReadUsageSince(s.Path, since); err != nil {
+		return nil, fmt.Errorf("reading usage: %w", err)
+	}
+
+	r.Suggestions = suggestPrevention(r, edits, lastCommit != nil)
+	return r, nil
+}
+
+// suggestPrevention turns the report's patterns into prevention advice.
+func suggestPrevention(r *FailureReport, edits int, committed bool) []string {
+	var out []string
+
+	counts := make(map[string]int)
+	var order []string
+	for _, e := range r.Errors {
+		key := e.Tool + ": " + e.Message
+		if counts[key] == 0 {
+			order = append(order, key)
+		}
+		counts[key]++
+	}
+	for _, key := range order {
+		if counts[key] >= repeatedErrorThreshold {
+			out = append(out, fmt.Sprintf("The same error repeated %d times (%s). Tell the agent to stop and escalate after two identical failures.",
+				counts[key], truncateText(key, 100)))
+		}
+	}
+
+	for _, e := range r.Errors {
+		msg := strings.ToLower(e.Message)
+		if strings.Contains(msg, "permission") || strings.Contains(msg, "not allowed") || strings.Contains(msg, "denied") {
+			out = append(out, fmt.Sprintf("%s calls were blocked by permissions. Pre-approve them in the rig's Claude settings.", e.Tool))
+			break
+		}
+	}
+
+	if len(r.Compactions) > 0 {
+		out = append(out, fmt.Sprintf("Context was compacted %d time(s), so later turns worked from a summary. Split the task or hand off before the context fills.",
+			len(r.Compactions)))
+	}
+	if edits > 0 && !committed {
+		out = append(out, "Files were edited but nothing was committed. Have agents commit checkpoints so a failed session leaves recoverable work.")
+	}
+	if r.PendingTool != "" {
+		out = append(out, fmt.Sprintf("The transcript ends during a %s call: the session crashed, timed out, or was killed. Check the witness log for the cause.", r.PendingTool))
+	}
+	if len(r.Errors) == 0 && r.PendingTool == "" {
+		out = append(out, "No tool errors were recorded. The failure is likely one of judgment or instructions; review the final turns against the task.")
+	}
+	return out
+}
+
+// toolCallTarget summarizes what a tool call acted on: the command for
+// Bash, the path for file tools, or the compacted input otherwise.
+func toolCallTarget(call ToolCall) string {
+	var input map[string]interface{}
+	if json.Unmarshal(call.Input, &input) == nil {
+		for _, key := range []string{"command", "file_path", "path", "pattern", "url", "description"} {
+			if v, ok := input[key].(string); ok && v != "" {
+				return truncateText(v, 200)
+			}
+		}
+	}
+	return truncateText(string(call.Input), 200)
+}
+
+// firstLine returns the first non-empty line of text, truncated.
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return truncateText(line, 160)
+		}
+	}
+	return ""
+}
+
+// truncateText shortens s to at most n runes.
+func truncateText(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
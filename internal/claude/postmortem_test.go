@@ -0,0 +1,66 @@
+package claude
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeFailure(t *testing.T) {
+	home := t.TempDir()
+	failing := func(id, ts string) []string {
+		return []string{
+			`{"type":"assistant","timestamp":"` + ts + `","message":{"id":"m` + id + `","role":"assistant","content":[{"type":"tool_use","id":"t` + id + `","name":"Bash","input":{"command":"go test ./..."}}],"usage":{"input_tokens":100,"output_tokens":50}}}`,
+			`{"type":"user","timestamp":"` + ts + `","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t` + id + `","content":"FAIL auth_test.go\nmore","is_error":true}]}}`,
+		}
+	}
+	lines := []string{
+		`{"type":"user","timestamp":"2025-01-01T10:00:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/polecats/toast <- mayor • 2025-01-01T10:00 • gt-42"}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T10:01:00Z","message":{"id":"m1","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":"auth.go"}}],"usage":{"input_tokens":1000,"output_tokens":10}}}`,
+		`{"type":"user","timestamp":"2025-01-01T10:01:00Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T10:02:00Z","message":{"id":"m2","role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"git commit -am wip"}}],"usage":{"input_tokens":1000,"output_tokens":10}}}`,
+		`{"type":"user","timestamp":"2025-01-01T10:02:00Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"[main abc] wip"}]}}`,
+	}
+	lines = append(lines, failing("3", "2025-01-01T10:03:00Z")...)
+	lines = append(lines, failing("4", "2025-01-01T10:04:00Z")...)
+	lines = append(lines, failing("5", "2025-01-01T10:05:00Z")...)
+	lines = append(lines,
+		`{"type":"assistant","timestamp":"2025-01-01T10:06:00Z","message":{"id":"m6","role":"assistant","content":[{"type":"tool_use","id":"t6","name":"Bash","input":{"command":"sleep 999"}}],"usage":{"input_tokens":100,"output_tokens":50}}}`,
+	)
+	path := writeSession(t, home, "-home-u-proj", "s1.jsonl", lines...)
+
+	r, err := AnalyzeFailure(SessionInfo{ID: "s1", Path: path})
+	if err != nil {
+		t.Fatalf("AnalyzeFailure: %v", err)
+	}
+
+	if len(r.Errors) != 3 || r.Errors[0].Input != "go test ./..." || r.Errors[0].Message != "FAIL auth_test.go" {
+		t.Errorf("errors = %+v", r.Errors)
+	}
+	if r.LastGood == nil || r.LastGood.Kind != EventCommit || !strings.Contains(r.LastGood.Detail, "git commit") {
+		t.Errorf("last good = %+v", r.LastGood)
+	}
+	if r.PendingTool != "Bash" {
+		t.Errorf("pending tool = %q, want Bash", r.PendingTool)
+	}
+	if r.Usage.TotalTokens() != 2620 || r.Wasted.TotalTokens() != 1610 {
+		t.Errorf("usage = %d, wasted = %d; want 2620, 1610", r.Usage.TotalTokens(), r.Wasted.TotalTokens())
+	}
+
+	kinds := make([]string, 0, len(r.Timeline))
+	for _, ev := range r.Timeline {
+		kinds = append(kinds, ev.Kind)
+	}
+	if got := strings.Join(kinds, ","); got != "start,commit,error,error,error" {
+		t.Errorf("timeline kinds = %s", got)
+	}
+
+	joined := strings.Join(r.Suggestions, "\n")
+	for _, want := range []string{"repeated 3 times", "ends during a Bash call"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "nothing was committed") {
+		t.Errorf("uncommitted-work suggestion despite a commit:\n%s", joined)
+	}
+}
@@ -0,0 +1,158 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// RedactRule replaces every match of Pattern with "[REDACTED:<Name>]".
+type RedactRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// redactRuleSets are the built-in named rule sets.
+// Most patterns match only token characters, but the private-key and
+// bearer-token rules also span spaces and punctuation, and user patterns
+// can match anything, so RedactSession checks every rewritten line is
+// still valid JSON.
+var redactRuleSets = map[string][]RedactRule{
+	"secrets": {
+		{Name: "anthropic-key", Pattern: regexp.MustCompile(`sk-ant-[A-Za-z0-9_\-]{20,}`)},
+		{Name: "openai-key", Pattern: regexp.MustCompile(`sk-(?:proj-)?[A-Za-z0-9]{32,}`)},
+		{Name: "aws-access-key", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+		{Name: "github-token", Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+		{Name: "slack-token", Pattern: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9\-]{10,}`)},
+		{Name: "private-key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)},
+		{Name: "bearer-token", Pattern: regexp.MustCompile(`Bearer [A-Za-z0-9\-._~+/]{20,}=*`)},
+	},
+}
+
+// RedactRuleSetNames returns the names of the built-in rule sets.
+func RedactRuleSetNames() []string {
+	names := make([]string, 0, len(redactRuleSets))
+	for name := range redactRuleSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RedactRules resolves named rule sets into rules.
+func RedactRules(names ...string) ([]RedactRule, error) {
+	var rules []RedactRule
+	for _, name := range names {
+		set, ok := redactRuleSets[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown rule set %q (available: %s)", name, strings.Join(RedactRuleSetNames(), ", "))
+		}
+		rules = append(rules, set...)
+	}
+	return rules, nil
+}
+
+// RedactResult reports what a redaction pass changed.
+type RedactResult struct {
+	Path       string         `json:"path"`
+	BackupPath string         `json:"backup_path,omitempty"`
+	Lines      int            `json:"lines"`   // lines that contained a match
+	Matches    map[string]int `json:"matches"` // rule name -> match count
+	Total      int            `json:"total"`   // total matches
+	DryRun     bool           `json:"dry_run"`
+}
+
+// RedactOptions controls RedactSession.
+type RedactOptions struct {
+	// DryRun reports matches without rewriting the file.
+	DryRun bool
+
+	// NoBackup skips writing a backup of the original transcript.
+	NoBackup bool
+}
+
+// RedactSession rewrites a session transcript with every rule match replaced.
+// Unless NoBackup is set, the original is kept alongside as
+// <file>.bak-<timestamp> (which still contains the secrets).
+//
+// The file is left untouched if a replacement would turn a valid JSON line
+// into an invalid one, or if the transcript changed while it was being
+// redacted (a session still writing to it).
+func RedactSession(path string, rules []RedactRule, opts RedactOptions) (*RedactResult, error) {
+	before, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RedactResult{
+		Path:    path,
+		Matches: make(map[string]int),
+		DryRun:  opts.DryRun,
+	}
+
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		changed := false
+		for _, rule := range rules {
+			n := len(rule.Pattern.FindAllStringIndex(line, -1))
+			if n == 0 {
+				continue
+			}
+			line = rule.Pattern.ReplaceAllString(line, "[REDACTED:"+rule.Name+"]")
+			result.Matches[rule.Name] += n
+			result.Total += n
+			changed = true
+		}
+		if changed {
+			result.Lines++
+			if !json.Valid([]byte(line)) && json.Valid(scanner.Bytes()) {
+				return nil, fmt.Errorf("redacting line %d of %s would leave invalid JSON; narrow the rules", lineNum, path)
+			}
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	if opts.DryRun || result.Total == 0 {
+		return result, nil
+	}
+
+	if after, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if after.Size() != before.Size() || !after.ModTime().Equal(before.ModTime()) {
+		return nil, fmt.Errorf("%s changed while being redacted; is the session still running?", path)
+	}
+
+	if !opts.NoBackup {
+		result.BackupPath = fmt.Sprintf("%s.bak-%s", path, time.Now().Format("20060102T150405"))
+		if err := os.WriteFile(result.BackupPath, data, 0600); err != nil {
+			return nil, fmt.Errorf("writing backup: %w", err)
+		}
+	}
+
+	if err := util.AtomicWriteFile(path, out.Bytes(), 0600); err != nil {
+		return nil, fmt.Errorf("rewriting %s: %w", path, err)
+	}
+
+	return result, nil
+}
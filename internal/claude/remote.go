@@ -0,0 +1,138 @@
+package claude
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrIncomplete is returned, wrapped, by DiscoverSessions along with the
+// sessions it did find when it gave up on part of a slow filesystem.
+// Callers that can work with partial results check for it with errors.Is.
+var ErrIncomplete = errors.New("session discovery incomplete")
+
+// discoveryTimeoutEnv sets the default discovery timeout, e.g. "2m".
+const discoveryTimeoutEnv = "GT_DISCOVERY_TIMEOUT"
+
+// defaultDiscoveryTimeout bounds discovery when neither the filter nor
+// $GT_DISCOVERY_TIMEOUT does. A local scan takes well under a second; a
+// hung network mount would otherwise block forever.
+const defaultDiscoveryTimeout = 30 * time.Second
+
+// discoveryTimeout resolves a SessionFilter.Timeout value: $GT_DISCOVERY_TIMEOUT
+// or defaultDiscoveryTimeout when unset. Negative means no timeout.
+func discoveryTimeout(timeout time.Duration) time.Duration {
+	if timeout == 0 {
+		timeout, _ = time.ParseDuration(os.Getenv(discoveryTimeoutEnv))
+	}
+	if timeout == 0 {
+		timeout = defaultDiscoveryTimeout
+	}
+	return timeout
+}
+
+// deadlineTimer returns a channel that fires at deadline, or nil (never
+// fires) for a zero deadline, and a function to release it.
+func deadlineTimer(deadline time.Time) (<-chan time.Time, func()) {
+	if deadline.IsZero() {
+		return nil, func() {}
+	}
+	t := time.NewTimer(time.Until(deadline))
+	return t.C, func() { t.Stop() }
+}
+
+var (
+	localOnlyMu sync.RWMutex
+	localOnly   bool
+)
+
+// localOnlyEnv set to 1 makes discovery skip network roots, as
+// SetLocalOnly does.
+const localOnlyEnv = "GT_LOCAL_ONLY"
+
+// SetLocalOnly makes session discovery skip Claude data directories on
+// network filesystems (NFS, SMB, AFS, Ceph), e.g. for a --local-only
+// flag. Where the filesystem type can't be told, every root counts as
+// local.
+func SetLocalOnly(on bool) {
+	localOnlyMu.Lock()
+	localOnly = on
+	localOnlyMu.Unlock()
+}
+
+// skipRemoteRoots reports whether discovery skips network roots.
+func skipRemoteRoots() bool {
+	localOnlyMu.RLock()
+	defer localOnlyMu.RUnlock()
+	return localOnly || os.Getenv(localOnlyEnv) == "1"
+}
+
+// rootListing is the result of listing one root's transcripts.
+type rootListing struct {
+	root  string
+	jobs  []parseJob
+	seen  map[string]bool
+	err   error
+	ready chan struct{}
+}
+
+// listRoots lists the transcripts in each root concurrently, so one hung
+// mount doesn't hold up the rest. Roots not listed by deadline are
+// returned as timedOut; their listings finish, or not, in the background.
+func listRoots(roots []string, filter SessionFilter, deadline time.Time) (done []*rootListing, timedOut []string) {
+	listings := make([]*rootListing, len(roots))
+	for i, root := range roots {
+		l := &rootListing{root: root, seen: make(map[string]bool), ready: make(chan struct{})}
+		listings[i] = l
+		go func() {
+			defer close(l.ready)
+			l.jobs, l.err = projectJobs(filepath.Join(l.root, "projects"), l.root, filter, l.seen)
+		}()
+	}
+
+	expired, stop := deadlineTimer(deadline)
+	defer stop()
+	late := false
+	for _, l := range listings {
+		if !late {
+			select {
+			case <-l.ready:
+			case <-expired:
+				late = true
+			}
+		}
+		select {
+		case <-l.ready:
+			done = append(done, l)
+		default:
+			timedOut = append(timedOut, l.root)
+		}
+	}
+	return done, timedOut
+}
+
+// statfsTimeout bounds how long localRoots waits to learn a root's
+// filesystem type.
+const statfsTimeout = 2 * time.Second
+
+// localRoots drops the roots on network filesystems. A root whose
+// filesystem doesn't answer within statfsTimeout is taken to be remote.
+func localRoots(roots []string) []string {
+	var out []string
+	for _, root := range roots {
+		remote := make(chan bool, 1)
+		go func() { remote <- isRemoteFS(root) }()
+		select {
+		case r := <-remote:
+			if r {
+				continue
+			}
+		case <-time.After(statfsTimeout):
+			continue
+		}
+		out = append(out, root)
+	}
+	return out
+}
@@ -0,0 +1,22 @@
+package claude
+
+import "syscall"
+
+// Filesystem magic numbers (statfs f_type) of network filesystems.
+var remoteFSTypes = map[uint32]bool{
+	0x6969:     true, // NFS
+	0x517B:     true, // SMB
+	0xFF534D42: true, // CIFS
+	0xFE534D42: true, // SMB2
+	0x5346414F: true, // AFS
+	0x00C36400: true, // Ceph
+}
+
+// isRemoteFS reports whether path is on a network filesystem.
+func isRemoteFS(path string) bool {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return false
+	}
+	return remoteFSTypes[uint32(st.Type)] //nolint:gosec // G115: f_type is a 32-bit magic number
+}
@@ -0,0 +1,43 @@
+package claude
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestDiscoverSessionsTimeout(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-home-u-proj", "ok.jsonl",
+		`{"type":"user","timestamp":"2025-03-01T12:00:00Z","message":{"role":"user","content":"hi"}}`,
+	)
+
+	// Reading a FIFO with no writer blocks, like a read from a hung mount
+	fifo := filepath.Join(home, ".claude", "projects", "-home-u-proj", "hung.jsonl")
+	if err := syscall.Mkfifo(fifo, 0644); err != nil {
+		t.Skipf("mkfifo: %v", err)
+	}
+	t.Cleanup(func() {
+		// Release the abandoned reader
+		if f, err := os.OpenFile(fifo, os.O_WRONLY, 0); err == nil {
+			f.Close()
+		}
+	})
+
+	start := time.Now()
+	sessions, err := DiscoverSessions(SessionFilter{Timeout: 200 * time.Millisecond, Workers: 2})
+	if !errors.Is(err, ErrIncomplete) {
+		t.Fatalf("err = %v, want ErrIncomplete", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("discovery took %v despite the timeout", elapsed)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "ok" {
+		t.Errorf("got %+v, want the readable session", sessions)
+	}
+}
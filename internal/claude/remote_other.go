@@ -0,0 +1,9 @@
+//go:build !linux
+
+package claude
+
+// isRemoteFS is only implemented on Linux; elsewhere every path counts as
+// local.
+func isRemoteFS(path string) bool {
+	return false
+}
@@ -0,0 +1,37 @@
+package claude
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiscoveryTimeout(t *testing.T) {
+	t.Setenv(discoveryTimeoutEnv, "")
+	if got := discoveryTimeout(0); got != defaultDiscoveryTimeout {
+		t.Errorf("discoveryTimeout(0) = %v, want the default", got)
+	}
+	if got := discoveryTimeout(-1); got != -1 {
+		t.Errorf("discoveryTimeout(-1) = %v, want no timeout", got)
+	}
+	t.Setenv(discoveryTimeoutEnv, "2m")
+	if got := discoveryTimeout(0); got != 2*time.Minute {
+		t.Errorf("discoveryTimeout(0) with $%s=2m = %v", discoveryTimeoutEnv, got)
+	}
+	if got := discoveryTimeout(time.Second); got != time.Second {
+		t.Errorf("discoveryTimeout(1s) = %v, want the filter's timeout", got)
+	}
+}
+
+func TestIncompleteError(t *testing.T) {
+	err := incompleteError([]string{"/nfs/u/.claude"}, 3, 10)
+	if !errors.Is(err, ErrIncomplete) {
+		t.Fatalf("incompleteError does not wrap ErrIncomplete: %v", err)
+	}
+	for _, want := range []string{"could not list /nfs/u/.claude", "read 7 of 10 transcripts"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q lacks %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+package claude
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/state"
+)
+
+// Old transcripts can be moved into archives (ArchiveSessions) or deleted
+// outright (PruneSessions) so the Claude data directory doesn't grow
+// without bound. Archives are the checksummed .tar.gz files WriteArchive
+// writes, and stay readable: ListArchivedSessions finds the sessions in
+// them and RestoreArchivedSession puts one back.
+//
+// Only this machine's own Claude data directory (ClaudeDir) is archived or
+// pruned. The extra roots discovery also reads (claude_roots) hold other
+// users' or machines' transcripts, which aren't ours to delete.
+
+// DefaultArchiveDir returns where ArchiveSessions puts archives unless
+// told otherwise.
+func DefaultArchiveDir() string {
+	return filepath.Join(state.StateDir(), "session-archives")
+}
+
+// RetentionResult reports what ArchiveSessions or PruneSessions did, or
+// in a dry run would do.
+type RetentionResult struct {
+	DryRun   bool          `json:"dry_run,omitempty"`
+	Archive  string        `json:"archive,omitempty"` // archive written; empty for prunes and dry runs
+	Sessions []SessionInfo `json:"sessions"`          // sessions moved or deleted
+	Files    int           `json:"files"`             // transcripts, counting subagents'
+	Bytes    int64         `json:"bytes"`             // space freed
+}
+
+// ArchiveSessions moves the transcripts of sessions last written before
+// olderThan, with their subagents' transcripts, into a new archive in
+// destination (DefaultArchiveDir if empty). The originals are deleted only
+// once the archive verifies. With dryRun, it reports what it would move
+// without touching anything.
+func ArchiveSessions(olderThan time.Time, destination string, dryRun bool, progress Progress) (*RetentionResult, error) {
+	result, files, err := expiredTranscripts(olderThan)
+	if err != nil || len(files) == 0 {
+		return result, err
+	}
+	result.DryRun = dryRun
+	if dryRun {
+		return result, nil
+	}
+
+	if destination == "" {
+		destination = DefaultArchiveDir()
+	}
+	dest := filepath.Join(destination, "sessions-"+time.Now().UTC().Format("20060102-150405")+".tar.gz")
+	if _, err := WriteArchive(dest, files, progress); err != nil {
+		return nil, fmt.Errorf("writing archive: %w", err)
+	}
+	v, err := VerifyArchive(dest)
+	if err != nil {
+		return nil, fmt.Errorf("verifying archive: %w", err)
+	}
+	if !v.OK() {
+		return nil, fmt.Errorf("archive %s failed verification (%s: %s); no transcripts were removed",
+			dest, v.Problems[0].Name, v.Problems[0].Reason)
+	}
+	result.Archive = dest
+
+	return result, removeTranscripts(files)
+}
+
+// PruneSessions deletes the transcripts of sessions last written before
+// olderThan, with their subagents' transcripts. With dryRun, it reports
+// what it would delete without touching anything.
+func PruneSessions(olderThan time.Time, dryRun bool) (*RetentionResult, error) {
+	result, files, err := expiredTranscripts(olderThan)
+	if err != nil || len(files) == 0 {
+		return result, err
+	}
+	result.DryRun = dryRun
+	if dryRun {
+		return result, nil
+	}
+	return result, removeTranscripts(files)
+}
+
+// expiredTranscripts finds the sessions last written before olderThan,
+// skipping any still active, and returns them with every transcript
+// file they own: their own and their subagents'.
+func expiredTranscripts(olderThan time.Time) (*RetentionResult, []SessionInfo, error) {
+	if olderThan.IsZero() {
+		return nil, nil, fmt.Errorf("no cutoff given")
+	}
+	sessions, err := DiscoverSessions(SessionFilter{Roots: []string{ClaudeDir()}})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &RetentionResult{Sessions: []SessionInfo{}}
+	var files []SessionInfo
+	for _, s := range sessions {
+		last := s.EndTime
+		if last.IsZero() {
+			stat, err := os.Stat(s.Path)
+			if err != nil {
+				continue
+			}
+			last = stat.ModTime()
+		}
+		if !last.Before(olderThan) || s.State == StateActive {
+			continue
+		}
+		result.Sessions = append(result.Sessions, s)
+		files = append(files, s)
+		files = append(files, subagentTranscripts(s)...)
+	}
+	for _, f := range files {
+		if stat, err := os.Stat(f.Path); err == nil {
+			result.Bytes += stat.Size()
+		}
+	}
+	result.Files = len(files)
+	return result, files, nil
+}
+
+// subagentTranscripts returns the transcripts under a session's
+// <session-id>/subagents/ directory, as archive inputs.
+func subagentTranscripts(s SessionInfo) []SessionInfo {
+	paths, _ := filepath.Glob(filepath.Join(filepath.Dir(s.Path), s.ID, "subagents", "*.jsonl"))
+	files := make([]SessionInfo, 0, len(paths))
+	for _, path := range paths {
+		files = append(files, SessionInfo{
+			ID:       strings.TrimSuffix(filepath.Base(path), ".jsonl"),
+			Path:     path,
+			Project:  s.Project,
+			Root:     s.Root,
+			ParentID: s.ID,
+		})
+	}
+	return files
+}
+
+// removeTranscripts deletes transcript files, then the session and
+// subagents directories they leave empty.
+func removeTranscripts(files []SessionInfo) error {
+	var errs []error
+	for _, f := range files {
+		if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+			continue
+		}
+		if f.ParentID != "" {
+			subagents := filepath.Dir(f.Path)
+			if os.Remove(subagents) == nil {
+				_ = os.Remove(filepath.Dir(subagents))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ArchivedSession is a transcript stored in a session archive.
+type ArchivedSession struct {
+	ArchiveEntry
+	Archive string `json:"archive"` // path of the archive holding it
+}
+
+// ListArchivedSessions returns the sessions in the archives in dir
+// (DefaultArchiveDir if empty), newest archive first. Subagent transcripts
+// are left out; they are restored with their session. A missing directory
+// yields no sessions.
+func ListArchivedSessions(dir string) ([]ArchivedSession, error) {
+	if dir == "" {
+		dir = DefaultArchiveDir()
+	}
+	archives, err := filepath.Glob(filepath.Join(dir, "*.tar.gz"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(archives)))
+
+	var sessions []ArchivedSession
+	for _, archive := range archives {
+		manifest, err := readArchiveManifest(archive)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", archive, err)
+		}
+		for _, entry := range manifest.Files {
+			if strings.Contains(entry.Name, "/subagents/") {
+				continue
+			}
+			sessions = append(sessions, ArchivedSession{ArchiveEntry: entry, Archive: archive})
+		}
+	}
+	return sessions, nil
+}
+
+// FindArchivedSession returns the archived session whose ID is id or
+// starts with it, searching the archives in dir (DefaultArchiveDir if
+// empty).
+func FindArchivedSession(dir, id string) (*ArchivedSession, error) {
+	sessions, err := ListArchivedSessions(dir)
+	if err != nil {
+		return nil, err
+	}
+	var found *ArchivedSession
+	for i, s := range sessions {
+		if s.SessionID == id {
+			return &sessions[i], nil
+		}
+		if strings.HasPrefix(s.SessionID, id) {
+			if found != nil && found.SessionID != s.SessionID {
+				return nil, fmt.Errorf("archived session %s is ambiguous", id)
+			}
+			found = &sessions[i]
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("session %s not found in archives", id)
+	}
+	return found, nil
+}
+
+// OpenArchivedSession returns a reader over an archived transcript.
+func OpenArchivedSession(s ArchivedSession) (io.ReadCloser, error) {
+	f, err := os.Open(s.Archive) //nolint:gosec // G304: archive found by ListArchivedSessions
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			f.Close()
+			if err == io.EOF {
+				return nil, fmt.Errorf("%s missing from %s", s.Name, s.Archive)
+			}
+			return nil, err
+		}
+		if hdr.Name == s.Name {
+			return struct {
+				io.Reader
+				io.Closer
+			}{tr, f}, nil
+		}
+	}
+}
+
+// RestoreArchivedSession writes an archived session's transcript, and its
+// subagents', back into the projects directory of the Claude data
+// directory it was archived from (ProjectsDir for older archives) so every
+// session tool can read it again, and returns the transcript's path. It
+// won't overwrite a transcript that is already there. The archive is left
+// as it is.
+func RestoreArchivedSession(s ArchivedSession) (string, error) {
+	projects := ProjectsDir()
+	if s.Root != "" {
+		projects = filepath.Join(s.Root, "projects")
+	}
+	path := filepath.Join(projects, filepath.FromSlash(s.Name))
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("session %s is already in %s", s.SessionID, filepath.Dir(path))
+	}
+	subagents := strings.TrimSuffix(s.Name, ".jsonl") + "/subagents/"
+
+	f, err := os.Open(s.Archive) //nolint:gosec // G304: archive found by ListArchivedSessions
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return "", err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	restored := false
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", s.Archive, err)
+		}
+		if hdr.Name != s.Name && !strings.HasPrefix(hdr.Name, subagents) {
+			continue
+		}
+		dest := filepath.Join(projects, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(dest, projects+string(filepath.Separator)) {
+			return "", fmt.Errorf("archive entry %s escapes the projects directory", hdr.Name)
+		}
+		if err := restoreFile(dest, tr, hdr.ModTime); err != nil {
+			return "", fmt.Errorf("restoring %s: %w", hdr.Name, err)
+		}
+		restored = restored || hdr.Name == s.Name
+	}
+	if !restored {
+		return "", fmt.Errorf("%s missing from %s", s.Name, s.Archive)
+	}
+	return path, nil
+}
+
+// restoreFile writes r to path, keeping the transcript's modification
+// time so discovery orders it as before.
+func restoreFile(path string, r io.Reader, modTime time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600) //nolint:gosec // G304: path inside the projects directory
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(path, modTime, modTime)
+}
+
+// readArchiveManifest reads just the manifest, the first entry of every
+// session archive.
+func readArchiveManifest(path string) (*ArchiveManifest, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: archive in the archive directory
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, err
+	}
+	if hdr.Name != ArchiveManifestName {
+		return nil, fmt.Errorf("not a session archive: first entry is %s", hdr.Name)
+	}
+	var manifest ArchiveManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("unreadable manifest: %w", err)
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,157 @@
+package claude
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchiveAndRestoreSessions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	oldLine := `{"type":"user","timestamp":"2025-01-02T09:00:00Z","message":{"role":"user","content":"old work"}}`
+	old := writeSession(t, home, "-home-u-proj", "aaaa1111.jsonl", oldLine)
+	recent := writeSession(t, home, "-home-u-proj", "bbbb2222.jsonl",
+		`{"type":"user","timestamp":"2025-12-30T09:00:00Z","message":{"role":"user","content":"new work"}}`,
+	)
+	if err := os.MkdirAll(filepath.Join(filepath.Dir(old), "aaaa1111", "subagents"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	sub := writeSession(t, home, "-home-u-proj", "aaaa1111/subagents/agent-1.jsonl",
+		`{"type":"user","timestamp":"2025-01-02T09:01:00Z","message":{"role":"user","content":"subtask"}}`,
+	)
+	cutoff := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	dry, err := ArchiveSessions(cutoff, t.TempDir(), true, nil)
+	if err != nil || len(dry.Sessions) != 1 || dry.Files != 2 || dry.Archive != "" {
+		t.Fatalf("dry run = %+v, %v", dry, err)
+	}
+	if _, err := os.Stat(old); err != nil {
+		t.Fatal("dry run removed a transcript")
+	}
+
+	archives := t.TempDir()
+	result, err := ArchiveSessions(cutoff, archives, false, nil)
+	if err != nil {
+		t.Fatalf("ArchiveSessions: %v", err)
+	}
+	if result.Archive == "" || result.Sessions[0].ID != "aaaa1111" {
+		t.Fatalf("result = %+v", result)
+	}
+	for _, path := range []string{old, sub, filepath.Dir(filepath.Dir(sub))} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s left behind after archiving", path)
+		}
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("recent session was archived: %v", err)
+	}
+
+	found, err := FindArchivedSession(archives, "aaaa")
+	if err != nil {
+		t.Fatalf("FindArchivedSession: %v", err)
+	}
+	rc, err := OpenArchivedSession(*found)
+	if err != nil {
+		t.Fatalf("OpenArchivedSession: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != oldLine+"\n" {
+		t.Errorf("archived transcript = %q", data)
+	}
+
+	path, err := RestoreArchivedSession(*found)
+	if err != nil || path != old {
+		t.Fatalf("RestoreArchivedSession = %q, %v", path, err)
+	}
+	if _, err := os.Stat(sub); err != nil {
+		t.Errorf("subagent transcript not restored: %v", err)
+	}
+	if _, err := RestoreArchivedSession(*found); err == nil {
+		t.Error("restoring over an existing transcript succeeded")
+	}
+}
+
+func TestPruneSessions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	old := writeSession(t, home, "-home-u-proj", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-01-02T09:00:00Z","message":{"role":"user","content":"old work"}}`,
+	)
+	cutoff := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := PruneSessions(time.Time{}, true); err == nil {
+		t.Error("PruneSessions accepted no cutoff")
+	}
+	if result, err := PruneSessions(cutoff, true); err != nil || len(result.Sessions) != 1 || !result.DryRun {
+		t.Fatalf("dry run = %+v, %v", result, err)
+	}
+	if _, err := os.Stat(old); err != nil {
+		t.Fatal("dry run removed a transcript")
+	}
+	if result, err := PruneSessions(cutoff, false); err != nil || result.Bytes == 0 {
+		t.Fatalf("PruneSessions = %+v, %v", result, err)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("pruned transcript still exists")
+	}
+}
+
+func TestRetentionStaysInOwnRoot(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	line := `{"type":"user","timestamp":"2025-01-02T09:00:00Z","message":{"role":"user","content":"old work"}}`
+	own := writeSession(t, home, "-home-u-proj", "aaaa1111.jsonl", line)
+
+	// Another user's home, read through claude_roots, with the same session
+	other := t.TempDir()
+	theirs := writeSession(t, other, "-home-u-proj", "aaaa1111.jsonl", line)
+	SetExtraRoots([]string{filepath.Join(other, ".claude")})
+	t.Cleanup(func() { SetExtraRoots(nil) })
+	cutoff := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	archives := t.TempDir()
+	result, err := ArchiveSessions(cutoff, archives, false, nil)
+	if err != nil || len(result.Sessions) != 1 {
+		t.Fatalf("ArchiveSessions = %+v, %v", result, err)
+	}
+	if _, err := os.Stat(theirs); err != nil {
+		t.Errorf("another root's transcript was archived: %v", err)
+	}
+	found, err := FindArchivedSession(archives, "aaaa1111")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Root != filepath.Dir(filepath.Dir(filepath.Dir(own))) {
+		t.Errorf("archived root = %q", found.Root)
+	}
+
+	// Restored into the root it came from, wherever ClaudeDir points now
+	t.Setenv("HOME", t.TempDir())
+	if path, err := RestoreArchivedSession(*found); err != nil || path != own {
+		t.Errorf("RestoreArchivedSession = %q, %v; want %s", path, err, own)
+	}
+
+	t.Setenv("HOME", home)
+	if _, err := PruneSessions(cutoff, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(theirs); err != nil {
+		t.Errorf("another root's transcript was pruned: %v", err)
+	}
+
+	// One archive can't hold the same entry from two roots
+	both := []SessionInfo{
+		{ID: "aaaa1111", Path: theirs, Project: "-home-u-proj", Root: filepath.Join(other, ".claude")},
+		{ID: "aaaa1111", Path: theirs, Project: "-home-u-proj", Root: filepath.Join(home, ".claude")},
+	}
+	if _, err := WriteArchive(filepath.Join(t.TempDir(), "x.tar.gz"), both, nil); err == nil {
+		t.Error("WriteArchive accepted colliding entries from two roots")
+	}
+}
@@ -0,0 +1,157 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ConfigDirEnv is the environment variable Claude Code reads to relocate
+// its data directory.
+const ConfigDirEnv = "CLAUDE_CONFIG_DIR"
+
+var (
+	rootMu       sync.RWMutex
+	rootOverride string
+	extraRoots   []string
+)
+
+// WithRoot makes the claude package read Claude Code data from path
+// instead of locating it, e.g. for a --claude-dir flag or tests. It
+// returns a function that restores the previous root.
+func WithRoot(path string) (restore func()) {
+	rootMu.Lock()
+	prev := rootOverride
+	rootOverride = path
+	rootMu.Unlock()
+	return func() {
+		rootMu.Lock()
+		rootOverride = prev
+		rootMu.Unlock()
+	}
+}
+
+// explicitClaudeDir returns the data directory set by WithRoot or
+// CLAUDE_CONFIG_DIR, or "" if neither is set.
+func explicitClaudeDir() string {
+	rootMu.RLock()
+	root := rootOverride
+	rootMu.RUnlock()
+	if root != "" {
+		return root
+	}
+	return os.Getenv(ConfigDirEnv)
+}
+
+// ClaudeDir returns the Claude Code data directory. In order: the root set
+// by WithRoot, $CLAUDE_CONFIG_DIR, then the first of ~/.claude,
+// $XDG_CONFIG_HOME/claude, and $XDG_DATA_HOME/claude that holds session
+// transcripts. Defaults to ~/.claude.
+func ClaudeDir() string {
+	if dir := explicitClaudeDir(); dir != "" {
+		return dir
+	}
+	candidates := ClaudeDirCandidates()
+	for _, dir := range candidates {
+		if info, err := os.Stat(filepath.Join(dir, "projects")); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return candidates[0]
+}
+
+// ClaudeDirCandidates returns the locations Claude Code may keep its data
+// in when not relocated explicitly, most conventional first. Under WSL,
+// the Windows users' ~/.claude directories follow the Linux ones.
+func ClaudeDirCandidates() []string {
+	home := homeDir()
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	candidates := []string{
+		filepath.Join(home, ".claude"),
+		filepath.Join(configHome, "claude"),
+		filepath.Join(dataHome, "claude"),
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		candidates = append(candidates, wslClaudeDirs()...)
+	}
+	return candidates
+}
+
+// homeDir returns the user's home directory. os.UserHomeDir reads $HOME,
+// or %USERPROFILE% on Windows; shells that leave the one it wants unset
+// fall back to the other, then to %HOMEDRIVE%%HOMEPATH%.
+func homeDir() string {
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		return home
+	}
+	if home := os.Getenv("USERPROFILE"); home != "" {
+		return home
+	}
+	if drive, path := os.Getenv("HOMEDRIVE"), os.Getenv("HOMEPATH"); drive != "" && path != "" {
+		return drive + path
+	}
+	return os.Getenv("HOME")
+}
+
+// wslMountRoot is where WSL mounts Windows drives.
+var wslMountRoot = "/mnt"
+
+// wslClaudeDirs returns the Claude data directories of Windows users on
+// the drives WSL mounts, e.g. /mnt/c/Users/x/.claude, sorted.
+func wslClaudeDirs() []string {
+	dirs, _ := filepath.Glob(filepath.Join(wslMountRoot, "*", "Users", "*", ".claude"))
+	sort.Strings(dirs)
+	return dirs
+}
+
+// ProjectsDir returns the directory holding per-project session transcripts.
+func ProjectsDir() string {
+	return filepath.Join(ClaudeDir(), "projects")
+}
+
+// SetExtraRoots adds Claude data directories that session discovery scans
+// after ClaudeDir(), e.g. other OS users' or mounted machines' homes (the
+// town's claude_roots setting). It replaces any earlier extra roots.
+func SetExtraRoots(dirs []string) {
+	rootMu.Lock()
+	extraRoots = append([]string(nil), dirs...)
+	rootMu.Unlock()
+}
+
+// Roots returns the Claude data directories sessions are discovered in:
+// ClaudeDir(), then the extra roots.
+func Roots() []string {
+	rootMu.RLock()
+	extra := extraRoots
+	rootMu.RUnlock()
+	return append([]string{ClaudeDir()}, extra...)
+}
+
+// resolveRoots cleans roots and drops duplicates. A root with no
+// projects directory of its own but a .claude one is taken to be a home
+// directory and replaced by its .claude directory.
+func resolveRoots(roots []string) []string {
+	seen := make(map[string]bool, len(roots))
+	var out []string
+	for _, root := range roots {
+		root = filepath.Clean(root)
+		if _, err := os.Stat(filepath.Join(root, "projects")); err != nil {
+			if _, err := os.Stat(filepath.Join(root, ".claude", "projects")); err == nil {
+				root = filepath.Join(root, ".claude")
+			}
+		}
+		if !seen[root] {
+			seen[root] = true
+			out = append(out, root)
+		}
+	}
+	return out
+}
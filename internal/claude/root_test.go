@@ -0,0 +1,159 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Tests locate Claude data under a temporary HOME; don't let the
+	// developer's own relocation settings leak in.
+	for _, env := range []string{ConfigDirEnv, "XDG_CONFIG_HOME", "XDG_DATA_HOME"} {
+		_ = os.Unsetenv(env)
+	}
+	os.Exit(m.Run())
+}
+
+func TestClaudeDirResolution(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// Nothing on disk: the conventional location.
+	if got, want := ClaudeDir(), filepath.Join(home, ".claude"); got != want {
+		t.Errorf("default ClaudeDir = %s, want %s", got, want)
+	}
+
+	// XDG layout with transcripts is found.
+	dataHome := filepath.Join(home, "data")
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	if err := os.MkdirAll(filepath.Join(dataHome, "claude", "projects"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ClaudeDir(), filepath.Join(dataHome, "claude"); got != want {
+		t.Errorf("XDG ClaudeDir = %s, want %s", got, want)
+	}
+	if got, want := UserConfigPath(), filepath.Join(home, ".claude.json"); got != want {
+		t.Errorf("XDG UserConfigPath = %s, want %s", got, want)
+	}
+
+	// CLAUDE_CONFIG_DIR wins over discovery, and holds .claude.json.
+	relocated := filepath.Join(home, "relocated")
+	t.Setenv(ConfigDirEnv, relocated)
+	if got := ClaudeDir(); got != relocated {
+		t.Errorf("CLAUDE_CONFIG_DIR ClaudeDir = %s, want %s", got, relocated)
+	}
+	if got, want := UserConfigPath(), filepath.Join(relocated, ".claude.json"); got != want {
+		t.Errorf("CLAUDE_CONFIG_DIR UserConfigPath = %s, want %s", got, want)
+	}
+
+	// WithRoot wins over everything until restored.
+	explicit := filepath.Join(home, "explicit")
+	restore := WithRoot(explicit)
+	if got := ProjectsDir(); got != filepath.Join(explicit, "projects") {
+		t.Errorf("WithRoot ProjectsDir = %s", got)
+	}
+	restore()
+	if got := ClaudeDir(); got != relocated {
+		t.Errorf("after restore ClaudeDir = %s, want %s", got, relocated)
+	}
+}
+
+func TestClaudeDirWindowsHome(t *testing.T) {
+	profile := t.TempDir()
+	t.Setenv("HOME", "")
+	t.Setenv("USERPROFILE", profile)
+
+	if got, want := homeDir(), profile; got != want {
+		t.Errorf("homeDir = %s, want %%USERPROFILE%% %s", got, want)
+	}
+	if got, want := UserConfigPath(), filepath.Join(profile, ".claude.json"); got != want {
+		t.Errorf("UserConfigPath = %s, want %s", got, want)
+	}
+}
+
+func TestClaudeDirWSL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	mnt := t.TempDir()
+	prev := wslMountRoot
+	wslMountRoot = mnt
+	defer func() { wslMountRoot = prev }()
+
+	windows := filepath.Join(mnt, "c", "Users", "x", ".claude")
+	if err := os.MkdirAll(filepath.Join(windows, "projects"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("WSL_DISTRO_NAME", "")
+	if got := ClaudeDir(); got == windows {
+		t.Errorf("outside WSL, ClaudeDir should not look under %s", mnt)
+	}
+	t.Setenv("WSL_DISTRO_NAME", "Ubuntu")
+	if got := ClaudeDir(); got != windows {
+		t.Errorf("WSL ClaudeDir = %s, want %s", got, windows)
+	}
+}
+
+func TestDiscoverSessionsWithRoot(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	root := t.TempDir()
+	defer WithRoot(filepath.Join(root, ".claude"))()
+
+	writeSession(t, root, "-home-u-proj", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":"hello"}}`,
+	)
+	sessions, err := DiscoverSessions(SessionFilter{})
+	if err != nil || len(sessions) != 1 || sessions[0].ID != "aaaa1111" {
+		t.Fatalf("DiscoverSessions = %+v, %v", sessions, err)
+	}
+	if s, err := FindSession("aaaa"); err != nil || s.ID != "aaaa1111" {
+		t.Errorf("FindSession = %+v, %v", s, err)
+	}
+}
+
+func TestDiscoverSessionsAcrossRoots(t *testing.T) {
+	home := t.TempDir()
+	other := t.TempDir() // another user's home
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	writeSession(t, home, "-home-me-proj", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-12-30T09:00:00Z","message":{"role":"user","content":"mine"}}`,
+	)
+	writeSession(t, other, "-home-ci-proj", "bbbb2222.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":"theirs"}}`,
+	)
+
+	sessions, err := DiscoverSessions(SessionFilter{Roots: []string{filepath.Join(home, ".claude"), other, other}})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != "bbbb2222" || sessions[1].ID != "aaaa1111" {
+		t.Fatalf("sessions = %+v, want both roots merged, newest first", sessions)
+	}
+	if got, want := sessions[0].Root, filepath.Join(other, ".claude"); got != want {
+		t.Errorf("Root = %s, want %s (home directory resolved to its .claude)", got, want)
+	}
+	if got, want := sessions[1].Root, filepath.Join(home, ".claude"); got != want {
+		t.Errorf("Root = %s, want %s", got, want)
+	}
+
+	// Scanning one root leaves the other's index entries alone.
+	if _, err := DiscoverSessions(SessionFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	if stats := SessionIndexInfo(); stats.Entries != 2 {
+		t.Errorf("index has %d entries after a single-root scan, want 2", stats.Entries)
+	}
+
+	// Extra roots apply to discovery and lookup by default.
+	SetExtraRoots([]string{other})
+	defer SetExtraRoots(nil)
+	if sessions, err := DiscoverSessions(SessionFilter{}); err != nil || len(sessions) != 2 {
+		t.Errorf("DiscoverSessions with extra roots = %d sessions, %v", len(sessions), err)
+	}
+	if s, err := FindSession("bbbb"); err != nil || s.Root != filepath.Join(other, ".claude") {
+		t.Errorf("FindSession = %+v, %v", s, err)
+	}
+}
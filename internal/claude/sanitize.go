@@ -0,0 +1,266 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// sanitizeIDKeys hold identifiers that are replaced with stable placeholders.
+// The same input always maps to the same placeholder, so links such as
+// parentUuid -> uuid and tool_use_id -> id survive sanitizing.
+var sanitizeIDKeys = map[string]bool{
+	"uuid":        true,
+	"parentUuid":  true,
+	"leafUuid":    true,
+	"sessionId":   true,
+	"requestId":   true,
+	"id":          true,
+	"tool_use_id": true,
+	"gitBranch":   true,
+}
+
+// sanitizePathKeys hold filesystem paths.
+var sanitizePathKeys = map[string]bool{
+	"cwd":            true,
+	"file_path":      true,
+	"path":           true,
+	"notebook_path":  true,
+	"filePath":       true,
+	"transcriptPath": true,
+}
+
+// sanitizeKeepKeys hold structural strings that carry no user content.
+var sanitizeKeepKeys = map[string]bool{
+	"type":          true,
+	"role":          true,
+	"name":          true,
+	"model":         true,
+	"timestamp":     true,
+	"stop_reason":   true,
+	"version":       true,
+	"userType":      true,
+	"subtype":       true,
+	"level":         true,
+	"service_tier":  true,
+	"media_type":    true,
+	"stop_sequence": true,
+}
+
+var (
+	// sanitizePathPattern matches absolute or home-relative paths with at
+	// least two components inside free text.
+	sanitizePathPattern = regexp.MustCompile(`(?:~|\.{1,2})?/[A-Za-z0-9._@\-]+(?:/[A-Za-z0-9._@\-]+)+/?`)
+
+	// sanitizeFencePattern matches fenced code blocks in free text.
+	sanitizeFencePattern = regexp.MustCompile("(?s)```[^\n]*\n.*?```")
+
+	// sanitizeUUIDPattern matches UUID-shaped identifiers.
+	sanitizeUUIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// SanitizeResult reports what a sanitizing pass replaced.
+type SanitizeResult struct {
+	Path       string `json:"path"`
+	Out        string `json:"out"`
+	Lines      int    `json:"lines"`   // lines written
+	Skipped    int    `json:"skipped"` // lines that weren't valid JSON
+	IDs        int    `json:"ids"`     // distinct identifiers replaced
+	Paths      int    `json:"paths"`   // distinct paths replaced
+	CodeBlocks int    `json:"code"`    // distinct code contents replaced
+	Secrets    int    `json:"secrets"` // secret matches redacted from text
+}
+
+// sanitizer holds the placeholder mappings for one transcript.
+type sanitizer struct {
+	ids     map[string]string
+	paths   map[string]string
+	code    map[string]string
+	secrets []RedactRule
+	result  *SanitizeResult
+}
+
+// SanitizeSession writes an anonymized copy of a session transcript to dest,
+// for sharing in bug reports. Identifiers, paths, and code (tool inputs,
+// tool results, and fenced blocks in messages) become stable placeholders;
+// secrets in the remaining text are redacted. Line structure, JSON shape,
+// timestamps, models, tool names, and token usage are preserved. Lines that
+// aren't valid JSON are dropped rather than copied.
+func SanitizeSession(path, dest string) (*SanitizeResult, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := RedactRules("secrets")
+	if err != nil {
+		return nil, err
+	}
+	s := &sanitizer{
+		ids:     make(map[string]string),
+		paths:   make(map[string]string),
+		code:    make(map[string]string),
+		secrets: secrets,
+		result:  &SanitizeResult{Path: path, Out: dest},
+	}
+
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		sanitized, err := s.line(line)
+		if err != nil {
+			s.result.Skipped++
+			continue
+		}
+		out.Write(sanitized)
+		out.WriteByte('\n')
+		s.result.Lines++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	s.result.IDs = len(s.ids)
+	s.result.Paths = len(s.paths)
+	s.result.CodeBlocks = len(s.code)
+
+	if err := util.AtomicWriteFile(dest, out.Bytes(), 0600); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", dest, err)
+	}
+	return s.result, nil
+}
+
+// line sanitizes one JSONL entry.
+func (s *sanitizer) line(line []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber() // Keep token counts and other numbers exact
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(s.value(v, "", false))
+}
+
+// value sanitizes v, found under key. Strings inside code (tool inputs and
+// results) are replaced wholesale; other strings are treated as prose.
+func (s *sanitizer) value(v interface{}, key string, inCode bool) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		blockType, _ := val["type"].(string)
+		// Visit keys in order so placeholder numbering is deterministic
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := val[k]
+			childInCode := inCode ||
+				(k == "input" && blockType == "tool_use") ||
+				(k == "content" && blockType == "tool_result") ||
+				k == "toolUseResult"
+			val[k] = s.value(child, k, childInCode)
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = s.value(child, key, inCode)
+		}
+		return val
+	case string:
+		switch {
+		case val == "":
+			return val
+		case sanitizeIDKeys[key]:
+			return s.id(val)
+		case sanitizePathKeys[key]:
+			return s.path(val)
+		case sanitizeKeepKeys[key]:
+			return val
+		case inCode:
+			return s.codeBlock(val)
+		default:
+			return s.text(val)
+		}
+	default:
+		return v
+	}
+}
+
+// text sanitizes prose: fenced code, paths, and secrets are replaced.
+func (s *sanitizer) text(text string) string {
+	text = sanitizeFencePattern.ReplaceAllStringFunc(text, func(block string) string {
+		return "```\n" + s.codeBlock(block) + "\n```"
+	})
+	text = sanitizePathPattern.ReplaceAllStringFunc(text, s.path)
+	for _, rule := range s.secrets {
+		n := len(rule.Pattern.FindAllStringIndex(text, -1))
+		if n == 0 {
+			continue
+		}
+		text = rule.Pattern.ReplaceAllString(text, "[REDACTED:"+rule.Name+"]")
+		s.result.Secrets += n
+	}
+	return text
+}
+
+// id returns the placeholder for an identifier. UUIDs stay UUID-shaped and
+// short alphabetic prefixes (msg_, toolu_) are kept.
+func (s *sanitizer) id(id string) string {
+	if placeholder, ok := s.ids[id]; ok {
+		return placeholder
+	}
+	n := len(s.ids) + 1
+	var placeholder string
+	if sanitizeUUIDPattern.MatchString(id) {
+		placeholder = fmt.Sprintf("00000000-0000-4000-8000-%012d", n)
+	} else {
+		prefix := ""
+		if i := strings.Index(id, "_"); i > 0 && i <= 8 && strings.IndexFunc(id[:i], isNotLower) < 0 {
+			prefix = id[:i+1]
+		}
+		placeholder = fmt.Sprintf("%sid-%d", prefix, n)
+	}
+	s.ids[id] = placeholder
+	return placeholder
+}
+
+// path returns the placeholder for a path, keeping its extension.
+func (s *sanitizer) path(path string) string {
+	if placeholder, ok := s.paths[path]; ok {
+		return placeholder
+	}
+	placeholder := fmt.Sprintf("/sanitized/path-%d%s", len(s.paths)+1, filepath.Ext(strings.TrimSuffix(path, "/")))
+	s.paths[path] = placeholder
+	return placeholder
+}
+
+// codeBlock returns the placeholder for code or tool output, noting only
+// its line count.
+func (s *sanitizer) codeBlock(code string) string {
+	if placeholder, ok := s.code[code]; ok {
+		return placeholder
+	}
+	lines := strings.Count(strings.TrimRight(code, "\n"), "\n") + 1
+	placeholder := fmt.Sprintf("[code-%d: %d lines]", len(s.code)+1, lines)
+	s.code[code] = placeholder
+	return placeholder
+}
+
+// isNotLower reports whether r is outside a-z.
+func isNotLower(r rune) bool {
+	return r < 'a' || r > 'z'
+}
@@ -0,0 +1,77 @@
+package claude
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeSession(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "session.jsonl")
+	lines := []string{
+		`{"type":"user","uuid":"11111111-2222-4333-8444-555555555555","sessionId":"abc","cwd":"/home/alice/acme/payments","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"Fix /home/alice/acme/payments/ledger.go, key sk-ant-REDACTED"}}`,
+		`{"type":"assistant","uuid":"66666666-7777-4888-8999-000000000000","parentUuid":"11111111-2222-4333-8444-555555555555","sessionId":"abc","message":{"id":"msg_01XYZ","model":"claude-sonnet-4","content":[{"type":"text","text":"Here:\n` + "```go\\nfunc secret() {}\\n```" + `"},{"type":"tool_use","id":"toolu_01ABC","name":"Edit","input":{"file_path":"/home/alice/acme/payments/ledger.go","old_string":"func a() {}","new_string":"func b() {}"}}],"usage":{"input_tokens":1234,"output_tokens":56}}}`,
+		`{"type":"user","uuid":"aaaaaaaa-bbbb-4ccc-8ddd-eeeeeeeeeeee","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_01ABC","content":"package ledger\nfunc b() {}\n"}]}}`,
+		`not json`,
+	}
+	if err := os.WriteFile(src, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(dir, "out.jsonl")
+	result, err := SanitizeSession(src, dest)
+	if err != nil {
+		t.Fatalf("SanitizeSession: %v", err)
+	}
+	if result.Lines != 3 || result.Skipped != 1 {
+		t.Errorf("Lines=%d Skipped=%d, want 3 and 1", result.Lines, result.Skipped)
+	}
+	if result.Secrets != 1 {
+		t.Errorf("Secrets = %d, want 1", result.Secrets)
+	}
+
+	data, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, leaked := range []string{"alice", "acme", "ledger", "func a", "func b", "func secret", "sk-ant-", "msg_01XYZ", "toolu_01ABC", "11111111-2222"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("output still contains %q:\n%s", leaked, out)
+		}
+	}
+	for _, kept := range []string{`"model":"claude-sonnet-4"`, `"name":"Edit"`, `"input_tokens":1234`, `"timestamp":"2025-01-01T00:00:00Z"`, "Fix "} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("output lost %q:\n%s", kept, out)
+		}
+	}
+
+	// Links between entries survive: parentUuid matches the first uuid and
+	// tool_use_id matches the tool_use id.
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		var e map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("output line is not JSON: %v", err)
+		}
+		entries = append(entries, e)
+	}
+	if entries[0]["uuid"] != entries[1]["parentUuid"] {
+		t.Errorf("uuid %v != parentUuid %v", entries[0]["uuid"], entries[1]["parentUuid"])
+	}
+	toolUse := entries[1]["message"].(map[string]interface{})["content"].([]interface{})[1].(map[string]interface{})
+	toolResult := entries[2]["message"].(map[string]interface{})["content"].([]interface{})[0].(map[string]interface{})
+	if toolUse["id"] != toolResult["tool_use_id"] {
+		t.Errorf("tool_use id %v != tool_use_id %v", toolUse["id"], toolResult["tool_use_id"])
+	}
+	if !strings.HasPrefix(toolUse["id"].(string), "toolu_") {
+		t.Errorf("tool id = %v, want toolu_ prefix kept", toolUse["id"])
+	}
+	input := toolUse["input"].(map[string]interface{})
+	if input["file_path"] != "/sanitized/path-2.go" {
+		t.Errorf("file_path = %v, want /sanitized/path-2.go", input["file_path"])
+	}
+}
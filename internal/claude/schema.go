@@ -0,0 +1,468 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Claude Code's JSONL format drifts between versions: fields are added,
+// renamed (parentUuid was parent_uuid in some builds), and timestamps have
+// been written as epoch numbers as well as RFC 3339 strings. Every reader
+// in this package decodes lines through decodeEntry, which accepts each
+// known spelling and leaves anything it doesn't recognize alone, so a new
+// Claude Code release degrades one field at a time instead of dropping
+// whole lines.
+
+// SchemaVariant names one way a transcript line can be written.
+type SchemaVariant string
+
+// Schema variants reported by ReadTranscriptSchema.
+const (
+	// VariantCamelCase lines use the field names current Claude Code
+	// writes (parentUuid, sessionId, gitBranch).
+	VariantCamelCase SchemaVariant = "camelCase"
+
+	// VariantSnakeCase lines use snake_case names for the same fields
+	// (parent_uuid, session_id, git_branch).
+	VariantSnakeCase SchemaVariant = "snake_case"
+
+	// VariantEpochTime lines carry a numeric timestamp (epoch seconds or
+	// milliseconds) instead of an RFC 3339 string.
+	VariantEpochTime SchemaVariant = "epoch_time"
+
+	// VariantBlockContent lines are system entries whose content is a
+	// block array rather than a string.
+	VariantBlockContent SchemaVariant = "block_content"
+)
+
+// knownEntryTypes are the entry types Gas Town understands or knowingly
+// ignores. Entries of any other type are kept raw; see RawEntry.
+var knownEntryTypes = map[string]bool{
+	"user":                  true,
+	"assistant":             true,
+	"system":                true,
+	"summary":               true,
+	"file-history-snapshot": true,
+	"queue-operation":       true,
+}
+
+// knownFields are the top-level fields decodeEntry reads or knowingly
+// ignores. Others are counted as unknown by ReadTranscriptSchema.
+var knownFields = map[string]bool{
+	"type": true, "subtype": true, "uuid": true, "parentUuid": true, "parent_uuid": true,
+	"logicalParentUuid": true, "sessionId": true, "session_id": true, "timestamp": true,
+	"version": true, "cwd": true, "gitBranch": true, "git_branch": true,
+	"isSidechain": true, "is_sidechain": true, "isCompactSummary": true, "is_compact_summary": true,
+	"isMeta": true, "isVisibleInTranscriptOnly": true, "isApiErrorMessage": true,
+	"userType": true, "requestId": true, "summary": true, "leafUuid": true, "content": true,
+	"level": true, "message": true, "toolUseResult": true, "tool_use_result": true,
+	"toolUseID": true, "compactMetadata": true, "compact_metadata": true,
+	"thinkingMetadata": true, "todos": true, "agentId": true, "slug": true,
+	"messageId": true, "snapshot": true, "isSnapshotUpdate": true, "operation": true,
+}
+
+// logEntry is one transcript line with its field names and formats
+// normalized. Message holds the API message body as written; use body to
+// decode it.
+type logEntry struct {
+	Type             string
+	Subtype          string
+	UUID             string
+	ParentUUID       string
+	SessionID        string
+	Version          string // Claude Code version that wrote the line
+	Cwd              string
+	GitBranch        string
+	Summary          string
+	Content          string // system entries
+	Timestamp        time.Time
+	IsSidechain      bool
+	IsCompactSummary bool
+	Message          json.RawMessage
+	ToolUseResult    json.RawMessage
+	CompactMetadata  *compactMetadata
+
+	// variants lists how the line was written, for ReadTranscriptSchema.
+	variants []SchemaVariant
+}
+
+// compactMetadata is what a compact_boundary entry records.
+type compactMetadata struct {
+	Trigger   string
+	PreTokens int64
+}
+
+// wireEntry is a transcript line as written, with every known spelling
+// of each field.
+type wireEntry struct {
+	Type      string          `json:"type"`
+	Subtype   string          `json:"subtype"`
+	UUID      string          `json:"uuid"`
+	Version   string          `json:"version"`
+	Cwd       string          `json:"cwd"`
+	Summary   string          `json:"summary"`
+	Timestamp wireTime        `json:"timestamp"`
+	Content   json.RawMessage `json:"content"`
+	Message   json.RawMessage `json:"message"`
+
+	ParentUUID       string          `json:"parentUuid"`
+	SessionID        string          `json:"sessionId"`
+	GitBranch        string          `json:"gitBranch"`
+	IsSidechain      bool            `json:"isSidechain"`
+	IsCompactSummary bool            `json:"isCompactSummary"`
+	ToolUseResult    json.RawMessage `json:"toolUseResult"`
+	CompactMetadata  *wireCompact    `json:"compactMetadata"`
+
+	ParentUUIDSnake       string          `json:"parent_uuid"`
+	SessionIDSnake        string          `json:"session_id"`
+	GitBranchSnake        string          `json:"git_branch"`
+	IsSidechainSnake      bool            `json:"is_sidechain"`
+	IsCompactSummarySnake bool            `json:"is_compact_summary"`
+	ToolUseResultSnake    json.RawMessage `json:"tool_use_result"`
+	CompactMetadataSnake  *wireCompact    `json:"compact_metadata"`
+}
+
+type wireCompact struct {
+	Trigger        string `json:"trigger"`
+	PreTokens      int64  `json:"preTokens"`
+	PreTokensSnake int64  `json:"pre_tokens"`
+}
+
+// wireTime is a timestamp written as an RFC 3339 string or as epoch
+// seconds or milliseconds. Values it can't read decode as the zero time.
+type wireTime struct {
+	time.Time
+	epoch bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *wireTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		t.Time, _ = time.Parse(time.RFC3339, s)
+		return nil
+	}
+	var n float64
+	if json.Unmarshal(data, &n) == nil && n > 0 {
+		if n >= 1e12 {
+			t.Time = time.UnixMilli(int64(n)).UTC()
+		} else {
+			t.Time = time.Unix(int64(n), 0).UTC()
+		}
+		t.epoch = true
+	}
+	return nil
+}
+
+// decodeEntry decodes one transcript line. It fails only on lines that
+// aren't a JSON object.
+func decodeEntry(line []byte) (logEntry, error) {
+	var w wireEntry
+	if err := json.Unmarshal(line, &w); err != nil {
+		return logEntry{}, err
+	}
+	e := logEntry{
+		Type:             w.Type,
+		Subtype:          w.Subtype,
+		UUID:             w.UUID,
+		ParentUUID:       firstNonEmpty(w.ParentUUID, w.ParentUUIDSnake),
+		SessionID:        firstNonEmpty(w.SessionID, w.SessionIDSnake),
+		Version:          w.Version,
+		Cwd:              w.Cwd,
+		GitBranch:        firstNonEmpty(w.GitBranch, w.GitBranchSnake),
+		Summary:          w.Summary,
+		Timestamp:        w.Timestamp.Time,
+		IsSidechain:      w.IsSidechain || w.IsSidechainSnake,
+		IsCompactSummary: w.IsCompactSummary || w.IsCompactSummarySnake,
+		Message:          w.Message,
+		ToolUseResult:    w.ToolUseResult,
+	}
+	if len(e.ToolUseResult) == 0 {
+		e.ToolUseResult = w.ToolUseResultSnake
+	}
+	md := w.CompactMetadata
+	if md == nil {
+		md = w.CompactMetadataSnake
+	}
+	if md != nil {
+		e.CompactMetadata = &compactMetadata{Trigger: md.Trigger, PreTokens: md.PreTokens}
+		if e.CompactMetadata.PreTokens == 0 {
+			e.CompactMetadata.PreTokens = md.PreTokensSnake
+		}
+	}
+
+	camel := w.ParentUUID != "" || w.SessionID != "" || w.GitBranch != "" || w.IsSidechain ||
+		w.IsCompactSummary || len(w.ToolUseResult) > 0 || w.CompactMetadata != nil
+	snake := w.ParentUUIDSnake != "" || w.SessionIDSnake != "" || w.GitBranchSnake != "" || w.IsSidechainSnake ||
+		w.IsCompactSummarySnake || len(w.ToolUseResultSnake) > 0 || w.CompactMetadataSnake != nil
+	if camel {
+		e.variants = append(e.variants, VariantCamelCase)
+	}
+	if snake {
+		e.variants = append(e.variants, VariantSnakeCase)
+	}
+	if w.Timestamp.epoch {
+		e.variants = append(e.variants, VariantEpochTime)
+	}
+
+	if len(w.Content) > 0 {
+		var s string
+		if json.Unmarshal(w.Content, &s) == nil {
+			e.Content = s
+		} else if text := blockText(w.Content); text != "" {
+			e.Content = text
+			e.variants = append(e.variants, VariantBlockContent)
+		}
+	}
+	return e, nil
+}
+
+// known reports whether the entry's type is one Gas Town recognizes.
+func (e *logEntry) known() bool {
+	return knownEntryTypes[e.Type]
+}
+
+// entryBody is the API message of a user or assistant entry.
+type entryBody struct {
+	ID      string          `json:"id"`
+	Role    string          `json:"role"`
+	Model   string          `json:"model"`
+	Content json.RawMessage `json:"content"`
+	Usage   *apiUsage       `json:"usage"`
+}
+
+// body decodes the entry's message, or returns the zero body.
+func (e *logEntry) body() entryBody {
+	var b entryBody
+	if len(e.Message) > 0 {
+		_ = json.Unmarshal(e.Message, &b)
+	}
+	return b
+}
+
+// apiUsage is the token usage of an API message, in either the API's
+// snake_case or the camelCase some Claude Code builds wrote.
+type apiUsage struct {
+	InputTokens              int64
+	OutputTokens             int64
+	CacheCreationInputTokens int64
+	CacheReadInputTokens     int64
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (u *apiUsage) UnmarshalJSON(data []byte) error {
+	var w struct {
+		InputTokens              int64 `json:"input_tokens"`
+		OutputTokens             int64 `json:"output_tokens"`
+		CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+
+		InputTokensCamel              int64 `json:"inputTokens"`
+		OutputTokensCamel             int64 `json:"outputTokens"`
+		CacheCreationInputTokensCamel int64 `json:"cacheCreationInputTokens"`
+		CacheReadInputTokensCamel     int64 `json:"cacheReadInputTokens"`
+	}
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	*u = apiUsage{
+		InputTokens:              w.InputTokens + w.InputTokensCamel,
+		OutputTokens:             w.OutputTokens + w.OutputTokensCamel,
+		CacheCreationInputTokens: w.CacheCreationInputTokens + w.CacheCreationInputTokensCamel,
+		CacheReadInputTokens:     w.CacheReadInputTokens + w.CacheReadInputTokensCamel,
+	}
+	return nil
+}
+
+// contextTokens returns the prompt size of the request the usage is for.
+func (u *apiUsage) contextTokens() int64 {
+	return u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+}
+
+// blockText joins the text blocks of a content block array.
+func blockText(raw json.RawMessage) string {
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" && b.Text != "" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// RawEntry is a transcript line of a type Gas Town doesn't recognize,
+// kept verbatim so exports don't lose it.
+type RawEntry struct {
+	Line int             `json:"line"` // 1-based line in the JSONL
+	Type string          `json:"type"`
+	Raw  json.RawMessage `json:"raw"`
+}
+
+// SchemaReport describes the transcript formats found in one or more
+// transcripts: which Claude Code versions wrote them, which schema
+// variants they use, and what the decoder didn't recognize.
+type SchemaReport struct {
+	Transcripts int `json:"transcripts"`
+	Lines       int `json:"lines"`
+	Invalid     int `json:"invalid,omitempty"` // lines that weren't JSON objects
+
+	Versions      map[string]int        `json:"versions,omitempty"`       // Claude Code version -> lines
+	Variants      map[SchemaVariant]int `json:"variants,omitempty"`       // variant -> lines
+	UnknownTypes  map[string]int        `json:"unknown_types,omitempty"`  // entry type -> lines
+	UnknownFields map[string]int        `json:"unknown_fields,omitempty"` // "type.field" -> lines
+
+	// Unknown holds the unrecognized entries themselves. It is filled by
+	// ReadTranscriptSchema and left out by Merge.
+	Unknown []RawEntry `json:"unknown,omitempty"`
+}
+
+// NewSchemaReport returns an empty report.
+func NewSchemaReport() *SchemaReport {
+	return &SchemaReport{
+		Versions:      make(map[string]int),
+		Variants:      make(map[SchemaVariant]int),
+		UnknownTypes:  make(map[string]int),
+		UnknownFields: make(map[string]int),
+	}
+}
+
+// Merge adds other's counts to r.
+func (r *SchemaReport) Merge(other *SchemaReport) {
+	r.Transcripts += other.Transcripts
+	r.Lines += other.Lines
+	r.Invalid += other.Invalid
+	for k, n := range other.Versions {
+		r.Versions[k] += n
+	}
+	for k, n := range other.Variants {
+		r.Variants[k] += n
+	}
+	for k, n := range other.UnknownTypes {
+		r.UnknownTypes[k] += n
+	}
+	for k, n := range other.UnknownFields {
+		r.UnknownFields[k] += n
+	}
+}
+
+// Clean reports whether every line was decoded in full: no invalid
+// lines, unknown entry types, or unknown fields.
+func (r *SchemaReport) Clean() bool {
+	return r.Invalid == 0 && len(r.UnknownTypes) == 0 && len(r.UnknownFields) == 0
+}
+
+// VersionNames returns the Claude Code versions seen, oldest first.
+func (r *SchemaReport) VersionNames() []string {
+	names := make([]string, 0, len(r.Versions))
+	for v := range r.Versions {
+		names = append(names, v)
+	}
+	sort.Slice(names, func(i, j int) bool { return compareVersions(names[i], names[j]) < 0 })
+	return names
+}
+
+// compareVersions orders dotted version strings numerically, falling
+// back to string order for parts that aren't numbers.
+func compareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := parseVersionPart(pa[i])
+		nb, errB := parseVersionPart(pb[i])
+		switch {
+		case errA || errB:
+			if c := strings.Compare(pa[i], pb[i]); c != 0 {
+				return c
+			}
+		case na != nb:
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(pa) - len(pb)
+}
+
+func parseVersionPart(s string) (int, bool) {
+	if s == "" {
+		return 0, true
+	}
+	n := 0
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, true
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, false
+}
+
+// ReadTranscriptSchema reads the transcript at path and reports the
+// schema variants it uses, keeping entries of unknown types verbatim.
+func ReadTranscriptSchema(path string) (*SchemaReport, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := NewSchemaReport()
+	r.Transcripts = 1
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		r.Lines++
+		entry, err := decodeEntry(line)
+		if err != nil {
+			r.Invalid++
+			continue
+		}
+		if entry.Version != "" {
+			r.Versions[entry.Version]++
+		}
+		for _, v := range entry.variants {
+			r.Variants[v]++
+		}
+		if !entry.known() {
+			r.UnknownTypes[entry.Type]++
+			r.Unknown = append(r.Unknown, RawEntry{Line: lineNum, Type: entry.Type, Raw: append(json.RawMessage(nil), line...)})
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(line, &fields) != nil {
+			continue
+		}
+		for name := range fields {
+			if !knownFields[name] {
+				r.UnknownFields[entry.Type+"."+name]++
+			}
+		}
+	}
+	return r, scanner.Err()
+}
@@ -0,0 +1,171 @@
+package claude
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeEntryVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		parent   string
+		ts       time.Time
+		variants []SchemaVariant
+	}{
+		{
+			name:     "camelCase",
+			line:     `{"type":"user","parentUuid":"p1","sessionId":"s1","timestamp":"2025-01-01T00:00:00Z"}`,
+			parent:   "p1",
+			ts:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			variants: []SchemaVariant{VariantCamelCase},
+		},
+		{
+			name:     "snake_case with epoch seconds",
+			line:     `{"type":"user","parent_uuid":"p2","session_id":"s1","timestamp":1735689600}`,
+			parent:   "p2",
+			ts:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			variants: []SchemaVariant{VariantSnakeCase, VariantEpochTime},
+		},
+		{
+			name:     "epoch milliseconds",
+			line:     `{"type":"user","timestamp":1735689600500}`,
+			ts:       time.Date(2025, 1, 1, 0, 0, 0, 500e6, time.UTC),
+			variants: []SchemaVariant{VariantEpochTime},
+		},
+		{
+			name: "unreadable timestamp",
+			line: `{"type":"user","timestamp":"yesterday"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := decodeEntry([]byte(tt.line))
+			if err != nil {
+				t.Fatalf("decodeEntry: %v", err)
+			}
+			if e.ParentUUID != tt.parent {
+				t.Errorf("ParentUUID = %q, want %q", e.ParentUUID, tt.parent)
+			}
+			if !e.Timestamp.Equal(tt.ts) {
+				t.Errorf("Timestamp = %v, want %v", e.Timestamp, tt.ts)
+			}
+			if len(e.variants) != len(tt.variants) {
+				t.Fatalf("variants = %v, want %v", e.variants, tt.variants)
+			}
+			for i := range tt.variants {
+				if e.variants[i] != tt.variants[i] {
+					t.Errorf("variants = %v, want %v", e.variants, tt.variants)
+				}
+			}
+		})
+	}
+
+	if _, err := decodeEntry([]byte(`{"type":"user"`)); err == nil {
+		t.Error("truncated line decoded")
+	}
+}
+
+func TestDecodeEntryUsageAndContent(t *testing.T) {
+	e, err := decodeEntry([]byte(`{"type":"assistant","message":{"id":"m1","usage":{"inputTokens":10,"cacheReadInputTokens":5,"output_tokens":3}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := e.body().Usage
+	if u == nil || u.contextTokens() != 15 || u.OutputTokens != 3 {
+		t.Errorf("usage = %+v, want 15 context and 3 output tokens", u)
+	}
+
+	e, err = decodeEntry([]byte(`{"type":"system","content":[{"type":"text","text":"Conversation compacted"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Content != "Conversation compacted" || len(e.variants) != 1 || e.variants[0] != VariantBlockContent {
+		t.Errorf("content = %q, variants = %v", e.Content, e.variants)
+	}
+}
+
+func TestReadTranscriptSchema(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(sessionIndexEnv, "off")
+	path := writeSession(t, home, "-home-u-proj", "abcd1234.jsonl",
+		`{"type":"user","version":"1.0.98","sessionId":"abcd1234","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"hi"}}`,
+		`{"type":"assistant","version":"1.0.100","session_id":"abcd1234","timestamp":1735689605,"mood":"curious","message":{"role":"assistant","content":"hello"}}`,
+		`{"type":"hologram","version":"1.0.100","frames":3}`,
+		`{"type":"user","message":`,
+	)
+
+	r, err := ReadTranscriptSchema(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Lines != 4 || r.Invalid != 1 {
+		t.Errorf("lines = %d, invalid = %d; want 4, 1", r.Lines, r.Invalid)
+	}
+	if got := r.VersionNames(); len(got) != 2 || got[0] != "1.0.98" || got[1] != "1.0.100" {
+		t.Errorf("versions = %v, want [1.0.98 1.0.100]", got)
+	}
+	if r.Variants[VariantCamelCase] != 1 || r.Variants[VariantSnakeCase] != 1 || r.Variants[VariantEpochTime] != 1 {
+		t.Errorf("variants = %v", r.Variants)
+	}
+	if r.UnknownTypes["hologram"] != 1 || r.UnknownFields["assistant.mood"] != 1 || len(r.UnknownFields) != 1 {
+		t.Errorf("unknown types = %v, fields = %v", r.UnknownTypes, r.UnknownFields)
+	}
+	if len(r.Unknown) != 1 || r.Unknown[0].Line != 3 || !strings.Contains(string(r.Unknown[0].Raw), `"frames":3`) {
+		t.Errorf("unknown = %+v", r.Unknown)
+	}
+	if r.Clean() {
+		t.Error("report with unknowns is clean")
+	}
+
+	total := NewSchemaReport()
+	total.Merge(r)
+	total.Merge(r)
+	if total.Transcripts != 2 || total.UnknownTypes["hologram"] != 2 || total.Unknown != nil {
+		t.Errorf("merged = %+v", total)
+	}
+
+	// The snake_case line still counts toward the session
+	info, err := parseSession(path, "-home-u-proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.AssistantMessages != 1 || info.ClaudeVersion != "1.0.100" || info.EndTime.Second() != 5 {
+		t.Errorf("info = %d replies, version %q, ends %v", info.AssistantMessages, info.ClaudeVersion, info.EndTime)
+	}
+}
+
+func TestExportKeepsUnknownEntries(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-home-u-proj", "abcd1234-5678.jsonl",
+		`{"type":"user","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"hi"}}`,
+		`{"type":"hologram","frames":3}`,
+	)
+
+	md, err := ExportTranscript("abcd", ExportMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(md), "## Unrecognized entries") || !strings.Contains(string(md), `"frames": 3`) {
+		t.Errorf("markdown lost the unknown entry:\n%s", md)
+	}
+	html, err := ExportTranscript("abcd", ExportHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(html), "line 2: hologram") {
+		t.Errorf("html lost the unknown entry:\n%s", html)
+	}
+
+	tr, err := LoadTranscript("abcd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.Messages) != 2 || tr.Messages[0].Raw != nil || tr.Messages[1].Raw == nil {
+		t.Errorf("messages = %+v, want the raw line kept only for the unknown type", tr.Messages)
+	}
+}
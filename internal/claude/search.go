@@ -0,0 +1,202 @@
+package claude
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fields of a message a search can match.
+const (
+	SearchText       = "text"        // user and assistant text, and summaries
+	SearchThinking   = "thinking"    // assistant thinking blocks
+	SearchToolInput  = "tool_input"  // tool_use inputs (commands, paths, ...)
+	SearchToolResult = "tool_result" // tool_result output
+)
+
+// SearchQuery is what SearchSessions looks for.
+type SearchQuery struct {
+	// Pattern is matched against each line of message content: as a
+	// literal substring, or as a regular expression when Regexp is set.
+	Pattern    string
+	Regexp     bool
+	IgnoreCase bool
+
+	// Fields restricts which content is searched. Empty means
+	// SearchText only.
+	Fields []string
+
+	// Context is how many lines around each matching line, within the
+	// same message, are returned with it.
+	Context int
+
+	// Limit caps the number of matches (0 = unlimited).
+	Limit int
+}
+
+// SearchMatch is one matching line of a session transcript.
+type SearchMatch struct {
+	Session   SessionInfo `json:"session"`
+	Line      int         `json:"line"` // 1-based JSONL line of the message
+	Type      string      `json:"type"` // user, assistant, summary, ...
+	Timestamp time.Time   `json:"timestamp,omitempty"`
+	Field     string      `json:"field"`          // SearchText, SearchToolInput, ...
+	Tool      string      `json:"tool,omitempty"` // tool name, for tool fields
+
+	// Text is the matching line; Before and After are up to
+	// SearchQuery.Context lines around it.
+	Text   string   `json:"text"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+}
+
+// SearchSessions searches the content of every session filter matches,
+// not just the headers discovery reads. Matches are ordered like
+// DiscoverSessions (most recent session first), then by position in the
+// transcript. filter.Limit bounds the sessions searched; query.Limit the
+// matches returned. If discovery times out, the sessions it found are
+// searched and the ErrIncomplete error returned with the matches.
+func SearchSessions(query SearchQuery, filter SessionFilter) ([]SearchMatch, error) {
+	return currentStore().Search(query, filter)
+}
+
+// searchSessionFiles is FileStore.Search.
+func searchSessionFiles(query SearchQuery, filter SessionFilter) ([]SearchMatch, error) {
+	match, err := query.matcher()
+	if err != nil {
+		return nil, err
+	}
+	fields := query.Fields
+	if len(fields) == 0 {
+		fields = []string{SearchText}
+	}
+	for _, f := range fields {
+		switch f {
+		case SearchText, SearchThinking, SearchToolInput, SearchToolResult:
+		default:
+			return nil, fmt.Errorf("unknown search field %q", f)
+		}
+	}
+
+	// Search what discovery found even if a slow filesystem cut it short
+	sessions, err := scanSessions(filter)
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return nil, err
+	}
+
+	results := make([][]SearchMatch, len(sessions))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := workerCount(filter.Workers, len(sessions)); w > 0; w-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = searchSession(sessions[i], match, fields, query)
+			}
+		}()
+	}
+	for i := range sessions {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var out []SearchMatch
+	for _, r := range results {
+		out = append(out, r...)
+		if query.Limit > 0 && len(out) >= query.Limit {
+			return out[:query.Limit], err
+		}
+	}
+	return out, err
+}
+
+// matcher compiles the query into a line predicate.
+func (q SearchQuery) matcher() (func(string) bool, error) {
+	if q.Pattern == "" {
+		return nil, fmt.Errorf("empty search pattern")
+	}
+	if q.Regexp {
+		expr := q.Pattern
+		if q.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search pattern: %w", err)
+		}
+		return re.MatchString, nil
+	}
+	if q.IgnoreCase {
+		pattern := strings.ToLower(q.Pattern)
+		return func(s string) bool { return strings.Contains(strings.ToLower(s), pattern) }, nil
+	}
+	return func(s string) bool { return strings.Contains(s, q.Pattern) }, nil
+}
+
+// searchSession returns the matches in one transcript. Unreadable
+// transcripts have none; they may have been removed since discovery.
+func searchSession(session SessionInfo, match func(string) bool, fields []string, query SearchQuery) []SearchMatch {
+	t, err := ReadTranscript(session.Path)
+	if err != nil {
+		return nil
+	}
+
+	tools := make(map[string]string) // tool_use ID -> tool name
+	for _, m := range t.Messages {
+		for _, use := range m.ToolUses {
+			tools[use.ID] = use.Name
+		}
+	}
+
+	var out []SearchMatch
+	for _, m := range t.Messages {
+		base := SearchMatch{Session: session, Line: m.Line, Type: m.Type, Timestamp: m.Timestamp}
+		for _, field := range fields {
+			switch field {
+			case SearchText:
+				out = appendLineMatches(out, base, field, "", m.Text, match, query.Context)
+			case SearchThinking:
+				out = appendLineMatches(out, base, field, "", m.Thinking, match, query.Context)
+			case SearchToolInput:
+				for _, use := range m.ToolUses {
+					out = appendLineMatches(out, base, field, use.Name, exportInput(use.Input), match, query.Context)
+				}
+			case SearchToolResult:
+				for _, result := range m.ToolResults {
+					out = appendLineMatches(out, base, field, tools[result.ToolUseID], result.Content, match, query.Context)
+				}
+			}
+		}
+		if query.Limit > 0 && len(out) >= query.Limit {
+			break
+		}
+	}
+	return out
+}
+
+// appendLineMatches appends a match for each line of content that
+// matches, with context lines from the same content.
+func appendLineMatches(out []SearchMatch, base SearchMatch, field, tool, content string, match func(string) bool, context int) []SearchMatch {
+	if content == "" {
+		return out
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if !match(line) {
+			continue
+		}
+		m := base
+		m.Field, m.Tool, m.Text = field, tool, line
+		if context > 0 {
+			m.Before = lines[max(0, i-context):i]
+			m.After = lines[i+1 : min(len(lines), i+1+context)]
+		}
+		out = append(out, m)
+	}
+	return out
+}
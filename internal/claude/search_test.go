@@ -0,0 +1,73 @@
+package claude
+
+import (
+	"testing"
+)
+
+func TestSearchSessions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	writeSession(t, home, "-home-u-proj", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"first\nfix the Auth bug\nthanks"}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:00:05Z","message":{"id":"m1","role":"assistant","content":[{"type":"text","text":"Looking."},{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"grep -r auth ."}}]}}`,
+		`{"type":"user","timestamp":"2025-01-01T00:00:06Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"auth.go:1: package auth"}]}}`,
+	)
+	writeSession(t, home, "-home-u-other", "bbbb2222.jsonl",
+		`{"type":"user","timestamp":"2025-02-01T00:00:00Z","message":{"role":"user","content":"auth again"}}`,
+	)
+
+	matches, err := SearchSessions(SearchQuery{Pattern: "auth", IgnoreCase: true, Context: 1}, SessionFilter{})
+	if err != nil {
+		t.Fatalf("SearchSessions: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches, want 2 (text only): %+v", len(matches), matches)
+	}
+	if matches[0].Session.ID != "bbbb2222" {
+		t.Errorf("most recent session should come first, got %s", matches[0].Session.ID)
+	}
+	m := matches[1]
+	if m.Text != "fix the Auth bug" || m.Line != 1 || m.Type != "user" || m.Field != SearchText {
+		t.Errorf("match = %+v", m)
+	}
+	if len(m.Before) != 1 || m.Before[0] != "first" || len(m.After) != 1 || m.After[0] != "thanks" {
+		t.Errorf("context = %q / %q", m.Before, m.After)
+	}
+
+	// Case-sensitive, tool fields
+	matches, err = SearchSessions(SearchQuery{
+		Pattern: "auth",
+		Fields:  []string{SearchToolInput, SearchToolResult},
+	}, SessionFilter{Path: "/home/u/proj"})
+	if err != nil {
+		t.Fatalf("SearchSessions: %v", err)
+	}
+	if len(matches) != 2 || matches[0].Field != SearchToolInput || matches[1].Field != SearchToolResult || matches[1].Tool != "Bash" {
+		t.Errorf("tool matches = %+v", matches)
+	}
+
+	matches, err = SearchSessions(SearchQuery{Pattern: `A\w+ bug`, Regexp: true, Limit: 5}, SessionFilter{})
+	if err != nil || len(matches) != 1 {
+		t.Errorf("regexp search = %+v, %v", matches, err)
+	}
+
+	matches, err = SearchSessions(SearchQuery{Pattern: "auth", IgnoreCase: true, Limit: 1}, SessionFilter{})
+	if err != nil || len(matches) != 1 {
+		t.Errorf("limited search = %+v, %v", matches, err)
+	}
+}
+
+func TestSearchSessionsInvalidQuery(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, q := range []SearchQuery{
+		{},
+		{Pattern: "(", Regexp: true},
+		{Pattern: "x", Fields: []string{"bogus"}},
+	} {
+		if _, err := SearchSessions(q, SessionFilter{}); err == nil {
+			t.Errorf("SearchSessions(%+v) accepted", q)
+		}
+	}
+}
@@ -0,0 +1,954 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/perf"
+)
+
+// SessionInfo describes a Claude Code session discovered on disk.
+type SessionInfo struct {
+	ID          string    `json:"id"`                // session UUID (JSONL filename)
+	Path        string    `json:"path"`              // path to the JSONL transcript
+	Project     string    `json:"project"`           // encoded project directory name
+	ProjectPath string    `json:"project_path"`      // working directory the session started in
+	StartTime   time.Time `json:"start_time"`        // first timestamp in the transcript
+	EndTime     time.Time `json:"end_time"`          // last timestamp in the transcript
+	Summary     string    `json:"summary,omitempty"` // summary line written by Claude Code, or a generated one
+	IsGasTown   bool      `json:"is_gastown"`        // session carries a [GAS TOWN] beacon
+	Role        string    `json:"role,omitempty"`    // beacon recipient (e.g., "gastown/crew/joe")
+	Topic       string    `json:"topic,omitempty"`   // beacon topic (e.g., "handoff", "assigned:gt-abc12")
+	Rig         string    `json:"rig,omitempty"`     // beacon rig (e.g., "gastown")
+	AgentName   string    `json:"agent,omitempty"`   // beacon agent name (e.g., "joe", "witness")
+	Bead        string    `json:"bead,omitempty"`    // bead the session was started on (e.g., "gt-abc12")
+	FileSize    int64     `json:"file_size"`         // transcript size in bytes
+	DiskSize    int64     `json:"disk_size"`         // bytes on disk: the transcript plus its <id>/ directory (subagents, tool output)
+	Tags        []string  `json:"tags,omitempty"`    // labels from the town's session_tags rules
+	Note        string    `json:"note,omitempty"`    // note added by hand (see SetSessionNote)
+	Root        string    `json:"root,omitempty"`    // Claude data directory the transcript was found in
+	Model       string    `json:"model,omitempty"`   // model of the last reply (e.g., "claude-sonnet-4-5-20250929")
+
+	// ClaudeVersion is the Claude Code version that wrote the session's
+	// last line, when recorded.
+	ClaudeVersion string `json:"claude_version,omitempty"`
+
+	// GitBranch is the git branch checked out at the session's last line,
+	// and GitBranches every branch it had checked out, in the order first
+	// seen, as Claude Code recorded them. GitCommit is the commit the
+	// session last made, read from git commit's output; HEAD when the
+	// session ended unless something else moved it. All are empty outside
+	// a git repository.
+	GitBranch   string   `json:"git_branch,omitempty"`
+	GitBranches []string `json:"git_branches,omitempty"`
+	GitCommit   string   `json:"git_commit,omitempty"`
+
+	// SummaryGenerated is set when the transcript had no summary line and
+	// Summary was generated by GenerateSummary instead.
+	SummaryGenerated bool `json:"summary_generated,omitempty"`
+
+	// ParentID is set on subagent transcripts (agent-*.jsonl): the ID of
+	// the session that spawned the subagent.
+	ParentID string `json:"parent_id,omitempty"`
+
+	// Duration is EndTime minus StartTime.
+	Duration time.Duration `json:"duration"`
+
+	// UserMessages counts prompts (user lines with text, not tool
+	// results). AssistantMessages counts replies, once per API message
+	// however many lines Claude Code split it across. Subagent traffic
+	// logged in the parent transcript is not counted.
+	UserMessages      int `json:"user_messages"`
+	AssistantMessages int `json:"assistant_messages"`
+
+	// CompactionCount is how many times the session's context filled up
+	// and was replaced by a summary, and LastCompaction when that last
+	// happened. Repeated compactions mean an agent keeps outgrowing its
+	// context; see ReadCompactions for the details of each.
+	CompactionCount int       `json:"compaction_count,omitempty"`
+	LastCompaction  time.Time `json:"last_compaction,omitempty"`
+
+	// ToolCalls counts the tool calls the session made. ErrorCount counts
+	// those whose result came back as an error, and ToolErrors breaks it
+	// down by tool (e.g. {"Bash": 12, "Edit": 3}); "unknown" holds
+	// failures whose call wasn't found. A high ErrorRate marks a flaky
+	// session. Subagent traffic logged in the parent transcript is not
+	// counted.
+	ToolCalls  int            `json:"tool_calls,omitempty"`
+	ErrorCount int            `json:"error_count,omitempty"`
+	ToolErrors map[string]int `json:"tool_errors,omitempty"`
+
+	// MCPServers lists, sorted, the MCP servers whose tools the session
+	// called, and MCPCalls counts those calls by full tool name (e.g.
+	// {"mcp__github__create_issue": 2}). See SummarizeMCPUsage.
+	MCPServers []string       `json:"mcp_servers,omitempty"`
+	MCPCalls   map[string]int `json:"mcp_calls,omitempty"`
+
+	// ContextTokens is the size of the context the last reply was
+	// generated from: its input, cache-creation, and cache-read tokens.
+	ContextTokens int64 `json:"context_tokens,omitempty"`
+
+	// Truncated is set when a transcript line was too long to read, so
+	// parsing stopped there. See Warnings.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Unfinished is set when the transcript ends mid-turn: on a prompt or
+	// tool result the agent never answered, or a tool call that never
+	// returned. A finished turn ends on the agent's reply. See State.
+	Unfinished bool `json:"unfinished,omitempty"`
+
+	// State is whether the session is active, idle, crashed, or
+	// abandoned, judged at discovery time (see ClassifySession).
+	State SessionState `json:"state,omitempty"`
+
+	// Outcome is how the session's work ended: completed, blocked,
+	// handoff, or abandoned. Closure commands (gt done, bd close,
+	// gt handoff, gt escalate) and a final reply saying it is blocked
+	// decide it from the transcript; otherwise discovery judges it once
+	// the session stops. Empty while it is still running.
+	Outcome SessionOutcome `json:"outcome,omitempty"`
+
+	// Chain lists the sessions of a resume chain collapsed into this one
+	// (see SessionFilter.CollapseResumes), oldest first and ending with
+	// this session, and ChainStart is when the first of them started.
+	// Empty for sessions that stand alone.
+	Chain      []string  `json:"chain,omitempty"`
+	ChainStart time.Time `json:"chain_start,omitempty"`
+}
+
+// SessionFilter narrows session discovery.
+type SessionFilter struct {
+	// GasTownOnly restricts results to sessions with a [GAS TOWN] beacon.
+	GasTownOnly bool
+
+	// Role matches sessions whose beacon role contains this string.
+	Role string
+
+	// Rig matches sessions whose role starts with "<rig>/" or whose
+	// project path contains the rig name.
+	Rig string
+
+	// Path matches sessions whose project path contains this string.
+	Path string
+
+	// Roots are the Claude data directories to scan, each holding a
+	// projects directory; results from all of them are merged. Empty
+	// means Roots().
+	Roots []string
+
+	// Tagger labels discovered sessions using the town's session_tags
+	// rules before filtering.
+	Tagger config.SessionTagger
+
+	// Tag matches sessions the Tagger labeled with this tag.
+	Tag string
+
+	// Model matches sessions whose model contains this string, so
+	// "opus" matches every Opus version.
+	Model string
+
+	// Branch matches sessions that had this git branch checked out at
+	// any point; "*" patterns match as in path.Match (see OnBranch).
+	Branch string
+
+	// State matches sessions in this state (see ClassifySession).
+	State SessionState
+
+	// Outcome matches sessions whose work ended this way (see
+	// SessionInfo.Outcome).
+	Outcome SessionOutcome
+
+	// MCPServer matches sessions that called a tool of this MCP server.
+	MCPServer string
+
+	// Since and Until bound when matching sessions started: at or after
+	// Since, and before Until. Zero values leave that end open.
+	Since time.Time
+	Until time.Time
+
+	// IncludeSubagents also returns subagent transcripts, which are
+	// skipped by default. They inherit their parent session's beacon, so
+	// Role, Rig, and GasTownOnly match them as they would the parent.
+	IncludeSubagents bool
+
+	// SortBy orders the results (default SortByStart), descending
+	// (latest, longest, costliest, Z to A) unless SortAsc is set.
+	// Sessions lacking the value, e.g. with no role, come last either
+	// way. IterSessions, which doesn't order sessions globally, ignores
+	// both.
+	SortBy  SessionSort
+	SortAsc bool
+
+	// Prices prices sessions for SortByCost (zero = list prices).
+	Prices config.PriceTable
+
+	// CollapseResumes lists each chain of resumed sessions once, as its
+	// latest segment with the chain recorded in Chain and ChainStart.
+	// Linking sessions means reading every transcript in directories
+	// with more than one match, so it's off by default.
+	CollapseResumes bool
+
+	// Limit caps the number of results (0 = unlimited).
+	Limit int
+
+	// Cursor resumes a listing after the last session of a previous page:
+	// the NextCursor of a SessionPage (see DiscoverSessionsPage).
+	// IterSessions, which doesn't order sessions globally, ignores it.
+	Cursor string
+
+	// Workers bounds how many transcripts are parsed concurrently
+	// (0 = $GT_SESSION_WORKERS, else runtime.NumCPU()).
+	Workers int
+
+	// Timeout bounds discovery on slow filesystems; when it passes,
+	// DiscoverSessions returns what it found with an ErrIncomplete error
+	// (0 = $GT_DISCOVERY_TIMEOUT, else 30s; negative = no timeout).
+	Timeout time.Duration
+}
+
+// beaconPrefix marks Gas Town startup beacons in session transcripts.
+const beaconPrefix = "[GAS TOWN]"
+
+// maxHeaderLines is how many JSONL lines parseSession searches for the
+// summary and beacon. Timestamps and message counts use every line.
+const maxHeaderLines = 50
+
+// DiscoverSessions finds Claude Code sessions, most recent first, in the
+// current SessionStore: the transcripts on disk unless SetStore replaced it.
+func DiscoverSessions(filter SessionFilter) ([]SessionInfo, error) {
+	return currentStore().List(filter)
+}
+
+// scanSessions is FileStore.List: DiscoverSessions over the Claude data
+// directories on disk.
+func scanSessions(filter SessionFilter) ([]SessionInfo, error) {
+	defer perf.AddSince(perf.ScanNanos, time.Now())
+	order, err := filter.order()
+	if err != nil {
+		return nil, err
+	}
+	roots := filter.Roots
+	if len(roots) == 0 {
+		roots = Roots()
+	}
+
+	// Reuse parsed headers for unchanged transcripts
+	var idx *sessionIndex
+	seen := make(map[string]bool)
+	if sessionIndexEnabled() {
+		idx = loadSessionIndex(ProjectsDir())
+	}
+
+	// Give up on slow or hung filesystems rather than block forever,
+	// keeping whatever was found by then
+	var deadline time.Time
+	if timeout := discoveryTimeout(filter.Timeout); timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	roots = resolveRoots(roots)
+	if skipRemoteRoots() {
+		roots = localRoots(roots)
+	}
+
+	var jobs []parseJob
+	var scanned []string
+	listings, timedOut := listRoots(roots, filter, deadline)
+	for _, l := range listings {
+		if l.err != nil {
+			if os.IsNotExist(l.err) {
+				continue
+			}
+			return nil, l.err
+		}
+		for path := range l.seen {
+			seen[path] = true
+		}
+		jobs = append(jobs, l.jobs...)
+		scanned = append(scanned, filepath.Join(l.root, "projects"))
+	}
+	if idx != nil && filter.Rig != "" {
+		// Leave other rigs' shards unread
+		jobs = idx.skipOtherRigs(jobs, seen, filter.Rig)
+	}
+
+	parsed, unparsed := parseSessions(idx, jobs, filter.Workers, deadline)
+	if filter.IncludeSubagents {
+		attributeSubagents(parsed)
+	}
+
+	states := newStateClassifier(time.Now(), parsed)
+	notes := loadAnnotations()
+	var matched []*SessionInfo
+	for _, info := range parsed {
+		if info != nil && annotateSession(info, filter, idx, notes, states) {
+			matched = append(matched, info)
+		}
+	}
+	if filter.CollapseResumes {
+		matched = collapseResumes(matched)
+	}
+	var sessions []SessionInfo
+	for _, info := range matched {
+		sessions = append(sessions, *info)
+	}
+
+	if idx != nil {
+		idx.prune(seen, scanned, !filter.IncludeSubagents)
+		idx.save()
+	}
+
+	positions := sortSessions(sessions, order)
+	if filter.Cursor != "" {
+		after, err := sessionsAfterCursor(sessions, positions, order, filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		sessions = after
+	}
+
+	if filter.Limit > 0 && len(sessions) > filter.Limit {
+		sessions = sessions[:filter.Limit]
+	}
+
+	if len(timedOut) > 0 || unparsed > 0 {
+		return sessions, incompleteError(timedOut, unparsed, len(jobs))
+	}
+	return sessions, nil
+}
+
+// annotateSession fills in what discovery adds to a parsed session (its
+// state and outcome, generated summary, note, and tags, from rules and by hand)
+// and reports whether it matches filter.
+func annotateSession(info *SessionInfo, filter SessionFilter, idx *sessionIndex, notes *sessionAnnotations, states *stateClassifier) bool {
+	info.State = states.classify(info)
+	info.settleOutcome()
+	if info.Summary == "" && notes.Summaries[info.ID] != "" {
+		info.Summary = notes.Summaries[info.ID]
+		info.SummaryGenerated = true
+	}
+	info.Tags = filter.Tagger.Tags(info.ProjectPath, info.tagTopic())
+	if len(notes.Tags[info.ID]) > 0 {
+		info.Tags = mergeTags(info.Tags, notes.Tags[info.ID])
+	}
+	info.Note = notes.Notes[info.ID]
+	return filter.matches(info)
+}
+
+// incompleteError describes what discovery skipped when it timed out.
+func incompleteError(timedOut []string, unparsed, total int) error {
+	var skipped []string
+	if len(timedOut) > 0 {
+		skipped = append(skipped, "could not list "+strings.Join(timedOut, ", "))
+	}
+	if unparsed > 0 {
+		skipped = append(skipped, fmt.Sprintf("read %d of %d transcripts", total-unparsed, total))
+	}
+	return fmt.Errorf("%w: timed out; %s", ErrIncomplete, strings.Join(skipped, "; "))
+}
+
+// projectJobs returns parse jobs for the transcripts filter may match in
+// one Claude data directory's projects directory, marking them seen.
+func projectJobs(projectsDir, root string, filter SessionFilter, seen map[string]bool) ([]parseJob, error) {
+	projects, err := os.ReadDir(projectsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []parseJob
+	for _, project := range projects {
+		if !project.IsDir() {
+			continue
+		}
+		jobs = append(jobs, projectDirJobs(projectsDir, project.Name(), root, filter, seen)...)
+	}
+	return jobs, nil
+}
+
+// projectDirJobs returns parse jobs for the transcripts filter may match
+// in one project directory, marking them seen.
+func projectDirJobs(projectsDir, project, root string, filter SessionFilter, seen map[string]bool) []parseJob {
+	projectDir := filepath.Join(projectsDir, project)
+	files, err := os.ReadDir(projectDir)
+	if err != nil {
+		return nil
+	}
+
+	var jobs []parseJob
+	for _, f := range files {
+		if f.IsDir() {
+			// Newer Claude Code versions nest subagent transcripts
+			// under <session-id>/subagents/
+			if filter.IncludeSubagents {
+				jobs = append(jobs, subagentJobs(filepath.Join(projectDir, f.Name(), "subagents"), project, root, seen)...)
+			}
+			continue
+		}
+		if !strings.HasSuffix(f.Name(), ".jsonl") {
+			continue
+		}
+		path := filepath.Join(projectDir, f.Name())
+		seen[path] = true
+		if isSubagentTranscript(path) && !filter.IncludeSubagents {
+			continue
+		}
+		if !filter.Since.IsZero() {
+			// Untouched since the window opened, so it started before it
+			if stat, err := f.Info(); err == nil && stat.ModTime().Before(filter.Since) {
+				continue
+			}
+		}
+		jobs = append(jobs, parseJob{path: path, project: project, root: root})
+	}
+	return jobs
+}
+
+// subagentJobs returns parse jobs for the transcripts in a nested
+// subagents directory, marking them seen.
+func subagentJobs(dir, project, root string, seen map[string]bool) []parseJob {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var jobs []parseJob
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".jsonl") {
+			continue
+		}
+		path := filepath.Join(dir, f.Name())
+		seen[path] = true
+		jobs = append(jobs, parseJob{path: path, project: project, root: root})
+	}
+	return jobs
+}
+
+// attributeSubagents copies each subagent's parent beacon onto it, so
+// subagent work is attributed to the agent that delegated it.
+func attributeSubagents(sessions []*SessionInfo) {
+	byID := make(map[string]*SessionInfo, len(sessions))
+	for _, s := range sessions {
+		if s != nil && s.ParentID == "" {
+			byID[s.ID] = s
+		}
+	}
+	for _, s := range sessions {
+		if s == nil || s.ParentID == "" {
+			continue
+		}
+		if parent := byID[s.ParentID]; parent != nil {
+			inheritBeacon(s, parent)
+		}
+	}
+}
+
+// inheritBeacon gives a subagent its parent's beacon, if it has one.
+func inheritBeacon(s, parent *SessionInfo) {
+	if parent.IsGasTown && !s.IsGasTown {
+		s.IsGasTown = true
+		s.Role = parent.Role
+		s.Topic = parent.Topic
+		s.Rig = parent.Rig
+		s.AgentName = parent.AgentName
+		s.Bead = parent.Bead
+	}
+}
+
+// subagentParentPath returns where the parent of the subagent transcript
+// at path lives: beside it, or above its <session-id>/subagents/ directory.
+func subagentParentPath(path, parentID string) string {
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) == "subagents" {
+		dir = filepath.Dir(filepath.Dir(dir))
+	}
+	return filepath.Join(dir, parentID+".jsonl")
+}
+
+// isSubagentTranscript reports whether path is a subagent transcript.
+func isSubagentTranscript(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), "agent-")
+}
+
+// sessionWorkersEnv sets the default discovery worker count.
+const sessionWorkersEnv = "GT_SESSION_WORKERS"
+
+// parseJob is a transcript for parseSessions to read.
+type parseJob struct {
+	path    string
+	project string
+	root    string // Claude data directory
+}
+
+// parseSessions parses transcripts on up to workers goroutines. Results
+// are in job order; unreadable or empty transcripts are nil. Jobs not
+// parsed by deadline (zero = none) are left nil and counted in unparsed;
+// workers stuck on a hung read are abandoned.
+func parseSessions(idx *sessionIndex, jobs []parseJob, workers int, deadline time.Time) (results []*SessionInfo, unparsed int) {
+	workers = workerCount(workers, len(jobs))
+
+	type parsed struct {
+		i    int
+		info *SessionInfo
+	}
+	results = make([]*SessionInfo, len(jobs))
+	out := make(chan parsed, len(jobs)) // abandoned workers never block
+	next := make(chan int)
+	stop := make(chan struct{})
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range next {
+				info, err := parseSessionIndexed(idx, jobs[i].path, jobs[i].project)
+				if err == nil && info != nil {
+					info.Root = jobs[i].root
+				} else {
+					info = nil
+				}
+				out <- parsed{i, info}
+			}
+		}()
+	}
+	go func() {
+		defer close(next)
+		for i := range jobs {
+			select {
+			case next <- i:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	expired, release := deadlineTimer(deadline)
+	defer release()
+	for n := range jobs {
+		select {
+		case r := <-out:
+			results[r.i] = r.info
+		case <-expired:
+			close(stop)
+			// Keep what finished in the meantime; results is only
+			// written here, so abandoned workers can't race with callers
+			for {
+				select {
+				case r := <-out:
+					results[r.i] = r.info
+					n++
+				default:
+					return results, len(jobs) - n
+				}
+			}
+		}
+	}
+	return results, 0
+}
+
+// workerCount resolves a SessionFilter.Workers value for n jobs:
+// $GT_SESSION_WORKERS or runtime.NumCPU() when unset, and no more than n.
+func workerCount(workers, n int) int {
+	if workers <= 0 {
+		workers, _ = strconv.Atoi(os.Getenv(sessionWorkersEnv))
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return min(workers, n)
+}
+
+// FindSession locates a session transcript by alias, ID, or unique ID
+// prefix.
+func FindSession(id string) (*SessionInfo, error) {
+	return currentStore().Get(id)
+}
+
+// findSessionFile is FileStore.Get: FindSession among the transcripts on
+// disk.
+func findSessionFile(id string) (*SessionInfo, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty session ID")
+	}
+	if target, ok := ResolveAlias(id); ok {
+		id = target
+	}
+
+	var matches []parseJob
+	for _, root := range resolveRoots(Roots()) {
+		projectsDir := filepath.Join(root, "projects")
+		projects, err := os.ReadDir(projectsDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, project := range projects {
+			if !project.IsDir() {
+				continue
+			}
+			projectDir := filepath.Join(projectsDir, project.Name())
+
+			// Exact match is the common case - avoid listing the directory
+			exact := filepath.Join(projectDir, id+".jsonl")
+			if _, err := os.Stat(exact); err == nil {
+				return parseSessionIn(parseJob{path: exact, project: project.Name(), root: root})
+			}
+
+			files, err := os.ReadDir(projectDir)
+			if err != nil {
+				continue
+			}
+			for _, f := range files {
+				name := f.Name()
+				if f.IsDir() || !strings.HasSuffix(name, ".jsonl") || strings.HasPrefix(name, "agent-") {
+					continue
+				}
+				if strings.HasPrefix(name, id) {
+					matches = append(matches, parseJob{path: filepath.Join(projectDir, name), project: project.Name(), root: root})
+				}
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("session %s not found", id)
+	case 1:
+		return parseSessionIn(matches[0])
+	default:
+		return nil, fmt.Errorf("session ID %s is ambiguous (%d matches)", id, len(matches))
+	}
+}
+
+// parseSessionIn parses a job's transcript, recording the root it is in.
+func parseSessionIn(job parseJob) (*SessionInfo, error) {
+	info, err := parseSession(job.path, job.project)
+	if err != nil {
+		return nil, err
+	}
+	info.Root = job.root
+	return info, nil
+}
+
+// matches reports whether a session passes the filter.
+func (f SessionFilter) matches(s *SessionInfo) bool {
+	if !f.IncludeSubagents && s.IsSubagent() {
+		return false
+	}
+	if f.GasTownOnly && !s.IsGasTown {
+		return false
+	}
+	if f.Role != "" && !strings.Contains(strings.ToLower(s.Role), strings.ToLower(f.Role)) {
+		return false
+	}
+	if f.Rig != "" {
+		rig := strings.ToLower(f.Rig)
+		if !strings.HasPrefix(strings.ToLower(s.Role), rig+"/") &&
+			!strings.EqualFold(s.Rig, f.Rig) &&
+			!strings.Contains(strings.ToLower(s.ProjectPath), rig) {
+			return false
+		}
+	}
+	if f.Path != "" && !strings.Contains(slashPath(s.ProjectPath), slashPath(f.Path)) {
+		return false
+	}
+	if f.Tag != "" && !slices.Contains(s.Tags, f.Tag) {
+		return false
+	}
+	if f.Model != "" && !strings.Contains(strings.ToLower(s.Model), strings.ToLower(f.Model)) {
+		return false
+	}
+	if f.Branch != "" && !s.OnBranch(f.Branch) {
+		return false
+	}
+	if f.State != "" && s.State != f.State {
+		return false
+	}
+	if f.MCPServer != "" && !s.UsedMCPServer(f.MCPServer) {
+		return false
+	}
+	if f.Outcome != "" && s.Outcome != f.Outcome {
+		return false
+	}
+	if !f.Since.IsZero() && s.StartTime.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !s.StartTime.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// tagTopic is the text topic tag rules match: the beacon topic, or the
+// summary for sessions without one.
+func (s SessionInfo) tagTopic() string {
+	if s.Topic != "" {
+		return s.Topic
+	}
+	return s.Summary
+}
+
+// entryMessage is the message body of user/assistant entries.
+type entryMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// parseSession reads a transcript to build its SessionInfo. The summary
+// and beacon come from its head; times and message counts from every line.
+// ProjectPath is the first cwd recorded, since decoding the project
+// directory name can't tell a hyphen from a path separator.
+// For subagent transcripts (agent-*.jsonl), ParentID is read from the
+// sessionId Claude Code stamps on each line, or from the enclosing
+// <session-id>/subagents/ directory.
+func parseSession(path, project string) (*SessionInfo, error) {
+	name := filepath.Base(path)
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path) //nolint:gosec // G304: path is from the Claude projects directory
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info := &SessionInfo{
+		ID:       strings.TrimSuffix(name, ".jsonl"),
+		Path:     path,
+		Project:  project,
+		FileSize: stat.Size(),
+		DiskSize: stat.Size(),
+	}
+
+	subagent := isSubagentTranscript(path)
+	if !subagent {
+		info.DiskSize += dirSize(filepath.Join(filepath.Dir(path), info.ID))
+	}
+	replies := make(map[string]bool)
+	toolNames := make(map[string]string) // tool_use ID -> tool
+	boundary := false                    // a compact_boundary awaits its summary
+	var marks outcomeMarks
+
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, maxLineSize)
+
+	for lineNum := 0; scanner.Scan(); lineNum++ {
+		entry, err := decodeEntry(scanner.Bytes())
+		if err != nil {
+			continue
+		}
+
+		if info.ProjectPath == "" && entry.Cwd != "" {
+			info.ProjectPath = entry.Cwd
+		}
+		info.addBranch(entry.GitBranch)
+
+		if entry.Version != "" {
+			info.ClaudeVersion = entry.Version
+		}
+		ts := entry.Timestamp
+		if !ts.IsZero() {
+			if info.StartTime.IsZero() {
+				info.StartTime = ts
+			}
+			info.EndTime = ts
+		}
+
+		// Compactions: a compact_boundary system entry, then the summary.
+		// Older Claude Code versions wrote only the summary.
+		switch {
+		case entry.Type == "system" && entry.Subtype == "compact_boundary":
+			info.CompactionCount++
+			info.LastCompaction = ts
+			boundary = true
+		case entry.IsCompactSummary:
+			if !boundary {
+				info.CompactionCount++
+				info.LastCompaction = ts
+			}
+			boundary = false
+		}
+
+		if !entry.IsSidechain || subagent {
+			switch entry.Type {
+			case "user":
+				text := messageText(entry.Message)
+				if !entry.IsCompactSummary && text != "" {
+					info.UserMessages++
+				}
+				info.Unfinished = userLeavesTurnOpen(text)
+				if text == "" && bytes.Contains(scanner.Bytes(), []byte("] ")) {
+					if commit := gitCommitIn(entry.Message); commit != "" {
+						info.GitCommit = commit
+					}
+				}
+				if text == "" && bytes.Contains(scanner.Bytes(), []byte(`"is_error":true`)) {
+					info.countToolErrors(entry.Message, toolNames)
+				}
+			case "assistant":
+				info.Unfinished = len(messageTools(entry.Message)) > 0
+				if bytes.Contains(scanner.Bytes(), []byte(`"tool_use"`)) {
+					info.countToolUses(entry.Message, toolNames)
+				}
+				marks.noteAssistant(scanner.Bytes(), entry.Message, lineNum, info.Bead)
+				meta := parseAssistantMeta(entry.Message)
+				if meta.ID == "" || !replies[meta.ID] {
+					replies[meta.ID] = true
+					info.AssistantMessages++
+				}
+				if meta.Model != "" && meta.Model != "<synthetic>" {
+					info.Model = meta.Model
+				}
+				if meta.Usage != nil {
+					info.ContextTokens = meta.Usage.contextTokens()
+				}
+			}
+		}
+
+		if lineNum >= maxHeaderLines {
+			continue
+		}
+
+		if entry.Type == "summary" && info.Summary == "" {
+			info.Summary = entry.Summary
+		}
+
+		if info.ParentID == "" && entry.SessionID != "" && entry.SessionID != info.ID && subagent {
+			info.ParentID = entry.SessionID
+		}
+
+		if !info.IsGasTown && entry.Type == "user" {
+			if b, ok := parseBeacon(messageText(entry.Message)); ok {
+				info.IsGasTown = true
+				b.apply(info)
+			}
+		}
+	}
+
+	if errors.Is(scanner.Err(), bufio.ErrTooLong) {
+		info.Truncated = true
+	}
+
+	if info.StartTime.IsZero() {
+		info.StartTime = stat.ModTime()
+		info.EndTime = stat.ModTime()
+	}
+	info.Duration = info.EndTime.Sub(info.StartTime)
+	info.Outcome = marks.outcome()
+	if info.ProjectPath == "" {
+		info.ProjectPath = decodePath(project)
+	}
+	if info.ParentID == "" && subagent {
+		if dir := filepath.Dir(path); filepath.Base(dir) == "subagents" {
+			info.ParentID = filepath.Base(filepath.Dir(dir))
+		}
+	}
+
+	return info, nil
+}
+
+// userLeavesTurnOpen reports whether a user entry leaves the turn
+// awaiting the agent. Prompts and tool results (which have no text) do;
+// an interruption or a local command such as /exit doesn't.
+func userLeavesTurnOpen(text string) bool {
+	return !strings.HasPrefix(text, "[Request interrupted by user") &&
+		!strings.Contains(text, "<command-name>") &&
+		!strings.Contains(text, "<local-command-stdout>") &&
+		!strings.Contains(text, "<local-command-caveat>")
+}
+
+// assistantMeta is what parseSession reads from an assistant message.
+type assistantMeta struct {
+	ID    string    `json:"id"`
+	Model string    `json:"model"`
+	Usage *apiUsage `json:"usage"`
+}
+
+// parseAssistantMeta returns the API message ID, model, and usage of an
+// assistant message.
+func parseAssistantMeta(raw json.RawMessage) assistantMeta {
+	var msg assistantMeta
+	if len(raw) == 0 || json.Unmarshal(raw, &msg) != nil {
+		return assistantMeta{}
+	}
+	return msg
+}
+
+// messageText extracts the plain text of a message whose content is either
+// a string or an array of content blocks.
+func messageText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var msg entryMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(msg.Content, &s); err == nil {
+		return s
+	}
+	return blockText(msg.Content)
+}
+
+// decodePath converts an encoded project directory name back to a path.
+// Claude Code encodes "/Users/x/project" as "-Users-x-project", and on
+// Windows "C:\Users\x\project" as "C--Users-x-project". The encoding is
+// lossy (a hyphen in a directory name decodes as a separator), so this
+// is only a fallback for transcripts that record no cwd.
+func decodePath(encoded string) string {
+	if len(encoded) > 3 && isDriveLetter(encoded[0]) && encoded[1:3] == "--" {
+		return encoded[:1] + `:\` + strings.ReplaceAll(encoded[3:], "-", `\`)
+	}
+	return strings.ReplaceAll(encoded, "-", "/")
+}
+
+func isDriveLetter(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+// slashPath normalizes Windows separators so paths from transcripts
+// written on either platform compare alike.
+func slashPath(p string) string {
+	return strings.ReplaceAll(p, `\`, "/")
+}
+
+// IsSubagent reports whether the session is a subagent transcript.
+func (s SessionInfo) IsSubagent() bool {
+	return isSubagentTranscript(s.Path) || s.ParentID != ""
+}
+
+// Span returns how long the session's resume chain ran, from the first
+// segment's start to this one's end; for a session that stands alone,
+// its Duration.
+func (s SessionInfo) Span() time.Duration {
+	if s.ChainStart.IsZero() || s.EndTime.IsZero() {
+		return s.Duration
+	}
+	return s.EndTime.Sub(s.ChainStart)
+}
+
+// ShortID returns the first 8 characters of the session ID.
+// Subagent IDs keep their "agent-" prefix.
+func (s SessionInfo) ShortID() string {
+	if rest, ok := strings.CutPrefix(s.ID, "agent-"); ok && len(rest) > 8 {
+		return "agent-" + rest[:8]
+	}
+	if len(s.ID) > 8 {
+		return s.ID[:8]
+	}
+	return s.ID
+}
@@ -0,0 +1,446 @@
+package claude
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// writeSession writes JSONL lines to a session file under a fake HOME.
+func writeSession(t testing.TB, home, project, name string, lines ...string) string {
+	t.Helper()
+	dir := filepath.Join(home, ".claude", "projects", project)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return path
+}
+
+func TestDiscoverSessions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	writeSession(t, home, "-home-u-gt-gastown-crew-joe", "aaaa1111.jsonl",
+		`{"type":"summary","summary":"Fix the widget"}`,
+		`{"type":"user","timestamp":"2025-12-30T15:42:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/crew/joe <- deacon • 2025-12-30T15:42 • assigned:gt-abc12"}}`,
+	)
+	writeSession(t, home, "-home-u-other", "bbbb2222.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":[{"type":"text","text":"hello"}]}}`,
+	)
+	writeSession(t, home, "-home-u-other", "agent-cccc.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T10:00:00Z","message":{"role":"user","content":"sub"}}`,
+	)
+
+	all, err := DiscoverSessions(SessionFilter{})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(all))
+	}
+	if all[0].ID != "bbbb2222" {
+		t.Errorf("most recent first: got %s", all[0].ID)
+	}
+
+	gt, err := DiscoverSessions(SessionFilter{GasTownOnly: true, Rig: "gastown"})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(gt) != 1 {
+		t.Fatalf("got %d gas town sessions, want 1", len(gt))
+	}
+	s := gt[0]
+	if s.Role != "gastown/crew/joe" || s.Topic != "assigned:gt-abc12" || s.Summary != "Fix the widget" {
+		t.Errorf("unexpected session: %+v", s)
+	}
+}
+
+func TestParseSessionTimesAndCounts(t *testing.T) {
+	home := t.TempDir()
+	path := writeSession(t, home, "-town", "aaaa1111.jsonl",
+		`{"type":"summary","summary":"Fix the widget"}`,
+		`{"type":"user","timestamp":"2025-12-30T15:00:00Z","message":{"role":"user","content":"fix the widget"}}`,
+		`{"type":"assistant","timestamp":"2025-12-30T15:00:05Z","message":{"id":"msg_1","role":"assistant","content":[{"type":"text","text":"Looking"}]}}`,
+		`{"type":"assistant","timestamp":"2025-12-30T15:00:06Z","message":{"id":"msg_1","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Read","input":{}}]}}`,
+		`{"type":"user","timestamp":"2025-12-30T15:00:07Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`,
+		`{"type":"user","isSidechain":true,"timestamp":"2025-12-30T15:01:00Z","message":{"role":"user","content":"subagent task"}}`,
+		`{"type":"assistant","timestamp":"2025-12-30T15:30:00Z","message":{"id":"msg_2","role":"assistant","content":[{"type":"text","text":"Fixed"}]}}`,
+		`{"type":"user","timestamp":"2025-12-30T15:45:00Z","message":{"role":"user","content":[{"type":"text","text":"thanks"}]}}`,
+	)
+
+	info, err := parseSession(path, "-town")
+	if err != nil {
+		t.Fatalf("parseSession: %v", err)
+	}
+	if !info.EndTime.Equal(time.Date(2025, 12, 30, 15, 45, 0, 0, time.UTC)) || info.Duration != 45*time.Minute {
+		t.Errorf("end = %v, duration = %v", info.EndTime, info.Duration)
+	}
+	if info.UserMessages != 2 || info.AssistantMessages != 2 {
+		t.Errorf("user = %d, assistant = %d, want 2 and 2", info.UserMessages, info.AssistantMessages)
+	}
+
+	// No timestamps: start and end fall back to the file's mtime
+	path = writeSession(t, home, "-town", "bbbb2222.jsonl", `{"type":"summary","summary":"empty"}`)
+	info, err = parseSession(path, "-town")
+	if err != nil {
+		t.Fatalf("parseSession: %v", err)
+	}
+	if info.StartTime.IsZero() || !info.EndTime.Equal(info.StartTime) || info.Duration != 0 {
+		t.Errorf("start = %v, end = %v, duration = %v", info.StartTime, info.EndTime, info.Duration)
+	}
+}
+
+func TestParseSessionProjectPath(t *testing.T) {
+	home := t.TempDir()
+	project := "-Users-x-my-project"
+
+	path := writeSession(t, home, project, "aaaa1111.jsonl",
+		`{"type":"summary","summary":"no cwd here"}`,
+		`{"type":"user","cwd":"/Users/x/my-project","timestamp":"2025-12-30T15:00:00Z","message":{"role":"user","content":"hi"}}`,
+		`{"type":"user","cwd":"/Users/x/my-project/sub","timestamp":"2025-12-30T15:01:00Z","message":{"role":"user","content":"cd sub"}}`,
+	)
+	info, err := parseSession(path, project)
+	if err != nil {
+		t.Fatalf("parseSession: %v", err)
+	}
+	if info.ProjectPath != "/Users/x/my-project" {
+		t.Errorf("ProjectPath = %q, want the first recorded cwd", info.ProjectPath)
+	}
+
+	// Without a cwd, fall back to decoding the directory name
+	path = writeSession(t, home, project, "bbbb2222.jsonl",
+		`{"type":"user","timestamp":"2025-12-30T15:00:00Z","message":{"role":"user","content":"hi"}}`,
+	)
+	if info, err = parseSession(path, project); err != nil {
+		t.Fatalf("parseSession: %v", err)
+	}
+	if info.ProjectPath != "/Users/x/my/project" {
+		t.Errorf("ProjectPath = %q, want the decoded directory name", info.ProjectPath)
+	}
+}
+
+func TestDecodePath(t *testing.T) {
+	tests := []struct{ encoded, want string }{
+		{"-Users-x-project", "/Users/x/project"},
+		{"-home-u--config", "/home/u//config"},
+		{"C--Users-x-project", `C:\Users\x\project`},
+		{"d--src", `d:\src`},
+	}
+	for _, tt := range tests {
+		if got := decodePath(tt.encoded); got != tt.want {
+			t.Errorf("decodePath(%q) = %q, want %q", tt.encoded, got, tt.want)
+		}
+	}
+}
+
+func TestSessionFilterPathWindows(t *testing.T) {
+	s := &SessionInfo{ProjectPath: `C:\Users\x\gt\gastown\crew\max`}
+	for _, path := range []string{`gastown\crew`, "gastown/crew"} {
+		if !(SessionFilter{Path: path}).matches(s) {
+			t.Errorf("Path %q should match %s", path, s.ProjectPath)
+		}
+	}
+}
+
+func TestDiscoverSessionsTags(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	writeSession(t, home, "-home-u-experiments-parser", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-12-30T15:42:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/crew/joe <- deacon • 2025-12-30T15:42 • spike: new parser"}}`,
+	)
+	writeSession(t, home, "-home-u-other", "bbbb2222.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":"hello"}}`,
+	)
+
+	tagger, err := config.CompileSessionTags([]config.SessionTagRule{
+		{Path: "*/experiments/*", Tag: "experiment"},
+		{Topic: "^spike", Tag: "spike"},
+	})
+	if err != nil {
+		t.Fatalf("CompileSessionTags: %v", err)
+	}
+
+	all, err := DiscoverSessions(SessionFilter{Tagger: tagger})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(all) != 2 || len(all[0].Tags) != 0 {
+		t.Fatalf("unexpected sessions: %+v", all)
+	}
+	if got := strings.Join(all[1].Tags, ","); got != "experiment,spike" {
+		t.Errorf("tags = %q, want experiment,spike", got)
+	}
+
+	tagged, err := DiscoverSessions(SessionFilter{Tagger: tagger, Tag: "experiment"})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(tagged) != 1 || tagged[0].ID != "aaaa1111" {
+		t.Errorf("Tag filter = %+v, want aaaa1111", tagged)
+	}
+}
+
+func TestDiscoverSessionsSubagents(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	project := "-home-u-gt-gastown-crew-joe"
+
+	writeSession(t, home, project, "aaaa1111.jsonl",
+		`{"type":"user","sessionId":"aaaa1111","timestamp":"2025-12-30T15:42:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/crew/joe <- deacon • 2025-12-30T15:42 • handoff"}}`,
+	)
+	writeSession(t, home, project, "agent-1234567890.jsonl",
+		`{"type":"user","sessionId":"aaaa1111","isSidechain":true,"timestamp":"2025-12-30T15:50:00Z","message":{"role":"user","content":"Find the widget"}}`,
+	)
+	writeSession(t, home, filepath.Join(project, "aaaa1111", "subagents"), "agent-nested.jsonl",
+		`{"type":"user","isSidechain":true,"timestamp":"2025-12-30T15:55:00Z","message":{"role":"user","content":"Check the tests"}}`,
+	)
+
+	sessions, err := DiscoverSessions(SessionFilter{})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "aaaa1111" {
+		t.Fatalf("subagents listed by default: %+v", sessions)
+	}
+
+	sessions, err = DiscoverSessions(SessionFilter{IncludeSubagents: true, GasTownOnly: true, Rig: "gastown"})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("got %d sessions, want 3: %+v", len(sessions), sessions)
+	}
+	for _, s := range sessions[:2] {
+		if !s.IsSubagent() || s.ParentID != "aaaa1111" || s.Role != "gastown/crew/joe" {
+			t.Errorf("subagent not attributed: %+v", s)
+		}
+	}
+	if sessions[0].ID != "agent-nested" || sessions[1].ShortID() != "agent-12345678" {
+		t.Errorf("unexpected order or IDs: %s, %s", sessions[0].ID, sessions[1].ShortID())
+	}
+	if sessions[2].IsSubagent() || sessions[2].ParentID != "" {
+		t.Errorf("parent marked as subagent: %+v", sessions[2])
+	}
+}
+
+func TestDiscoverSessionsTimeWindow(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	for _, day := range []string{"01", "02", "03"} {
+		writeSession(t, home, "-home-u-proj", "day"+day+".jsonl",
+			`{"type":"user","timestamp":"2025-03-`+day+`T12:00:00Z","message":{"role":"user","content":"hi"}}`)
+	}
+	at := func(day int) time.Time { return time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC) }
+
+	ids := func(filter SessionFilter) []string {
+		sessions, err := DiscoverSessions(filter)
+		if err != nil {
+			t.Fatalf("DiscoverSessions: %v", err)
+		}
+		var out []string
+		for _, s := range sessions {
+			out = append(out, s.ID)
+		}
+		return out
+	}
+	if got := ids(SessionFilter{Since: at(2)}); !reflect.DeepEqual(got, []string{"day03", "day02"}) {
+		t.Errorf("since = %v", got)
+	}
+	if got := ids(SessionFilter{Until: at(2)}); !reflect.DeepEqual(got, []string{"day01"}) {
+		t.Errorf("until = %v", got)
+	}
+	if got := ids(SessionFilter{Since: at(2), Until: at(3)}); !reflect.DeepEqual(got, []string{"day02"}) {
+		t.Errorf("since and until = %v", got)
+	}
+}
+
+func TestDiscoverSessionsModel(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-home-u-proj", "opus.jsonl",
+		`{"type":"user","timestamp":"2025-03-01T12:00:00Z","message":{"role":"user","content":"hi"}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:00:05Z","message":{"id":"m1","model":"claude-sonnet-4-5-20250929","content":[]}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:00:09Z","message":{"id":"m2","model":"claude-opus-4-1-20250805","content":[]}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:00:10Z","message":{"id":"m3","model":"<synthetic>","content":[]}}`,
+	)
+	writeSession(t, home, "-home-u-proj", "haiku.jsonl",
+		`{"type":"user","timestamp":"2025-03-02T12:00:00Z","message":{"role":"user","content":"hi"}}`,
+		`{"type":"assistant","timestamp":"2025-03-02T12:00:05Z","message":{"id":"m1","model":"claude-haiku-4-5","content":[]}}`,
+	)
+
+	sessions, err := DiscoverSessions(SessionFilter{Model: "OPUS"})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "opus" {
+		t.Fatalf("got %+v, want only the opus session", sessions)
+	}
+	if got := sessions[0].Model; got != "claude-opus-4-1-20250805" {
+		t.Errorf("Model = %q, want the last real model", got)
+	}
+}
+
+func TestDiscoverSessionsGitBranch(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-home-u-proj", "feature.jsonl",
+		`{"type":"user","timestamp":"2025-03-01T12:00:00Z","gitBranch":"main","message":{"role":"user","content":"start a branch"}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:00:05Z","gitBranch":"main","message":{"id":"m1","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{}}]}}`,
+		`{"type":"user","timestamp":"2025-03-01T12:00:06Z","gitBranch":"feat/login","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"[feat/login 1a2b3c4] Add login\n 1 file changed"}]}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:00:07Z","gitBranch":"feat/login","message":{"id":"m2","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{}}]}}`,
+		`{"type":"user","timestamp":"2025-03-01T12:00:08Z","gitBranch":"feat/login","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":[{"type":"text","text":"[feat/login 9f8e7d6c] Fix login"}]}]}}`,
+		`{"type":"user","timestamp":"2025-03-01T12:00:09Z","gitBranch":"main","message":{"role":"user","content":"back to main"}}`,
+	)
+	writeSession(t, home, "-home-u-proj", "plain.jsonl",
+		`{"type":"user","timestamp":"2025-03-02T12:00:00Z","message":{"role":"user","content":"[main 1234567] not a tool result"}}`,
+	)
+
+	sessions, err := DiscoverSessions(SessionFilter{Branch: "feat/login"})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "feature" {
+		t.Fatalf("got %+v, want only the feature session", sessions)
+	}
+	s := sessions[0]
+	if s.GitBranch != "main" || !reflect.DeepEqual(s.GitBranches, []string{"main", "feat/login"}) {
+		t.Errorf("GitBranch = %q, GitBranches = %v", s.GitBranch, s.GitBranches)
+	}
+	if s.GitCommit != "9f8e7d6c" {
+		t.Errorf("GitCommit = %q, want the last commit made", s.GitCommit)
+	}
+
+	if got, _ := DiscoverSessions(SessionFilter{Branch: "feat/*"}); len(got) != 1 {
+		t.Errorf("pattern feat/* matched %d sessions, want 1", len(got))
+	}
+	if got, _ := DiscoverSessions(SessionFilter{Branch: "feat"}); len(got) != 0 {
+		t.Errorf("branch feat matched %d sessions, want none", len(got))
+	}
+	plain, _ := DiscoverSessions(SessionFilter{Path: "/home/u/proj"})
+	for _, p := range plain {
+		if p.ID == "plain" && (p.GitCommit != "" || p.GitBranch != "") {
+			t.Errorf("plain session has git info %q %q", p.GitBranch, p.GitCommit)
+		}
+	}
+}
+
+func TestDiscoverSessionsMissingDir(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	sessions, err := DiscoverSessions(SessionFilter{})
+	if err != nil || len(sessions) != 0 {
+		t.Errorf("DiscoverSessions = %v, %v; want empty, nil", sessions, err)
+	}
+}
+
+func TestDiscoverSessionsParallelOrder(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(sessionIndexEnv, "off")
+	writeSessionTree(t, home, 20, 5)
+
+	serial, err := DiscoverSessions(SessionFilter{Workers: 1})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(serial) != 100 {
+		t.Fatalf("found %d sessions, want 100", len(serial))
+	}
+	for i := 0; i < 3; i++ {
+		parallel, err := DiscoverSessions(SessionFilter{Workers: 8})
+		if err != nil {
+			t.Fatalf("DiscoverSessions: %v", err)
+		}
+		if !reflect.DeepEqual(parallel, serial) {
+			t.Fatal("parallel discovery order differs from serial")
+		}
+	}
+}
+
+// writeSessionTree writes projects×perProject transcripts. Every project's
+// sessions share start times, so ordering depends on tie-breaking.
+func writeSessionTree(t testing.TB, home string, projects, perProject int) {
+	t.Helper()
+	padding := strings.Repeat(`{"type":"assistant","timestamp":"2025-12-30T15:43:00Z","message":{"role":"assistant","content":"working"}}`+"\n", 40)
+	for p := 0; p < projects; p++ {
+		for s := 0; s < perProject; s++ {
+			writeSession(t, home, fmt.Sprintf("-home-u-project-%03d", p), fmt.Sprintf("%04d%04d.jsonl", p, s),
+				fmt.Sprintf(`{"type":"user","timestamp":"2025-12-30T15:%02d:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/polecats/p%d <- witness • 2025-12-30T15:42 • work"}}`, s, p),
+				strings.TrimSuffix(padding, "\n"),
+			)
+		}
+	}
+}
+
+func BenchmarkDiscoverSessions(b *testing.B) {
+	home := b.TempDir()
+	b.Setenv("HOME", home)
+	b.Setenv(sessionIndexEnv, "off")
+	writeSessionTree(b, home, 200, 5)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := DiscoverSessions(SessionFilter{Workers: workers}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseBeacon(t *testing.T) {
+	tests := []struct {
+		text string
+		want beacon
+	}{
+		{
+			"[GAS TOWN] gastown/witness <- self • 2025-12-30T14:00 • handoff\n\nCheck your hook",
+			beacon{Role: "gastown/witness", Topic: "handoff", Rig: "gastown", Agent: "witness"},
+		},
+		{
+			"[GAS TOWN] gastown/crew/gus <- deacon • 2025-12-30T15:42 • assigned:gt-abc12",
+			beacon{Role: "gastown/crew/gus", Topic: "assigned:gt-abc12", Rig: "gastown", Agent: "gus", Bead: "gt-abc12"},
+		},
+		{
+			"[GAS TOWN] gastown/polecats/Toast <- witness • 2025-12-30T15:42 • gt-xyz99",
+			beacon{Role: "gastown/polecats/Toast", Topic: "gt-xyz99", Rig: "gastown", Agent: "Toast", Bead: "gt-xyz99"},
+		},
+		{
+			"[GAS TOWN] deacon <- mayor • 2025-12-30T08:00 • cold-start",
+			beacon{Role: "deacon", Topic: "cold-start", Agent: "deacon"},
+		},
+		{
+			"[GAS TOWN] role=gastown/crew/gus from=deacon rig=gastown agent=gus topic=assigned bead=gt-abc12 ts=2025-12-30T15:42:00Z\n\nWork is on your hook.",
+			beacon{Role: "gastown/crew/gus", Topic: "assigned:gt-abc12", Rig: "gastown", Agent: "gus", Bead: "gt-abc12"},
+		},
+		{
+			// Any order, quoted values, unknown keys, derived rig and agent
+			`[GAS TOWN] ts=2025-12-30T15:42:00Z from="gt canary" role=canary/crew/canary future=x topic=canary`,
+			beacon{Role: "canary/crew/canary", Topic: "canary", Rig: "canary", Agent: "canary"},
+		},
+	}
+	for _, tt := range tests {
+		got, ok := parseBeacon(tt.text)
+		if !ok || got != tt.want {
+			t.Errorf("parseBeacon(%q) = %+v, %v; want %+v", tt.text, got, ok, tt.want)
+		}
+	}
+	if _, ok := parseBeacon("no beacon here"); ok {
+		t.Error("expected no beacon")
+	}
+}
@@ -1,11 +1,13 @@
-// Package claude provides Claude Code configuration management.
+// Package claude provides Claude Code configuration management and session discovery.
 package claude
 
 import (
+	"bytes"
 	"embed"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 )
 
 //go:embed config/*.json
@@ -74,6 +76,7 @@ func EnsureSettingsAt(workDir string, roleType RoleType, settingsDir, settingsFi
 	}
 
 	// Write settings file
+	content = hookCommandsFor(runtime.GOOS, content)
 	if err := os.WriteFile(settingsPath, content, 0600); err != nil {
 		return fmt.Errorf("writing settings: %w", err)
 	}
@@ -81,6 +84,22 @@ func EnsureSettingsAt(workDir string, roleType RoleType, settingsDir, settingsFi
 	return nil
 }
 
+// hookPathPrefix starts each hook command in the settings templates. It
+// puts Go-installed binaries on PATH, in sh syntax.
+const hookPathPrefix = `export PATH=\"$HOME/go/bin:$HOME/bin:$PATH\" && `
+
+// hookCommandsFor adapts a settings template's hook commands to goos. On
+// Windows, Claude Code may run hooks under cmd.exe, which has no export,
+// so the PATH prefix is dropped and gt is found on the user's PATH as
+// the Windows installers leave it. The rest of each command only chains
+// gt calls with &&, which cmd.exe, PowerShell, and sh all accept.
+func hookCommandsFor(goos string, content []byte) []byte {
+	if goos != "windows" {
+		return content
+	}
+	return bytes.ReplaceAll(content, []byte(hookPathPrefix), nil)
+}
+
 // EnsureSettingsForRole is a convenience function that combines RoleTypeFor and EnsureSettings.
 func EnsureSettingsForRole(workDir, role string) error {
 	return EnsureSettings(workDir, RoleTypeFor(role))
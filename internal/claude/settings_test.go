@@ -0,0 +1,28 @@
+package claude
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHookCommandsFor(t *testing.T) {
+	template, err := configFS.ReadFile("config/settings-autonomous.json")
+	if err != nil {
+		t.Fatalf("reading template: %v", err)
+	}
+	if !strings.Contains(string(template), hookPathPrefix) {
+		t.Fatal("template no longer starts hooks with hookPathPrefix")
+	}
+	if got := hookCommandsFor("linux", template); string(got) != string(template) {
+		t.Error("linux settings should be the template unchanged")
+	}
+
+	got := hookCommandsFor("windows", template)
+	if !json.Valid(got) {
+		t.Fatalf("windows settings are not valid JSON:\n%s", got)
+	}
+	if strings.Contains(string(got), "export PATH") || !strings.Contains(string(got), `"command": "gt prime && gt mail check --inject`) {
+		t.Errorf("windows hook commands still use sh syntax:\n%s", got)
+	}
+}
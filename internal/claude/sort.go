@@ -0,0 +1,159 @@
+package claude
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// SessionSort is what a session listing is ordered by (see
+// SessionFilter.SortBy).
+type SessionSort string
+
+const (
+	SortByStart    SessionSort = "start"    // start time (the default)
+	SortByEnd      SessionSort = "end"      // time of the last line
+	SortByDuration SessionSort = "duration" // EndTime minus StartTime
+	SortByRole     SessionSort = "role"     // beacon role
+	SortByRig      SessionSort = "rig"      // beacon rig
+	SortByCost     SessionSort = "cost"     // estimated cost, read from the transcript
+)
+
+// SessionSorts lists the valid SessionSort values.
+var SessionSorts = []SessionSort{SortByStart, SortByEnd, SortByDuration, SortByRole, SortByRig, SortByCost}
+
+// ParseSessionSort parses a sort order name, e.g. from a --sort flag.
+// Empty means SortByStart.
+func ParseSessionSort(s string) (SessionSort, error) {
+	if s == "" {
+		return SortByStart, nil
+	}
+	for _, by := range SessionSorts {
+		if strings.EqualFold(s, string(by)) {
+			return by, nil
+		}
+	}
+	names := make([]string, len(SessionSorts))
+	for i, by := range SessionSorts {
+		names[i] = string(by)
+	}
+	return "", fmt.Errorf("unknown sort %q (want one of %s)", s, strings.Join(names, ", "))
+}
+
+// sessionOrder is how a listing is sorted.
+type sessionOrder struct {
+	by     SessionSort
+	asc    bool
+	prices config.PriceTable
+}
+
+// order returns the sort order filter asks for.
+func (f SessionFilter) order() (sessionOrder, error) {
+	by, err := ParseSessionSort(string(f.SortBy))
+	if err != nil {
+		return sessionOrder{}, err
+	}
+	prices := f.Prices
+	if prices.Custom == nil && prices.Builtin == nil {
+		prices.Builtin = config.DefaultPricing
+	}
+	return sessionOrder{by: by, asc: f.SortAsc, prices: prices}, nil
+}
+
+// sortKey is a session's value for the field a listing is sorted by.
+type sortKey struct {
+	N int64  `json:"n,omitempty"` // times and durations in ns, costs in millionths of a dollar
+	S string `json:"s,omitempty"` // roles and rigs, lowercased
+
+	// Missing is set when the session has no value (no role, unreadable
+	// usage); such sessions sort last either way.
+	Missing bool `json:"m,omitempty"`
+}
+
+// sessionPos is a session's position in a sorted listing.
+type sessionPos struct {
+	Key   sortKey `json:"k"`
+	Start int64   `json:"t"` // UnixNano
+	ID    string  `json:"i"`
+	Path  string  `json:"p"`
+}
+
+// position returns where s sorts in o.
+func (o sessionOrder) position(s SessionInfo) sessionPos {
+	var key sortKey
+	switch o.by {
+	case SortByStart:
+		key = sortKey{N: s.StartTime.UnixNano(), Missing: s.StartTime.IsZero()}
+	case SortByEnd:
+		key = sortKey{N: s.EndTime.UnixNano(), Missing: s.EndTime.IsZero()}
+	case SortByDuration:
+		key = sortKey{N: int64(s.Duration), Missing: s.StartTime.IsZero() || s.EndTime.IsZero()}
+	case SortByRole:
+		key = sortKey{S: strings.ToLower(s.Role), Missing: s.Role == ""}
+	case SortByRig:
+		key = sortKey{S: strings.ToLower(s.Rig), Missing: s.Rig == ""}
+	case SortByCost:
+		if u, err := ReadUsage(s.Path); err == nil {
+			key = sortKey{N: int64(math.Round(EstimateCost(*u, o.prices) * 1e6))}
+		} else {
+			key = sortKey{Missing: true}
+		}
+	}
+	return sessionPos{Key: key, Start: s.StartTime.UnixNano(), ID: s.ID, Path: s.Path}
+}
+
+// before reports whether the session at a sorts before the one at b.
+// Sessions with the same key are ordered most recent first, then by ID
+// and path, so every listing, and so every page, comes out the same.
+func (o sessionOrder) before(a, b sessionPos) bool {
+	if a.Key.Missing != b.Key.Missing {
+		return b.Key.Missing
+	}
+	c := cmp.Compare(a.Key.N, b.Key.N)
+	if c == 0 {
+		c = strings.Compare(a.Key.S, b.Key.S)
+	}
+	if c != 0 {
+		return (c < 0) == o.asc
+	}
+	if a.Start != b.Start {
+		return a.Start > b.Start
+	}
+	if a.ID != b.ID {
+		return a.ID < b.ID
+	}
+	return a.Path < b.Path
+}
+
+// sortSessions sorts sessions in o and returns their positions, in the
+// same order.
+func sortSessions(sessions []SessionInfo, o sessionOrder) []sessionPos {
+	positions := make([]sessionPos, len(sessions))
+	for i, s := range sessions {
+		positions[i] = o.position(s)
+	}
+	sort.Sort(sessionsByPos{sessions, positions, o})
+	return positions
+}
+
+// sessionsByPos sorts sessions along with their positions.
+type sessionsByPos struct {
+	sessions  []SessionInfo
+	positions []sessionPos
+	order     sessionOrder
+}
+
+func (s sessionsByPos) Len() int { return len(s.sessions) }
+
+func (s sessionsByPos) Less(i, j int) bool {
+	return s.order.before(s.positions[i], s.positions[j])
+}
+
+func (s sessionsByPos) Swap(i, j int) {
+	s.sessions[i], s.sessions[j] = s.sessions[j], s.sessions[i]
+	s.positions[i], s.positions[j] = s.positions[j], s.positions[i]
+}
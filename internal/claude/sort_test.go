@@ -0,0 +1,83 @@
+package claude
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiscoverSessionsSortBy(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	session := func(id, role, start, end string, outputTokens int) {
+		beacon := ""
+		if role != "" {
+			beacon = fmt.Sprintf("[GAS TOWN] %s <- mayor • 2025-03-01T00:00 • handoff", role)
+		}
+		writeSession(t, home, "-home-u-proj", id+".jsonl",
+			fmt.Sprintf(`{"type":"user","timestamp":"%s","message":{"role":"user","content":%q}}`, start, beacon),
+			fmt.Sprintf(`{"type":"assistant","timestamp":"%s","message":{"role":"assistant","model":"claude-sonnet-4-5","content":"ok","usage":{"input_tokens":10,"output_tokens":%d}}}`, end, outputTokens),
+		)
+	}
+	// a: oldest, longest, cheapest. b: newest, shortest. c: no role, costliest
+	session("a", "gastown/crew/zoe", "2025-03-01T09:00:00Z", "2025-03-01T12:00:00Z", 10)
+	session("b", "gastown/crew/amy", "2025-03-03T09:00:00Z", "2025-03-03T09:10:00Z", 500)
+	session("c", "", "2025-03-02T09:00:00Z", "2025-03-02T10:00:00Z", 90000)
+
+	for _, tt := range []struct {
+		by   SessionSort
+		asc  bool
+		want string
+	}{
+		{"", false, "b c a"},
+		{SortByStart, true, "a c b"},
+		{SortByEnd, false, "b c a"},
+		{SortByDuration, false, "a c b"},
+		{SortByDuration, true, "b c a"},
+		{SortByRole, false, "a b c"},
+		{SortByRole, true, "b a c"},
+		{SortByCost, false, "c b a"},
+		{SortByCost, true, "a b c"},
+	} {
+		sessions, err := DiscoverSessions(SessionFilter{SortBy: tt.by, SortAsc: tt.asc})
+		if err != nil {
+			t.Fatalf("DiscoverSessions(%s): %v", tt.by, err)
+		}
+		var ids []string
+		for _, s := range sessions {
+			ids = append(ids, s.ID)
+		}
+		if got := strings.Join(ids, " "); got != tt.want {
+			t.Errorf("sort %q asc=%v = %s, want %s", tt.by, tt.asc, got, tt.want)
+		}
+	}
+
+	// Pages follow the sort, and a cursor only continues its own
+	page, err := DiscoverSessionsPage(SessionFilter{SortBy: SortByCost, Limit: 1})
+	if err != nil || len(page.Sessions) != 1 || page.Sessions[0].ID != "c" {
+		t.Fatalf("first cost page = %+v, %v", page, err)
+	}
+	next, err := DiscoverSessionsPage(SessionFilter{SortBy: SortByCost, Limit: 1, Cursor: page.NextCursor})
+	if err != nil || len(next.Sessions) != 1 || next.Sessions[0].ID != "b" {
+		t.Errorf("second cost page = %+v, %v", next, err)
+	}
+	if _, err := DiscoverSessions(SessionFilter{Cursor: page.NextCursor}); err == nil {
+		t.Error("a cost cursor continued a start-time listing")
+	}
+	if _, err := DiscoverSessions(SessionFilter{SortBy: "size"}); err == nil {
+		t.Error("DiscoverSessions accepted an unknown sort")
+	}
+}
+
+func TestParseSessionSort(t *testing.T) {
+	if by, err := ParseSessionSort("Cost"); err != nil || by != SortByCost {
+		t.Errorf("ParseSessionSort(Cost) = %q, %v", by, err)
+	}
+	if by, err := ParseSessionSort(""); err != nil || by != SortByStart {
+		t.Errorf("ParseSessionSort(\"\") = %q, %v", by, err)
+	}
+	if _, err := ParseSessionSort("size"); err == nil || !strings.Contains(err.Error(), "duration") {
+		t.Errorf("ParseSessionSort(size) error = %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package claude
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SessionState is what became of a session, judged from its transcript
+// and the running Claude processes.
+type SessionState string
+
+// Session states.
+const (
+	// StateActive sessions wrote to their transcript in the last few
+	// minutes.
+	StateActive SessionState = "active"
+
+	// StateIdle sessions are quiet: the agent finished its turn, or its
+	// process is still running and waiting.
+	StateIdle SessionState = "idle"
+
+	// StateCrashed sessions ended mid-turn with no process left to
+	// finish it, recently enough that the work is probably still wanted.
+	StateCrashed SessionState = "crashed"
+
+	// StateAbandoned sessions ended mid-turn long ago and were never
+	// picked back up.
+	StateAbandoned SessionState = "abandoned"
+)
+
+// Thresholds for ClassifySession.
+const (
+	stateIdleAfter    = 10 * time.Minute
+	stateAbandonAfter = 24 * time.Hour
+)
+
+// ClassifySession judges a session's state at now. A session that wrote
+// in the last 10 minutes is active. Otherwise it is idle if it finished
+// its turn or running reports a live Claude process for it. A session
+// left mid-turn with no process is crashed, or abandoned once it has been
+// quiet for a day.
+func ClassifySession(s *SessionInfo, now time.Time, running bool) SessionState {
+	age := now.Sub(s.EndTime)
+	switch {
+	case age < stateIdleAfter:
+		return StateActive
+	case !s.Unfinished || running:
+		return StateIdle
+	case age < stateAbandonAfter:
+		return StateCrashed
+	}
+	return StateAbandoned
+}
+
+// claudeProcess is a running Claude Code process.
+type claudeProcess struct {
+	Args string // command line
+	Cwd  string // working directory, when the platform exposes it
+}
+
+// listClaudeProcesses returns the running Claude Code processes. A
+// variable so tests can stub it.
+var listClaudeProcesses = func() ([]claudeProcess, error) {
+	out, err := exec.Command("ps", "-eo", "pid=,args=").Output()
+	if err != nil {
+		return nil, err
+	}
+	var procs []claudeProcess
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !isClaudeCommand(fields[1:]) {
+			continue
+		}
+		p := claudeProcess{Args: strings.Join(fields[1:], " ")}
+		// Linux exposes the working directory; elsewhere Cwd stays empty
+		// and only sessions named on the command line are matched.
+		if cwd, err := os.Readlink(filepath.Join("/proc", fields[0], "cwd")); err == nil {
+			p.Cwd = cwd
+		}
+		procs = append(procs, p)
+	}
+	return procs, nil
+}
+
+// isClaudeCommand reports whether args run Claude Code, directly or as a
+// node script.
+func isClaudeCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	cmd := filepath.Base(args[0])
+	if (cmd == "node" || cmd == "bun") && len(args) > 1 {
+		cmd = filepath.Base(args[1])
+	}
+	return cmd == "claude" || cmd == "claude-code"
+}
+
+// stateClassifier fills in SessionInfo.State during discovery. Processes
+// are listed at most once, and only if some session's state depends on
+// them.
+type stateClassifier struct {
+	now     time.Time
+	byID    map[string]*SessionInfo
+	latest  map[string]*SessionInfo // newest top-level session per project path
+	procs   []claudeProcess
+	listed  bool
+	running map[string]bool
+}
+
+func newStateClassifier(now time.Time, sessions []*SessionInfo) *stateClassifier {
+	c := &stateClassifier{
+		now:     now,
+		byID:    make(map[string]*SessionInfo),
+		latest:  make(map[string]*SessionInfo),
+		running: make(map[string]bool),
+	}
+	for _, s := range sessions {
+		if s == nil {
+			continue
+		}
+		c.byID[s.ID] = s
+		if s.ParentID != "" {
+			continue
+		}
+		if cur, ok := c.latest[s.ProjectPath]; !ok || s.EndTime.After(cur.EndTime) {
+			c.latest[s.ProjectPath] = s
+		}
+	}
+	return c
+}
+
+// reset points c at another batch of sessions, keeping the process
+// listing, so discovery that classifies batch by batch lists processes
+// at most once.
+func (c *stateClassifier) reset(sessions []*SessionInfo) {
+	next := newStateClassifier(c.now, sessions)
+	next.procs, next.listed, next.running = c.procs, c.listed, c.running
+	*c = *next
+}
+
+// classify returns s's state.
+func (c *stateClassifier) classify(s *SessionInfo) SessionState {
+	if !s.Unfinished || c.now.Sub(s.EndTime) < stateIdleAfter {
+		return ClassifySession(s, c.now, false)
+	}
+	return ClassifySession(s, c.now, c.isRunning(s))
+}
+
+// isRunning reports whether a Claude process is running s: one whose
+// command line names it (claude --resume <id>), or a fresh one started in
+// its directory when s is the newest session there. Subagents run as long
+// as their parent does.
+func (c *stateClassifier) isRunning(s *SessionInfo) bool {
+	if s.ParentID != "" {
+		if parent, ok := c.byID[s.ParentID]; ok {
+			return c.isRunning(parent)
+		}
+		return false
+	}
+	if running, ok := c.running[s.ID]; ok {
+		return running
+	}
+	if !c.listed {
+		c.procs, _ = listClaudeProcesses()
+		c.listed = true
+	}
+	running := false
+	for _, p := range c.procs {
+		if strings.Contains(p.Args, s.ID) ||
+			(p.Cwd != "" && p.Cwd == s.ProjectPath && c.latest[s.ProjectPath] == s && !namesSession(p.Args)) {
+			running = true
+			break
+		}
+	}
+	c.running[s.ID] = running
+	return running
+}
+
+// namesSession reports whether a Claude command line names the session
+// it runs. Without one it runs a fresh session, or with --continue the
+// newest one, in its directory.
+func namesSession(args string) bool {
+	for _, flag := range []string{"--resume", "-r ", "--session-id"} {
+		if strings.Contains(args+" ", " "+flag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package claude
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClassifySession(t *testing.T) {
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		ago        time.Duration
+		unfinished bool
+		running    bool
+		want       SessionState
+	}{
+		{"recent", 2 * time.Minute, true, false, StateActive},
+		{"finished turn", time.Hour, false, false, StateIdle},
+		{"waiting process", time.Hour, true, true, StateIdle},
+		{"died mid-task", time.Hour, true, false, StateCrashed},
+		{"died long ago", 3 * 24 * time.Hour, true, false, StateAbandoned},
+		{"finished long ago", 3 * 24 * time.Hour, false, false, StateIdle},
+	}
+	for _, tt := range tests {
+		s := &SessionInfo{EndTime: now.Add(-tt.ago), Unfinished: tt.unfinished}
+		if got := ClassifySession(s, now, tt.running); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiscoverSessionsState(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	old := listClaudeProcesses
+	defer func() { listClaudeProcesses = old }()
+	listed := 0
+	listClaudeProcesses = func() ([]claudeProcess, error) {
+		listed++
+		return []claudeProcess{{Args: "claude --resume resumed1"}}, nil
+	}
+
+	ts := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	prompt := `{"type":"user","timestamp":"` + ts + `","message":{"role":"user","content":"fix it"}}`
+	toolCall := `{"type":"assistant","timestamp":"` + ts + `","message":{"id":"m1","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{}}]}}`
+	reply := `{"type":"assistant","timestamp":"` + ts + `","message":{"id":"m2","content":[{"type":"text","text":"Done."}]}}`
+	interrupt := `{"type":"user","timestamp":"` + ts + `","message":{"role":"user","content":[{"type":"text","text":"[Request interrupted by user]"}]}}`
+
+	writeSession(t, home, "-home-u-a", "finished.jsonl", prompt, reply)
+	writeSession(t, home, "-home-u-b", "crashed1.jsonl", prompt, toolCall)
+	writeSession(t, home, "-home-u-c", "pending1.jsonl", prompt)
+	writeSession(t, home, "-home-u-d", "resumed1.jsonl", prompt, toolCall)
+	writeSession(t, home, "-home-u-e", "stopped1.jsonl", prompt, toolCall, interrupt)
+
+	sessions, err := DiscoverSessions(SessionFilter{})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	want := map[string]SessionState{
+		"finished": StateIdle,
+		"crashed1": StateCrashed,
+		"pending1": StateCrashed,
+		"resumed1": StateIdle, // its process is still running
+		"stopped1": StateIdle,
+	}
+	for _, s := range sessions {
+		if s.State != want[s.ID] {
+			t.Errorf("%s: state %s (unfinished %v), want %s", s.ID, s.State, s.Unfinished, want[s.ID])
+		}
+	}
+	if listed != 1 {
+		t.Errorf("processes listed %d times, want once", listed)
+	}
+
+	crashed, err := DiscoverSessions(SessionFilter{State: StateCrashed})
+	if err != nil || len(crashed) != 2 {
+		t.Errorf("State filter = %d sessions, %v; want 2", len(crashed), err)
+	}
+}
+
+func TestStateClassifierCwd(t *testing.T) {
+	now := time.Now()
+	older := &SessionInfo{ID: "older", ProjectPath: "/w/rig", EndTime: now.Add(-3 * time.Hour), Unfinished: true}
+	newer := &SessionInfo{ID: "newer", ProjectPath: "/w/rig", EndTime: now.Add(-time.Hour), Unfinished: true}
+	sub := &SessionInfo{ID: "agent-1", ParentID: "newer", ProjectPath: "/w/rig", EndTime: now.Add(-time.Hour), Unfinished: true}
+
+	old := listClaudeProcesses
+	defer func() { listClaudeProcesses = old }()
+	listClaudeProcesses = func() ([]claudeProcess, error) {
+		return []claudeProcess{{Args: "claude --dangerously-skip-permissions", Cwd: "/w/rig"}}, nil
+	}
+
+	c := newStateClassifier(now, []*SessionInfo{older, newer, sub})
+	if got := c.classify(newer); got != StateIdle {
+		t.Errorf("newest session in the process's directory = %s, want idle", got)
+	}
+	if got := c.classify(sub); got != StateIdle {
+		t.Errorf("subagent of a running session = %s, want idle", got)
+	}
+	if got := c.classify(older); got != StateCrashed {
+		t.Errorf("older session in the same directory = %s, want crashed", got)
+	}
+}
+
+func TestIsClaudeCommand(t *testing.T) {
+	for args, want := range map[string]bool{
+		"claude --resume abc":                   true,
+		"/usr/local/bin/claude":                 true,
+		"node /opt/node_modules/.bin/claude -c": true,
+		"vim claude.md":                         false,
+		"grep claude":                           false,
+	} {
+		if got := isClaudeCommand(strings.Fields(args)); got != want {
+			t.Errorf("isClaudeCommand(%q) = %v, want %v", args, got, want)
+		}
+	}
+}
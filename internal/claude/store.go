@@ -0,0 +1,81 @@
+package claude
+
+import (
+	"context"
+	"sync"
+)
+
+// SessionStore is where sessions are discovered: the transcripts on disk
+// by default, or e.g. a database index or a remote daemon. DiscoverSessions,
+// FindSession, WatchSessions, and SearchSessions go through the current
+// store, so callers don't change when it does.
+type SessionStore interface {
+	// List returns the sessions filter matches, most recent first, as
+	// DiscoverSessions documents.
+	List(filter SessionFilter) ([]SessionInfo, error)
+
+	// Get returns a session by alias, ID, or unique ID prefix.
+	Get(id string) (*SessionInfo, error)
+
+	// Watch reports new sessions and transcript lines until ctx is
+	// cancelled, as WatchSessions documents.
+	Watch(ctx context.Context, filter SessionFilter) (<-chan SessionEvent, error)
+
+	// Search searches the content of the sessions filter matches.
+	Search(query SearchQuery, filter SessionFilter) ([]SearchMatch, error)
+}
+
+// FileStore is the default SessionStore. It scans the transcripts in the
+// Claude data directories (see Roots), caching parsed headers in the
+// session index.
+type FileStore struct{}
+
+// Verify FileStore implements SessionStore.
+var _ SessionStore = FileStore{}
+
+// List implements SessionStore.
+func (FileStore) List(filter SessionFilter) ([]SessionInfo, error) {
+	return scanSessions(filter)
+}
+
+// Get implements SessionStore.
+func (FileStore) Get(id string) (*SessionInfo, error) {
+	return findSessionFile(id)
+}
+
+// Watch implements SessionStore.
+func (FileStore) Watch(ctx context.Context, filter SessionFilter) (<-chan SessionEvent, error) {
+	return watchSessionFiles(ctx, filter)
+}
+
+// Search implements SessionStore.
+func (FileStore) Search(query SearchQuery, filter SessionFilter) ([]SearchMatch, error) {
+	return searchSessionFiles(query, filter)
+}
+
+var (
+	storeMu sync.RWMutex
+	store   SessionStore = FileStore{}
+)
+
+// SetStore makes session discovery use s instead of the current store,
+// e.g. to swap in another backend or a fake in tests. It returns a
+// function that restores the previous store.
+func SetStore(s SessionStore) (restore func()) {
+	storeMu.Lock()
+	prev := store
+	store = s
+	storeMu.Unlock()
+	return func() {
+		storeMu.Lock()
+		store = prev
+		storeMu.Unlock()
+	}
+}
+
+// currentStore returns the store discovery goes through.
+func currentStore() SessionStore {
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	return store
+}
@@ -0,0 +1,71 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// fakeStore serves a fixed set of sessions.
+type fakeStore struct {
+	sessions []SessionInfo
+}
+
+func (f fakeStore) List(filter SessionFilter) ([]SessionInfo, error) {
+	var out []SessionInfo
+	for _, s := range f.sessions {
+		if filter.matches(&s) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (f fakeStore) Get(id string) (*SessionInfo, error) {
+	for _, s := range f.sessions {
+		if s.ID == id {
+			return &s, nil
+		}
+	}
+	return nil, fmt.Errorf("session %s not found", id)
+}
+
+func (f fakeStore) Watch(ctx context.Context, filter SessionFilter) (<-chan SessionEvent, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (f fakeStore) Search(query SearchQuery, filter SessionFilter) ([]SearchMatch, error) {
+	return nil, nil
+}
+
+func TestSetStore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-home-u-proj", "ondisk.jsonl",
+		`{"type":"user","timestamp":"2025-03-01T12:00:00Z","message":{"role":"user","content":"hi"}}`,
+	)
+
+	restore := SetStore(fakeStore{sessions: []SessionInfo{
+		{ID: "remote-1", Role: "gastown/crew/joe", IsGasTown: true},
+		{ID: "remote-2"},
+	}})
+	sessions, err := DiscoverSessions(SessionFilter{GasTownOnly: true})
+	if err != nil {
+		t.Fatalf("DiscoverSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "remote-1" {
+		t.Errorf("DiscoverSessions = %+v, want the store's Gas Town session", sessions)
+	}
+	if s, err := FindSession("remote-2"); err != nil || s.ID != "remote-2" {
+		t.Errorf("FindSession(remote-2) = %v, %v", s, err)
+	}
+	if _, err := FindSession("ondisk"); err == nil {
+		t.Error("FindSession found a transcript on disk while another store was set")
+	}
+
+	restore()
+	if s, err := FindSession("ondisk"); err != nil || s.ID != "ondisk" {
+		t.Errorf("after restore, FindSession(ondisk) = %v, %v", s, err)
+	}
+}
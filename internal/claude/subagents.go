@@ -0,0 +1,200 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// subagentTools are the tool names Claude Code uses to delegate to a subagent.
+var subagentTools = map[string]bool{"Task": true, "Agent": true}
+
+// SubagentCall is one delegation to a subagent via the Task tool.
+type SubagentCall struct {
+	ToolUseID   string    `json:"tool_use_id"`
+	AgentID     string    `json:"agent_id,omitempty"`
+	Type        string    `json:"type,omitempty"` // subagent_type, e.g. "general-purpose"
+	Description string    `json:"description,omitempty"`
+	Prompt      string    `json:"prompt,omitempty"`
+	Result      string    `json:"result,omitempty"` // final text the subagent returned
+	Timestamp   time.Time `json:"timestamp"`
+
+	// AfterTurn is how many turns (as returned by ReadTurns) precede the
+	// subagent's result, for rendering it inline.
+	AfterTurn int `json:"after_turn"`
+}
+
+// subagentBlock is a tool_use or tool_result content block.
+type subagentBlock struct {
+	Type      string          `json:"type"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	Content   json.RawMessage `json:"content,omitempty"`
+}
+
+// ReadSubagentCalls returns the Task tool delegations in a transcript, in
+// the order their results arrived. Calls still running have no Result.
+func ReadSubagentCalls(path string) ([]SubagentCall, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	pending := make(map[string]*SubagentCall)
+	var order []string
+	var calls []SubagentCall
+	turns := 0
+
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if _, ok := ParseTurn(line); ok {
+			turns++
+		}
+
+		entry, err := decodeEntry(line)
+		if err != nil {
+			continue
+		}
+		var blocks []subagentBlock
+		if err := json.Unmarshal(entry.body().Content, &blocks); err != nil {
+			continue
+		}
+
+		for _, b := range blocks {
+			switch {
+			case b.Type == "tool_use" && subagentTools[b.Name]:
+				var input struct {
+					Description  string `json:"description"`
+					Prompt       string `json:"prompt"`
+					SubagentType string `json:"subagent_type"`
+				}
+				_ = json.Unmarshal(b.Input, &input)
+				call := &SubagentCall{
+					ToolUseID:   b.ID,
+					Type:        input.SubagentType,
+					Description: input.Description,
+					Prompt:      input.Prompt,
+				}
+				call.Timestamp = entry.Timestamp
+				pending[b.ID] = call
+				order = append(order, b.ID)
+
+			case b.Type == "tool_result":
+				call, ok := pending[b.ToolUseID]
+				if !ok {
+					continue
+				}
+				delete(pending, b.ToolUseID)
+				call.Result = toolResultText(b.Content)
+				call.AgentID = toolUseResultAgentID(entry.ToolUseResult)
+				call.AfterTurn = turns
+				calls = append(calls, *call)
+			}
+		}
+	}
+
+	// Calls without a result yet (still running, or session ended) go last
+	for _, id := range order {
+		if call, ok := pending[id]; ok {
+			call.AfterTurn = turns
+			calls = append(calls, *call)
+		}
+	}
+	return calls, scanner.Err()
+}
+
+// toolResultText extracts the text of a tool_result's content, which is
+// either a string or an array of text blocks.
+func toolResultText(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.TrimSpace(s)
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" && b.Text != "" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n"))
+}
+
+// toolUseResultAgentID returns the agentId Claude Code records for a
+// completed Task call, if any.
+func toolUseResultAgentID(raw json.RawMessage) string {
+	var result struct {
+		AgentID string `json:"agentId"`
+	}
+	if len(raw) == 0 || json.Unmarshal(raw, &result) != nil {
+		return ""
+	}
+	return result.AgentID
+}
+
+// SubagentTranscript returns the path of the transcript for a subagent call
+// made in the session at sessionPath, or "" if it can't be found.
+// Claude Code writes subagent transcripts as agent-<id>.jsonl, either next
+// to the session or under <session-id>/subagents/. Calls without a recorded
+// agent ID are matched by their prompt.
+func SubagentTranscript(sessionPath string, call SubagentCall) string {
+	dir := filepath.Dir(sessionPath)
+	sessionID := strings.TrimSuffix(filepath.Base(sessionPath), ".jsonl")
+	dirs := []string{dir, filepath.Join(dir, sessionID, "subagents")}
+
+	if call.AgentID != "" {
+		for _, d := range dirs {
+			path := filepath.Join(d, "agent-"+call.AgentID+".jsonl")
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+
+	if call.Prompt == "" {
+		return ""
+	}
+	for _, d := range dirs {
+		matches, _ := filepath.Glob(filepath.Join(d, "agent-*.jsonl"))
+		for _, path := range matches {
+			if firstUserText(path) == strings.TrimSpace(call.Prompt) {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// firstUserText returns the text of the first user turn in a transcript.
+func firstUserText(path string) string {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a subagent transcript
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+	for scanner.Scan() {
+		if turn, ok := ParseTurn(scanner.Bytes()); ok && turn.Role == "user" {
+			return turn.Text
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadSubagentCalls(t *testing.T) {
+	dir := t.TempDir()
+	sessionPath := filepath.Join(dir, "sess-1.jsonl")
+	lines := []string{
+		`{"type":"user","timestamp":"2025-01-01T10:00:00Z","message":{"role":"user","content":"Audit auth"}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T10:00:05Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"toolu_1","name":"Task","input":{"description":"Find auth bugs","prompt":"Look for auth bugs","subagent_type":"general-purpose"}}]}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T10:00:06Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"toolu_2","name":"Task","input":{"description":"Check tests","prompt":"Run the auth tests"}}]}}`,
+		`{"type":"user","timestamp":"2025-01-01T10:02:00Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"Found 2 bugs.\nDetails follow."}]}]},"toolUseResult":{"agentId":"abc123","status":"completed"}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T10:02:05Z","message":{"role":"assistant","content":[{"type":"text","text":"Fixing them"}]}}`,
+	}
+	if err := os.WriteFile(sessionPath, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	calls, err := ReadSubagentCalls(sessionPath)
+	if err != nil {
+		t.Fatalf("ReadSubagentCalls: %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("got %d calls, want 2", len(calls))
+	}
+
+	done := calls[0]
+	if done.ToolUseID != "toolu_1" || done.AgentID != "abc123" || done.Type != "general-purpose" {
+		t.Errorf("completed call = %+v", done)
+	}
+	if done.Result != "Found 2 bugs.\nDetails follow." {
+		t.Errorf("Result = %q", done.Result)
+	}
+	if done.AfterTurn != 3 {
+		t.Errorf("AfterTurn = %d, want 3", done.AfterTurn)
+	}
+
+	running := calls[1]
+	if running.ToolUseID != "toolu_2" || running.Result != "" || running.AfterTurn != 4 {
+		t.Errorf("running call = %+v", running)
+	}
+
+	// Transcript lookup by agent ID (in the subagents dir) and by prompt.
+	subDir := filepath.Join(dir, "sess-1", "subagents")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	byID := filepath.Join(subDir, "agent-abc123.jsonl")
+	byPrompt := filepath.Join(dir, "agent-zzz.jsonl")
+	child := `{"type":"user","message":{"role":"user","content":"Run the auth tests"}}` + "\n"
+	if err := os.WriteFile(byID, []byte(child), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(byPrompt, []byte(child), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := SubagentTranscript(sessionPath, done); got != byID {
+		t.Errorf("SubagentTranscript(by id) = %q, want %q", got, byID)
+	}
+	if got := SubagentTranscript(sessionPath, running); got != byPrompt {
+		t.Errorf("SubagentTranscript(by prompt) = %q, want %q", got, byPrompt)
+	}
+	if got := SubagentTranscript(sessionPath, SubagentCall{Prompt: "something else"}); got != "" {
+		t.Errorf("SubagentTranscript(no match) = %q, want empty", got)
+	}
+}
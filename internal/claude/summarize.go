@@ -0,0 +1,130 @@
+package claude
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SummarizeOptions control GenerateSummary.
+type SummarizeOptions struct {
+	// Turns is how many of the session's first user and assistant turns
+	// the model sees. Default 6.
+	Turns int
+
+	// MaxTurnChars truncates each turn's text. Default 1500.
+	MaxTurnChars int
+
+	// Summarize sends a prompt to a model and returns its reply, e.g. by
+	// running "claude --print". Required.
+	Summarize func(prompt string) (string, error)
+}
+
+func (o SummarizeOptions) withDefaults() SummarizeOptions {
+	if o.Turns <= 0 {
+		o.Turns = 6
+	}
+	if o.MaxTurnChars <= 0 {
+		o.MaxTurnChars = 1500
+	}
+	return o
+}
+
+// maxSummaryLen caps a generated summary, in runes.
+const maxSummaryLen = 120
+
+// SummaryPrompt builds the prompt GenerateSummary sends for s: an
+// instruction followed by the session's opening turns.
+func SummaryPrompt(s SessionInfo, opts SummarizeOptions) (string, error) {
+	opts = opts.withDefaults()
+	turns, err := ReadTurns(s.Path)
+	if err != nil {
+		return "", fmt.Errorf("reading transcript: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("Below are the opening turns of a Claude Code session. Reply with a single line, " +
+		"under 80 characters, summarizing what the session set out to do, in the style of a " +
+		"commit subject. Reply with the summary only.\n")
+	n := 0
+	for _, t := range turns {
+		if t.Text == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n[%s]\n%s\n", t.Role, truncateText(t.Text, opts.MaxTurnChars))
+		if n++; n == opts.Turns {
+			break
+		}
+	}
+	if n == 0 {
+		return "", fmt.Errorf("session %s has no turns to summarize", s.ShortID())
+	}
+	return b.String(), nil
+}
+
+// GenerateSummary returns a one-line summary of a session that has no
+// summary line, asking a model for it from the session's opening turns.
+// Summaries are kept in the annotations file by session ID, so each is
+// generated once; later discoveries fill them in as s.Summary with
+// SummaryGenerated set. A session that has a summary line is returned
+// as is.
+func GenerateSummary(s SessionInfo, opts SummarizeOptions) (string, error) {
+	if s.Summary != "" {
+		return s.Summary, nil
+	}
+	if opts.Summarize == nil {
+		return "", fmt.Errorf("no summarizer configured")
+	}
+	if cached, ok := loadAnnotations().Summaries[s.ID]; ok {
+		return cached, nil
+	}
+
+	prompt, err := SummaryPrompt(s, opts)
+	if err != nil {
+		return "", err
+	}
+	reply, err := opts.Summarize(prompt)
+	if err != nil {
+		return "", fmt.Errorf("summarizing %s: %w", s.ShortID(), err)
+	}
+	summary := cleanSummary(reply)
+	if summary == "" {
+		return "", fmt.Errorf("summarizing %s: empty reply", s.ShortID())
+	}
+
+	var old string
+	err = updateAnnotations(func(a *sessionAnnotations) bool {
+		old = a.Summaries[s.ID]
+		if a.Summaries == nil {
+			a.Summaries = make(map[string]string)
+		}
+		a.Summaries[s.ID] = summary
+		return summary != old
+	})
+	if err != nil {
+		return "", fmt.Errorf("storing summary: %w", err)
+	}
+	if summary != old {
+		if err := recordOverlayChanges(OverlayChange{Session: s.ID, Kind: OverlaySummary, Op: OverlaySet, Value: summary, Old: old}); err != nil {
+			return "", err
+		}
+	}
+	return summary, nil
+}
+
+// cleanSummary reduces a model reply to one line: the first non-empty
+// line, without surrounding quotes or a "Summary:" label, capped at
+// maxSummaryLen.
+func cleanSummary(reply string) string {
+	var line string
+	for _, l := range strings.Split(reply, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			line = l
+			break
+		}
+	}
+	if label, rest, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(label), "summary") {
+		line = strings.TrimSpace(rest)
+	}
+	line = strings.Trim(line, "\"'`")
+	return truncateText(line, maxSummaryLen)
+}
@@ -0,0 +1,87 @@
+package claude
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSummary(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-home-u-proj", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-03-01T12:00:00Z","message":{"role":"user","content":"Please fix the flaky login test"}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:00:05Z","message":{"id":"m1","content":[{"type":"text","text":"Looking at the test now."}]}}`,
+		`{"type":"user","timestamp":"2025-03-01T12:01:00Z","message":{"role":"user","content":"Also update the docs"}}`,
+	)
+
+	sessions, err := DiscoverSessions(SessionFilter{})
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("DiscoverSessions = %v, %v", sessions, err)
+	}
+	s := sessions[0]
+	if s.Summary != "" {
+		t.Fatalf("Summary = %q before generating", s.Summary)
+	}
+
+	calls := 0
+	var prompt string
+	opts := SummarizeOptions{Turns: 2, Summarize: func(p string) (string, error) {
+		calls++
+		prompt = p
+		return "\nSummary: \"Fix the flaky login test\"\nextra chatter\n", nil
+	}}
+	got, err := GenerateSummary(s, opts)
+	if err != nil {
+		t.Fatalf("GenerateSummary: %v", err)
+	}
+	if got != "Fix the flaky login test" {
+		t.Errorf("summary = %q", got)
+	}
+	if !strings.Contains(prompt, "flaky login test") || !strings.Contains(prompt, "Looking at the test") || strings.Contains(prompt, "update the docs") {
+		t.Errorf("prompt should hold the first 2 turns only:\n%s", prompt)
+	}
+
+	// Cached: no second call, and discovery fills it in
+	if _, err := GenerateSummary(s, opts); err != nil || calls != 1 {
+		t.Errorf("second GenerateSummary: %v, %d calls", err, calls)
+	}
+	sessions, err = DiscoverSessions(SessionFilter{})
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("DiscoverSessions = %v, %v", sessions, err)
+	}
+	if sessions[0].Summary != "Fix the flaky login test" || !sessions[0].SummaryGenerated {
+		t.Errorf("discovered summary = %q, generated %v", sessions[0].Summary, sessions[0].SummaryGenerated)
+	}
+
+	// Survives an index reset
+	if err := InvalidateSessionIndex(); err != nil {
+		t.Fatalf("InvalidateSessionIndex: %v", err)
+	}
+	if sessions, _ = DiscoverSessions(SessionFilter{}); len(sessions) != 1 || !sessions[0].SummaryGenerated {
+		t.Errorf("summary lost after index reset: %+v", sessions)
+	}
+}
+
+func TestGenerateSummaryErrors(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	s := SessionInfo{ID: "bbbb2222", Summary: "Existing"}
+	if got, err := GenerateSummary(s, SummarizeOptions{}); err != nil || got != "Existing" {
+		t.Errorf("existing summary = %q, %v", got, err)
+	}
+
+	s = SessionInfo{ID: "bbbb2222", Path: writeSession(t, t.TempDir(), "p", "bbbb2222.jsonl",
+		`{"type":"user","message":{"role":"user","content":"hi"}}`)}
+	if _, err := GenerateSummary(s, SummarizeOptions{}); err == nil {
+		t.Error("want error without a summarizer")
+	}
+	fail := SummarizeOptions{Summarize: func(string) (string, error) { return "", errors.New("boom") }}
+	if _, err := GenerateSummary(s, fail); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("err = %v, want the summarizer's error", err)
+	}
+	blank := SummarizeOptions{Summarize: func(string) (string, error) { return "  \n", nil }}
+	if _, err := GenerateSummary(s, blank); err == nil {
+		t.Error("want error for an empty reply")
+	}
+}
@@ -0,0 +1,128 @@
+package claude
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Hand-added tags label sessions the town's session_tags rules don't, e.g.
+// from the dashboard. They are kept in the annotations file, so they
+// survive index rebuilds. Discovery merges them into SessionInfo.Tags, and
+// sets a session's hand-added note, if any, as SessionInfo.Note.
+
+// ValidateTag reports whether tag can label a session: letters, digits,
+// '-', '_', '.', and ':'.
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("empty tag")
+	}
+	for _, r := range tag {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' || r == ':') {
+			return fmt.Errorf("tag %q may only contain letters, digits, '-', '_', '.', and ':'", tag)
+		}
+	}
+	return nil
+}
+
+// TagSession adds tag to the session with the given full ID. Adding a tag
+// the session already has is not an error.
+func TagSession(id, tag string) error {
+	if err := ValidateTag(tag); err != nil {
+		return err
+	}
+	added := false
+	err := updateAnnotations(func(a *sessionAnnotations) bool {
+		if slices.Contains(a.Tags[id], tag) {
+			return false
+		}
+		if a.Tags == nil {
+			a.Tags = make(map[string][]string)
+		}
+		a.Tags[id] = mergeTags(a.Tags[id], []string{tag})
+		added = true
+		return true
+	})
+	if err != nil || !added {
+		return err
+	}
+	return recordOverlayChanges(OverlayChange{Session: id, Kind: OverlayTag, Op: OverlayAdd, Value: tag})
+}
+
+// UntagSession removes a hand-added tag from the session with the given
+// full ID. Tags from session_tags rules can't be removed this way.
+func UntagSession(id, tag string) error {
+	found := false
+	err := updateAnnotations(func(a *sessionAnnotations) bool {
+		tags := a.Tags[id]
+		i := slices.Index(tags, tag)
+		if i < 0 {
+			return false
+		}
+		if tags = slices.Delete(tags, i, i+1); len(tags) == 0 {
+			delete(a.Tags, id)
+		} else {
+			a.Tags[id] = tags
+		}
+		found = true
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("session %s has no tag %q", id, tag)
+	}
+	return recordOverlayChanges(OverlayChange{Session: id, Kind: OverlayTag, Op: OverlayRemove, Old: tag})
+}
+
+// TagsOf returns the tags added by hand to the session id, sorted.
+func TagsOf(id string) []string {
+	return slices.Clone(loadAnnotations().Tags[id])
+}
+
+// SetSessionNote sets the note on the session with the given full ID,
+// replacing any earlier one. An empty note removes it.
+func SetSessionNote(id, note string) error {
+	note = strings.TrimSpace(note)
+	var change *OverlayChange
+	err := updateAnnotations(func(a *sessionAnnotations) bool {
+		old, had := a.Notes[id]
+		if note == old && (had || note == "") {
+			return false
+		}
+		change = &OverlayChange{Session: id, Kind: OverlayNote, Op: OverlaySet, Value: note, Old: old}
+		if note == "" {
+			delete(a.Notes, id)
+			change.Op = OverlayClear
+		} else {
+			if a.Notes == nil {
+				a.Notes = make(map[string]string)
+			}
+			a.Notes[id] = note
+		}
+		return true
+	})
+	if err != nil || change == nil {
+		return err
+	}
+	return recordOverlayChanges(*change)
+}
+
+// NoteOf returns the note added by hand to the session id, or "".
+func NoteOf(id string) string {
+	return loadAnnotations().Notes[id]
+}
+
+// mergeTags returns the sorted union of two tag lists.
+func mergeTags(a, b []string) []string {
+	out := slices.Clone(a)
+	for _, tag := range b {
+		if !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
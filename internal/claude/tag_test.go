@@ -0,0 +1,78 @@
+package claude
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTagSession(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-home-u-proj", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":"hello"}}`,
+	)
+
+	if err := TagSession("aaaa1111", "needs review"); err == nil {
+		t.Error("TagSession accepted a tag with a space")
+	}
+	for _, tag := range []string{"review", "incident:42", "review"} {
+		if err := TagSession("aaaa1111", tag); err != nil {
+			t.Fatalf("TagSession(%s): %v", tag, err)
+		}
+	}
+	if got := TagsOf("aaaa1111"); !slices.Equal(got, []string{"incident:42", "review"}) {
+		t.Errorf("TagsOf = %v", got)
+	}
+
+	// Discovery merges them, and they survive an index reset
+	if err := InvalidateSessionIndex(); err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := DiscoverSessions(SessionFilter{Tag: "review"})
+	if err != nil || len(sessions) != 1 || !slices.Equal(sessions[0].Tags, []string{"incident:42", "review"}) {
+		t.Fatalf("DiscoverSessions(tag=review) = %+v, %v", sessions, err)
+	}
+
+	if err := UntagSession("aaaa1111", "review"); err != nil {
+		t.Fatalf("UntagSession: %v", err)
+	}
+	if err := UntagSession("aaaa1111", "review"); err == nil {
+		t.Error("UntagSession removed a tag the session doesn't have")
+	}
+	if sessions, _ := DiscoverSessions(SessionFilter{Tag: "review"}); len(sessions) != 0 {
+		t.Errorf("untagged session still matches: %+v", sessions)
+	}
+}
+
+func TestSetSessionNote(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-home-u-proj", "aaaa1111.jsonl",
+		`{"type":"user","timestamp":"2025-12-31T09:00:00Z","message":{"role":"user","content":"hello"}}`,
+	)
+
+	if err := SetSessionNote("aaaa1111", "  good handoff; resume from here \n"); err != nil {
+		t.Fatalf("SetSessionNote: %v", err)
+	}
+	if got := NoteOf("aaaa1111"); got != "good handoff; resume from here" {
+		t.Errorf("NoteOf = %q", got)
+	}
+
+	// Discovery sets it, and it survives an index reset
+	if err := InvalidateSessionIndex(); err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := DiscoverSessions(SessionFilter{})
+	if err != nil || len(sessions) != 1 || sessions[0].Note != "good handoff; resume from here" {
+		t.Fatalf("DiscoverSessions = %+v, %v", sessions, err)
+	}
+
+	if err := SetSessionNote("aaaa1111", ""); err != nil {
+		t.Fatalf("clearing note: %v", err)
+	}
+	if got := NoteOf("aaaa1111"); got != "" {
+		t.Errorf("NoteOf after clearing = %q", got)
+	}
+}
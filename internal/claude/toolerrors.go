@@ -0,0 +1,95 @@
+package claude
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// unknownTool names failed calls whose tool_use wasn't found, such as
+// one made before a compaction trimmed the transcript.
+const unknownTool = "unknown"
+
+// toolBlock is the subset of a content block needed to pair tool calls
+// with their results.
+type toolBlock struct {
+	Type      string `json:"type"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+// toolBlocks returns a message's content blocks, or nil when its content
+// is plain text.
+func toolBlocks(raw json.RawMessage) []toolBlock {
+	var msg entryMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &msg) != nil {
+		return nil
+	}
+	var blocks []toolBlock
+	if json.Unmarshal(msg.Content, &blocks) != nil {
+		return nil
+	}
+	return blocks
+}
+
+// countToolUses counts an assistant message's tool calls, remembering
+// each call's tool in names so its result can be attributed.
+func (s *SessionInfo) countToolUses(raw json.RawMessage, names map[string]string) {
+	for _, b := range toolBlocks(raw) {
+		if b.Type == "tool_use" {
+			s.ToolCalls++
+			names[b.ID] = b.Name
+			s.countMCPCall(b.Name)
+		}
+	}
+}
+
+// countToolErrors counts the failed tool results in a user message by the
+// tool that was called.
+func (s *SessionInfo) countToolErrors(raw json.RawMessage, names map[string]string) {
+	for _, b := range toolBlocks(raw) {
+		if b.Type != "tool_result" || !b.IsError {
+			continue
+		}
+		tool := names[b.ToolUseID]
+		if tool == "" {
+			tool = unknownTool
+		}
+		if s.ToolErrors == nil {
+			s.ToolErrors = make(map[string]int)
+		}
+		s.ToolErrors[tool]++
+		s.ErrorCount++
+	}
+}
+
+// ErrorRate is the fraction of the session's tool calls that failed, or 0
+// when it made none.
+func (s SessionInfo) ErrorRate() float64 {
+	if s.ToolCalls == 0 {
+		return 0
+	}
+	return float64(s.ErrorCount) / float64(s.ToolCalls)
+}
+
+// ToolErrorCount is a tool and how many of its calls failed.
+type ToolErrorCount struct {
+	Tool   string `json:"tool"`
+	Errors int    `json:"errors"`
+}
+
+// ToolErrorBreakdown returns ToolErrors as a list, most failures first.
+func (s SessionInfo) ToolErrorBreakdown() []ToolErrorCount {
+	out := make([]ToolErrorCount, 0, len(s.ToolErrors))
+	for tool, n := range s.ToolErrors {
+		out = append(out, ToolErrorCount{Tool: tool, Errors: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Errors != out[j].Errors {
+			return out[i].Errors > out[j].Errors
+		}
+		return out[i].Tool < out[j].Tool
+	})
+	return out
+}
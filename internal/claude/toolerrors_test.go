@@ -0,0 +1,57 @@
+package claude
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToolErrorCounts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	writeSession(t, home, "-home-u-proj", "flaky.jsonl",
+		`{"type":"user","timestamp":"2025-03-01T12:00:00Z","message":{"role":"user","content":"fix it"}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:00:01Z","message":{"id":"m1","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test"}},{"type":"tool_use","id":"t2","name":"Edit","input":{}}]}}`,
+		`{"type":"user","timestamp":"2025-03-01T12:00:02Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"FAIL","is_error":true},{"type":"tool_result","tool_use_id":"t2","content":"String not found","is_error":true}]}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:00:03Z","message":{"id":"m2","content":[{"type":"tool_use","id":"t3","name":"Bash","input":{"command":"go test"}}]}}`,
+		`{"type":"user","timestamp":"2025-03-01T12:00:04Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t3","content":"FAIL","is_error":true}]}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:00:05Z","message":{"id":"m3","content":[{"type":"tool_use","id":"t4","name":"Read","input":{}}]}}`,
+		`{"type":"user","timestamp":"2025-03-01T12:00:06Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t4","content":"ok"},{"type":"tool_result","tool_use_id":"gone","content":"x","is_error":true}]}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:00:07Z","isSidechain":true,"message":{"id":"s1","content":[{"type":"tool_use","id":"s1","name":"Bash","input":{}}]}}`,
+	)
+
+	sessions, err := DiscoverSessions(SessionFilter{})
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("DiscoverSessions = %v, %v", sessions, err)
+	}
+	s := sessions[0]
+	if s.ToolCalls != 4 || s.ErrorCount != 4 {
+		t.Fatalf("tool calls %d, errors %d", s.ToolCalls, s.ErrorCount)
+	}
+	if s.ToolErrors["Bash"] != 2 || s.ToolErrors["Edit"] != 1 || s.ToolErrors[unknownTool] != 1 {
+		t.Errorf("ToolErrors = %v", s.ToolErrors)
+	}
+	if got := s.ToolErrorBreakdown(); got[0].Tool != "Bash" || got[1].Tool != "Edit" {
+		t.Errorf("breakdown = %+v", got)
+	}
+	if s.ErrorRate() != 1 {
+		t.Errorf("ErrorRate = %v", s.ErrorRate())
+	}
+	if (SessionInfo{}).ErrorRate() != 0 {
+		t.Error("ErrorRate without tool calls is not 0")
+	}
+}
+
+func TestFlakyWarning(t *testing.T) {
+	flaky := SessionInfo{ToolCalls: 20, ErrorCount: 8, ToolErrors: map[string]int{"Bash": 6, "Edit": 2}}
+	w := flaky.Warnings(Limits{})
+	if len(w) != 1 || w[0].Glyph != "‼" || !strings.Contains(w[0].Reason, "8 of 20") || !strings.Contains(w[0].Reason, "mostly Bash") {
+		t.Errorf("flaky warnings = %+v", w)
+	}
+	if got := (SessionInfo{ToolCalls: 4, ErrorCount: 3}).Warnings(Limits{}); len(got) != 0 {
+		t.Errorf("too few errors to judge = %v", got)
+	}
+	if got := flaky.Warnings(Limits{MaxErrorRate: 0.5}); len(got) != 0 {
+		t.Errorf("under a 50%% limit = %v", got)
+	}
+}
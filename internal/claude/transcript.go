@@ -0,0 +1,224 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Transcript is a whole session transcript parsed into typed messages,
+// in file order.
+type Transcript struct {
+	Session  *SessionInfo `json:"session"`
+	Messages []Message    `json:"messages"`
+
+	// Skipped counts lines that were not valid JSON (e.g., a line cut
+	// short by a crash).
+	Skipped int `json:"skipped,omitempty"`
+}
+
+// Message is one transcript line. Assistant replies Claude Code split
+// across several lines share a MessageID.
+type Message struct {
+	Line        int       `json:"line"` // 1-based line in the JSONL
+	Type        string    `json:"type"` // user, assistant, system, summary, ...
+	UUID        string    `json:"uuid,omitempty"`
+	ParentUUID  string    `json:"parent_uuid,omitempty"`
+	MessageID   string    `json:"message_id,omitempty"` // API message ID (assistant)
+	Role        string    `json:"role,omitempty"`
+	Model       string    `json:"model,omitempty"`
+	Timestamp   time.Time `json:"timestamp,omitempty"`
+	IsSidechain bool      `json:"is_sidechain,omitempty"` // subagent traffic
+	Cwd         string    `json:"cwd,omitempty"`          // working directory when written
+	GitBranch   string    `json:"git_branch,omitempty"`   // branch checked out when written
+	Version     string    `json:"version,omitempty"`      // Claude Code version that wrote the line
+
+	Text        string       `json:"text,omitempty"`     // text blocks, or the summary
+	Thinking    string       `json:"thinking,omitempty"` // thinking blocks
+	ToolUses    []ToolUse    `json:"tool_uses,omitempty"`
+	ToolResults []ToolResult `json:"tool_results,omitempty"`
+	Usage       *TokenUsage  `json:"usage,omitempty"`
+
+	// Raw is the line verbatim when its type is one Gas Town doesn't
+	// recognize, so a newer Claude Code's entries survive a round trip.
+	Raw json.RawMessage `json:"raw,omitempty"`
+}
+
+// ToolUse is a tool_use content block.
+type ToolUse struct {
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+// ToolResult is a tool_result content block.
+type ToolResult struct {
+	ToolUseID string `json:"tool_use_id"`
+	Content   string `json:"content,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+// TokenUsage is the usage reported on one assistant line.
+type TokenUsage struct {
+	InputTokens         int64 `json:"input_tokens"`
+	OutputTokens        int64 `json:"output_tokens"`
+	CacheCreationTokens int64 `json:"cache_creation_tokens"`
+	CacheReadTokens     int64 `json:"cache_read_tokens"`
+}
+
+// LoadTranscript parses a whole session. ref is a session ID (or alias or
+// unique prefix, see FindSession) or a path to a JSONL transcript.
+func LoadTranscript(ref string) (*Transcript, error) {
+	var session *SessionInfo
+	if strings.HasSuffix(ref, ".jsonl") || strings.ContainsRune(ref, filepath.Separator) {
+		info, err := parseSession(ref, filepath.Base(filepath.Dir(ref)))
+		if err != nil {
+			return nil, err
+		}
+		session = info
+	} else {
+		info, err := FindSession(ref)
+		if err != nil {
+			return nil, err
+		}
+		session = info
+	}
+
+	t, err := ReadTranscript(session.Path)
+	if err != nil {
+		return nil, err
+	}
+	t.Session = session
+	return t, nil
+}
+
+// ReadTranscript parses every line of the transcript at path. Session is
+// left nil; use LoadTranscript to fill it in.
+func ReadTranscript(path string) (*Transcript, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	t := &Transcript{}
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		entry, err := decodeEntry(scanner.Bytes())
+		if err != nil {
+			t.Skipped++
+			continue
+		}
+		m := entry.message(line)
+		if !entry.known() {
+			m.Raw = append(json.RawMessage(nil), scanner.Bytes()...)
+		}
+		t.Messages = append(t.Messages, m)
+	}
+	return t, scanner.Err()
+}
+
+// message converts a decoded line into a Message.
+func (e *logEntry) message(line int) Message {
+	body := e.body()
+	m := Message{
+		Line:        line,
+		Type:        e.Type,
+		UUID:        e.UUID,
+		ParentUUID:  e.ParentUUID,
+		MessageID:   body.ID,
+		Role:        body.Role,
+		Model:       body.Model,
+		Timestamp:   e.Timestamp,
+		IsSidechain: e.IsSidechain,
+		Cwd:         e.Cwd,
+		GitBranch:   e.GitBranch,
+		Version:     e.Version,
+	}
+	if u := body.Usage; u != nil {
+		m.Usage = &TokenUsage{
+			InputTokens:         u.InputTokens,
+			OutputTokens:        u.OutputTokens,
+			CacheCreationTokens: u.CacheCreationInputTokens,
+			CacheReadTokens:     u.CacheReadInputTokens,
+		}
+	}
+
+	switch e.Type {
+	case "summary":
+		m.Text = e.Summary
+		return m
+	case "system":
+		m.Text = e.Content
+		return m
+	}
+
+	var s string
+	if err := json.Unmarshal(body.Content, &s); err == nil {
+		m.Text = s
+		return m
+	}
+	var blocks []transcriptBlock
+	if err := json.Unmarshal(body.Content, &blocks); err != nil {
+		return m
+	}
+	var text, thinking []string
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			if b.Text != "" {
+				text = append(text, b.Text)
+			}
+		case "thinking":
+			if b.Thinking != "" {
+				thinking = append(thinking, b.Thinking)
+			}
+		case "tool_use":
+			m.ToolUses = append(m.ToolUses, ToolUse{ID: b.ID, Name: b.Name, Input: b.Input})
+		case "tool_result":
+			m.ToolResults = append(m.ToolResults, ToolResult{
+				ToolUseID: b.ToolUseID,
+				Content:   toolResultText(b.Content),
+				IsError:   b.IsError,
+			})
+		}
+	}
+	m.Text = strings.Join(text, "\n")
+	m.Thinking = strings.Join(thinking, "\n")
+	return m
+}
+
+// ToolUse returns the tool call with the given ID and the message that
+// made it.
+func (t *Transcript) ToolUse(id string) (*ToolUse, *Message) {
+	for i := range t.Messages {
+		m := &t.Messages[i]
+		for j := range m.ToolUses {
+			if m.ToolUses[j].ID == id {
+				return &m.ToolUses[j], m
+			}
+		}
+	}
+	return nil, nil
+}
+
+// ToolResult returns the result of the tool call with the given ID, or nil
+// if the transcript has none (the call was still running).
+func (t *Transcript) ToolResult(id string) *ToolResult {
+	for i := range t.Messages {
+		for j := range t.Messages[i].ToolResults {
+			if r := &t.Messages[i].ToolResults[j]; r.ToolUseID == id {
+				return r
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package claude
+
+import (
+	"testing"
+)
+
+func TestLoadTranscript(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := writeSession(t, home, "-home-u-proj", "abcd1234.jsonl",
+		`{"type":"summary","summary":"Fix auth"}`,
+		`{"type":"user","uuid":"u1","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"fix the auth bug"}}`,
+		`{"type":"assistant","uuid":"a1","parentUuid":"u1","timestamp":"2025-01-01T00:00:05Z","message":{"id":"m1","role":"assistant","model":"claude-opus-4","content":[{"type":"thinking","thinking":"check auth.go"},{"type":"text","text":"Looking."},{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"auth.go"}}],"usage":{"input_tokens":10,"output_tokens":5,"cache_read_input_tokens":100}}}`,
+		`{"type":"user","uuid":"u2","parentUuid":"a1","timestamp":"2025-01-01T00:00:06Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":[{"type":"text","text":"package auth"}],"is_error":false}]}}`,
+		`{"type":"system","content":"Conversation compacted","timestamp":"2025-01-01T00:00:07Z"}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:00:08Z","message":{"id":"m2","role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test"}}]}}`,
+		`{"type":"assistant","trunc`,
+	)
+
+	byID, err := LoadTranscript("abcd")
+	if err != nil {
+		t.Fatalf("LoadTranscript by ID: %v", err)
+	}
+	byPath, err := LoadTranscript(path)
+	if err != nil {
+		t.Fatalf("LoadTranscript by path: %v", err)
+	}
+	for _, tr := range []*Transcript{byID, byPath} {
+		if tr.Session == nil || tr.Session.ID != "abcd1234" || tr.Session.Summary != "Fix auth" {
+			t.Errorf("session = %+v", tr.Session)
+		}
+		if len(tr.Messages) != 6 || tr.Skipped != 1 {
+			t.Fatalf("got %d messages, %d skipped", len(tr.Messages), tr.Skipped)
+		}
+	}
+
+	tr := byPath
+	if m := tr.Messages[0]; m.Type != "summary" || m.Text != "Fix auth" || m.Line != 1 {
+		t.Errorf("summary = %+v", m)
+	}
+	a := tr.Messages[2]
+	if a.Text != "Looking." || a.Thinking != "check auth.go" || a.Model != "claude-opus-4" ||
+		a.ParentUUID != "u1" || a.MessageID != "m1" || len(a.ToolUses) != 1 {
+		t.Errorf("assistant = %+v", a)
+	}
+	if a.Usage == nil || a.Usage.InputTokens != 10 || a.Usage.CacheReadTokens != 100 {
+		t.Errorf("usage = %+v", a.Usage)
+	}
+	if m := tr.Messages[4]; m.Type != "system" || m.Text != "Conversation compacted" {
+		t.Errorf("system = %+v", m)
+	}
+
+	use, msg := tr.ToolUse("t1")
+	if use == nil || use.Name != "Read" || string(use.Input) != `{"file_path":"auth.go"}` || msg.UUID != "a1" {
+		t.Errorf("ToolUse(t1) = %+v, %+v", use, msg)
+	}
+	if r := tr.ToolResult("t1"); r == nil || r.Content != "package auth" || r.IsError {
+		t.Errorf("ToolResult(t1) = %+v", r)
+	}
+	if r := tr.ToolResult("t2"); r != nil {
+		t.Errorf("ToolResult(t2) = %+v, want nil for a pending call", r)
+	}
+}
+
+func TestLoadTranscriptNotFound(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if _, err := LoadTranscript("nope"); err == nil {
+		t.Error("LoadTranscript found a missing session")
+	}
+	if _, err := LoadTranscript("/no/such/file.jsonl"); err == nil {
+		t.Error("LoadTranscript read a missing file")
+	}
+}
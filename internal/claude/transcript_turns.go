@@ -0,0 +1,172 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+)
+
+// TranscriptTurn is one user or assistant turn with its tool calls nested,
+// for rendering a conversation outside Gas Town.
+type TranscriptTurn struct {
+	Index     int        `json:"index"`
+	Timestamp time.Time  `json:"timestamp"`
+	Role      string     `json:"role"` // "user" or "assistant"
+	Text      string     `json:"text,omitempty"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+// ToolCall is a tool_use block paired with its tool_result.
+type ToolCall struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Input   json.RawMessage `json:"input,omitempty"`
+	Result  string          `json:"result,omitempty"`
+	IsError bool            `json:"is_error,omitempty"`
+
+	// Pending is set when the transcript has no result for the call
+	// (still running, or the session ended first).
+	Pending bool `json:"pending,omitempty"`
+
+	// AgentID identifies the subagent for Task calls, when recorded.
+	AgentID string `json:"agent_id,omitempty"`
+
+	// Subagent holds the subagent's own turns for Task calls, when the
+	// caller expands them (see ExpandSubagentTurns).
+	Subagent []TranscriptTurn `json:"subagent,omitempty"`
+}
+
+// transcriptBlock is a content block of any type.
+type transcriptBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	Thinking  string          `json:"thinking,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   json.RawMessage `json:"content,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+}
+
+// ReadTranscriptTurns returns a transcript's turns with tool calls nested
+// under the assistant turn that made them. It smooths over the JSONL
+// quirks: content that is a string or a block array, assistant messages
+// split across several lines with the same message ID, and tool results
+// arriving as separate user entries. User entries that only carry tool
+// results don't become turns of their own.
+func ReadTranscriptTurns(path string) ([]TranscriptTurn, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var turns []TranscriptTurn
+	type callRef struct{ turn, call int }
+	calls := make(map[string]callRef)
+	lastMessageID := ""
+
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+	for scanner.Scan() {
+		entry, err := decodeEntry(scanner.Bytes())
+		if err != nil {
+			continue
+		}
+		if entry.Type != "user" && entry.Type != "assistant" {
+			continue
+		}
+		ts := entry.Timestamp
+		body := entry.body()
+
+		var text []string
+		var blocks []transcriptBlock
+		var s string
+		if err := json.Unmarshal(body.Content, &s); err == nil {
+			text = append(text, s)
+		} else if err := json.Unmarshal(body.Content, &blocks); err != nil {
+			continue
+		}
+
+		var newCalls []ToolCall
+		for _, b := range blocks {
+			switch b.Type {
+			case "text":
+				if b.Text != "" {
+					text = append(text, b.Text)
+				}
+			case "tool_use":
+				newCalls = append(newCalls, ToolCall{ID: b.ID, Name: b.Name, Input: b.Input, Pending: true})
+			case "tool_result":
+				ref, ok := calls[b.ToolUseID]
+				if !ok {
+					continue
+				}
+				call := &turns[ref.turn].ToolCalls[ref.call]
+				call.Result = toolResultText(b.Content)
+				call.IsError = b.IsError
+				call.Pending = false
+				if subagentTools[call.Name] {
+					call.AgentID = toolUseResultAgentID(entry.ToolUseResult)
+				}
+			}
+		}
+
+		joined := strings.TrimSpace(strings.Join(text, "\n"))
+		if joined == "" && len(newCalls) == 0 {
+			continue
+		}
+
+		// Continuation of a split assistant message: merge into its turn.
+		continued := entry.Type == "assistant" && body.ID != "" &&
+			body.ID == lastMessageID && len(turns) > 0
+		if !continued {
+			turns = append(turns, TranscriptTurn{Index: len(turns), Timestamp: ts, Role: entry.Type})
+		}
+		lastMessageID = body.ID
+		turn := &turns[len(turns)-1]
+		if joined != "" {
+			if turn.Text != "" {
+				turn.Text += "\n"
+			}
+			turn.Text += joined
+		}
+		for _, c := range newCalls {
+			calls[c.ID] = callRef{turn: len(turns) - 1, call: len(turn.ToolCalls)}
+			turn.ToolCalls = append(turn.ToolCalls, c)
+		}
+	}
+	return turns, scanner.Err()
+}
+
+// ExpandSubagentTurns fills in Subagent for each Task call in turns made by
+// the session at sessionPath, recursing into nested delegations. Calls
+// whose subagent transcript can't be found are left as they are.
+func ExpandSubagentTurns(sessionPath string, turns []TranscriptTurn) {
+	for i := range turns {
+		for j := range turns[i].ToolCalls {
+			call := &turns[i].ToolCalls[j]
+			if !subagentTools[call.Name] {
+				continue
+			}
+			var input struct {
+				Prompt string `json:"prompt"`
+			}
+			_ = json.Unmarshal(call.Input, &input)
+			transcript := SubagentTranscript(sessionPath, SubagentCall{AgentID: call.AgentID, Prompt: input.Prompt})
+			if transcript == "" {
+				continue
+			}
+			sub, err := ReadTranscriptTurns(transcript)
+			if err != nil {
+				continue
+			}
+			ExpandSubagentTurns(transcript, sub)
+			call.Subagent = sub
+		}
+	}
+}
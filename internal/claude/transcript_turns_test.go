@@ -0,0 +1,51 @@
+package claude
+
+import (
+	"testing"
+)
+
+func TestReadTranscriptTurns(t *testing.T) {
+	home := t.TempDir()
+	path := writeSession(t, home, "-home-u-proj", "s1.jsonl",
+		`{"type":"summary","summary":"Fix auth"}`,
+		`{"type":"user","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"fix the auth bug"}}`,
+		// One assistant message split across two lines
+		`{"type":"assistant","timestamp":"2025-01-01T00:00:05Z","message":{"id":"m1","role":"assistant","content":[{"type":"text","text":"Looking."}]}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:00:05Z","message":{"id":"m1","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"auth.go"}}]}}`,
+		`{"type":"user","timestamp":"2025-01-01T00:00:06Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":[{"type":"text","text":"package auth"}]}]}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:00:07Z","message":{"id":"m2","role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"false"}},{"type":"tool_use","id":"t3","name":"Task","input":{"prompt":"dig"}}]}}`,
+		`{"type":"user","timestamp":"2025-01-01T00:00:08Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"exit 1","is_error":true}]}}`,
+		`not json`,
+	)
+
+	turns, err := ReadTranscriptTurns(path)
+	if err != nil {
+		t.Fatalf("ReadTranscriptTurns: %v", err)
+	}
+	if len(turns) != 3 {
+		t.Fatalf("got %d turns, want 3: %+v", len(turns), turns)
+	}
+	if turns[0].Role != "user" || turns[0].Text != "fix the auth bug" || turns[0].Index != 0 {
+		t.Errorf("turn 0 = %+v", turns[0])
+	}
+
+	merged := turns[1]
+	if merged.Text != "Looking." || len(merged.ToolCalls) != 1 {
+		t.Fatalf("split message not merged: %+v", merged)
+	}
+	read := merged.ToolCalls[0]
+	if read.Name != "Read" || read.Result != "package auth" || read.Pending || string(read.Input) != `{"file_path":"auth.go"}` {
+		t.Errorf("Read call = %+v", read)
+	}
+
+	calls := turns[2].ToolCalls
+	if len(calls) != 2 || turns[2].Index != 2 {
+		t.Fatalf("turn 2 = %+v", turns[2])
+	}
+	if !calls[0].IsError || calls[0].Result != "exit 1" {
+		t.Errorf("Bash call = %+v", calls[0])
+	}
+	if !calls[1].Pending || calls[1].Result != "" {
+		t.Errorf("Task call without result should be pending: %+v", calls[1])
+	}
+}
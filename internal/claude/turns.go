@@ -0,0 +1,130 @@
+package claude
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Turn is one user or assistant message in a transcript.
+type Turn struct {
+	Timestamp time.Time `json:"timestamp"`
+	Role      string    `json:"role"`            // "user" or "assistant"
+	Text      string    `json:"text,omitempty"`  // concatenated text blocks
+	Tools     []string  `json:"tools,omitempty"` // tool names invoked (assistant turns)
+}
+
+// ReadTurns returns the user and assistant turns of a transcript in order.
+// Turns with neither text nor tool calls (e.g., bare tool results) are
+// omitted.
+func ReadTurns(path string) ([]Turn, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var turns []Turn
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+	for scanner.Scan() {
+		if turn, ok := ParseTurn(scanner.Bytes()); ok {
+			turns = append(turns, turn)
+		}
+	}
+	return turns, scanner.Err()
+}
+
+// ParseTurn decodes a single transcript line into a Turn.
+// It reports false for lines that aren't displayable turns.
+func ParseTurn(line []byte) (Turn, bool) {
+	entry, err := decodeEntry(line)
+	if err != nil {
+		return Turn{}, false
+	}
+	if entry.Type != "user" && entry.Type != "assistant" {
+		return Turn{}, false
+	}
+
+	turn := Turn{Role: entry.Type, Timestamp: entry.Timestamp}
+	turn.Text = strings.TrimSpace(messageText(entry.Message))
+	turn.Tools = messageTools(entry.Message)
+
+	if turn.Text == "" && len(turn.Tools) == 0 {
+		return Turn{}, false
+	}
+	return turn, true
+}
+
+// messageTools returns the names of tool_use blocks in a message.
+func messageTools(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var msg entryMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return nil
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(msg.Content, &blocks); err != nil {
+		return nil
+	}
+	var tools []string
+	for _, b := range blocks {
+		if b.Type == "tool_use" && b.Name != "" {
+			tools = append(tools, b.Name)
+		}
+	}
+	return tools
+}
+
+// FollowTurns polls a transcript for lines appended after offset and calls
+// fn for each new turn, until ctx is cancelled. Partial lines are held back
+// until Claude Code finishes writing them.
+func FollowTurns(ctx context.Context, path string, offset int64, interval time.Duration, fn func(Turn)) error {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(file)
+	var pending []byte
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			chunk, err := reader.ReadBytes('\n')
+			pending = append(pending, chunk...)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if turn, ok := ParseTurn(pending); ok {
+				fn(turn)
+			}
+			pending = pending[:0]
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package claude
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestReadTurns(t *testing.T) {
+	home := t.TempDir()
+	path := writeSession(t, home, "-home-u-proj", "s1.jsonl",
+		`{"type":"summary","summary":"Fix auth"}`,
+		`{"type":"user","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"fix the auth bug"}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:00:05Z","message":{"role":"assistant","content":[{"type":"text","text":"Looking."},{"type":"tool_use","name":"Read","input":{}}]}}`,
+		`{"type":"user","timestamp":"2025-01-01T00:00:06Z","message":{"role":"user","content":[{"type":"tool_result","content":"..."}]}}`,
+		`not json`,
+	)
+
+	turns, err := ReadTurns(path)
+	if err != nil {
+		t.Fatalf("ReadTurns: %v", err)
+	}
+	if len(turns) != 2 {
+		t.Fatalf("got %d turns, want 2: %+v", len(turns), turns)
+	}
+	if turns[0].Role != "user" || turns[0].Text != "fix the auth bug" {
+		t.Errorf("turn 0 = %+v", turns[0])
+	}
+	if turns[1].Text != "Looking." || len(turns[1].Tools) != 1 || turns[1].Tools[0] != "Read" {
+		t.Errorf("turn 1 = %+v", turns[1])
+	}
+}
+
+func TestFollowTurns(t *testing.T) {
+	home := t.TempDir()
+	path := writeSession(t, home, "-home-u-proj", "s1.jsonl",
+		`{"type":"user","message":{"role":"user","content":"old"}}`)
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	got := make(chan Turn, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- FollowTurns(ctx, path, stat.Size(), 10*time.Millisecond, func(turn Turn) { got <- turn })
+	}()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Write a line in two pieces to exercise partial-line handling
+	_, _ = f.WriteString(`{"type":"assistant","message":{"role":"assistant",`)
+	time.Sleep(30 * time.Millisecond)
+	_, _ = f.WriteString(`"content":"new"}}` + "\n")
+	f.Close()
+
+	select {
+	case turn := <-got:
+		if turn.Text != "new" {
+			t.Errorf("followed turn = %+v, want text %q", turn, "new")
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for appended turn")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("FollowTurns: %v", err)
+	}
+}
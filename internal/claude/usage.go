@@ -0,0 +1,126 @@
+package claude
+
+import (
+	"bufio"
+	"os"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Usage totals token consumption across a session transcript.
+type Usage struct {
+	Model               string        `json:"model,omitempty"` // last model seen
+	InputTokens         int64         `json:"input_tokens"`
+	OutputTokens        int64         `json:"output_tokens"`
+	CacheCreationTokens int64         `json:"cache_creation_tokens"`
+	CacheReadTokens     int64         `json:"cache_read_tokens"`
+	FirstTimestamp      time.Time     `json:"first_timestamp"`
+	LastTimestamp       time.Time     `json:"last_timestamp"`
+	Duration            time.Duration `json:"duration"`
+}
+
+// TotalTokens returns all tokens billed for the session.
+func (u Usage) TotalTokens() int64 {
+	return u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens
+}
+
+// ReadUsage scans a whole transcript and totals its token usage.
+// Claude Code writes one entry per content block, repeating the same usage
+// for each; entries are deduplicated by message ID.
+func ReadUsage(path string) (*Usage, error) {
+	return ReadUsageSince(path, time.Time{})
+}
+
+// ReadUsageSince totals the token usage of assistant messages written at
+// or after since, e.g. the spend after a session's last good state.
+// FirstTimestamp and LastTimestamp still span the whole transcript.
+func ReadUsageSince(path string, since time.Time) (*Usage, error) {
+	return readUsage(path, since, make(map[string]bool))
+}
+
+// ReadUsageChain reads the usage of each transcript in paths, which
+// should be oldest first. A message is counted only in the first
+// transcript it appears in, so a resumed session isn't billed again for
+// the history it repeats from its predecessor.
+func ReadUsageChain(paths []string) ([]*Usage, error) {
+	seen := make(map[string]bool)
+	usages := make([]*Usage, 0, len(paths))
+	for _, path := range paths {
+		u, err := readUsage(path, time.Time{}, seen)
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, u)
+	}
+	return usages, nil
+}
+
+// readUsage totals the usage in a transcript of messages written at or
+// after since and not already in seen, adding them to seen.
+func readUsage(path string, since time.Time, seen map[string]bool) (*Usage, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	u := &Usage{}
+
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+
+	for scanner.Scan() {
+		entry, err := decodeEntry(scanner.Bytes())
+		if err != nil {
+			continue
+		}
+
+		ts := entry.Timestamp
+		if !ts.IsZero() {
+			if u.FirstTimestamp.IsZero() {
+				u.FirstTimestamp = ts
+			}
+			u.LastTimestamp = ts
+		}
+
+		if entry.Type != "assistant" {
+			continue
+		}
+		body := entry.body()
+		if body.Usage == nil {
+			continue
+		}
+		if body.Model != "" && body.Model != "<synthetic>" {
+			u.Model = body.Model
+		}
+		if !since.IsZero() && ts.Before(since) {
+			continue
+		}
+		if id := body.ID; id != "" {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+		}
+		usage := body.Usage
+		u.InputTokens += usage.InputTokens
+		u.OutputTokens += usage.OutputTokens
+		u.CacheCreationTokens += usage.CacheCreationInputTokens
+		u.CacheReadTokens += usage.CacheReadInputTokens
+	}
+
+	if !u.FirstTimestamp.IsZero() {
+		u.Duration = u.LastTimestamp.Sub(u.FirstTimestamp)
+	}
+
+	return u, scanner.Err()
+}
+
+// EstimateCost returns the USD cost of a session's usage, priced at the
+// rates in effect when the session started.
+func EstimateCost(u Usage, prices config.PriceTable) float64 {
+	return prices.Cost(u.Model, u.FirstTimestamp,
+		u.InputTokens, u.OutputTokens, u.CacheCreationTokens, u.CacheReadTokens)
+}
@@ -0,0 +1,69 @@
+package claude
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestReadUsage(t *testing.T) {
+	home := t.TempDir()
+	path := writeSession(t, home, "-home-u-proj", "s1.jsonl",
+		`{"type":"user","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"hi"}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:01:00Z","message":{"id":"m1","model":"claude-sonnet-4","usage":{"input_tokens":10,"output_tokens":20,"cache_read_input_tokens":100}}}`,
+		// Same message ID repeated per content block: counted once
+		`{"type":"assistant","timestamp":"2025-01-01T00:01:01Z","message":{"id":"m1","model":"claude-sonnet-4","usage":{"input_tokens":10,"output_tokens":20,"cache_read_input_tokens":100}}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T00:05:00Z","message":{"id":"m2","model":"claude-sonnet-4","usage":{"input_tokens":5,"output_tokens":5,"cache_creation_input_tokens":50}}}`,
+	)
+
+	u, err := ReadUsage(path)
+	if err != nil {
+		t.Fatalf("ReadUsage: %v", err)
+	}
+	if u.InputTokens != 15 || u.OutputTokens != 25 || u.CacheReadTokens != 100 || u.CacheCreationTokens != 50 {
+		t.Errorf("unexpected usage: %+v", u)
+	}
+	if u.TotalTokens() != 190 {
+		t.Errorf("TotalTokens = %d, want 190", u.TotalTokens())
+	}
+	if u.Duration != 5*time.Minute {
+		t.Errorf("Duration = %v, want 5m", u.Duration)
+	}
+	if u.Model != "claude-sonnet-4" {
+		t.Errorf("Model = %q", u.Model)
+	}
+}
+
+func TestReadUsageChain(t *testing.T) {
+	home := t.TempDir()
+	first := writeSession(t, home, "-home-u-proj", "s1.jsonl",
+		`{"type":"assistant","timestamp":"2025-01-01T00:01:00Z","message":{"id":"m1","model":"claude-sonnet-4","usage":{"input_tokens":10,"output_tokens":20}}}`,
+	)
+	// The resumed session repeats m1 before adding m2
+	resumed := writeSession(t, home, "-home-u-proj", "s2.jsonl",
+		`{"type":"assistant","timestamp":"2025-01-01T00:01:00Z","message":{"id":"m1","model":"claude-sonnet-4","usage":{"input_tokens":10,"output_tokens":20}}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T01:00:00Z","message":{"id":"m2","model":"claude-sonnet-4","usage":{"input_tokens":5,"output_tokens":5}}}`,
+	)
+
+	usages, err := ReadUsageChain([]string{first, resumed})
+	if err != nil {
+		t.Fatalf("ReadUsageChain: %v", err)
+	}
+	if usages[0].TotalTokens() != 30 || usages[1].TotalTokens() != 10 {
+		t.Errorf("chain totals = %d, %d; want 30, 10", usages[0].TotalTokens(), usages[1].TotalTokens())
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	prices := (*config.TownSettings)(nil).PriceTable()
+	u := Usage{Model: "claude-opus-4", InputTokens: 1_000_000, OutputTokens: 1_000_000}
+	if got := EstimateCost(u, prices); math.Abs(got-90) > 1e-9 {
+		t.Errorf("opus cost = %v, want 90", got)
+	}
+	u.Model = "mystery"
+	if got := EstimateCost(u, prices); math.Abs(got-18) > 1e-9 {
+		t.Errorf("default cost = %v, want 18", got)
+	}
+}
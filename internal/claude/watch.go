@@ -0,0 +1,226 @@
+package claude
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SessionEventType says what changed about a session.
+type SessionEventType string
+
+// Session event types.
+const (
+	// SessionCreated: a session matching the filter appeared. Sessions
+	// that only match once later lines arrive (the beacon, say) are
+	// reported when they start to match.
+	SessionCreated SessionEventType = "created"
+
+	// SessionUpdated: lines were appended to a matching session.
+	SessionUpdated SessionEventType = "updated"
+)
+
+// SessionEvent reports a change to a session transcript. The new data is
+// the byte range [Offset, Size) of Session.Path; it may end in a line
+// Claude Code has not finished writing (see FollowTurns).
+type SessionEvent struct {
+	Type    SessionEventType `json:"type"`
+	Session SessionInfo      `json:"session"`
+	Offset  int64            `json:"offset"` // 0 for created, or when the file was rewritten
+	Size    int64            `json:"size"`
+}
+
+// How often WatchSessions rescans every transcript: without file
+// notifications, polling is the only way changes are seen; with them, a
+// slow rescan catches anything a notification missed.
+var (
+	watchPollInterval   = 2 * time.Second
+	watchRescanInterval = 30 * time.Second
+)
+
+// watchHeaderBytes is how far into a transcript WatchSessions keeps
+// re-checking a session that doesn't match the filter. The beacon and
+// summary are written near the start; past this, sessions that still
+// don't match are ignored.
+const watchHeaderBytes = 64 * 1024
+
+// WatchSessions reports new sessions and new transcript lines as they
+// are written, until ctx is cancelled. Sessions that exist when it is
+// called are not reported until they change. filter applies as in
+// DiscoverSessions, except Limit, which is ignored.
+//
+// On Linux changes are picked up from inotify; elsewhere, or if inotify
+// is unavailable, transcripts are polled every few seconds.
+func WatchSessions(ctx context.Context, filter SessionFilter) (<-chan SessionEvent, error) {
+	return currentStore().Watch(ctx, filter)
+}
+
+// watchSessionFiles is FileStore.Watch.
+func watchSessionFiles(ctx context.Context, filter SessionFilter) (<-chan SessionEvent, error) {
+	filter.Limit = 0
+	w := &sessionWatcher{
+		dir:    ProjectsDir(),
+		filter: filter,
+		known:  make(map[string]*watchedSession),
+		events: make(chan SessionEvent, 64),
+	}
+
+	// Baseline: remember what's already there, so only changes are reported
+	existing, err := scanSessions(filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range existing {
+		w.known[s.Path] = &watchedSession{size: s.FileSize, info: &s}
+	}
+	w.scan(func(path string, size int64) {
+		if _, ok := w.known[path]; !ok {
+			w.known[path] = &watchedSession{size: size}
+		}
+	})
+
+	changes, err := notifyChanges(ctx, w.dir)
+	interval := watchRescanInterval
+	if err != nil {
+		changes, interval = nil, watchPollInterval
+	}
+	go w.run(ctx, changes, interval)
+	return w.events, nil
+}
+
+// sessionWatcher holds WatchSessions' state. It is only used from the
+// run goroutine after setup.
+type sessionWatcher struct {
+	dir    string
+	filter SessionFilter
+	known  map[string]*watchedSession
+	events chan SessionEvent
+}
+
+// watchedSession is what the watcher remembers about one transcript.
+type watchedSession struct {
+	size int64
+	info *SessionInfo // set once the session matched the filter
+}
+
+// run reacts to change notifications and periodic rescans.
+func (w *sessionWatcher) run(ctx context.Context, changes <-chan string, interval time.Duration) {
+	defer close(w.events)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case path, ok := <-changes:
+			if !ok {
+				changes = nil // notifications failed; fall back to polling
+				ticker.Reset(watchPollInterval)
+				continue
+			}
+			if strings.HasSuffix(path, ".jsonl") {
+				w.check(ctx, path)
+			} else {
+				w.scanDir(ctx, path)
+			}
+		case <-ticker.C:
+			w.scan(func(path string, _ int64) { w.check(ctx, path) })
+		}
+	}
+}
+
+// scan calls fn for every top-level transcript under the projects dir.
+func (w *sessionWatcher) scan(fn func(path string, size int64)) {
+	projects, err := os.ReadDir(w.dir)
+	if err != nil {
+		return
+	}
+	for _, p := range projects {
+		if p.IsDir() {
+			w.scanFiles(filepath.Join(w.dir, p.Name()), fn)
+		}
+	}
+}
+
+// scanDir checks every transcript in one project directory.
+func (w *sessionWatcher) scanDir(ctx context.Context, dir string) {
+	w.scanFiles(dir, func(path string, _ int64) { w.check(ctx, path) })
+}
+
+// scanFiles calls fn for each transcript in dir.
+func (w *sessionWatcher) scanFiles(dir string, fn func(path string, size int64)) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".jsonl") {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		fn(filepath.Join(dir, f.Name()), info.Size())
+	}
+}
+
+// check compares a transcript against what the watcher last saw and
+// emits an event if a matching session appeared or grew.
+func (w *sessionWatcher) check(ctx context.Context, path string) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		delete(w.known, path)
+		return
+	}
+	size := stat.Size()
+	s := w.known[path]
+	if s == nil {
+		s = &watchedSession{}
+		w.known[path] = s
+	} else if size == s.size {
+		return
+	}
+	prev := s.size
+	s.size = size
+
+	if s.info == nil {
+		if prev > watchHeaderBytes {
+			return
+		}
+		info, err := parseSession(path, filepath.Base(filepath.Dir(path)))
+		if err != nil || info == nil {
+			return
+		}
+		if info.ParentID != "" {
+			if parent := w.known[subagentParentPath(path, info.ParentID)]; parent != nil && parent.info != nil {
+				inheritBeacon(info, parent.info)
+			}
+		}
+		info.Tags = w.filter.Tagger.Tags(info.ProjectPath, info.tagTopic())
+		if !w.filter.matches(info) {
+			return
+		}
+		s.info = info
+		w.emit(ctx, SessionEvent{Type: SessionCreated, Session: *info, Size: size})
+		return
+	}
+
+	offset := prev
+	if size < prev { // rewritten, e.g. by gt seance redact
+		offset = 0
+	}
+	s.info.FileSize = size
+	w.emit(ctx, SessionEvent{Type: SessionUpdated, Session: *s.info, Offset: offset, Size: size})
+}
+
+// emit delivers an event unless the watch was cancelled.
+func (w *sessionWatcher) emit(ctx context.Context, ev SessionEvent) {
+	select {
+	case w.events <- ev:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,86 @@
+package claude
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// Inotify masks for the projects directory and each project directory.
+const (
+	inotifyProjectsMask = syscall.IN_CREATE | syscall.IN_MOVED_TO | syscall.IN_ONLYDIR
+	inotifyProjectMask  = syscall.IN_CREATE | syscall.IN_MODIFY | syscall.IN_MOVED_TO | syscall.IN_CLOSE_WRITE
+)
+
+// notifyChanges watches the projects directory with inotify. It sends the
+// path of each transcript written to, and of each new project directory,
+// and closes the channel if reading notifications fails.
+func notifyChanges(ctx context.Context, projectsDir string) (<-chan string, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	// Non-blocking, so reads go through the runtime poller and Close
+	// interrupts them
+	file := os.NewFile(uintptr(fd), "inotify")
+
+	dirs := make(map[int]string)
+	watch := func(path string, mask uint32) error {
+		wd, err := syscall.InotifyAddWatch(fd, path, mask)
+		if err == nil {
+			dirs[wd] = path
+		}
+		return err
+	}
+	if err := watch(projectsDir, inotifyProjectsMask); err != nil {
+		file.Close()
+		return nil, err
+	}
+	projects, _ := os.ReadDir(projectsDir)
+	for _, p := range projects {
+		if p.IsDir() {
+			_ = watch(filepath.Join(projectsDir, p.Name()), inotifyProjectMask)
+		}
+	}
+
+	changes := make(chan string, 64)
+	go func() {
+		<-ctx.Done()
+		file.Close()
+	}()
+	go func() {
+		defer close(changes)
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				return
+			}
+			for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+				ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off])) //nolint:gosec // G103: decoding a kernel inotify record
+				name := buf[off+syscall.SizeofInotifyEvent : off+syscall.SizeofInotifyEvent+int(ev.Len)]
+				off += syscall.SizeofInotifyEvent + int(ev.Len)
+
+				dir, ok := dirs[int(ev.Wd)]
+				if !ok || len(name) == 0 {
+					continue
+				}
+				path := filepath.Join(dir, string(bytes.TrimRight(name, "\x00")))
+				if dir == projectsDir {
+					if ev.Mask&syscall.IN_ISDIR == 0 || watch(path, inotifyProjectMask) != nil {
+						continue
+					}
+				}
+				select {
+				case changes <- path:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return changes, nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package claude
+
+import (
+	"context"
+	"errors"
+)
+
+// notifyChanges is only implemented on Linux; elsewhere WatchSessions
+// polls.
+func notifyChanges(ctx context.Context, projectsDir string) (<-chan string, error) {
+	return nil, errors.New("file notifications not supported on this platform")
+}
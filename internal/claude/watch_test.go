@@ -0,0 +1,63 @@
+package claude
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWatchSessions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(sessionIndexEnv, "off")
+	watchPollInterval, watchRescanInterval = 50*time.Millisecond, 50*time.Millisecond
+	t.Cleanup(func() { watchPollInterval, watchRescanInterval = 2*time.Second, 30*time.Second })
+
+	beacon := `{"type":"user","timestamp":"2025-12-30T15:42:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/crew/joe <- deacon • 2025-12-30T15:42 • work"}}`
+	existing := writeSession(t, home, "-home-u-gastown", "aaaa1111.jsonl", beacon)
+	writeSession(t, home, "-home-u-other", "cccc3333.jsonl", `{"type":"user","message":{"role":"user","content":"hello"}}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := WatchSessions(ctx, SessionFilter{GasTownOnly: true})
+	if err != nil {
+		t.Fatalf("WatchSessions: %v", err)
+	}
+
+	next := func() SessionEvent {
+		t.Helper()
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a session event")
+		}
+		return SessionEvent{}
+	}
+
+	// New lines in an existing session
+	stat, _ := os.Stat(existing)
+	f, err := os.OpenFile(existing, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"type":"assistant","message":{"role":"assistant","content":"on it"}}` + "\n")
+	f.Close()
+	ev := next()
+	if ev.Type != SessionUpdated || ev.Session.ID != "aaaa1111" || ev.Offset != stat.Size() || ev.Size <= ev.Offset {
+		t.Errorf("append event = %+v", ev)
+	}
+
+	// A session in a new project; the non-Gas Town one is filtered out
+	writeSession(t, home, "-home-u-other", "dddd4444.jsonl", `{"type":"user","message":{"role":"user","content":"hi"}}`)
+	writeSession(t, home, "-home-u-new", "bbbb2222.jsonl", beacon)
+	ev = next()
+	if ev.Type != SessionCreated || ev.Session.ID != "bbbb2222" || ev.Session.Role != "gastown/crew/joe" || ev.Offset != 0 {
+		t.Errorf("create event = %+v", ev)
+	}
+
+	cancel()
+	for range events {
+	}
+}
@@ -0,0 +1,272 @@
+// Package cleanup finds and removes the build artifacts, dependencies,
+// and stale caches that pile up in agent worktrees.
+package cleanup
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Kind is what an artifact directory holds.
+type Kind string
+
+const (
+	KindDependencies Kind = "dependencies"
+	KindBuild        Kind = "build"
+	KindCache        Kind = "cache"
+)
+
+// DefaultPatterns maps directory names to the kind of artifact they hold.
+// Every one can be regenerated by the project's own tooling.
+var DefaultPatterns = map[string]Kind{
+	"node_modules":  KindDependencies,
+	".venv":         KindDependencies,
+	"target":        KindBuild,
+	"dist":          KindBuild,
+	"build":         KindBuild,
+	".next":         KindBuild,
+	"__pycache__":   KindCache,
+	".pytest_cache": KindCache,
+	".mypy_cache":   KindCache,
+	".ruff_cache":   KindCache,
+	".turbo":        KindCache,
+	".gradle":       KindCache,
+	".cache":        KindCache,
+}
+
+// DefaultCacheAge is how long a cache must go untouched before it is stale.
+const DefaultCacheAge = 7 * 24 * time.Hour
+
+// Artifact is a removable directory inside a worktree.
+type Artifact struct {
+	Path    string    `json:"path"`
+	RelPath string    `json:"rel_path"`
+	Kind    Kind      `json:"kind"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`        // newest entry inside
+	Stale   bool      `json:"stale,omitempty"` // caches only
+}
+
+// Removable reports whether gt clean removes the artifact: dependencies
+// and build output always, caches only once stale.
+func (a Artifact) Removable() bool {
+	return a.Kind != KindCache || a.Stale
+}
+
+// Report is the disk usage of one worktree.
+type Report struct {
+	Dir       string     `json:"dir"`
+	Size      int64      `json:"size"`
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// Removable returns the artifacts gt clean would remove, largest first.
+func (r *Report) Removable() []Artifact {
+	var out []Artifact
+	for _, a := range r.Artifacts {
+		if a.Removable() {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// RemovableSize returns how much removing the removable artifacts frees.
+func (r *Report) RemovableSize() int64 {
+	var n int64
+	for _, a := range r.Removable() {
+		n += a.Size
+	}
+	return n
+}
+
+// Options configures Scan.
+type Options struct {
+	// Patterns maps directory names to artifact kinds. Nil means
+	// DefaultPatterns.
+	Patterns map[string]Kind
+
+	// CacheAge is how long a cache must go untouched before it is stale.
+	// Zero means DefaultCacheAge.
+	CacheAge time.Duration
+
+	// Now is the time caches are aged against. Zero means time.Now().
+	Now time.Time
+}
+
+// Scan measures dir and finds the artifact directories in it. Inside a
+// git repository, only directories .gitignore excludes count as
+// artifacts, so a tracked directory that happens to be called build is
+// left alone.
+// Artifacts are not searched for inside other artifacts or .git.
+func Scan(dir string, opts Options) (*Report, error) {
+	if opts.Patterns == nil {
+		opts.Patterns = DefaultPatterns
+	}
+	if opts.CacheAge == 0 {
+		opts.CacheAge = DefaultCacheAge
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	r := &Report{Dir: dir, Artifacts: []Artifact{}}
+	var candidates []Artifact
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil // unreadable entries are skipped
+		}
+		if !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				r.Size += info.Size()
+			}
+			return nil
+		}
+		if path == dir {
+			return nil
+		}
+
+		name := d.Name()
+		kind, ok := opts.Patterns[name]
+		if name != ".git" && !ok {
+			return nil
+		}
+		size, modTime := measure(path)
+		r.Size += size
+		if ok {
+			rel, _ := filepath.Rel(dir, path)
+			candidates = append(candidates, Artifact{
+				Path:    path,
+				RelPath: filepath.ToSlash(rel),
+				Kind:    kind,
+				Size:    size,
+				ModTime: modTime,
+				Stale:   kind == KindCache && opts.Now.Sub(modTime) >= opts.CacheAge,
+			})
+		}
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	kept := candidates[:0]
+	for _, a := range candidates {
+		ok, err := ignoredOrOutsideRepo(a.Path)
+		if err != nil {
+			return nil, fmt.Errorf("checking .gitignore: %w", err)
+		}
+		if ok {
+			kept = append(kept, a)
+		}
+	}
+	candidates = kept
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Size != candidates[j].Size {
+			return candidates[i].Size > candidates[j].Size
+		}
+		return candidates[i].RelPath < candidates[j].RelPath
+	})
+	r.Artifacts = append(r.Artifacts, candidates...)
+	return r, nil
+}
+
+// ignoredOrOutsideRepo reports whether path is outside any git
+// repository or excluded by its .gitignore. Untracked paths that aren't
+// ignored don't qualify: they may be work not yet committed. The
+// repository is the one enclosing path, which may be nested inside the
+// scanned directory (e.g., polecats/<name>/<rig>).
+func ignoredOrOutsideRepo(path string) (bool, error) {
+	g := git.NewGit(filepath.Dir(path))
+	if !g.IsRepo() {
+		return true, nil
+	}
+	ignored, err := g.IgnoredPaths(filepath.Base(path))
+	return len(ignored) > 0, err
+}
+
+// measure returns the total size of the files under dir and the newest
+// modification time of anything in it.
+func measure(dir string) (int64, time.Time) {
+	var size int64
+	var newest time.Time
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			size += info.Size()
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		return nil
+	})
+	return size, newest
+}
+
+// Remove deletes artifacts, returning the bytes freed. It keeps going
+// past failures and returns the first one.
+func Remove(artifacts []Artifact) (int64, error) {
+	var freed int64
+	var firstErr error
+	for _, a := range artifacts {
+		if err := os.RemoveAll(a.Path); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("removing %s: %w", a.Path, err)
+			}
+			continue
+		}
+		freed += a.Size
+	}
+	return freed, firstErr
+}
+
+// ParseSize parses a size such as "500MB", "2GB", "1.5G", or "1024"
+// (bytes). Units are powers of 1024.
+func ParseSize(s string) (int64, error) {
+	num := strings.ToUpper(strings.TrimSpace(s))
+	num = strings.TrimSuffix(strings.TrimSuffix(num, "B"), "I")
+	mult := 1.0
+	if i := len(num) - 1; i >= 0 {
+		if exp := strings.IndexByte("KMGT", num[i]); exp >= 0 {
+			mult = float64(int64(1) << (10 * (exp + 1)))
+			num = num[:i]
+		}
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (want e.g. 500MB, 2GB)", s)
+	}
+	return int64(n * mult), nil
+}
+
+// FormatSize formats a byte count with a binary unit, e.g. "1.5 GB".
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 3; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
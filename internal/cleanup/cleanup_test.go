@@ -0,0 +1,126 @@
+package cleanup
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeFile writes size bytes to dir/rel, creating parent directories.
+func writeFile(t *testing.T, dir, rel string, size int) {
+	t.Helper()
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("node_modules/\n.cache/\n__pycache__/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "main.go", 100)
+	writeFile(t, dir, "node_modules/left-pad/index.js", 1000)
+	writeFile(t, dir, "node_modules/.cache/x", 50) // inside an artifact: not separate
+	writeFile(t, dir, "build/gen.go", 200)         // not ignored: tracked source
+	writeFile(t, dir, ".cache/old", 300)
+	writeFile(t, dir, "pkg/__pycache__/m.pyc", 400)
+
+	now := time.Now()
+	old := now.Add(-30 * 24 * time.Hour)
+	for _, p := range []string{".cache/old", ".cache"} {
+		if err := os.Chtimes(filepath.Join(dir, p), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := Scan(dir, Options{Now: now})
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	got := map[string]Artifact{}
+	for _, a := range r.Artifacts {
+		got[a.RelPath] = a
+	}
+	if len(got) != 3 {
+		t.Fatalf("artifacts = %+v, want node_modules, .cache, pkg/__pycache__", r.Artifacts)
+	}
+	if a := got["node_modules"]; a.Kind != KindDependencies || a.Size != 1050 || !a.Removable() {
+		t.Errorf("node_modules = %+v", a)
+	}
+	if a := got[".cache"]; a.Kind != KindCache || !a.Stale || !a.Removable() {
+		t.Errorf(".cache = %+v, want stale", a)
+	}
+	if a := got["pkg/__pycache__"]; a.Stale || a.Removable() {
+		t.Errorf("fresh cache removable: %+v", a)
+	}
+	if r.Artifacts[0].RelPath != "node_modules" {
+		t.Errorf("not largest first: %+v", r.Artifacts)
+	}
+	if r.Size < 2050 {
+		t.Errorf("size = %d, want at least the files written", r.Size)
+	}
+	if r.RemovableSize() != 1350 {
+		t.Errorf("removable = %d, want 1350", r.RemovableSize())
+	}
+
+	freed, err := Remove(r.Removable())
+	if err != nil || freed != 1350 {
+		t.Fatalf("Remove = %d, %v", freed, err)
+	}
+	for _, p := range []string{"node_modules", ".cache"} {
+		if _, err := os.Stat(filepath.Join(dir, p)); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", p)
+		}
+	}
+	for _, p := range []string{"build/gen.go", "pkg/__pycache__/m.pyc", "main.go"} {
+		if _, err := os.Stat(filepath.Join(dir, p)); err != nil {
+			t.Errorf("%s removed: %v", p, err)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	for in, want := range map[string]int64{
+		"1024":  1024,
+		"500MB": 500 << 20,
+		"2GB":   2 << 30,
+		"2gib":  2 << 30,
+		"1.5G":  3 << 29,
+		"10 KB": 10 << 10,
+		"0":     0,
+	} {
+		got, err := ParseSize(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "GB", "-1GB", "2PB", "lots"} {
+		if _, err := ParseSize(bad); err == nil {
+			t.Errorf("ParseSize(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for n, want := range map[int64]string{
+		512:      "512 B",
+		2048:     "2.0 KB",
+		3 << 29:  "1.5 GB",
+		80 << 30: "80.0 GB",
+		5 << 40:  "5.0 TB",
+	} {
+		if got := FormatSize(n); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
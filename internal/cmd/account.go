@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 
 	"github.com/spf13/cobra"
@@ -368,7 +369,7 @@ func runAccountSwitch(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("getting home directory: %w", err)
 	}
-	claudeDir := home + "/.claude"
+	claudeDir := filepath.Join(home, ".claude")
 
 	// Check current state of ~/.claude
 	fileInfo, err := os.Lstat(claudeDir)
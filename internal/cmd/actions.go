@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/actions"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+var (
+	actionsSince  string
+	actionsUntil  string
+	actionsKind   string
+	actionsTarget string
+	actionsLimit  int
+	actionsFull   bool
+	actionsJSON   bool
+
+	actionsReplayDryRun bool
+)
+
+var actionsCmd = &cobra.Command{
+	Use:     "actions",
+	GroupID: GroupDiag,
+	Short:   "Show and replay the log of session spawns, resumes, and kills",
+	Long: `Every agent session gt spawns, resumes, or kills is appended to an action
+log with its parameters and outcome: the tmux session, working directory,
+full launch command (including the startup prompt), the gt command line
+that took the action, the agent that ran it, and whether it succeeded.
+
+The log is per machine, at ~/.local/state/gastown/actions.jsonl (or under
+$XDG_STATE_HOME). Parallel gt processes append to it safely.
+
+Examples:
+  gt actions list --since yesterday        # What did I launch yesterday?
+  gt actions list --kind spawn --full      # With the full launch commands
+  gt actions replay 3f9c2a1b --dry-run     # Show what replaying would run
+  gt actions replay 3f9c2a1b`,
+	RunE: requireSubcommand,
+}
+
+var actionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded spawns, resumes, and kills",
+	Args:  cobra.NoArgs,
+	RunE:  runActionsList,
+}
+
+var actionsReplayCmd = &cobra.Command{
+	Use:   "replay <action-id>",
+	Short: "Repeat a recorded spawn, resume, or kill",
+	Long: `Repeat a recorded action exactly: the same tmux session, working
+directory, and launch command. A spawn is refused while its session is
+still running. The replay is itself recorded.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runActionsReplay,
+}
+
+func init() {
+	actionsListCmd.Flags().StringVar(&actionsSince, "since", "", "Only actions at or after this time (e.g., 2d, 6h, today, 2025-01-02)")
+	actionsListCmd.Flags().StringVar(&actionsUntil, "until", "", "Only actions before this time (same formats as --since)")
+	actionsListCmd.Flags().StringVar(&actionsKind, "kind", "", "Only this kind: spawn, resume, or kill")
+	actionsListCmd.Flags().StringVar(&actionsTarget, "target", "", "Only actions whose tmux session or pane contains this")
+	actionsListCmd.Flags().IntVarP(&actionsLimit, "limit", "n", 20, "Show the most recent N actions (0 = all)")
+	actionsListCmd.Flags().BoolVar(&actionsFull, "full", false, "Show full launch commands")
+	actionsListCmd.Flags().BoolVar(&actionsJSON, "json", false, "Output as JSON")
+
+	actionsReplayCmd.Flags().BoolVarP(&actionsReplayDryRun, "dry-run", "n", false, "Show what would run without running it")
+
+	actionsCmd.AddCommand(actionsListCmd)
+	actionsCmd.AddCommand(actionsReplayCmd)
+	rootCmd.AddCommand(actionsCmd)
+}
+
+func runActionsList(cmd *cobra.Command, args []string) error {
+	since, until, err := parseTimeRange(actionsSince, actionsUntil, time.Now())
+	if err != nil {
+		return err
+	}
+	switch actionsKind {
+	case "", actions.KindSpawn, actions.KindResume, actions.KindKill:
+	default:
+		return fmt.Errorf("invalid --kind %q (want spawn, resume, or kill)", actionsKind)
+	}
+
+	all, err := actions.Load(since)
+	if err != nil {
+		return err
+	}
+	var list []actions.Action
+	for _, a := range all {
+		if !until.IsZero() && !a.Time.Before(until) {
+			continue
+		}
+		if actionsKind != "" && a.Kind != actionsKind {
+			continue
+		}
+		if actionsTarget != "" && !strings.Contains(a.Target, actionsTarget) {
+			continue
+		}
+		list = append(list, a)
+	}
+	if actionsLimit > 0 && len(list) > actionsLimit {
+		list = list[len(list)-actionsLimit:]
+	}
+
+	if actionsJSON {
+		if list == nil {
+			list = []actions.Action{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+	if len(list) == 0 {
+		fmt.Println("No actions recorded.")
+		fmt.Println(style.Dim.Render("Spawns, resumes, and kills are logged to " + actions.Path()))
+		return nil
+	}
+
+	for _, a := range list {
+		mark := style.SuccessPrefix
+		if a.Outcome != actions.OutcomeOK {
+			mark = style.ErrorPrefix
+		}
+		actor := a.Actor
+		if actor == "" {
+			actor = "human"
+		}
+		fmt.Printf("%s %s  %s  %-6s  %-28s  %s\n", mark, a.Time.Local().Format("2006-01-02 15:04:05"),
+			a.ID, a.Kind, a.Target, style.Dim.Render(actor+" · "+a.Invocation))
+		if a.Command != "" {
+			command := a.Command
+			if !actionsFull {
+				command = truncateStr(command, 100)
+			}
+			fmt.Printf("    %s\n", style.Dim.Render(command))
+		}
+		if a.Error != "" {
+			fmt.Printf("    %s\n", a.Error)
+		}
+	}
+	return nil
+}
+
+func runActionsReplay(cmd *cobra.Command, args []string) error {
+	a, err := actions.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	t := tmux.NewTmux()
+	var run func() error
+	var desc string
+	switch a.Op {
+	case actions.OpNewSession:
+		desc = fmt.Sprintf("tmux new-session -d -s %s -c %s %q", a.Target, a.WorkDir, a.Command)
+		run = func() error {
+			if running, _ := t.HasSession(a.Target); running {
+				return fmt.Errorf("session %s is running; kill it before replaying its spawn", a.Target)
+			}
+			return t.NewSessionWithCommand(a.Target, a.WorkDir, a.Command)
+		}
+	case actions.OpRespawnPane:
+		desc = fmt.Sprintf("tmux respawn-pane -k -t %s %q", a.Target, a.Command)
+		run = func() error { return t.RespawnPane(a.Target, a.Command) }
+	case actions.OpKillSession:
+		desc = "tmux kill-session -t " + a.Target
+		run = func() error { return t.KillSession(a.Target) }
+	case actions.OpExec:
+		desc = a.Command
+		run = func() error {
+			c := util.ShellCommand(context.Background(), a.Command) // replaying the user's own recorded command
+			c.Dir = a.WorkDir
+			c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+			done := actions.Track(a.Kind, a.Op, a.Target, a.WorkDir, a.Command)
+			err := c.Run()
+			done(err)
+			return err
+		}
+	default:
+		return fmt.Errorf("action %s: cannot replay %q", a.ID, a.Op)
+	}
+
+	if actionsReplayDryRun {
+		fmt.Printf("Would %s %s:\n  %s\n", a.Kind, a.Target, desc)
+		return nil
+	}
+	if err := run(); err != nil {
+		return fmt.Errorf("replaying %s: %w", a.ID, err)
+	}
+	if a.Op != actions.OpExec {
+		fmt.Printf("%s Replayed %s of %s\n", style.SuccessPrefix, a.Kind, a.Target)
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// compatShim keeps an old flag or subcommand spelling working after a
+// rename, with a deprecation notice on stderr, so scripts keep running
+// while they are updated. A renamed flag stays registered as a hidden
+// flag, so it also parses for callers that run rootCmd directly; the shim
+// only adds the notice. A renamed subcommand is rewritten to its new name.
+type compatShim struct {
+	Command string // path of the command the shim applies under, without "gt" ("" for the root)
+	Old     string // old spelling: a long flag ("--out") or a subcommand name
+	New     string // current spelling
+	Since   string // gt version that deprecated Old
+}
+
+// compatShims lists the renamed spellings gt still accepts. When renaming a
+// flag, keep the old name as a hidden flag and add it here.
+var compatShims = []compatShim{
+	{Command: "seance export", Old: "--out", New: "--output", Since: "0.2.5"},
+}
+
+// deprecationQuietEnv silences compat shim notices when set to "1".
+const deprecationQuietEnv = "GT_NO_DEPRECATION_WARNINGS"
+
+// isFlag reports whether the shim renames a flag rather than a subcommand.
+func (s compatShim) isFlag() bool {
+	return strings.HasPrefix(s.Old, "-")
+}
+
+// oldSpelling returns the deprecated spelling as a user would type it.
+func (s compatShim) oldSpelling() string {
+	return strings.TrimSpace("gt " + s.Command + " " + s.Old)
+}
+
+// newSpelling returns the replacement spelling as a user would type it.
+func (s compatShim) newSpelling() string {
+	return strings.TrimSpace("gt " + s.Command + " " + s.New)
+}
+
+// rewriteArgs applies command aliases and compat shims to the command
+// line before cobra parses it. aliases is only called when the first
+// argument isn't a built-in command, so ordinary commands don't pay for
+// loading alias files.
+func rewriteArgs(root *cobra.Command, args []string, aliases func() map[string]string, shims []compatShim, warn io.Writer) []string {
+	args = expandCommandAlias(root, args, aliases)
+	return applyCompatShims(root, args, shims, warn)
+}
+
+// expandCommandAlias replaces a leading alias with its expansion. Built-in
+// commands take precedence, and expansions are not expanded again, so an
+// alias can neither shadow a command nor loop.
+func expandCommandAlias(root *cobra.Command, args []string, aliases func() map[string]string) []string {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isBuiltinCommand(root, args[0]) {
+		return args
+	}
+	expansion, ok := aliases()[args[0]]
+	if !ok {
+		return args
+	}
+	words, err := config.SplitCommandLine(expansion)
+	if err != nil || len(words) == 0 {
+		return args
+	}
+	return append(words, args[1:]...)
+}
+
+// isBuiltinCommand reports whether name is one of root's subcommands or
+// their aliases, including the help and completion commands cobra adds.
+func isBuiltinCommand(root *cobra.Command, name string) bool {
+	switch name {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	for _, c := range root.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyCompatShims notes deprecated spellings for the command args
+// resolve to on warn, rewriting renamed subcommands.
+func applyCompatShims(root *cobra.Command, args []string, shims []compatShim, warn io.Writer) []string {
+	if len(shims) == 0 || len(args) == 0 {
+		return args
+	}
+	target, rest, err := root.Find(args)
+	if err != nil || target == nil {
+		return args
+	}
+	path := strings.TrimSpace(strings.TrimPrefix(target.CommandPath(), root.Name()))
+
+	for _, s := range shims {
+		if s.Command != path {
+			continue
+		}
+		if s.isFlag() {
+			if usesFlag(args, s.Old) {
+				noteDeprecated(warn, s)
+			}
+			continue
+		}
+		// A renamed subcommand leaves its parent as the target, with the
+		// old name as the first remaining argument.
+		if len(rest) == 0 || rest[0] != s.Old {
+			continue
+		}
+		for i, a := range args {
+			if a == s.Old {
+				args = append(append(append([]string{}, args[:i]...), s.New), args[i+1:]...)
+				noteDeprecated(warn, s)
+				break
+			}
+		}
+	}
+	return args
+}
+
+// usesFlag reports whether args set the long flag name, in either the
+// "--name value" or "--name=value" form, before any "--".
+func usesFlag(args []string, name string) bool {
+	for _, a := range args {
+		if a == "--" {
+			break
+		}
+		if a == name || strings.HasPrefix(a, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// noteDeprecated tells the user a deprecated spelling was used.
+func noteDeprecated(w io.Writer, s compatShim) {
+	if w == nil || os.Getenv(deprecationQuietEnv) == "1" {
+		return
+	}
+	fmt.Fprintf(w, "%s '%s' is deprecated since v%s; use '%s'\n",
+		style.WarningPrefix, s.oldSpelling(), s.Since, s.newSpelling())
+}
+
+// userAliasesPath returns the path of this user's command alias file.
+func userAliasesPath() string {
+	return filepath.Join(state.ConfigDir(), config.UserAliasesFile)
+}
+
+// commandAlias is one configured alias and where it was defined.
+type commandAlias struct {
+	Name      string `json:"name"`
+	Expansion string `json:"expansion"`
+	Source    string `json:"source"`             // "user" or "town"
+	Shadowed  bool   `json:"shadowed,omitempty"` // a built-in command of the same name wins
+}
+
+// loadCommandAliases merges the town's command_aliases with this user's
+// alias file, the user's winning. Unreadable files contribute nothing.
+func loadCommandAliases() map[string]commandAlias {
+	merged := make(map[string]commandAlias)
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		for name, exp := range loadTownSettingsQuiet(townRoot).CommandAliases {
+			merged[name] = commandAlias{Name: name, Expansion: exp, Source: "town"}
+		}
+	}
+	if user, err := config.LoadCommandAliases(userAliasesPath()); err == nil {
+		for name, exp := range user {
+			merged[name] = commandAlias{Name: name, Expansion: exp, Source: "user"}
+		}
+	}
+	return merged
+}
+
+// commandAliasExpansions adapts loadCommandAliases for expandCommandAlias.
+func commandAliasExpansions() map[string]string {
+	aliases := loadCommandAliases()
+	expansions := make(map[string]string, len(aliases))
+	for name, a := range aliases {
+		expansions[name] = a.Expansion
+	}
+	return expansions
+}
+
+var aliasesJSON bool
+
+var aliasesCmd = &cobra.Command{
+	Use:     "aliases",
+	GroupID: GroupConfig,
+	Short:   "List command aliases and deprecated spellings",
+	Long: `List command aliases and the deprecated spellings gt still accepts.
+
+Aliases are shortcuts for gt command lines: 'gt s' can run
+'gt seance --recent 10'. Arguments after the alias are appended to its
+expansion. They come from two places:
+
+  town  command_aliases in settings/config.json, shared by everyone
+  user  ~/.config/gastown/aliases.json, managed with gt aliases set/remove
+
+A user alias overrides a town alias of the same name. Built-in commands
+always win, so an alias named after one is listed as shadowed and never
+runs.
+
+Deprecated spellings are flags and commands that were renamed. The old
+spelling keeps working and prints a notice naming the new one; set
+GT_NO_DEPRECATION_WARNINGS=1 to silence the notices.
+
+Examples:
+  gt aliases
+  gt aliases set s seance --recent 10
+  gt aliases remove s
+  gt aliases --json`,
+	Args: cobra.NoArgs,
+	RunE: runAliases,
+}
+
+var aliasesSetCmd = &cobra.Command{
+	Use:   "set <name> <command...>",
+	Short: "Define a user command alias",
+	Long: `Define a command alias in ~/.config/gastown/aliases.json.
+
+The command is everything after the name, without the leading "gt".
+Quote it to keep flags from being read by gt aliases itself.
+
+Examples:
+  gt aliases set s "seance --recent 10"
+  gt aliases set today seance --since 1d`,
+	DisableFlagParsing: true,
+	RunE:               runAliasesSet,
+}
+
+var aliasesRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a user command alias",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAliasesRemove,
+}
+
+func init() {
+	aliasesCmd.Flags().BoolVar(&aliasesJSON, "json", false, "Output as JSON")
+	aliasesCmd.AddCommand(aliasesSetCmd)
+	aliasesCmd.AddCommand(aliasesRemoveCmd)
+
+	rootCmd.AddCommand(aliasesCmd)
+}
+
+func runAliases(cmd *cobra.Command, args []string) error {
+	merged := loadCommandAliases()
+	aliases := make([]commandAlias, 0, len(merged))
+	for _, a := range merged {
+		a.Shadowed = isBuiltinCommand(rootCmd, a.Name)
+		aliases = append(aliases, a)
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Name < aliases[j].Name })
+
+	if aliasesJSON {
+		type shimJSON struct {
+			Old   string `json:"old"`
+			New   string `json:"new"`
+			Since string `json:"since"`
+		}
+		shims := make([]shimJSON, 0, len(compatShims))
+		for _, s := range compatShims {
+			shims = append(shims, shimJSON{Old: s.oldSpelling(), New: s.newSpelling(), Since: s.Since})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Aliases    []commandAlias `json:"aliases"`
+			Deprecated []shimJSON     `json:"deprecated"`
+		}{aliases, shims})
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render("Command aliases"))
+	if len(aliases) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("None. Add one with: gt aliases set <name> <command...>"))
+	}
+	for _, a := range aliases {
+		line := fmt.Sprintf("  %-12s gt %-40s %s", a.Name, a.Expansion, style.Dim.Render(a.Source))
+		if a.Shadowed {
+			line += " " + style.Warning.Render("(shadowed by built-in command)")
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Deprecated spellings"))
+	if len(compatShims) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("None."))
+	}
+	for _, s := range compatShims {
+		fmt.Printf("  %-32s → %-32s %s\n", s.oldSpelling(), s.newSpelling(), style.Dim.Render("since v"+s.Since))
+	}
+	return nil
+}
+
+func runAliasesSet(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+		return cmd.Help()
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("expected <name> <command...>")
+	}
+	name := args[0]
+	expansion := strings.Join(args[1:], " ")
+	if len(args) == 2 {
+		expansion = args[1]
+	}
+	expansion = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(expansion), "gt "))
+
+	path := userAliasesPath()
+	aliases, err := config.LoadCommandAliases(path)
+	if err != nil {
+		return err
+	}
+	aliases[name] = expansion
+	if err := config.SaveCommandAliases(path, aliases); err != nil {
+		return err
+	}
+	fmt.Printf("%s gt %s → gt %s\n", style.SuccessPrefix, name, expansion)
+	if isBuiltinCommand(rootCmd, name) {
+		fmt.Printf("%s '%s' is a built-in command, so the alias won't run\n", style.WarningPrefix, name)
+	}
+	return nil
+}
+
+func runAliasesRemove(cmd *cobra.Command, args []string) error {
+	path := userAliasesPath()
+	aliases, err := config.LoadCommandAliases(path)
+	if err != nil {
+		return err
+	}
+	if _, ok := aliases[args[0]]; !ok {
+		return fmt.Errorf("no user alias %q", args[0])
+	}
+	delete(aliases, args[0])
+	if err := config.SaveCommandAliases(path, aliases); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed alias %s\n", style.SuccessPrefix, args[0])
+	return nil
+}
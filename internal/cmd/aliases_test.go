@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// aliasTestRoot builds a small command tree: gt seance {export,list} and
+// gt status (alias st).
+func aliasTestRoot() *cobra.Command {
+	root := &cobra.Command{Use: "gt"}
+	seance := &cobra.Command{Use: "seance", Run: func(*cobra.Command, []string) {}}
+	export := &cobra.Command{Use: "export", Run: func(*cobra.Command, []string) {}}
+	export.Flags().String("output", "", "")
+	seance.AddCommand(export, &cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}})
+	seance.Flags().Int("recent", 20, "")
+	root.AddCommand(seance, &cobra.Command{Use: "status", Aliases: []string{"st"}, Run: func(*cobra.Command, []string) {}})
+	return root
+}
+
+func TestExpandCommandAlias(t *testing.T) {
+	root := aliasTestRoot()
+	loads := 0
+	aliases := func() map[string]string {
+		loads++
+		return map[string]string{
+			"s":      "seance --recent 10",
+			"status": "seance",     // shadowed by the built-in
+			"st":     "seance",     // shadowed by a built-in alias
+			"loop":   "loop again", // expansions aren't re-expanded
+			"quoted": `seance export --output "my file.md"`,
+		}
+	}
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"s"}, []string{"seance", "--recent", "10"}},
+		{[]string{"s", "--json"}, []string{"seance", "--recent", "10", "--json"}},
+		{[]string{"quoted"}, []string{"seance", "export", "--output", "my file.md"}},
+		{[]string{"loop", "x"}, []string{"loop", "again", "x"}},
+		{[]string{"status"}, []string{"status"}},
+		{[]string{"st"}, []string{"st"}},
+		{[]string{"unknown"}, []string{"unknown"}},
+		{[]string{"--version"}, []string{"--version"}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := expandCommandAlias(root, tt.args, aliases); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expandCommandAlias(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+
+	loads = 0
+	expandCommandAlias(root, []string{"seance", "list"}, aliases)
+	if loads != 0 {
+		t.Error("aliases loaded for a built-in command")
+	}
+}
+
+func TestApplyCompatShims(t *testing.T) {
+	root := aliasTestRoot()
+	shims := []compatShim{
+		{Command: "seance export", Old: "--out", New: "--output", Since: "0.2.5"},
+		{Command: "seance", Old: "ls", New: "list", Since: "0.2.5"},
+	}
+
+	tests := []struct {
+		args   []string
+		want   []string
+		notice string
+	}{
+		{[]string{"seance", "export", "abc", "--out", "x.md"}, []string{"seance", "export", "abc", "--out", "x.md"}, "'gt seance export --out' is deprecated since v0.2.5; use 'gt seance export --output'"},
+		{[]string{"seance", "export", "--out=x.md"}, []string{"seance", "export", "--out=x.md"}, "--out"},
+		{[]string{"seance", "export", "--", "--out"}, []string{"seance", "export", "--", "--out"}, ""},
+		{[]string{"seance", "export", "--output", "x.md"}, []string{"seance", "export", "--output", "x.md"}, ""},
+		{[]string{"seance", "ls"}, []string{"seance", "list"}, "'gt seance ls' is deprecated"},
+		{[]string{"seance", "--out", "x"}, []string{"seance", "--out", "x"}, ""},
+		{[]string{"nope", "--out"}, []string{"nope", "--out"}, ""},
+	}
+	for _, tt := range tests {
+		var warn bytes.Buffer
+		got := applyCompatShims(root, tt.args, shims, &warn)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("applyCompatShims(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+		if tt.notice == "" && warn.Len() > 0 {
+			t.Errorf("applyCompatShims(%q) warned %q", tt.args, warn.String())
+		}
+		if tt.notice != "" && !strings.Contains(warn.String(), tt.notice) {
+			t.Errorf("applyCompatShims(%q) warned %q, want %q", tt.args, warn.String(), tt.notice)
+		}
+	}
+
+	t.Setenv(deprecationQuietEnv, "1")
+	var warn bytes.Buffer
+	if got := applyCompatShims(root, []string{"seance", "export", "--out", "x"}, shims, &warn); warn.Len() > 0 {
+		t.Errorf("quiet shim = %q, warned %q", got, warn.String())
+	}
+}
+
+func TestCompatShimsResolve(t *testing.T) {
+	for _, s := range compatShims {
+		target, _, err := rootCmd.Find(strings.Fields(s.Command))
+		if err != nil || strings.TrimSpace(strings.TrimPrefix(target.CommandPath(), "gt")) != s.Command {
+			t.Errorf("shim %q: command %q not found", s.Old, s.Command)
+			continue
+		}
+		if !s.isFlag() {
+			continue
+		}
+		if target.Flags().Lookup(strings.TrimLeft(s.New, "-")) == nil {
+			t.Errorf("shim %q: %s has no flag %s", s.Old, s.Command, s.New)
+		}
+		if f := target.Flags().Lookup(strings.TrimLeft(s.Old, "-")); f == nil || !f.Hidden {
+			t.Errorf("shim %q: %s must keep the old flag registered and hidden", s.Old, s.Command)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	bannerRole  string
+	bannerFrom  string
+	bannerTopic string
+	bannerJSON  bool
+)
+
+var bannerCmd = &cobra.Command{
+	Use:     "banner",
+	GroupID: GroupAgents,
+	Short:   "Print an agent's startup banner",
+	Long: `Print the startup banner for an agent: its standing orders, current
+assignments (hooked or in-progress beads), unread mail count, and the
+[GAS TOWN] beacon it should emit.
+
+Designed to run from the SessionStart hook, so every agent boots with the
+same situational awareness. Output is markdown for the agent to read.
+Lookups that fail (no bd, no mailbox) are reported in the banner rather
+than failing the hook.
+
+The role defaults to the one detected from GT_ROLE or the working
+directory. --role takes an address; rig-level roles may omit the rig when
+run inside it (crew/joe, polecats/toast, witness).
+
+Examples:
+  gt banner
+  gt banner --role crew/joe
+  gt banner --role gastown/polecats/toast --from witness --topic assigned
+  gt banner --json`,
+	RunE: runBanner,
+}
+
+func init() {
+	bannerCmd.Flags().StringVar(&bannerRole, "role", "", "Agent address (default: detected)")
+	bannerCmd.Flags().StringVar(&bannerFrom, "from", "self", "Sender named in the beacon")
+	bannerCmd.Flags().StringVar(&bannerTopic, "topic", "", "Beacon topic (default: assigned when work is hooked, else cold-start)")
+	bannerCmd.Flags().BoolVar(&bannerJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(bannerCmd)
+}
+
+// banner is what an agent is told at startup.
+type banner struct {
+	Identity       string             `json:"identity"`
+	Role           Role               `json:"role"`
+	StandingOrders []string           `json:"standing_orders"`
+	Assignments    []bannerAssignment `json:"assignments"`
+	UnreadMail     int                `json:"unread_mail"`
+	Beacon         string             `json:"beacon"`
+	Warnings       []string           `json:"warnings,omitempty"`
+}
+
+// bannerAssignment is a bead the agent is working.
+type bannerAssignment struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+func runBanner(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cwd, _ := os.Getwd()
+
+	identity, err := resolveBannerIdentity(bannerRole, os.Getenv("GT_RIG"), detectRole(cwd, townRoot).Rig)
+	if err != nil {
+		return err
+	}
+	role, rig, name := parseRoleString(identity)
+	identity = getAgentIdentity(RoleInfo{Role: role, Rig: rig, Polecat: name})
+	if identity == "" {
+		return fmt.Errorf("unknown role %q", bannerRole)
+	}
+
+	b := banner{
+		Identity:       identity,
+		Role:           role,
+		StandingOrders: standingOrders(role),
+		Assignments:    []bannerAssignment{},
+	}
+
+	assignments, err := bannerAssignments(townRoot, role, rig, identity)
+	if err != nil {
+		b.Warnings = append(b.Warnings, fmt.Sprintf("assignments unavailable: %v", err))
+	}
+	b.Assignments = append(b.Assignments, assignments...)
+
+	if mailbox, err := mail.NewRouter(townRoot).GetMailbox(identity); err != nil {
+		b.Warnings = append(b.Warnings, fmt.Sprintf("mail unavailable: %v", err))
+	} else if _, unread, err := mailbox.Count(); err != nil {
+		b.Warnings = append(b.Warnings, fmt.Sprintf("mail unavailable: %v", err))
+	} else {
+		b.UnreadMail = unread
+	}
+
+	nudge := session.StartupNudgeConfig{Recipient: identity, Sender: bannerFrom, Topic: bannerTopic}
+	if nudge.Topic == "" {
+		nudge.Topic = "cold-start"
+		if len(b.Assignments) > 0 {
+			nudge.Topic = "assigned"
+		}
+	}
+	if nudge.Topic == "assigned" && len(b.Assignments) > 0 {
+		nudge.MolID = b.Assignments[0].ID
+	}
+	b.Beacon = session.FormatStartupNudge(nudge)
+
+	if bannerJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(b)
+	}
+	renderBanner(os.Stdout, b)
+	return nil
+}
+
+// resolveBannerIdentity turns a --role value into an agent address. An
+// empty role is detected from the environment; rig-level roles without a
+// rig take envRig, then cwdRig.
+func resolveBannerIdentity(role, envRig, cwdRig string) (string, error) {
+	role = strings.TrimSuffix(strings.TrimSpace(role), "/")
+	if role == "" {
+		sender := strings.TrimSuffix(detectSender(), "/")
+		if sender == "overseer" {
+			return "", fmt.Errorf("could not detect an agent role here; pass --role")
+		}
+		return sender, nil
+	}
+
+	first := strings.SplitN(role, "/", 2)[0]
+	switch first {
+	case "crew", "polecats", "witness", "refinery":
+		rig := envRig
+		if rig == "" {
+			rig = cwdRig
+		}
+		if rig == "" {
+			return "", fmt.Errorf("role %q needs a rig: use <rig>/%s", role, role)
+		}
+		return rig + "/" + role, nil
+	}
+	return role, nil
+}
+
+// standingOrders returns the rules an agent in role always follows.
+func standingOrders(role Role) []string {
+	orders := []string{
+		"If work is on your hook, run it immediately. The hook is your assignment.",
+		"Check mail with `gt mail inbox` and act on anything addressed to you.",
+	}
+	switch role {
+	case RoleMayor:
+		orders = append(orders,
+			"Coordinate across rigs; delegate to refineries and crews, not directly to polecats.",
+			"Look for 🤝 HANDOFF mail and continue your predecessor's work.")
+	case RoleDeacon:
+		orders = append(orders,
+			"Run your patrol; keep the town's agents alive and escalate what you can't fix.")
+	case RoleWitness:
+		orders = append(orders,
+			"Watch your rig's polecats; nudge the stuck and report the dead.")
+	case RoleRefinery:
+		orders = append(orders,
+			"Process the merge queue in order; never merge a red build.")
+	case RolePolecat:
+		orders = append(orders,
+			"Work only your hooked bead; when it's finished, run `gt done`.",
+			"Stuck? Send mail to your witness rather than waiting.")
+	case RoleCrew:
+		orders = append(orders,
+			"Close beads you finish with `bd close`; file new work with `bd create`.")
+	}
+	return append(orders,
+		"Before your context runs out, hand off with `gt handoff`.")
+}
+
+// bannerAssignments returns the beads hooked to, or in progress for, an
+// agent. Rig-level agents are looked up in their rig's beads; town-level
+// agents in the town's, then every rig's.
+func bannerAssignments(townRoot string, role Role, rig, identity string) ([]bannerAssignment, error) {
+	dir := townRoot
+	if rig != "" {
+		dir = filepath.Join(townRoot, rig)
+	}
+	b := beads.New(dir)
+
+	var issues []*beads.Issue
+	for _, status := range []string{beads.StatusHooked, "in_progress"} {
+		found, err := b.List(beads.ListOptions{Status: status, Assignee: identity, Priority: -1})
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, found...)
+	}
+	if len(issues) == 0 && (role == RoleMayor || role == RoleDeacon) {
+		issues = scanAllRigsForHookedBeads(townRoot, identity)
+	}
+
+	out := make([]bannerAssignment, 0, len(issues))
+	for _, issue := range issues {
+		out = append(out, bannerAssignment{ID: issue.ID, Title: issue.Title, Status: issue.Status})
+	}
+	return out, nil
+}
+
+// renderBanner writes the banner as markdown.
+func renderBanner(w io.Writer, b banner) {
+	fmt.Fprintf(w, "# Gas Town: %s\n\n", b.Identity)
+
+	fmt.Fprintln(w, "## Standing orders")
+	for i, order := range b.StandingOrders {
+		fmt.Fprintf(w, "%d. %s\n", i+1, order)
+	}
+
+	fmt.Fprintln(w, "\n## Assignments")
+	if len(b.Assignments) == 0 {
+		fmt.Fprintln(w, "Nothing hooked or in progress. Wait for instructions or check `bd ready`.")
+	}
+	for _, a := range b.Assignments {
+		fmt.Fprintf(w, "- %s [%s] %s\n", a.ID, a.Status, a.Title)
+	}
+
+	fmt.Fprintln(w, "\n## Mail")
+	switch b.UnreadMail {
+	case 0:
+		fmt.Fprintln(w, "No unread mail.")
+	case 1:
+		fmt.Fprintln(w, "1 unread message. Read it with `gt mail inbox`.")
+	default:
+		fmt.Fprintf(w, "%d unread messages. Read them with `gt mail inbox`.\n", b.UnreadMail)
+	}
+
+	fmt.Fprintln(w, "\n## Beacon")
+	fmt.Fprintln(w, "Emit this as your first message:")
+	fmt.Fprintf(w, "\n```\n%s\n```\n", b.Beacon)
+
+	if len(b.Warnings) > 0 {
+		fmt.Fprintln(w, "\n## Warnings")
+		for _, warning := range b.Warnings {
+			fmt.Fprintf(w, "- %s\n", warning)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestResolveBannerIdentity(t *testing.T) {
+	tests := []struct {
+		role, envRig, cwdRig string
+		want                 string
+		wantErr              bool
+	}{
+		{role: "gastown/crew/joe", want: "gastown/crew/joe"},
+		{role: "mayor/", want: "mayor"},
+		{role: "crew/joe", cwdRig: "gastown", want: "gastown/crew/joe"},
+		{role: "polecats/toast", envRig: "beads", cwdRig: "gastown", want: "beads/polecats/toast"},
+		{role: "witness", cwdRig: "gastown", want: "gastown/witness"},
+		{role: "crew/joe", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := resolveBannerIdentity(tt.role, tt.envRig, tt.cwdRig)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveBannerIdentity(%q) error = %v, wantErr %v", tt.role, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resolveBannerIdentity(%q) = %q, want %q", tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestRenderBanner(t *testing.T) {
+	b := banner{
+		Identity:       "gastown/crew/joe",
+		Role:           RoleCrew,
+		StandingOrders: standingOrders(RoleCrew),
+		Assignments:    []bannerAssignment{{ID: "gt-abc12", Title: "Fix the widget", Status: "hooked"}},
+		UnreadMail:     3,
+		Beacon:         "[GAS TOWN] gastown/crew/joe <- self • 2026-01-01T09:00 • assigned:gt-abc12",
+	}
+	var buf bytes.Buffer
+	renderBanner(&buf, b)
+	out := buf.String()
+	for _, want := range []string{
+		"# Gas Town: gastown/crew/joe",
+		"1. If work is on your hook",
+		"- gt-abc12 [hooked] Fix the widget",
+		"3 unread messages",
+		"```\n[GAS TOWN] gastown/crew/joe <- self",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("banner missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "## Warnings") {
+		t.Errorf("unexpected warnings section:\n%s", out)
+	}
+}
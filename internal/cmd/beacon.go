@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/activity"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beaconHeartbeatEvery time.Duration
+	beaconHeartbeatTopic string
+)
+
+var beaconCmd = &cobra.Command{
+	Use:     "beacon",
+	GroupID: GroupDiag,
+	Short:   "Agent liveness signals",
+	RunE:    requireSubcommand,
+	Long: `Liveness signals that complement session beacons.
+
+Heartbeats record that an agent is alive even while its transcript is
+quiet (e.g., during a long build). The dashboard uses the most recent
+heartbeat for "last seen".`,
+}
+
+var beaconHeartbeatCmd = &cobra.Command{
+	Use:   "heartbeat",
+	Short: "Record a liveness heartbeat for the current agent",
+	Long: `Record a heartbeat for the current agent.
+
+Designed to run from a PostToolUse hook: calls within --every of the last
+heartbeat are no-ops, so the event log gets at most one record per
+interval. Changing --topic always records immediately.
+
+The session ID is read from the hook payload on stdin when present.
+
+Examples:
+  gt beacon heartbeat                       # From a hook
+  gt beacon heartbeat --topic "running e2e"  # Update what you're doing`,
+	RunE: runBeaconHeartbeat,
+}
+
+var beaconStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the last heartbeat from each agent",
+	RunE:  runBeaconStatus,
+}
+
+func init() {
+	beaconHeartbeatCmd.Flags().DurationVar(&beaconHeartbeatEvery, "every", 5*time.Minute, "Minimum interval between heartbeats")
+	beaconHeartbeatCmd.Flags().StringVar(&beaconHeartbeatTopic, "topic", "", "What the agent is working on")
+
+	beaconCmd.AddCommand(beaconHeartbeatCmd)
+	beaconCmd.AddCommand(beaconStatusCmd)
+	rootCmd.AddCommand(beaconCmd)
+}
+
+func runBeaconHeartbeat(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil // Hooks outside a town are a no-op
+	}
+
+	hb := events.Heartbeat{Actor: detectSender(), Topic: beaconHeartbeatTopic}
+	if input := readStdinJSON(); input != nil {
+		hb.SessionID = input.SessionID
+	}
+
+	_, err = events.RecordHeartbeat(townRoot, hb, beaconHeartbeatEvery)
+	return err
+}
+
+func runBeaconStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	heartbeats, err := events.LoadHeartbeats(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(heartbeats) == 0 {
+		fmt.Println(style.Dim.Render("No heartbeats recorded. Add 'gt beacon heartbeat' to a PostToolUse hook."))
+		return nil
+	}
+
+	actors := make([]string, 0, len(heartbeats))
+	for actor := range heartbeats {
+		actors = append(actors, actor)
+	}
+	sort.Slice(actors, func(i, j int) bool {
+		return heartbeats[actors[i]].Timestamp.After(heartbeats[actors[j]].Timestamp)
+	})
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Last Seen"))
+	for _, actor := range actors {
+		hb := heartbeats[actor]
+		info := activity.Calculate(hb.Timestamp)
+		fmt.Printf("  %-30s  %-8s  %s\n", compactAddress(actor), info.FormattedAge, style.Dim.Render(hb.Topic))
+	}
+	return nil
+}
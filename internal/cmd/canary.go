@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// canaryRole is the beacon recipient used by canary sessions.
+const canaryRole = "canary/crew/canary"
+
+// canaryMarker is echoed by the canary session's tool call.
+const canaryMarker = "gt-canary-ok"
+
+// canaryHooks are the hook events the canary expects to fire.
+var canaryHooks = []string{"SessionStart", "PostToolUse", "Stop"}
+
+var (
+	canaryClaude  string
+	canaryTimeout time.Duration
+	canaryKeep    bool
+	canaryJSON    bool
+)
+
+var canaryCmd = &cobra.Command{
+	Use:     "canary",
+	GroupID: GroupDiag,
+	Short:   "Check a Claude Code version's compatibility before upgrading",
+	RunE:    requireSubcommand,
+}
+
+var canaryRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run a scripted session and verify Gas Town still understands it",
+	Long: `Run a small scripted session against a Claude Code binary and check
+that Gas Town's integrations still work with it:
+
+  - the session runs and reports its session ID
+  - the transcript is where Gas Town looks and every JSONL line parses
+  - turns, tool calls, and token usage are extracted
+  - the [GAS TOWN] beacon registers the session's role
+  - SessionStart, PostToolUse, and Stop hooks fire
+
+The session runs in a scratch directory with its own hook settings, so
+the town is untouched. Point --claude at the new version before the whole
+town upgrades. Exits non-zero if any check fails.
+
+The canary makes one real (small) API call.
+
+Examples:
+  gt canary run
+  gt canary run --claude ~/Downloads/claude-next/claude
+  gt canary run --json`,
+	RunE: runCanary,
+}
+
+var canaryRecordHookCmd = &cobra.Command{
+	Use:    "record-hook <event>",
+	Short:  "Record that a hook fired (used by canary sessions)",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE:   runCanaryRecordHook,
+}
+
+func init() {
+	canaryRunCmd.Flags().StringVar(&canaryClaude, "claude", "claude", "Claude Code binary to test")
+	canaryRunCmd.Flags().DurationVar(&canaryTimeout, "timeout", 3*time.Minute, "Maximum time for the scripted session")
+	canaryRunCmd.Flags().BoolVar(&canaryKeep, "keep", false, "Keep the scratch directory for inspection")
+	canaryRunCmd.Flags().BoolVar(&canaryJSON, "json", false, "Output as JSON")
+
+	canaryCmd.AddCommand(canaryRunCmd)
+	canaryCmd.AddCommand(canaryRecordHookCmd)
+	rootCmd.AddCommand(canaryCmd)
+}
+
+// canaryCheck is the result of one compatibility check.
+type canaryCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// canaryReport is the outcome of a canary run.
+type canaryReport struct {
+	Version    string        `json:"version"`
+	SessionID  string        `json:"session_id,omitempty"`
+	Transcript string        `json:"transcript,omitempty"`
+	Checks     []canaryCheck `json:"checks"`
+	OK         bool          `json:"ok"`
+}
+
+func runCanary(cmd *cobra.Command, args []string) error {
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating gt binary: %w", err)
+	}
+
+	versionOut, err := exec.Command(canaryClaude, "--version").Output() //nolint:gosec // G204: user-specified binary
+	if err != nil {
+		return fmt.Errorf("running %s --version: %w", canaryClaude, err)
+	}
+	report := canaryReport{Version: strings.TrimSpace(string(versionOut))}
+
+	dir, err := os.MkdirTemp("", "gt-canary-")
+	if err != nil {
+		return fmt.Errorf("creating scratch directory: %w", err)
+	}
+	// Resolve symlinks (e.g., /tmp on macOS) so the transcript path matches
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	if canaryKeep {
+		fmt.Fprintf(os.Stderr, "Scratch directory: %s\n", dir)
+	} else {
+		defer os.RemoveAll(dir)
+	}
+
+	if err := writeCanarySettings(dir, gtPath); err != nil {
+		return err
+	}
+
+	if !canaryJSON {
+		fmt.Printf("%s Running canary session with %s...\n", style.Bold.Render("🐤"), report.Version)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), canaryTimeout)
+	defer cancel()
+	beacon := session.FormatStartupNudge(session.StartupNudgeConfig{Recipient: canaryRole, Sender: "canary", Topic: "canary"})
+	prompt := fmt.Sprintf("%s\n\nRun the shell command `echo %s` with the Bash tool, then reply with the single word DONE.",
+		beacon, canaryMarker)
+	c := exec.CommandContext(ctx, canaryClaude, //nolint:gosec // G204: user-specified binary
+		"--print", "--output-format", "json",
+		"--allowedTools", "Bash(echo:*)",
+		prompt)
+	c.Dir = dir
+	c.Env = append(os.Environ(), "GT_CANARY_DIR="+dir)
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, runErr := c.Output()
+
+	var result struct {
+		SessionID string `json:"session_id"`
+	}
+	switch {
+	case runErr != nil:
+		report.Checks = append(report.Checks, canaryCheck{Name: "session runs", Detail: fmt.Sprintf("%v: %s", runErr, strings.TrimSpace(stderr.String()))})
+	case json.Unmarshal(out, &result) != nil || result.SessionID == "":
+		report.Checks = append(report.Checks, canaryCheck{Name: "session runs", Detail: "--output-format json did not report a session_id"})
+	default:
+		report.SessionID = result.SessionID
+		report.Checks = append(report.Checks, canaryCheck{Name: "session runs", OK: true, Detail: "session " + result.SessionID})
+	}
+
+	if report.SessionID != "" {
+		info, err := claude.FindSession(report.SessionID)
+		if err != nil {
+			report.Checks = append(report.Checks, canaryCheck{Name: "transcript found", Detail: err.Error()})
+		} else {
+			report.Transcript = info.Path
+			report.Checks = append(report.Checks, canaryCheck{Name: "transcript found", OK: true, Detail: info.Path})
+			report.Checks = append(report.Checks, evaluateCanaryTranscript(info)...)
+		}
+	}
+	report.Checks = append(report.Checks, evaluateCanaryHooks(filepath.Join(dir, "hooks"))...)
+
+	report.OK = true
+	for _, check := range report.Checks {
+		report.OK = report.OK && check.OK
+	}
+
+	if canaryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+		for _, check := range report.Checks {
+			mark := style.SuccessPrefix
+			if !check.OK {
+				mark = style.ErrorPrefix
+			}
+			fmt.Printf("%s %-20s %s\n", mark, check.Name, style.Dim.Render(check.Detail))
+		}
+		fmt.Println()
+	}
+
+	if !report.OK {
+		return NewSilentExit(1)
+	}
+	if !canaryJSON {
+		fmt.Printf("%s %s is compatible with this version of gt\n", style.SuccessPrefix, report.Version)
+	}
+	return nil
+}
+
+// writeCanarySettings installs hooks in the scratch directory that record
+// each event via gt canary record-hook.
+func writeCanarySettings(dir, gtPath string) error {
+	hook := func(event string) []map[string]interface{} {
+		return []map[string]interface{}{{
+			"matcher": "",
+			"hooks": []map[string]interface{}{{
+				"type":    "command",
+				"command": fmt.Sprintf("%q canary record-hook %s", gtPath, event),
+			}},
+		}}
+	}
+	hooks := make(map[string]interface{})
+	for _, event := range canaryHooks {
+		hooks[event] = hook(event)
+	}
+	data, err := json.MarshalIndent(map[string]interface{}{"hooks": hooks}, "", "  ")
+	if err != nil {
+		return err
+	}
+	settingsDir := filepath.Join(dir, ".claude")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		return fmt.Errorf("creating canary settings: %w", err)
+	}
+	return os.WriteFile(filepath.Join(settingsDir, "settings.json"), data, 0644) //nolint:gosec // G306: scratch settings
+}
+
+func runCanaryRecordHook(cmd *cobra.Command, args []string) error {
+	dir := os.Getenv("GT_CANARY_DIR")
+	if dir == "" {
+		return nil // Not a canary session
+	}
+	hooksDir := filepath.Join(dir, "hooks")
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return err
+	}
+	payload, _ := io.ReadAll(io.LimitReader(os.Stdin, 1<<20))
+	name := filepath.Base(args[0]) + ".json"
+	return os.WriteFile(filepath.Join(hooksDir, name), payload, 0644) //nolint:gosec // G306: scratch hook marker
+}
+
+// evaluateCanaryTranscript checks that Gas Town can parse the canary
+// session's transcript.
+func evaluateCanaryTranscript(info *claude.SessionInfo) []canaryCheck {
+	var checks []canaryCheck
+
+	lines, bad, err := countJSONLLines(info.Path)
+	switch {
+	case err != nil:
+		checks = append(checks, canaryCheck{Name: "JSONL parses", Detail: err.Error()})
+	case bad > 0:
+		checks = append(checks, canaryCheck{Name: "JSONL parses", Detail: fmt.Sprintf("%d of %d lines are not valid JSON", bad, lines)})
+	default:
+		checks = append(checks, canaryCheck{Name: "JSONL parses", OK: true, Detail: fmt.Sprintf("%d lines", lines)})
+	}
+
+	turns, err := claude.ReadTurns(info.Path)
+	users, assistants, sawBash := 0, 0, false
+	for _, t := range turns {
+		switch t.Role {
+		case "user":
+			users++
+		case "assistant":
+			assistants++
+		}
+		for _, tool := range t.Tools {
+			sawBash = sawBash || tool == "Bash"
+		}
+	}
+	turnsCheck := canaryCheck{Name: "turns extracted", Detail: fmt.Sprintf("%d user, %d assistant", users, assistants)}
+	turnsCheck.OK = err == nil && users > 0 && assistants > 0
+	checks = append(checks, turnsCheck)
+	checks = append(checks, canaryCheck{Name: "tool calls extracted", OK: sawBash, Detail: "expected a Bash call"})
+
+	usage, err := claude.ReadUsage(info.Path)
+	usageCheck := canaryCheck{Name: "usage extracted"}
+	if err != nil {
+		usageCheck.Detail = err.Error()
+	} else {
+		usageCheck.OK = usage.TotalTokens() > 0 && usage.Model != ""
+		usageCheck.Detail = fmt.Sprintf("%d tokens, model %q", usage.TotalTokens(), usage.Model)
+	}
+	checks = append(checks, usageCheck)
+
+	beacon := canaryCheck{Name: "beacon registers", OK: info.IsGasTown && info.Role == canaryRole}
+	beacon.Detail = fmt.Sprintf("role %q", info.Role)
+	checks = append(checks, beacon)
+
+	return checks
+}
+
+// evaluateCanaryHooks checks that every expected hook recorded a marker.
+func evaluateCanaryHooks(hooksDir string) []canaryCheck {
+	checks := make([]canaryCheck, 0, len(canaryHooks))
+	for _, event := range canaryHooks {
+		check := canaryCheck{Name: event + " hook"}
+		data, err := os.ReadFile(filepath.Join(hooksDir, event+".json")) //nolint:gosec // G304: scratch directory
+		switch {
+		case err != nil:
+			check.Detail = "did not fire"
+		case !json.Valid(bytes.TrimSpace(data)):
+			check.Detail = "fired with unparseable input"
+		default:
+			check.OK = true
+			check.Detail = "fired"
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// countJSONLLines counts non-empty lines in a JSONL file and how many of
+// them aren't valid JSON.
+func countJSONLLines(path string) (lines, bad int, err error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		lines++
+		if !json.Valid(line) {
+			bad++
+		}
+	}
+	return lines, bad, scanner.Err()
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+func TestEvaluateCanaryHooks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "SessionStart.json"), []byte(`{"session_id":"abc"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Stop.json"), []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := evaluateCanaryHooks(dir)
+	want := map[string]bool{"SessionStart hook": true, "PostToolUse hook": false, "Stop hook": false}
+	if len(checks) != len(want) {
+		t.Fatalf("got %d checks, want %d", len(checks), len(want))
+	}
+	for _, c := range checks {
+		if c.OK != want[c.Name] {
+			t.Errorf("%s: OK = %v, want %v (%s)", c.Name, c.OK, want[c.Name], c.Detail)
+		}
+	}
+}
+
+func TestEvaluateCanaryTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	transcript := `{"type":"user","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"[GAS TOWN] role=canary/crew/canary from=canary rig=canary agent=canary topic=canary ts=2025-01-01T00:00:00Z"}}
+{"type":"assistant","timestamp":"2025-01-01T00:00:01Z","message":{"role":"assistant","model":"claude-sonnet-4","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"echo gt-canary-ok"}}],"usage":{"input_tokens":10,"output_tokens":5}}}
+{"type":"assistant","timestamp":"2025-01-01T00:00:02Z","message":{"role":"assistant","model":"claude-sonnet-4","content":[{"type":"text","text":"DONE"}],"usage":{"input_tokens":12,"output_tokens":1}}}
+`
+	if err := os.WriteFile(path, []byte(transcript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info := &claude.SessionInfo{Path: path, IsGasTown: true, Role: canaryRole}
+	for _, c := range evaluateCanaryTranscript(info) {
+		if !c.OK {
+			t.Errorf("%s failed: %s", c.Name, c.Detail)
+		}
+	}
+
+	// A truncated line and a missing beacon role are both reported.
+	if err := os.WriteFile(path, []byte(transcript+`{"type":"user",`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info.Role = ""
+	failed := make(map[string]bool)
+	for _, c := range evaluateCanaryTranscript(info) {
+		if !c.OK {
+			failed[c.Name] = true
+		}
+	}
+	if !failed["JSONL parses"] || !failed["beacon registers"] {
+		t.Errorf("expected JSONL and beacon failures, got %v", failed)
+	}
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/task"
+	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	checkSession  string
+	checkTask     string
+	checkDir      string
+	checkTimeout  string
+	checkJSON     bool
+	checkNoRecord bool
+)
+
+var checkCmd = &cobra.Command{
+	Use:     "check",
+	GroupID: GroupDiag,
+	Short:   "Run task acceptance checks",
+	RunE:    requireSubcommand,
+	Long: `Run the acceptance checks from a task spec and record the result.
+
+Acceptance items with a prefix are checked automatically:
+
+  acceptance:
+    - "run: go test ./internal/mail/..."   # command must exit 0
+    - "exists: docs/mail-retry.md"         # path must exist
+    - "missing: internal/mail/legacy.go"   # path must not exist
+    - Locked inboxes delay delivery        # manual; left to a reviewer
+
+Quote items containing " #" so the rest isn't read as a comment.`,
+}
+
+var checkRunCmd = &cobra.Command{
+	Use:   "run --session <id>",
+	Short: "Run a session's acceptance checks in its worktree",
+	Long: `Run the acceptance checks from a session's task spec in the session's
+worktree, and record pass/fail in the outcome store.
+
+The spec is the one given with --task, else the one attached to the
+session's bead by gt sling --task, else task.yaml in the worktree. Checks
+run in the session's project directory unless --dir is given.
+
+The recorded outcome is success when every automated check passes,
+failure when none do, and partial otherwise; each check's result is
+stored with it. Manual checks are listed but not counted.
+
+Exits non-zero when any check fails.
+
+Examples:
+  gt check run --session abc123
+  gt check run --session abc123 --task tasks/retry.task.yaml
+  gt check run --session abc123 --no-record --json`,
+	Args: cobra.NoArgs,
+	RunE: runCheckRun,
+}
+
+func init() {
+	checkRunCmd.Flags().StringVar(&checkSession, "session", "", "Session whose work to check (required)")
+	checkRunCmd.Flags().StringVar(&checkTask, "task", "", "Task spec to check against (default: the session's)")
+	checkRunCmd.Flags().StringVar(&checkDir, "dir", "", "Directory to run checks in (default: the session's worktree)")
+	checkRunCmd.Flags().StringVar(&checkTimeout, "timeout", "10m", "Time limit for each run: check")
+	checkRunCmd.Flags().BoolVar(&checkJSON, "json", false, "Output as JSON")
+	checkRunCmd.Flags().BoolVar(&checkNoRecord, "no-record", false, "Don't record the result as the session's outcome")
+	_ = checkRunCmd.MarkFlagRequired("session")
+
+	checkCmd.AddCommand(checkRunCmd)
+	rootCmd.AddCommand(checkCmd)
+}
+
+// checkRunOutput is the JSON form of gt check run.
+type checkRunOutput struct {
+	SessionID string             `json:"session_id"`
+	Task      string             `json:"task"`
+	Dir       string             `json:"dir"`
+	Status    outcome.Status     `json:"status"`
+	Recorded  bool               `json:"recorded"`
+	Results   []task.CheckResult `json:"results"`
+}
+
+func runCheckRun(cmd *cobra.Command, args []string) error {
+	timeout, err := parseCheckTimeout(checkTimeout)
+	if err != nil {
+		return err
+	}
+
+	session, err := claude.FindSession(checkSession)
+	if err != nil {
+		return err
+	}
+	dir := checkDir
+	if dir == "" {
+		dir = session.ProjectPath
+	}
+	if dir == "" {
+		return fmt.Errorf("session %s has no project directory; pass --dir", shortSessionID(session.ID))
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("worktree %s not found; pass --dir", dir)
+	}
+
+	specPath, err := sessionTaskPath(session, dir)
+	if err != nil {
+		return err
+	}
+	spec, err := task.Load(specPath)
+	if err != nil {
+		return err
+	}
+	checks := spec.Checks()
+	automated := 0
+	for _, c := range checks {
+		if c.Automated() {
+			automated++
+		}
+	}
+	if automated == 0 {
+		return fmt.Errorf("%s has no automated acceptance checks (prefix items with run:, exists:, or missing:)", specPath)
+	}
+
+	results := task.RunChecks(context.Background(), checks, dir, timeout)
+	status, passed := checkStatus(results)
+
+	recorded := false
+	if !checkNoRecord {
+		recorded, err = recordCheckOutcome(session, status, passed, automated, results)
+		if err != nil {
+			return err
+		}
+	}
+
+	if checkJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(checkRunOutput{
+			SessionID: session.ID,
+			Task:      specPath,
+			Dir:       dir,
+			Status:    status,
+			Recorded:  recorded,
+			Results:   results,
+		}); err != nil {
+			return err
+		}
+	} else {
+		printCheckResults(session, specPath, dir, results, status, passed, automated, recorded)
+	}
+
+	if passed < automated {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// sessionTaskPath finds the task spec for a session: --task, then the
+// spec attached to the session's bead, then task.yaml in dir.
+func sessionTaskPath(session *claude.SessionInfo, dir string) (string, error) {
+	if checkTask != "" {
+		return filepath.Abs(checkTask)
+	}
+	if session.Bead != "" {
+		if issue, err := beads.New(dir).Show(session.Bead); err == nil {
+			if fields := beads.ParseAttachmentFields(issue); fields != nil && fields.AttachedTask != "" {
+				return fields.AttachedTask, nil
+			}
+		}
+	}
+	path := filepath.Join(dir, defaultTaskFile)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("no task spec for session %s: none attached to its bead and no %s in %s; pass --task",
+			shortSessionID(session.ID), defaultTaskFile, dir)
+	}
+	return path, nil
+}
+
+// parseCheckTimeout parses --timeout, which must be positive.
+func parseCheckTimeout(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid --timeout %q (e.g. 5m)", s)
+	}
+	return d, nil
+}
+
+// checkStatus derives an outcome from check results: success when every
+// automated check passed, failure when none did, partial otherwise.
+func checkStatus(results []task.CheckResult) (outcome.Status, int) {
+	passed, run := 0, 0
+	for _, r := range results {
+		if r.Skipped {
+			continue
+		}
+		run++
+		if r.Passed {
+			passed++
+		}
+	}
+	switch {
+	case passed == run:
+		return outcome.Success, passed
+	case passed == 0:
+		return outcome.Failure, passed
+	}
+	return outcome.Partial, passed
+}
+
+// recordCheckOutcome appends the check results to the outcome store. It
+// reports false without error for sessions with no Gas Town role, which
+// outcomes can't be attributed to.
+func recordCheckOutcome(session *claude.SessionInfo, status outcome.Status, passed, automated int, results []task.CheckResult) (bool, error) {
+	if session.Role == "" {
+		return false, nil
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return false, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	role, rig, _ := parseRoleString(session.Role)
+	rec := outcome.Record{
+		SessionID:     session.ID,
+		Status:        status,
+		Reason:        fmt.Sprintf("%d/%d acceptance checks passed", passed, automated),
+		Role:          string(role),
+		Rig:           rig,
+		PromptVersion: templates.RoleVersion(string(role)),
+		SetBy:         "gt check run",
+	}
+	for _, r := range results {
+		if r.Skipped {
+			continue
+		}
+		rec.Checks = append(rec.Checks, outcome.Check{Check: r.Text, Passed: r.Passed, Detail: r.Detail})
+	}
+	prev, _ := outcome.Get(townRoot, rec.SessionID)
+	if err := outcome.Append(townRoot, rec); err != nil {
+		return false, fmt.Errorf("recording outcome: %w", err)
+	}
+	if err := logOutcomeChange(prev, rec); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+func printCheckResults(session *claude.SessionInfo, specPath, dir string, results []task.CheckResult, status outcome.Status, passed, automated int, recorded bool) {
+	fmt.Printf("%s %s\n", style.Bold.Render("Checks for"), shortSessionID(session.ID))
+	fmt.Printf("  Task: %s\n", specPath)
+	fmt.Printf("  Dir:  %s\n\n", dir)
+
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			fmt.Printf("  %s %s\n", style.Dim.Render("-"), style.Dim.Render(r.Text+" (manual)"))
+		case r.Passed:
+			fmt.Printf("  %s %s\n", style.SuccessPrefix, r.Text)
+		default:
+			fmt.Printf("  %s %s\n", style.ErrorPrefix, r.Text)
+			for _, line := range strings.Split(r.Detail, "\n") {
+				fmt.Printf("      %s\n", style.Dim.Render(line))
+			}
+		}
+	}
+
+	fmt.Printf("\n%d/%d automated checks passed: %s\n", passed, automated, status)
+	switch {
+	case recorded:
+		fmt.Printf("%s Recorded %s for %s\n", style.SuccessPrefix, status, shortSessionID(session.ID))
+	case !checkNoRecord:
+		fmt.Printf("%s Not recorded: session has no Gas Town role\n", style.WarningPrefix)
+	}
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/task"
+)
+
+func TestCheckStatus(t *testing.T) {
+	pass := task.CheckResult{Passed: true}
+	fail := task.CheckResult{}
+	manual := task.CheckResult{Skipped: true}
+
+	tests := []struct {
+		name    string
+		results []task.CheckResult
+		want    outcome.Status
+		passed  int
+	}{
+		{"all pass", []task.CheckResult{pass, pass, manual}, outcome.Success, 2},
+		{"none pass", []task.CheckResult{fail, manual, fail}, outcome.Failure, 0},
+		{"some pass", []task.CheckResult{pass, fail}, outcome.Partial, 1},
+	}
+	for _, tt := range tests {
+		got, passed := checkStatus(tt.results)
+		if got != tt.want || passed != tt.passed {
+			t.Errorf("%s: checkStatus = %s, %d; want %s, %d", tt.name, got, passed, tt.want, tt.passed)
+		}
+	}
+}
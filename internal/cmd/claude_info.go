@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/perf"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// claudeInfoCacheFile caches the probe, relative to the town root.
+const claudeInfoCacheFile = ".runtime/claude-info.json"
+
+// claudeInfoModelsTTL is how long the recently used models list is reused.
+const claudeInfoModelsTTL = time.Hour
+
+// claudeInfoModelSessions is how many recent sessions are scanned for models.
+const claudeInfoModelSessions = 20
+
+var (
+	claudeInfoRig     string
+	claudeInfoRefresh bool
+	claudeInfoJSON    bool
+)
+
+var claudeCmd = &cobra.Command{
+	Use:     "claude",
+	GroupID: GroupDiag,
+	Short:   "Inspect the installed Claude Code",
+	RunE:    requireSubcommand,
+}
+
+var claudeInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show installed Claude Code capabilities and check rig requirements",
+	Long: `Probe the installed Claude Code and report:
+  - its version
+  - the models recent sessions used, and any model set in settings
+  - configured MCP servers (user, local, and project scope)
+  - the permission mode each agent directory runs in
+
+Gas Town agents are launched with their rig's agent args, so a
+--dangerously-skip-permissions or --permission-mode flag there decides
+the effective mode, ahead of settings files.
+
+Rigs can declare requirements in <rig>/settings/config.json:
+
+  "claude": {
+    "min_version": "2.0.0",
+    "mcp_servers": ["github"],
+    "permission_mode": "bypassPermissions"
+  }
+
+Unmet requirements are listed as warnings and the command exits 1.
+
+The version and model list are cached in .runtime/claude-info.json; the
+version is re-read when the claude binary changes. Use --refresh to probe
+everything again.
+
+Examples:
+  gt claude info
+  gt claude info --rig gastown
+  gt claude info --json --refresh`,
+	Args: cobra.NoArgs,
+	RunE: runClaudeInfo,
+}
+
+func init() {
+	claudeInfoCmd.Flags().StringVar(&claudeInfoRig, "rig", "", "Only show this rig's agent directories and requirements")
+	claudeInfoCmd.Flags().BoolVar(&claudeInfoRefresh, "refresh", false, "Ignore the cache and probe again")
+	claudeInfoCmd.Flags().BoolVar(&claudeInfoJSON, "json", false, "Output as JSON")
+
+	claudeCmd.AddCommand(claudeInfoCmd)
+	rootCmd.AddCommand(claudeCmd)
+}
+
+// claudeInfoCache is the cached part of a probe.
+type claudeInfoCache struct {
+	Binary       *claude.BinaryInfo `json:"binary"`
+	RecentModels []string           `json:"recent_models"`
+	ModelsAt     time.Time          `json:"models_at"`
+}
+
+// claudeProject is an agent directory and the configuration it runs with.
+type claudeProject struct {
+	Rig  string `json:"rig,omitempty"`
+	Role string `json:"role"`
+	claude.ProjectInfo
+}
+
+// claudeRequirementIssue is an unmet rig requirement.
+type claudeRequirementIssue struct {
+	Rig         string `json:"rig"`
+	Requirement string `json:"requirement"`
+	Detail      string `json:"detail"`
+}
+
+// claudeInfoReport is the output of gt claude info.
+type claudeInfoReport struct {
+	Binary         *claude.BinaryInfo       `json:"binary"`
+	RecentModels   []string                 `json:"recent_models"`
+	UserMCPServers []claude.MCPServer       `json:"user_mcp_servers"`
+	Projects       []claudeProject          `json:"projects"`
+	Issues         []claudeRequirementIssue `json:"issues"`
+}
+
+func runClaudeInfo(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	cache, err := probeClaudeCached(townRoot, claudeInfoRefresh, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	var rigs []string
+	for name := range rigsConfig.Rigs {
+		if claudeInfoRig == "" || name == claudeInfoRig {
+			rigs = append(rigs, name)
+		}
+	}
+	if claudeInfoRig != "" && len(rigs) == 0 {
+		return fmt.Errorf("rig '%s' not found", claudeInfoRig)
+	}
+	sort.Strings(rigs)
+
+	report := claudeInfoReport{
+		Binary:         cache.Binary,
+		RecentModels:   cache.RecentModels,
+		UserMCPServers: claude.UserMCPServers(),
+		Projects:       claudeAgentProjects(townRoot, rigs, claudeInfoRig == ""),
+	}
+	for _, rigName := range rigs {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName)))
+		if err != nil || settings.Claude == nil {
+			continue
+		}
+		report.Issues = append(report.Issues,
+			checkClaudeRequirements(rigName, settings.Claude, report.Binary.Version, report.UserMCPServers, report.Projects)...)
+	}
+
+	if claudeInfoJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printClaudeInfo(report)
+	}
+	if len(report.Issues) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// probeClaudeCached returns the binary version and recent models, reusing
+// the cache while the binary is unchanged and the models are fresh.
+func probeClaudeCached(townRoot string, refresh bool, now time.Time) (*claudeInfoCache, error) {
+	path := filepath.Join(townRoot, claudeInfoCacheFile)
+	var cached claudeInfoCache
+	if !refresh {
+		if data, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: path is constructed internally
+			_ = json.Unmarshal(data, &cached)
+		}
+	}
+
+	current, err := claude.StatBinary("claude")
+	if err != nil {
+		return nil, err
+	}
+	changed := false
+	binaryStale := !current.SameBinary(cached.Binary) || cached.Binary.Version == ""
+	modelsStale := now.Sub(cached.ModelsAt) > claudeInfoModelsTTL
+	perf.Hit(!binaryStale)
+	perf.Hit(!modelsStale)
+	if binaryStale {
+		if cached.Binary, err = claude.ProbeBinary("claude"); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if modelsStale {
+		cached.RecentModels = claude.RecentModels(claudeInfoModelSessions)
+		cached.ModelsAt = now
+		changed = true
+	}
+
+	if changed {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			_ = util.AtomicWriteJSON(path, cached)
+		}
+	}
+	return &cached, nil
+}
+
+// claudeAgentProjects probes each existing agent directory: mayor/ and
+// deacon/ (when includeTown), then each rig's witness/, refinery/, crew/,
+// and polecats/.
+func claudeAgentProjects(townRoot string, rigs []string, includeTown bool) []claudeProject {
+	var projects []claudeProject
+	add := func(rigName, role, dir, launchMode string) {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return
+		}
+		p := claudeProject{Rig: rigName, Role: role, ProjectInfo: claude.ProbeProject(dir)}
+		if launchMode != "" {
+			p.PermissionMode = launchMode
+			p.PermissionSource = "agent launch args"
+		}
+		projects = append(projects, p)
+	}
+
+	if includeTown {
+		mode := launchPermissionMode(config.ResolveAgentConfig(townRoot, filepath.Join(townRoot, "mayor")).Args)
+		add("", "mayor", filepath.Join(townRoot, "mayor"), mode)
+		add("", "deacon", filepath.Join(townRoot, "deacon"), mode)
+	}
+	for _, rigName := range rigs {
+		rigPath := filepath.Join(townRoot, rigName)
+		mode := launchPermissionMode(config.ResolveAgentConfig(townRoot, rigPath).Args)
+		for _, role := range []string{"witness", "refinery", "crew", "polecats"} {
+			add(rigName, role, filepath.Join(rigPath, role), mode)
+		}
+	}
+	return projects
+}
+
+// launchPermissionMode returns the permission mode set by agent launch
+// args, or "" if the args don't set one.
+func launchPermissionMode(args []string) string {
+	for i, arg := range args {
+		switch {
+		case arg == "--dangerously-skip-permissions":
+			return claude.PermissionBypass
+		case arg == "--permission-mode" && i+1 < len(args):
+			return args[i+1]
+		case strings.HasPrefix(arg, "--permission-mode="):
+			return strings.TrimPrefix(arg, "--permission-mode=")
+		}
+	}
+	return ""
+}
+
+// checkClaudeRequirements checks one rig's requirements against the probe.
+func checkClaudeRequirements(rigName string, req *config.ClaudeRequirements, version string, userServers []claude.MCPServer, projects []claudeProject) []claudeRequirementIssue {
+	var issues []claudeRequirementIssue
+	issue := func(requirement, format string, a ...interface{}) {
+		issues = append(issues, claudeRequirementIssue{Rig: rigName, Requirement: requirement, Detail: fmt.Sprintf(format, a...)})
+	}
+
+	if req.MinVersion != "" {
+		if cmp, ok := claude.CompareVersions(version, req.MinVersion); !ok {
+			issue("min_version", "can't compare installed version %q with %q", version, req.MinVersion)
+		} else if cmp < 0 {
+			issue("min_version", "installed %s is older than required %s", version, req.MinVersion)
+		}
+	}
+
+	userHas := make(map[string]bool)
+	for _, s := range userServers {
+		userHas[s.Name] = true
+	}
+	for _, p := range projects {
+		if p.Rig != rigName {
+			continue
+		}
+		for _, name := range req.MCPServers {
+			if userHas[name] || projectHasMCPServer(p.ProjectInfo, name) {
+				continue
+			}
+			issue("mcp_servers", "%s/%s: MCP server %q not configured", rigName, p.Role, name)
+		}
+		if req.PermissionMode != "" && p.PermissionMode != req.PermissionMode {
+			issue("permission_mode", "%s/%s runs in %s (from %s), rig requires %s",
+				rigName, p.Role, p.PermissionMode, p.PermissionSource, req.PermissionMode)
+		}
+	}
+	return issues
+}
+
+// projectHasMCPServer returns true if a project has an MCP server by name.
+func projectHasMCPServer(p claude.ProjectInfo, name string) bool {
+	for _, s := range p.MCPServers {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// printClaudeInfo prints a probe report for humans.
+func printClaudeInfo(report claudeInfoReport) {
+	fmt.Printf("%s %s\n", style.Bold.Render("Claude Code"), report.Binary.Version)
+	fmt.Printf("  %s\n\n", style.Dim.Render(report.Binary.Path))
+
+	fmt.Println(style.Bold.Render("Models"))
+	if len(report.RecentModels) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("no recent sessions"))
+	} else {
+		fmt.Printf("  Recently used: %s\n", strings.Join(report.RecentModels, ", "))
+	}
+	configured := make(map[string]bool)
+	for _, p := range report.Projects {
+		if p.Model != "" && !configured[p.Model] {
+			configured[p.Model] = true
+			fmt.Printf("  Configured: %s %s\n", p.Model, style.Dim.Render("("+p.Dir+")"))
+		}
+	}
+	fmt.Println()
+
+	fmt.Println(style.Bold.Render("MCP servers"))
+	if len(report.UserMCPServers) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("none at user scope"))
+	}
+	for _, s := range report.UserMCPServers {
+		fmt.Printf("  %s  %s\n", s.Name, style.Dim.Render(s.Scope+", "+s.Type))
+	}
+	fmt.Println()
+
+	fmt.Println(style.Bold.Render("Agent directories"))
+	for _, p := range report.Projects {
+		label := p.Role
+		if p.Rig != "" {
+			label = p.Rig + "/" + p.Role
+		}
+		fmt.Printf("  %-22s %-18s %s\n", label, p.PermissionMode, style.Dim.Render(p.PermissionSource))
+		for _, s := range p.MCPServers {
+			fmt.Printf("  %-22s   mcp: %s %s\n", "", s.Name, style.Dim.Render("("+s.Scope+")"))
+		}
+	}
+
+	if len(report.Issues) > 0 {
+		fmt.Println()
+		fmt.Println(style.Bold.Render("Rig requirements"))
+		for _, issue := range report.Issues {
+			fmt.Printf("  %s %s: %s\n", style.WarningPrefix, issue.Requirement, issue.Detail)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestLaunchPermissionMode(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--dangerously-skip-permissions"}, claude.PermissionBypass},
+		{[]string{"--permission-mode", "plan"}, "plan"},
+		{[]string{"--permission-mode=acceptEdits"}, "acceptEdits"},
+		{[]string{"--model", "opus"}, ""},
+	}
+	for _, tt := range tests {
+		if got := launchPermissionMode(tt.args); got != tt.want {
+			t.Errorf("launchPermissionMode(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestCheckClaudeRequirements(t *testing.T) {
+	req := &config.ClaudeRequirements{
+		MinVersion:     "2.1.0",
+		MCPServers:     []string{"github", "db"},
+		PermissionMode: claude.PermissionBypass,
+	}
+	projects := []claudeProject{
+		{Rig: "gastown", Role: "crew", ProjectInfo: claude.ProjectInfo{
+			PermissionMode: claude.PermissionBypass,
+			MCPServers:     []claude.MCPServer{{Name: "db"}},
+		}},
+		{Rig: "gastown", Role: "refinery", ProjectInfo: claude.ProjectInfo{PermissionMode: "default", PermissionSource: "default"}},
+		{Rig: "beads", Role: "crew", ProjectInfo: claude.ProjectInfo{PermissionMode: "plan"}},
+	}
+	user := []claude.MCPServer{{Name: "github"}}
+
+	issues := checkClaudeRequirements("gastown", req, "2.0.14 (Claude Code)", user, projects)
+	want := map[string]int{"min_version": 1, "mcp_servers": 1, "permission_mode": 1}
+	got := make(map[string]int)
+	for _, issue := range issues {
+		got[issue.Requirement]++
+	}
+	for k, n := range want {
+		if got[k] != n {
+			t.Errorf("%s issues = %d, want %d (all: %+v)", k, got[k], n, issues)
+		}
+	}
+
+	if issues := checkClaudeRequirements("gastown", &config.ClaudeRequirements{MinVersion: "2.0.0"}, "2.0.14", nil, projects); len(issues) != 0 {
+		t.Errorf("met requirements reported issues: %+v", issues)
+	}
+}
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	claudePermissionsRig  string
+	claudePermissionsAll  bool
+	claudePermissionsJSON bool
+)
+
+var claudePermissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Audit Claude Code permission settings",
+	RunE:  requireSubcommand,
+}
+
+var claudePermissionsAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Find agent directories that auto-approve dangerous tools",
+	Long: `Audit the Claude Code permission settings of every agent directory.
+
+For each directory, reports the effective permission mode (agent launch
+args first, then .claude/settings.local.json, .claude/settings.json, and
+~/.claude/settings.json) and the settings entries that let agents act
+without asking: defaultMode bypassPermissions, and permissions.allow
+rules such as unrestricted Bash, Bash(rm:*), or Bash(git push:*).
+
+Worker directories (crew/<name>, polecats/<name>, witness/rig, ...) are
+included when they carry their own settings files, which is where drift
+usually hides.
+
+Rigs set their policy in <rig>/settings/config.json:
+
+  "claude": {"permission_mode": "acceptEdits"}
+
+A directory drifts when its effective mode differs from the policy, or
+when the policy is stricter than bypassPermissions and a settings file
+still auto-approves something dangerous. Drift exits 1.
+
+Examples:
+  gt claude permissions audit
+  gt claude permissions audit --rig gastown
+  gt claude permissions audit --all --json   # Every project Claude Code knows`,
+	Args: cobra.NoArgs,
+	RunE: runClaudePermissionsAudit,
+}
+
+func init() {
+	claudePermissionsAuditCmd.Flags().StringVar(&claudePermissionsRig, "rig", "", "Only audit this rig")
+	claudePermissionsAuditCmd.Flags().BoolVar(&claudePermissionsAll, "all", false, "Also audit projects outside the town listed in ~/.claude.json")
+	claudePermissionsAuditCmd.Flags().BoolVar(&claudePermissionsJSON, "json", false, "Output as JSON")
+
+	claudePermissionsCmd.AddCommand(claudePermissionsAuditCmd)
+	claudeCmd.AddCommand(claudePermissionsCmd)
+}
+
+// permissionAudit is one audited directory.
+type permissionAudit struct {
+	Rig        string                     `json:"rig,omitempty"`
+	Role       string                     `json:"role,omitempty"` // empty outside the town
+	Dir        string                     `json:"dir"`
+	Mode       string                     `json:"mode"`
+	ModeSource string                     `json:"mode_source"`
+	Policy     string                     `json:"policy,omitempty"` // the rig's required mode
+	Findings   []claude.PermissionFinding `json:"findings"`
+	Drift      []string                   `json:"drift,omitempty"`
+}
+
+// permissionAuditDir is a directory to audit and the agent it belongs to.
+type permissionAuditDir struct {
+	rig, role, dir string
+}
+
+func runClaudePermissionsAudit(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	var rigs []string
+	for name := range rigsConfig.Rigs {
+		if claudePermissionsRig == "" || name == claudePermissionsRig {
+			rigs = append(rigs, name)
+		}
+	}
+	if claudePermissionsRig != "" && len(rigs) == 0 {
+		return fmt.Errorf("rig '%s' not found", claudePermissionsRig)
+	}
+	sort.Strings(rigs)
+
+	var audits []permissionAudit
+	seen := make(map[string]bool)
+	audit := func(d permissionAuditDir, launchMode, policy string) {
+		seen[d.dir] = true
+		a := auditPermissionDir(d, launchMode, policy)
+		audits = append(audits, a)
+	}
+
+	if claudePermissionsRig == "" {
+		mode := launchPermissionMode(config.ResolveAgentConfig(townRoot, filepath.Join(townRoot, "mayor")).Args)
+		for _, d := range permissionAuditDirs(townRoot, "") {
+			audit(d, mode, "")
+		}
+	}
+	for _, rigName := range rigs {
+		rigPath := filepath.Join(townRoot, rigName)
+		mode := launchPermissionMode(config.ResolveAgentConfig(townRoot, rigPath).Args)
+		policy := ""
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil && settings.Claude != nil {
+			policy = settings.Claude.PermissionMode
+		}
+		for _, d := range permissionAuditDirs(townRoot, rigName) {
+			audit(d, mode, policy)
+		}
+	}
+	if claudePermissionsAll {
+		for _, dir := range claude.KnownProjects() {
+			if !seen[dir] {
+				audit(permissionAuditDir{dir: dir}, "", "")
+			}
+		}
+	}
+
+	drifted := 0
+	for _, a := range audits {
+		if len(a.Drift) > 0 {
+			drifted++
+		}
+	}
+
+	if claudePermissionsJSON {
+		if audits == nil {
+			audits = []permissionAudit{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(audits); err != nil {
+			return err
+		}
+	} else {
+		printPermissionAudit(audits, drifted)
+	}
+	if drifted > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// permissionAuditDirs lists the directories to audit for a rig, or for
+// the town-level agents when rigName is empty. Role directories are always
+// listed; worker directories only when they have their own settings.
+func permissionAuditDirs(townRoot, rigName string) []permissionAuditDir {
+	var dirs []permissionAuditDir
+	isDir := func(path string) bool {
+		info, err := os.Stat(path)
+		return err == nil && info.IsDir()
+	}
+	addRole := func(role, dir string) {
+		if isDir(dir) {
+			dirs = append(dirs, permissionAuditDir{rig: rigName, role: role, dir: dir})
+		}
+	}
+	addWorker := func(role, dir string) {
+		if isDir(dir) && len(claude.ProjectSettingsFiles(dir)) > 0 {
+			dirs = append(dirs, permissionAuditDir{rig: rigName, role: role, dir: dir})
+		}
+	}
+
+	if rigName == "" {
+		addRole("mayor", filepath.Join(townRoot, "mayor"))
+		addRole("deacon", filepath.Join(townRoot, "deacon"))
+		return dirs
+	}
+
+	rigPath := filepath.Join(townRoot, rigName)
+	for _, role := range []string{"witness", "refinery"} {
+		addRole(role, filepath.Join(rigPath, role))
+		addWorker(role+"/rig", filepath.Join(rigPath, role, "rig"))
+	}
+	for _, role := range []string{"crew", "polecats"} {
+		roleDir := filepath.Join(rigPath, role)
+		addRole(role, roleDir)
+		entries, err := os.ReadDir(roleDir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() || e.Name()[0] == '.' {
+				continue
+			}
+			addWorker(role+"/"+e.Name(), filepath.Join(roleDir, e.Name()))
+			if role == "polecats" {
+				addWorker(role+"/"+e.Name()+"/"+rigName, filepath.Join(roleDir, e.Name(), rigName))
+			}
+		}
+	}
+	return dirs
+}
+
+// auditPermissionDir audits one directory against its rig's policy.
+// Launch args, when they set a mode, override the settings files.
+func auditPermissionDir(d permissionAuditDir, launchMode, policy string) permissionAudit {
+	probe := claude.ProbeProject(d.dir)
+	a := permissionAudit{
+		Rig:        d.rig,
+		Role:       d.role,
+		Dir:        d.dir,
+		Mode:       probe.PermissionMode,
+		ModeSource: probe.PermissionSource,
+		Policy:     policy,
+		Findings:   claude.AuditPermissions(d.dir),
+	}
+	if launchMode != "" {
+		a.Mode, a.ModeSource = launchMode, "agent launch args"
+	}
+	if a.Findings == nil {
+		a.Findings = []claude.PermissionFinding{}
+	}
+
+	if policy == "" {
+		return a
+	}
+	if a.Mode != policy {
+		a.Drift = append(a.Drift, fmt.Sprintf("runs in %s (from %s), rig policy requires %s", a.Mode, a.ModeSource, policy))
+	}
+	if policy != claude.PermissionBypass {
+		for _, f := range a.Findings {
+			a.Drift = append(a.Drift, fmt.Sprintf("%s %q in %s %s", f.Setting, f.Value, f.File, f.Reason))
+		}
+	}
+	return a
+}
+
+// printPermissionAudit prints audit results grouped by rig.
+func printPermissionAudit(audits []permissionAudit, drifted int) {
+	fmt.Printf("%s %s\n", style.Bold.Render("Permission audit"), style.Dim.Render(fmt.Sprintf("(%d directories)", len(audits))))
+
+	group := "-"
+	for _, a := range audits {
+		label := a.Rig
+		switch {
+		case a.Role == "":
+			label = "other projects"
+		case a.Rig == "":
+			label = "town"
+		}
+		if label != group {
+			group = label
+			header := style.Bold.Render(label)
+			if a.Policy != "" {
+				header += " " + style.Dim.Render("(policy: "+a.Policy+")")
+			}
+			fmt.Printf("\n%s\n", header)
+		}
+
+		name := a.Role
+		if name == "" {
+			name = a.Dir
+		}
+		mark := style.SuccessPrefix
+		switch {
+		case len(a.Drift) > 0:
+			mark = style.ErrorPrefix
+		case len(a.Findings) > 0 || a.Mode == claude.PermissionBypass:
+			mark = style.WarningPrefix
+		}
+		fmt.Printf("  %s %-24s %-18s %s\n", mark, name, a.Mode, style.Dim.Render(a.ModeSource))
+		for _, drift := range a.Drift {
+			fmt.Printf("      drift: %s\n", drift)
+		}
+		if len(a.Drift) == 0 || a.Policy == claude.PermissionBypass {
+			for _, f := range a.Findings {
+				fmt.Printf("      %s %q: %s %s\n", f.Setting, f.Value, f.Reason, style.Dim.Render("("+f.File+")"))
+			}
+		}
+	}
+
+	fmt.Println()
+	if drifted > 0 {
+		fmt.Printf("%s %d directories drift from their rig's policy\n", style.ErrorPrefix, drifted)
+	} else {
+		fmt.Printf("%s No drift from rig policy\n", style.SuccessPrefix)
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+func TestAuditPermissionDir(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".claude"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"permissions":{"defaultMode":"acceptEdits","allow":["Bash(rm:*)"]}}`
+	if err := os.WriteFile(filepath.Join(dir, ".claude", "settings.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d := permissionAuditDir{rig: "gastown", role: "crew/joe", dir: dir}
+
+	// Settings mode matches the policy, but a dangerous allow rule drifts
+	a := auditPermissionDir(d, "", claude.PermissionAcceptEdits)
+	if a.Mode != claude.PermissionAcceptEdits || len(a.Findings) != 1 || len(a.Drift) != 1 {
+		t.Errorf("acceptEdits policy: %+v", a)
+	}
+
+	// Launch args override the settings file
+	a = auditPermissionDir(d, claude.PermissionBypass, claude.PermissionAcceptEdits)
+	if a.Mode != claude.PermissionBypass || a.ModeSource != "agent launch args" || len(a.Drift) != 2 {
+		t.Errorf("bypass launch: %+v", a)
+	}
+
+	// A bypass policy tolerates allow rules
+	a = auditPermissionDir(d, claude.PermissionBypass, claude.PermissionBypass)
+	if len(a.Drift) != 0 || len(a.Findings) != 1 {
+		t.Errorf("bypass policy: %+v", a)
+	}
+
+	// No policy: findings are reported, never drift
+	a = auditPermissionDir(d, "", "")
+	if len(a.Drift) != 0 || len(a.Findings) != 1 {
+		t.Errorf("no policy: %+v", a)
+	}
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cleanup"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	cleanRig    string
+	cleanAll    bool
+	cleanQuota  string
+	cleanDryRun bool
+	cleanForce  bool
+	cleanJSON   bool
+)
+
+// defaultCleanQuota applies when a rig sets no clean.quota.
+const defaultCleanQuota = "2GB"
+
+var cleanCmd = &cobra.Command{
+	Use:     "clean",
+	GroupID: GroupWorkspace,
+	Short:   "Reclaim disk from agent worktrees over their size quota",
+	Long: `Measure every agent worktree in a rig (crew, polecats, refinery, and
+the mayor's clone) and, in those over the rig's size quota, remove what
+the project's tooling can regenerate:
+
+  dependencies   node_modules, .venv
+  build output   target, dist, build, .next
+  stale caches   __pycache__, .pytest_cache, .mypy_cache, .ruff_cache,
+                 .turbo, .gradle, .cache (untouched for the cache age)
+
+Inside a git repository a directory is only removed when .gitignore
+excludes it, so tracked directories with these names are left alone.
+Worktrees whose agent session is running are skipped unless --force.
+
+Quotas are set per rig in <rig>/settings/config.json:
+
+  "clean": {"quota": "5GB", "cache_age": "7d", "build": ["coverage"]}
+
+The default quota is 2GB and the default cache age 7d.
+
+Examples:
+  gt clean --rig gastown --dry-run   # Report what would be removed
+  gt clean --rig gastown
+  gt clean --all --quota 1GB
+  gt clean --rig gastown --json`,
+	Args: cobra.NoArgs,
+	RunE: runClean,
+}
+
+func init() {
+	cleanCmd.Flags().StringVar(&cleanRig, "rig", "", "Rig to clean")
+	cleanCmd.Flags().BoolVar(&cleanAll, "all", false, "Clean every rig")
+	cleanCmd.Flags().StringVar(&cleanQuota, "quota", "", "Override the rig's worktree quota (e.g., 1GB)")
+	cleanCmd.Flags().BoolVarP(&cleanDryRun, "dry-run", "n", false, "Report what would be removed without removing it")
+	cleanCmd.Flags().BoolVarP(&cleanForce, "force", "f", false, "Also clean worktrees whose agent session is running")
+	cleanCmd.Flags().BoolVar(&cleanJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(cleanCmd)
+}
+
+// cleanWorktree is one measured agent worktree.
+type cleanWorktree struct {
+	Rig       string             `json:"rig"`
+	Agent     string             `json:"agent"` // e.g. "polecats/toast"
+	Dir       string             `json:"dir"`
+	Size      int64              `json:"size"`
+	Quota     int64              `json:"quota"`
+	OverQuota bool               `json:"over_quota"`
+	Running   bool               `json:"running,omitempty"` // agent session is up
+	Artifacts []cleanup.Artifact `json:"artifacts"`
+	Removed   []cleanup.Artifact `json:"removed,omitempty"`
+	Freed     int64              `json:"freed,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// cleanTarget is an agent worktree and the tmux session that uses it.
+type cleanTarget struct {
+	agent, dir, session string
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	if cleanRig == "" && !cleanAll {
+		return fmt.Errorf("specify --rig <name> or --all")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	var rigs []string
+	for name := range rigsConfig.Rigs {
+		if cleanAll || name == cleanRig {
+			rigs = append(rigs, name)
+		}
+	}
+	if len(rigs) == 0 && !cleanAll {
+		return fmt.Errorf("rig '%s' not found", cleanRig)
+	}
+	sort.Strings(rigs)
+
+	t := tmux.NewTmux()
+	var results []cleanWorktree
+	for _, rigName := range rigs {
+		rigPath := filepath.Join(townRoot, rigName)
+		quota, opts, err := cleanSettings(rigPath)
+		if err != nil {
+			return fmt.Errorf("rig %s: %w", rigName, err)
+		}
+
+		for _, target := range cleanTargets(rigPath, rigName) {
+			w := cleanWorktree{Rig: rigName, Agent: target.agent, Dir: target.dir, Quota: quota, Artifacts: []cleanup.Artifact{}}
+			if target.session != "" {
+				w.Running, _ = t.HasSession(target.session)
+			}
+			report, err := cleanup.Scan(target.dir, opts)
+			if err != nil {
+				w.Error = err.Error()
+				results = append(results, w)
+				continue
+			}
+			w.Size, w.Artifacts = report.Size, report.Artifacts
+			w.OverQuota = report.Size > quota
+
+			if w.OverQuota && (!w.Running || cleanForce) {
+				w.Removed = report.Removable()
+				if cleanDryRun {
+					w.Freed = report.RemovableSize()
+				} else if w.Freed, err = cleanup.Remove(w.Removed); err != nil {
+					w.Error = err.Error()
+				}
+			}
+			results = append(results, w)
+		}
+	}
+
+	if cleanJSON {
+		if results == nil {
+			results = []cleanWorktree{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	printClean(results)
+	return nil
+}
+
+// cleanSettings returns a rig's worktree quota and scan options, from its
+// settings with --quota taking precedence.
+func cleanSettings(rigPath string) (int64, cleanup.Options, error) {
+	var opts cleanup.Options
+	cfg := &config.CleanConfig{}
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil && settings.Clean != nil {
+		cfg = settings.Clean
+	}
+
+	quotaStr := cfg.Quota
+	if cleanQuota != "" {
+		quotaStr = cleanQuota
+	}
+	if quotaStr == "" {
+		quotaStr = defaultCleanQuota
+	}
+	quota, err := cleanup.ParseSize(quotaStr)
+	if err != nil {
+		return 0, opts, err
+	}
+
+	if cfg.CacheAge != "" {
+		if opts.CacheAge, err = parseDuration(cfg.CacheAge); err != nil {
+			return 0, opts, fmt.Errorf("invalid clean.cache_age: %w", err)
+		}
+	}
+	if len(cfg.Build) > 0 {
+		opts.Patterns = make(map[string]cleanup.Kind, len(cleanup.DefaultPatterns)+len(cfg.Build))
+		for name, kind := range cleanup.DefaultPatterns {
+			opts.Patterns[name] = kind
+		}
+		for _, name := range cfg.Build {
+			opts.Patterns[name] = cleanup.KindBuild
+		}
+	}
+	return quota, opts, nil
+}
+
+// cleanTargets lists a rig's agent worktrees that exist on disk.
+func cleanTargets(rigPath, rigName string) []cleanTarget {
+	var targets []cleanTarget
+	add := func(agent, dir, sess string) {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			targets = append(targets, cleanTarget{agent: agent, dir: dir, session: sess})
+		}
+	}
+
+	add("mayor/rig", filepath.Join(rigPath, "mayor", "rig"), "")
+	add("refinery/rig", filepath.Join(rigPath, "refinery", "rig"), session.RefinerySessionName(rigName))
+	for _, role := range []string{"crew", "polecats"} {
+		entries, err := os.ReadDir(filepath.Join(rigPath, role))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() || e.Name()[0] == '.' {
+				continue
+			}
+			sess := session.CrewSessionName(rigName, e.Name())
+			if role == "polecats" {
+				sess = session.PolecatSessionName(rigName, e.Name())
+			}
+			add(role+"/"+e.Name(), filepath.Join(rigPath, role, e.Name()), sess)
+		}
+	}
+	return targets
+}
+
+// printClean prints clean results grouped by rig.
+func printClean(results []cleanWorktree) {
+	verb := "Freed"
+	if cleanDryRun {
+		verb = "Would free"
+	}
+
+	rig := ""
+	var freed, total int64
+	cleaned := 0
+	for _, w := range results {
+		if w.Rig != rig {
+			rig = w.Rig
+			fmt.Printf("\n%s %s\n", style.Bold.Render(rig), style.Dim.Render("(quota "+cleanup.FormatSize(w.Quota)+" per worktree)"))
+		}
+		total += w.Size
+
+		mark := style.SuccessPrefix
+		note := ""
+		switch {
+		case w.Error != "":
+			mark, note = style.ErrorPrefix, w.Error
+		case w.OverQuota && w.Running && !cleanForce:
+			mark, note = style.WarningPrefix, "over quota, session running (use --force)"
+		case w.OverQuota && len(w.Removed) == 0:
+			mark, note = style.WarningPrefix, "over quota, nothing removable"
+		case w.OverQuota:
+			mark, note = style.WarningPrefix, fmt.Sprintf("over quota, %s %s", strings.ToLower(verb), cleanup.FormatSize(w.Freed))
+		}
+		fmt.Printf("  %s %-24s %10s  %s\n", mark, compactAddress(w.Agent), cleanup.FormatSize(w.Size), style.Dim.Render(note))
+
+		for _, a := range w.Removed {
+			kind := string(a.Kind)
+			if a.Stale {
+				kind += " (stale)"
+			}
+			fmt.Printf("      %-32s %-20s %10s\n", a.RelPath, kind, cleanup.FormatSize(a.Size))
+		}
+		if w.Freed > 0 {
+			freed += w.Freed
+			cleaned++
+		}
+	}
+
+	fmt.Println()
+	if cleaned == 0 {
+		fmt.Printf("%s %d worktree(s), %s; nothing to clean\n", style.SuccessPrefix, len(results), cleanup.FormatSize(total))
+		return
+	}
+	fmt.Printf("%s %s %s from %d of %d worktree(s)\n", style.SuccessPrefix, verb, cleanup.FormatSize(freed), cleaned, len(results))
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/cleanup"
+)
+
+func TestCleanTargets(t *testing.T) {
+	rigPath := filepath.Join(t.TempDir(), "gastown")
+	for _, dir := range []string{"mayor/rig", "refinery/rig", "crew/joe", "polecats/toast", "polecats/.pending", "witness"} {
+		if err := os.MkdirAll(filepath.Join(rigPath, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var agents []string
+	for _, target := range cleanTargets(rigPath, "gastown") {
+		agents = append(agents, target.agent)
+		if target.agent == "mayor/rig" && target.session != "" {
+			t.Errorf("mayor clone has a session: %q", target.session)
+		}
+		if target.agent == "polecats/toast" && target.session == "" {
+			t.Error("polecat has no session name")
+		}
+	}
+	want := []string{"mayor/rig", "refinery/rig", "crew/joe", "polecats/toast"}
+	if len(agents) != len(want) {
+		t.Fatalf("agents = %v, want %v", agents, want)
+	}
+	for i := range want {
+		if agents[i] != want[i] {
+			t.Errorf("agents = %v, want %v", agents, want)
+			break
+		}
+	}
+}
+
+func TestCleanSettings(t *testing.T) {
+	rigPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"type":"rig-settings","version":1,"clean":{"quota":"5GB","cache_age":"2d","build":["coverage"]}}`
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	quota, opts, err := cleanSettings(rigPath)
+	if err != nil {
+		t.Fatalf("cleanSettings: %v", err)
+	}
+	if quota != 5<<30 || opts.CacheAge != 48*time.Hour {
+		t.Errorf("quota = %d, cache age = %v", quota, opts.CacheAge)
+	}
+	if opts.Patterns["coverage"] != cleanup.KindBuild || opts.Patterns["node_modules"] != cleanup.KindDependencies {
+		t.Errorf("patterns = %v", opts.Patterns)
+	}
+
+	cleanQuota = "1GB"
+	defer func() { cleanQuota = "" }()
+	if quota, _, _ := cleanSettings(t.TempDir()); quota != 1<<30 {
+		t.Errorf("--quota not applied: %d", quota)
+	}
+}
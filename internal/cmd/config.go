@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -29,7 +30,8 @@ Commands:
   gt config agent get <name>         Show agent configuration
   gt config agent set <name> <cmd>   Set custom agent command
   gt config agent remove <name>      Remove custom agent
-  gt config default-agent [name]     Get or set default agent`,
+  gt config default-agent [name]     Get or set default agent
+  gt config rig-alias [rig] [alias]  List, show, or set rig aliases`,
 }
 
 // Agent subcommands
@@ -119,9 +121,52 @@ Examples:
 	RunE: runConfigDefaultAgent,
 }
 
+// Rig-alias subcommand
+
+var configRigAliasCmd = &cobra.Command{
+	Use:   "rig-alias [rig] [alias]",
+	Short: "List, show, or set rig aliases",
+	Long: `List, show, or set short aliases for rig names.
+
+Aliases are accepted anywhere --rig is, and tables display the compact
+form so long rig names don't blow out column widths.
+
+With no arguments, lists all aliases.
+With one argument, shows the alias for that rig.
+With two arguments, sets the alias for the rig.
+
+Examples:
+  gt config rig-alias                    # List aliases
+  gt config rig-alias gastown gt         # gastown can now be written as gt
+  gt config rig-alias gastown --remove   # Remove the alias`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runConfigRigAlias,
+}
+
+// Assign-guard subcommand
+
+var configAssignGuardCmd = &cobra.Command{
+	Use:   "assign-guard [block|warn|off]",
+	Short: "Get or set the duplicate assignment guard",
+	Long: `Get or set what gt sling does when an issue already has an active assignee.
+
+Modes:
+  block   Refuse to sling unless --force is given (default)
+  warn    Print a warning pointing at the existing session, then proceed
+  off     Skip the check
+
+Examples:
+  gt config assign-guard          # Show current mode
+  gt config assign-guard warn     # Warn instead of blocking`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{config.AssignGuardBlock, config.AssignGuardWarn, config.AssignGuardOff},
+	RunE:      runConfigAssignGuard,
+}
+
 // Flags
 var (
-	configAgentListJSON bool
+	configAgentListJSON  bool
+	configRigAliasRemove bool
 )
 
 // AgentListItem represents an agent in list output.
@@ -444,9 +489,120 @@ func runConfigDefaultAgent(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runConfigRigAlias(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+
+	settingsPath := config.TownSettingsPath(townRoot)
+	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+
+	if len(args) == 0 {
+		if len(townSettings.RigAliases) == 0 {
+			fmt.Println("No rig aliases configured.")
+			return nil
+		}
+		rigs := make([]string, 0, len(townSettings.RigAliases))
+		for rig := range townSettings.RigAliases {
+			rigs = append(rigs, rig)
+		}
+		sort.Strings(rigs)
+		fmt.Printf("%s\n\n", style.Bold.Render("Rig Aliases"))
+		for _, rig := range rigs {
+			fmt.Printf("  %s = %s\n", style.Bold.Render(rig), townSettings.RigAliases[rig])
+		}
+		return nil
+	}
+
+	rig := townSettings.ResolveRigAlias(args[0])
+
+	if configRigAliasRemove {
+		if _, ok := townSettings.RigAliases[rig]; !ok {
+			return fmt.Errorf("rig '%s' has no alias", rig)
+		}
+		delete(townSettings.RigAliases, rig)
+		if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+			return fmt.Errorf("saving town settings: %w", err)
+		}
+		fmt.Printf("Removed alias for rig '%s'\n", style.Bold.Render(rig))
+		return nil
+	}
+
+	if len(args) == 1 {
+		alias, ok := townSettings.RigAliases[rig]
+		if !ok {
+			fmt.Printf("Rig '%s' has no alias\n", rig)
+			return nil
+		}
+		fmt.Printf("%s = %s\n", style.Bold.Render(rig), alias)
+		return nil
+	}
+
+	alias := args[1]
+	var rigNames []string
+	if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		for name := range rigsConfig.Rigs {
+			rigNames = append(rigNames, name)
+		}
+	}
+	if err := townSettings.CheckRigAlias(rig, alias, rigNames); err != nil {
+		return err
+	}
+
+	if townSettings.RigAliases == nil {
+		townSettings.RigAliases = make(map[string]string)
+	}
+	townSettings.RigAliases[rig] = alias
+
+	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+
+	fmt.Printf("Rig '%s' aliased as '%s'\n", style.Bold.Render(rig), alias)
+	return nil
+}
+
+func runConfigAssignGuard(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+
+	settingsPath := config.TownSettingsPath(townRoot)
+	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+
+	if len(args) == 0 {
+		fmt.Printf("Assign guard: %s\n", style.Bold.Render(townSettings.AssignGuardMode()))
+		return nil
+	}
+
+	mode := args[0]
+	switch mode {
+	case config.AssignGuardBlock, config.AssignGuardWarn, config.AssignGuardOff:
+	default:
+		return fmt.Errorf("invalid mode '%s' (want block, warn, or off)", mode)
+	}
+
+	townSettings.AssignGuard = mode
+	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+
+	fmt.Printf("Assign guard set to '%s'\n", style.Bold.Render(mode))
+	return nil
+}
+
 func init() {
 	// Add flags
 	configAgentListCmd.Flags().BoolVar(&configAgentListJSON, "json", false, "Output as JSON")
+	configRigAliasCmd.Flags().BoolVar(&configRigAliasRemove, "remove", false, "Remove the rig's alias")
 
 	// Add agent subcommands
 	configAgentCmd := &cobra.Command{
@@ -462,6 +618,8 @@ func init() {
 	// Add subcommands to config
 	configCmd.AddCommand(configAgentCmd)
 	configCmd.AddCommand(configDefaultAgentCmd)
+	configCmd.AddCommand(configRigAliasCmd)
+	configCmd.AddCommand(configAssignGuardCmd)
 
 	// Register with root
 	rootCmd.AddCommand(configCmd)
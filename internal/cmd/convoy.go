@@ -17,6 +17,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/task"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -61,6 +62,7 @@ func looksLikeIssueID(s string) bool {
 // Convoy command flags
 var (
 	convoyMolecule     string
+	convoyTask         string
 	convoyNotify       string
 	convoyStatusJSON   bool
 	convoyListJSON     bool
@@ -122,7 +124,11 @@ Examples:
   gt convoy create "Deploy v2.0" gt-abc bd-xyz
   gt convoy create "Release prep" gt-abc --notify           # defaults to mayor/
   gt convoy create "Release prep" gt-abc --notify ops/      # notify ops/
-  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release`,
+  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release
+  gt convoy create "Auth rewrite" gt-a gt-b --task auth.task.yaml
+
+--task records a task spec (see 'gt task') describing what the convoy
+must deliver; it is validated first.`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runConvoyCreate,
 }
@@ -202,6 +208,7 @@ Examples:
 func init() {
 	// Create flags
 	convoyCreateCmd.Flags().StringVar(&convoyMolecule, "molecule", "", "Associated molecule ID")
+	convoyCreateCmd.Flags().StringVar(&convoyTask, "task", "", "Task spec (task.yaml) describing what the convoy delivers")
 	convoyCreateCmd.Flags().StringVar(&convoyNotify, "notify", "", "Address to notify on completion (default: mayor/ if flag used without value)")
 	convoyCreateCmd.Flags().Lookup("notify").NoOptDefVal = "mayor/"
 
@@ -269,6 +276,14 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMolecule != "" {
 		description += fmt.Sprintf("\nMolecule: %s", convoyMolecule)
 	}
+	var spec *task.Spec
+	var specPath string
+	if convoyTask != "" {
+		if spec, specPath, err = loadTaskRef(convoyTask); err != nil {
+			return err
+		}
+		description += fmt.Sprintf("\nTask: %s", specPath)
+	}
 
 	// Generate convoy ID with cv- prefix
 	convoyID := fmt.Sprintf("hq-cv-%s", generateShortID())
@@ -323,6 +338,9 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMolecule != "" {
 		fmt.Printf("  Molecule: %s\n", convoyMolecule)
 	}
+	if spec != nil {
+		fmt.Printf("  Task:     %s %s\n", spec.Headline(), style.Dim.Render("("+specPath+")"))
+	}
 
 	fmt.Printf("\n  %s\n", style.Dim.Render("Convoy auto-closes when all tracked issues complete"))
 
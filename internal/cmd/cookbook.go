@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var cookbookCmd = &cobra.Command{
+	Use:     "cookbook [topic]",
+	GroupID: GroupDiag,
+	Short:   "Task-oriented recipes for common Gas Town chores",
+	Long: `Show task-oriented recipes for common Gas Town chores.
+
+Each recipe is a short sequence of gt commands. Commands are checked
+against this build of gt, so flags that don't exist here are flagged
+rather than silently suggested. Examples are personalized with the rig
+you're standing in (or the first registered rig).
+
+Works offline - no network or beads access needed.
+
+Examples:
+  gt cookbook                # List recipe topics
+  gt cookbook crashed        # Resume a crashed polecat
+  gt cookbook cost           # Audit yesterday's cost`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCookbook,
+}
+
+func init() {
+	rootCmd.AddCommand(cookbookCmd)
+}
+
+// cookbookStep is a single command in a recipe.
+// Args are the command words and flags after "gt"; "{rig}" is replaced
+// with the detected rig name.
+type cookbookStep struct {
+	Note string
+	Args []string
+}
+
+// cookbookRecipe is a task-oriented sequence of commands.
+type cookbookRecipe struct {
+	Topic   string
+	Title   string
+	Summary string
+	Steps   []cookbookStep
+}
+
+// cookbookRecipes is the built-in recipe catalog.
+var cookbookRecipes = []cookbookRecipe{
+	{
+		Topic:   "crashed",
+		Title:   "Resume a crashed polecat",
+		Summary: "Find polecats whose sessions died, then restart them with their hooked work intact.",
+		Steps: []cookbookStep{
+			{Note: "Find polecats whose sessions are not running", Args: []string{"session", "check", "{rig}"}},
+			{Note: "See what the polecat was working on", Args: []string{"session", "status", "{rig}/<polecat>"}},
+			{Note: "Restart the session (hooked work survives)", Args: []string{"session", "restart", "{rig}/<polecat>"}},
+			{Note: "Ask the dead session where it left off", Args: []string{"seance", "--rig", "{rig}", "--role", "polecat"}},
+		},
+	},
+	{
+		Topic:   "cost",
+		Title:   "Audit yesterday's cost",
+		Summary: "Roll up yesterday's session costs and break them down by role and rig.",
+		Steps: []cookbookStep{
+			{Note: "Preview yesterday's digest", Args: []string{"costs", "digest", "--yesterday", "--dry-run"}},
+			{Note: "Breakdown by role", Args: []string{"costs", "--week", "--by-role"}},
+			{Note: "Breakdown by rig", Args: []string{"costs", "--week", "--by-rig"}},
+		},
+	},
+	{
+		Topic:   "vacation",
+		Title:   "Hand off before vacation",
+		Summary: "Capture your state into a pinned handoff so your successor (or future you) can pick up cleanly.",
+		Steps: []cookbookStep{
+			{Note: "Check for anything unread first", Args: []string{"mail", "inbox", "--unread"}},
+			{Note: "Preview the handoff", Args: []string{"handoff", "--collect", "--dry-run"}},
+			{Note: "Hand off with collected state", Args: []string{"handoff", "--collect", "-s", "Out until <date>"}},
+		},
+	},
+	{
+		Topic:   "predecessor",
+		Title:   "Ask a predecessor session a question",
+		Summary: "Find the session that did the work and ask it directly instead of reading logs.",
+		Steps: []cookbookStep{
+			{Note: "List recent sessions in the rig", Args: []string{"seance", "--rig", "{rig}", "--recent", "10"}},
+			{Note: "Ask a one-shot question", Args: []string{"seance", "--talk", "<session-id>", "-p", "Where did you leave off?"}},
+		},
+	},
+	{
+		Topic:   "health",
+		Title:   "Check town health",
+		Summary: "Run diagnostics and fix what can be fixed automatically.",
+		Steps: []cookbookStep{
+			{Note: "Run all checks for the rig", Args: []string{"doctor", "--rig", "{rig}"}},
+			{Note: "Apply automatic fixes", Args: []string{"doctor", "--fix"}},
+		},
+	},
+}
+
+func runCookbook(cmd *cobra.Command, args []string) error {
+	rigName := cookbookRig()
+
+	if len(args) == 0 {
+		fmt.Printf("%s\n\n", style.Bold.Render("Gas Town Cookbook"))
+		for _, r := range cookbookRecipes {
+			fmt.Printf("  %-12s %s\n", style.Bold.Render(r.Topic), r.Title)
+		}
+		fmt.Printf("\n%s\n", style.Dim.Render("Show a recipe: gt cookbook <topic>"))
+		return nil
+	}
+
+	recipe := findCookbookRecipe(args[0])
+	if recipe == nil {
+		return fmt.Errorf("no recipe matching %q (run 'gt cookbook' to list topics)", args[0])
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render(recipe.Title))
+	fmt.Printf("%s\n\n", style.Dim.Render(recipe.Summary))
+
+	for i, step := range recipe.Steps {
+		line := "gt " + strings.Join(quoteCookbookArgs(personalizeCookbookArgs(step.Args, rigName)), " ")
+		fmt.Printf("  %d. %s\n", i+1, step.Note)
+		if err := validateCookbookStep(rootCmd, step); err != nil {
+			fmt.Printf("     %s %s\n", style.Dim.Render(line), style.Warning.Render("("+err.Error()+")"))
+			continue
+		}
+		fmt.Printf("     %s\n", line)
+	}
+
+	return nil
+}
+
+// findCookbookRecipe matches a recipe by topic prefix or title substring.
+func findCookbookRecipe(query string) *cookbookRecipe {
+	q := strings.ToLower(query)
+	for i := range cookbookRecipes {
+		if strings.HasPrefix(cookbookRecipes[i].Topic, q) {
+			return &cookbookRecipes[i]
+		}
+	}
+	for i := range cookbookRecipes {
+		if strings.Contains(strings.ToLower(cookbookRecipes[i].Title), q) {
+			return &cookbookRecipes[i]
+		}
+	}
+	return nil
+}
+
+// validateCookbookStep checks that a step's command and flags exist in this build.
+func validateCookbookStep(root *cobra.Command, step cookbookStep) error {
+	var words []string
+	for _, a := range step.Args {
+		if strings.HasPrefix(a, "-") {
+			break
+		}
+		words = append(words, a)
+	}
+
+	target, _, err := root.Find(words)
+	if err != nil || target == root {
+		return fmt.Errorf("command not available")
+	}
+
+	for _, a := range step.Args {
+		if !strings.HasPrefix(a, "-") {
+			continue
+		}
+		name := strings.TrimLeft(a, "-")
+		if strings.HasPrefix(a, "--") {
+			if target.Flags().Lookup(name) == nil {
+				return fmt.Errorf("flag %s not available", a)
+			}
+		} else if target.Flags().ShorthandLookup(name) == nil {
+			return fmt.Errorf("flag %s not available", a)
+		}
+	}
+	return nil
+}
+
+// personalizeCookbookArgs substitutes the detected rig into a step's args.
+func personalizeCookbookArgs(args []string, rigName string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		out[i] = strings.ReplaceAll(a, "{rig}", rigName)
+	}
+	return out
+}
+
+// quoteCookbookArgs quotes args containing spaces so examples are copy-pasteable.
+func quoteCookbookArgs(args []string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		if strings.ContainsAny(a, " ?") {
+			a = fmt.Sprintf("%q", a)
+		}
+		out[i] = a
+	}
+	return out
+}
+
+// cookbookRig picks the rig to personalize examples with: the rig containing
+// cwd, otherwise the first registered rig, otherwise a placeholder.
+func cookbookRig() string {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return "<rig>"
+	}
+
+	if cwd, err := os.Getwd(); err == nil {
+		if abs, err := filepath.Abs(cwd); err == nil {
+			if r := detectRigFromPath(townRoot, abs); r != "" {
+				return r
+			}
+		}
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil || len(rigsConfig.Rigs) == 0 {
+		return "<rig>"
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names[0]
+}
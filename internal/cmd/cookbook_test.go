@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+)
+
+// TestCookbookRecipesMatchInstalledCommands guards against recipes drifting
+// from the real CLI when commands or flags are renamed.
+func TestCookbookRecipesMatchInstalledCommands(t *testing.T) {
+	for _, recipe := range cookbookRecipes {
+		for _, step := range recipe.Steps {
+			if err := validateCookbookStep(rootCmd, step); err != nil {
+				t.Errorf("recipe %q step %v: %v", recipe.Topic, step.Args, err)
+			}
+		}
+	}
+}
+
+func TestValidateCookbookStepRejectsUnknown(t *testing.T) {
+	if err := validateCookbookStep(rootCmd, cookbookStep{Args: []string{"no-such-command"}}); err == nil {
+		t.Error("expected error for unknown command")
+	}
+	if err := validateCookbookStep(rootCmd, cookbookStep{Args: []string{"seance", "--no-such-flag"}}); err == nil {
+		t.Error("expected error for unknown flag")
+	}
+}
+
+func TestFindCookbookRecipe(t *testing.T) {
+	if r := findCookbookRecipe("cost"); r == nil || r.Topic != "cost" {
+		t.Errorf("findCookbookRecipe(cost) = %v", r)
+	}
+	if r := findCookbookRecipe("vacation"); r == nil || r.Topic != "vacation" {
+		t.Errorf("findCookbookRecipe(vacation) = %v", r)
+	}
+	if r := findCookbookRecipe("zzz"); r != nil {
+		t.Errorf("findCookbookRecipe(zzz) = %v, want nil", r)
+	}
+}
+
+func TestPersonalizeCookbookArgs(t *testing.T) {
+	got := personalizeCookbookArgs([]string{"session", "status", "{rig}/<polecat>"}, "gastown")
+	if got[2] != "gastown/<polecat>" {
+		t.Errorf("got %q, want %q", got[2], "gastown/<polecat>")
+	}
+}
@@ -40,6 +40,7 @@ var (
 
 var costsCmd = &cobra.Command{
 	Use:     "costs",
+	Aliases: []string{"cost"},
 	GroupID: GroupDiag,
 	Short:   "Show costs for running Claude sessions",
 	Long: `Display costs for Claude Code sessions in Gas Town.
@@ -59,7 +60,8 @@ Examples:
 
 Subcommands:
   gt costs record       # Record session cost as ephemeral wisp (Stop hook)
-  gt costs digest       # Aggregate wisps into daily digest bead (Deacon patrol)`,
+  gt costs digest       # Aggregate wisps into daily digest bead (Deacon patrol)
+  gt costs models       # Show model pricing used for estimates`,
 	RunE: runCosts,
 }
 
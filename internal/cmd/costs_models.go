@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var costsModelsJSON bool
+
+var costsModelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "Show the model pricing used for cost estimates",
+	Long: `Show the token prices used by every cost computation.
+
+Prices are USD per million tokens. Built-in list prices can be overridden
+(e.g., for negotiated discounts or price changes) with "pricing" entries
+in settings/config.json:
+
+  "pricing": [
+    {"model": "opus", "input": 12, "output": 60,
+     "cache_write": 15, "cache_read": 1.2, "effective_from": "2025-06-01"}
+  ]
+
+Models match as case-insensitive substrings of the model ID; the most
+specific match wins, then the latest effective date. Sessions are priced
+at the rates in effect when they started, so history stays correct after
+a price change. Unmatched models are priced as ` + config.DefaultModel + `.
+
+Examples:
+  gt costs models
+  gt costs models --json`,
+	RunE: runCostsModels,
+}
+
+func init() {
+	costsModelsCmd.Flags().BoolVar(&costsModelsJSON, "json", false, "Output as JSON")
+	costsCmd.AddCommand(costsModelsCmd)
+}
+
+// costModelRow is one pricing entry with its resolution status.
+type costModelRow struct {
+	config.ModelPrice
+	Source string `json:"source"` // "config" or "builtin"
+	Active bool   `json:"active"` // what Lookup returns for this model today
+}
+
+func runCostsModels(cmd *cobra.Command, args []string) error {
+	townRoot, _ := workspace.FindFromCwd()
+	table := loadTownSettingsQuiet(townRoot).PriceTable()
+	if err := table.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v (entry ignored)\n", style.WarningPrefix, err)
+	}
+
+	rows := costModelRows(table, time.Now())
+
+	if costsModelsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Model pricing (USD per million tokens)"))
+	fmt.Printf("  %-20s  %8s  %8s  %11s  %10s  %-12s  %s\n",
+		"MODEL", "INPUT", "OUTPUT", "CACHE WRITE", "CACHE READ", "EFFECTIVE", "SOURCE")
+	for _, r := range rows {
+		effective := r.EffectiveFrom
+		if effective == "" {
+			effective = "-"
+		}
+		line := fmt.Sprintf("  %-20s  %8.2f  %8.2f  %11.2f  %10.2f  %-12s  %s",
+			r.Model, r.Input, r.Output, r.CacheWrite, r.CacheRead, effective, r.Source)
+		if !r.Active {
+			line = style.Dim.Render(line + "  (inactive)")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// costModelRows lists custom then built-in prices, marking the entries
+// that are in effect at now. An entry is inactive when it is superseded,
+// overridden by a custom entry, or not yet effective.
+func costModelRows(table config.PriceTable, now time.Time) []costModelRow {
+	var rows []costModelRow
+	add := func(prices []config.ModelPrice, source string, custom bool) {
+		for _, p := range prices {
+			active, activeCustom := table.Lookup(p.Model, now)
+			rows = append(rows, costModelRow{
+				ModelPrice: p,
+				Source:     source,
+				Active:     activeCustom == custom && active == p,
+			})
+		}
+	}
+	add(table.Custom, "config", true)
+	add(table.Builtin, "builtin", false)
+	return rows
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -24,6 +25,8 @@ The daemon is a simple Go process that:
 - Pokes agents periodically (heartbeat)
 - Processes lifecycle requests (cycle, restart, shutdown)
 - Restarts sessions when agents request cycling
+- Indexes agent transcripts in batches as they are written (the town
+  settings' "ingest" section tunes or disables this)
 
 The daemon is a "dumb scheduler" - all intelligence is in agents.`,
 }
@@ -201,6 +204,14 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 				}
 			}
 		}
+
+		if ingest, ok := claude.IngestInfo(); ok && ingest.Live(time.Now()) {
+			fmt.Printf("  Session ingest: %d queued, %d indexed", ingest.Queued, ingest.Indexed)
+			if !ingest.LastAt.IsZero() {
+				fmt.Printf(" (last batch of %d at %s)", ingest.LastBatch, ingest.LastAt.Format("15:04:05"))
+			}
+			fmt.Println()
+		}
 	} else {
 		fmt.Printf("%s Daemon is %s\n",
 			style.Dim.Render("○"),
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/web"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -27,8 +28,24 @@ The dashboard shows real-time convoy status with:
 - Convoy list with status indicators
 - Progress tracking for each convoy
 - Last activity indicator (green/yellow/red)
+- Possible duplicate work: sessions in different rigs active at the
+  same time that edited the same files or share a topic
 - Auto-refresh every 30 seconds via htmx
 
+Access control:
+  With tokens in the town settings' "server" section, every request must
+  carry one, as an "Authorization: Bearer <token>" header or as the
+  password when the browser prompts. Viewer tokens can read; operator
+  tokens can also take actions (spawn, kill, mail). Actions and refused
+  requests are logged to logs/api-audit.jsonl in the town.
+
+    "server": {"tokens": [
+      {"name": "alice", "token_env": "GT_TOKEN_ALICE", "role": "operator"},
+      {"name": "team", "token_env": "GT_TOKEN_TEAM", "role": "viewer"}
+    ]}
+
+  Without tokens the dashboard is open, so only expose it with them set.
+
 Example:
   gt dashboard              # Start on default port 8080
   gt dashboard --port 3000  # Start on port 3000
@@ -44,7 +61,8 @@ func init() {
 
 func runDashboard(cmd *cobra.Command, args []string) error {
 	// Verify we're in a workspace
-	if _, err := workspace.FindFromCwdOrError(); err != nil {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
@@ -55,10 +73,14 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create the handler
-	handler, err := web.NewConvoyHandler(fetcher)
+	convoyHandler, err := web.NewConvoyHandler(fetcher)
 	if err != nil {
 		return fmt.Errorf("creating convoy handler: %w", err)
 	}
+	handler, err := withServerAuth(townRoot, convoyHandler)
+	if err != nil {
+		return err
+	}
 
 	// Build the URL
 	url := fmt.Sprintf("http://localhost:%d", dashboardPort)
@@ -70,6 +92,7 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 
 	// Start the server with timeouts
 	fmt.Printf("🚚 Gas Town Dashboard starting at %s\n", url)
+	printServerAuth(townRoot, handler)
 	fmt.Printf("   Press Ctrl+C to stop\n")
 
 	server := &http.Server{
@@ -83,6 +106,38 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	return server.ListenAndServe()
 }
 
+// withServerAuth puts handler behind token auth when the town's settings
+// configure server tokens. Settings are loaded strictly: a broken file
+// must not leave the server open.
+func withServerAuth(townRoot string, handler http.Handler) (http.Handler, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	configured := settings.ServerTokens()
+	if len(configured) == 0 {
+		return handler, nil
+	}
+	tokens := make([]web.Token, 0, len(configured))
+	for _, t := range configured {
+		tokens = append(tokens, web.Token{Name: t.Name, Secret: t.Secret(), Role: t.Role})
+	}
+	auth, err := web.NewTokenAuth(handler, tokens, web.AuditLogPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("configuring server tokens: %w", err)
+	}
+	return auth, nil
+}
+
+// printServerAuth tells whoever starts a server whether it needs tokens.
+func printServerAuth(townRoot string, handler http.Handler) {
+	if _, ok := handler.(*web.TokenAuth); ok {
+		fmt.Printf("   Token auth on; audit log: %s\n", web.AuditLogPath(townRoot))
+	} else {
+		fmt.Printf("   No server tokens configured: anyone who can reach this port can use it\n")
+	}
+}
+
 // openBrowser opens the specified URL in the default browser.
 func openBrowser(url string) {
 	var cmd *exec.Cmd
@@ -92,7 +147,9 @@ func openBrowser(url string) {
 	case "linux":
 		cmd = exec.Command("xdg-open", url)
 	case "windows":
-		cmd = exec.Command("cmd", "/c", "start", url)
+		// Not "cmd /c start", which splits URLs at & and treats a quoted
+		// URL as a window title
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
 	default:
 		return
 	}
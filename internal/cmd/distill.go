@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+var (
+	distillRig      string
+	distillSince    string
+	distillOut      string
+	distillLink     string
+	distillMaxChars int
+	distillDryRun   bool
+)
+
+// distillTurnChars caps how much of any single turn goes into the prompt.
+const distillTurnChars = 600
+
+var distillCmd = &cobra.Command{
+	Use:     "distill",
+	GroupID: GroupDiag,
+	Short:   "Distill lessons from session transcripts into a knowledge file",
+	Long: `Extract recurring lessons, gotchas, and decisions from recent sessions.
+
+Transcripts of Gas Town sessions in the rig are condensed and handed to a
+headless Claude pass, which writes a curated markdown file. Every lesson
+cites the session IDs it came from, so 'gt seance --talk <id>' can recover
+the full context.
+
+Use --link to add an @-import of the notes to a CLAUDE.md so future agents
+start with the lessons loaded.
+
+Examples:
+  gt distill --rig gastown --since 30d --out docs/agent-notes.md
+  gt distill --rig gastown --out docs/agent-notes.md --link CLAUDE.md
+  gt distill --rig gastown --dry-run    # print the prompt without running Claude`,
+	RunE: runDistill,
+}
+
+func init() {
+	distillCmd.Flags().StringVar(&distillRig, "rig", "", "Rig whose sessions to distill")
+	distillCmd.Flags().StringVar(&distillSince, "since", "30d", "Only include sessions started within this window (e.g., 7d, 72h)")
+	distillCmd.Flags().StringVarP(&distillOut, "out", "o", "", "Output markdown file (default: stdout)")
+	distillCmd.Flags().StringVar(&distillLink, "link", "", "CLAUDE.md to add an @-import of the output to")
+	distillCmd.Flags().IntVar(&distillMaxChars, "max-chars", 200_000, "Maximum transcript characters sent to Claude")
+	distillCmd.Flags().BoolVar(&distillDryRun, "dry-run", false, "Print the distillation prompt instead of running Claude")
+	rootCmd.AddCommand(distillCmd)
+}
+
+func runDistill(cmd *cobra.Command, args []string) error {
+	if distillLink != "" && distillOut == "" {
+		return fmt.Errorf("--link requires --out")
+	}
+
+	window, err := parseDuration(distillSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	cutoff := time.Now().Add(-window)
+
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{
+		GasTownOnly: true,
+		Rig:         distillRig,
+	})
+	if err != nil {
+		return fmt.Errorf("discovering sessions: %w", err)
+	}
+
+	var recent []claude.SessionInfo
+	for _, s := range sessions {
+		if s.StartTime.After(cutoff) {
+			recent = append(recent, s)
+		}
+	}
+	if len(recent) == 0 {
+		return fmt.Errorf("no Gas Town sessions found in the last %s", distillSince)
+	}
+
+	prompt, included := buildDistillPrompt(recent, distillMaxChars)
+
+	if distillDryRun {
+		fmt.Print(prompt)
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "%s Distilling %d session(s)...\n", style.Bold.Render("⚗"), included)
+
+	claudeCmd := exec.Command("claude", "--print")
+	claudeCmd.Stdin = strings.NewReader(prompt)
+	var out bytes.Buffer
+	claudeCmd.Stdout = &out
+	claudeCmd.Stderr = os.Stderr
+	if err := claudeCmd.Run(); err != nil {
+		return fmt.Errorf("running claude: %w", err)
+	}
+
+	header := fmt.Sprintf("<!-- Generated by gt distill on %s from %d session(s). Edit freely; regenerate to refresh. -->\n\n",
+		time.Now().Format("2006-01-02"), included)
+	notes := header + strings.TrimSpace(out.String()) + "\n"
+
+	if distillOut == "" {
+		fmt.Print(notes)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(distillOut), 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	if err := util.AtomicWriteFile(distillOut, []byte(notes), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", distillOut, err)
+	}
+	fmt.Printf("%s Wrote %s\n", style.SuccessPrefix, distillOut)
+
+	if distillLink != "" {
+		added, err := linkDistilledNotes(distillLink, distillOut)
+		if err != nil {
+			return err
+		}
+		if added {
+			fmt.Printf("%s Linked from %s\n", style.SuccessPrefix, distillLink)
+		}
+	}
+	return nil
+}
+
+// buildDistillPrompt condenses sessions into a prompt, newest first, until
+// maxChars is reached. Returns the prompt and the number of sessions used.
+func buildDistillPrompt(sessions []claude.SessionInfo, maxChars int) (string, int) {
+	var b strings.Builder
+	b.WriteString(`You are curating a knowledge base for AI coding agents working in this project.
+Below are condensed transcripts of recent agent sessions. Extract the recurring
+lessons, gotchas, and decisions that a future agent should know before starting.
+
+Rules:
+- Output markdown with sections: "## Gotchas", "## Decisions", "## Lessons".
+- Each bullet must be specific and actionable, not generic advice.
+- End every bullet with citations of the session IDs it came from, like [s:abc12345].
+- Skip anything that only applied to a single one-off task.
+- Output only the markdown document.
+
+`)
+
+	included := 0
+	for _, s := range sessions {
+		turns, err := claude.ReadTurns(s.Path)
+		if err != nil || len(turns) == 0 {
+			continue
+		}
+
+		var section strings.Builder
+		fmt.Fprintf(&section, "### Session s:%s (%s, %s)\n", s.ShortID(), s.Role, s.StartTime.Format("2006-01-02"))
+		if s.Summary != "" {
+			fmt.Fprintf(&section, "Summary: %s\n", s.Summary)
+		}
+		for _, t := range turns {
+			text := t.Text
+			if len(text) > distillTurnChars {
+				text = text[:distillTurnChars] + "…"
+			}
+			if text == "" {
+				text = "[tools: " + strings.Join(t.Tools, ", ") + "]"
+			}
+			fmt.Fprintf(&section, "%s: %s\n", t.Role, text)
+		}
+		section.WriteString("\n")
+
+		if b.Len()+section.Len() > maxChars && included > 0 {
+			break
+		}
+		b.WriteString(section.String())
+		included++
+	}
+	return b.String(), included
+}
+
+// linkDistilledNotes appends an @-import of notesPath to claudeMD if it
+// isn't already referenced. Reports whether the file was changed.
+func linkDistilledNotes(claudeMD, notesPath string) (bool, error) {
+	rel, err := filepath.Rel(filepath.Dir(claudeMD), notesPath)
+	if err != nil {
+		rel = notesPath
+	}
+	importLine := "@" + filepath.ToSlash(rel)
+
+	existing, err := os.ReadFile(claudeMD) //nolint:gosec // G304: user-specified CLAUDE.md
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("reading %s: %w", claudeMD, err)
+	}
+	for _, line := range strings.Split(string(existing), "\n") {
+		if strings.TrimSpace(line) == importLine {
+			return false, nil
+		}
+	}
+
+	content := string(existing)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += "\n## Agent notes\n\n" + importLine + "\n"
+	if err := util.AtomicWriteFile(claudeMD, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("writing %s: %w", claudeMD, err)
+	}
+	return true, nil
+}
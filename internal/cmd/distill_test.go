@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLinkDistilledNotes(t *testing.T) {
+	dir := t.TempDir()
+	claudeMD := filepath.Join(dir, "CLAUDE.md")
+	if err := os.WriteFile(claudeMD, []byte("# Project"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	notes := filepath.Join(dir, "docs", "agent-notes.md")
+
+	added, err := linkDistilledNotes(claudeMD, notes)
+	if err != nil || !added {
+		t.Fatalf("first link: added=%v err=%v", added, err)
+	}
+	added, err = linkDistilledNotes(claudeMD, notes)
+	if err != nil || added {
+		t.Fatalf("second link should be a no-op: added=%v err=%v", added, err)
+	}
+
+	data, err := os.ReadFile(claudeMD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(data), "@docs/agent-notes.md") != 1 {
+		t.Errorf("CLAUDE.md = %q", data)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doctor"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
 )
 
 var (
@@ -14,6 +15,7 @@ var (
 	doctorVerbose         bool
 	doctorRig             string
 	doctorRestartSessions bool
+	doctorYes             bool
 )
 
 var doctorCmd = &cobra.Command{
@@ -30,6 +32,7 @@ Workspace checks:
   - town-config-valid        Check mayor/town.json is valid
   - rigs-registry-exists     Check mayor/rigs.json exists (fixable)
   - rigs-registry-valid      Check registered rigs exist (fixable)
+  - rigs-from-sessions       Detect unregistered rigs used by recent sessions (remediation)
   - mayor-exists             Check mayor/ directory structure
 
 Town root protection:
@@ -47,6 +50,8 @@ Cleanup checks (fixable):
   - orphan-sessions          Detect orphaned tmux sessions
   - orphan-processes         Detect orphaned Claude processes
   - wisp-gc                  Detect and clean abandoned wisps (>1h)
+  - orphaned-worktrees       Detect worktrees whose directories were deleted (remediation)
+  - runtime-records          Detect corrupt event, outcome, and heartbeat records (remediation)
 
 Clone divergence checks:
   - persistent-role-branches Detect crew/witness/refinery not on main
@@ -72,6 +77,7 @@ Routing checks (fixable):
 Session hook checks:
   - session-hooks            Check settings.json use session-start.sh
   - claude-settings          Check Claude settings.json match templates (fixable)
+  - hooks-installed          Detect agent directories missing Claude hooks (remediation)
 
 Patrol checks:
   - patrol-molecules-exist   Verify patrol molecules exist
@@ -80,7 +86,12 @@ Patrol checks:
   - patrol-plugins-accessible Verify plugin directories
   - patrol-roles-have-prompts Verify role prompts exist
 
-Use --fix to attempt automatic fixes for issues that support it.
+Use --fix to attempt automatic fixes for issues that support it. In a
+terminal, each fix is confirmed first; --yes applies every fix without
+asking. Remediation fixes (re-registering rigs, reinstalling hooks,
+pruning worktrees, rewriting records) change more state, so when stdin
+is not a terminal they only run with --yes.
+
 Use --rig to check a specific rig instead of the entire workspace.`,
 	RunE: runDoctor,
 }
@@ -90,6 +101,7 @@ func init() {
 	doctorCmd.Flags().BoolVarP(&doctorVerbose, "verbose", "v", false, "Show detailed output")
 	doctorCmd.Flags().StringVar(&doctorRig, "rig", "", "Check specific rig only")
 	doctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings (use with --fix)")
+	doctorCmd.Flags().BoolVarP(&doctorYes, "yes", "y", false, "Apply fixes without confirmation, including remediation fixes (use with --fix)")
 	rootCmd.AddCommand(doctorCmd)
 }
 
@@ -107,12 +119,19 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		Verbose:         doctorVerbose,
 		RestartSessions: doctorRestartSessions,
 	}
+	switch {
+	case doctorYes:
+		ctx.ConfirmFix = func(doctor.Check, *doctor.CheckResult) bool { return true }
+	case term.IsTerminal(int(os.Stdin.Fd())):
+		ctx.ConfirmFix = confirmDoctorFix
+	}
 
 	// Create doctor and register checks
 	d := doctor.NewDoctor()
 
 	// Register workspace-level checks first (fundamental)
 	d.RegisterAll(doctor.WorkspaceChecks()...)
+	d.Register(doctor.NewSessionRigsCheck())
 
 	d.Register(doctor.NewGlobalStateCheck())
 
@@ -134,6 +153,8 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewOrphanSessionCheck())
 	d.Register(doctor.NewOrphanProcessCheck())
 	d.Register(doctor.NewWispGCCheck())
+	d.Register(doctor.NewOrphanedWorktreeCheck())
+	d.Register(doctor.NewRuntimeRecordsCheck())
 	d.Register(doctor.NewBranchCheck())
 	d.Register(doctor.NewBeadsSyncOrphanCheck())
 	d.Register(doctor.NewCloneDivergenceCheck())
@@ -160,6 +181,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewRuntimeGitignoreCheck())
 	d.Register(doctor.NewLegacyGastownCheck())
 	d.Register(doctor.NewClaudeSettingsCheck())
+	d.Register(doctor.NewSettingsInstalledCheck())
 
 	// Priming subsystem check
 	d.Register(doctor.NewPrimingCheck())
@@ -200,3 +222,12 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// confirmDoctorFix asks before applying a fix.
+func confirmDoctorFix(check doctor.Check, result *doctor.CheckResult) bool {
+	fmt.Printf("\n%s: %s\n", check.Name(), result.Message)
+	for _, detail := range result.Details {
+		fmt.Printf("  %s\n", detail)
+	}
+	return promptYesNo("Apply fix?")
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/web"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	editorServeHost string
+	editorServePort int
+)
+
+var editorCmd = &cobra.Command{
+	Use:     "editor",
+	GroupID: GroupServices,
+	Short:   "Serve Gas Town to editor plugins",
+	RunE:    requireSubcommand,
+}
+
+var editorServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Start the JSON API editor plugins talk to",
+	Long: `Start a JSON API for editor plugins (VS Code, Neovim) so they can show
+Gas Town panels next to the code:
+
+  GET  /api/sessions?file=PATH[&limit=N]
+       Sessions that read or wrote the file, most recent first
+       (as gt seance near).
+  GET  /api/blame?file=PATH
+       git blame hunks, each attributed to the agent session that made
+       the commit when one is found.
+  GET  /api/transcript?session=ID[&turn=N&count=N]
+       A session's transcript from turn N, count turns at a time
+       (default 20, at most 200).
+  POST /api/mail   {"to": "gastown/witness", "subject": "...", "body": "..."}
+       Send mail to an agent, from whoever runs the server. The body
+       must be sent as application/json, and requests from web pages
+       not served from localhost are refused.
+  GET  /api/health
+
+File paths are absolute or relative to where the server runs. Errors
+are JSON objects with an "error" field.
+
+The server listens on localhost only unless --host says otherwise. Server
+tokens in the town settings apply as for gt dashboard: viewer tokens
+can read, and sending mail needs an operator token.
+
+Examples:
+  gt editor serve
+  gt editor serve --port 7879
+  curl 'localhost:7878/api/sessions?file=internal/cmd/seance.go'`,
+	Args: cobra.NoArgs,
+	RunE: runEditorServe,
+}
+
+func init() {
+	editorServeCmd.Flags().StringVar(&editorServeHost, "host", "127.0.0.1", "Address to listen on")
+	editorServeCmd.Flags().IntVar(&editorServePort, "port", 7878, "HTTP port to listen on")
+
+	editorCmd.AddCommand(editorServeCmd)
+	rootCmd.AddCommand(editorCmd)
+}
+
+func runEditorServe(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	handler, err := withServerAuth(townRoot, web.NewEditorHandler(&liveEditorSource{townRoot: townRoot}))
+	if err != nil {
+		return err
+	}
+
+	addr := fmt.Sprintf("%s:%d", editorServeHost, editorServePort)
+	fmt.Printf("✏️  Gas Town editor API at http://%s/api\n", addr)
+	printServerAuth(townRoot, handler)
+	fmt.Printf("   Press Ctrl+C to stop\n")
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      120 * time.Second, // first lookups read every transcript
+		IdleTimeout:       120 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+// liveEditorSource answers editor API requests from the town and the
+// Claude session data.
+type liveEditorSource struct {
+	townRoot string
+}
+
+// fileSessions returns every session that touched file, matched as gt
+// seance near matches it. Partial discovery still answers.
+func (s *liveEditorSource) fileSessions(file string) (string, []claude.FileSession, error) {
+	path, err := nearLookupPath(file, false)
+	if err != nil {
+		return "", nil, err
+	}
+	matches, err := claude.SessionsNear(path, claude.SessionFilter{})
+	if err != nil && !errors.Is(err, claude.ErrIncomplete) {
+		return "", nil, fmt.Errorf("finding sessions: %w", err)
+	}
+	return path, matches, nil
+}
+
+func (s *liveEditorSource) FileSessions(file string, limit int) (*web.FileSessions, error) {
+	path, matches, err := s.fileSessions(file)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	if matches == nil {
+		matches = []claude.FileSession{}
+	}
+	return &web.FileSessions{File: path, Sessions: matches}, nil
+}
+
+func (s *liveEditorSource) FileBlame(file string) (*web.FileBlame, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := git.NewGit(filepath.Dir(abs)).Blame(filepath.Base(abs))
+	if err != nil {
+		return nil, fmt.Errorf("blaming %s: %w", file, err)
+	}
+	_, matches, err := s.fileSessions(abs)
+	if err != nil {
+		return nil, err
+	}
+	return &web.FileBlame{File: abs, Hunks: blameHunks(lines, matches)}, nil
+}
+
+func (s *liveEditorSource) TranscriptAt(id string, turn, count int) (*web.TranscriptPage, error) {
+	session, err := claude.FindSession(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", web.ErrNotFound, err)
+	}
+	turns, err := claude.ReadTranscriptTurns(session.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading transcript: %w", err)
+	}
+	start := min(turn, len(turns))
+	end := min(start+count, len(turns))
+	return &web.TranscriptPage{Session: *session, Start: start, Total: len(turns), Turns: turns[start:end]}, nil
+}
+
+func (s *liveEditorSource) SendMail(req web.MailRequest) (*web.MailResult, error) {
+	from := detectSender()
+	router := mail.NewRouter(s.townRoot)
+	msg := mail.NewMessage(from, req.To, req.Subject, req.Body)
+	msg.Wisp = true // as gt mail send does by default
+	if err := router.Send(msg); err != nil {
+		return nil, fmt.Errorf("sending message: %w", err)
+	}
+	_ = events.LogFeed(events.TypeMail, from, events.MailPayload(req.To, req.Subject))
+
+	result := &web.MailResult{From: from, To: req.To}
+	if rec := router.LastDelivery(); rec != nil && rec.Pending() {
+		result.Queued = true
+	}
+	return result, nil
+}
+
+// blameCommitSlack allows for a commit landing just after the session's
+// last transcript entry.
+const blameCommitSlack = 2 * time.Minute
+
+// blameHunks groups blame lines into runs from the same commit and
+// attributes each commit to a session that wrote the file: the one that
+// reported making it, or else the latest one running when it was made.
+func blameHunks(lines []git.BlameLine, matches []claude.FileSession) []web.BlameHunk {
+	bySHA := make(map[string]*claude.SessionInfo)
+	sessionFor := func(line git.BlameLine) *claude.SessionInfo {
+		if line.Uncommitted() {
+			return nil
+		}
+		if s, ok := bySHA[line.Commit]; ok {
+			return s
+		}
+		var found *claude.SessionInfo
+		for i := range matches {
+			s := &matches[i].Session
+			if !matches[i].Wrote {
+				continue
+			}
+			if s.GitCommit != "" && strings.HasPrefix(line.Commit, s.GitCommit) {
+				found = s
+				break
+			}
+			ran := !s.StartTime.After(line.Time) && (s.EndTime.IsZero() || !line.Time.After(s.EndTime.Add(blameCommitSlack)))
+			if ran && (found == nil || s.StartTime.After(found.StartTime)) {
+				found = s
+			}
+		}
+		bySHA[line.Commit] = found
+		return found
+	}
+
+	hunks := []web.BlameHunk{}
+	prev := ""
+	for _, line := range lines {
+		if n := len(hunks); n > 0 && prev == line.Commit && hunks[n-1].End == line.Line-1 {
+			hunks[n-1].End = line.Line
+			continue
+		}
+		prev = line.Commit
+		hunk := web.BlameHunk{Start: line.Line, End: line.Line, Uncommitted: line.Uncommitted()}
+		if !hunk.Uncommitted {
+			hunk.Commit, hunk.Author, hunk.Time, hunk.Summary = line.Commit, line.Author, line.Time, line.Summary
+		}
+		if s := sessionFor(line); s != nil {
+			hunk.SessionID, hunk.Role = s.ID, s.Role
+		}
+		hunks = append(hunks, hunk)
+	}
+	return hunks
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestBlameHunks(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2025, 1, 2, h, 0, 0, 0, time.UTC) }
+	commitA := strings.Repeat("a", 40)
+	commitB := "b1b2b3b" + strings.Repeat("0", 33)
+	commitC := strings.Repeat("c", 40)
+	uncommitted := strings.Repeat("0", 40)
+	lines := []git.BlameLine{
+		{Line: 1, Commit: commitA, Time: at(10), Summary: "during polecat"},
+		{Line: 2, Commit: commitA, Time: at(10), Summary: "during polecat"},
+		{Line: 3, Commit: commitB, Time: at(20), Summary: "reported by crew"},
+		{Line: 4, Commit: commitC, Time: at(5), Summary: "by hand"},
+		{Line: 5, Commit: uncommitted},
+		{Line: 6, Commit: uncommitted},
+	}
+	matches := []claude.FileSession{
+		{Session: claude.SessionInfo{ID: "polecat1", Role: "gastown/polecats/toast", StartTime: at(9), EndTime: at(11)}, Wrote: true},
+		{Session: claude.SessionInfo{ID: "crew1", Role: "gastown/crew/joe", StartTime: at(9).Add(30 * time.Minute), EndTime: at(12), GitCommit: "b1b2b3b"}, Wrote: true},
+		{Session: claude.SessionInfo{ID: "reader1", StartTime: at(4), EndTime: at(6)}, Read: true},
+	}
+
+	hunks := blameHunks(lines, matches)
+	if len(hunks) != 4 {
+		t.Fatalf("hunks = %+v", hunks)
+	}
+	// Both writers ran at 10:00; the later-started one wins
+	if h := hunks[0]; h.Start != 1 || h.End != 2 || h.SessionID != "crew1" {
+		t.Errorf("hunk 1 = %+v", h)
+	}
+	if h := hunks[1]; h.SessionID != "crew1" || h.Role != "gastown/crew/joe" {
+		t.Errorf("reported commit = %+v", h)
+	}
+	if h := hunks[2]; h.SessionID != "" {
+		t.Errorf("commit made while only a reader ran = %+v", h)
+	}
+	if h := hunks[3]; !h.Uncommitted || h.Commit != "" || h.Start != 5 || h.End != 6 {
+		t.Errorf("uncommitted hunk = %+v", h)
+	}
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	exportSince string
+	exportOut   string
+	exportRig   string
+	exportSplit bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	GroupID: GroupDiag,
+	Short:   "Export town activity to other tools",
+	RunE:    requireSubcommand,
+}
+
+var exportICalCmd = &cobra.Command{
+	Use:   "ical",
+	Short: "Export agent sessions as an iCalendar (.ics) file",
+	Long: `Export Gas Town sessions as calendar events.
+
+Each session becomes an event spanning its first to last transcript
+entry, titled with its topic and categorized by role and by any tags
+from the town's session_tags rules. Import the file into a calendar app
+to overlay agent activity on human calendars for capacity conversations.
+
+With --split, one calendar per role is written instead
+(<out>-<role>.ics), so each role can be toggled on its own.
+
+Examples:
+  gt export ical --since 30d
+  gt export ical --since 7d --rig gastown -o gastown.ics
+  gt export ical --split -o town.ics`,
+	RunE: runExportICal,
+}
+
+func init() {
+	exportICalCmd.Flags().StringVar(&exportSince, "since", "30d", "Only export sessions started within this window")
+	exportICalCmd.Flags().StringVarP(&exportOut, "out", "o", "gastown.ics", "Output file (- for stdout)")
+	exportICalCmd.Flags().StringVar(&exportRig, "rig", "", "Only export sessions from this rig")
+	exportICalCmd.Flags().BoolVar(&exportSplit, "split", false, "Write one calendar per role")
+
+	exportCmd.AddCommand(exportICalCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+// icalEvent is one session rendered as a calendar event.
+type icalEvent struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Category    string
+	Tags        []string
+}
+
+func runExportICal(cmd *cobra.Command, args []string) error {
+	since, err := parseDuration(exportSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if exportSplit && exportOut == "-" {
+		return fmt.Errorf("--split writes one file per role; use -o with a file name")
+	}
+
+	townRoot, _ := workspace.FindFromCwd()
+	tagger, err := loadTownSettingsQuiet(townRoot).SessionTagger()
+	if err != nil {
+		return err
+	}
+
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{GasTownOnly: true, Rig: exportRig, Tagger: tagger})
+	if err != nil {
+		return fmt.Errorf("discovering sessions: %w", err)
+	}
+
+	cutoff := time.Now().Add(-since)
+	byRole := make(map[string][]icalEvent)
+	total := 0
+	for _, s := range sessions {
+		if s.StartTime.IsZero() || s.StartTime.Before(cutoff) {
+			continue
+		}
+		ev := sessionICalEvent(s)
+		if usage, err := claude.ReadUsage(s.Path); err == nil && usage.LastTimestamp.After(ev.Start) {
+			ev.End = usage.LastTimestamp
+		}
+		byRole[ev.Category] = append(byRole[ev.Category], ev)
+		total++
+	}
+
+	if !exportSplit {
+		var all []icalEvent
+		for _, events := range byRole {
+			all = append(all, events...)
+		}
+		if exportOut == "-" {
+			return writeICal(os.Stdout, "Gas Town", all, time.Now())
+		}
+		if err := writeICalFile(exportOut, "Gas Town", all); err != nil {
+			return err
+		}
+		fmt.Printf("%s Wrote %d session(s) to %s\n", style.SuccessPrefix, total, exportOut)
+		return nil
+	}
+
+	base := strings.TrimSuffix(exportOut, filepath.Ext(exportOut))
+	roles := make([]string, 0, len(byRole))
+	for role := range byRole {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		path := fmt.Sprintf("%s-%s.ics", base, role)
+		if err := writeICalFile(path, "Gas Town: "+role, byRole[role]); err != nil {
+			return err
+		}
+		fmt.Printf("%s Wrote %d %s session(s) to %s\n", style.SuccessPrefix, len(byRole[role]), role, path)
+	}
+	if len(roles) == 0 {
+		fmt.Println(style.Dim.Render("No sessions in this window."))
+	}
+	return nil
+}
+
+// sessionICalEvent builds the event for a session. End defaults to one
+// minute after the start until the transcript's last entry is known.
+func sessionICalEvent(s claude.SessionInfo) icalEvent {
+	role, rig, _ := parseRoleString(s.Role)
+	category := string(role)
+	if category == "" {
+		category = "unknown"
+	}
+
+	title := s.Topic
+	if title == "" {
+		title = s.Summary
+	}
+	if title == "" {
+		title = "session " + s.ShortID()
+	}
+
+	desc := []string{"Agent: " + s.Role}
+	if rig != "" {
+		desc = append(desc, "Rig: "+rig)
+	}
+	if s.Summary != "" && s.Summary != title {
+		desc = append(desc, "Summary: "+s.Summary)
+	}
+	desc = append(desc, "Session: "+s.ID, "Open: gt seance show "+s.ShortID())
+
+	return icalEvent{
+		UID:         s.ID + "@gastown",
+		Start:       s.StartTime,
+		End:         s.StartTime.Add(time.Minute),
+		Summary:     fmt.Sprintf("[%s] %s", category, title),
+		Description: strings.Join(desc, "\n"),
+		Category:    category,
+		Tags:        s.Tags,
+	}
+}
+
+// writeICalFile writes a calendar to path.
+func writeICalFile(path, name string, events []icalEvent) error {
+	f, err := os.Create(path) //nolint:gosec // G304: user-specified output path
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	if err := writeICal(f, name, events, time.Now()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// writeICal writes events as an RFC 5545 calendar, oldest first.
+func writeICal(w io.Writer, name string, events []icalEvent, now time.Time) error {
+	sorted := append([]icalEvent(nil), events...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var b strings.Builder
+	line := func(s string) { b.WriteString(foldICalLine(s)) }
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Gas Town//gt export ical//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + escapeICalText(name))
+	stamp := icalTime(now)
+	for _, ev := range sorted {
+		line("BEGIN:VEVENT")
+		line("UID:" + escapeICalText(ev.UID))
+		line("DTSTAMP:" + stamp)
+		line("DTSTART:" + icalTime(ev.Start))
+		line("DTEND:" + icalTime(ev.End))
+		line("SUMMARY:" + escapeICalText(ev.Summary))
+		if ev.Description != "" {
+			line("DESCRIPTION:" + escapeICalText(ev.Description))
+		}
+		var categories []string
+		for _, c := range append([]string{ev.Category}, ev.Tags...) {
+			if c != "" {
+				categories = append(categories, escapeICalText(c))
+			}
+		}
+		if len(categories) > 0 {
+			line("CATEGORIES:" + strings.Join(categories, ","))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// icalTime formats t as a UTC iCalendar date-time.
+func icalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeICalText escapes a TEXT value per RFC 5545 section 3.3.11.
+func escapeICalText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// foldICalLine folds a content line to at most 75 octets per line, without
+// splitting multi-byte characters, and terminates it with CRLF.
+func foldICalLine(s string) string {
+	limit := 75
+	var b strings.Builder
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = 74 // Continuation lines start with a space
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteICal(t *testing.T) {
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	events := []icalEvent{
+		{UID: "b@gastown", Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), Summary: "[crew] later", Category: "crew", Tags: []string{"experiment", "spike"}},
+		{UID: "a@gastown", Start: start, End: start.Add(30 * time.Minute), Summary: "[polecat] fix auth; retry, again", Description: "Agent: gastown/polecats/toast\nRig: gastown", Category: "polecat"},
+	}
+
+	var b strings.Builder
+	if err := writeICal(&b, "Gas Town", events, start); err != nil {
+		t.Fatalf("writeICal: %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Gas Town\r\n",
+		"DTSTART:20250106T090000Z\r\n",
+		"DTEND:20250106T093000Z\r\n",
+		`SUMMARY:[polecat] fix auth\; retry\, again` + "\r\n",
+		`DESCRIPTION:Agent: gastown/polecats/toast\nRig: gastown` + "\r\n",
+		"CATEGORIES:polecat\r\n",
+		"CATEGORIES:crew,experiment,spike\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "UID:a@gastown") > strings.Index(out, "UID:b@gastown") {
+		t.Error("events should be sorted oldest first")
+	}
+}
+
+func TestFoldICalLine(t *testing.T) {
+	long := "SUMMARY:" + strings.Repeat("é", 100)
+	folded := foldICalLine(long)
+	for _, line := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("folded line has %d octets, want <= 75", len(line))
+		}
+	}
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", "")
+	if unfolded != long {
+		t.Error("unfolding should restore the original line")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"syscall"
 
@@ -53,6 +54,14 @@ By default, launches an interactive TUI dashboard with:
   - Event stream (bottom): Chronological feed you can scroll through
   - Vim-style navigation: j/k to scroll, tab to switch panels, 1/2/3 for panels, q to quit
 
+Quick actions: focus the agent tree (1) and select an agent with j/k, then:
+  v  open its latest transcript in $PAGER
+  m  send it mail
+  t  tag its latest session
+  O  record the latest session's outcome (success/failure/partial)
+  X  stop it (asks to confirm)
+  R  resume its latest session (in a new tmux window inside tmux)
+
 The feed combines multiple event sources:
   - Beads activity: Issue creates, updates, completions (from bd activity)
   - GT events: Agent activity like patrol, sling, handoff (from .events.jsonl)
@@ -107,13 +116,13 @@ func runFeed(cmd *cobra.Command, args []string) error {
 	if feedRig != "" {
 		// Try common beads locations for the rig
 		candidates := []string{
-			fmt.Sprintf("%s/%s/mayor/rig", townRoot, feedRig),
-			fmt.Sprintf("%s/%s", townRoot, feedRig),
+			filepath.Join(townRoot, feedRig, "mayor", "rig"),
+			filepath.Join(townRoot, feedRig),
 		}
 
 		found := false
 		for _, candidate := range candidates {
-			if _, err := os.Stat(candidate + "/.beads"); err == nil {
+			if _, err := os.Stat(filepath.Join(candidate, ".beads")); err == nil {
 				workDir = candidate
 				found = true
 				break
@@ -232,6 +241,7 @@ func runFeedTUI(workDir string) error {
 	m := feed.NewModel()
 	m.SetEventChannel(multiSource.Events())
 	m.SetTownRoot(townRoot)
+	m.SetActionFunc(feedAction)
 
 	// Run the TUI
 	p := tea.NewProgram(m, tea.WithAltScreen())
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/feed"
+)
+
+// feedMailSubject is the subject of mail sent from the feed dashboard.
+const feedMailSubject = "Note from the dashboard"
+
+// feedAction builds the gt command for a quick action on an agent in the
+// feed dashboard (see feed.ActionFunc).
+func feedAction(action feed.Action, agent feed.Agent, input string) (*exec.Cmd, error) {
+	gt, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("finding gt: %w", err)
+	}
+
+	switch action {
+	case feed.ActionMail:
+		return exec.Command(gt, "mail", "send", agent.ID, "-s", feedMailSubject, "-m", input), nil //nolint:gosec // G204: runs gt itself
+	case feed.ActionKill:
+		args, err := feedKillArgs(agent.ID)
+		if err != nil {
+			return nil, err
+		}
+		return exec.Command(gt, args...), nil //nolint:gosec // G204: runs gt itself
+	}
+
+	// The rest act on the agent's latest session
+	session, err := latestAgentSession(agent.ID)
+	if err != nil {
+		return nil, err
+	}
+	switch action {
+	case feed.ActionTranscript:
+		if runtime.GOOS == "windows" {
+			return exec.Command(gt, "seance", "show", session.ID), nil //nolint:gosec // G204: runs gt itself
+		}
+		return exec.Command("sh", "-c", `"$0" seance show "$1" | ${PAGER:-less -R}`, gt, session.ID), nil
+	case feed.ActionTag:
+		return exec.Command(gt, append([]string{"seance", "tag", session.ID}, strings.Fields(input)...)...), nil //nolint:gosec // G204: runs gt itself
+	case feed.ActionOutcome:
+		return exec.Command(gt, "outcome", "set", session.ID, strings.ToLower(input)), nil //nolint:gosec // G204: runs gt itself
+	case feed.ActionResume:
+		if !tmux.IsInsideTmux() {
+			// Resume in this terminal; the dashboard comes back when it ends
+			return exec.Command(gt, "seance", "resume", session.ID), nil //nolint:gosec // G204: runs gt itself
+		}
+		return exec.Command("tmux", "new-window", "-n", "resume-"+session.ShortID(), "-c", session.ProjectPath, //nolint:gosec // G204: runs gt itself
+			gt, "seance", "resume", session.ID), nil
+	}
+	return nil, fmt.Errorf("unknown action %q", action)
+}
+
+// feedKillArgs returns the gt arguments that stop the agent with the
+// given address.
+func feedKillArgs(address string) ([]string, error) {
+	role, rig, name := parseRoleString(address)
+	switch role {
+	case RoleMayor:
+		return []string{"mayor", "stop"}, nil
+	case RoleDeacon:
+		return []string{"deacon", "stop"}, nil
+	case RoleWitness:
+		return []string{"witness", "stop", rig}, nil
+	case RoleRefinery:
+		return []string{"refinery", "stop", rig}, nil
+	case RoleCrew:
+		if name != "" {
+			return []string{"crew", "stop", rig + "/" + name}, nil
+		}
+	case RolePolecat:
+		if name != "" {
+			return []string{"session", "stop", rig + "/" + name}, nil
+		}
+	}
+	return nil, fmt.Errorf("don't know how to stop %s", address)
+}
+
+// latestAgentSession returns the most recent session whose beacon names
+// address exactly.
+func latestAgentSession(address string) (*claude.SessionInfo, error) {
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{GasTownOnly: true, Role: address})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sessions {
+		if strings.EqualFold(s.Role, address) {
+			return &s, nil
+		}
+	}
+	return nil, fmt.Errorf("no sessions found for %s", address)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFeedKillArgs(t *testing.T) {
+	tests := []struct {
+		address string
+		want    []string
+	}{
+		{"mayor", []string{"mayor", "stop"}},
+		{"deacon", []string{"deacon", "stop"}},
+		{"gastown/witness", []string{"witness", "stop", "gastown"}},
+		{"gastown/refinery", []string{"refinery", "stop", "gastown"}},
+		{"gastown/crew/max", []string{"crew", "stop", "gastown/max"}},
+		{"gastown/polecats/toast", []string{"session", "stop", "gastown/toast"}},
+	}
+	for _, tt := range tests {
+		got, err := feedKillArgs(tt.address)
+		if err != nil {
+			t.Errorf("feedKillArgs(%q): %v", tt.address, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("feedKillArgs(%q) = %v, want %v", tt.address, got, tt.want)
+		}
+	}
+
+	if _, err := feedKillArgs("gastown/polecats"); err == nil {
+		t.Error("feedKillArgs of a polecat without a name should fail")
+	}
+}
@@ -0,0 +1,522 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Result types returned by gt find.
+const (
+	findTypeSession    = "session"
+	findTypeMail       = "mail"
+	findTypeHandoff    = "handoff"
+	findTypeAssignment = "assignment"
+	findTypeNote       = "note"
+)
+
+// findTypes lists every result type in display order.
+var findTypes = []string{findTypeSession, findTypeMail, findTypeHandoff, findTypeAssignment, findTypeNote}
+
+// findSnippetWidth is how much context surrounds a match in snippets.
+const findSnippetWidth = 60
+
+var (
+	findTypeFilter string
+	findRig        string
+	findSince      string
+	findLimit      int
+	findTag        string
+	findSubagents  bool
+	findJSON       bool
+)
+
+var findCmd = &cobra.Command{
+	Use:     "find <query>",
+	GroupID: GroupDiag,
+	Short:   "Search sessions, mail, handoffs, assignments, and notes",
+	Args:    cobra.ExactArgs(1),
+	Long: `Search everything Gas Town knows about in one place.
+
+Matches are case-insensitive literal text. Each result is typed and comes
+with the command that opens it:
+
+  session     Agent session transcripts    (gt seance tail <id>)
+  mail        Your inbox and archive       (gt mail read <id>)
+  handoff     Handoff mail                 (gt mail read <id>)
+  assignment  Assigned beads in every rig  (bd show <id>)
+  note        Notes imported by CLAUDE.md  (file path)
+
+Examples:
+  gt find "merge queue"
+  gt find flaky --type session,handoff
+  gt find auth --rig gastown --since 7d
+  gt find retry --tag experiment     # Sessions auto-tagged by session_tags rules
+  gt find "race" --subagents         # Also search Task subagent transcripts
+  gt find gt-abc --json`,
+	RunE: runFind,
+}
+
+func init() {
+	findCmd.Flags().StringVar(&findTypeFilter, "type", "", "Comma-separated result types (session,mail,handoff,assignment,note)")
+	findCmd.Flags().StringVar(&findRig, "rig", "", "Only search this rig's sessions, assignments, and notes")
+	findCmd.Flags().StringVar(&findSince, "since", "30d", "Only search sessions modified within this window")
+	findCmd.Flags().IntVarP(&findLimit, "limit", "n", 20, "Maximum results per type (0 = unlimited)")
+	findCmd.Flags().StringVar(&findTag, "tag", "", "Only search sessions with this tag (implies --type session)")
+	findCmd.Flags().BoolVar(&findSubagents, "subagents", false, "Also search subagent transcripts, attributed to the session that spawned them")
+	findCmd.Flags().BoolVar(&findJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(findCmd)
+}
+
+// findResult is one match from any searchable subsystem.
+type findResult struct {
+	Type    string    `json:"type"`
+	ID      string    `json:"id"`
+	Title   string    `json:"title"`
+	Snippet string    `json:"snippet,omitempty"`
+	Owner   string    `json:"owner,omitempty"` // agent, assignee, or rig
+	Tags    []string  `json:"tags,omitempty"`  // session tags
+	Time    time.Time `json:"time,omitempty"`
+	Open    string    `json:"open"` // command or path that opens the result
+}
+
+func runFind(cmd *cobra.Command, args []string) error {
+	query := strings.TrimSpace(args[0])
+	if query == "" {
+		return fmt.Errorf("query is required")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	typeFilter := findTypeFilter
+	if findTag != "" && typeFilter == "" {
+		typeFilter = findTypeSession
+	}
+	types, err := parseFindTypes(typeFilter)
+	if err != nil {
+		return err
+	}
+	tagger, err := loadTownSettingsQuiet(townRoot).SessionTagger()
+	if err != nil {
+		return err
+	}
+	since, err := parseDuration(findSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+
+	var results []findResult
+	var warnings []string
+	collect := func(name string, found []findResult, err error) {
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", name, err))
+		}
+		results = append(results, found...)
+	}
+
+	if types[findTypeSession] {
+		filter := claude.SessionFilter{GasTownOnly: true, Rig: findRig, Tagger: tagger, Tag: findTag, IncludeSubagents: findSubagents}
+		found, err := findSessions(query, filter, time.Now().Add(-since))
+		collect("sessions", found, err)
+	}
+	if types[findTypeMail] || types[findTypeHandoff] {
+		found, err := findMail(townRoot, query)
+		collect("mail", found, err)
+	}
+	if types[findTypeAssignment] {
+		found, err := findAssignments(townRoot, query, findRig)
+		collect("assignments", found, err)
+	}
+	if types[findTypeNote] {
+		found, err := findNotes(townRoot, query, findRig)
+		collect("notes", found, err)
+	}
+
+	results = limitFindResults(filterFindResults(results, types), findLimit)
+
+	if findJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "%s %s\n", style.WarningPrefix, w)
+	}
+
+	fmt.Printf("%s Results for %q: %d\n", style.Bold.Render("🔍"), query, len(results))
+	if len(results) == 0 {
+		fmt.Printf("\n  %s\n", style.Dim.Render("(no matches)"))
+		return nil
+	}
+
+	currentType := ""
+	for _, r := range results {
+		if r.Type != currentType {
+			currentType = r.Type
+			fmt.Printf("\n%s\n", style.Bold.Render(strings.ToUpper(currentType)))
+		}
+		owner := ""
+		if r.Owner != "" {
+			owner = "  " + style.Dim.Render(r.Owner)
+		}
+		if len(r.Tags) > 0 {
+			owner += "  " + style.Dim.Render("#"+strings.Join(r.Tags, " #"))
+		}
+		fmt.Printf("  %s%s\n", r.Title, owner)
+		if r.Snippet != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(r.Snippet))
+		}
+		fmt.Printf("    → %s\n", r.Open)
+	}
+	return nil
+}
+
+// parseFindTypes turns the --type flag into a set. Empty means all types.
+func parseFindTypes(s string) (map[string]bool, error) {
+	types := make(map[string]bool)
+	if strings.TrimSpace(s) == "" {
+		for _, t := range findTypes {
+			types[t] = true
+		}
+		return types, nil
+	}
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(strings.ToLower(t))
+		if t == "" {
+			continue
+		}
+		valid := false
+		for _, known := range findTypes {
+			if t == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown result type %q (valid: %s)", t, strings.Join(findTypes, ", "))
+		}
+		types[t] = true
+	}
+	return types, nil
+}
+
+// filterFindResults drops results whose type wasn't requested.
+func filterFindResults(results []findResult, types map[string]bool) []findResult {
+	filtered := results[:0]
+	for _, r := range results {
+		if types[r.Type] {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// limitFindResults groups results by type in display order, newest first
+// within each type, keeping at most limit per type (0 = unlimited).
+func limitFindResults(results []findResult, limit int) []findResult {
+	order := make(map[string]int, len(findTypes))
+	for i, t := range findTypes {
+		order[t] = i
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Type != results[j].Type {
+			return order[results[i].Type] < order[results[j].Type]
+		}
+		return results[i].Time.After(results[j].Time)
+	})
+
+	if limit <= 0 {
+		return results
+	}
+	counts := make(map[string]int)
+	limited := results[:0]
+	for _, r := range results {
+		if counts[r.Type] < limit {
+			counts[r.Type]++
+			limited = append(limited, r)
+		}
+	}
+	return limited
+}
+
+// findSnippet returns the text around the first case-insensitive match of
+// query in text, flattened to one line, or "" if there is no match.
+func findSnippet(text, query string) string {
+	lowerText := strings.ToLower(text)
+	idx := strings.Index(lowerText, strings.ToLower(query))
+	if idx < 0 {
+		return ""
+	}
+
+	start := idx - findSnippetWidth
+	if start < 0 {
+		start = 0
+	}
+	end := idx + len(query) + findSnippetWidth
+	if end > len(text) {
+		end = len(text)
+	}
+	// Avoid splitting multi-byte characters at the edges
+	for start > 0 && !isRuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !isRuneStart(text[end]) {
+		end++
+	}
+
+	snippet := strings.Join(strings.Fields(text[start:end]), " ")
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// isRuneStart reports whether b begins a UTF-8 encoded rune.
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// findSessions searches the turns of Gas Town sessions modified since cutoff.
+func findSessions(query string, filter claude.SessionFilter, cutoff time.Time) ([]findResult, error) {
+	sessions, err := discoverClaudeSessions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []findResult
+	for _, s := range sessions {
+		stat, err := os.Stat(s.Path)
+		if err != nil || stat.ModTime().Before(cutoff) {
+			continue
+		}
+		turns, err := claude.ReadTurns(s.Path)
+		if err != nil {
+			continue
+		}
+		for _, turn := range turns {
+			snippet := findSnippet(turn.Text, query)
+			if snippet == "" {
+				continue
+			}
+			title := s.Summary
+			if title == "" {
+				title = s.Topic
+			}
+			if title == "" {
+				title = "session " + s.ShortID()
+			}
+			open := "gt seance tail " + s.ShortID()
+			if s.ParentID != "" {
+				// Subagent transcripts are shown inline in their parent
+				parent := claude.SessionInfo{ID: s.ParentID}.ShortID()
+				title = fmt.Sprintf("subagent of %s: %s", parent, title)
+				open = "gt seance show " + parent
+			}
+			results = append(results, findResult{
+				Type:    findTypeSession,
+				ID:      s.ID,
+				Title:   title,
+				Snippet: snippet,
+				Owner:   s.Role,
+				Tags:    s.Tags,
+				Time:    turn.Timestamp,
+				Open:    open,
+			})
+			break // One result per session
+		}
+	}
+	return results, nil
+}
+
+// findMail searches the current agent's inbox and archive. Handoff mail is
+// reported as its own type.
+func findMail(townRoot, query string) ([]findResult, error) {
+	mailbox, err := mail.NewRouter(townRoot).GetMailbox(detectSender())
+	if err != nil {
+		return nil, err
+	}
+	messages, err := mailbox.Search(mail.SearchOptions{Query: query})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]findResult, 0, len(messages))
+	for _, msg := range messages {
+		resultType := findTypeMail
+		if strings.Contains(msg.Subject, "HANDOFF") {
+			resultType = findTypeHandoff
+		}
+		results = append(results, findResult{
+			Type:    resultType,
+			ID:      msg.ID,
+			Title:   msg.Subject,
+			Snippet: findSnippet(msg.Body, query),
+			Owner:   msg.From,
+			Time:    msg.Timestamp,
+			Open:    "gt mail read " + msg.ID,
+		})
+	}
+	return results, nil
+}
+
+// findAssignments searches assigned beads in the town and every rig.
+func findAssignments(townRoot, query, rig string) ([]findResult, error) {
+	var dirs []string
+	if rig == "" {
+		dirs = append(dirs, townRoot)
+	}
+	for _, name := range findRigNames(townRoot, rig) {
+		dirs = append(dirs, filepath.Join(townRoot, name))
+	}
+
+	var results []findResult
+	var firstErr error
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		issues, err := beads.New(beads.ResolveBeadsDir(dir)).List(beads.ListOptions{Status: "all", Priority: -1})
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, issue := range issues {
+			if issue.Assignee == "" || seen[issue.ID] {
+				continue
+			}
+			snippet := findSnippet(issue.Title, query)
+			if snippet == "" {
+				snippet = findSnippet(issue.Description, query)
+			}
+			if snippet == "" && !strings.EqualFold(issue.ID, query) {
+				continue
+			}
+			seen[issue.ID] = true
+			updated, _ := time.Parse(time.RFC3339, issue.UpdatedAt)
+			results = append(results, findResult{
+				Type:    findTypeAssignment,
+				ID:      issue.ID,
+				Title:   fmt.Sprintf("%s: %s [%s]", issue.ID, issue.Title, issue.Status),
+				Snippet: snippet,
+				Owner:   issue.Assignee,
+				Time:    updated,
+				Open:    "bd show " + issue.ID,
+			})
+		}
+	}
+	return results, firstErr
+}
+
+// findNotes searches markdown files imported by the town's and rigs'
+// CLAUDE.md files (e.g., notes written by gt distill --link).
+func findNotes(townRoot, query, rig string) ([]findResult, error) {
+	var claudeMDs []string
+	if rig == "" {
+		claudeMDs = append(claudeMDs, filepath.Join(townRoot, "CLAUDE.md"))
+	}
+	for _, name := range findRigNames(townRoot, rig) {
+		claudeMDs = append(claudeMDs, filepath.Join(townRoot, name, "CLAUDE.md"))
+	}
+
+	var results []findResult
+	seen := make(map[string]bool)
+	for _, claudeMD := range claudeMDs {
+		for _, notesPath := range claudeMDImports(claudeMD) {
+			if seen[notesPath] {
+				continue
+			}
+			seen[notesPath] = true
+
+			data, err := os.ReadFile(notesPath) //nolint:gosec // G304: path comes from a CLAUDE.md import
+			if err != nil {
+				continue
+			}
+			snippet := findSnippet(string(data), query)
+			if snippet == "" {
+				continue
+			}
+			var modTime time.Time
+			if stat, err := os.Stat(notesPath); err == nil {
+				modTime = stat.ModTime()
+			}
+			rel, err := filepath.Rel(townRoot, notesPath)
+			if err != nil {
+				rel = notesPath
+			}
+			results = append(results, findResult{
+				Type:    findTypeNote,
+				ID:      rel,
+				Title:   rel,
+				Snippet: snippet,
+				Time:    modTime,
+				Open:    notesPath,
+			})
+		}
+	}
+	return results, nil
+}
+
+// claudeMDImports returns the absolute paths of files imported with
+// "@path" lines in a CLAUDE.md. A missing file has no imports.
+func claudeMDImports(claudeMD string) []string {
+	data, err := os.ReadFile(claudeMD) //nolint:gosec // G304: CLAUDE.md within the town
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "@") || strings.ContainsAny(line, " \t") {
+			continue
+		}
+		path := filepath.FromSlash(strings.TrimPrefix(line, "@"))
+		if strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(claudeMD), path)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// findRigNames returns the registered rigs to search: just rig if set,
+// otherwise all of them in name order.
+func findRigNames(townRoot, rig string) []string {
+	if rig != "" {
+		return []string{rig}
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
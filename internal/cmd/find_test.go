@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindSnippet(t *testing.T) {
+	text := strings.Repeat("a ", 50) + "the Merge Queue is stuck\nagain " + strings.Repeat("b ", 50)
+
+	snippet := findSnippet(text, "merge queue")
+	if !strings.Contains(snippet, "Merge Queue is stuck again") {
+		t.Errorf("snippet = %q, want match with newline flattened", snippet)
+	}
+	if !strings.HasPrefix(snippet, "…") || !strings.HasSuffix(snippet, "…") {
+		t.Errorf("snippet = %q, want ellipses on both sides", snippet)
+	}
+
+	if got := findSnippet("short text", "short"); got != "short text" {
+		t.Errorf("findSnippet(short) = %q, want whole text", got)
+	}
+	if got := findSnippet("nothing here", "merge"); got != "" {
+		t.Errorf("findSnippet(no match) = %q, want empty", got)
+	}
+}
+
+func TestParseFindTypes(t *testing.T) {
+	all, err := parseFindTypes("")
+	if err != nil {
+		t.Fatalf("parseFindTypes(\"\"): %v", err)
+	}
+	if len(all) != len(findTypes) {
+		t.Errorf("empty filter selected %d types, want %d", len(all), len(findTypes))
+	}
+
+	some, err := parseFindTypes("Session, handoff")
+	if err != nil {
+		t.Fatalf("parseFindTypes: %v", err)
+	}
+	if !some[findTypeSession] || !some[findTypeHandoff] || some[findTypeMail] {
+		t.Errorf("parseFindTypes = %v", some)
+	}
+
+	if _, err := parseFindTypes("session,bogus"); err == nil {
+		t.Error("expected error for unknown type")
+	}
+}
+
+func TestLimitFindResults(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	results := []findResult{
+		{Type: findTypeNote, ID: "n1", Time: base},
+		{Type: findTypeSession, ID: "s1", Time: base},
+		{Type: findTypeSession, ID: "s2", Time: base.Add(time.Hour)},
+		{Type: findTypeSession, ID: "s3", Time: base.Add(2 * time.Hour)},
+		{Type: findTypeMail, ID: "m1", Time: base},
+	}
+
+	got := limitFindResults(results, 2)
+	var ids []string
+	for _, r := range got {
+		ids = append(ids, r.ID)
+	}
+	want := "s3,s2,m1,n1"
+	if strings.Join(ids, ",") != want {
+		t.Errorf("limitFindResults = %s, want %s", strings.Join(ids, ","), want)
+	}
+}
+
+func TestFindNotes(t *testing.T) {
+	townRoot := t.TempDir()
+	notesDir := filepath.Join(townRoot, "notes")
+	if err := os.MkdirAll(notesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(notesDir, "agents.md"), []byte("# Notes\n\nRun the flaky e2e suite twice.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	claudeMD := "# Town\n\n## Agent notes\n\n@notes/agents.md\n@notes/missing.md\nEmail me @ noon\n"
+	if err := os.WriteFile(filepath.Join(townRoot, "CLAUDE.md"), []byte(claudeMD), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	imports := claudeMDImports(filepath.Join(townRoot, "CLAUDE.md"))
+	if len(imports) != 2 {
+		t.Fatalf("claudeMDImports = %v, want 2 imports", imports)
+	}
+
+	results, err := findNotes(townRoot, "FLAKY", "")
+	if err != nil {
+		t.Fatalf("findNotes: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1: %+v", len(results), results)
+	}
+	r := results[0]
+	if r.Type != findTypeNote || r.ID != filepath.Join("notes", "agents.md") {
+		t.Errorf("result = %+v", r)
+	}
+	if !strings.Contains(r.Snippet, "flaky e2e") {
+		t.Errorf("Snippet = %q", r.Snippet)
+	}
+}
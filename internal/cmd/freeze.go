@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wisp"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Wisp config keys for a rig freeze window.
+const (
+	RigFreezeSinceKey  = "freeze_since"
+	RigFreezeUntilKey  = "freeze_until"
+	RigFreezeReasonKey = "freeze_reason"
+	RigFreezeByKey     = "freeze_by"
+)
+
+var (
+	freezeRig    string
+	freezeUntil  string
+	freezeReason string
+	freezeQuiet  bool
+	thawRig      string
+	thawQuiet    bool
+)
+
+var freezeCmd = &cobra.Command{
+	Use:     "freeze",
+	GroupID: GroupWork,
+	Short:   "Freeze a rig: block spawning and assigning work",
+	Long: `Freeze a rig for a release window, when no agent should touch the repo.
+
+While a rig is frozen:
+  - gt sling refuses to assign work to the rig or its agents
+  - no new polecats are spawned in the rig
+  - agents priming in the rig are told about the freeze
+
+Freezing mails every agent in the rig so sessions already running stop
+at a safe point. The freeze lifts on its own at --until, or early with
+'gt thaw'. Without --until it lasts until thawed.
+
+--until accepts a time of day (18:00, rolled to tomorrow if already
+past), a duration (2h, 1d), or a timestamp (2025-06-01T18:00:00Z).
+
+This is a local (wisp-layer) setting, like 'gt rig park'.
+
+Run without --rig to list frozen rigs.
+
+Examples:
+  gt freeze --rig gastown --until 18:00 --reason "release"
+  gt freeze --rig beads --until 2h
+  gt freeze`,
+	Args: cobra.NoArgs,
+	RunE: runFreeze,
+}
+
+var thawCmd = &cobra.Command{
+	Use:     "thaw",
+	GroupID: GroupWork,
+	Short:   "Lift a rig freeze",
+	Long: `Lift a freeze set with 'gt freeze' before its window ends.
+
+Agents in the rig are mailed that work can resume.
+
+Examples:
+  gt thaw --rig gastown`,
+	Args: cobra.NoArgs,
+	RunE: runThaw,
+}
+
+func init() {
+	freezeCmd.Flags().StringVar(&freezeRig, "rig", "", "Rig to freeze")
+	freezeCmd.Flags().StringVar(&freezeUntil, "until", "", "When the freeze lifts (18:00, 2h, or a timestamp)")
+	freezeCmd.Flags().StringVar(&freezeReason, "reason", "", "Why the rig is frozen (shown to agents)")
+	freezeCmd.Flags().BoolVarP(&freezeQuiet, "quiet", "q", false, "Don't mail the rig's agents")
+
+	thawCmd.Flags().StringVar(&thawRig, "rig", "", "Rig to thaw (required)")
+	thawCmd.Flags().BoolVarP(&thawQuiet, "quiet", "q", false, "Don't mail the rig's agents")
+	_ = thawCmd.MarkFlagRequired("rig")
+
+	rootCmd.AddCommand(freezeCmd)
+	rootCmd.AddCommand(thawCmd)
+}
+
+// RigFreeze is an active freeze window on a rig.
+type RigFreeze struct {
+	Rig    string
+	Reason string
+	By     string
+	Since  time.Time
+	Until  time.Time // Zero until thawed
+}
+
+// describe returns a one-line description of the freeze window.
+func (f *RigFreeze) describe() string {
+	s := "frozen"
+	if !f.Until.IsZero() {
+		s += " until " + formatFreezeTime(f.Until, time.Now())
+	} else {
+		s += " until thawed"
+	}
+	if f.Reason != "" {
+		s += " (" + f.Reason + ")"
+	}
+	return s
+}
+
+func runFreeze(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if freezeRig == "" {
+		if freezeUntil != "" || freezeReason != "" {
+			return fmt.Errorf("--rig is required to freeze a rig")
+		}
+		return listRigFreezes(townRoot)
+	}
+
+	if _, _, err := getRig(freezeRig); err != nil {
+		return err
+	}
+	now := time.Now()
+	until, err := parseFreezeUntil(freezeUntil, now)
+	if err != nil {
+		return err
+	}
+
+	by := detectSender()
+	wispCfg := wisp.NewConfig(townRoot, freezeRig)
+	values := map[string]string{
+		RigFreezeSinceKey:  now.UTC().Format(time.RFC3339),
+		RigFreezeUntilKey:  "",
+		RigFreezeReasonKey: freezeReason,
+		RigFreezeByKey:     by,
+	}
+	if !until.IsZero() {
+		values[RigFreezeUntilKey] = until.UTC().Format(time.RFC3339)
+	}
+	for key, value := range values {
+		if err := wispCfg.Set(key, value); err != nil {
+			return fmt.Errorf("setting freeze: %w", err)
+		}
+	}
+
+	freeze := GetRigFreeze(townRoot, freezeRig)
+	fmt.Printf("%s Rig %s %s\n", style.SuccessPrefix, style.Bold.Render(freezeRig), freeze.describe())
+	fmt.Printf("  Sling and polecat spawn are blocked; lift early with %s\n", style.Dim.Render("gt thaw --rig "+freezeRig))
+
+	if !freezeQuiet {
+		body := fmt.Sprintf("Rig %s is %s.\n\nFinish or pause at a safe point. Do not push, merge, or start new work in this rig until the freeze lifts.", freezeRig, freeze.describe())
+		notifyRigAgents(townRoot, freezeRig, by, "🧊 FREEZE: "+freezeRig+" "+freeze.describe(), body)
+	}
+	return nil
+}
+
+func runThaw(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	freeze := GetRigFreeze(townRoot, thawRig)
+	if err := clearRigFreeze(townRoot, thawRig); err != nil {
+		return err
+	}
+	if freeze == nil {
+		fmt.Printf("%s Rig %s was not frozen\n", style.Dim.Render("○"), thawRig)
+		return nil
+	}
+
+	fmt.Printf("%s Rig %s thawed\n", style.SuccessPrefix, style.Bold.Render(thawRig))
+	if !thawQuiet {
+		notifyRigAgents(townRoot, thawRig, detectSender(), "☀️ THAW: "+thawRig+" is open for work",
+			fmt.Sprintf("The freeze on rig %s has been lifted. Normal work can resume.", thawRig))
+	}
+	return nil
+}
+
+// listRigFreezes prints every registered rig that is currently frozen.
+func listRigFreezes(townRoot string) error {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	found := false
+	for _, name := range names {
+		freeze := GetRigFreeze(townRoot, name)
+		if freeze == nil {
+			continue
+		}
+		found = true
+		fmt.Printf("🧊 %s  %s", style.Bold.Render(name), freeze.describe())
+		if freeze.By != "" {
+			fmt.Printf("  %s", style.Dim.Render("by "+freeze.By))
+		}
+		fmt.Println()
+	}
+	if !found {
+		fmt.Println(style.Dim.Render("No frozen rigs."))
+	}
+	return nil
+}
+
+// GetRigFreeze returns the rig's active freeze, or nil if it isn't frozen.
+// A freeze whose window has passed is cleared.
+func GetRigFreeze(townRoot, rigName string) *RigFreeze {
+	if townRoot == "" || rigName == "" {
+		return nil
+	}
+	wispCfg := wisp.NewConfig(townRoot, rigName)
+	since, err := time.Parse(time.RFC3339, wispCfg.GetString(RigFreezeSinceKey))
+	if err != nil {
+		return nil
+	}
+	freeze := &RigFreeze{
+		Rig:    rigName,
+		Reason: wispCfg.GetString(RigFreezeReasonKey),
+		By:     wispCfg.GetString(RigFreezeByKey),
+		Since:  since,
+	}
+	if until := wispCfg.GetString(RigFreezeUntilKey); until != "" {
+		freeze.Until, _ = time.Parse(time.RFC3339, until)
+		if !freeze.Until.IsZero() && !time.Now().Before(freeze.Until) {
+			_ = clearRigFreeze(townRoot, rigName)
+			return nil
+		}
+	}
+	return freeze
+}
+
+// clearRigFreeze removes a rig's freeze window.
+func clearRigFreeze(townRoot, rigName string) error {
+	wispCfg := wisp.NewConfig(townRoot, rigName)
+	for _, key := range []string{RigFreezeSinceKey, RigFreezeUntilKey, RigFreezeReasonKey, RigFreezeByKey} {
+		if err := wispCfg.Unset(key); err != nil {
+			return fmt.Errorf("clearing freeze: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkRigFreeze returns an error if the rig is frozen.
+func checkRigFreeze(townRoot, rigName string) error {
+	freeze := GetRigFreeze(townRoot, rigName)
+	if freeze == nil {
+		return nil
+	}
+	return fmt.Errorf("rig %s is %s\nUse 'gt thaw --rig %s' to lift the freeze", rigName, freeze.describe(), rigName)
+}
+
+// checkSlingFreeze blocks slinging to a frozen rig or to an agent in one.
+// An empty or "." target means the current agent.
+func checkSlingFreeze(townRoot, target string) error {
+	if target == "" || target == "." {
+		target = detectSender()
+	}
+	rigName, isRig := IsRigName(target)
+	if !isRig {
+		rigName, _, _ = strings.Cut(strings.TrimSuffix(target, "/"), "/")
+		if rigName == "mayor" || rigName == "deacon" || !strings.Contains(target, "/") {
+			return nil
+		}
+	}
+	return checkRigFreeze(townRoot, rigName)
+}
+
+// notifyRigAgents mails every agent in a rig, best-effort.
+func notifyRigAgents(townRoot, rigName, from, subject, body string) {
+	msg := mail.NewMessage(from, "@rig/"+rigName, subject, body)
+	msg.Priority = mail.PriorityHigh
+	if err := mail.NewRouterWithTownRoot(townRoot, townRoot).Send(msg); err != nil {
+		fmt.Printf("  %s Could not mail agents: %v\n", style.WarningPrefix, err)
+		return
+	}
+	fmt.Printf("  Mailed agents in %s\n", rigName)
+}
+
+// outputRigFreezeNotice tells an agent priming in a frozen rig to stand down.
+func outputRigFreezeNotice(ctx RoleContext) {
+	freeze := GetRigFreeze(ctx.TownRoot, ctx.Rig)
+	if freeze == nil {
+		return
+	}
+	fmt.Println()
+	fmt.Printf("%s\n\n", style.Bold.Render("## 🧊 Rig Frozen"))
+	fmt.Printf("Rig %s is %s.\n", ctx.Rig, freeze.describe())
+	fmt.Println("Do not push, merge, or start new work in this rig until the freeze lifts.")
+}
+
+// parseFreezeUntil parses --until: a time of day (HH:MM, rolled to the
+// next day if already past), a duration, or a timestamp. Empty means no
+// end time.
+func parseFreezeUntil(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if clock, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if d, err := parseDuration(s); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --until %q (use 18:00, 2h, or 2006-01-02T15:04:05Z)", s)
+}
+
+// formatFreezeTime formats t as a time of day, with the date if it isn't today.
+func formatFreezeTime(t, now time.Time) string {
+	t = t.Local()
+	if y, m, d := t.Date(); y == now.Year() && m == now.Month() && d == now.Day() {
+		return t.Format("15:04")
+	}
+	return t.Format("Jan 2 15:04")
+}
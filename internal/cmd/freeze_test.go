@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+func TestParseFreezeUntil(t *testing.T) {
+	now := time.Date(2025, 6, 1, 15, 0, 0, 0, time.Local)
+
+	got, err := parseFreezeUntil("18:00", now)
+	if err != nil || !got.Equal(time.Date(2025, 6, 1, 18, 0, 0, 0, time.Local)) {
+		t.Errorf("18:00 = %v, %v", got, err)
+	}
+	got, err = parseFreezeUntil("09:30", now)
+	if err != nil || !got.Equal(time.Date(2025, 6, 2, 9, 30, 0, 0, time.Local)) {
+		t.Errorf("past time of day should roll to tomorrow: %v, %v", got, err)
+	}
+	got, err = parseFreezeUntil("2h", now)
+	if err != nil || !got.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("2h = %v, %v", got, err)
+	}
+	got, err = parseFreezeUntil("", now)
+	if err != nil || !got.IsZero() {
+		t.Errorf("empty = %v, %v", got, err)
+	}
+	if _, err := parseFreezeUntil("after lunch", now); err == nil {
+		t.Error("expected error for unparseable --until")
+	}
+}
+
+func TestRigFreezeLifecycle(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := wisp.NewConfig(townRoot, "gastown")
+	set := func(key, value string) {
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	set(RigFreezeSinceKey, time.Now().Add(-time.Hour).UTC().Format(time.RFC3339))
+	set(RigFreezeUntilKey, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	set(RigFreezeReasonKey, "release")
+
+	freeze := GetRigFreeze(townRoot, "gastown")
+	if freeze == nil || freeze.Reason != "release" {
+		t.Fatalf("GetRigFreeze = %+v", freeze)
+	}
+	err := checkSlingFreeze(townRoot, "gastown/crew/max")
+	if err == nil || !strings.Contains(err.Error(), "release") {
+		t.Errorf("sling to frozen rig's crew: %v", err)
+	}
+	if err := checkSlingFreeze(townRoot, "beads/crew/max"); err != nil {
+		t.Errorf("sling to other rig: %v", err)
+	}
+	if err := checkSlingFreeze(townRoot, "mayor/"); err != nil {
+		t.Errorf("sling to mayor: %v", err)
+	}
+
+	// A window that has passed lifts itself.
+	set(RigFreezeUntilKey, time.Now().Add(-time.Minute).UTC().Format(time.RFC3339))
+	if freeze := GetRigFreeze(townRoot, "gastown"); freeze != nil {
+		t.Errorf("expired freeze still active: %+v", freeze)
+	}
+	if cfg.GetString(RigFreezeSinceKey) != "" {
+		t.Error("expired freeze was not cleared")
+	}
+}
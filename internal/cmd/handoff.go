@@ -8,9 +8,11 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -41,8 +43,9 @@ Examples:
   gt handoff crew                     # Hand off crew session
   gt handoff mayor                    # Hand off mayor session
 
-The --collect (-c) flag gathers current state (hooked work, inbox, ready beads,
-in-progress items) and includes it in the handoff mail. This provides context
+The --collect (-c) flag gathers current state (hooked work, this session's
+task list, inbox, ready beads, in-progress items) and includes it in the
+handoff mail. This provides context
 for the next session without manual summarization.
 
 Any molecule on the hook will be auto-continued by the new session.
@@ -413,7 +416,7 @@ func sessionWorkDir(sessionName, townRoot string) (string, error) {
 		return townRoot, nil
 
 	case sessionName == deaconSession:
-		return townRoot + "/deacon", nil
+		return filepath.Join(townRoot, "deacon"), nil
 
 	case strings.Contains(sessionName, "-crew-"):
 		// gt-<rig>-crew-<name> -> <townRoot>/<rig>/crew/<name>
@@ -426,7 +429,7 @@ func sessionWorkDir(sessionName, townRoot string) (string, error) {
 			if p == "crew" && i > 1 && i < len(parts)-1 {
 				rig := strings.Join(parts[1:i], "-")
 				name := strings.Join(parts[i+1:], "-")
-				return fmt.Sprintf("%s/%s/crew/%s", townRoot, rig, name), nil
+				return filepath.Join(townRoot, rig, "crew", name), nil
 			}
 		}
 		return "", fmt.Errorf("cannot parse crew session name: %s", sessionName)
@@ -435,13 +438,13 @@ func sessionWorkDir(sessionName, townRoot string) (string, error) {
 		// gt-<rig>-witness -> <townRoot>/<rig>/witness/rig
 		rig := strings.TrimPrefix(sessionName, "gt-")
 		rig = strings.TrimSuffix(rig, "-witness")
-		return fmt.Sprintf("%s/%s/witness/rig", townRoot, rig), nil
+		return filepath.Join(townRoot, rig, "witness", "rig"), nil
 
 	case strings.HasSuffix(sessionName, "-refinery"):
 		// gt-<rig>-refinery -> <townRoot>/<rig>/refinery/rig
 		rig := strings.TrimPrefix(sessionName, "gt-")
 		rig = strings.TrimSuffix(rig, "-refinery")
-		return fmt.Sprintf("%s/%s/refinery/rig", townRoot, rig), nil
+		return filepath.Join(townRoot, rig, "refinery", "rig"), nil
 
 	default:
 		return "", fmt.Errorf("unknown session type: %s (try specifying role explicitly)", sessionName)
@@ -710,6 +713,12 @@ func collectHandoffState() string {
 		}
 	}
 
+	// Get this session's task list, so the successor sees what was
+	// planned and what is left
+	if plan := currentSessionPlan(); plan != nil {
+		parts = append(parts, "## Plan\n"+formatPlanMarkdown(plan))
+	}
+
 	// Get inbox summary (first few messages)
 	inboxOutput, err := exec.Command("gt", "mail", "inbox").Output()
 	if err == nil {
@@ -757,3 +766,40 @@ func collectHandoffState() string {
 
 	return strings.Join(parts, "\n\n")
 }
+
+// currentSessionPlan returns the task list of the Claude session running
+// this command, or nil if the session can't be found or kept none.
+func currentSessionPlan() *claude.Plan {
+	id := runtime.SessionIDFromEnv()
+	if id == "" {
+		id = ReadPersistedSessionID()
+	}
+	if id == "" {
+		return nil
+	}
+	session, err := claude.FindSession(id)
+	if err != nil {
+		return nil
+	}
+	plan, err := claude.ReadPlan(session.Path)
+	if err != nil {
+		return nil
+	}
+	return plan
+}
+
+// formatPlanMarkdown renders a plan as a Markdown checklist.
+func formatPlanMarkdown(plan *claude.Plan) string {
+	lines := []string{fmt.Sprintf("%d/%d done", plan.Completed, len(plan.Items))}
+	for _, it := range plan.Items {
+		switch it.State {
+		case claude.PlanCompleted:
+			lines = append(lines, "- [x] "+it.Content)
+		case claude.PlanInProgress:
+			lines = append(lines, "- [ ] "+it.Content+" (in progress)")
+		default:
+			lines = append(lines, "- [ ] "+it.Content)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
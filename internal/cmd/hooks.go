@@ -298,7 +298,7 @@ func outputHooksHuman(townRoot string, hooks []HookInfo) error {
 				matcherStr = fmt.Sprintf(" [%s]", h.Matcher)
 			}
 
-			fmt.Printf("  %s %-25s%s\n", statusIcon, h.Agent, style.Dim.Render(matcherStr))
+			fmt.Printf("  %s %-25s%s\n", statusIcon, compactAddress(h.Agent), style.Dim.Render(matcherStr))
 
 			if hooksVerbose {
 				for _, cmd := range h.Commands {
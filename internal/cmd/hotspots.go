@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	hotspotsSince string
+	hotspotsRig   string
+	hotspotsDirs  bool
+	hotspotsLimit int
+	hotspotsSubs  bool
+	hotspotsJSON  bool
+)
+
+var hotspotsCmd = &cobra.Command{
+	Use:     "hotspots",
+	GroupID: GroupDiag,
+	Short:   "Rank the files agents edit most",
+	Long: `Rank the files (or directories) agents edited most across sessions.
+
+Files edited again and again, by many sessions, are where agents churn:
+unclear code, flaky tests, or a task that keeps bouncing. They are also
+where human review pays off most.
+
+Paths are relative to each session's working directory, so the same file
+edited in different worktrees of a repo is counted together.
+
+With --subagents, edits made by Task subagents count too, attributed to
+the agent whose session spawned them.
+
+Examples:
+  gt hotspots
+  gt hotspots --since 14d --dirs
+  gt hotspots --rig gastown -n 50 --json`,
+	RunE: runHotspots,
+}
+
+func init() {
+	hotspotsCmd.Flags().StringVar(&hotspotsSince, "since", "14d", "Only count edits within this window (e.g., 7d, 72h)")
+	hotspotsCmd.Flags().StringVar(&hotspotsRig, "rig", "", "Only include sessions from this rig")
+	hotspotsCmd.Flags().BoolVar(&hotspotsDirs, "dirs", false, "Rank directories instead of files")
+	hotspotsCmd.Flags().IntVarP(&hotspotsLimit, "limit", "n", 20, "Maximum rows (0 = unlimited)")
+	hotspotsCmd.Flags().BoolVar(&hotspotsSubs, "subagents", false, "Include edits made by subagents")
+	hotspotsCmd.Flags().BoolVar(&hotspotsJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(hotspotsCmd)
+}
+
+// hotspot is one file or directory's edit totals.
+type hotspot struct {
+	Path     string    `json:"path"`
+	Edits    int       `json:"edits"`
+	Sessions int       `json:"sessions"`
+	Agents   []string  `json:"agents,omitempty"` // beacon roles, sorted
+	LastEdit time.Time `json:"last_edit"`
+}
+
+// sessionEdits pairs a session with the edits it made.
+type sessionEdits struct {
+	Session claude.SessionInfo
+	Edits   []claude.FileEdit
+}
+
+func runHotspots(cmd *cobra.Command, args []string) error {
+	window, err := parseDuration(hotspotsSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	since := time.Now().Add(-window)
+
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{GasTownOnly: true, Rig: hotspotsRig, IncludeSubagents: hotspotsSubs})
+	if err != nil {
+		return fmt.Errorf("discovering sessions: %w", err)
+	}
+	var edits []sessionEdits
+	for _, s := range sessions {
+		if stat, err := os.Stat(s.Path); err != nil || stat.ModTime().Before(since) {
+			continue
+		}
+		e, err := claude.ReadFileEdits(s.Path, since)
+		if err != nil || len(e) == 0 {
+			continue
+		}
+		edits = append(edits, sessionEdits{Session: s, Edits: e})
+	}
+
+	spots := rankHotspots(edits, hotspotsDirs)
+	if hotspotsLimit > 0 && len(spots) > hotspotsLimit {
+		spots = spots[:hotspotsLimit]
+	}
+
+	if hotspotsJSON {
+		if spots == nil {
+			spots = []hotspot{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(spots)
+	}
+
+	what := "files"
+	if hotspotsDirs {
+		what = "directories"
+	}
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Most-edited "+what), style.Dim.Render("(last "+hotspotsSince+")"))
+	if len(spots) == 0 {
+		fmt.Println(style.Dim.Render("No agent edits in this window."))
+		return nil
+	}
+	fmt.Printf("%5s  %8s  %6s  %9s  %s\n", "EDITS", "SESSIONS", "AGENTS", "LAST", "PATH")
+	for _, h := range spots {
+		fmt.Printf("%5d  %8d  %6d  %9s  %s\n",
+			h.Edits, h.Sessions, len(h.Agents), formatDuration(time.Since(h.LastEdit))+" ago", h.Path)
+	}
+	return nil
+}
+
+// rankHotspots totals edits per file (or per directory when dirs is set),
+// most edits first. Ties go to the path edited by more sessions.
+func rankHotspots(edits []sessionEdits, dirs bool) []hotspot {
+	type acc struct {
+		hotspot
+		sessions map[string]bool
+		agents   map[string]bool
+	}
+	byPath := make(map[string]*acc)
+	for _, se := range edits {
+		for _, e := range se.Edits {
+			p := e.RelPath
+			if dirs {
+				p = path.Dir(p)
+			}
+			a := byPath[p]
+			if a == nil {
+				a = &acc{hotspot: hotspot{Path: p}, sessions: map[string]bool{}, agents: map[string]bool{}}
+				byPath[p] = a
+			}
+			a.Edits++
+			a.sessions[hotspotSession(se.Session)] = true
+			if se.Session.Role != "" {
+				a.agents[se.Session.Role] = true
+			}
+			if e.Timestamp.After(a.LastEdit) {
+				a.LastEdit = e.Timestamp
+			}
+		}
+	}
+
+	var spots []hotspot
+	for _, a := range byPath {
+		h := a.hotspot
+		h.Sessions = len(a.sessions)
+		for agent := range a.agents {
+			h.Agents = append(h.Agents, agent)
+		}
+		sort.Strings(h.Agents)
+		spots = append(spots, h)
+	}
+	sort.Slice(spots, func(i, j int) bool {
+		if spots[i].Edits != spots[j].Edits {
+			return spots[i].Edits > spots[j].Edits
+		}
+		if spots[i].Sessions != spots[j].Sessions {
+			return spots[i].Sessions > spots[j].Sessions
+		}
+		return spots[i].Path < spots[j].Path
+	})
+	return spots
+}
+
+// hotspotSession is the session an edit counts toward: subagent edits
+// count toward the session that spawned the subagent.
+func hotspotSession(s claude.SessionInfo) string {
+	if s.ParentID != "" {
+		return s.ParentID
+	}
+	return s.ID
+}
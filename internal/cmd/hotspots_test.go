@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+func TestRankHotspots(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	edit := func(rel string, minutes int) claude.FileEdit {
+		return claude.FileEdit{RelPath: rel, Timestamp: base.Add(time.Duration(minutes) * time.Minute)}
+	}
+	edits := []sessionEdits{
+		{Session: claude.SessionInfo{ID: "s1", Role: "gastown/polecats/toast"},
+			Edits: []claude.FileEdit{edit("internal/cmd/seance.go", 0), edit("internal/cmd/seance.go", 5), edit("README.md", 1)}},
+		{Session: claude.SessionInfo{ID: "s2", Role: "gastown/crew/joe"},
+			Edits: []claude.FileEdit{edit("internal/cmd/seance.go", 30), edit("internal/cmd/find.go", 2)}},
+		{Session: claude.SessionInfo{ID: "s3"},
+			Edits: []claude.FileEdit{edit("internal/claude/edits.go", 3)}},
+	}
+
+	files := rankHotspots(edits, false)
+	if len(files) != 4 {
+		t.Fatalf("got %d files: %+v", len(files), files)
+	}
+	top := files[0]
+	if top.Path != "internal/cmd/seance.go" || top.Edits != 3 || top.Sessions != 2 ||
+		!top.LastEdit.Equal(base.Add(30*time.Minute)) ||
+		!reflect.DeepEqual(top.Agents, []string{"gastown/crew/joe", "gastown/polecats/toast"}) {
+		t.Errorf("top file = %+v", top)
+	}
+	if files[1].Path != "README.md" || len(files[2].Agents) != 0 {
+		t.Errorf("ties not broken by path: %+v", files)
+	}
+
+	dirs := rankHotspots(edits, true)
+	if dirs[0].Path != "internal/cmd" || dirs[0].Edits != 4 || dirs[0].Sessions != 2 {
+		t.Errorf("top dir = %+v", dirs[0])
+	}
+
+	// Subagent edits count toward the session that spawned them
+	withSub := rankHotspots(append(edits, sessionEdits{
+		Session: claude.SessionInfo{ID: "agent-a1", ParentID: "s1", Role: "gastown/polecats/toast"},
+		Edits:   []claude.FileEdit{edit("internal/cmd/seance.go", 40)},
+	}), false)
+	if withSub[0].Edits != 4 || withSub[0].Sessions != 2 {
+		t.Errorf("top file with subagent = %+v", withSub[0])
+	}
+}
@@ -12,6 +12,8 @@ var issueCmd = &cobra.Command{
 	Use:     "issue",
 	GroupID: GroupConfig,
 	Short:   "Manage current issue for status line display",
+	Long: `Manage the issue shown in the tmux status line, and report the agent
+time spent on an issue (gt issue time).`,
 }
 
 var issueSetCmd = &cobra.Command{
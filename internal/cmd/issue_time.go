@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var issueTimeJSON bool
+
+var issueTimeCmd = &cobra.Command{
+	Use:   "time <issue-id>",
+	Short: "Total the agent time and tokens spent on an issue",
+	Long: `Total the wall-clock time, tokens, and estimated cost of every session
+that worked on an issue, to answer how much a feature cost in agent time.
+
+A session worked on the issue when its Gas Town beacon names it (e.g.
+"assigned:gt-123"), and so did the sessions that continued it by handoff,
+resume, or compaction, up to one naming a different issue. Subagents'
+tokens count toward the session that spawned them.
+
+Agent time adds up each session's duration, so two agents working for an
+hour count two hours; elapsed is from the first session's start to the
+last one's end. A message repeated by a resumed session is counted once.
+Cost is estimated from the town's price table.
+
+Examples:
+  gt issue time gt-123
+  gt issue time gt-123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueTime,
+}
+
+func init() {
+	issueTimeCmd.Flags().BoolVar(&issueTimeJSON, "json", false, "Output as JSON")
+
+	issueCmd.AddCommand(issueTimeCmd)
+}
+
+// issueTimeTotals is time and usage summed over sessions.
+type issueTimeTotals struct {
+	Sessions     int           `json:"sessions"`
+	AgentTime    time.Duration `json:"agent_time"`
+	InputTokens  int64         `json:"input_tokens"`
+	OutputTokens int64         `json:"output_tokens"`
+	CacheTokens  int64         `json:"cache_tokens"`
+	TotalTokens  int64         `json:"total_tokens"`
+	Cost         float64       `json:"cost_usd"`
+}
+
+// add adds a session's duration (zero for subagents, whose time is their
+// parent's) and usage.
+func (t *issueTimeTotals) add(d time.Duration, u *claude.Usage, prices config.PriceTable) {
+	t.AgentTime += d
+	if u == nil {
+		return
+	}
+	t.InputTokens += u.InputTokens
+	t.OutputTokens += u.OutputTokens
+	t.CacheTokens += u.CacheCreationTokens + u.CacheReadTokens
+	t.TotalTokens += u.TotalTokens()
+	t.Cost += claude.EstimateCost(*u, prices)
+}
+
+// issueTimeAgent is one agent's share of an issue.
+type issueTimeAgent struct {
+	Agent string `json:"agent"`
+	issueTimeTotals
+}
+
+// issueTimeReport is the result of gt issue time.
+type issueTimeReport struct {
+	Issue string    `json:"issue"`
+	First time.Time `json:"first,omitempty"`
+	Last  time.Time `json:"last,omitempty"`
+	issueTimeTotals
+	Elapsed    time.Duration    `json:"elapsed"`
+	Agents     []issueTimeAgent `json:"agents"`
+	SessionIDs []string         `json:"session_ids"`
+}
+
+func runIssueTime(cmd *cobra.Command, args []string) error {
+	issue := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{GasTownOnly: true, IncludeSubagents: true})
+	if err != nil {
+		return fmt.Errorf("discovering sessions: %w", err)
+	}
+	events, err := discoverSessions(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading session events: %w", err)
+	}
+	linked := issueSessions(issue, sessions, collapseSessionChains(events))
+	if len(linked) == 0 {
+		return fmt.Errorf("no sessions found for %s", issue)
+	}
+
+	paths := make([]string, len(linked))
+	for i, s := range linked {
+		paths[i] = s.Path
+	}
+	usages, err := claude.ReadUsageChain(paths)
+	if err != nil {
+		return fmt.Errorf("reading usage: %w", err)
+	}
+	report := buildIssueTimeReport(issue, linked, usages, loadTownSettingsQuiet(townRoot).PriceTable())
+
+	if issueTimeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printIssueTimeReport(report)
+	return nil
+}
+
+// issueSessions returns the sessions that worked on issue, oldest first:
+// those whose beacon names it, the sessions after them in their chains
+// (see collapseSessionChains) up to one naming another issue, and the
+// subagents of all of these.
+func issueSessions(issue string, sessions []claude.SessionInfo, chains []seanceRow) []claude.SessionInfo {
+	byID := make(map[string]claude.SessionInfo, len(sessions))
+	for _, s := range sessions {
+		byID[s.ID] = s
+	}
+	onIssue := func(s claude.SessionInfo) bool { return strings.EqualFold(s.Bead, issue) }
+
+	linked := make(map[string]bool)
+	for _, s := range sessions {
+		if s.ParentID == "" && onIssue(s) {
+			linked[s.ID] = true
+		}
+	}
+	// Handoffs carry the issue forward until a session names another
+	for _, row := range chains {
+		carried := false
+		for _, id := range chainIDs(row) {
+			s, ok := byID[id]
+			switch {
+			case !ok:
+			case s.Bead == "":
+				if carried {
+					linked[id] = true
+				}
+			default:
+				carried = onIssue(s)
+			}
+		}
+	}
+
+	var out []claude.SessionInfo
+	for _, s := range sessions {
+		if linked[s.ID] || (s.ParentID != "" && linked[s.ParentID]) {
+			out = append(out, s)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].StartTime.Before(out[j].StartTime) })
+	return out
+}
+
+// buildIssueTimeReport totals linked sessions; usages holds each
+// session's usage, in the same order, or nil where it couldn't be read.
+func buildIssueTimeReport(issue string, linked []claude.SessionInfo, usages []*claude.Usage, prices config.PriceTable) *issueTimeReport {
+	report := &issueTimeReport{Issue: issue, Agents: []issueTimeAgent{}}
+	byAgent := make(map[string]*issueTimeAgent)
+	for i, s := range linked {
+		agent := s.Role
+		if agent == "" {
+			agent = "unknown"
+		}
+		a := byAgent[agent]
+		if a == nil {
+			a = &issueTimeAgent{Agent: agent}
+			byAgent[agent] = a
+		}
+
+		var d time.Duration
+		if s.ParentID == "" {
+			d = s.Duration
+			report.SessionIDs = append(report.SessionIDs, s.ID)
+			report.Sessions++
+			a.Sessions++
+			if report.First.IsZero() || s.StartTime.Before(report.First) {
+				report.First = s.StartTime
+			}
+			if s.EndTime.After(report.Last) {
+				report.Last = s.EndTime
+			}
+		}
+		report.add(d, usages[i], prices)
+		a.add(d, usages[i], prices)
+	}
+	if !report.First.IsZero() && report.Last.After(report.First) {
+		report.Elapsed = report.Last.Sub(report.First)
+	}
+
+	for _, a := range byAgent {
+		report.Agents = append(report.Agents, *a)
+	}
+	sort.Slice(report.Agents, func(i, j int) bool {
+		if report.Agents[i].AgentTime != report.Agents[j].AgentTime {
+			return report.Agents[i].AgentTime > report.Agents[j].AgentTime
+		}
+		return report.Agents[i].Agent < report.Agents[j].Agent
+	})
+	return report
+}
+
+func printIssueTimeReport(r *issueTimeReport) {
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Time on"), r.Issue)
+	fmt.Printf("  Sessions:   %d\n", r.Sessions)
+	fmt.Printf("  Agent time: %s\n", formatDuration(r.AgentTime))
+	fmt.Printf("  Elapsed:    %s  %s\n", formatDuration(r.Elapsed),
+		style.Dim.Render(r.First.Local().Format("2006-01-02 15:04")+" → "+r.Last.Local().Format("2006-01-02 15:04")))
+	fmt.Printf("  Tokens:     %s  %s\n", formatTokenCount(float64(r.TotalTokens)),
+		style.Dim.Render(fmt.Sprintf("(%s in, %s out, %s cache)",
+			formatTokenCount(float64(r.InputTokens)), formatTokenCount(float64(r.OutputTokens)), formatTokenCount(float64(r.CacheTokens)))))
+	fmt.Printf("  Cost:       $%.2f %s\n", r.Cost, style.Dim.Render("(estimated)"))
+
+	if len(r.Agents) > 1 {
+		fmt.Printf("\n%-30s  %8s  %10s  %8s  %8s\n", "AGENT", "SESSIONS", "TIME", "TOKENS", "COST")
+		for _, a := range r.Agents {
+			fmt.Printf("%-30s  %8d  %10s  %8s  %8s\n", compactAddress(a.Agent), a.Sessions, formatDuration(a.AgentTime),
+				formatTokenCount(float64(a.TotalTokens)), fmt.Sprintf("$%.2f", a.Cost))
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestIssueSessions(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2025, 3, 1, h, 0, 0, 0, time.UTC) }
+	sessions := []claude.SessionInfo{
+		{ID: "a1", Bead: "gt-123", Role: "gastown/polecats/toast", StartTime: at(1), EndTime: at(2), Duration: time.Hour},
+		{ID: "a2", Role: "gastown/polecats/toast", StartTime: at(3), EndTime: at(4), Duration: time.Hour}, // handoff
+		{ID: "a3", Bead: "gt-456", Role: "gastown/polecats/toast", StartTime: at(5), EndTime: at(6), Duration: time.Hour},
+		{ID: "a4", Role: "gastown/polecats/toast", StartTime: at(7), EndTime: at(8), Duration: time.Hour}, // carries gt-456
+		{ID: "b1", Bead: "GT-123", Role: "gastown/crew/max", StartTime: at(2), EndTime: at(3), Duration: 30 * time.Minute},
+		{ID: "sub", ParentID: "a2", Bead: "gt-123", Role: "gastown/polecats/toast", StartTime: at(3)},
+	}
+	chains := []seanceRow{{Chain: []string{"a1", "a2", "a3", "a4"}}}
+
+	var ids []string
+	for _, s := range issueSessions("gt-123", sessions, chains) {
+		ids = append(ids, s.ID)
+	}
+	if got := strings.Join(ids, " "); got != "a1 b1 a2 sub" {
+		t.Fatalf("issueSessions = %q, want a1 b1 a2 sub", got)
+	}
+
+	linked := issueSessions("gt-123", sessions, chains)
+	usages := []*claude.Usage{
+		{InputTokens: 100, OutputTokens: 10},
+		{InputTokens: 50},
+		nil,
+		{OutputTokens: 5},
+	}
+	prices := (*config.TownSettings)(nil).PriceTable()
+	r := buildIssueTimeReport("gt-123", linked, usages, prices)
+	if r.Sessions != 3 || r.AgentTime != 150*time.Minute || r.Elapsed != 3*time.Hour {
+		t.Errorf("totals = %d sessions, %v agent time, %v elapsed", r.Sessions, r.AgentTime, r.Elapsed)
+	}
+	if r.TotalTokens != 165 {
+		t.Errorf("TotalTokens = %d, want 165 (subagent included)", r.TotalTokens)
+	}
+	if len(r.Agents) != 2 || r.Agents[0].Agent != "gastown/polecats/toast" || r.Agents[0].AgentTime != 2*time.Hour {
+		t.Errorf("agents = %+v", r.Agents)
+	}
+}
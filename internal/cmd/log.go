@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -308,9 +309,9 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 	if err != nil || townRoot == "" {
 		// Try to find town root from conventional location
 		// This is called from tmux hook which may not have proper cwd
-		home := os.Getenv("HOME")
-		defaultRoot := home + "/gt"
-		if _, statErr := os.Stat(defaultRoot + "/mayor"); statErr == nil {
+		home, _ := os.UserHomeDir()
+		defaultRoot := filepath.Join(home, "gt")
+		if _, statErr := os.Stat(filepath.Join(defaultRoot, "mayor")); statErr == nil {
 			townRoot = defaultRoot
 		}
 		if townRoot == "" {
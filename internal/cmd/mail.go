@@ -41,6 +41,10 @@ var (
 
 	// Clear flags
 	mailClearAll bool
+
+	// Delivery flags
+	mailDeliveryAll  bool
+	mailDeliveryJSON bool
 )
 
 var mailCmd = &cobra.Command{
@@ -411,6 +415,52 @@ Examples:
 	RunE: runMailAnnounces,
 }
 
+var mailDeliveryCmd = &cobra.Command{
+	Use:   "delivery [message-id]",
+	Short: "Show delivery state of sent messages",
+	Long: `Show the delivery state of direct messages.
+
+Every direct message is tracked until its recipient reads it:
+  queued     Not yet announced: the recipient had no active session, or
+             the message couldn't be stored yet. Retried with backoff.
+  delivered  The recipient was notified in a live session.
+  read       The recipient has read the message.
+  expired    Stayed queued past its expiry; the sender was told.
+
+Queued messages are retried by the daemon on each heartbeat, or on demand
+with 'gt mail retry'. Retry intervals and expiry are configured in the
+"delivery" section of ~/gt/config/messaging.json:
+
+  "delivery": {
+    "retry_interval": "5m",
+    "max_retry_interval": "1h",
+    "expire_after": "7d",
+    "expire_by_priority": {"low": "2d", "urgent": "30d"}
+  }
+
+By default only queued messages are listed; use --all to include
+delivered, read, and expired messages.
+
+Examples:
+  gt mail delivery              # Messages awaiting delivery
+  gt mail delivery --all        # Every tracked message
+  gt mail delivery hq-abc123    # One message by delivery or message ID`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMailDelivery,
+}
+
+var mailRetryCmd = &cobra.Command{
+	Use:   "retry",
+	Short: "Retry delivery of queued messages now",
+	Long: `Run one delivery retry pass immediately.
+
+Queued messages are stored if an earlier store failed, announced to
+recipients that now have a session, and expired once past their expiry.
+The daemon runs the same pass on every heartbeat.`,
+	Args: cobra.NoArgs,
+	RunE: runMailRetry,
+}
+
 func init() {
 	// Send flags
 	mailSendCmd.Flags().StringVarP(&mailSubject, "subject", "s", "", "Message subject (required)")
@@ -463,6 +513,10 @@ func init() {
 	// Clear flags
 	mailClearCmd.Flags().BoolVar(&mailClearAll, "all", false, "Clear all messages (default behavior)")
 
+	// Delivery flags
+	mailDeliveryCmd.Flags().BoolVar(&mailDeliveryAll, "all", false, "Include delivered, read, and expired messages")
+	mailDeliveryCmd.Flags().BoolVar(&mailDeliveryJSON, "json", false, "Output as JSON")
+
 	// Add subcommands
 	mailCmd.AddCommand(mailSendCmd)
 	mailCmd.AddCommand(mailInboxCmd)
@@ -480,6 +534,8 @@ func init() {
 	mailCmd.AddCommand(mailClearCmd)
 	mailCmd.AddCommand(mailSearchCmd)
 	mailCmd.AddCommand(mailAnnouncesCmd)
+	mailCmd.AddCommand(mailDeliveryCmd)
+	mailCmd.AddCommand(mailRetryCmd)
 
 	rootCmd.AddCommand(mailCmd)
 }
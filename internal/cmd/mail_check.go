@@ -62,6 +62,9 @@ func runMailCheck(cmd *cobra.Command, args []string) error {
 
 	// Inject mode: output system-reminder if mail exists
 	if mailCheckInject {
+		// A hook running in a live session counts as delivery for anything
+		// that was queued while this agent had no session.
+		_, _ = router.MarkDelivered(address)
 		if unread > 0 {
 			// Get subjects for context
 			messages, _ := mailbox.ListUnread()
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+func runMailDelivery(cmd *cobra.Command, args []string) error {
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router := mail.NewRouter(workDir)
+
+	var records []*mail.DeliveryRecord
+	if len(args) == 1 {
+		rec, err := router.DeliveryStatus(args[0])
+		if err != nil {
+			return fmt.Errorf("no delivery record for %s: %w", args[0], err)
+		}
+		records = []*mail.DeliveryRecord{rec}
+	} else {
+		all, err := router.ListDeliveries()
+		if err != nil {
+			return fmt.Errorf("listing deliveries: %w", err)
+		}
+		for _, rec := range all {
+			if mailDeliveryAll || rec.Pending() {
+				records = append(records, rec)
+			}
+		}
+	}
+
+	if mailDeliveryJSON {
+		if records == nil {
+			records = []*mail.DeliveryRecord{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	if len(records) == 0 {
+		if mailDeliveryAll {
+			fmt.Println(style.Dim.Render("No tracked messages."))
+		} else {
+			fmt.Println(style.Dim.Render("No messages awaiting delivery."))
+		}
+		return nil
+	}
+
+	now := time.Now()
+	for _, rec := range records {
+		id := rec.BeadID
+		if id == "" {
+			id = rec.ID
+		}
+		fmt.Printf("%s %s  %s → %s\n", deliveryStateIcon(rec.State), style.Bold.Render(id), rec.From, rec.To)
+		fmt.Printf("    %s\n", rec.Subject)
+		detail := fmt.Sprintf("%s, queued %s ago", rec.State, formatDeliveryAge(now.Sub(rec.QueuedAt)))
+		if rec.Attempts > 0 {
+			detail += fmt.Sprintf(", %d attempt(s)", rec.Attempts)
+		}
+		if rec.Pending() {
+			if !rec.NextAttempt.IsZero() {
+				detail += fmt.Sprintf(", next retry in %s", formatDeliveryAge(rec.NextAttempt.Sub(now)))
+			}
+			detail += fmt.Sprintf(", expires in %s", formatDeliveryAge(rec.ExpiresAt.Sub(now)))
+		}
+		fmt.Printf("    %s\n", style.Dim.Render(detail))
+		if rec.LastError != "" {
+			fmt.Printf("    %s\n", style.Dim.Render("last error: "+rec.LastError))
+		}
+	}
+	return nil
+}
+
+func runMailRetry(cmd *cobra.Command, args []string) error {
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	result, err := mail.NewRouter(workDir).RetryDeliveries(time.Now())
+	if err != nil {
+		return fmt.Errorf("retrying deliveries: %w", err)
+	}
+	if result.Skipped {
+		fmt.Printf("%s Another retry pass is running; try again shortly\n", style.WarningPrefix)
+		return nil
+	}
+
+	fmt.Printf("%s Retry pass complete: %d stored, %d delivered, %d read, %d expired, %d still queued\n",
+		style.SuccessPrefix, result.Stored, result.Delivered, result.Read, result.Expired, result.Pending)
+	return nil
+}
+
+// deliveryStateIcon returns a status glyph for a delivery state.
+func deliveryStateIcon(state mail.DeliveryState) string {
+	switch state {
+	case mail.DeliveryQueued:
+		return "⏳"
+	case mail.DeliveryDelivered:
+		return "📬"
+	case mail.DeliveryRead:
+		return "✓"
+	case mail.DeliveryExpired:
+		return "✗"
+	}
+	return "?"
+}
+
+// formatDeliveryAge formats a duration coarsely (e.g., "45s", "12m", "3h", "2d").
+func formatDeliveryAge(d time.Duration) string {
+	switch {
+	case d < 0:
+		return "0s"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
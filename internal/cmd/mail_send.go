@@ -131,6 +131,13 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
 	fmt.Printf("  Subject: %s\n", mailSubject)
+	if rec := router.LastDelivery(); rec != nil && rec.Pending() {
+		if !rec.Stored {
+			fmt.Printf("  %s Not stored yet (%s); queued for retry\n", style.WarningPrefix, rec.LastError)
+		} else if rec.LastError != "" {
+			fmt.Printf("  %s\n", style.Dim.Render("Recipient has no active session; queued until it starts"))
+		}
+	}
 
 	// Show fan-out recipients for list addresses
 	if len(listRecipients) > 0 {
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	mayorEstimateHistory int
+	mayorEstimateJSON    bool
+)
+
+var mayorEstimateCmd = &cobra.Command{
+	Use:   "estimate <plan.yaml>",
+	Short: "Predict token, cost, and time ranges for a plan",
+	Long: `Dry-run a plan against session history before dispatching it.
+
+Each task is matched against past Gas Town sessions with the same role
+type (and rig, when given). Tasks with at least three sessions on a
+similar topic are estimated from those; otherwise all sessions for the
+role are used. Ranges are the 25th–75th percentile with the median.
+
+Plan format:
+  name: auth rewrite
+  tasks:
+    - id: t1
+      role: polecat
+      rig: gastown
+      topic: refactor session auth
+    - id: t2
+      role: polecat
+      task: session-tests.task.yaml
+
+A task may point at a task spec (see 'gt task'), relative to the plan.
+Its goal stands in for the topic, and tasks whose median estimate
+exceeds the spec's budget are flagged.
+
+Costs are computed from transcript token usage at the town's pricing
+(see 'gt costs models').
+
+Examples:
+  gt mayor estimate plan.yaml
+  gt mayor estimate plan.yaml --history 500 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMayorEstimate,
+}
+
+func init() {
+	mayorEstimateCmd.Flags().IntVar(&mayorEstimateHistory, "history", 200, "Number of recent sessions to learn from")
+	mayorEstimateCmd.Flags().BoolVar(&mayorEstimateJSON, "json", false, "Output as JSON")
+	mayorCmd.AddCommand(mayorEstimateCmd)
+}
+
+func runMayorEstimate(cmd *cobra.Command, args []string) error {
+	plan, err := mayor.LoadPlan(args[0])
+	if err != nil {
+		return err
+	}
+
+	townRoot, _ := workspace.FindFromCwd()
+	prices := loadTownSettingsQuiet(townRoot).PriceTable()
+
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{
+		GasTownOnly: true,
+		Limit:       mayorEstimateHistory,
+	})
+	if err != nil {
+		return fmt.Errorf("discovering sessions: %w", err)
+	}
+
+	history := make([]mayor.HistorySample, 0, len(sessions))
+	for _, s := range sessions {
+		usage, err := claude.ReadUsage(s.Path)
+		if err != nil || usage.TotalTokens() == 0 {
+			continue
+		}
+		role, rig, _ := parseRoleString(s.Role)
+		history = append(history, mayor.HistorySample{
+			Role:     string(role),
+			Rig:      rig,
+			Topic:    strings.TrimSpace(s.Topic + " " + s.Summary),
+			Tokens:   usage.TotalTokens(),
+			Cost:     claude.EstimateCost(*usage, prices),
+			Duration: usage.Duration,
+		})
+	}
+
+	est := mayor.EstimatePlan(plan, history)
+
+	if mayorEstimateJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(est)
+	}
+
+	title := "Plan estimate"
+	if plan.Name != "" {
+		title += ": " + plan.Name
+	}
+	fmt.Printf("%s\n", style.Bold.Render(title))
+	fmt.Printf("%s\n\n", style.Dim.Render(fmt.Sprintf("Learned from %d session(s)", len(history))))
+
+	fmt.Printf("%-12s  %-10s  %-20s  %-18s  %-16s  %s\n", "TASK", "ROLE", "TOKENS", "COST", "TIME", "BASIS")
+	for _, te := range est.Tasks {
+		if te.Basis == mayor.BasisNone {
+			fmt.Printf("%-12s  %-10s  %s\n", te.Task.ID, te.Task.Role, style.Dim.Render("no history for this role"))
+			continue
+		}
+		fmt.Printf("%-12s  %-10s  %-20s  %-18s  %-16s  %s\n",
+			te.Task.ID, te.Task.Role,
+			formatTokenRange(te.Tokens),
+			formatCostRange(te.CostUSD),
+			formatMinutesRange(te.Minutes),
+			style.Dim.Render(fmt.Sprintf("%s, n=%d", te.Basis, te.Samples)))
+	}
+
+	fmt.Printf("\n%-12s  %-10s  %-20s  %-18s  %s\n", style.Bold.Render("TOTAL"), "",
+		formatTokenRange(est.Tokens), formatCostRange(est.CostUSD), formatMinutesRange(est.Minutes))
+
+	if anyOverBudget(est.Tasks) {
+		fmt.Println()
+		for _, te := range est.Tasks {
+			if len(te.OverBudget) > 0 {
+				fmt.Printf("%s Task %s is likely over its budget (%s)\n",
+					style.WarningPrefix, te.Task.ID, strings.Join(te.OverBudget, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// anyOverBudget reports whether any of tasks is likely over its budget.
+func anyOverBudget(tasks []mayor.TaskEstimate) bool {
+	for _, te := range tasks {
+		if len(te.OverBudget) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func formatTokenRange(r mayor.Range) string {
+	return fmt.Sprintf("%s–%s", formatTokenCount(r.Low), formatTokenCount(r.High))
+}
+
+func formatTokenCount(n float64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", n/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.0fk", n/1_000)
+	default:
+		return fmt.Sprintf("%.0f", n)
+	}
+}
+
+func formatCostRange(r mayor.Range) string {
+	return fmt.Sprintf("$%.2f–$%.2f", r.Low, r.High)
+}
+
+func formatMinutesRange(r mayor.Range) string {
+	return fmt.Sprintf("%.0f–%.0fm", r.Low, r.High)
+}
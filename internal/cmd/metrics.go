@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/takeover"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"github.com/steveyegge/gastown/internal/xlsx"
+)
+
+var (
+	metricsExportSince  string
+	metricsExportUntil  string
+	metricsExportRole   string
+	metricsExportRig    string
+	metricsExportOut    string
+	metricsExportFormat string
+)
+
+var metricsCmd = &cobra.Command{
+	Use:     "metrics",
+	GroupID: GroupDiag,
+	Short:   "Export per-session metrics",
+	RunE:    requireSubcommand,
+}
+
+var metricsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export one row per session for spreadsheet pivoting",
+	Long: `Export one row per Gas Town session, with computed columns for
+pivoting in a spreadsheet: who ran it (role, rig, agent, bead, model),
+how long it took, what it cost (tokens and estimated cost from the town's
+price table), what it did (files written, commands, test runs and
+failures, commits), and how it ended (state and recorded outcome).
+
+The format follows the --out extension: .xlsx writes an Excel workbook,
+anything else CSV. Without --out, CSV goes to stdout.
+
+Columns:
+  session_id, start, date, week, role, role_type, rig, agent, bead,
+  model, state, duration_min, user_messages, assistant_messages,
+  compactions, tool_calls, tool_errors, input_tokens, output_tokens,
+  cache_tokens, total_tokens, cost_usd, files_written, commands,
+  failed_commands, test_runs, test_failures, commits, outcome, blended
+
+"outcome" is what 'gt outcome set' recorded; "blended" marks sessions a
+human took over (gt takeover). Test runs are shell commands like go test,
+pytest, or npm test; commits are git commit commands. tool_errors counts
+tool calls of any kind whose result came back as an error.
+
+Examples:
+  gt metrics export --since quarter --out metrics.xlsx
+  gt metrics export --since month --role polecat --out polecats.csv
+  gt metrics export --since 2025-01-01 --until 2025-04-01 > q1.csv`,
+	Args: cobra.NoArgs,
+	RunE: runMetricsExport,
+}
+
+func init() {
+	metricsExportCmd.Flags().StringVar(&metricsExportSince, "since", "month", "Only sessions started at or after this time (e.g., quarter, month, 30d, 2025-01-01)")
+	metricsExportCmd.Flags().StringVar(&metricsExportUntil, "until", "", "Only sessions started before this time (same formats as --since)")
+	metricsExportCmd.Flags().StringVar(&metricsExportRole, "role", "", "Only sessions of this role type (crew, polecat, witness, ...)")
+	metricsExportCmd.Flags().StringVar(&metricsExportRig, "rig", "", "Only sessions in this rig")
+	metricsExportCmd.Flags().StringVarP(&metricsExportOut, "out", "o", "", "Write to this file (.xlsx or .csv) instead of stdout")
+	metricsExportCmd.Flags().StringVar(&metricsExportFormat, "format", "", "Output format: csv or xlsx (default: from --out)")
+
+	metricsCmd.AddCommand(metricsExportCmd)
+	rootCmd.AddCommand(metricsCmd)
+}
+
+// metricsColumns are the exported columns, in order; sessionMetricsRow
+// fills them.
+var metricsColumns = []string{
+	"session_id", "start", "date", "week", "role", "role_type", "rig", "agent", "bead",
+	"model", "state", "duration_min", "user_messages", "assistant_messages",
+	"compactions", "tool_calls", "tool_errors", "input_tokens", "output_tokens",
+	"cache_tokens", "total_tokens", "cost_usd", "files_written", "commands",
+	"failed_commands", "test_runs", "test_failures", "commits", "outcome",
+	"blended",
+}
+
+func runMetricsExport(cmd *cobra.Command, args []string) error {
+	format, err := metricsFormat(metricsExportFormat, metricsExportOut)
+	if err != nil {
+		return err
+	}
+	if format == "xlsx" && metricsExportOut == "" {
+		return fmt.Errorf("xlsx output needs --out")
+	}
+	since, until, err := parseTimeRange(metricsExportSince, metricsExportUntil, time.Now())
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{
+		GasTownOnly: true,
+		Rig:         metricsExportRig,
+		Since:       since,
+		Until:       until,
+	})
+	if err != nil {
+		return fmt.Errorf("discovering sessions: %w", err)
+	}
+	records, err := outcome.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	outcomes := make(map[string]outcome.Status, len(records))
+	for _, rec := range records {
+		outcomes[rec.SessionID] = rec.Status
+	}
+	takeovers, err := takeover.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	prices := loadTownSettingsQuiet(townRoot).PriceTable()
+
+	// Oldest first, the natural order for a spreadsheet
+	rows := [][]any{anySlice(metricsColumns)}
+	for i := len(sessions) - 1; i >= 0; i-- {
+		s := sessions[i]
+		role, _, _ := parseRoleString(s.Role)
+		if metricsExportRole != "" && string(role) != metricsExportRole {
+			continue
+		}
+		rows = append(rows, sessionMetricsRow(s, prices, outcomes[s.ID], len(takeovers[s.ID]) > 0))
+	}
+
+	if err := writeMetrics(format, metricsExportOut, rows); err != nil {
+		return err
+	}
+	if metricsExportOut != "" {
+		fmt.Fprintf(os.Stderr, "%s Exported %d session(s) to %s\n", style.SuccessPrefix, len(rows)-1, metricsExportOut)
+	}
+	return nil
+}
+
+// metricsFormat picks the export format from --format, else from the
+// output file's extension.
+func metricsFormat(format, out string) (string, error) {
+	if format == "" {
+		if strings.EqualFold(filepath.Ext(out), ".xlsx") {
+			return "xlsx", nil
+		}
+		return "csv", nil
+	}
+	switch format = strings.ToLower(format); format {
+	case "csv", "xlsx":
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown format %q (want csv or xlsx)", format)
+}
+
+// sessionMetricsRow computes a session's row of metricsColumns. The
+// transcript is read for usage and activity; if it can't be, those
+// columns are left empty.
+func sessionMetricsRow(s claude.SessionInfo, prices config.PriceTable, status outcome.Status, blended bool) []any {
+	role, rig, _ := parseRoleString(s.Role)
+	if rig == "" {
+		rig = s.Rig
+	}
+	year, week := s.StartTime.Local().ISOWeek()
+	row := []any{
+		s.ID,
+		s.StartTime.Local(),
+		s.StartTime.Local().Format("2006-01-02"),
+		fmt.Sprintf("%d-W%02d", year, week),
+		s.Role,
+		string(role),
+		rig,
+		s.AgentName,
+		s.Bead,
+		s.Model,
+		string(s.State),
+		roundTo(s.Duration.Minutes(), 1),
+		s.UserMessages,
+		s.AssistantMessages,
+		s.CompactionCount,
+		s.ToolCalls,
+		s.ErrorCount,
+	}
+
+	if u, err := claude.ReadUsage(s.Path); err == nil {
+		row = append(row,
+			u.InputTokens,
+			u.OutputTokens,
+			u.CacheCreationTokens+u.CacheReadTokens,
+			u.TotalTokens(),
+			roundTo(claude.EstimateCost(*u, prices), 4),
+		)
+	} else {
+		row = append(row, nil, nil, nil, nil, nil)
+	}
+
+	if t, err := claude.LoadTranscript(s.Path); err == nil {
+		a := t.Activity()
+		var failed, tests, testFailures, commits int
+		for _, c := range a.Commands {
+			if c.Failed {
+				failed++
+			}
+			if c.IsTest() {
+				tests++
+				if c.Failed {
+					testFailures++
+				}
+			}
+			if c.IsCommit() && !c.Failed {
+				commits++
+			}
+		}
+		row = append(row, len(a.FilesWritten), len(a.Commands), failed, tests, testFailures, commits)
+	} else {
+		row = append(row, nil, nil, nil, nil, nil, nil)
+	}
+
+	return append(row, string(status), blended)
+}
+
+// writeMetrics writes rows as format to out, or to stdout when out is "".
+func writeMetrics(format, out string, rows [][]any) error {
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.Create(out) //nolint:gosec // G304: path is user-provided
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == "xlsx" {
+		if err := xlsx.Write(w, "Sessions", rows); err != nil {
+			return fmt.Errorf("writing %s: %w", out, err)
+		}
+		return nil
+	}
+	cw := csv.NewWriter(w)
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			record[i] = csvCell(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvCell formats a metrics value for CSV.
+func csvCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format("2006-01-02 15:04:05")
+	}
+	return fmt.Sprint(v)
+}
+
+// roundTo rounds f to n decimal places.
+func roundTo(f float64, n int) float64 {
+	p := math.Pow10(n)
+	return math.Round(f*p) / p
+}
+
+// anySlice converts strings to a row of cells.
+func anySlice(ss []string) []any {
+	out := make([]any, len(ss))
+	for i, s := range ss {
+		out[i] = s
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/outcome"
+)
+
+func TestMetricsFormat(t *testing.T) {
+	tests := []struct{ format, out, want string }{
+		{"", "metrics.xlsx", "xlsx"},
+		{"", "metrics.XLSX", "xlsx"},
+		{"", "metrics.csv", "csv"},
+		{"", "", "csv"},
+		{"XLSX", "out.dat", "xlsx"},
+	}
+	for _, tt := range tests {
+		if got, err := metricsFormat(tt.format, tt.out); err != nil || got != tt.want {
+			t.Errorf("metricsFormat(%q, %q) = %q, %v; want %q", tt.format, tt.out, got, err, tt.want)
+		}
+	}
+	if _, err := metricsFormat("ods", ""); err == nil {
+		t.Error("metricsFormat accepted ods")
+	}
+}
+
+func TestSessionMetricsRow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "m1.jsonl")
+	lines := []string{
+		`{"type":"user","timestamp":"2025-03-01T12:00:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/polecats/toast <- witness • 2025-03-01T12:00 • work"}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:01:00Z","message":{"id":"m1","model":"claude-sonnet-4-5","usage":{"input_tokens":100,"output_tokens":50,"cache_read_input_tokens":1000},"content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./..."}}]}}`,
+		`{"type":"user","timestamp":"2025-03-01T12:02:00Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"FAIL","is_error":true}]}}`,
+		`{"type":"assistant","timestamp":"2025-03-01T12:03:00Z","message":{"id":"m2","model":"claude-sonnet-4-5","content":[{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"/x/a.go"}},{"type":"tool_use","id":"t3","name":"Bash","input":{"command":"git add -A && git commit -m fix"}}]}}`,
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := claude.SessionInfo{
+		ID:        "m1",
+		Path:      path,
+		Role:      "gastown/polecats/toast",
+		AgentName: "toast",
+		StartTime: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Duration:  3 * time.Minute,
+	}
+	row := sessionMetricsRow(s, config.PriceTable{}, outcome.Success, true)
+	if len(row) != len(metricsColumns) {
+		t.Fatalf("row has %d cells for %d columns", len(row), len(metricsColumns))
+	}
+	cell := func(name string) any {
+		for i, c := range metricsColumns {
+			if c == name {
+				return row[i]
+			}
+		}
+		t.Fatalf("no column %s", name)
+		return nil
+	}
+	for name, want := range map[string]any{
+		"role_type":       "polecat",
+		"rig":             "gastown",
+		"duration_min":    3.0,
+		"total_tokens":    int64(1150),
+		"files_written":   1,
+		"commands":        2,
+		"failed_commands": 1,
+		"test_runs":       1,
+		"test_failures":   1,
+		"commits":         1,
+		"outcome":         "success",
+		"blended":         true,
+	} {
+		if got := cell(name); got != want {
+			t.Errorf("%s = %v (%T), want %v", name, got, got, want)
+		}
+	}
+}
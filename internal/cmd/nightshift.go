@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/nightshift"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	nightshiftArgs     string
+	nightshiftPriority int
+	nightshiftListAll  bool
+	nightshiftListJSON bool
+	nightshiftRunForce bool
+	nightshiftRunJSON  bool
+)
+
+var nightshiftCmd = &cobra.Command{
+	Use:     "nightshift",
+	GroupID: GroupWork,
+	Short:   "Queue low-priority work to run off-hours",
+	RunE:    requireSubcommand,
+	Long: `Queue low-priority work during the day and run it overnight.
+
+Queued tasks are slung by the daemon while the nightshift window is open,
+highest priority first, without exceeding the concurrency limit or the
+night's budget. A task is done when its bead is closed. Once the window
+closes, a report of the night's results is mailed (to the overseer by
+default).
+
+Configure in settings/config.json (window is local time and may wrap
+midnight; budget_usd caps the estimated spend since the window opened):
+  "nightshift": {"window": "22:00-06:00", "max_concurrent": 2,
+                 "budget_usd": 25, "notify": "overseer"}
+
+The queue is stored in <town>/.runtime/nightshift.jsonl.`,
+}
+
+var nightshiftQueueCmd = &cobra.Command{
+	Use:   "queue <bead> [target]",
+	Short: "Queue a bead to be slung overnight",
+	Long: `Queue a bead to be slung during the nightshift window.
+
+The target is passed to 'gt sling' as-is: a rig to spawn a polecat, or
+any other sling target.
+
+Examples:
+  gt nightshift queue gt-abc gastown
+  gt nightshift queue gt-def gastown --priority 4 --args "docs only"`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runNightshiftQueue,
+}
+
+var nightshiftListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show queued and running nightshift tasks",
+	Long: `Show queued and running nightshift tasks.
+
+Use --all to include finished and canceled tasks.`,
+	RunE: runNightshiftList,
+}
+
+var nightshiftRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Make a nightshift pass now",
+	Long: `Make the pass the daemon makes on each heartbeat: mark tasks whose
+bead has closed as done, launch queued tasks if the window is open, and
+send the report once it has closed.
+
+Use --force to launch queued tasks outside the window. The concurrency
+limit and budget still apply.
+
+Examples:
+  gt nightshift run
+  gt nightshift run --force`,
+	RunE: runNightshiftRun,
+}
+
+var nightshiftCancelCmd = &cobra.Command{
+	Use:   "cancel <task-id>",
+	Short: "Remove a queued task",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNightshiftCancel,
+}
+
+func init() {
+	nightshiftQueueCmd.Flags().StringVarP(&nightshiftArgs, "args", "a", "", "Natural language instructions for the executor")
+	nightshiftQueueCmd.Flags().IntVarP(&nightshiftPriority, "priority", "p", nightshift.DefaultPriority, "Priority (0=highest, 4=lowest)")
+
+	nightshiftListCmd.Flags().BoolVar(&nightshiftListAll, "all", false, "Include finished and canceled tasks")
+	nightshiftListCmd.Flags().BoolVar(&nightshiftListJSON, "json", false, "Output as JSON")
+
+	nightshiftRunCmd.Flags().BoolVar(&nightshiftRunForce, "force", false, "Launch tasks even outside the window")
+	nightshiftRunCmd.Flags().BoolVar(&nightshiftRunJSON, "json", false, "Output as JSON")
+
+	nightshiftCmd.AddCommand(nightshiftQueueCmd)
+	nightshiftCmd.AddCommand(nightshiftListCmd)
+	nightshiftCmd.AddCommand(nightshiftRunCmd)
+	nightshiftCmd.AddCommand(nightshiftCancelCmd)
+	rootCmd.AddCommand(nightshiftCmd)
+}
+
+func runNightshiftQueue(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if nightshiftPriority < 0 || nightshiftPriority > 4 {
+		return fmt.Errorf("invalid priority %d (want 0-4)", nightshiftPriority)
+	}
+
+	t := nightshift.Task{
+		Bead:     args[0],
+		Args:     nightshiftArgs,
+		Priority: nightshiftPriority,
+		QueuedBy: detectSender(),
+	}
+	if len(args) > 1 {
+		t.Target = args[1]
+	}
+	t, err = nightshift.Queue(townRoot, t)
+	if err != nil {
+		return fmt.Errorf("queuing task: %w", err)
+	}
+
+	cfg := loadTownSettingsQuiet(townRoot).NightshiftSettings()
+	fmt.Printf("%s Queued %s as %s (runs %s)\n", style.SuccessPrefix, t.Bead, t.ID, cfg.Window)
+	return nil
+}
+
+func runNightshiftList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	tasks, err := nightshift.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	shown := []nightshift.Task{}
+	for _, t := range tasks {
+		if nightshiftListAll || !t.Finished() {
+			shown = append(shown, t)
+		}
+	}
+
+	if nightshiftListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(shown)
+	}
+
+	if len(shown) == 0 {
+		fmt.Println(style.Dim.Render("Nothing queued. Use 'gt nightshift queue <bead> [target]'."))
+		return nil
+	}
+
+	cfg := loadTownSettingsQuiet(townRoot).NightshiftSettings()
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Nightshift"), style.Dim.Render("(window "+cfg.Window+")"))
+	fmt.Printf("%-9s  %-9s  %3s  %-14s  %-16s  %s\n", "ID", "STATUS", "PRI", "BEAD", "TARGET", "SINCE")
+	for _, t := range shown {
+		since := t.QueuedAt
+		if !t.LaunchedAt.IsZero() {
+			since = t.LaunchedAt
+		}
+		target := t.Target
+		if target == "" {
+			target = "-"
+		}
+		line := fmt.Sprintf("%-9s  %-9s  %3s  %-14s  %-16s  %s ago",
+			t.ID, t.Status, fmt.Sprintf("P%d", t.Priority), t.Bead, target, formatDuration(time.Since(since)))
+		if t.Error != "" {
+			line += "  " + style.Error.Render(t.Error)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runNightshiftRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	settings := loadTownSettingsQuiet(townRoot)
+	cfg := settings.NightshiftSettings()
+	result, err := nightshift.Run(townRoot, nightshift.RunOptions{
+		Config: cfg,
+		Force:  nightshiftRunForce,
+		Launch: nightshift.Sling(townRoot),
+		Status: nightshift.BeadStatus(townRoot),
+		Spent:  nightshift.SessionSpend(settings.PriceTable()),
+		Notify: nightshift.Mail(townRoot, cfg.Notify),
+	})
+	if err != nil {
+		return err
+	}
+
+	if nightshiftRunJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	for _, t := range result.Finished {
+		fmt.Printf("%s %s done\n", style.SuccessPrefix, t.Bead)
+	}
+	for _, t := range result.Launched {
+		fmt.Printf("%s Launched %s (%s)\n", style.SuccessPrefix, t.Bead, t.ID)
+	}
+	for _, t := range result.Failed {
+		fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), t.Bead, t.Error)
+	}
+	if len(result.Reported) > 0 {
+		fmt.Printf("%s Reported %d task(s) to %s\n", style.SuccessPrefix, len(result.Reported), cfg.Notify)
+	}
+	fmt.Printf("%d running, %d queued\n", result.Running, result.Queued)
+	if result.Held != "" && result.Queued > 0 {
+		fmt.Printf("%s\n", style.Dim.Render("Holding queued tasks: "+result.Held))
+	}
+	return nil
+}
+
+func runNightshiftCancel(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	tasks, err := nightshift.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	t, err := nightshift.Find(tasks, args[0])
+	if err != nil {
+		return err
+	}
+	if t.Status != nightshift.StatusQueued {
+		return fmt.Errorf("task %s is %s, not queued", t.ID, t.Status)
+	}
+
+	t.Status = nightshift.StatusCanceled
+	t.FinishedAt = time.Now().UTC()
+	if err := nightshift.Save(townRoot, t); err != nil {
+		return fmt.Errorf("canceling task: %w", err)
+	}
+	fmt.Printf("%s Canceled %s (%s)\n", style.SuccessPrefix, t.ID, t.Bead)
+	return nil
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/takeover"
+	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	outcomeReason        string
+	outcomeRole          string
+	outcomePromptVersion string
+	outcomeSetBy         string
+	outcomeReportJSON    bool
+	outcomeReportRig     string
+)
+
+var outcomeCmd = &cobra.Command{
+	Use:     "outcome",
+	GroupID: GroupDiag,
+	Short:   "Record and report whether sessions achieved their task",
+	RunE:    requireSubcommand,
+	Long: `Record per-session outcomes and report success rates.
+
+Outcomes can be set by humans after reviewing work, or by hooks when a
+session ends. Each outcome is attributed to the session's role and the
+version (content hash) of that role's prompt template, so success rates
+can be compared across prompt changes.
+
+Outcomes are stored in <town>/.runtime/outcomes.jsonl. Setting an outcome
+again for the same session replaces the earlier one.`,
+}
+
+var outcomeSetCmd = &cobra.Command{
+	Use:   "set <session-id> success|failure|partial",
+	Short: "Record the outcome of a session",
+	Long: `Record whether a session achieved its task.
+
+The role and rig are taken from the session's Gas Town beacon when the
+transcript is found locally; use --role for sessions recorded elsewhere.
+
+Examples:
+  gt outcome set abc123 success
+  gt outcome set abc123 failure --reason "tests never passed"
+  gt outcome set abc123 partial --reason "API done, UI pending" --by hook`,
+	Args: cobra.ExactArgs(2),
+	RunE: runOutcomeSet,
+}
+
+var outcomeReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show success rates by role and prompt version",
+	Long: `Show success rates grouped by role and prompt version.
+
+The prompt version is a hash of the role template in effect when the
+outcome was recorded; the current version of each template is marked.
+
+Examples:
+  gt outcome report
+  gt outcome report --rig gastown --json`,
+	RunE: runOutcomeReport,
+}
+
+func init() {
+	outcomeSetCmd.Flags().StringVar(&outcomeReason, "reason", "", "Why the session succeeded or failed")
+	outcomeSetCmd.Flags().StringVar(&outcomeRole, "role", "", "Role type override (polecat, crew, ...)")
+	outcomeSetCmd.Flags().StringVar(&outcomePromptVersion, "prompt-version", "", "Prompt version override (default: current role template hash)")
+	outcomeSetCmd.Flags().StringVar(&outcomeSetBy, "by", "", "Who is recording the outcome (default: detected identity)")
+
+	outcomeReportCmd.Flags().BoolVar(&outcomeReportJSON, "json", false, "Output as JSON")
+	outcomeReportCmd.Flags().StringVar(&outcomeReportRig, "rig", "", "Only include sessions from this rig")
+
+	outcomeCmd.AddCommand(outcomeSetCmd)
+	outcomeCmd.AddCommand(outcomeReportCmd)
+	rootCmd.AddCommand(outcomeCmd)
+}
+
+func runOutcomeSet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	status, err := outcome.ParseStatus(strings.ToLower(args[1]))
+	if err != nil {
+		return err
+	}
+
+	rec := outcome.Record{
+		SessionID: args[0],
+		Status:    status,
+		Reason:    outcomeReason,
+		Role:      outcomeRole,
+		SetBy:     outcomeSetBy,
+	}
+
+	// Resolve the full ID and role from the transcript when available
+	if s, err := claude.FindSession(args[0]); err == nil {
+		rec.SessionID = s.ID
+		if s.Role != "" {
+			role, rig, _ := parseRoleString(s.Role)
+			if rec.Role == "" {
+				rec.Role = string(role)
+			}
+			rec.Rig = rig
+		}
+	}
+
+	if rec.Role == "" {
+		return fmt.Errorf("session %s has no Gas Town role; pass --role", args[0])
+	}
+	rec.PromptVersion = outcomePromptVersion
+	if rec.PromptVersion == "" {
+		rec.PromptVersion = templates.RoleVersion(rec.Role)
+	}
+	if rec.SetBy == "" {
+		rec.SetBy = detectSender()
+	}
+
+	prev, _ := outcome.Get(townRoot, rec.SessionID)
+	if err := outcome.Append(townRoot, rec); err != nil {
+		return fmt.Errorf("recording outcome: %w", err)
+	}
+	if err := logOutcomeChange(prev, rec); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Recorded %s for %s (%s)\n", style.SuccessPrefix, rec.Status, shortSessionID(rec.SessionID), rec.Role)
+	return nil
+}
+
+// logOutcomeChange adds an outcome written for a session to the session
+// metadata history (gt seance index history), with the outcome it
+// replaces, if any.
+func logOutcomeChange(prev *outcome.Record, rec outcome.Record) error {
+	change := claude.OverlayChange{
+		Session: rec.SessionID,
+		Kind:    claude.OverlayOutcome,
+		Op:      claude.OverlaySet,
+		Value:   describeOutcomeRecord(rec),
+	}
+	if prev != nil {
+		change.Old = describeOutcomeRecord(*prev)
+	}
+	if err := claude.RecordOverlayChange(change); err != nil {
+		return fmt.Errorf("logging outcome change: %w", err)
+	}
+	return nil
+}
+
+// describeOutcomeRecord renders an outcome for the history, e.g.
+// "failure: tests still red".
+func describeOutcomeRecord(rec outcome.Record) string {
+	if rec.Reason == "" {
+		return string(rec.Status)
+	}
+	return string(rec.Status) + ": " + rec.Reason
+}
+
+func runOutcomeReport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	records, err := outcome.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if outcomeReportRig != "" {
+		filtered := records[:0]
+		for _, r := range records {
+			if r.Rig == outcomeReportRig {
+				filtered = append(filtered, r)
+			}
+		}
+		records = filtered
+	}
+
+	takeovers, err := takeover.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	stats := outcome.Summarize(records, takeover.Blended(takeovers))
+
+	if outcomeReportJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	if len(stats) == 0 {
+		fmt.Println(style.Dim.Render("No outcomes recorded. Use 'gt outcome set <session-id> success|failure|partial'."))
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Session Outcomes"))
+	blended := 0
+	for _, s := range stats {
+		blended += s.Blended
+	}
+
+	fmt.Printf("%-10s  %-10s  %6s  %7s  %7s  %7s  %5s", "ROLE", "PROMPT", "TOTAL", "SUCCESS", "PARTIAL", "FAILURE", "RATE")
+	if blended > 0 {
+		fmt.Printf("  %7s", "BLENDED")
+	}
+	fmt.Println()
+	for _, s := range stats {
+		version := s.PromptVersion
+		if version == "" {
+			version = "-"
+		} else if version == templates.RoleVersion(s.Role) {
+			version += "*"
+		}
+		rate := "-"
+		if s.Total > 0 {
+			rate = fmt.Sprintf("%.0f%%", s.SuccessRate*100)
+		}
+		fmt.Printf("%-10s  %-10s  %6d  %7d  %7d  %7d  %5s",
+			s.Role, version, s.Total, s.Success, s.Partial, s.Failure, rate)
+		if blended > 0 {
+			fmt.Printf("  %7d", s.Blended)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("* current prompt version"))
+	if blended > 0 {
+		fmt.Printf("%s\n", style.Dim.Render("Blended sessions (see gt takeover) are excluded from the rates"))
+	}
+	return nil
+}
+
+// shortSessionID truncates a session UUID for display.
+func shortSessionID(id string) string {
+	return claude.SessionInfo{ID: id}.ShortID()
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/pause"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var pauseAgentReason string
+
+// pauseGatePollInterval is how often a blocked agent rechecks its pause.
+const pauseGatePollInterval = 2 * time.Second
+
+var pauseCmd = &cobra.Command{
+	Use:     "pause [role|rig|address|session]",
+	GroupID: GroupAgents,
+	Short:   "Freeze agents at their next tool call",
+	Long: `Pause agents without killing their sessions.
+
+A paused agent's next tool call blocks (via the PreToolUse hook) until
+the pause is lifted with 'gt resume-role'. Sessions keep their context,
+so work continues exactly where it stopped.
+
+Targets:
+  all                      Every agent in the town
+  polecat, crew, ...       Every agent of a role type
+  <rig>                    Every agent in a rig
+  <rig>/crew/<name>        A single agent by address
+  <session-id>             A single session (ID prefix, 6+ chars)
+
+With no argument, lists active pauses.
+
+Agents whose settings predate the pause gate need a PreToolUse hook
+running 'gt pause-gate'.
+
+Examples:
+  gt pause all --reason "prod incident, hands off main"
+  gt pause gastown/polecats/toast
+  gt pause
+  gt resume-role all`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPause,
+}
+
+var resumeRoleCmd = &cobra.Command{
+	Use:     "resume-role <target>",
+	GroupID: GroupAgents,
+	Short:   "Lift a pause set by 'gt pause'",
+	Long: `Lift a pause so blocked agents continue with their tool call.
+
+The target must match the one given to 'gt pause'.
+
+Examples:
+  gt resume-role all
+  gt resume-role gastown/polecats/toast`,
+	Args: cobra.ExactArgs(1),
+	RunE: runResumeRole,
+}
+
+var pauseGateCmd = &cobra.Command{
+	Use:    "pause-gate",
+	Hidden: true,
+	Short:  "PreToolUse hook: block while this agent is paused",
+	Long: `Block while a pause matches the calling agent.
+
+Called by the PreToolUse hook before every tool call. Reads the hook
+payload from stdin for the session ID and returns immediately when
+nothing is paused.`,
+	RunE: runPauseGate,
+}
+
+func init() {
+	pauseCmd.Flags().StringVar(&pauseAgentReason, "reason", "", "Why agents are paused (shown to the agent)")
+
+	rootCmd.AddCommand(pauseCmd)
+	rootCmd.AddCommand(resumeRoleCmd)
+	rootCmd.AddCommand(pauseGateCmd)
+}
+
+func runPause(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if len(args) == 0 {
+		pauses, err := pause.Load(townRoot)
+		if err != nil {
+			return err
+		}
+		if len(pauses) == 0 {
+			fmt.Println(style.Dim.Render("Nothing is paused."))
+			return nil
+		}
+		fmt.Printf("%s\n\n", style.Bold.Render("Active Pauses"))
+		for _, p := range pauses {
+			fmt.Printf("  %-28s  %s  %s\n", compactAddress(p.Target),
+				style.Dim.Render(p.Since.Local().Format("2006-01-02 15:04")+" by "+compactAddress(p.By)), p.Reason)
+		}
+		return nil
+	}
+
+	p := pause.Pause{Target: args[0], Reason: pauseAgentReason, By: detectSender()}
+	if err := pause.Add(townRoot, p); err != nil {
+		return fmt.Errorf("pausing %s: %w", args[0], err)
+	}
+
+	fmt.Printf("%s Paused %s\n", style.SuccessPrefix, args[0])
+	fmt.Printf("  %s\n", style.Dim.Render("Agents block at their next tool call. Lift with: gt resume-role "+args[0]))
+	return nil
+}
+
+func runResumeRole(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	removed, err := pause.Remove(townRoot, args[0])
+	if err != nil {
+		return fmt.Errorf("resuming %s: %w", args[0], err)
+	}
+	if !removed {
+		return fmt.Errorf("no pause for %s (see 'gt pause' for active pauses)", args[0])
+	}
+
+	fmt.Printf("%s Resumed %s\n", style.SuccessPrefix, args[0])
+	return nil
+}
+
+func runPauseGate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil // Outside a town nothing can be paused
+	}
+
+	pauses, err := pause.Load(townRoot)
+	if err != nil || len(pauses) == 0 {
+		return nil // Never wedge an agent on a bad pause file
+	}
+
+	agent := pause.Agent{Address: detectSender()}
+	role, rig, _ := parseRoleString(agent.Address)
+	agent.RoleType, agent.Rig = string(role), rig
+	if input := readStdinJSON(); input != nil {
+		agent.SessionID = input.SessionID
+	}
+
+	announced := false
+	for {
+		p := pause.Match(pauses, agent)
+		if p == nil {
+			return nil
+		}
+		if !announced {
+			msg := fmt.Sprintf("gt: paused by %s (target %s)", p.By, p.Target)
+			if p.Reason != "" {
+				msg += ": " + p.Reason
+			}
+			fmt.Fprintln(os.Stderr, msg)
+			announced = true
+		}
+
+		time.Sleep(pauseGatePollInterval)
+		if pauses, err = pause.Load(townRoot); err != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/perf"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	perfReportSince   string
+	perfReportCommand string
+	perfReportJSON    bool
+)
+
+var perfCmd = &cobra.Command{
+	Use:     "perf",
+	GroupID: GroupDiag,
+	Short:   "Measure how fast gt commands run on this machine",
+	Long: `Measure gt itself with an opt-in local telemetry log.
+
+When enabled, every gt command run in this town appends its latency and
+work counters (session scan time, transcripts parsed, cache hits and
+misses) to .runtime/perf.jsonl. Nothing leaves the machine. Use the
+report to check whether the index and daemon are actually helping.
+
+Examples:
+  gt perf enable
+  gt perf report
+  gt perf report --since 24h --command "gt seance"
+  gt perf disable`,
+	RunE: requireSubcommand,
+}
+
+var perfReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize recorded command latencies",
+	Long: `Summarize recorded command runs, slowest first.
+
+For each command: run count, p50/p95/max latency, average time spent
+scanning sessions, average transcripts parsed, and the cache hit rate.`,
+	Args: cobra.NoArgs,
+	RunE: runPerfReport,
+}
+
+var perfEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Start recording command latencies",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setTelemetry(true)
+	},
+}
+
+var perfDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stop recording command latencies",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setTelemetry(false)
+	},
+}
+
+func init() {
+	perfReportCmd.Flags().StringVar(&perfReportSince, "since", "7d", "Only include runs newer than this")
+	perfReportCmd.Flags().StringVar(&perfReportCommand, "command", "", "Only include commands starting with this (e.g., \"gt seance\")")
+	perfReportCmd.Flags().BoolVar(&perfReportJSON, "json", false, "Output as JSON")
+
+	perfCmd.AddCommand(perfReportCmd)
+	perfCmd.AddCommand(perfEnableCmd)
+	perfCmd.AddCommand(perfDisableCmd)
+	rootCmd.AddCommand(perfCmd)
+}
+
+// recordCommandPerf appends a telemetry record for the command that just
+// ran, if the town opted in. Errors are ignored: telemetry must never
+// break a command.
+func recordCommandPerf(cmd *cobra.Command, start time.Time, exitCode int) {
+	if cmd == nil {
+		return
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	if !loadTownSettingsQuiet(townRoot).TelemetryEnabled() {
+		return
+	}
+	_ = perf.Append(townRoot, perf.Record{
+		Command:  buildCommandPath(cmd),
+		Start:    start.UTC(),
+		Duration: time.Since(start),
+		ExitCode: exitCode,
+		Counters: perf.Snapshot(),
+	})
+}
+
+// setTelemetry turns the town's telemetry log on or off.
+func setTelemetry(enabled bool) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settingsPath := config.TownSettingsPath(townRoot)
+	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	townSettings.Telemetry = &config.TelemetryConfig{Enabled: enabled}
+	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+
+	if enabled {
+		fmt.Printf("%s Telemetry enabled: command timings go to %s\n", style.SuccessPrefix, perf.Path(townRoot))
+	} else {
+		fmt.Printf("%s Telemetry disabled (recorded timings kept; delete %s to discard them)\n",
+			style.SuccessPrefix, perf.Path(townRoot))
+	}
+	return nil
+}
+
+func runPerfReport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	since, err := parseDuration(perfReportSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+
+	records, err := perf.Load(townRoot, time.Now().Add(-since))
+	if err != nil {
+		return err
+	}
+	if perfReportCommand != "" {
+		var filtered []perf.Record
+		for _, rec := range records {
+			if strings.HasPrefix(rec.Command, perfReportCommand) {
+				filtered = append(filtered, rec)
+			}
+		}
+		records = filtered
+	}
+	stats := perf.Summarize(records)
+
+	if perfReportJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	if len(stats) == 0 {
+		fmt.Println("No command timings recorded.")
+		if !loadTownSettingsQuiet(townRoot).TelemetryEnabled() {
+			fmt.Println(style.Dim.Render("Telemetry is off. Turn it on with: gt perf enable"))
+		}
+		return nil
+	}
+
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Command Timings"),
+		style.Dim.Render(fmt.Sprintf("(%d runs, last %s)", len(records), perfReportSince)))
+	fmt.Printf("%-28s %5s %8s %8s %8s %8s %7s %6s\n",
+		"COMMAND", "RUNS", "P50", "P95", "MAX", "SCAN", "PARSED", "HIT%")
+	for _, s := range stats {
+		command := s.Command
+		if len(command) > 28 {
+			command = command[:27] + "…"
+		}
+		hit := "-"
+		if rate, ok := s.HitRate(); ok {
+			hit = fmt.Sprintf("%.0f%%", rate*100)
+		}
+		scan := "-"
+		if s.AvgScan > 0 {
+			scan = formatLatency(s.AvgScan)
+		}
+		fmt.Printf("%-28s %5d %8s %8s %8s %8s %7.0f %6s\n",
+			command, s.Runs, formatLatency(s.P50), formatLatency(s.P95), formatLatency(s.Max),
+			scan, s.AvgParsed, hit)
+	}
+	return nil
+}
+
+// formatLatency formats a command latency compactly (e.g., "85ms", "2.4s").
+func formatLatency(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	default:
+		return formatDuration(d)
+	}
+}
@@ -51,6 +51,11 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	// No new polecats in a frozen rig
+	if err := checkRigFreeze(townRoot, rigName); err != nil {
+		return nil, err
+	}
+
 	// Load rig config
 	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
 	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
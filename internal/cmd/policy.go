@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/policy"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	policyLintFix  bool
+	policyLintJSON bool
+)
+
+var policyCmd = &cobra.Command{
+	Use:     "policy",
+	GroupID: GroupConfig,
+	Short:   "Check guard rail configuration",
+	RunE:    requireSubcommand,
+}
+
+var policyLintCmd = &cobra.Command{
+	Use:   "lint [file...]",
+	Short: "Validate settings, messaging, plan, and task files",
+	Long: `Statically check the files that configure Gas Town's guard rails.
+
+The loaders ignore keys they don't recognize, so a misspelled setting
+(say "asign_guard") silently falls back to the default. Lint reports:
+
+  - unknown keys, with the closest known key
+  - keys that only match case-insensitively, and duplicate keys
+  - values of the wrong type
+  - invalid values: assign_guard, permission modes, on_conflict
+    strategies, durations, prices, session tag patterns
+  - mailing lists, queues, announces, and nudge channels with no members,
+    and negative announce retention
+  - plans with unknown fields, unindented tasks, or tasks without a role
+  - task specs with unknown fields, no goal, or invalid budgets
+
+Issues are reported as file:line:col. With no arguments, lint checks the
+town settings, config/messaging.json, and every rig's settings. Files
+given explicitly may also be mayor plans (.yaml, .yml, or .json) or
+task specs (task.yaml or <name>.task.yaml; see gt task lint).
+
+--fix renames keys with an unambiguous close match, corrects the case of
+enum values, and indents flush plan tasks, then re-checks the file.
+
+Exits non-zero when errors remain.
+
+Examples:
+  gt policy lint
+  gt policy lint --fix
+  gt policy lint plan.yaml settings/config.json`,
+	RunE: runPolicyLint,
+}
+
+func init() {
+	policyLintCmd.Flags().BoolVar(&policyLintFix, "fix", false, "Apply fixes for common mistakes")
+	policyLintCmd.Flags().BoolVar(&policyLintJSON, "json", false, "Output as JSON")
+
+	policyCmd.AddCommand(policyLintCmd)
+	rootCmd.AddCommand(policyCmd)
+}
+
+func runPolicyLint(cmd *cobra.Command, args []string) error {
+	files := args
+	if len(files) == 0 {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		files = policyFiles(townRoot)
+		if len(files) == 0 {
+			fmt.Printf("%s\n", style.Dim.Render("No settings or messaging files to check"))
+			return nil
+		}
+	}
+
+	results := make([]*policy.Result, 0, len(files))
+	fixed := make(map[string]int)
+	for _, path := range files {
+		result, err := policy.LintFile(path)
+		if err != nil {
+			return err
+		}
+		if policyLintFix && result.Fixable() > 0 {
+			if result, fixed[path], err = fixPolicyFile(result); err != nil {
+				return err
+			}
+		}
+		results = append(results, result)
+	}
+
+	errorCount := 0
+	for _, r := range results {
+		errorCount += r.Errors()
+	}
+
+	if policyLintJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printPolicyResults(results, fixed, policyLintFix)
+	}
+
+	if errorCount > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// policyFiles lists the town's guard rail config files that exist.
+func policyFiles(townRoot string) []string {
+	candidates := []string{
+		config.TownSettingsPath(townRoot),
+		config.MessagingConfigPath(townRoot),
+	}
+	if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		names := make([]string, 0, len(rigsConfig.Rigs))
+		for name := range rigsConfig.Rigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			candidates = append(candidates, config.RigSettingsPath(filepath.Join(townRoot, name)))
+		}
+	}
+
+	var files []string
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// policyFixPasses bounds --fix re-runs: a renamed key can expose a value
+// fix, which the next pass applies.
+const policyFixPasses = 3
+
+// fixPolicyFile applies a result's fixes to its file, re-linting after each
+// pass until nothing fixable remains.
+func fixPolicyFile(result *policy.Result) (*policy.Result, int, error) {
+	info, err := os.Stat(result.File)
+	if err != nil {
+		return nil, 0, err
+	}
+	data, err := os.ReadFile(result.File)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+	for pass := 0; pass < policyFixPasses && result.Fixable() > 0; pass++ {
+		var applied int
+		data, applied = policy.ApplyFixes(data, result.Issues)
+		if applied == 0 {
+			break
+		}
+		total += applied
+		result = policy.Lint(result.File, result.Kind, data)
+	}
+	if total == 0 {
+		return result, 0, nil
+	}
+	if err := os.WriteFile(result.File, data, info.Mode().Perm()); err != nil {
+		return nil, 0, fmt.Errorf("writing %s: %w", result.File, err)
+	}
+	return result, total, nil
+}
+
+// printPolicyResults prints lint issues as file:line:col, then a summary;
+// fixing is whether --fix was given.
+func printPolicyResults(results []*policy.Result, fixed map[string]int, fixing bool) {
+	errorCount, warnings, fixable := 0, 0, 0
+	for _, r := range results {
+		if n := fixed[r.File]; n > 0 {
+			fmt.Printf("%s Fixed %d issue(s) in %s\n", style.SuccessPrefix, n, r.File)
+		}
+		for _, issue := range r.Issues {
+			label := style.Error.Render(issue.Severity)
+			if issue.Severity == policy.SeverityWarning {
+				label = style.Warning.Render(issue.Severity)
+				warnings++
+			} else {
+				errorCount++
+			}
+			fmt.Printf("%s:%d:%d: %s: %s", issue.File, issue.Line, issue.Col, label, issue.Message)
+			if issue.Fix != nil {
+				fmt.Printf(" %s", style.Dim.Render("[fixable: "+issue.Fix.Description+"]"))
+				fixable++
+			}
+			fmt.Println()
+		}
+	}
+
+	if errorCount == 0 && warnings == 0 {
+		fmt.Printf("%s %d file(s) OK\n", style.SuccessPrefix, len(results))
+		return
+	}
+	summary := fmt.Sprintf("%d error(s), %d warning(s) in %d file(s)", errorCount, warnings, len(results))
+	if fixable > 0 && !fixing {
+		summary += fmt.Sprintf("; %d fixable with --fix", fixable)
+	}
+	fmt.Printf("\n%s\n", summary)
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/takeover"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	postmortemOut   string
+	postmortemForce bool
+	postmortemJSON  bool
+)
+
+// postmortemMaxErrors caps the errors listed in the report; the rest are
+// counted.
+const postmortemMaxErrors = 15
+
+var postmortemCmd = &cobra.Command{
+	Use:     "postmortem <session-id>",
+	GroupID: GroupDiag,
+	Short:   "Write a failure report for a session marked failed",
+	Long: `Assemble a structured failure report for a session, in Markdown.
+
+The report is built from the session's transcript: a timeline of prompts,
+errors, commits, and compactions; every failed tool call; the last good
+state (last commit, or last success before the first error); the tokens
+and cost spent after it; and prevention suggestions drawn from the
+patterns found (retry loops, permission blocks, compactions, uncommitted
+work, crashes).
+
+The session must have a failure or partial outcome (gt outcome set);
+use --force to report on any session.
+
+Examples:
+  gt postmortem abc123
+  gt postmortem abc123 -o postmortem.md
+  gt postmortem abc123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPostmortem,
+}
+
+func init() {
+	postmortemCmd.Flags().StringVarP(&postmortemOut, "out", "o", "", "Write the report to a file instead of stdout")
+	postmortemCmd.Flags().BoolVar(&postmortemForce, "force", false, "Report on a session not marked as failed")
+	postmortemCmd.Flags().BoolVar(&postmortemJSON, "json", false, "Output the report data as JSON")
+
+	rootCmd.AddCommand(postmortemCmd)
+}
+
+func runPostmortem(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	session, err := claude.FindSession(args[0])
+	if err != nil {
+		return err
+	}
+
+	rec, err := outcome.Get(townRoot, session.ID)
+	if err != nil {
+		return fmt.Errorf("reading outcomes: %w", err)
+	}
+	if !postmortemForce && (rec == nil || rec.Status == outcome.Success) {
+		status := "no outcome"
+		if rec != nil {
+			status = "outcome " + string(rec.Status)
+		}
+		return fmt.Errorf("session %s has %s; mark it with 'gt outcome set %s failure' or use --force",
+			session.ShortID(), status, session.ShortID())
+	}
+
+	report, err := claude.AnalyzeFailure(*session)
+	if err != nil {
+		return err
+	}
+	takeovers, err := takeover.Load(townRoot)
+	if err != nil {
+		return err
+	}
+
+	var out string
+	if postmortemJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		out = string(data) + "\n"
+	} else {
+		out = renderPostmortem(report, rec, takeovers[session.ID], loadTownSettingsQuiet(townRoot).PriceTable())
+	}
+
+	if postmortemOut == "" {
+		fmt.Print(out)
+		return nil
+	}
+	if err := os.WriteFile(postmortemOut, []byte(out), 0644); err != nil { //nolint:gosec // G306: report is not secret
+		return fmt.Errorf("writing report: %w", err)
+	}
+	fmt.Printf("%s Wrote post-mortem for %s to %s\n", style.SuccessPrefix, session.ShortID(), postmortemOut)
+	return nil
+}
+
+// renderPostmortem formats a failure report as Markdown.
+func renderPostmortem(r *claude.FailureReport, rec *outcome.Record, takeovers []takeover.Record, prices config.PriceTable) string {
+	var b strings.Builder
+	s := r.Session
+	ts := func(t time.Time) string { return t.Local().Format("2006-01-02 15:04:05") }
+
+	fmt.Fprintf(&b, "# Post-mortem: session %s\n\n", s.ShortID())
+	if s.Role != "" {
+		fmt.Fprintf(&b, "- **Agent:** %s\n", s.Role)
+	}
+	if s.Topic != "" {
+		fmt.Fprintf(&b, "- **Topic:** %s\n", s.Topic)
+	}
+	if rec != nil {
+		fmt.Fprintf(&b, "- **Outcome:** %s", rec.Status)
+		if rec.Reason != "" {
+			fmt.Fprintf(&b, " — %s", rec.Reason)
+		}
+		b.WriteString("\n")
+	}
+	if len(takeovers) > 0 {
+		fmt.Fprintf(&b, "- **Takeover:** %s — a blended session, not the agent's work alone\n", takeover.Describe(takeovers))
+	}
+	if !r.Start.IsZero() {
+		fmt.Fprintf(&b, "- **Ran:** %s → %s (%s)\n", ts(r.Start), ts(r.End), formatDuration(r.End.Sub(r.Start)))
+	}
+	fmt.Fprintf(&b, "- **Transcript:** `gt seance show %s`\n", s.ShortID())
+
+	b.WriteString("\n## Timeline\n\n")
+	if len(r.Timeline) == 0 {
+		b.WriteString("_No turns recorded._\n")
+	}
+	for _, ev := range r.Timeline {
+		fmt.Fprintf(&b, "- `%s` **%s** %s\n", ev.Time.Local().Format("15:04:05"), ev.Kind, ev.Detail)
+	}
+
+	fmt.Fprintf(&b, "\n## Errors (%d)\n\n", len(r.Errors))
+	if len(r.Errors) == 0 {
+		b.WriteString("_No tool call failed._\n")
+	}
+	for i, e := range r.Errors {
+		if i == postmortemMaxErrors {
+			fmt.Fprintf(&b, "- … and %d more\n", len(r.Errors)-postmortemMaxErrors)
+			break
+		}
+		fmt.Fprintf(&b, "- `%s` **%s**", e.Time.Local().Format("15:04:05"), e.Tool)
+		if e.Input != "" {
+			fmt.Fprintf(&b, " `%s`", strings.ReplaceAll(e.Input, "`", "'"))
+		}
+		fmt.Fprintf(&b, ": %s\n", e.Message)
+	}
+	if r.PendingTool != "" {
+		fmt.Fprintf(&b, "\nThe transcript ends while a **%s** call was still running.\n", r.PendingTool)
+	}
+
+	b.WriteString("\n## Last good state\n\n")
+	if r.LastGood == nil {
+		b.WriteString("_None found: no commit or successful tool call before the first error._\n")
+	} else {
+		fmt.Fprintf(&b, "`%s` %s\n", ts(r.LastGood.Time), r.LastGood.Detail)
+	}
+
+	b.WriteString("\n## Cost wasted\n\n")
+	if r.Usage != nil && r.Wasted != nil {
+		total, wasted := r.Usage.TotalTokens(), r.Wasted.TotalTokens()
+		fmt.Fprintf(&b, "%s of %s tokens were spent after the last good state",
+			formatTokenCount(float64(wasted)), formatTokenCount(float64(total)))
+		if cost := claude.EstimateCost(*r.Wasted, prices); cost > 0 {
+			fmt.Fprintf(&b, " (about $%.2f of $%.2f)", cost, claude.EstimateCost(*r.Usage, prices))
+		}
+		b.WriteString(".\n")
+	}
+
+	b.WriteString("\n## Suggested prevention\n\n")
+	for _, suggestion := range r.Suggestions {
+		fmt.Fprintf(&b, "- %s\n", suggestion)
+	}
+	return b.String()
+}
@@ -60,7 +60,10 @@ HOOK MODE (--hook):
   Claude Code sends JSON on stdin:
     {"session_id": "uuid", "transcript_path": "/path", "source": "startup|resume"}
 
-  Other agents can set GT_SESSION_ID environment variable instead.`,
+  Other agents can set GT_SESSION_ID environment variable instead.
+
+  On startup and resume, hook mode also writes DIGEST.md into the workspace:
+  mail, assignment changes, and rig commits since the previous session.`,
 	RunE: runPrime,
 }
 
@@ -110,8 +113,10 @@ func runPrime(cmd *cobra.Command, args []string) error {
 	}
 
 	// Handle hook mode: read session ID from stdin and persist it
+	var hookSource string
 	if primeHookMode {
 		sessionID, source := readHookSessionID()
+		hookSource = source
 		if !primeDryRun {
 			persistSessionID(townRoot, sessionID)
 			if cwd != townRoot {
@@ -131,10 +136,11 @@ func runPrime(cmd *cobra.Command, args []string) error {
 
 	// Check for handoff marker (prevents handoff loop bug)
 	// In dry-run mode, use the non-mutating version
+	postHandoff := false
 	if primeDryRun {
 		checkHandoffMarkerDryRun(cwd)
 	} else {
-		checkHandoffMarker(cwd)
+		postHandoff = checkHandoffMarker(cwd)
 	}
 
 	// Get role using env-aware detection
@@ -188,7 +194,7 @@ func runPrime(cmd *cobra.Command, args []string) error {
 
 	// Emit session_start event for seance discovery
 	if !primeDryRun {
-		emitSessionEvent(ctx)
+		emitSessionEvent(ctx, hookSource, postHandoff)
 	}
 
 	// Output session metadata for seance discovery
@@ -204,6 +210,9 @@ func runPrime(cmd *cobra.Command, args []string) error {
 	// Output handoff content if present
 	outputHandoffContent(ctx)
 
+	// Warn agents in a rig frozen with gt freeze
+	outputRigFreezeNotice(ctx)
+
 	// Output attachment status (for autonomous work detection)
 	outputAttachmentStatus(ctx)
 
@@ -218,6 +227,16 @@ func runPrime(cmd *cobra.Command, args []string) error {
 	// Output previous session checkpoint for crash recovery
 	outputCheckpointContext(ctx)
 
+	// Brief a session resumed with gt seance resume --with-context
+	if primeHookMode && !primeDryRun {
+		outputResumeContext(ctx, hookSource)
+	}
+
+	// Point the agent at DIGEST.md: what changed since its last session
+	if primeHookMode && !primeDryRun {
+		outputDigest(ctx, hookSource)
+	}
+
 	// Run bd prime to output beads workflow context
 	if !primeDryRun {
 		runBdPrime(cwd)
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/digest"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// digestMaxCommits caps the rig commits listed in a digest.
+const digestMaxCommits = 30
+
+// outputDigest writes DIGEST.md for a starting or resumed session: the
+// mail, assignment changes, and rig commits since the agent's previous
+// session started. The prime output points at it. Compaction continues
+// the same session, so it neither writes a digest nor resets the clock.
+func outputDigest(ctx RoleContext, source string) {
+	if ctx.Role == RoleUnknown || source == "compact" {
+		return
+	}
+	identity := getAgentIdentity(ctx)
+	if identity == "" {
+		return
+	}
+
+	now := time.Now()
+	prev, _ := digest.ReadState(ctx.WorkDir)
+	d := composeDigest(ctx, identity, prev, now)
+
+	next := &digest.State{LastSession: now, Assignments: make(map[string]string)}
+	for _, a := range d.Assignments {
+		if a.Change != digest.ChangeRemoved {
+			next.Assignments[a.ID] = a.Status
+		}
+	}
+	if len(d.Warnings) > 0 && prev != nil {
+		next.Assignments = prev.Assignments // lookups failed; keep the last known set
+	}
+	_ = digest.WriteState(ctx.WorkDir, next)
+
+	// The first session has nothing to catch up on
+	if prev == nil || d.Changes() == 0 {
+		return
+	}
+	if err := digest.Write(ctx.WorkDir, d); err != nil {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("%s\n\n", style.Bold.Render("## 📰 Digest"))
+	fmt.Printf("Since your previous session started %s ago: %d new mail, %d assignment change(s), %d rig commit(s).\n",
+		formatDuration(now.Sub(d.Since)), len(d.Mail), d.AssignmentChanges(), len(d.Commits))
+	fmt.Printf("Read %s before starting work.\n", digest.Filename)
+}
+
+// composeDigest gathers what changed for identity since prev.LastSession.
+// Sources that can't be read are noted in the digest's warnings.
+func composeDigest(ctx RoleContext, identity string, prev *digest.State, now time.Time) *digest.Digest {
+	d := &digest.Digest{Identity: identity, Generated: now}
+	var prevAssignments map[string]string
+	if prev != nil {
+		d.Since, prevAssignments = prev.LastSession, prev.Assignments
+	}
+
+	if mailbox, err := mail.NewRouter(ctx.TownRoot).GetMailbox(identity); err != nil {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("mail unavailable: %v", err))
+	} else if messages, err := mailbox.List(); err != nil {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("mail unavailable: %v", err))
+	} else {
+		for _, m := range messages {
+			if m.Timestamp.After(d.Since) {
+				d.Mail = append(d.Mail, digest.Mail{ID: m.ID, From: m.From, Subject: m.Subject, Time: m.Timestamp, Read: m.Read})
+			}
+		}
+	}
+
+	assignments, err := bannerAssignments(ctx.TownRoot, ctx.Role, ctx.Rig, identity)
+	if err != nil {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("assignments unavailable: %v", err))
+	}
+	current := make([]digest.Assignment, 0, len(assignments))
+	for _, a := range assignments {
+		current = append(current, digest.Assignment{ID: a.ID, Title: a.Title, Status: a.Status})
+	}
+	if err != nil {
+		d.Assignments = current // without a full list, removals can't be told apart
+	} else {
+		d.Assignments = digest.DiffAssignments(prevAssignments, current)
+	}
+
+	if ctx.Rig != "" && !d.Since.IsZero() {
+		commits, err := digestCommits(ctx, d.Since)
+		if err != nil {
+			d.Warnings = append(d.Warnings, fmt.Sprintf("rig commits unavailable: %v", err))
+		}
+		d.Commits = commits
+	}
+	return d
+}
+
+// digestCommits returns the commits on the rig's default branch since
+// since. The agent's own worktree is used when it is one; other rig
+// agents read the mayor's clone. Only refs already fetched are seen.
+func digestCommits(ctx RoleContext, since time.Time) ([]git.LogEntry, error) {
+	g := git.NewGit(ctx.WorkDir)
+	if !g.IsRepo() {
+		g = git.NewGit(filepath.Join(ctx.TownRoot, ctx.Rig, "mayor", "rig"))
+		if !g.IsRepo() {
+			return nil, nil
+		}
+	}
+	branch := g.RemoteDefaultBranch()
+	commits, err := g.CommitsSince("origin/"+branch, since, digestMaxCommits)
+	if err != nil {
+		return g.CommitsSince(branch, since, digestMaxCommits)
+	}
+	return commits, nil
+}
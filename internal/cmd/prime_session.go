@@ -150,7 +150,9 @@ func resolveSessionIDForPrime(actor string) string {
 // emitSessionEvent emits a session_start event for seance discovery.
 // The event is written to ~/gt/.events.jsonl and can be queried via gt seance.
 // Session ID resolution order: GT_SESSION_ID, CLAUDE_SESSION_ID, persisted file, fallback.
-func emitSessionEvent(ctx RoleContext) {
+// The hook source and post-handoff flag let seance link the session to its
+// predecessor when collapsing chains.
+func emitSessionEvent(ctx RoleContext, source string, postHandoff bool) {
 	if ctx.Role == RoleUnknown {
 		return
 	}
@@ -172,6 +174,12 @@ func emitSessionEvent(ctx RoleContext) {
 
 	// Emit the event
 	payload := events.SessionPayload(sessionID, actor, topic, ctx.WorkDir)
+	if source != "" {
+		payload["source"] = source
+	}
+	if postHandoff {
+		payload["handoff"] = true
+	}
 	_ = events.LogFeed(events.TypeSessionStart, actor, payload)
 }
 
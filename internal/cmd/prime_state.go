@@ -79,12 +79,13 @@ func detectSessionState(ctx RoleContext) SessionState {
 // This prevents the "handoff loop" bug where a new session sees /handoff in context
 // and incorrectly runs it again. The marker tells the new session: "handoff is DONE,
 // the /handoff you see in context was from YOUR PREDECESSOR, not a request for you."
-func checkHandoffMarker(workDir string) {
+// Returns true if a marker was found (this session is a handoff successor).
+func checkHandoffMarker(workDir string) bool {
 	markerPath := filepath.Join(workDir, constants.DirRuntime, constants.FileHandoffMarker)
 	data, err := os.ReadFile(markerPath)
 	if err != nil {
 		// No marker = not post-handoff, normal startup
-		return
+		return false
 	}
 
 	// Marker found - this is a post-handoff session
@@ -95,6 +96,7 @@ func checkHandoffMarker(workDir string) {
 
 	// Output prominent warning
 	outputHandoffWarning(prevSession)
+	return true
 }
 
 // checkHandoffMarkerDryRun checks for handoff marker without removing it (for --dry-run).
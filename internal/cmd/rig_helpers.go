@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"sync"
 
+	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
@@ -25,6 +27,8 @@ func getRig(rigName string) (string, *rig.Rig, error) {
 		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
 	}
 
+	rigName = loadTownSettingsQuiet(townRoot).ResolveRigAlias(rigName)
+
 	g := git.NewGit(townRoot)
 	rigMgr := rig.NewManager(townRoot, rigsConfig, g)
 	r, err := rigMgr.GetRig(rigName)
@@ -34,3 +38,52 @@ func getRig(rigName string) (string, *rig.Rig, error) {
 
 	return townRoot, r, nil
 }
+
+// loadTownSettingsQuiet loads town settings, returning nil on any error.
+// Used by display and alias helpers that must never fail a command.
+func loadTownSettingsQuiet(townRoot string) *config.TownSettings {
+	if townRoot == "" {
+		return nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return settings
+}
+
+// displaySettings is the current town's settings, loaded once for display
+// helpers; nil outside a town.
+var displaySettings = sync.OnceValue(func() *config.TownSettings {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return nil
+	}
+	return loadTownSettingsQuiet(townRoot)
+})
+
+// compactAddress shortens the rig of an agent address, or a bare rig
+// name, to its configured alias, so table columns stay narrow
+// ("gastown/crew/joe" -> "gt/crew/joe").
+func compactAddress(address string) string {
+	return displaySettings().CompactAddress(address)
+}
+
+// resolveRigFlag rewrites a --rig flag given as an alias (e.g., "gt") to the
+// full rig name, so every command accepting --rig also accepts aliases.
+func resolveRigFlag(cmd *cobra.Command) {
+	flag := cmd.Flags().Lookup("rig")
+	if flag == nil || !flag.Changed || flag.Value.Type() != "string" {
+		return
+	}
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+
+	resolved := loadTownSettingsQuiet(townRoot).ResolveRigAlias(flag.Value.String())
+	if resolved != flag.Value.String() {
+		_ = flag.Value.Set(resolved)
+	}
+}
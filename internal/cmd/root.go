@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/actions"
+	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/version"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -27,20 +31,26 @@ across distributed teams of AI agents working on shared codebases.`,
 // Commands that don't require beads to be installed/checked.
 // These are basic utility commands that should work without beads.
 var beadsExemptCommands = map[string]bool{
-	"version":    true,
-	"help":       true,
-	"completion": true,
+	"version":     true,
+	"help":        true,
+	"completion":  true,
+	"pause-gate":  true, // Runs before every tool call
+	"heartbeat":   true, // Runs from hooks and patrols
+	"record-hook": true, // Runs from canary session hooks
 }
 
 // Commands exempt from the town root branch warning.
 // These are commands that help fix the problem or are diagnostic.
 var branchCheckExemptCommands = map[string]bool{
-	"version":    true,
-	"help":       true,
-	"completion": true,
-	"doctor":     true, // Used to fix the problem
-	"install":    true, // Initial setup
-	"git-init":   true, // Git setup
+	"version":     true,
+	"help":        true,
+	"completion":  true,
+	"doctor":      true, // Used to fix the problem
+	"install":     true, // Initial setup
+	"git-init":    true, // Git setup
+	"pause-gate":  true, // Runs before every tool call
+	"heartbeat":   true, // Runs from hooks and patrols
+	"record-hook": true, // Runs from canary session hooks
 }
 
 // persistentPreRun runs before every command.
@@ -48,6 +58,12 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	// Get the root command name being run
 	cmdName := cmd.Name()
 
+	// Expand rig aliases before any command sees its --rig flag
+	resolveRigFlag(cmd)
+
+	// Log the sessions this command spawns, resumes, or kills
+	actions.Enable()
+
 	// Check town root branch (warning only, non-blocking)
 	if !branchCheckExemptCommands[cmdName] {
 		warnIfTownRootOffMain()
@@ -58,6 +74,12 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Scan the town's extra Claude homes in session discovery
+	useTownClaudeRoots()
+	if seanceLocalOnly {
+		claude.SetLocalOnly(true)
+	}
+
 	// Check beads version
 	return CheckBeadsVersion()
 }
@@ -72,7 +94,7 @@ func warnIfTownRootOffMain() {
 	}
 
 	// Check if it's a git repo
-	gitDir := townRoot + "/.git"
+	gitDir := filepath.Join(townRoot, ".git")
 	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
 		return
 	}
@@ -97,6 +119,18 @@ func warnIfTownRootOffMain() {
 		style.Dim.Render("gt doctor --fix"))
 }
 
+// useTownClaudeRoots adds the town's claude_roots to the Claude data
+// directories session discovery scans.
+func useTownClaudeRoots() {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	if roots := loadTownSettingsQuiet(townRoot).ClaudeRootDirs(townRoot); len(roots) > 0 {
+		claude.SetExtraRoots(roots)
+	}
+}
+
 // checkBeadsDependency verifies beads meets minimum version requirements.
 // Skips check for exempt commands (version, help, completion).
 // Deprecated: Use persistentPreRun instead, which calls CheckBeadsVersion.
@@ -158,15 +192,26 @@ func checkStaleBinaryWarning() {
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
 func Execute() int {
-	if err := rootCmd.Execute(); err != nil {
-		// Check for silent exit (scripting commands that signal status via exit code)
-		if code, ok := IsSilentExit(err); ok {
-			return code
-		}
-		// Other errors already printed by cobra
-		return 1
+	start := time.Now()
+	// Expand command aliases and rewrite deprecated spellings first
+	rootCmd.SetArgs(rewriteArgs(rootCmd, os.Args[1:], commandAliasExpansions, compatShims, os.Stderr))
+	cmd, err := rootCmd.ExecuteC()
+	code := exitCode(err)
+	recordCommandPerf(cmd, start, code)
+	return code
+}
+
+// exitCode maps a command error to the process exit code.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	// Check for silent exit (scripting commands that signal status via exit code)
+	if code, ok := IsSilentExit(err); ok {
+		return code
 	}
-	return 0
+	// Other errors already printed by cobra
+	return 1
 }
 
 // Command group IDs - used by subcommands to organize help output
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,18 +13,33 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/takeover"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
-	seanceRole   string
-	seanceRig    string
-	seanceRecent int
-	seanceTalk   string
-	seancePrompt string
-	seanceJSON   bool
+	seanceRole       string
+	seanceRig        string
+	seanceRecent     int
+	seanceSince      string
+	seanceUntil      string
+	seanceTalk       string
+	seancePrompt     string
+	seanceJSON       bool
+	seanceGlobal     bool
+	seanceNoCollapse bool
+	seanceWatch      bool
+	seanceLocalOnly  bool
+	seanceSort       string
+	seanceSortAsc    bool
+	seanceBranch     string
+	seanceTag        string
+	seanceOutcome    string
 )
 
 var seanceCmd = &cobra.Command{
@@ -45,14 +61,66 @@ DISCOVERY:
   gt seance --role crew         # Filter by role type
   gt seance --rig gastown       # Filter by rig
   gt seance --recent 10         # Last N sessions
+  gt seance --since 2d --until yesterday  # Sessions started in a window
+  gt seance --global            # All rigs, even when run inside one
+  gt seance --no-collapse       # One row per session, not per chain
+  gt seance --sort cost         # Costliest first (also end, duration, role, rig)
+  gt seance --sort role --asc   # Reverse the order
+  gt seance --branch feat/login # Sessions that worked on a git branch (or "polecat/*")
+  gt seance --tag do-not-resume # Sessions with a tag (gt seance tag, or session_tags rules)
+  gt seance --outcome blocked   # Sessions whose work ended blocked (also completed, handoff, abandoned)
+  gt seance --watch             # Then stream new sessions and turns live
+
+CHAINS:
+  Sessions that continue an agent's previous session (resumed, compacted,
+  or started by gt handoff) are collapsed into one row showing the latest
+  session, with a badge like "×4" counting the sessions in the chain.
+  Resumes are also recognized from the transcripts, which repeat the
+  history they continue. SPAN is how long the whole chain ran.
+  Sessions a human and an agent both worked on (gt takeover) show "⇄".
+  Sessions that are risky to resume are flagged: ✂ a transcript line too
+  long to parse, ◕ context nearly full when the session ended, ↻ more
+  than three compactions, ‼ over a quarter of its tool calls failed.
+  --json lists the reasons under "warnings".
+  Sessions that died mid-task are marked ✗ (crashed in the last day) or
+  ∅ (abandoned longer ago); --json has each session's "state".
+
+OUTCOMES:
+  Each session's work is classified by how it ended: completed (gt done,
+  or its bead closed), blocked (gt escalate, its bead marked blocked, or
+  a final reply saying it is stuck), handoff (gt handoff), or abandoned
+  (it stopped mid-turn, or with its assigned bead still open). Sessions
+  still running have none yet. --outcome filters on the latest session
+  of each row; --json has each row's "outcome".
+  gt seance lineage <id> shows a session's resumes and forks as a tree.
+
+SCOPE:
+  Inside a rig, seance shows only that rig's sessions (detected from the
+  working directory via the rig registry). Use --global to see the whole
+  town, or --rig to pick a different rig.
 
 THE SEANCE (talk to predecessor):
   gt seance --talk <session-id>              # Interactive conversation
   gt seance --talk <id> -p "Where is X?"     # One-shot question
+  gt seance diff <a> <b> --conversational    # Compare two attempts at a task
+  gt seance alias <id> auth-refactor-v2      # Name a session; use the name as its ID
+  gt seance tag <id> good-handoff            # Label a session; shown under its row
+  gt seance note <id> "resume from step 3"   # Leave a note on a session
+  gt seance export <id> -o session.html      # Share a readable copy of a session
+  gt seance summarize --since 2d             # Summarize sessions that lack a summary
+  gt seance archive --older-than 30d         # Move old transcripts into an archive
+  gt seance prune --older-than 90d --dry-run # See what deleting old transcripts would free
+  gt seance stats                            # Which rigs and projects use the most disk
 
 The --talk flag spawns: claude --fork-session --resume <id>
 This loads the predecessor's full context without modifying their session.
 
+SLOW FILESYSTEMS:
+  Discovery gives up after 30s ($GT_DISCOVERY_TIMEOUT) on a slow or hung
+  filesystem, such as an NFS home directory, and shows what it found with
+  a warning. --local-only (or GT_LOCAL_ONLY=1) skips Claude data
+  directories on network filesystems altogether.
+
 Sessions are discovered from:
   1. Events emitted by SessionStart hooks (~/gt/.events.jsonl)
   2. The [GAS TOWN] beacon makes sessions searchable in /resume`,
@@ -63,9 +131,20 @@ func init() {
 	seanceCmd.Flags().StringVar(&seanceRole, "role", "", "Filter by role (crew, polecat, witness, etc.)")
 	seanceCmd.Flags().StringVar(&seanceRig, "rig", "", "Filter by rig name")
 	seanceCmd.Flags().IntVarP(&seanceRecent, "recent", "n", 20, "Number of recent sessions to show")
+	seanceCmd.Flags().StringVar(&seanceSince, "since", "", "Only sessions started at or after this time (e.g., 2d, 6h, today, 2025-01-02)")
+	seanceCmd.Flags().StringVar(&seanceUntil, "until", "", "Only sessions started before this time (same formats as --since)")
 	seanceCmd.Flags().StringVarP(&seanceTalk, "talk", "t", "", "Session ID to commune with")
 	seanceCmd.Flags().StringVarP(&seancePrompt, "prompt", "p", "", "One-shot prompt (with --talk)")
 	seanceCmd.Flags().BoolVar(&seanceJSON, "json", false, "Output as JSON")
+	seanceCmd.Flags().BoolVar(&seanceNoCollapse, "no-collapse", false, "List every session instead of collapsing resumed and handed-off chains")
+	seanceCmd.Flags().StringVar(&seanceSort, "sort", "", "Order by start, end, duration, role, rig, or cost (default: start)")
+	seanceCmd.Flags().BoolVar(&seanceSortAsc, "asc", false, "Sort ascending instead of descending")
+	seanceCmd.Flags().StringVar(&seanceBranch, "branch", "", "Only sessions that had this git branch checked out (* patterns allowed)")
+	seanceCmd.Flags().StringVar(&seanceTag, "tag", "", "Only sessions with this tag")
+	seanceCmd.Flags().StringVar(&seanceOutcome, "outcome", "", "Only sessions whose work ended this way: completed, blocked, handoff, or abandoned")
+	seanceCmd.Flags().BoolVarP(&seanceWatch, "watch", "w", false, "After listing, stream new sessions and turns as they are written")
+	seanceCmd.PersistentFlags().BoolVar(&seanceGlobal, "global", false, "Show sessions from every rig, ignoring the current directory")
+	seanceCmd.PersistentFlags().BoolVar(&seanceLocalOnly, "local-only", false, "Skip Claude data directories on network filesystems")
 
 	rootCmd.AddCommand(seanceCmd)
 }
@@ -84,8 +163,16 @@ func runSeance(cmd *cobra.Command, args []string) error {
 		return runSeanceTalk(seanceTalk, seancePrompt)
 	}
 
+	// With --json, --watch streams events instead of listing first
+	if seanceWatch && seanceJSON {
+		return runSeanceWatch()
+	}
+
 	// Otherwise, list discoverable sessions
-	return runSeanceList()
+	if err := runSeanceList(); err != nil || !seanceWatch {
+		return err
+	}
+	return runSeanceWatch()
 }
 
 func runSeanceList() error {
@@ -100,9 +187,33 @@ func runSeanceList() error {
 		return fmt.Errorf("discovering sessions: %w", err)
 	}
 
+	since, until, err := parseTimeRange(seanceSince, seanceUntil, time.Now())
+	if err != nil {
+		return err
+	}
+	sortBy, err := claude.ParseSessionSort(seanceSort)
+	if err != nil {
+		return err
+	}
+	var outcome claude.SessionOutcome
+	if seanceOutcome != "" {
+		if outcome, err = claude.ParseSessionOutcome(seanceOutcome); err != nil {
+			return err
+		}
+	}
+
+	// Scope to the current rig unless told otherwise
+	scopeRig := ""
+	if seanceRig == "" && !seanceGlobal {
+		scopeRig = seanceScopeRig(townRoot)
+	}
+
 	// Apply filters
 	var filtered []sessionEvent
 	for _, s := range sessions {
+		if scopeRig != "" && !strings.HasPrefix(strings.ToLower(s.Actor), strings.ToLower(scopeRig)+"/") {
+			continue
+		}
 		if seanceRole != "" {
 			actor := strings.ToLower(s.Actor)
 			if !strings.Contains(actor, strings.ToLower(seanceRole)) {
@@ -115,21 +226,99 @@ func runSeanceList() error {
 				continue
 			}
 		}
+		if !since.IsZero() || !until.IsZero() {
+			started, err := time.Parse(time.RFC3339, s.Timestamp)
+			if err != nil || started.Before(since) || (!until.IsZero() && !started.Before(until)) {
+				continue
+			}
+		}
 		filtered = append(filtered, s)
 	}
 
+	var rows []seanceRow
+	if seanceNoCollapse {
+		for _, s := range filtered {
+			rows = append(rows, seanceRow{sessionEvent: s})
+		}
+	} else {
+		rows = collapseSessionChains(filtered)
+	}
+	if takeovers, err := takeover.Load(townRoot); err == nil {
+		markTakeovers(rows, takeovers)
+	}
+
+	// Events come newest first; any other order needs the transcripts
+	var transcripts []claude.SessionInfo
+	sorted := sortBy != claude.SortByStart || seanceSortAsc
+	if len(rows) > 0 {
+		tagger, tagErr := loadTownSettingsQuiet(townRoot).SessionTagger()
+		if tagErr != nil && seanceTag != "" {
+			return tagErr
+		}
+		// Only the rows' rig is listed, so leave other rigs' shards unread
+		rig := seanceRig
+		if rig == "" {
+			rig = scopeRig
+		}
+		transcripts, err = discoverClaudeSessions(claude.SessionFilter{
+			GasTownOnly:     true,
+			Rig:             rig,
+			SortBy:          sortBy,
+			SortAsc:         seanceSortAsc,
+			Prices:          loadTownSettingsQuiet(townRoot).PriceTable(),
+			CollapseResumes: !seanceNoCollapse,
+			Tagger:          tagger,
+		})
+		if err != nil && sorted {
+			return fmt.Errorf("discovering sessions to sort: %w", err)
+		}
+		if err != nil && seanceBranch != "" {
+			return fmt.Errorf("discovering sessions to match --branch: %w", err)
+		}
+		if err != nil && seanceTag != "" {
+			return fmt.Errorf("discovering sessions to match --tag: %w", err)
+		}
+		if err != nil && outcome != "" {
+			return fmt.Errorf("discovering sessions to match --outcome: %w", err)
+		}
+	}
+	if !seanceNoCollapse {
+		rows = mergeResumeChains(rows, transcripts)
+	}
+	if seanceBranch != "" {
+		rows = filterRowsByBranch(rows, transcripts, seanceBranch)
+	}
+	if seanceTag != "" {
+		rows = filterRowsByTag(rows, transcripts, seanceTag)
+	}
+	if outcome != "" {
+		rows = filterRowsByOutcome(rows, transcripts, outcome)
+	}
+	if sorted {
+		sortSeanceRows(rows, transcripts)
+	}
+
 	// Apply limit
-	if seanceRecent > 0 && len(filtered) > seanceRecent {
-		filtered = filtered[:seanceRecent]
+	if seanceRecent > 0 && len(rows) > seanceRecent {
+		rows = rows[:seanceRecent]
 	}
 
+	// Flag sessions that died or are near a limit; best effort, the
+	// listing works without
+	markSessionInfo(rows, transcripts, claude.Limits{})
+
 	if seanceJSON {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
-		return enc.Encode(filtered)
+		return enc.Encode(rows)
 	}
 
-	if len(filtered) == 0 {
+	if len(rows) == 0 {
+		if scopeRig != "" {
+			fmt.Printf("No session events found in rig %s.\n", scopeRig)
+			fmt.Println(style.Dim.Render("Use --global to list sessions from every rig"))
+			return nil
+		}
 		fmt.Println("No session events found.")
 		fmt.Println(style.Dim.Render("Sessions are discovered from ~/gt/.events.jsonl"))
 		fmt.Println(style.Dim.Render("Ensure SessionStart hooks emit session_start events"))
@@ -137,28 +326,48 @@ func runSeanceList() error {
 	}
 
 	// Print header
-	fmt.Printf("%s\n\n", style.Bold.Render("Discoverable Sessions"))
+	if scopeRig != "" {
+		fmt.Printf("%s %s\n\n", style.Bold.Render("Discoverable Sessions"),
+			style.Dim.Render("in rig "+scopeRig+" (--global for all)"))
+	} else {
+		fmt.Printf("%s\n\n", style.Bold.Render("Discoverable Sessions"))
+	}
 
 	// Column widths
 	idWidth := 12
 	roleWidth := 26
 	timeWidth := 16
+	spanWidth := 10
 	topicWidth := 28
 
-	fmt.Printf("%-*s  %-*s  %-*s  %-*s\n",
+	fmt.Printf("%-*s  %-*s  %-*s  %-*s  %-*s\n",
 		idWidth, "SESSION_ID",
 		roleWidth, "ROLE",
 		timeWidth, "STARTED",
+		spanWidth, "SPAN",
 		topicWidth, "TOPIC")
-	fmt.Printf("%s\n", strings.Repeat("─", idWidth+roleWidth+timeWidth+topicWidth+6))
+	fmt.Printf("%s\n", strings.Repeat("─", idWidth+roleWidth+timeWidth+spanWidth+topicWidth+8))
 
-	for _, s := range filtered {
+	townSettings := loadTownSettingsQuiet(townRoot)
+	aliases := seanceAliasNames()
+	for _, s := range rows {
 		sessionID := getPayloadString(s.Payload, "session_id")
-		if len(sessionID) > idWidth {
-			sessionID = sessionID[:idWidth-1] + "…"
+		if alias, ok := aliases[sessionID]; ok {
+			sessionID = alias
+		}
+		badge := s.badge()
+		width := idWidth
+		if badge != "" {
+			width -= len([]rune(badge)) + 1
+		}
+		if len(sessionID) > width {
+			sessionID = sessionID[:width-1] + "…"
+		}
+		if badge != "" {
+			sessionID += " " + badge
 		}
 
-		role := s.Actor
+		role := townSettings.CompactAddress(s.Actor)
 		if len(role) > roleWidth {
 			role = role[:roleWidth-1] + "…"
 		}
@@ -173,11 +382,24 @@ func runSeanceList() error {
 			topic = topic[:topicWidth-1] + "…"
 		}
 
-		fmt.Printf("%-*s  %-*s  %-*s  %-*s\n",
+		span := "-"
+		if s.Span > 0 {
+			span = formatDuration(s.Span)
+		}
+
+		fmt.Printf("%-*s  %-*s  %-*s  %-*s  %-*s\n",
 			idWidth, sessionID,
 			roleWidth, role,
 			timeWidth, timeStr,
+			spanWidth, span,
 			topicWidth, topic)
+		if labels := s.labels(); labels != "" {
+			fmt.Printf("  %s\n", style.Dim.Render(labels))
+		}
+	}
+
+	if legend := seanceWarningLegend(rows); legend != "" {
+		fmt.Printf("\n%s\n", style.Dim.Render(legend))
 	}
 
 	fmt.Printf("\n%s\n", style.Bold.Render("Talk to a predecessor:"))
@@ -189,7 +411,10 @@ func runSeanceList() error {
 
 func runSeanceTalk(sessionID, prompt string) error {
 	// Expand short IDs if needed (user might provide partial)
-	// For now, require full ID or let claude --resume handle it
+	// For now, require full ID or alias, or let claude --resume handle it
+	if id, ok := claude.ResolveAlias(sessionID); ok {
+		sessionID = id
+	}
 
 	fmt.Printf("%s Summoning session %s...\n\n", style.Bold.Render("🔮"), sessionID)
 
@@ -232,6 +457,34 @@ func runSeanceTalk(sessionID, prompt string) error {
 	return nil
 }
 
+// seanceScopeRig returns the registered rig containing the current
+// directory, or "" when cwd is outside any rig.
+func seanceScopeRig(townRoot string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+		cwd = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(townRoot); err == nil {
+		townRoot = resolved
+	}
+
+	rig := detectRigFromPath(townRoot, cwd)
+	if rig == "" {
+		return ""
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return ""
+	}
+	if _, ok := rigsConfig.Rigs[rig]; !ok {
+		return ""
+	}
+	return rig
+}
+
 // discoverSessions reads session_start events from our event stream.
 func discoverSessions(townRoot string) ([]sessionEvent, error) {
 	eventsPath := filepath.Join(townRoot, events.EventsFile)
@@ -287,3 +540,27 @@ func formatEventTime(ts string) string {
 	}
 	return t.Local().Format("2006-01-02 15:04")
 }
+
+// seanceAliasNames maps session IDs to the alias listed in their place,
+// the alphabetically first when a session has several.
+func seanceAliasNames() map[string]string {
+	names := make(map[string]string)
+	for alias, id := range claude.Aliases() {
+		if prev, ok := names[id]; !ok || alias < prev {
+			names[id] = alias
+		}
+	}
+	return names
+}
+
+// discoverClaudeSessions runs claude.DiscoverSessions, warning instead of
+// failing when a slow filesystem cuts discovery short.
+func discoverClaudeSessions(filter claude.SessionFilter) ([]claude.SessionInfo, error) {
+	sessions, err := claude.DiscoverSessions(filter)
+	if errors.Is(err, claude.ErrIncomplete) {
+		fmt.Fprintf(os.Stderr, "%s %v\n", style.WarningPrefix, err)
+		fmt.Fprintf(os.Stderr, "    %s Showing partial results; --local-only skips network filesystems\n", style.ArrowPrefix)
+		return sessions, nil
+	}
+	return sessions, err
+}
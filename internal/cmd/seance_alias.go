@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceAliasRemove bool
+	seanceAliasJSON   bool
+)
+
+var seanceAliasCmd = &cobra.Command{
+	Use:   "alias [<session-id> <alias>]",
+	Short: "Give a session a memorable name",
+	Long: `Give a session an alias that works anywhere a session ID does, so
+"auth-refactor-v2" can stand in for a short hash in conversation and in
+commands like gt seance show, diff, and --talk.
+
+A session can have several aliases; setting an alias that already names
+another session moves it. Aliases use letters, digits, '-', '_', and '.',
+and can't look like a session ID. They are stored in the state directory,
+so they are kept when the session index is rebuilt or cleared.
+
+Examples:
+  gt seance alias abc123 auth-refactor-v2   # Name a session
+  gt seance show auth-refactor-v2
+  gt seance alias                           # List aliases
+  gt seance alias --remove auth-refactor-v2`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case seanceAliasRemove && len(args) != 1:
+			return fmt.Errorf("--remove takes one alias")
+		case !seanceAliasRemove && len(args) != 0 && len(args) != 2:
+			return fmt.Errorf("expected <session-id> <alias>, or no arguments to list aliases")
+		}
+		return nil
+	},
+	RunE: runSeanceAlias,
+}
+
+func init() {
+	seanceAliasCmd.Flags().BoolVarP(&seanceAliasRemove, "remove", "d", false, "Remove an alias")
+	seanceAliasCmd.Flags().BoolVar(&seanceAliasJSON, "json", false, "Output as JSON (when listing)")
+
+	seanceCmd.AddCommand(seanceAliasCmd)
+}
+
+func runSeanceAlias(cmd *cobra.Command, args []string) error {
+	switch {
+	case seanceAliasRemove:
+		if err := claude.RemoveAlias(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("%s Removed alias %s\n", style.SuccessPrefix, args[0])
+		return nil
+	case len(args) == 2:
+		session, err := claude.FindSession(args[0])
+		if err != nil {
+			return err
+		}
+		if err := claude.SetAlias(args[1], session.ID); err != nil {
+			return fmt.Errorf("setting alias: %w", err)
+		}
+		fmt.Printf("%s %s is now %s\n", style.SuccessPrefix, session.ShortID(), style.Bold.Render(args[1]))
+		return nil
+	}
+
+	aliases := claude.Aliases()
+	if seanceAliasJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(aliases)
+	}
+	if len(aliases) == 0 {
+		fmt.Println("No session aliases.")
+		fmt.Println(style.Dim.Render("Name one with: gt seance alias <session-id> <alias>"))
+		return nil
+	}
+
+	names := make([]string, 0, len(aliases))
+	width := 0
+	for name := range aliases {
+		names = append(names, name)
+		width = max(width, len(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("  %-*s  %s\n", width, name, style.Dim.Render(aliases[name]))
+	}
+	return nil
+}
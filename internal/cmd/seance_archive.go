@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceBundleOut  string
+	seanceVerifyJSON bool
+
+	seanceOlderThan     string
+	seanceArchiveTo     string
+	seanceArchiveDir    string
+	seanceRetentionDry  bool
+	seanceRetentionJSON bool
+)
+
+var seanceBundleCmd = &cobra.Command{
+	Use:   "bundle <session-id>...",
+	Short: "Bundle session transcripts into a checksummed archive",
+	Long: `Bundle one or more session transcripts into a .tar.gz archive.
+
+The archive carries a manifest with the SHA-256 of every transcript,
+and a <archive>.sha256 sidecar records the digest of the archive itself.
+Use 'gt seance verify-archive' to detect corruption or tampering later.
+
+The archive is built in <archive>.partial. If bundling is interrupted,
+running the same command again resumes from the last transcript written.
+
+Examples:
+  gt seance bundle abc123 def456 --out incident-42.tar.gz`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSeanceBundle,
+}
+
+var seanceVerifyArchiveCmd = &cobra.Command{
+	Use:   "verify-archive <archive>",
+	Short: "Verify a session archive against its checksums",
+	Long: `Verify a session archive for corruption or tampering.
+
+Checks:
+  - The archive matches its <archive>.sha256 sidecar (if present)
+  - Every transcript listed in the manifest is present and its SHA-256 matches
+  - No transcripts were added that aren't in the manifest
+
+Exits non-zero if any check fails.
+
+Examples:
+  gt seance verify-archive incident-42.tar.gz
+  gt seance verify-archive incident-42.tar.gz --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSeanceVerifyArchive,
+}
+
+var seanceArchiveCmd = &cobra.Command{
+	Use:   "archive --older-than <age>",
+	Short: "Move old session transcripts into a compressed archive",
+	Long: `Move the transcripts of sessions last written before a cutoff, with
+their subagents' transcripts, out of ~/.claude/projects into a new
+checksummed .tar.gz archive.
+
+Archives go to ~/.local/state/gastown/session-archives unless --to is
+given. The transcripts are removed only once the archive verifies, and
+sessions still being written are never archived. Archived sessions are
+listed by 'gt seance archived' and put back by 'gt seance unarchive',
+into the Claude directory they came from.
+
+Only this machine's own Claude directory is archived; transcripts read
+from the town's claude_roots (other users' or machines' homes) are left
+alone.
+
+--older-than takes an age (30d, 2w) or a date (2025-01-02).
+
+Examples:
+  gt seance archive --older-than 30d --dry-run
+  gt seance archive --older-than 30d
+  gt seance archive --older-than 2025-01-01 --to /mnt/backup/sessions`,
+	Args: cobra.NoArgs,
+	RunE: runSeanceArchive,
+}
+
+var seancePruneCmd = &cobra.Command{
+	Use:   "prune --older-than <age>",
+	Short: "Delete old session transcripts",
+	Long: `Delete the transcripts of sessions last written before a cutoff, with
+their subagents' transcripts, without archiving them. Sessions still being
+written are never deleted, and neither are transcripts read from the
+town's claude_roots: only this machine's own Claude directory is pruned.
+
+This can't be undone; run with --dry-run first, or use 'gt seance archive'
+to keep a copy.
+
+Examples:
+  gt seance prune --older-than 90d --dry-run
+  gt seance prune --older-than 90d`,
+	Args: cobra.NoArgs,
+	RunE: runSeancePrune,
+}
+
+var seanceArchivedCmd = &cobra.Command{
+	Use:   "archived",
+	Short: "List sessions moved into archives",
+	Long: `List the sessions in the archives written by 'gt seance archive',
+newest archive first.
+
+Examples:
+  gt seance archived
+  gt seance archived --dir /mnt/backup/sessions --json`,
+	Args: cobra.NoArgs,
+	RunE: runSeanceArchived,
+}
+
+var seanceUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive <session-id>",
+	Short: "Restore an archived session's transcript",
+	Long: `Copy an archived session's transcript, and its subagents', back into
+~/.claude/projects so seance, export, and the other session commands can
+read it again. The archive is left as it is.
+
+Examples:
+  gt seance unarchive abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSeanceUnarchive,
+}
+
+func init() {
+	seanceBundleCmd.Flags().StringVarP(&seanceBundleOut, "out", "o", "", "Archive path (required)")
+	_ = seanceBundleCmd.MarkFlagRequired("out")
+	seanceVerifyArchiveCmd.Flags().BoolVar(&seanceVerifyJSON, "json", false, "Output as JSON")
+
+	for _, cmd := range []*cobra.Command{seanceArchiveCmd, seancePruneCmd} {
+		cmd.Flags().StringVar(&seanceOlderThan, "older-than", "", "Only sessions last written before this age or date (required)")
+		cmd.Flags().BoolVar(&seanceRetentionDry, "dry-run", false, "Show what would be removed without changing anything")
+		cmd.Flags().BoolVar(&seanceRetentionJSON, "json", false, "Output as JSON")
+		_ = cmd.MarkFlagRequired("older-than")
+	}
+	seanceArchiveCmd.Flags().StringVar(&seanceArchiveTo, "to", "", "Directory to write the archive in (default: ~/.local/state/gastown/session-archives)")
+	seanceArchivedCmd.Flags().StringVar(&seanceArchiveDir, "dir", "", "Archive directory (default: ~/.local/state/gastown/session-archives)")
+	seanceArchivedCmd.Flags().BoolVar(&seanceRetentionJSON, "json", false, "Output as JSON")
+	seanceUnarchiveCmd.Flags().StringVar(&seanceArchiveDir, "dir", "", "Archive directory (default: ~/.local/state/gastown/session-archives)")
+
+	seanceCmd.AddCommand(seanceBundleCmd)
+	seanceCmd.AddCommand(seanceVerifyArchiveCmd)
+	seanceCmd.AddCommand(seanceArchiveCmd)
+	seanceCmd.AddCommand(seancePruneCmd)
+	seanceCmd.AddCommand(seanceArchivedCmd)
+	seanceCmd.AddCommand(seanceUnarchiveCmd)
+}
+
+func runSeanceBundle(cmd *cobra.Command, args []string) error {
+	var sessions []claude.SessionInfo
+	for _, id := range args {
+		s, err := claude.FindSession(id)
+		if err != nil {
+			return err
+		}
+		sessions = append(sessions, *s)
+	}
+
+	manifest, err := claude.WriteArchive(seanceBundleOut, sessions, terminalProgress())
+	if err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+
+	fmt.Printf("%s Bundled %d session(s) into %s\n", style.SuccessPrefix, len(manifest.Files), seanceBundleOut)
+	for _, f := range manifest.Files {
+		fmt.Printf("  %s  %s\n", style.Dim.Render(f.SHA256[:12]), f.Name)
+	}
+	fmt.Printf("  %s\n", style.Dim.Render("checksum: "+seanceBundleOut+".sha256"))
+	return nil
+}
+
+func runSeanceVerifyArchive(cmd *cobra.Command, args []string) error {
+	v, err := claude.VerifyArchive(args[0])
+	if err != nil {
+		return fmt.Errorf("reading archive: %w", err)
+	}
+
+	if seanceVerifyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	} else {
+		printArchiveVerification(v)
+	}
+
+	if !v.OK() {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func printArchiveVerification(v *claude.ArchiveVerification) {
+	if v.OK() {
+		fmt.Printf("%s %s: %d file(s) verified\n", style.SuccessPrefix, v.Path, v.FilesVerified)
+		if !v.SidecarChecked {
+			fmt.Printf("  %s\n", style.Dim.Render("no .sha256 sidecar - manifest edits can't be detected"))
+		}
+		return
+	}
+
+	fmt.Printf("%s %s: %d problem(s)\n", style.ErrorPrefix, v.Path, len(v.Problems))
+	for _, p := range v.Problems {
+		fmt.Printf("  %s %s\n", style.Bold.Render(p.Name), p.Reason)
+	}
+}
+
+func runSeanceArchive(cmd *cobra.Command, args []string) error {
+	cutoff, err := parseOlderThan(seanceOlderThan)
+	if err != nil {
+		return err
+	}
+	result, err := claude.ArchiveSessions(cutoff, seanceArchiveTo, seanceRetentionDry, terminalProgress())
+	if err != nil {
+		return err
+	}
+	return printRetentionResult(result, "Archived", "archive")
+}
+
+func runSeancePrune(cmd *cobra.Command, args []string) error {
+	cutoff, err := parseOlderThan(seanceOlderThan)
+	if err != nil {
+		return err
+	}
+	result, err := claude.PruneSessions(cutoff, seanceRetentionDry)
+	if err != nil {
+		return err
+	}
+	return printRetentionResult(result, "Deleted", "delete")
+}
+
+// parseOlderThan parses --older-than into the cutoff time.
+func parseOlderThan(s string) (time.Time, error) {
+	cutoff, err := parseTimeBound(s, time.Now())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --older-than: %w", err)
+	}
+	if cutoff.IsZero() {
+		return time.Time{}, fmt.Errorf("--older-than is required")
+	}
+	return cutoff, nil
+}
+
+// printRetentionResult reports an archive or prune; done is the past
+// tense ("Archived") and verb the infinitive ("archive").
+func printRetentionResult(result *claude.RetentionResult, done, verb string) error {
+	if seanceRetentionJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	if len(result.Sessions) == 0 {
+		fmt.Printf("No sessions older than %s.\n", seanceOlderThan)
+		return nil
+	}
+
+	if result.DryRun {
+		fmt.Printf("Would %s %d session(s), %d transcript(s), %s:\n", verb, len(result.Sessions), result.Files, formatBytes(result.Bytes))
+	} else {
+		fmt.Printf("%s %s %d session(s), %d transcript(s), %s\n", style.SuccessPrefix, done, len(result.Sessions), result.Files, formatBytes(result.Bytes))
+	}
+	for _, s := range result.Sessions {
+		fmt.Printf("  %s  %s  %s\n", s.ShortID(), s.EndTime.Local().Format("2006-01-02"), style.Dim.Render(s.ProjectPath))
+	}
+	if result.Archive != "" {
+		fmt.Printf("  %s\n", style.Dim.Render("archive: "+result.Archive))
+	}
+	return nil
+}
+
+func runSeanceArchived(cmd *cobra.Command, args []string) error {
+	sessions, err := claude.ListArchivedSessions(seanceArchiveDir)
+	if err != nil {
+		return err
+	}
+	if seanceRetentionJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sessions)
+	}
+	if len(sessions) == 0 {
+		fmt.Println("No archived sessions.")
+		return nil
+	}
+	archive := ""
+	for _, s := range sessions {
+		if s.Archive != archive {
+			archive = s.Archive
+			fmt.Printf("%s\n", style.Bold.Render(filepath.Base(archive)))
+		}
+		fmt.Printf("  %s  %8s  %s\n", s.SessionID, formatBytes(s.Size), style.Dim.Render(path.Dir(s.Name)))
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Restore one with: gt seance unarchive <session-id>"))
+	return nil
+}
+
+func runSeanceUnarchive(cmd *cobra.Command, args []string) error {
+	s, err := claude.FindArchivedSession(seanceArchiveDir, args[0])
+	if err != nil {
+		return err
+	}
+	path, err := claude.RestoreArchivedSession(*s)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Restored %s from %s\n", style.SuccessPrefix, s.SessionID, filepath.Base(s.Archive))
+	fmt.Printf("  %s\n", style.Dim.Render(path))
+	return nil
+}
+
+// formatBytes renders a byte count as B, KB, MB, or GB.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
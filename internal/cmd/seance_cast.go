@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceCastOut         string
+	seanceCastWidth       int
+	seanceCastHeight      int
+	seanceCastIdleLimit   time.Duration
+	seanceCastLineDelay   time.Duration
+	seanceCastResultLines int
+)
+
+var seanceCastCmd = &cobra.Command{
+	Use:   "cast <session-id>",
+	Short: "Render a session as an asciinema recording",
+	Long: `Render a session as a terminal recording (asciicast v2) for playback in
+asciinema players, e.g. for demos or to show new team members how agents
+work.
+
+The recording replays the conversation: each prompt, the agent's replies,
+and every tool call with the first lines of its output. Prompts are
+markers, so players can jump between them. Pauses follow the session's
+own timing, with long gaps cut to --idle-limit.
+
+Play it with 'asciinema play session.cast', or embed it with
+asciinema-player on a web page.
+
+Examples:
+  gt seance cast abc123 --out session.cast
+  gt seance cast abc123 --out demo.cast --width 120 --result-lines 4
+  gt seance cast abc123 | asciinema play -`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSeanceCast,
+}
+
+func init() {
+	seanceCastCmd.Flags().StringVarP(&seanceCastOut, "out", "o", "", "Write the recording to this file instead of stdout")
+	seanceCastCmd.Flags().IntVar(&seanceCastWidth, "width", 100, "Terminal width in columns")
+	seanceCastCmd.Flags().IntVar(&seanceCastHeight, "height", 30, "Terminal height in rows")
+	seanceCastCmd.Flags().DurationVar(&seanceCastIdleLimit, "idle-limit", 2*time.Second, "Longest pause between turns")
+	seanceCastCmd.Flags().DurationVar(&seanceCastLineDelay, "line-delay", 40*time.Millisecond, "Pause between printed lines")
+	seanceCastCmd.Flags().IntVar(&seanceCastResultLines, "result-lines", 8, "Lines of tool output to show per call (-1 = all)")
+
+	seanceCmd.AddCommand(seanceCastCmd)
+}
+
+func runSeanceCast(cmd *cobra.Command, args []string) error {
+	out, err := claude.ExportCast(args[0], claude.CastOptions{
+		Width:       seanceCastWidth,
+		Height:      seanceCastHeight,
+		IdleLimit:   seanceCastIdleLimit,
+		LineDelay:   seanceCastLineDelay,
+		ResultLines: seanceCastResultLines,
+	})
+	if err != nil {
+		return err
+	}
+	if seanceCastOut == "" {
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+	if err := os.WriteFile(seanceCastOut, out, 0644); err != nil { //nolint:gosec // G306: recordings are meant to be shared
+		return fmt.Errorf("writing recording: %w", err)
+	}
+	fmt.Printf("%s Recorded %s to %s\n", style.SuccessPrefix, args[0], seanceCastOut)
+	fmt.Println(style.Dim.Render("Play it with: asciinema play " + seanceCastOut))
+	return nil
+}
@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/takeover"
+)
+
+// seanceRow is a row in the seance listing: a session, or the latest
+// session of a chain of resumed and handed-off sessions.
+type seanceRow struct {
+	sessionEvent
+
+	// Chain lists the session IDs in the chain, oldest first. Empty for
+	// sessions that stand alone.
+	Chain []string `json:"chain,omitempty"`
+
+	// Takeover describes human/agent takeovers in the row's sessions
+	// (see gt takeover), e.g. "human (overseer)".
+	Takeover string `json:"takeover,omitempty"`
+
+	// Warnings are the limits the row's latest session is at or near,
+	// which make it risky to resume (see claude.SessionInfo.Warnings).
+	Warnings []claude.SessionWarning `json:"warnings,omitempty"`
+
+	// State is the row's latest session's state (see claude.SessionState).
+	State claude.SessionState `json:"state,omitempty"`
+
+	// Outcome is how the row's latest session's work ended (see
+	// claude.SessionOutcome).
+	Outcome claude.SessionOutcome `json:"outcome,omitempty"`
+
+	// Span is how long the row's chain ran, from the start of its first
+	// transcript to the end of its latest; for a single session, its
+	// duration. Zero when the transcripts weren't found.
+	Span time.Duration `json:"span,omitempty"`
+
+	// GitBranch and GitCommit are the git branch the row's latest session
+	// ended on and the last commit it made (see claude.SessionInfo).
+	GitBranch string `json:"git_branch,omitempty"`
+	GitCommit string `json:"git_commit,omitempty"`
+
+	// Tags and Note label the row's latest session, from the town's
+	// session_tags rules and by hand (gt seance tag, gt seance note).
+	Tags []string `json:"tags,omitempty"`
+	Note string   `json:"note,omitempty"`
+}
+
+// chainBadge returns the "×N" badge for a collapsed chain, or "".
+func (r seanceRow) chainBadge() string {
+	if len(r.Chain) < 2 {
+		return ""
+	}
+	return fmt.Sprintf("×%d", len(r.Chain))
+}
+
+// seanceStateGlyphs mark sessions that died mid-task.
+var seanceStateGlyphs = map[claude.SessionState]string{
+	claude.StateCrashed:   "✗",
+	claude.StateAbandoned: "∅",
+}
+
+// badge returns the row's chain badge plus "⇄" for blended sessions, a
+// glyph for sessions that died mid-task, and the glyphs of its warnings.
+func (r seanceRow) badge() string {
+	badge := r.chainBadge()
+	if r.Takeover != "" {
+		badge = strings.TrimSpace(badge + " ⇄")
+	}
+	glyphs := seanceStateGlyphs[r.State] + claude.WarningGlyphs(r.Warnings)
+	if glyphs != "" {
+		badge = strings.TrimSpace(badge + " " + glyphs)
+	}
+	return badge
+}
+
+// labels returns the row's tags and note for the line under it in the
+// listing, e.g. "#good-handoff #review  resume from step 3", or "".
+func (r seanceRow) labels() string {
+	parts := make([]string, 0, len(r.Tags)+1)
+	for _, tag := range r.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	labels := strings.Join(parts, " ")
+	if r.Note != "" {
+		labels = strings.TrimSpace(labels + "  " + r.Note)
+	}
+	return labels
+}
+
+// markSessionInfo fills in State, Outcome, tags and note, and Warnings for rows
+// whose latest session is near a limit, from the parsed transcripts in
+// sessions.
+func markSessionInfo(rows []seanceRow, sessions []claude.SessionInfo, limits claude.Limits) {
+	byID := make(map[string]claude.SessionInfo, len(sessions))
+	for _, s := range sessions {
+		byID[s.ID] = s
+	}
+	for i := range rows {
+		s, ok := byID[getPayloadString(rows[i].Payload, "session_id")]
+		if !ok {
+			continue
+		}
+		rows[i].Warnings = s.Warnings(limits)
+		rows[i].State = s.State
+		rows[i].Outcome = s.Outcome
+		rows[i].GitBranch = s.GitBranch
+		rows[i].GitCommit = s.GitCommit
+		rows[i].Tags = s.Tags
+		rows[i].Note = s.Note
+
+		// The chain started with its oldest transcript we know of
+		start := s.StartTime
+		if !s.ChainStart.IsZero() {
+			start = s.ChainStart
+		}
+		for _, id := range rows[i].Chain {
+			if earlier, ok := byID[id]; ok && earlier.StartTime.Before(start) {
+				start = earlier.StartTime
+			}
+		}
+		if !start.IsZero() && !s.EndTime.IsZero() {
+			rows[i].Span = s.EndTime.Sub(start)
+		}
+	}
+}
+
+// filterRowsByBranch keeps the rows with a session in their chain that
+// had a branch matching pattern checked out (see claude.SessionInfo.OnBranch).
+func filterRowsByBranch(rows []seanceRow, sessions []claude.SessionInfo, pattern string) []seanceRow {
+	return filterRowChains(rows, sessions, func(s claude.SessionInfo) bool { return s.OnBranch(pattern) })
+}
+
+// filterRowsByTag keeps the rows with a session in their chain tagged
+// tag, by the town's session_tags rules or by hand.
+func filterRowsByTag(rows []seanceRow, sessions []claude.SessionInfo, tag string) []seanceRow {
+	return filterRowChains(rows, sessions, func(s claude.SessionInfo) bool { return slices.Contains(s.Tags, tag) })
+}
+
+// filterRowsByOutcome keeps the rows whose latest session's work ended
+// with outcome: earlier sessions in a chain were continued, so theirs
+// doesn't say how the work ended.
+func filterRowsByOutcome(rows []seanceRow, sessions []claude.SessionInfo, outcome claude.SessionOutcome) []seanceRow {
+	ended := make(map[string]bool)
+	for _, s := range sessions {
+		if s.Outcome == outcome {
+			ended[s.ID] = true
+		}
+	}
+	var kept []seanceRow
+	for _, r := range rows {
+		if ended[getPayloadString(r.Payload, "session_id")] {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// filterRowChains keeps the rows with a session in their chain for which
+// match returns true.
+func filterRowChains(rows []seanceRow, sessions []claude.SessionInfo, match func(claude.SessionInfo) bool) []seanceRow {
+	matched := make(map[string]bool)
+	for _, s := range sessions {
+		if match(s) {
+			matched[s.ID] = true
+			for _, id := range s.Chain {
+				matched[id] = true
+			}
+		}
+	}
+	var kept []seanceRow
+	for _, r := range rows {
+		if slices.ContainsFunc(chainIDs(r), func(id string) bool { return matched[id] }) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// mergeResumeChains folds rows for sessions that a transcript chain shows
+// were resumed (see claude.SessionFilter.CollapseResumes) into the row of
+// the chain's latest session, if it has one. Resumes made outside Gas
+// Town's hooks have no events linking them, so collapseSessionChains
+// can't see them.
+func mergeResumeChains(rows []seanceRow, sessions []claude.SessionInfo) []seanceRow {
+	rowOf := make(map[string]int, len(rows))
+	for i, r := range rows {
+		rowOf[getPayloadString(r.Payload, "session_id")] = i
+	}
+	merged := make(map[int]bool)
+	for _, s := range sessions {
+		latest, ok := rowOf[s.ID]
+		if len(s.Chain) < 2 || !ok {
+			continue
+		}
+		var earlier []string
+		for _, id := range s.Chain[:len(s.Chain)-1] {
+			if i, ok := rowOf[id]; ok && i != latest && !merged[i] {
+				merged[i] = true
+				earlier = append(earlier, chainIDs(rows[i])...)
+			}
+		}
+		rows[latest].Chain = mergeChainIDs(append(earlier, chainIDs(rows[latest])...), s.Chain)
+	}
+	if len(merged) == 0 {
+		return rows
+	}
+
+	out := rows[:0]
+	for i, r := range rows {
+		if !merged[i] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// chainIDs returns the session IDs of a row's chain, oldest first.
+func chainIDs(r seanceRow) []string {
+	if len(r.Chain) > 0 {
+		return r.Chain
+	}
+	return []string{getPayloadString(r.Payload, "session_id")}
+}
+
+// mergeChainIDs combines a row's chain with a transcript chain, both
+// oldest first: the IDs only the row knew of, then the transcript chain.
+func mergeChainIDs(row, transcript []string) []string {
+	inTranscript := make(map[string]bool, len(transcript))
+	for _, id := range transcript {
+		inTranscript[id] = true
+	}
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range row {
+		if !inTranscript[id] && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return append(ids, transcript...)
+}
+
+// sortSeanceRows orders rows as their latest sessions appear in
+// sessions, a listing sorted by claude.DiscoverSessions. Rows whose
+// session has no transcript keep their order, after the rest.
+func sortSeanceRows(rows []seanceRow, sessions []claude.SessionInfo) {
+	rank := make(map[string]int, len(sessions))
+	for i, s := range sessions {
+		rank[s.ID] = i
+	}
+	rankOf := func(r seanceRow) int {
+		if i, ok := rank[getPayloadString(r.Payload, "session_id")]; ok {
+			return i
+		}
+		return len(sessions)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rankOf(rows[i]) < rankOf(rows[j])
+	})
+}
+
+// markTakeovers fills in Takeover for rows whose session, or any session
+// in their chain, was taken over.
+func markTakeovers(rows []seanceRow, bySession map[string][]takeover.Record) {
+	for i := range rows {
+		ids := rows[i].Chain
+		if len(ids) == 0 {
+			ids = []string{getPayloadString(rows[i].Payload, "session_id")}
+		}
+		var records []takeover.Record
+		for _, id := range ids {
+			records = append(records, bySession[id]...)
+		}
+		rows[i].Takeover = takeover.Describe(records)
+	}
+}
+
+// continuesChain reports whether a session picks up where the same
+// agent's previous session left off: a resume or compaction of it, or a
+// successor started by gt handoff.
+func continuesChain(prev, next sessionEvent) bool {
+	if id := getPayloadString(next.Payload, "session_id"); id != "" && id == getPayloadString(prev.Payload, "session_id") {
+		return true
+	}
+	if handoff, ok := next.Payload["handoff"].(bool); ok && handoff {
+		return true
+	}
+	switch getPayloadString(next.Payload, "source") {
+	case "resume", "compact":
+		return true
+	}
+	return false
+}
+
+// collapseSessionChains folds each chain of an agent's consecutive linked
+// sessions into one row showing the latest session. sessions must be most
+// recent first; rows are returned in the same order.
+func collapseSessionChains(sessions []sessionEvent) []seanceRow {
+	// Walk each actor's sessions oldest first, extending the open chain
+	// while sessions continue it.
+	type chain struct {
+		latest sessionEvent
+		ids    []string
+	}
+	var chains []*chain
+	open := make(map[string]*chain)
+	for i := len(sessions) - 1; i >= 0; i-- {
+		s := sessions[i]
+		id := getPayloadString(s.Payload, "session_id")
+		c := open[s.Actor]
+		if c == nil || !continuesChain(c.latest, s) {
+			c = &chain{}
+			chains = append(chains, c)
+			open[s.Actor] = c
+		}
+		c.latest = s
+		if len(c.ids) == 0 || c.ids[len(c.ids)-1] != id {
+			c.ids = append(c.ids, id)
+		}
+	}
+
+	rows := make([]seanceRow, 0, len(chains))
+	for _, c := range chains {
+		row := seanceRow{sessionEvent: c.latest}
+		if len(c.ids) > 1 {
+			row.Chain = c.ids
+		}
+		rows = append(rows, row)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Timestamp > rows[j].Timestamp
+	})
+	return rows
+}
+
+// seanceWarningLabels name the warning glyphs in the listing legend.
+var seanceWarningLabels = map[string]string{
+	"✂": "transcript too long to parse",
+	"◕": "context nearly full",
+	"↻": "compacted often",
+	"‼": "many failed tool calls",
+}
+
+// seanceWarningLegend explains the state and warning glyphs shown in
+// rows, or returns "" when there are none.
+func seanceWarningLegend(rows []seanceRow) string {
+	seen := make(map[string]bool)
+	var parts []string
+	for _, r := range rows {
+		if glyph := seanceStateGlyphs[r.State]; glyph != "" && !seen[glyph] {
+			seen[glyph] = true
+			parts = append(parts, glyph+" "+string(r.State)+" mid-task")
+		}
+		for _, w := range r.Warnings {
+			if !seen[w.Glyph] {
+				seen[w.Glyph] = true
+				parts = append(parts, w.Glyph+" "+seanceWarningLabels[w.Glyph])
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	sort.Strings(parts)
+	return "Marks: " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceCompactionsFull  bool
+	seanceCompactionsJSON  bool
+	seanceCompactionsMin   int
+	seanceCompactionsSince string
+	seanceCompactionsRig   string
+)
+
+// seanceCompactionsSummaryLimit truncates summaries unless --full is given.
+const seanceCompactionsSummaryLimit = 800
+
+var seanceCompactionsCmd = &cobra.Command{
+	Use:   "compactions [<session-id>]",
+	Short: "Show each context compaction's summary and what it dropped",
+	Long: `Show the context compactions in a session.
+
+When a session's context fills up, Claude Code replaces the conversation
+with a summary. Anything the summary left out is gone for the agent. Use
+this to diagnose "the agent forgot X" complaints: check whether X made it
+into the summary that followed.
+
+For each compaction, shows the trigger (auto or manual), the context size
+before and after, how many turns were summarized, and the summary itself.
+
+Without a session ID, lists the Gas Town sessions that compacted at least
+--min times, most compactions first, to spot agents that keep blowing
+through their context. Those usually need smaller tasks or a handoff.
+
+Examples:
+  gt seance compactions abc123
+  gt seance compactions abc123 --full
+  gt seance compactions abc123 --json
+  gt seance compactions --min 3 --since 2d   # Who keeps running out of context?`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSeanceCompactions,
+}
+
+func init() {
+	seanceCompactionsCmd.Flags().BoolVar(&seanceCompactionsFull, "full", false, "Don't truncate summaries")
+	seanceCompactionsCmd.Flags().BoolVar(&seanceCompactionsJSON, "json", false, "Output as JSON")
+	seanceCompactionsCmd.Flags().IntVar(&seanceCompactionsMin, "min", 1, "When listing, only sessions with at least this many compactions")
+	seanceCompactionsCmd.Flags().StringVar(&seanceCompactionsSince, "since", "7d", "When listing, only sessions started at or after this time")
+	seanceCompactionsCmd.Flags().StringVar(&seanceCompactionsRig, "rig", "", "When listing, only sessions in this rig")
+
+	seanceCmd.AddCommand(seanceCompactionsCmd)
+}
+
+func runSeanceCompactions(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return runSeanceCompactionsList()
+	}
+	session, err := claude.FindSession(args[0])
+	if err != nil {
+		return err
+	}
+
+	compactions, err := claude.ReadCompactions(session.Path)
+	if err != nil {
+		return fmt.Errorf("reading transcript: %w", err)
+	}
+
+	if seanceCompactionsJSON {
+		type compactionJSON struct {
+			claude.Compaction
+			Dropped int64 `json:"dropped"`
+		}
+		out := make([]compactionJSON, 0, len(compactions))
+		for _, c := range compactions {
+			out = append(out, compactionJSON{Compaction: c, Dropped: c.Dropped()})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	fmt.Printf("%s Compactions in %s: %d\n", style.Bold.Render("🗜"), session.ShortID(), len(compactions))
+	if len(compactions) == 0 {
+		fmt.Printf("\n  %s\n", style.Dim.Render("(session was never compacted)"))
+		return nil
+	}
+
+	for i, c := range compactions {
+		ts := ""
+		if !c.Timestamp.IsZero() {
+			ts = c.Timestamp.Local().Format("2006-01-02 15:04")
+		}
+		trigger := c.Trigger
+		if trigger == "" {
+			trigger = "unknown"
+		}
+		fmt.Printf("\n%s  %s  %s  %s\n",
+			style.Bold.Render(fmt.Sprintf("#%d", i+1)), ts, trigger, formatCompactionSize(c))
+		fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("%d turn(s) summarized", c.Turns)))
+
+		summary := strings.TrimSpace(c.Summary)
+		if summary == "" {
+			fmt.Printf("    %s\n", style.Dim.Render("(no summary recorded)"))
+			continue
+		}
+		if !seanceCompactionsFull && len(summary) > seanceCompactionsSummaryLimit {
+			summary = summary[:seanceCompactionsSummaryLimit] + "… (--full for more)"
+		}
+		for _, line := range strings.Split(summary, "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	return nil
+}
+
+// formatCompactionSize describes the context before and after a compaction.
+func formatCompactionSize(c claude.Compaction) string {
+	if c.PreTokens == 0 {
+		return "context size unknown"
+	}
+	pre := formatTokenCount(float64(c.PreTokens))
+	if c.PostTokens == 0 {
+		return pre + " tokens → ?"
+	}
+	dropped := c.Dropped()
+	return fmt.Sprintf("%s → %s tokens (dropped %s, %.0f%%)",
+		pre, formatTokenCount(float64(c.PostTokens)),
+		formatTokenCount(float64(dropped)), 100*float64(dropped)/float64(c.PreTokens))
+}
+
+// runSeanceCompactionsList lists the sessions that compacted the most.
+func runSeanceCompactionsList() error {
+	since, _, err := parseTimeRange(seanceCompactionsSince, "", time.Now())
+	if err != nil {
+		return err
+	}
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{
+		GasTownOnly: true,
+		Rig:         seanceCompactionsRig,
+		Since:       since,
+	})
+	if err != nil {
+		return fmt.Errorf("discovering sessions: %w", err)
+	}
+
+	var list []claude.SessionInfo
+	for _, s := range sessions {
+		if s.CompactionCount >= max(seanceCompactionsMin, 1) {
+			list = append(list, s)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].CompactionCount > list[j].CompactionCount
+	})
+
+	if seanceCompactionsJSON {
+		if list == nil {
+			list = []claude.SessionInfo{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+	if len(list) == 0 {
+		fmt.Printf("No sessions with %d+ compactions since %s.\n", max(seanceCompactionsMin, 1), since.Local().Format("2006-01-02 15:04"))
+		return nil
+	}
+
+	fmt.Printf("%s Sessions by compactions\n\n", style.Bold.Render("🗜"))
+	for _, s := range list {
+		last := ""
+		if !s.LastCompaction.IsZero() {
+			last = "last " + formatAge(s.LastCompaction)
+		}
+		glyphs := claude.WarningGlyphs(s.Warnings(claude.Limits{}))
+		fmt.Printf("  %3d  %-12s  %-28s  %-3s %s\n", s.CompactionCount, s.ShortID(), compactAddress(s.Role), glyphs, style.Dim.Render(last))
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Details: gt seance compactions <session-id>"))
+	return nil
+}
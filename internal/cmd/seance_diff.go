@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	seanceDiffConversational bool
+	seanceDiffRefresh        bool
+	seanceDiffMaxChars       int
+	seanceDiffDryRun         bool
+	seanceDiffJSON           bool
+)
+
+var seanceDiffCmd = &cobra.Command{
+	Use:   "diff <session-a> <session-b>",
+	Short: "Compare two sessions side by side",
+	Long: `Compare two sessions, typically two attempts at the same task.
+
+By default the comparison is metadata only: duration, turns, tokens and
+estimated cost, tool calls, commands run, and which files each session
+wrote that the other didn't.
+
+With --conversational, both transcripts are condensed and handed to a
+headless Claude pass, which compares the approaches ("A wrote tests
+first, B skipped the migration"). The result is cached next to the
+session index until either transcript changes; --refresh regenerates it.
+
+Examples:
+  gt seance diff abc123 def456
+  gt seance diff abc123 def456 --conversational
+  gt seance diff abc123 def456 --conversational --dry-run   # print the prompt
+  gt seance diff abc123 def456 --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runSeanceDiff,
+}
+
+func init() {
+	seanceDiffCmd.Flags().BoolVar(&seanceDiffConversational, "conversational", false, "Also compare the approaches with a headless Claude pass")
+	seanceDiffCmd.Flags().BoolVar(&seanceDiffRefresh, "refresh", false, "Regenerate a cached conversational comparison")
+	seanceDiffCmd.Flags().IntVar(&seanceDiffMaxChars, "max-chars", 120_000, "Maximum transcript characters sent to Claude")
+	seanceDiffCmd.Flags().BoolVar(&seanceDiffDryRun, "dry-run", false, "Print the comparison prompt instead of running Claude")
+	seanceDiffCmd.Flags().BoolVar(&seanceDiffJSON, "json", false, "Output as JSON")
+
+	seanceCmd.AddCommand(seanceDiffCmd)
+}
+
+// seanceDiffReport is the output of gt seance diff.
+type seanceDiffReport struct {
+	*claude.SessionDiff
+	TokensA        int64                      `json:"tokens_a"`
+	TokensB        int64                      `json:"tokens_b"`
+	CostA          float64                    `json:"cost_usd_a"`
+	CostB          float64                    `json:"cost_usd_b"`
+	Conversational *claude.ConversationalDiff `json:"conversational,omitempty"`
+	Cached         bool                       `json:"cached,omitempty"`
+}
+
+func runSeanceDiff(cmd *cobra.Command, args []string) error {
+	var transcripts [2]*claude.Transcript
+	for i, ref := range args {
+		t, err := claude.LoadTranscript(ref)
+		if err != nil {
+			return fmt.Errorf("loading session %s: %w", ref, err)
+		}
+		transcripts[i] = t
+	}
+	a, b := transcripts[0].Session, transcripts[1].Session
+	if a.Path == b.Path {
+		return fmt.Errorf("both arguments name session %s", a.ShortID())
+	}
+
+	if seanceDiffConversational && seanceDiffDryRun {
+		fmt.Print(buildSeanceDiffPrompt(a, b, seanceDiffMaxChars))
+		return nil
+	}
+
+	report := seanceDiffReport{
+		SessionDiff: claude.DiffSessions(transcripts[0].Profile(), transcripts[1].Profile()),
+	}
+	townRoot, _ := workspace.FindFromCwd()
+	prices := loadTownSettingsQuiet(townRoot).PriceTable()
+	if usage, err := claude.ReadUsage(a.Path); err == nil {
+		report.TokensA, report.CostA = usage.TotalTokens(), claude.EstimateCost(*usage, prices)
+	}
+	if usage, err := claude.ReadUsage(b.Path); err == nil {
+		report.TokensB, report.CostB = usage.TotalTokens(), claude.EstimateCost(*usage, prices)
+	}
+
+	if seanceDiffConversational {
+		d, cached := claude.CachedConversationalDiff(a, b)
+		if !cached || seanceDiffRefresh {
+			var err error
+			if d, err = runConversationalDiff(a, b); err != nil {
+				return err
+			}
+			cached = false
+			if err := claude.StoreConversationalDiff(a, b, d); err != nil {
+				fmt.Fprintf(os.Stderr, "%s could not cache comparison: %v\n", style.WarningPrefix, err)
+			}
+		}
+		report.Conversational, report.Cached = d, cached
+	}
+
+	if seanceDiffJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printSeanceDiff(os.Stdout, report)
+	return nil
+}
+
+// runConversationalDiff asks a headless Claude pass to compare the two
+// sessions' approaches.
+func runConversationalDiff(a, b *claude.SessionInfo) (*claude.ConversationalDiff, error) {
+	fmt.Fprintf(os.Stderr, "%s Comparing %s and %s...\n", style.Bold.Render("⚖"), a.ShortID(), b.ShortID())
+
+	claudeCmd := exec.Command("claude", "--print")
+	claudeCmd.Stdin = strings.NewReader(buildSeanceDiffPrompt(a, b, seanceDiffMaxChars))
+	var out bytes.Buffer
+	claudeCmd.Stdout = &out
+	claudeCmd.Stderr = os.Stderr
+	if err := claudeCmd.Run(); err != nil {
+		return nil, fmt.Errorf("running claude: %w", err)
+	}
+
+	d, err := claude.ParseConversationalDiff(out.String())
+	if err != nil {
+		return nil, fmt.Errorf("reading claude's comparison: %w", err)
+	}
+	d.GeneratedAt = time.Now().UTC()
+	return d, nil
+}
+
+// buildSeanceDiffPrompt condenses both sessions into a comparison prompt,
+// giving each half of maxChars.
+func buildSeanceDiffPrompt(a, b *claude.SessionInfo, maxChars int) string {
+	var p strings.Builder
+	p.WriteString(`You are reviewing two AI coding agent sessions, A and B, that worked on the
+same task. Below are condensed transcripts of both. Compare their approaches:
+what each did first, what each tested or skipped, where they diverged, and
+which mistakes only one of them made.
+
+Output only a JSON object of this shape:
+{
+  "summary": "one or two sentences on how the approaches differed",
+  "differences": [
+    {"aspect": "short label, e.g. testing", "a": "what A did", "b": "what B did"}
+  ],
+  "verdict": "which approach held up better and why, or empty if unclear"
+}
+
+Each difference must be specific to these transcripts, not generic advice.
+
+`)
+	for _, side := range []struct {
+		label string
+		s     *claude.SessionInfo
+	}{{"A", a}, {"B", b}} {
+		fmt.Fprintf(&p, "### Session %s (s:%s, %s)\n", side.label, side.s.ShortID(), side.s.StartTime.Format("2006-01-02 15:04"))
+		if side.s.Summary != "" {
+			fmt.Fprintf(&p, "Summary: %s\n", side.s.Summary)
+		}
+		p.WriteString(condenseTurns(side.s.Path, maxChars/2))
+		p.WriteString("\n")
+	}
+	return p.String()
+}
+
+// condenseTurns renders a transcript's turns for a prompt, each capped at
+// distillTurnChars, stopping before maxChars. Later turns are dropped
+// first, since a task's approach is set early.
+func condenseTurns(path string, maxChars int) string {
+	turns, err := claude.ReadTurns(path)
+	if err != nil {
+		return fmt.Sprintf("[transcript unreadable: %v]\n", err)
+	}
+	var b strings.Builder
+	for i, t := range turns {
+		text := t.Text
+		if len(text) > distillTurnChars {
+			text = text[:distillTurnChars] + "…"
+		}
+		if text == "" {
+			text = "[tools: " + strings.Join(t.Tools, ", ") + "]"
+		}
+		line := fmt.Sprintf("%s: %s\n", t.Role, text)
+		if b.Len()+len(line) > maxChars {
+			fmt.Fprintf(&b, "[%d later turns omitted]\n", len(turns)-i)
+			break
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// printSeanceDiff prints a side-by-side comparison.
+func printSeanceDiff(w io.Writer, r seanceDiffReport) {
+	a, b := r.A, r.B
+	fmt.Fprintf(w, "%s %s vs %s\n\n", style.Bold.Render("⚖ Session diff"), a.Session.ShortID(), b.Session.ShortID())
+
+	row := func(label, va, vb string) {
+		fmt.Fprintf(w, "  %-10s %-28s %s\n", label, va, vb)
+	}
+	row("", "A "+a.Session.ShortID(), "B "+b.Session.ShortID())
+	row("Started", a.Start.Local().Format("2006-01-02 15:04"), b.Start.Local().Format("2006-01-02 15:04"))
+	row("Duration", formatDuration(a.Duration()), formatDuration(b.Duration()))
+	row("Turns", fmt.Sprint(a.Turns), fmt.Sprint(b.Turns))
+	row("Tokens", fmt.Sprint(r.TokensA), fmt.Sprint(r.TokensB))
+	row("Cost", fmt.Sprintf("$%.2f", r.CostA), fmt.Sprintf("$%.2f", r.CostB))
+	row("Commands", formatCommandCount(a), formatCommandCount(b))
+	row("Tools", formatToolCounts(a.ToolCalls, 4), formatToolCounts(b.ToolCalls, 4))
+
+	files := func(label string, paths []string) {
+		if len(paths) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n%s\n", style.Bold.Render(label))
+		for _, p := range paths {
+			fmt.Fprintf(w, "  %s\n", p)
+		}
+	}
+	files("Written only by A", r.FilesOnlyA)
+	files("Written only by B", r.FilesOnlyB)
+	files("Written by both", r.FilesBoth)
+
+	d := r.Conversational
+	if d == nil {
+		return
+	}
+	header := "Approaches"
+	if r.Cached {
+		header += " " + style.Dim.Render("(cached "+d.GeneratedAt.Local().Format("2006-01-02 15:04")+")")
+	}
+	fmt.Fprintf(w, "\n%s\n", style.Bold.Render(header))
+	if d.Summary != "" {
+		fmt.Fprintf(w, "  %s\n", d.Summary)
+	}
+	for _, p := range d.Differences {
+		fmt.Fprintf(w, "\n  %s\n", style.Bold.Render(p.Aspect))
+		fmt.Fprintf(w, "    A: %s\n", p.A)
+		fmt.Fprintf(w, "    B: %s\n", p.B)
+	}
+	if d.Verdict != "" {
+		fmt.Fprintf(w, "\n  %s %s\n", style.Bold.Render("Verdict:"), d.Verdict)
+	}
+}
+
+// formatCommandCount formats a profile's commands and failures.
+func formatCommandCount(p *claude.SessionProfile) string {
+	if p.FailedCommands == 0 {
+		return fmt.Sprint(p.Commands)
+	}
+	return fmt.Sprintf("%d (%d failed)", p.Commands, p.FailedCommands)
+}
+
+// formatToolCounts formats the n most-used tools, most-used first, ties
+// by name.
+func formatToolCounts(counts map[string]int, n int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", name, counts[name])
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+func TestFormatToolCounts(t *testing.T) {
+	counts := map[string]int{"Read": 5, "Edit": 2, "Bash": 5, "Grep": 1}
+	if got := formatToolCounts(counts, 3); got != "Bash 5, Read 5, Edit 2" {
+		t.Errorf("formatToolCounts = %q", got)
+	}
+	if got := formatToolCounts(nil, 3); got != "-" {
+		t.Errorf("formatToolCounts(nil) = %q", got)
+	}
+}
+
+func TestBuildSeanceDiffPrompt(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, turns int) *claude.SessionInfo {
+		var lines []string
+		for i := 0; i < turns; i++ {
+			lines = append(lines, `{"type":"user","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"`+strings.Repeat("x", 100)+`"}}`)
+		}
+		path := filepath.Join(dir, name+".jsonl")
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return &claude.SessionInfo{ID: name, Path: path}
+	}
+	a, b := write("aaaa0001", 2), write("bbbb0002", 20)
+
+	prompt := buildSeanceDiffPrompt(a, b, 1000)
+	if !strings.Contains(prompt, "### Session A (s:aaaa0001") || !strings.Contains(prompt, "### Session B (s:bbbb0002") {
+		t.Errorf("prompt missing session headers:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, `"differences"`) {
+		t.Error("prompt does not ask for the JSON shape")
+	}
+	if !strings.Contains(prompt, "later turns omitted") {
+		t.Errorf("long session not truncated:\n%s", prompt)
+	}
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceExportFormat  string
+	seanceExportOutput  string
+	seanceExportFilters []string
+)
+
+var seanceExportCmd = &cobra.Command{
+	Use:   "export [<session-id>]",
+	Short: "Export a session as Markdown or HTML",
+	Long: `Render a session transcript as a readable document for sharing or
+review: Markdown, or a standalone HTML page that opens in any browser
+without network access.
+
+User and assistant turns appear in order with their timestamps. Each tool
+call is collapsed into a <details> block showing its input and output, so
+the conversation reads top to bottom and the detail is a click away.
+
+The format defaults to Markdown, or to HTML when --output ends in .html.
+
+BATCH EXPORT:
+  With --filter instead of a session ID, every matching Gas Town session
+  is exported into the --output directory, one file per session, with an
+  index.md manifest listing each session's role, topic, start time,
+  duration, and summary, linked to its file. Use it to hand over the
+  complete agent work record of a project, e.g. for an audit.
+
+  Progress is checkpointed in the output directory; if the export is
+  interrupted, running it again skips the sessions already exported.
+
+  Filters are key=value and may be repeated; all must match:
+    rig=<name>      role=<text>     path=<text>     tag=<tag>
+    model=<text>    branch=<name>   since=<time>    until=<time>
+    outcome=<outcome>  mcp=<server> gastown=false
+  Times take the same forms as gt seance --since (2d, today, 2025-01-02).
+  gastown=false also includes sessions without a Gas Town beacon.
+  branch= matches sessions that had that git branch checked out.
+  outcome= is completed, blocked, handoff, or abandoned (see gt seance).
+  mcp= matches sessions that called a tool of that MCP server.
+
+Examples:
+  gt seance export abc123 > abc123.md
+  gt seance export abc123 -o abc123.html
+  gt seance export auth-refactor-v2 --format html -o review.html
+  gt seance export --filter rig=gastown --filter since=2025-07-01 \
+      --filter until=2025-10-01 --output audit-q3/ --format md`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSeanceExport,
+}
+
+func init() {
+	seanceExportCmd.Flags().StringVarP(&seanceExportFormat, "format", "f", "", "Output format: "+strings.Join(claude.ExportFormats(), " or "))
+	seanceExportCmd.Flags().StringVarP(&seanceExportOutput, "output", "o", "", "Write to this file instead of stdout (with --filter, this directory)")
+	seanceExportCmd.Flags().StringVar(&seanceExportOutput, "out", "", "Same as --output")
+	seanceExportCmd.Flags().StringArrayVar(&seanceExportFilters, "filter", nil, "Export every session matching key=value (repeatable; see above)")
+	_ = seanceExportCmd.Flags().MarkHidden("out")
+
+	seanceCmd.AddCommand(seanceExportCmd)
+}
+
+func runSeanceExport(cmd *cobra.Command, args []string) error {
+	if len(seanceExportFilters) > 0 {
+		if len(args) > 0 {
+			return fmt.Errorf("give a session ID or --filter, not both")
+		}
+		return runSeanceExportBatch()
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("requires a session ID, or --filter to export many")
+	}
+
+	format := seanceExportFormat
+	if format == "" {
+		format = claude.ExportMarkdown
+		switch strings.ToLower(filepath.Ext(seanceExportOutput)) {
+		case ".html", ".htm":
+			format = claude.ExportHTML
+		}
+	}
+
+	out, err := claude.ExportTranscript(args[0], format)
+	if err != nil {
+		return err
+	}
+	if seanceExportOutput == "" {
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+	if err := os.WriteFile(seanceExportOutput, out, 0644); err != nil { //nolint:gosec // G306: exports are meant to be shared
+		return fmt.Errorf("writing export: %w", err)
+	}
+	fmt.Printf("%s Exported %s to %s\n", style.SuccessPrefix, args[0], seanceExportOutput)
+	return nil
+}
+
+// runSeanceExportBatch exports every session matching --filter into the
+// --output directory, with a manifest.
+func runSeanceExportBatch() error {
+	if seanceExportOutput == "" {
+		return fmt.Errorf("--filter needs --output <dir>")
+	}
+	format := seanceExportFormat
+	if format == "" {
+		format = claude.ExportMarkdown
+	}
+	filter, err := parseExportFilters(seanceExportFilters, time.Now())
+	if err != nil {
+		return err
+	}
+	sessions, err := discoverClaudeSessions(filter)
+	if err != nil {
+		return fmt.Errorf("discovering sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		return fmt.Errorf("no sessions match %s", strings.Join(seanceExportFilters, " "))
+	}
+
+	// Oldest first, so the manifest reads as a history
+	for i, j := 0, len(sessions)-1; i < j; i, j = i+1, j-1 {
+		sessions[i], sessions[j] = sessions[j], sessions[i]
+	}
+	exported, err := claude.ExportSessions(sessions, seanceExportOutput, format, terminalProgress())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Exported %d session(s) to %s\n", style.SuccessPrefix, len(exported),
+		filepath.Join(seanceExportOutput, claude.ExportManifestName))
+	return nil
+}
+
+// parseExportFilters builds a session filter from key=value expressions.
+// Only Gas Town sessions match unless gastown=false is given.
+func parseExportFilters(exprs []string, now time.Time) (claude.SessionFilter, error) {
+	filter := claude.SessionFilter{GasTownOnly: true}
+	var since, until string
+	for _, expr := range exprs {
+		key, value, ok := strings.Cut(expr, "=")
+		if !ok || value == "" {
+			return filter, fmt.Errorf("invalid filter %q (want key=value)", expr)
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "rig":
+			filter.Rig = value
+		case "role":
+			filter.Role = value
+		case "path", "project":
+			filter.Path = value
+		case "tag":
+			filter.Tag = value
+		case "model":
+			filter.Model = value
+		case "branch":
+			filter.Branch = value
+		case "mcp":
+			filter.MCPServer = value
+		case "outcome":
+			o, err := claude.ParseSessionOutcome(value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid filter %q: %w", expr, err)
+			}
+			filter.Outcome = o
+		case "since":
+			since = value
+		case "until":
+			until = value
+		case "gastown":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid filter %q: %w", expr, err)
+			}
+			filter.GasTownOnly = b
+		default:
+			return filter, fmt.Errorf("unknown filter key %q (want rig, role, path, tag, model, branch, outcome, mcp, since, until, or gastown)", key)
+		}
+	}
+	var err error
+	filter.Since, filter.Until, err = parseTimeRange(since, until, now)
+	return filter, err
+}
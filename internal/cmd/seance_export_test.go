@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+func TestParseExportFilters(t *testing.T) {
+	now := time.Date(2025, 10, 15, 12, 0, 0, 0, time.UTC)
+	filter, err := parseExportFilters([]string{"rig=gastown", "role=crew", "model=opus", "branch=feat/login", "since=2025-07-01", "until=2025-10-01"}, now)
+	if err != nil {
+		t.Fatalf("parseExportFilters: %v", err)
+	}
+	if !filter.GasTownOnly || filter.Rig != "gastown" || filter.Role != "crew" || filter.Model != "opus" || filter.Branch != "feat/login" {
+		t.Errorf("filter = %+v", filter)
+	}
+	if filter.Since.Format("2006-01-02") != "2025-07-01" || filter.Until.Format("2006-01-02") != "2025-10-01" {
+		t.Errorf("since %v, until %v", filter.Since, filter.Until)
+	}
+
+	if filter, err := parseExportFilters([]string{"gastown=false"}, now); err != nil || filter.GasTownOnly {
+		t.Errorf("gastown=false: %+v, %v", filter, err)
+	}
+	if filter, err := parseExportFilters([]string{"outcome=Blocked"}, now); err != nil || filter.Outcome != claude.OutcomeBlocked {
+		t.Errorf("outcome=Blocked: %+v, %v", filter, err)
+	}
+	if filter, err := parseExportFilters([]string{"mcp=github"}, now); err != nil || filter.MCPServer != "github" {
+		t.Errorf("mcp=github: %+v, %v", filter, err)
+	}
+	for _, bad := range []string{"rig", "rig=", "color=red", "gastown=maybe", "since=whenever", "outcome=done"} {
+		if _, err := parseExportFilters([]string{bad}, now); err == nil {
+			t.Errorf("%q: want error", bad)
+		}
+	}
+}
+
+// TestSeanceExportOutFlag runs the deprecated --out spelling through
+// rootCmd, as callers that skip Execute's argument rewriting do.
+func TestSeanceExportOutFlag(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake bd is a shell script")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, ".state"))
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "bd"), []byte("#!/bin/sh\necho 'bd version 0.44.0'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Chdir(home)
+
+	project := filepath.Join(home, ".claude", "projects", "-tmp-work")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	transcript := `{"type":"user","timestamp":"2025-10-01T10:00:00Z","message":{"role":"user","content":"export me"}}` + "\n"
+	if err := os.WriteFile(filepath.Join(project, "e0e0e0e0-1111-2222-3333-444444444444.jsonl"), []byte(transcript), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(home, "export.md")
+	t.Cleanup(func() {
+		seanceExportOutput = ""
+		seanceExportCmd.Flags().Lookup("out").Changed = false
+		rootCmd.SetArgs(nil)
+	})
+	rootCmd.SetArgs([]string{"seance", "export", "e0e0e0e0", "--out", out})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("gt seance export --out: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("export not written to --out: %v", err)
+	}
+	if !strings.Contains(string(data), "export me") {
+		t.Errorf("export missing the transcript:\n%s", data)
+	}
+}
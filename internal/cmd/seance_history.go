@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceHistoryJSON  bool
+	seanceHistorySince string
+)
+
+// seanceHistoryValueLimit truncates notes and summaries in text output.
+const seanceHistoryValueLimit = 60
+
+var seanceIndexHistoryCmd = &cobra.Command{
+	Use:   "history [<session-id>]",
+	Short: "Show who changed sessions' aliases, tags, notes, summaries, and outcomes",
+	Long: `Show the audit trail of session metadata.
+
+Transcripts are never modified, but metadata is layered on top of them:
+aliases (gt seance alias), hand-added tags and notes (gt seance tag,
+gt seance note), generated summaries, outcomes (gt outcome set, gt check
+run), and takeovers (gt takeover). Every change to it is appended to a
+log in the state directory with the time, the actor (BD_ACTOR, or the OS
+user), and the gt command that made it. The log is kept when the index
+is cleared or rebuilt.
+
+With a session ID, shows that session's changes, including for sessions
+whose transcript has since been deleted. Without, shows every session's.
+
+Also available as gt index history.
+
+Examples:
+  gt seance index history abc123
+  gt seance index history --since 7d
+  gt seance index history abc123 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSeanceIndexHistory,
+}
+
+// indexCmd is a top-level shorthand for gt seance index's metadata
+// commands, so gt index history works as well.
+var indexCmd = &cobra.Command{
+	Use:     "index",
+	GroupID: GroupDiag,
+	Short:   "Session index shorthand (see gt seance index)",
+	RunE:    requireSubcommand,
+}
+
+var indexHistoryCmd = &cobra.Command{
+	Use:   seanceIndexHistoryCmd.Use,
+	Short: seanceIndexHistoryCmd.Short,
+	Long:  seanceIndexHistoryCmd.Long,
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runSeanceIndexHistory,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{seanceIndexHistoryCmd, indexHistoryCmd} {
+		c.Flags().BoolVar(&seanceHistoryJSON, "json", false, "Output as JSON")
+		c.Flags().StringVar(&seanceHistorySince, "since", "", "Only changes at or after this time")
+	}
+
+	seanceIndexCmd.AddCommand(seanceIndexHistoryCmd)
+	indexCmd.AddCommand(indexHistoryCmd)
+	rootCmd.AddCommand(indexCmd)
+}
+
+func runSeanceIndexHistory(cmd *cobra.Command, args []string) error {
+	since, _, err := parseTimeRange(seanceHistorySince, "", time.Now())
+	if err != nil {
+		return err
+	}
+	id := ""
+	if len(args) == 1 {
+		if id, err = resolveHistorySession(args[0]); err != nil {
+			return err
+		}
+	}
+	changes, err := claude.OverlayHistory(id, since)
+	if err != nil {
+		return err
+	}
+
+	if seanceHistoryJSON {
+		if changes == nil {
+			changes = []claude.OverlayChange{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(changes)
+	}
+	if len(changes) == 0 {
+		if id != "" {
+			fmt.Printf("No recorded changes to session %s.\n", shortSessionID(id))
+		} else {
+			fmt.Println("No recorded session metadata changes.")
+		}
+		return nil
+	}
+
+	if id != "" {
+		fmt.Printf("%s Metadata history of %s (%d change(s))\n\n", style.Bold.Render("📜"), shortSessionID(id), len(changes))
+	} else {
+		fmt.Printf("%s Session metadata history (%d change(s))\n\n", style.Bold.Render("📜"), len(changes))
+	}
+	for _, c := range changes {
+		session := ""
+		if id == "" {
+			session = fmt.Sprintf("%-10s ", shortSessionID(c.Session))
+		}
+		fmt.Printf("  %s  %s%-8s %-40s %s\n",
+			c.Time.Local().Format("2006-01-02 15:04"), session, c.Kind,
+			describeOverlayChange(c), style.Dim.Render(c.Actor))
+	}
+	return nil
+}
+
+// resolveHistorySession turns a session reference into a full ID. A
+// session that can't be found may still have history, so a unique ID
+// prefix in the log is accepted too.
+func resolveHistorySession(ref string) (string, error) {
+	if session, err := claude.FindSession(ref); err == nil {
+		return session.ID, nil
+	}
+	ids, err := claude.OverlayHistorySessions(ref)
+	if err != nil {
+		return "", err
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("no session or recorded history matches %q", ref)
+	case 1:
+		return ids[0], nil
+	}
+	return "", fmt.Errorf("%q matches %d sessions in the history; use a longer prefix", ref, len(ids))
+}
+
+// describeOverlayChange renders what a change did, e.g. `+ experiment`
+// for an added tag or `"old" → "new"` for a replaced note.
+func describeOverlayChange(c claude.OverlayChange) string {
+	switch c.Op {
+	case claude.OverlayAdd:
+		return "+ " + c.Value
+	case claude.OverlayRemove:
+		return "- " + c.Old
+	case claude.OverlayClear:
+		return "cleared " + quoteHistoryValue(c.Old)
+	}
+	if c.Old == "" {
+		return quoteHistoryValue(c.Value)
+	}
+	return quoteHistoryValue(c.Old) + " → " + quoteHistoryValue(c.Value)
+}
+
+// quoteHistoryValue quotes a note or summary, shortened to one line.
+func quoteHistoryValue(s string) string {
+	s, _, cut := strings.Cut(s, "\n")
+	if r := []rune(s); len(r) > seanceHistoryValueLimit {
+		s, cut = string(r[:seanceHistoryValueLimit]), true
+	}
+	if cut {
+		s += "…"
+	}
+	return fmt.Sprintf("%q", s)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/takeover"
+)
+
+func TestQuoteHistoryValue(t *testing.T) {
+	if got := quoteHistoryValue("short note"); got != `"short note"` {
+		t.Errorf("short = %s", got)
+	}
+	if got := quoteHistoryValue("first line\nsecond"); got != `"first line…"` {
+		t.Errorf("multi-line = %s", got)
+	}
+
+	long := strings.Repeat("é", seanceHistoryValueLimit+5)
+	got := quoteHistoryValue(long)
+	if strings.Contains(got, `\x`) || !utf8.ValidString(got) {
+		t.Errorf("multi-byte value split mid-rune: %s", got)
+	}
+	if want := `"` + strings.Repeat("é", seanceHistoryValueLimit) + `…"`; got != want {
+		t.Errorf("long = %s, want %s", got, want)
+	}
+}
+
+func TestHistoryLogsOutcomesAndTakeovers(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("BD_ACTOR", "mayor")
+	const id = "aaaa1111-0000"
+
+	first := outcome.Record{SessionID: id, Status: outcome.Failure, Reason: "tests red"}
+	if err := logOutcomeChange(nil, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := logOutcomeChange(&first, outcome.Record{SessionID: id, Status: outcome.Success}); err != nil {
+		t.Fatal(err)
+	}
+	took := takeover.Record{SessionID: id, Direction: takeover.ToHuman, By: "alice", Note: "fixed by hand"}
+	if err := logTakeoverChange(took, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := logTakeoverChange(takeover.Record{SessionID: id, Cleared: true}, []takeover.Record{took}); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := claude.OverlayHistory(id, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`"failure: tests red"`,
+		`"failure: tests red" → "success"`,
+		"+ human (alice): fixed by hand",
+		`cleared "human (alice)"`,
+	}
+	if len(history) != len(want) {
+		t.Fatalf("history = %+v", history)
+	}
+	for i, c := range history {
+		if got := describeOverlayChange(c); got != want[i] || c.Actor != "mayor" {
+			t.Errorf("change %d = %q by %q, want %q by mayor", i, got, c.Actor, want[i])
+		}
+	}
+	if history[0].Kind != claude.OverlayOutcome || history[2].Kind != claude.OverlayTakeover {
+		t.Errorf("kinds = %s, %s", history[0].Kind, history[2].Kind)
+	}
+}
+
+func TestIndexHistoryCommandPaths(t *testing.T) {
+	for _, path := range []string{"index history", "seance index history"} {
+		c, _, err := rootCmd.Find(strings.Fields(path))
+		if err != nil || c.RunE == nil || c.Flags().Lookup("since") == nil {
+			t.Errorf("gt %s: %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+	"golang.org/x/term"
+)
+
+var (
+	seanceIndexRebuild bool
+	seanceIndexClear   bool
+	seanceIndexJSON    bool
+)
+
+var seanceIndexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Show, rebuild, or clear the session index",
+	Long: `Manage the on-disk session index.
+
+Session discovery caches each transcript's parsed header (start time,
+summary, beacon) keyed by file path, size, and modification time, so
+repeated seance calls only re-parse transcripts that changed. The index
+lives in the per-machine cache directory, split into one shard per rig:
+commands scoped with --rig read only the shards that rig's sessions are
+in. --rebuild and --clear keep the aliases set with gt seance alias.
+
+--rebuild saves its progress as it goes: if it is interrupted (Ctrl-C, a
+reboot), running it again picks up where it left off.
+
+Changes to the metadata layered on sessions (aliases, tags, notes,
+summaries) are logged; gt seance index history shows who made them.
+
+Set GT_SESSION_INDEX=off to bypass the index entirely.
+
+Examples:
+  gt seance index             # Show index location and size
+  gt seance index --rebuild   # Re-parse every transcript
+  gt seance index --clear     # Delete the index
+  gt seance index history abc123  # Who changed a session's metadata`,
+	Args: cobra.NoArgs,
+	RunE: runSeanceIndex,
+}
+
+func init() {
+	seanceIndexCmd.Flags().BoolVar(&seanceIndexRebuild, "rebuild", false, "Discard the index and re-parse every transcript")
+	seanceIndexCmd.Flags().BoolVar(&seanceIndexClear, "clear", false, "Delete the index")
+	seanceIndexCmd.Flags().BoolVar(&seanceIndexJSON, "json", false, "Output as JSON")
+
+	seanceCmd.AddCommand(seanceIndexCmd)
+}
+
+func runSeanceIndex(cmd *cobra.Command, args []string) error {
+	if seanceIndexRebuild && seanceIndexClear {
+		return fmt.Errorf("--rebuild and --clear are mutually exclusive")
+	}
+
+	switch {
+	case seanceIndexClear:
+		if err := claude.InvalidateSessionIndex(); err != nil {
+			return fmt.Errorf("clearing session index: %w", err)
+		}
+		if !seanceIndexJSON {
+			fmt.Printf("%s Session index cleared\n", style.SuccessPrefix)
+		}
+	case seanceIndexRebuild:
+		start := time.Now()
+		n, err := claude.RebuildSessionIndex(terminalProgress())
+		if err != nil {
+			return fmt.Errorf("rebuilding session index: %w", err)
+		}
+		if !seanceIndexJSON {
+			fmt.Printf("%s Indexed %d session(s) in %s\n", style.SuccessPrefix, n, formatLatency(time.Since(start)))
+		}
+	}
+
+	stats := claude.SessionIndexInfo()
+	if seanceIndexJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	if seanceIndexClear {
+		return nil
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("Session index:"), stats.Path)
+	if stats.UpdatedAt.IsZero() {
+		fmt.Printf("  %s\n", style.Dim.Render("(not built yet; the next session discovery builds it)"))
+		return nil
+	}
+	fmt.Printf("  Entries: %d in %d shard(s)\n", stats.Entries, stats.Shards)
+	fmt.Printf("  Size:    %.1f KB\n", float64(stats.SizeBytes)/1024)
+	fmt.Printf("  Updated: %s\n", stats.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+// terminalProgress returns a claude.Progress that redraws a progress line
+// on stderr, or nil when stderr isn't a terminal.
+func terminalProgress() claude.Progress {
+	if !term.IsTerminal(int(os.Stderr.Fd())) {
+		return nil
+	}
+	return func(stage string, done, total int) {
+		fmt.Fprintf(os.Stderr, "\r  %s %d/%d", stage, done, total)
+		if done == total {
+			fmt.Fprintln(os.Stderr)
+		}
+	}
+}
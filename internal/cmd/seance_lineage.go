@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var seanceLineageJSON bool
+
+var seanceLineageCmd = &cobra.Command{
+	Use:   "lineage <session-id>",
+	Short: "Show the tree of sessions resumed or forked from one another",
+	Long: `Show a session's lineage: the session it was resumed or forked from,
+that session's predecessor, and every session that branched off along
+the way, as a tree rooted at the original session.
+
+A resumed session picks up from its parent's last message; a fork
+branches from an earlier one (gt seance --talk forks, for example).
+The requested session is marked with ▶.
+
+Examples:
+  gt seance lineage abc123
+  gt seance lineage abc123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSeanceLineage,
+}
+
+func init() {
+	seanceLineageCmd.Flags().BoolVar(&seanceLineageJSON, "json", false, "Output as JSON")
+
+	seanceCmd.AddCommand(seanceLineageCmd)
+}
+
+func runSeanceLineage(cmd *cobra.Command, args []string) error {
+	session, err := claude.FindSession(args[0])
+	if err != nil {
+		return err
+	}
+	root, err := claude.Lineage(session.ID)
+	if err != nil {
+		return fmt.Errorf("reading lineage: %w", err)
+	}
+
+	if seanceLineageJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(root)
+	}
+
+	fmt.Printf("%s Lineage of %s: %d session(s)\n\n", style.Bold.Render("🌳"), session.ShortID(), root.Size())
+	printLineage(root, session.ID, "", "")
+	return nil
+}
+
+// printLineage prints a lineage node and its children as a tree.
+func printLineage(n *claude.LineageNode, focus, prefix, branch string) {
+	marker := "  "
+	if n.Session.ID == focus {
+		marker = "▶ "
+	}
+	line := fmt.Sprintf("%s%s%s", prefix+branch, marker, n.Session.ShortID())
+	if !n.Session.StartTime.IsZero() {
+		line += "  " + n.Session.StartTime.Local().Format("2006-01-02 15:04")
+	}
+	if n.Kind != "" {
+		line += "  " + style.Dim.Render(n.Kind)
+	}
+	if title := lineageTitle(n.Session); title != "" {
+		line += "  " + title
+	}
+	fmt.Println(line)
+
+	switch branch {
+	case "├─ ":
+		prefix += "│  "
+	case "└─ ":
+		prefix += "   "
+	}
+	for i, c := range n.Children {
+		next := "├─ "
+		if i == len(n.Children)-1 {
+			next = "└─ "
+		}
+		printLineage(c, focus, prefix, next)
+	}
+}
+
+// lineageTitle describes a session in one short line.
+func lineageTitle(s claude.SessionInfo) string {
+	title := s.Summary
+	if title == "" {
+		title = s.Topic
+	}
+	if s.Role != "" {
+		title = strings.TrimSpace(s.Role + " " + title)
+	}
+	return truncateStr(title, 60)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/issuelink"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	seanceLinksScan  bool
+	seanceLinksSince string
+	seanceLinksJSON  bool
+)
+
+var seanceLinksCmd = &cobra.Command{
+	Use:   "links [session-id|issue]",
+	Short: "Show which sessions worked on which issues",
+	Long: `Show the links between sessions and the issues they referenced.
+
+When a session's beacon or opening prompts mention a bead (gt-123) or a
+GitHub issue (#456), the daemon records the link. With issue_links.comment
+enabled in settings/config.json, it also comments on the bead with the
+session's summary once the session ends (GitHub issues too with
+issue_links.github_comments, using the gh CLI).
+
+Pass a session ID (or prefix) to list its issues, or an issue to list
+the sessions that worked on it. Use --scan to run a linking pass now
+instead of waiting for the daemon.
+
+Examples:
+  gt seance links
+  gt seance links gt-123
+  gt seance links abc123
+  gt seance links --scan --since 24h`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSeanceLinks,
+}
+
+func init() {
+	seanceLinksCmd.Flags().BoolVar(&seanceLinksScan, "scan", false, "Scan recent sessions for issue references first")
+	seanceLinksCmd.Flags().StringVar(&seanceLinksSince, "since", "7d", "How far back --scan looks")
+	seanceLinksCmd.Flags().BoolVar(&seanceLinksJSON, "json", false, "Output as JSON")
+
+	seanceCmd.AddCommand(seanceLinksCmd)
+}
+
+func runSeanceLinks(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if seanceLinksScan {
+		since, err := parseDuration(seanceLinksSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		result, err := scanIssueLinks(townRoot, time.Now().Add(-since))
+		if err != nil {
+			return err
+		}
+		if !seanceLinksJSON {
+			fmt.Printf("%s Scanned: %d new link(s), %d comment(s) posted\n",
+				style.SuccessPrefix, len(result.Linked), len(result.Commented))
+			for _, e := range result.Errors {
+				fmt.Printf("%s comment failed: %s\n", style.WarningPrefix, e)
+			}
+		}
+	}
+
+	records, err := issuelink.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		records = issuelink.Filter(records, args[0])
+	}
+
+	if seanceLinksJSON {
+		if records == nil {
+			records = []issuelink.Record{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	if len(records) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No session-issue links recorded"))
+		return nil
+	}
+	for _, rec := range records {
+		id := rec.SessionID
+		if len(id) > 8 {
+			id = id[:8]
+		}
+		status := ""
+		if rec.Commented {
+			status = style.Dim.Render(" (commented)")
+		}
+		fmt.Printf("  %-10s %s  %s  %s%s\n", style.Bold.Render(id), rec.Issue,
+			rec.Role, style.Dim.Render(rec.Timestamp.Local().Format("2006-01-02 15:04")), status)
+	}
+	return nil
+}
+
+// scanIssueLinks runs an issue linking pass over Gas Town sessions
+// written since the given time, using the town's issue_links settings.
+func scanIssueLinks(townRoot string, since time.Time) (*issuelink.ScanResult, error) {
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{GasTownOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("discovering sessions: %w", err)
+	}
+	settings := loadTownSettingsQuiet(townRoot).IssueLinkSettings()
+	return issuelink.Scan(townRoot, sessions, issuelink.ScanOptions{
+		Since:     since,
+		IdleAfter: settings.IdleDuration(),
+		Comment:   settings.Comment,
+		GitHub:    settings.GitHubComments,
+		Prefixes:  issuelink.Prefixes(townRoot),
+	})
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceMCPJSON  bool
+	seanceMCPSince string
+	seanceMCPRig   string
+)
+
+var seanceMCPCmd = &cobra.Command{
+	Use:   "mcp [<server>]",
+	Short: "Audit which MCP servers agents call",
+	Long: `Audit the MCP integrations Gas Town sessions actually use.
+
+Claude Code names MCP tools mcp__<server>__<tool>. Every call an agent
+makes to one is counted per session, so this shows which servers are in
+use across the town: how many sessions called each, how many calls and
+failed calls they made, by which roles, and when last. Servers that are
+configured but never appear here are candidates for removal.
+
+With a server name, lists the sessions that called it, most recent
+first, with their calls by tool.
+
+Examples:
+  gt seance mcp
+  gt seance mcp --since 30d --rig gastown
+  gt seance mcp github
+  gt seance mcp --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSeanceMCP,
+}
+
+func init() {
+	seanceMCPCmd.Flags().BoolVar(&seanceMCPJSON, "json", false, "Output as JSON")
+	seanceMCPCmd.Flags().StringVar(&seanceMCPSince, "since", "7d", "Only sessions started at or after this time")
+	seanceMCPCmd.Flags().StringVar(&seanceMCPRig, "rig", "", "Only sessions in this rig")
+
+	seanceCmd.AddCommand(seanceMCPCmd)
+}
+
+func runSeanceMCP(cmd *cobra.Command, args []string) error {
+	since, _, err := parseTimeRange(seanceMCPSince, "", time.Now())
+	if err != nil {
+		return err
+	}
+	filter := claude.SessionFilter{
+		GasTownOnly: true,
+		Rig:         seanceMCPRig,
+		Since:       since,
+	}
+	if len(args) == 1 {
+		filter.MCPServer = args[0]
+	}
+	sessions, err := discoverClaudeSessions(filter)
+	if err != nil {
+		return fmt.Errorf("discovering sessions: %w", err)
+	}
+
+	if len(args) == 1 {
+		return printMCPServerSessions(args[0], sessions, since)
+	}
+
+	usage := claude.SummarizeMCPUsage(sessions)
+	if seanceMCPJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(usage)
+	}
+	if len(usage) == 0 {
+		fmt.Printf("No MCP tool calls in %d session(s) since %s.\n", len(sessions), since.Local().Format("2006-01-02 15:04"))
+		return nil
+	}
+
+	fmt.Printf("%s MCP servers called in %d session(s) since %s\n\n",
+		style.Bold.Render("🔌"), len(sessions), since.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("  %-20s %8s %7s %7s  %-15s %s\n", "SERVER", "SESSIONS", "CALLS", "ERRORS", "LAST USED", "ROLES")
+	for _, u := range usage {
+		errors := fmt.Sprintf("%7s", "-")
+		if u.Errors > 0 {
+			errors = style.Warning.Render(fmt.Sprintf("%7d", u.Errors))
+		}
+		fmt.Printf("  %-20s %8d %7d %s  %-15s %s\n",
+			u.Server, u.Sessions, u.Calls, errors, formatAge(u.LastUsed), summarizeRoles(u.Roles, 3))
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Sessions per server: gt seance mcp <server>"))
+	return nil
+}
+
+// printMCPServerSessions lists the sessions that called server.
+func printMCPServerSessions(server string, sessions []claude.SessionInfo, since time.Time) error {
+	prefix := "mcp__" + server + "__"
+	if seanceMCPJSON {
+		if sessions == nil {
+			sessions = []claude.SessionInfo{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sessions)
+	}
+	if len(sessions) == 0 {
+		fmt.Printf("No sessions called MCP server %q since %s.\n", server, since.Local().Format("2006-01-02 15:04"))
+		return nil
+	}
+
+	fmt.Printf("%s Sessions that called %s\n\n", style.Bold.Render("🔌"), server)
+	for _, s := range sessions {
+		var tools []string
+		for name, n := range s.MCPCalls {
+			if len(name) > len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+				tools = append(tools, fmt.Sprintf("%s×%d", name[len(prefix):], n))
+			}
+		}
+		sort.Strings(tools)
+		fmt.Printf("  %-12s  %-28s  %-15s %s\n", s.ShortID(), compactAddress(s.Role), formatAge(s.EndTime), strings.Join(tools, " "))
+	}
+	return nil
+}
+
+// summarizeRoles lists up to n roles, noting how many more there are.
+func summarizeRoles(roles []string, n int) string {
+	compact := make([]string, len(roles))
+	for i, role := range roles {
+		compact[i] = compactAddress(role)
+	}
+	if len(compact) <= n {
+		return strings.Join(compact, ", ")
+	}
+	return fmt.Sprintf("%s, +%d more", strings.Join(compact[:n], ", "), len(compact)-n)
+}
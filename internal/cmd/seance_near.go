@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceNearRig   string
+	seanceNearSince string
+	seanceNearLimit int
+	seanceNearExact bool
+	seanceNearJSON  bool
+)
+
+var seanceNearCmd = &cobra.Command{
+	Use:   "near <file>",
+	Short: "List the sessions that read or wrote a file",
+	Long: `List the agent sessions that read or wrote a file, most recently
+touched first: what agent context exists about this file?
+
+The file is resolved against the current directory. Inside a git
+checkout it matches by its path in the repository, so sessions that
+touched the same file in other checkouts (polecat worktrees, crew
+clones) are found too; --exact matches only this absolute path.
+
+The files each transcript touched are kept in the session index, so
+only transcripts that changed since the last lookup are read. This makes
+the command cheap enough for editor integrations to call on file open.
+
+Examples:
+  gt seance near internal/cmd/seance.go
+  gt seance near ./main.go --since 7d -n 5
+  gt seance near /town/gastown/mayor/rig/README.md --exact
+  gt seance near seance.go --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSeanceNear,
+}
+
+func init() {
+	seanceNearCmd.Flags().StringVar(&seanceNearRig, "rig", "", "Only sessions in this rig")
+	seanceNearCmd.Flags().StringVar(&seanceNearSince, "since", "", "Only sessions started at or after this time (e.g., 2d, 6h, today, 2025-01-02)")
+	seanceNearCmd.Flags().IntVarP(&seanceNearLimit, "limit", "n", 10, "Maximum sessions to list (0 = unlimited)")
+	seanceNearCmd.Flags().BoolVar(&seanceNearExact, "exact", false, "Match only this absolute path, not the same file in other checkouts")
+	seanceNearCmd.Flags().BoolVar(&seanceNearJSON, "json", false, "Output as JSON")
+
+	seanceCmd.AddCommand(seanceNearCmd)
+}
+
+// seanceNearOutput is the JSON form of gt seance near.
+type seanceNearOutput struct {
+	File     string               `json:"file"` // path matched against the sessions' files
+	Sessions []claude.FileSession `json:"sessions"`
+}
+
+func runSeanceNear(cmd *cobra.Command, args []string) error {
+	file, err := nearLookupPath(args[0], seanceNearExact)
+	if err != nil {
+		return err
+	}
+	filter := claude.SessionFilter{Rig: seanceNearRig}
+	if seanceNearSince != "" {
+		if filter.Since, err = parseTimeBound(seanceNearSince, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	matches, err := claude.SessionsNear(file, filter)
+	if err != nil {
+		if !errors.Is(err, claude.ErrIncomplete) {
+			return fmt.Errorf("finding sessions: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "%s %v; showing partial results\n", style.WarningPrefix, err)
+	}
+	if seanceNearLimit > 0 && len(matches) > seanceNearLimit {
+		matches = matches[:seanceNearLimit]
+	}
+
+	if seanceNearJSON {
+		if matches == nil {
+			matches = []claude.FileSession{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(seanceNearOutput{File: file, Sessions: matches})
+	}
+
+	if len(matches) == 0 {
+		fmt.Printf("No sessions touched %s\n", file)
+		return nil
+	}
+	fmt.Printf("%s %d session(s) touched %s\n\n", style.Bold.Render("📍"), len(matches), file)
+	for _, m := range matches {
+		line := fmt.Sprintf("  %s  %s  %-10s  %s", m.Session.ShortID(), m.Last.Local().Format("2006-01-02 15:04"),
+			nearAction(m), lineageTitle(m.Session))
+		fmt.Println(strings.TrimRight(line, " "))
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%d call(s) on %s", m.Count, m.Path)))
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Open one with: gt seance tail <id>"))
+	return nil
+}
+
+// nearLookupPath resolves the file argument to the path sessions' files
+// are matched against: relative to its git checkout's root when it is in
+// one, so every checkout of the file matches, and absolute otherwise or
+// with exact.
+func nearLookupPath(arg string, exact bool) (string, error) {
+	abs, err := filepath.Abs(arg)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", arg, err)
+	}
+	if exact {
+		return abs, nil
+	}
+	top, err := git.NewGit(filepath.Dir(abs)).TopLevel()
+	if err != nil {
+		return abs, nil
+	}
+	rel, err := filepath.Rel(filepath.Clean(top), abs)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return abs, nil
+	}
+	return rel, nil
+}
+
+// nearAction describes what a session did to the file.
+func nearAction(m claude.FileSession) string {
+	switch {
+	case m.Read && m.Wrote:
+		return "read+wrote"
+	case m.Wrote:
+		return "wrote"
+	default:
+		return "read"
+	}
+}
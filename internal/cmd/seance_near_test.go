@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+func TestNearLookupPath(t *testing.T) {
+	repo, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "init", repo).CombinedOutput(); err != nil {
+		t.Skipf("git init: %v: %s", err, out)
+	}
+	file := filepath.Join(repo, "internal", "cmd", "seance.go")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := nearLookupPath(file, false); err != nil || got != filepath.Join("internal", "cmd", "seance.go") {
+		t.Errorf("in checkout = %q, %v", got, err)
+	}
+	if got, err := nearLookupPath(file, true); err != nil || got != file {
+		t.Errorf("exact = %q, %v", got, err)
+	}
+	outside := filepath.Join(t.TempDir(), "notes.md")
+	if got, err := nearLookupPath(outside, false); err != nil || got != outside {
+		t.Errorf("outside a checkout = %q, %v", got, err)
+	}
+}
+
+func TestNearAction(t *testing.T) {
+	for _, tc := range []struct {
+		m    claude.FileSession
+		want string
+	}{
+		{claude.FileSession{Read: true}, "read"},
+		{claude.FileSession{Wrote: true}, "wrote"},
+		{claude.FileSession{Read: true, Wrote: true}, "read+wrote"},
+	} {
+		if got := nearAction(tc.m); got != tc.want {
+			t.Errorf("nearAction(%+v) = %q, want %q", tc.m, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// preflightTimeout bounds each service reachability check.
+var preflightTimeout = 3 * time.Second
+
+// Pre-flight check outcomes. A failed check aborts the resume; a warning
+// is printed and the resume goes ahead.
+const (
+	preflightOK   = "ok"
+	preflightWarn = "warn"
+	preflightFail = "fail"
+)
+
+// preflightCheck is one check of the workspace a session resumes into.
+type preflightCheck struct {
+	Name   string
+	Status string
+	Detail string
+	Fix    string // what to do about a warning or failure
+}
+
+// resumePreflight checks that session can resume into a working
+// workspace: its directory exists, the branch it was on still does, the
+// worktree is clean or stashable, and the services its rig's settings
+// list are reachable. townRoot may be empty outside a town, which skips
+// the service checks.
+func resumePreflight(townRoot string, session *claude.SessionInfo) []preflightCheck {
+	dir := session.ProjectPath
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return []preflightCheck{{
+			Name:   "workdir",
+			Status: preflightFail,
+			Detail: dir + " no longer exists",
+			Fix:    "recreate the worktree (e.g., gt crew add) or resume from another directory with claude --resume " + session.ID,
+		}}
+	}
+	checks := []preflightCheck{{Name: "workdir", Status: preflightOK, Detail: dir}}
+
+	branch := ""
+	if t, err := claude.ReadTranscript(session.Path); err == nil {
+		for _, m := range t.Messages {
+			if m.GitBranch != "" {
+				branch = m.GitBranch
+			}
+		}
+	}
+	checks = append(checks, checkResumeWorktree(dir, branch)...)
+
+	_, rig, _ := parseRoleString(session.Role)
+	if townRoot != "" && rig != "" {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rig)))
+		if err == nil {
+			for _, svc := range settings.Services {
+				checks = append(checks, checkService(svc))
+			}
+		}
+	}
+	return checks
+}
+
+// checkResumeWorktree checks the git state of dir against the branch the
+// session was last on ("" if the transcript doesn't say).
+func checkResumeWorktree(dir, branch string) []preflightCheck {
+	g := git.NewGit(dir)
+	if !g.IsRepo() {
+		return nil
+	}
+	var checks []preflightCheck
+
+	current, _ := g.CurrentBranch()
+	switch {
+	case branch == "" || branch == "HEAD":
+		// Unknown or detached: nothing to compare against
+	case current == branch:
+		checks = append(checks, preflightCheck{Name: "branch", Status: preflightOK, Detail: branch})
+	default:
+		local, err := g.BranchExists(branch)
+		_, remoteErr := g.Rev("origin/" + branch)
+		switch {
+		case err == nil && local:
+			checks = append(checks, preflightCheck{
+				Name:   "branch",
+				Status: preflightWarn,
+				Detail: fmt.Sprintf("on %s, but the session was on %s", current, branch),
+				Fix:    "git checkout " + branch,
+			})
+		case remoteErr == nil:
+			checks = append(checks, preflightCheck{
+				Name:   "branch",
+				Status: preflightFail,
+				Detail: fmt.Sprintf("%s exists only on origin", branch),
+				Fix:    fmt.Sprintf("git checkout -b %s origin/%s", branch, branch),
+			})
+		default:
+			checks = append(checks, preflightCheck{
+				Name:   "branch",
+				Status: preflightFail,
+				Detail: fmt.Sprintf("%s no longer exists (merged or deleted?)", branch),
+				Fix:    "recreate it, or resume on purpose with --skip-preflight and tell the session which branch to use",
+			})
+		}
+	}
+
+	if conflicts, err := g.GetConflictingFiles(); err == nil && len(conflicts) > 0 {
+		return append(checks, preflightCheck{
+			Name:   "worktree",
+			Status: preflightFail,
+			Detail: fmt.Sprintf("%d file(s) with unresolved conflicts", len(conflicts)),
+			Fix:    "resolve them, or git merge --abort / git rebase --abort",
+		})
+	}
+	status, err := g.Status()
+	switch {
+	case err != nil:
+		checks = append(checks, preflightCheck{Name: "worktree", Status: preflightWarn, Detail: err.Error()})
+	case status.Clean:
+		checks = append(checks, preflightCheck{Name: "worktree", Status: preflightOK, Detail: "clean"})
+	default:
+		n := len(status.Modified) + len(status.Added) + len(status.Deleted) + len(status.Untracked)
+		checks = append(checks, preflightCheck{
+			Name:   "worktree",
+			Status: preflightWarn,
+			Detail: fmt.Sprintf("%d uncommitted change(s)", n),
+			Fix:    "if they aren't the session's own work in progress: git stash",
+		})
+	}
+	return checks
+}
+
+// checkService checks a rig service is reachable.
+func checkService(svc config.ServiceConfig) preflightCheck {
+	name := "service " + svc.Name
+	if svc.Address != "" {
+		conn, err := net.DialTimeout("tcp", svc.Address, preflightTimeout)
+		if err != nil {
+			return preflightCheck{Name: name, Status: preflightFail, Detail: svc.Address + " unreachable", Fix: "start " + svc.Name + " before resuming"}
+		}
+		_ = conn.Close()
+	}
+	if svc.URL != "" {
+		client := &http.Client{Timeout: preflightTimeout}
+		resp, err := client.Get(svc.URL)
+		if err != nil {
+			return preflightCheck{Name: name, Status: preflightFail, Detail: svc.URL + " unreachable", Fix: "start " + svc.Name + " before resuming"}
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			return preflightCheck{Name: name, Status: preflightFail, Detail: fmt.Sprintf("%s answered %s", svc.URL, resp.Status), Fix: "check " + svc.Name + "'s logs"}
+		}
+	}
+	target := svc.Address
+	if target == "" {
+		target = svc.URL
+	}
+	return preflightCheck{Name: name, Status: preflightOK, Detail: target}
+}
+
+// printPreflight prints the checks and reports whether any failed.
+func printPreflight(checks []preflightCheck) bool {
+	failed := false
+	fmt.Printf("%s\n", style.Bold.Render("Pre-flight checks:"))
+	for _, c := range checks {
+		mark := style.SuccessPrefix
+		switch c.Status {
+		case preflightWarn:
+			mark = style.WarningPrefix
+		case preflightFail:
+			mark = style.ErrorPrefix
+			failed = true
+		}
+		fmt.Printf("  %s %-16s %s\n", mark, c.Name, c.Detail)
+		if c.Fix != "" {
+			fmt.Printf("    %s\n", style.Dim.Render("→ "+c.Fix))
+		}
+	}
+	fmt.Println()
+	return failed
+}
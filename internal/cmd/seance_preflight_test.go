@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func initPreflightRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.email=t@example.com", "-c", "user.name=t", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"branch", "feature"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func checkByName(checks []preflightCheck, name string) *preflightCheck {
+	for i := range checks {
+		if checks[i].Name == name {
+			return &checks[i]
+		}
+	}
+	return nil
+}
+
+func TestCheckResumeWorktree(t *testing.T) {
+	dir := initPreflightRepo(t)
+
+	tests := []struct {
+		branch string
+		want   string // status of the branch check; "" for none
+	}{
+		{"main", preflightOK},
+		{"feature", preflightWarn},
+		{"gone", preflightFail},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		checks := checkResumeWorktree(dir, tt.branch)
+		c := checkByName(checks, "branch")
+		switch {
+		case tt.want == "" && c != nil:
+			t.Errorf("branch %q: unexpected branch check %+v", tt.branch, *c)
+		case tt.want != "" && (c == nil || c.Status != tt.want):
+			t.Errorf("branch %q: branch check = %+v, want %s", tt.branch, c, tt.want)
+		}
+		if w := checkByName(checks, "worktree"); w == nil || w.Status != preflightOK {
+			t.Errorf("branch %q: worktree check = %+v, want ok", tt.branch, w)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "scratch.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w := checkByName(checkResumeWorktree(dir, "main"), "worktree")
+	if w == nil || w.Status != preflightWarn || w.Fix == "" {
+		t.Errorf("dirty worktree check = %+v, want a warning with a fix", w)
+	}
+}
+
+func TestCheckService(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+
+	if c := checkService(config.ServiceConfig{Name: "db", Address: addr}); c.Status != preflightOK {
+		t.Errorf("listening service = %+v, want ok", c)
+	}
+	_ = ln.Close()
+	if c := checkService(config.ServiceConfig{Name: "db", Address: addr}); c.Status != preflightFail {
+		t.Errorf("closed service = %+v, want fail", c)
+	}
+}
+
+func TestResumePreflightMissingWorkdir(t *testing.T) {
+	session := &claude.SessionInfo{ID: "abc123", ProjectPath: filepath.Join(t.TempDir(), "gone")}
+	checks := resumePreflight("", session)
+	if len(checks) != 1 || checks[0].Name != "workdir" || checks[0].Status != preflightFail {
+		t.Errorf("checks = %+v, want a single failed workdir check", checks)
+	}
+}
+
+func TestResumePreflightServices(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	settings := config.NewRigSettings()
+	settings.Services = []config.ServiceConfig{{Name: "db", Address: "127.0.0.1:1"}}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	session := &claude.SessionInfo{ID: "abc123", ProjectPath: t.TempDir(), Role: "gastown/crew/joe"}
+	c := checkByName(resumePreflight(townRoot, session), "service db")
+	if c == nil || c.Status != preflightFail {
+		t.Errorf("service check = %+v, want fail", c)
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceRedactRules    string
+	seanceRedactPatterns []string
+	seanceRedactDryRun   bool
+	seanceRedactNoBackup bool
+	seanceRedactJSON     bool
+)
+
+var seanceRedactCmd = &cobra.Command{
+	Use:   "redact <session-id>",
+	Short: "Remove secrets from a session transcript in place",
+	Long: `Rewrite a Claude Code session transcript with secrets removed.
+
+For incident response when an agent accidentally echoed a key into its
+transcript. Each match is replaced with [REDACTED:<rule>]. The original
+is kept as <file>.bak-<timestamp> unless --no-backup is given - delete
+the backup once you've confirmed the redaction.
+
+Built-in rule sets:
+  secrets   Anthropic/OpenAI keys, AWS access keys, GitHub and Slack
+            tokens, private key blocks, bearer tokens
+
+Examples:
+  gt seance redact abc123 --rules secrets --dry-run
+  gt seance redact abc123 --rules secrets
+  gt seance redact abc123 --pattern 'hunter2'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSeanceRedact,
+}
+
+func init() {
+	seanceRedactCmd.Flags().StringVar(&seanceRedactRules, "rules", "secrets", "Comma-separated rule sets to apply")
+	seanceRedactCmd.Flags().StringArrayVar(&seanceRedactPatterns, "pattern", nil, "Additional regex to redact (repeatable)")
+	seanceRedactCmd.Flags().BoolVar(&seanceRedactDryRun, "dry-run", false, "Report matches without rewriting")
+	seanceRedactCmd.Flags().BoolVar(&seanceRedactNoBackup, "no-backup", false, "Don't keep a backup of the original")
+	seanceRedactCmd.Flags().BoolVar(&seanceRedactJSON, "json", false, "Output as JSON")
+
+	seanceCmd.AddCommand(seanceRedactCmd)
+}
+
+func runSeanceRedact(cmd *cobra.Command, args []string) error {
+	session, err := claude.FindSession(args[0])
+	if err != nil {
+		return err
+	}
+
+	var rules []claude.RedactRule
+	if seanceRedactRules != "" {
+		rules, err = claude.RedactRules(strings.Split(seanceRedactRules, ",")...)
+		if err != nil {
+			return err
+		}
+	}
+	for i, p := range seanceRedactPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("invalid --pattern %q: %w", p, err)
+		}
+		rules = append(rules, claude.RedactRule{Name: fmt.Sprintf("pattern-%d", i+1), Pattern: re})
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("no rules given (use --rules or --pattern)")
+	}
+
+	result, err := claude.RedactSession(session.Path, rules, claude.RedactOptions{
+		DryRun:   seanceRedactDryRun,
+		NoBackup: seanceRedactNoBackup,
+	})
+	if err != nil {
+		return fmt.Errorf("redacting session: %w", err)
+	}
+
+	if seanceRedactJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	if result.Total == 0 {
+		fmt.Printf("%s No matches in %s\n", style.SuccessPrefix, session.ShortID())
+		return nil
+	}
+
+	names := make([]string, 0, len(result.Matches))
+	for name := range result.Matches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	verb := "Redacted"
+	if result.DryRun {
+		verb = "Would redact"
+	}
+	fmt.Printf("%s %s %d match(es) on %d line(s) in %s\n",
+		style.Bold.Render("🔒"), verb, result.Total, result.Lines, session.ShortID())
+	for _, name := range names {
+		fmt.Printf("  %-16s %d\n", name, result.Matches[name])
+	}
+
+	if result.BackupPath != "" {
+		fmt.Printf("\n%s Backup (still contains the secrets): %s\n", style.WarningPrefix, result.BackupPath)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/actions"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// resumeContextDir holds context packets waiting for a resumed session's
+// SessionStart hook, relative to the town root.
+const resumeContextDir = ".runtime/resume-context"
+
+// resumeContextTTL is how long a packet waits for its session to start.
+const resumeContextTTL = 15 * time.Minute
+
+var (
+	seanceResumeWithContext   bool
+	seanceResumeDryRun        bool
+	seanceResumeSkipPreflight bool
+)
+
+var seanceResumeCmd = &cobra.Command{
+	Use:   "resume <session-id>",
+	Short: "Resume a dead session, optionally briefing it on what changed",
+	Long: `Resume a predecessor session in its original working directory.
+
+Unlike --talk, which forks a read-only copy, resume continues the session
+itself so it can pick its work back up.
+
+Pre-flight checks run first, and the resume is aborted with guidance if
+the workspace is broken:
+  - the working directory still exists
+  - the branch the session was on still exists and is checked out
+  - the worktree is clean, or its changes can be stashed
+  - the services in the rig's settings are reachable, e.g.
+      "services": [{"name": "postgres", "address": "localhost:5432"},
+                   {"name": "api", "url": "http://localhost:3000/health"}]
+Use --skip-preflight to resume anyway.
+
+With --with-context, a context packet is prepended to the resumed session
+through the SessionStart hook (gt prime --hook), so the agent immediately
+learns what changed while it was dead:
+  - unread mail
+  - handoffs sent to it since the session ended
+  - beads assigned to it since the session ended
+
+Examples:
+  gt seance resume abc123
+  gt seance resume abc123 --with-context
+  gt seance resume abc123 --with-context --dry-run   # Show the packet only
+  gt seance resume abc123 --skip-preflight`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSeanceResume,
+}
+
+func init() {
+	seanceResumeCmd.Flags().BoolVar(&seanceResumeWithContext, "with-context", false, "Brief the resumed session on mail, handoffs, and assignments since it ended")
+	seanceResumeCmd.Flags().BoolVarP(&seanceResumeDryRun, "dry-run", "n", false, "Print the context packet without resuming")
+	seanceResumeCmd.Flags().BoolVar(&seanceResumeSkipPreflight, "skip-preflight", false, "Resume without checking the workspace first")
+
+	seanceCmd.AddCommand(seanceResumeCmd)
+}
+
+// resumeContext is a context packet waiting for a resumed session.
+type resumeContext struct {
+	SessionID string    `json:"session_id"`
+	WorkDir   string    `json:"work_dir"`
+	Context   string    `json:"context"`
+	Created   time.Time `json:"created"`
+}
+
+func runSeanceResume(cmd *cobra.Command, args []string) error {
+	session, err := claude.FindSession(args[0])
+	if err != nil {
+		return err
+	}
+
+	if !seanceResumeSkipPreflight {
+		townRoot, _ := workspace.FindFromCwd()
+		if printPreflight(resumePreflight(townRoot, session)) {
+			return fmt.Errorf("pre-flight checks failed; fix the above or pass --skip-preflight")
+		}
+	}
+
+	if seanceResumeWithContext {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		if session.Role == "" {
+			return fmt.Errorf("session %s has no Gas Town role; cannot build context", session.ShortID())
+		}
+
+		packet := buildResumeContext(townRoot, session.Role, sessionEndTime(*session))
+		if seanceResumeDryRun {
+			if packet == "" {
+				fmt.Println(style.Dim.Render("Nothing changed since the session ended."))
+			} else {
+				fmt.Print(packet)
+			}
+			return nil
+		}
+		if packet != "" {
+			if err := saveResumeContext(townRoot, resumeContext{
+				SessionID: session.ID,
+				WorkDir:   session.ProjectPath,
+				Context:   packet,
+				Created:   time.Now(),
+			}); err != nil {
+				return err
+			}
+			fmt.Printf("%s Context packet queued for the resumed session\n", style.SuccessPrefix)
+		}
+	} else if seanceResumeDryRun {
+		return fmt.Errorf("--dry-run requires --with-context")
+	}
+
+	fmt.Printf("%s Resuming session %s...\n\n", style.Bold.Render("🔮"), session.ShortID())
+	c := exec.Command("claude", "--resume", session.ID) //nolint:gosec // G204: session ID from discovery
+	if info, err := os.Stat(session.ProjectPath); err == nil && info.IsDir() {
+		c.Dir = session.ProjectPath
+	}
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	done := actions.Track(actions.KindResume, actions.OpExec, session.ID, c.Dir, strings.Join(c.Args, " "))
+	err = c.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok && (exitErr.ExitCode() == 0 || exitErr.ExitCode() == 130) {
+		err = nil
+	}
+	done(err)
+	if err != nil {
+		return fmt.Errorf("resume ended: %w", err)
+	}
+	return nil
+}
+
+// sessionEndTime returns when a session last wrote to its transcript.
+func sessionEndTime(s claude.SessionInfo) time.Time {
+	if usage, err := claude.ReadUsage(s.Path); err == nil && !usage.LastTimestamp.IsZero() {
+		return usage.LastTimestamp
+	}
+	if info, err := os.Stat(s.Path); err == nil {
+		return info.ModTime()
+	}
+	return s.StartTime
+}
+
+// buildResumeContext summarizes what changed for agent since the given
+// time. Returns "" when there is nothing to report.
+func buildResumeContext(townRoot, agent string, since time.Time) string {
+	var unread, handoffs []string
+	if mailbox, err := mail.NewRouter(townRoot).GetMailbox(agent); err == nil {
+		if messages, err := mailbox.List(); err == nil {
+			for _, msg := range messages {
+				line := fmt.Sprintf("- %s: %s (from %s, %s)", msg.ID, msg.Subject, msg.From, msg.Timestamp.Local().Format("Jan 2 15:04"))
+				switch {
+				case strings.Contains(msg.Subject, "HANDOFF") && msg.Timestamp.After(since):
+					handoffs = append(handoffs, line)
+				case !msg.Read:
+					unread = append(unread, line)
+				}
+			}
+		}
+	}
+
+	var assigned []string
+	_, rig, _ := parseRoleString(agent)
+	beadsDir := townRoot
+	if rig != "" {
+		beadsDir = filepath.Join(townRoot, rig)
+	}
+	if issues, err := beads.New(beads.ResolveBeadsDir(beadsDir)).ListByAssignee(agent); err == nil {
+		for _, issue := range issues {
+			if issue.Status == "closed" {
+				continue
+			}
+			updated, err := time.Parse(time.RFC3339, issue.UpdatedAt)
+			if err != nil || !updated.After(since) {
+				continue
+			}
+			assigned = append(assigned, fmt.Sprintf("- %s: %s [%s]", issue.ID, issue.Title, issue.Status))
+		}
+	}
+
+	if len(unread)+len(handoffs)+len(assigned) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are resuming after being offline since %s. Here is what changed:\n", since.Local().Format("2006-01-02 15:04"))
+	section := func(title, hint string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n### %s\n", title)
+		for _, l := range lines {
+			b.WriteString(l + "\n")
+		}
+		if hint != "" {
+			fmt.Fprintf(&b, "(%s)\n", hint)
+		}
+	}
+	section("Handoffs", "read with: gt mail read <id>", handoffs)
+	section("Unread mail", "read with: gt mail read <id>", unread)
+	section("New or updated assignments", "details: bd show <id>", assigned)
+	return b.String()
+}
+
+// resumeContextPath returns the packet file for a working directory.
+func resumeContextPath(townRoot, workDir string) string {
+	key := strings.NewReplacer("/", "-", string(filepath.Separator), "-", ":", "-").Replace(filepath.Clean(workDir))
+	return filepath.Join(townRoot, resumeContextDir, key+".json")
+}
+
+// saveResumeContext queues a packet for the next session started in its
+// working directory.
+func saveResumeContext(townRoot string, rc resumeContext) error {
+	path := resumeContextPath(townRoot, rc.WorkDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating resume context directory: %w", err)
+	}
+	return util.AtomicWriteJSON(path, rc)
+}
+
+// takeResumeContext returns and removes the packet queued for workDir, if
+// one exists and hasn't expired.
+func takeResumeContext(townRoot, workDir string, now time.Time) *resumeContext {
+	path := resumeContextPath(townRoot, workDir)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil
+	}
+	_ = os.Remove(path)
+
+	var rc resumeContext
+	if err := json.Unmarshal(data, &rc); err != nil || now.Sub(rc.Created) > resumeContextTTL {
+		return nil
+	}
+	return &rc
+}
+
+// outputResumeContext prints a queued context packet for a session being
+// resumed via gt seance resume --with-context. Claude Code adds SessionStart
+// hook output to the session's context.
+func outputResumeContext(ctx RoleContext, source string) {
+	if source != "" && source != "resume" {
+		return
+	}
+	rc := takeResumeContext(ctx.TownRoot, ctx.WorkDir, time.Now())
+	if rc == nil {
+		return
+	}
+	fmt.Println()
+	fmt.Printf("%s\n\n", style.Bold.Render("## 📬 While You Were Away"))
+	fmt.Print(rc.Context)
+}
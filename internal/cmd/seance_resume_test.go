@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResumeContextRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	workDir := "/home/gt/gastown/crew/joe"
+	now := time.Now()
+
+	if rc := takeResumeContext(townRoot, workDir, now); rc != nil {
+		t.Fatalf("takeResumeContext with nothing queued = %+v", rc)
+	}
+
+	if err := saveResumeContext(townRoot, resumeContext{SessionID: "s1", WorkDir: workDir, Context: "news", Created: now}); err != nil {
+		t.Fatalf("saveResumeContext: %v", err)
+	}
+	rc := takeResumeContext(townRoot, workDir, now.Add(time.Minute))
+	if rc == nil || rc.Context != "news" || rc.SessionID != "s1" {
+		t.Fatalf("takeResumeContext = %+v", rc)
+	}
+	if rc := takeResumeContext(townRoot, workDir, now); rc != nil {
+		t.Error("packet should be consumed after the first take")
+	}
+
+	// Expired packets are discarded
+	if err := saveResumeContext(townRoot, resumeContext{WorkDir: workDir, Context: "old", Created: now}); err != nil {
+		t.Fatal(err)
+	}
+	if rc := takeResumeContext(townRoot, workDir, now.Add(resumeContextTTL+time.Minute)); rc != nil {
+		t.Errorf("expired packet returned: %+v", rc)
+	}
+}
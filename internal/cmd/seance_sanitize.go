@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceSanitizeOut  string
+	seanceSanitizeJSON bool
+)
+
+var seanceSanitizeCmd = &cobra.Command{
+	Use:   "sanitize <session-id>",
+	Short: "Write an anonymized copy of a transcript for bug reports",
+	Long: `Write an anonymized copy of a Claude Code session transcript.
+
+Use this before sending a transcript to a vendor's support team. The
+original is left untouched. In the copy:
+
+  - session, message, and tool IDs become stable placeholders, so
+    parent/child links still line up
+  - paths become /sanitized/path-N (extensions kept)
+  - tool inputs, tool results, and fenced code in messages become
+    [code-N: L lines]
+  - secrets in the remaining text are redacted
+
+Timestamps, models, tool names, token usage, and the JSONL structure are
+preserved. Review the output before sending it - prose in messages is
+kept and may still mention project details.
+
+Examples:
+  gt seance sanitize abc123 --out sanitized.jsonl`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSeanceSanitize,
+}
+
+func init() {
+	seanceSanitizeCmd.Flags().StringVarP(&seanceSanitizeOut, "out", "o", "", "Output file (default: <session-id>.sanitized.jsonl)")
+	seanceSanitizeCmd.Flags().BoolVar(&seanceSanitizeJSON, "json", false, "Output as JSON")
+
+	seanceCmd.AddCommand(seanceSanitizeCmd)
+}
+
+func runSeanceSanitize(cmd *cobra.Command, args []string) error {
+	session, err := claude.FindSession(args[0])
+	if err != nil {
+		return err
+	}
+
+	out := seanceSanitizeOut
+	if out == "" {
+		out = session.ShortID() + ".sanitized.jsonl"
+	}
+
+	result, err := claude.SanitizeSession(session.Path, out)
+	if err != nil {
+		return fmt.Errorf("sanitizing session: %w", err)
+	}
+
+	if seanceSanitizeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	fmt.Printf("%s Wrote %s (%d lines)\n", style.SuccessPrefix, out, result.Lines)
+	fmt.Printf("  %-18s %d\n", "IDs replaced:", result.IDs)
+	fmt.Printf("  %-18s %d\n", "Paths replaced:", result.Paths)
+	fmt.Printf("  %-18s %d\n", "Code replaced:", result.CodeBlocks)
+	fmt.Printf("  %-18s %d\n", "Secrets redacted:", result.Secrets)
+	if result.Skipped > 0 {
+		fmt.Printf("  %s %d unparseable line(s) dropped\n", style.WarningPrefix, result.Skipped)
+	}
+	fmt.Printf("\n%s Review the prose before sending; it is kept as written.\n", style.Dim.Render("Note:"))
+	return nil
+}
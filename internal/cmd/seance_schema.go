@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceSchemaJSON  bool
+	seanceSchemaSince string
+)
+
+// seanceSchemaFieldLimit caps the unknown fields listed in text output.
+const seanceSchemaFieldLimit = 20
+
+var seanceSchemaCmd = &cobra.Command{
+	Use:   "schema [<session-id>]",
+	Short: "Report the transcript formats Claude Code wrote",
+	Long: `Report which transcript schema variants sessions use.
+
+Claude Code's JSONL format changes between versions: fields are added or
+renamed, and timestamps have been written both as strings and as epoch
+numbers. gt reads every known variant, keeps entries of types it doesn't
+recognize (exports include them verbatim), and ignores fields it doesn't
+know. This command shows what it found, so a Claude Code upgrade that
+changed the format is spotted before numbers drift:
+
+  versions        Claude Code versions that wrote the lines
+  variants        camelCase or snake_case field names, epoch_time
+                  timestamps, block_content system entries
+  unknown types   entry types gt skips (kept raw in exports)
+  unknown fields  top-level fields gt ignores, as type.field
+
+With a session ID, reports that session and lists its unrecognized
+entries by line. Without, totals every session started since --since.
+
+Examples:
+  gt seance schema
+  gt seance schema --since 30d --json
+  gt seance schema abc123`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSeanceSchema,
+}
+
+func init() {
+	seanceSchemaCmd.Flags().BoolVar(&seanceSchemaJSON, "json", false, "Output as JSON")
+	seanceSchemaCmd.Flags().StringVar(&seanceSchemaSince, "since", "7d", "Without a session ID, only sessions started at or after this time")
+
+	seanceCmd.AddCommand(seanceSchemaCmd)
+}
+
+func runSeanceSchema(cmd *cobra.Command, args []string) error {
+	var report *claude.SchemaReport
+	title := ""
+	if len(args) == 1 {
+		session, err := claude.FindSession(args[0])
+		if err != nil {
+			return err
+		}
+		report, err = claude.ReadTranscriptSchema(session.Path)
+		if err != nil {
+			return fmt.Errorf("reading transcript: %w", err)
+		}
+		title = "Transcript schema of " + session.ShortID()
+	} else {
+		since, _, err := parseTimeRange(seanceSchemaSince, "", time.Now())
+		if err != nil {
+			return err
+		}
+		sessions, err := discoverClaudeSessions(claude.SessionFilter{Since: since})
+		if err != nil {
+			return fmt.Errorf("discovering sessions: %w", err)
+		}
+		report = claude.NewSchemaReport()
+		for _, s := range sessions {
+			r, err := claude.ReadTranscriptSchema(s.Path)
+			if err != nil {
+				continue
+			}
+			report.Merge(r)
+		}
+		title = fmt.Sprintf("Transcript schema since %s", since.Local().Format("2006-01-02 15:04"))
+	}
+
+	if seanceSchemaJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printSchemaReport(title, report)
+	return nil
+}
+
+// printSchemaReport prints a report's sections, skipping empty ones.
+func printSchemaReport(title string, r *claude.SchemaReport) {
+	fmt.Printf("%s %s\n", style.Bold.Render("🧬"), title)
+	fmt.Printf("  %d transcript(s), %d line(s)", r.Transcripts, r.Lines)
+	if r.Invalid > 0 {
+		fmt.Printf(", %s", style.Warning.Render(fmt.Sprintf("%d not JSON", r.Invalid)))
+	}
+	fmt.Println()
+
+	if names := r.VersionNames(); len(names) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Claude Code versions"))
+		for _, v := range names {
+			fmt.Printf("  %-16s %d\n", v, r.Versions[v])
+		}
+	}
+	if len(r.Variants) > 0 {
+		counts := make(map[string]int, len(r.Variants))
+		for v, n := range r.Variants {
+			counts[string(v)] = n
+		}
+		printSchemaCounts("Variants", counts, 0)
+	}
+	printSchemaCounts("Unknown entry types", r.UnknownTypes, 0)
+	printSchemaCounts("Unknown fields", r.UnknownFields, seanceSchemaFieldLimit)
+
+	if len(r.Unknown) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Unrecognized entries"))
+		for _, e := range r.Unknown {
+			fmt.Printf("  line %-6d %s\n", e.Line, e.Type)
+		}
+	}
+	if r.Clean() && r.Lines > 0 {
+		fmt.Printf("\n%s\n", style.Dim.Render("Every line was decoded in full."))
+	}
+}
+
+// printSchemaCounts prints counts, most frequent first, up to limit
+// entries (0 for all).
+func printSchemaCounts(heading string, counts map[string]int, limit int) {
+	if len(counts) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	fmt.Printf("\n%s\n", style.Bold.Render(heading))
+	for i, k := range keys {
+		if limit > 0 && i == limit {
+			fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("… and %d more (--json for all)", len(keys)-limit)))
+			break
+		}
+		fmt.Printf("  %-32s %d\n", k, counts[k])
+	}
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/ui"
+)
+
+var (
+	seanceShowFull            bool
+	seanceShowExpandSubagents bool
+	seanceShowJSONTurns       bool
+	seanceShowPlan            bool
+)
+
+// seanceShowResultLimit truncates inline subagent result summaries.
+const seanceShowResultLimit = 120
+
+var seanceShowCmd = &cobra.Command{
+	Use:   "show <session-id>",
+	Short: "Show a session's full conversation",
+	Long: `Pretty-print every turn of a session transcript.
+
+Task tool calls are resolved to their subagent and shown inline with a
+one-line summary of what the subagent returned. With --expand-subagents,
+the subagent's whole conversation is inlined instead, so reviewing
+delegation-heavy sessions doesn't require chasing agent-*.jsonl files.
+
+With --json-turns, the transcript is written as a JSON array of turns
+for external review tools. Each turn has an index, timestamp, role, text,
+and tool_calls; each tool call carries its input, result, and error flag.
+Split assistant messages are merged and tool results are nested under the
+call that produced them. Combine with --expand-subagents to nest each Task
+call's subagent turns under "subagent".
+
+If the session kept a task list (TodoWrite, or TaskCreate/TaskUpdate), its
+final state is printed after the conversation: what the agent planned and
+which items it finished. With --plan, only the plan is printed.
+
+Examples:
+  gt seance show abc123
+  gt seance show abc123 --expand-subagents
+  gt seance show abc123 --full | less -R
+  gt seance show abc123 --plan
+  gt seance show abc123 --json-turns > abc123.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSeanceShow,
+}
+
+func init() {
+	seanceShowCmd.Flags().BoolVar(&seanceShowFull, "full", false, "Don't truncate long turns")
+	seanceShowCmd.Flags().BoolVar(&seanceShowExpandSubagents, "expand-subagents", false, "Inline each subagent's full conversation")
+	seanceShowCmd.Flags().BoolVar(&seanceShowJSONTurns, "json-turns", false, "Output turns as structured JSON with tool calls nested")
+	seanceShowCmd.Flags().BoolVar(&seanceShowPlan, "plan", false, "Only show the session's task list")
+
+	seanceCmd.AddCommand(seanceShowCmd)
+}
+
+func runSeanceShow(cmd *cobra.Command, args []string) error {
+	session, err := claude.FindSession(args[0])
+	if err != nil {
+		return err
+	}
+
+	if seanceShowJSONTurns {
+		return writeSeanceJSONTurns(os.Stdout, session.Path, seanceShowExpandSubagents)
+	}
+
+	plan, err := claude.ReadPlan(session.Path)
+	if err != nil {
+		return fmt.Errorf("reading plan: %w", err)
+	}
+	if seanceShowPlan {
+		if plan == nil {
+			fmt.Printf("Session %s kept no task list.\n", session.ShortID())
+			return nil
+		}
+		printSessionPlan(plan)
+		return nil
+	}
+
+	turns, err := claude.ReadTurns(session.Path)
+	if err != nil {
+		return fmt.Errorf("reading transcript: %w", err)
+	}
+	calls, err := claude.ReadSubagentCalls(session.Path)
+	if err != nil {
+		return fmt.Errorf("reading subagent calls: %w", err)
+	}
+
+	label := session.Role
+	if label == "" {
+		label = session.ProjectPath
+	}
+	fmt.Printf("%s %s  %s\n\n", style.Bold.Render("🔮"), session.ShortID(), style.Dim.Render(label))
+
+	next := 0
+	printCallsUpTo := func(turnCount int) {
+		for next < len(calls) && calls[next].AfterTurn <= turnCount {
+			printSubagentCall(session.Path, calls[next])
+			next++
+		}
+	}
+
+	printCallsUpTo(0)
+	for i, t := range turns {
+		printSeanceTurnIndented(t, "", seanceShowFull)
+		printCallsUpTo(i + 1)
+	}
+	printCallsUpTo(len(turns))
+	if plan != nil {
+		printSessionPlan(plan)
+	}
+	return nil
+}
+
+// printSessionPlan prints a session's final task list with each item's
+// state.
+func printSessionPlan(plan *claude.Plan) {
+	fmt.Printf("%s %d/%d done (%.0f%%)\n", style.Bold.Render("📋 Plan"),
+		plan.Completed, len(plan.Items), plan.Completion*100)
+	for _, it := range plan.Items {
+		switch it.State {
+		case claude.PlanCompleted:
+			fmt.Printf("  %s %s\n", style.Success.Render(ui.StatusIconClosed), style.Dim.Render(it.Content))
+		case claude.PlanInProgress:
+			fmt.Printf("  %s %s %s\n", style.Warning.Render(ui.StatusIconInProgress), it.Content, style.Dim.Render("(in progress)"))
+		default:
+			fmt.Printf("  %s %s\n", ui.StatusIconOpen, it.Content)
+		}
+	}
+}
+
+// writeSeanceJSONTurns writes a transcript's structured turns as JSON.
+func writeSeanceJSONTurns(w io.Writer, path string, expandSubagents bool) error {
+	turns, err := claude.ReadTranscriptTurns(path)
+	if err != nil {
+		return fmt.Errorf("reading transcript: %w", err)
+	}
+	if expandSubagents {
+		claude.ExpandSubagentTurns(path, turns)
+	}
+	if turns == nil {
+		turns = []claude.TranscriptTurn{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(turns)
+}
+
+// printSubagentCall renders a Task delegation inline: a one-line result
+// summary, or with --expand-subagents the child's whole conversation.
+func printSubagentCall(sessionPath string, call claude.SubagentCall) {
+	header := "subagent"
+	if call.Type != "" {
+		header += " " + call.Type
+	}
+	if call.Description != "" {
+		header += ": " + call.Description
+	}
+
+	if !seanceShowExpandSubagents {
+		fmt.Printf("  %s %s\n", style.Bold.Render("↳"), header)
+		fmt.Printf("    %s\n\n", style.Dim.Render(subagentResultSummary(call.Result)))
+		return
+	}
+
+	transcript := claude.SubagentTranscript(sessionPath, call)
+	source := "transcript not found"
+	if transcript != "" {
+		source = filepath.Base(transcript)
+	}
+	fmt.Printf("  %s %s  %s\n", style.Bold.Render("┌"), header, style.Dim.Render(source))
+	fmt.Println("  │")
+
+	if transcript != "" {
+		turns, err := claude.ReadTurns(transcript)
+		if err != nil {
+			fmt.Printf("  │ %s\n", style.Dim.Render(fmt.Sprintf("(reading transcript: %v)", err)))
+		}
+		for _, t := range turns {
+			printSeanceTurnIndented(t, "  │ ", seanceShowFull)
+		}
+	}
+	fmt.Printf("  %s %s\n\n", style.Bold.Render("└"), style.Dim.Render(subagentResultSummary(call.Result)))
+}
+
+// subagentResultSummary returns the first line of a subagent's result,
+// truncated for inline display.
+func subagentResultSummary(result string) string {
+	result = strings.TrimSpace(result)
+	if result == "" {
+		return "(no result yet)"
+	}
+	line, _, _ := strings.Cut(result, "\n")
+	if len(line) > seanceShowResultLimit {
+		line = line[:seanceShowResultLimit] + "…"
+	} else if strings.Contains(result, "\n") {
+		line += " …"
+	}
+	return line
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceStatsTop  int
+	seanceStatsJSON bool
+)
+
+var seanceStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how much disk space session transcripts take",
+	Long: `Show the disk space session data takes up in the Claude data
+directories: the total, by rig, by project directory, and the largest
+sessions.
+
+A session's size is its transcript plus its <session-id>/ directory of
+subagent transcripts and tool output. Rig sizes add up the sizes of each
+rig's Gas Town sessions; sessions without a beacon count as "-".
+
+Use 'gt seance archive' or 'gt seance prune' to reclaim the space.
+
+Examples:
+  gt seance stats
+  gt seance stats --top 25
+  gt seance stats --json`,
+	Args: cobra.NoArgs,
+	RunE: runSeanceStats,
+}
+
+func init() {
+	seanceStatsCmd.Flags().IntVarP(&seanceStatsTop, "top", "n", 10, "Number of projects and sessions to list")
+	seanceStatsCmd.Flags().BoolVar(&seanceStatsJSON, "json", false, "Output as JSON")
+
+	seanceCmd.AddCommand(seanceStatsCmd)
+}
+
+// rigDiskUsage is the space one rig's sessions take up.
+type rigDiskUsage struct {
+	Rig      string `json:"rig"`
+	Sessions int    `json:"sessions"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// sessionDiskUsage is one of the largest sessions.
+type sessionDiskUsage struct {
+	SessionID   string `json:"session_id"`
+	Role        string `json:"role,omitempty"`
+	ProjectPath string `json:"project_path,omitempty"`
+	Bytes       int64  `json:"bytes"`
+}
+
+// seanceStatsOutput is the JSON form of gt seance stats.
+type seanceStatsOutput struct {
+	Bytes    int64                     `json:"bytes"`
+	Rigs     []rigDiskUsage            `json:"rigs"`
+	Projects []claude.ProjectDiskUsage `json:"projects"`
+	Largest  []sessionDiskUsage        `json:"largest"`
+}
+
+func runSeanceStats(cmd *cobra.Command, args []string) error {
+	report, err := claude.DiskUsage()
+	if err != nil {
+		if !errors.Is(err, claude.ErrIncomplete) {
+			return fmt.Errorf("measuring session data: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "%s %v; sizes are partial\n", style.WarningPrefix, err)
+	}
+
+	out := seanceStatsOutput{
+		Bytes:    report.Bytes,
+		Rigs:     diskUsageByRig(report.Sessions),
+		Projects: report.Projects,
+		Largest:  largestSessions(report.Sessions, seanceStatsTop),
+	}
+	if seanceStatsTop > 0 && len(out.Projects) > seanceStatsTop {
+		out.Projects = out.Projects[:seanceStatsTop]
+	}
+
+	if seanceStatsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	printSeanceStats(out, len(report.Projects), len(report.Sessions))
+	return nil
+}
+
+// diskUsageByRig totals session sizes by rig, largest first. Sessions
+// without a rig are grouped under "-".
+func diskUsageByRig(sessions []claude.SessionInfo) []rigDiskUsage {
+	byRig := make(map[string]*rigDiskUsage)
+	for _, s := range sessions {
+		rig := s.Rig
+		if rig == "" {
+			rig = "-"
+		}
+		r := byRig[rig]
+		if r == nil {
+			r = &rigDiskUsage{Rig: rig}
+			byRig[rig] = r
+		}
+		r.Sessions++
+		r.Bytes += s.DiskSize
+	}
+	rigs := make([]rigDiskUsage, 0, len(byRig))
+	for _, r := range byRig {
+		rigs = append(rigs, *r)
+	}
+	sort.Slice(rigs, func(i, j int) bool {
+		if rigs[i].Bytes != rigs[j].Bytes {
+			return rigs[i].Bytes > rigs[j].Bytes
+		}
+		return rigs[i].Rig < rigs[j].Rig
+	})
+	return rigs
+}
+
+// largestSessions returns the n sessions taking up the most space.
+func largestSessions(sessions []claude.SessionInfo, n int) []sessionDiskUsage {
+	sorted := append([]claude.SessionInfo(nil), sessions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].DiskSize > sorted[j].DiskSize })
+	if n > 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	largest := make([]sessionDiskUsage, 0, len(sorted))
+	for _, s := range sorted {
+		largest = append(largest, sessionDiskUsage{SessionID: s.ID, Role: s.Role, ProjectPath: s.ProjectPath, Bytes: s.DiskSize})
+	}
+	return largest
+}
+
+func printSeanceStats(out seanceStatsOutput, projects, sessions int) {
+	fmt.Printf("%s %s in %d session(s) across %d project(s)\n",
+		style.Bold.Render("Session data:"), formatBytes(out.Bytes), sessions, projects)
+
+	fmt.Printf("\n%s\n", style.Bold.Render("By rig"))
+	for _, r := range out.Rigs {
+		fmt.Printf("  %-24s  %10s  %s\n", compactAddress(r.Rig), formatBytes(r.Bytes), style.Dim.Render(fmt.Sprintf("%d session(s)", r.Sessions)))
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Largest projects"))
+	for _, p := range out.Projects {
+		name := p.ProjectPath
+		if name == "" {
+			name = p.Project
+		}
+		detail := fmt.Sprintf("%d session(s)", p.Sessions)
+		if len(p.Rigs) > 0 {
+			detail += ", rig " + strings.Join(p.Rigs, ", ")
+		}
+		fmt.Printf("  %10s  %s  %s\n", formatBytes(p.Bytes), name, style.Dim.Render(detail))
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Largest sessions"))
+	for _, s := range out.Largest {
+		who := s.Role
+		if who == "" {
+			who = s.ProjectPath
+		}
+		fmt.Printf("  %10s  %s  %s\n", formatBytes(s.Bytes), shortSessionID(s.SessionID), style.Dim.Render(who))
+	}
+
+	fmt.Printf("\n%s\n", style.Dim.Render("Reclaim space with: gt seance archive --older-than 30d"))
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+func TestDiskUsageByRig(t *testing.T) {
+	sessions := []claude.SessionInfo{
+		{ID: "a", Rig: "gastown", DiskSize: 100},
+		{ID: "b", Rig: "beads", DiskSize: 500},
+		{ID: "c", Rig: "gastown", DiskSize: 300},
+		{ID: "d", DiskSize: 50},
+	}
+
+	rigs := diskUsageByRig(sessions)
+	want := []rigDiskUsage{{"beads", 1, 500}, {"gastown", 2, 400}, {"-", 1, 50}}
+	if len(rigs) != len(want) {
+		t.Fatalf("rigs = %+v", rigs)
+	}
+	for i := range want {
+		if rigs[i] != want[i] {
+			t.Errorf("rigs[%d] = %+v, want %+v", i, rigs[i], want[i])
+		}
+	}
+
+	largest := largestSessions(sessions, 2)
+	if len(largest) != 2 || largest[0].SessionID != "b" || largest[1].SessionID != "c" {
+		t.Errorf("largestSessions = %+v", largest)
+	}
+}
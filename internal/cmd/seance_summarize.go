@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceSummarizeSince  string
+	seanceSummarizeRig    string
+	seanceSummarizeLimit  int
+	seanceSummarizeTurns  int
+	seanceSummarizeDryRun bool
+)
+
+var seanceSummarizeCmd = &cobra.Command{
+	Use:   "summarize [<session-id>...]",
+	Short: "Generate summaries for sessions that lack a summary line",
+	Long: `Generate one-line summaries for sessions whose transcript has no summary
+line, which is common. Listings and searches then show what the session
+was about instead of a bare ID.
+
+Each summary is written by a headless Claude pass (claude --print) over
+the session's first few turns, so it costs a model call. Summaries are
+kept with the session annotations and generated once, and survive an
+index rebuild; later discoveries fill them in automatically (with
+"summary_generated" set in JSON output).
+
+Without session IDs, summarizes the Gas Town sessions started since
+--since that have no summary, newest first, up to --limit.
+
+Examples:
+  gt seance summarize abc123
+  gt seance summarize --since 2d --rig gastown
+  gt seance summarize --limit 5 --dry-run   # Show the prompts only`,
+	RunE: runSeanceSummarize,
+}
+
+func init() {
+	seanceSummarizeCmd.Flags().StringVar(&seanceSummarizeSince, "since", "7d", "Only sessions started at or after this time")
+	seanceSummarizeCmd.Flags().StringVar(&seanceSummarizeRig, "rig", "", "Only sessions in this rig")
+	seanceSummarizeCmd.Flags().IntVarP(&seanceSummarizeLimit, "limit", "n", 20, "Most sessions to summarize (0 = no limit)")
+	seanceSummarizeCmd.Flags().IntVar(&seanceSummarizeTurns, "turns", 6, "Opening turns to summarize from")
+	seanceSummarizeCmd.Flags().BoolVar(&seanceSummarizeDryRun, "dry-run", false, "Print the prompts instead of calling Claude")
+
+	seanceCmd.AddCommand(seanceSummarizeCmd)
+}
+
+func runSeanceSummarize(cmd *cobra.Command, args []string) error {
+	sessions, err := seanceSummarizeTargets(args)
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		fmt.Println("No sessions need a summary.")
+		return nil
+	}
+
+	opts := claude.SummarizeOptions{Turns: seanceSummarizeTurns, Summarize: runClaudePrint}
+	if seanceSummarizeDryRun {
+		for _, s := range sessions {
+			prompt, err := claude.SummaryPrompt(s, opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s %s: %v\n", style.WarningPrefix, s.ShortID(), err)
+				continue
+			}
+			fmt.Printf("── %s ──\n%s\n", s.ShortID(), prompt)
+		}
+		return nil
+	}
+
+	failed := 0
+	for _, s := range sessions {
+		summary, err := claude.GenerateSummary(s, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", style.WarningPrefix, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  %-12s  %s\n", s.ShortID(), summary)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d session(s) could not be summarized", failed, len(sessions))
+	}
+	fmt.Printf("\n%s Summarized %d session(s)\n", style.SuccessPrefix, len(sessions))
+	return nil
+}
+
+// seanceSummarizeTargets returns the sessions named by args, or the recent
+// Gas Town sessions without a summary when there are none.
+func seanceSummarizeTargets(args []string) ([]claude.SessionInfo, error) {
+	if len(args) > 0 {
+		var out []claude.SessionInfo
+		for _, id := range args {
+			s, err := claude.FindSession(id)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, *s)
+		}
+		return out, nil
+	}
+
+	since, _, err := parseTimeRange(seanceSummarizeSince, "", time.Now())
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{
+		GasTownOnly: true,
+		Rig:         seanceSummarizeRig,
+		Since:       since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("discovering sessions: %w", err)
+	}
+	var out []claude.SessionInfo
+	for _, s := range sessions {
+		if s.Summary != "" {
+			continue
+		}
+		out = append(out, s)
+		if seanceSummarizeLimit > 0 && len(out) == seanceSummarizeLimit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// runClaudePrint sends prompt to a headless Claude pass and returns its
+// reply.
+func runClaudePrint(prompt string) (string, error) {
+	claudeCmd := exec.Command("claude", "--print")
+	claudeCmd.Stdin = strings.NewReader(prompt)
+	var out bytes.Buffer
+	claudeCmd.Stdout = &out
+	claudeCmd.Stderr = os.Stderr
+	if err := claudeCmd.Run(); err != nil {
+		return "", fmt.Errorf("running claude: %w", err)
+	}
+	return out.String(), nil
+}
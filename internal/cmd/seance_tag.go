@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceTagRemove bool
+	seanceNoteClear bool
+)
+
+var seanceTagCmd = &cobra.Command{
+	Use:   "tag <session-id> [tag...]",
+	Short: "Tag a session by hand",
+	Long: `Add tags to a session, alongside those the town's session_tags rules
+apply, so it can be found again with gt find --tag or an export filter.
+With no tags, show the session's tags.
+
+Tags use letters, digits, '-', '_', '.', and ':'. They are stored in the
+state directory, so they are kept when the session index is rebuilt or
+cleared. Only tags added by hand can be removed; rule tags follow the
+rules. gt seance shows tags under each session and lists only those with
+one given --tag.
+
+Examples:
+  gt seance tag abc123 good-handoff         # Mark a session worth resuming
+  gt seance tag abc123 do-not-resume        # ...or one that isn't
+  gt seance tag abc123 incident:42 review   # Add tags
+  gt seance tag abc123                      # Show its tags
+  gt seance tag abc123 --remove review`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case len(args) == 0:
+			return fmt.Errorf("expected <session-id> [tag...]")
+		case seanceTagRemove && len(args) < 2:
+			return fmt.Errorf("--remove takes the tags to remove")
+		}
+		return nil
+	},
+	RunE: runSeanceTag,
+}
+
+var seanceNoteCmd = &cobra.Command{
+	Use:   "note <session-id> [text...]",
+	Short: "Leave a note on a session",
+	Long: `Leave a free-text note on a session, e.g. why it is a good place to
+resume from or what it left unfinished. gt seance shows the note under the
+session; setting a note replaces the earlier one. With no text, show it.
+
+Like hand-added tags, notes are stored in the state directory, so they are
+kept when the session index is rebuilt or cleared.
+
+Examples:
+  gt seance note abc123 "clean handoff; resume from the migration step"
+  gt seance note abc123            # Show its note
+  gt seance note abc123 --clear`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case len(args) == 0:
+			return fmt.Errorf("expected <session-id> [text...]")
+		case seanceNoteClear && len(args) > 1:
+			return fmt.Errorf("--clear takes no text")
+		}
+		return nil
+	},
+	RunE: runSeanceNote,
+}
+
+func init() {
+	seanceTagCmd.Flags().BoolVarP(&seanceTagRemove, "remove", "d", false, "Remove the given tags")
+	seanceNoteCmd.Flags().BoolVar(&seanceNoteClear, "clear", false, "Remove the session's note")
+
+	seanceCmd.AddCommand(seanceTagCmd)
+	seanceCmd.AddCommand(seanceNoteCmd)
+}
+
+func runSeanceTag(cmd *cobra.Command, args []string) error {
+	session, err := claude.FindSession(args[0])
+	if err != nil {
+		return err
+	}
+	for _, tag := range args[1:] {
+		if seanceTagRemove {
+			err = claude.UntagSession(session.ID, tag)
+		} else {
+			err = claude.TagSession(session.ID, tag)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	tags := claude.TagsOf(session.ID)
+	switch {
+	case len(args) > 1 && seanceTagRemove:
+		fmt.Printf("%s Removed %s from %s\n", style.SuccessPrefix, strings.Join(args[1:], ", "), session.ShortID())
+	case len(args) > 1:
+		fmt.Printf("%s Tagged %s\n", style.SuccessPrefix, session.ShortID())
+	}
+	if len(tags) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("no tags added by hand"))
+		return nil
+	}
+	fmt.Printf("  %s\n", strings.Join(tags, " "))
+	return nil
+}
+
+func runSeanceNote(cmd *cobra.Command, args []string) error {
+	session, err := claude.FindSession(args[0])
+	if err != nil {
+		return err
+	}
+	text := strings.Join(args[1:], " ")
+	switch {
+	case seanceNoteClear:
+		if err := claude.SetSessionNote(session.ID, ""); err != nil {
+			return err
+		}
+		fmt.Printf("%s Cleared the note on %s\n", style.SuccessPrefix, session.ShortID())
+		return nil
+	case strings.TrimSpace(text) != "":
+		if err := claude.SetSessionNote(session.ID, text); err != nil {
+			return err
+		}
+		fmt.Printf("%s Noted %s\n", style.SuccessPrefix, session.ShortID())
+	}
+
+	if note := claude.NoteOf(session.ID); note != "" {
+		fmt.Printf("  %s\n", note)
+	} else {
+		fmt.Printf("  %s\n", style.Dim.Render("no note"))
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	seanceTailLines  int
+	seanceTailFollow bool
+	seanceTailFull   bool
+)
+
+// seanceTailTextLimit truncates long turns unless --full is given.
+const seanceTailTextLimit = 400
+
+var seanceTailCmd = &cobra.Command{
+	Use:   "tail <session-id>",
+	Short: "Show the last turns of a session, optionally following it",
+	Long: `Pretty-print the last N turns of a session transcript.
+
+With -f, keeps watching the transcript and prints new turns as the agent
+works - a lightweight way to peek at one agent without the dashboard.
+
+Examples:
+  gt seance tail abc123
+  gt seance tail abc123 -n 5 -f`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSeanceTail,
+}
+
+func init() {
+	seanceTailCmd.Flags().IntVarP(&seanceTailLines, "lines", "n", 10, "Number of turns to show")
+	seanceTailCmd.Flags().BoolVarP(&seanceTailFollow, "follow", "f", false, "Follow the transcript for new turns")
+	seanceTailCmd.Flags().BoolVar(&seanceTailFull, "full", false, "Don't truncate long turns")
+
+	seanceCmd.AddCommand(seanceTailCmd)
+}
+
+func runSeanceTail(cmd *cobra.Command, args []string) error {
+	session, err := claude.FindSession(args[0])
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(session.Path)
+	if err != nil {
+		return fmt.Errorf("reading transcript: %w", err)
+	}
+
+	// Only consume complete lines so following resumes at the right place
+	offset := bytes.LastIndexByte(data, '\n') + 1
+	var turns []claude.Turn
+	for _, line := range bytes.Split(data[:offset], []byte("\n")) {
+		if turn, ok := claude.ParseTurn(line); ok {
+			turns = append(turns, turn)
+		}
+	}
+	if seanceTailLines > 0 && len(turns) > seanceTailLines {
+		turns = turns[len(turns)-seanceTailLines:]
+	}
+
+	label := session.Role
+	if label == "" {
+		label = session.ProjectPath
+	}
+	fmt.Printf("%s %s  %s\n\n", style.Bold.Render("🔮"), session.ShortID(), style.Dim.Render(label))
+	for _, t := range turns {
+		printSeanceTurn(t)
+	}
+
+	if !seanceTailFollow {
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Dim.Render("Following (Ctrl+C to stop)..."))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return claude.FollowTurns(ctx, session.Path, int64(offset), 500*time.Millisecond, printSeanceTurn)
+}
+
+// printSeanceTurn prints one turn with a timestamp and role marker.
+func printSeanceTurn(t claude.Turn) {
+	printSeanceTurnIndented(t, "", seanceTailFull)
+}
+
+// printSeanceTurnIndented prints a turn with every line prefixed by indent,
+// truncating long text unless full is set.
+func printSeanceTurnIndented(t claude.Turn, indent string, full bool) {
+	ts := ""
+	if !t.Timestamp.IsZero() {
+		ts = t.Timestamp.Local().Format("15:04:05")
+	}
+
+	marker := style.Bold.Render("▶ user")
+	if t.Role == "assistant" {
+		marker = style.Bold.Render("◀ agent")
+	}
+	fmt.Printf("%s%s %s\n", indent, style.Dim.Render(ts), marker)
+
+	if t.Text != "" {
+		text := t.Text
+		if !full && len(text) > seanceTailTextLimit {
+			text = text[:seanceTailTextLimit] + "…"
+		}
+		for _, line := range strings.Split(text, "\n") {
+			fmt.Printf("%s  %s\n", indent, line)
+		}
+	}
+	if len(t.Tools) > 0 {
+		fmt.Printf("%s  %s\n", indent, style.Dim.Render("⚙ "+strings.Join(t.Tools, ", ")))
+	}
+	fmt.Println(strings.TrimRight(indent, " "))
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/takeover"
+)
+
+func TestSeanceScopeRig(t *testing.T) {
+	townRoot := setupTestTownForCrewList(t, map[string][]string{"rig-a": {"alice"}})
+	if err := os.WriteFile(filepath.Join(townRoot, "rig-a", "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Looks like a rig on disk but isn't registered
+	if err := os.MkdirAll(filepath.Join(townRoot, "stray"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "stray", "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{filepath.Join(townRoot, "rig-a", "crew", "alice"), "rig-a"},
+		{filepath.Join(townRoot, "stray"), ""},
+		{townRoot, ""},
+	}
+	for _, tt := range tests {
+		if err := os.Chdir(tt.dir); err != nil {
+			t.Fatal(err)
+		}
+		if got := seanceScopeRig(townRoot); got != tt.want {
+			t.Errorf("seanceScopeRig from %s = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestCollapseSessionChains(t *testing.T) {
+	ev := func(ts, actor, id string, extra map[string]interface{}) sessionEvent {
+		p := map[string]interface{}{"session_id": id}
+		for k, v := range extra {
+			p[k] = v
+		}
+		return sessionEvent{Timestamp: ts, Type: "session_start", Actor: actor, Payload: p}
+	}
+	// Most recent first, as discoverSessions returns them.
+	sessions := []sessionEvent{
+		ev("2025-01-01T10:05:00Z", "gastown/crew/joe", "j3", map[string]interface{}{"handoff": true}),
+		ev("2025-01-01T10:04:00Z", "gastown/witness", "w2", map[string]interface{}{"source": "startup"}),
+		ev("2025-01-01T10:03:00Z", "gastown/crew/joe", "j2", map[string]interface{}{"source": "compact"}),
+		ev("2025-01-01T10:02:00Z", "gastown/crew/joe", "j1", map[string]interface{}{"source": "resume"}),
+		ev("2025-01-01T10:01:00Z", "gastown/witness", "w1", nil),
+		ev("2025-01-01T10:00:00Z", "gastown/crew/joe", "j1", nil),
+	}
+
+	rows := collapseSessionChains(sessions)
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3: %+v", len(rows), rows)
+	}
+
+	if id := getPayloadString(rows[0].Payload, "session_id"); id != "j3" {
+		t.Errorf("row 0 = %s, want j3", id)
+	}
+	if got := strings.Join(rows[0].Chain, ","); got != "j1,j2,j3" {
+		t.Errorf("joe chain = %s, want j1,j2,j3", got)
+	}
+	if rows[0].chainBadge() != "×3" {
+		t.Errorf("badge = %q, want ×3", rows[0].chainBadge())
+	}
+
+	// A fresh startup breaks the chain.
+	for i, want := range []string{"w2", "w1"} {
+		row := rows[i+1]
+		if id := getPayloadString(row.Payload, "session_id"); id != want || row.chainBadge() != "" {
+			t.Errorf("row %d = %s %q, want %s alone", i+1, id, row.chainBadge(), want)
+		}
+	}
+}
+
+func TestMarkTakeovers(t *testing.T) {
+	rows := []seanceRow{
+		{sessionEvent: sessionEvent{Payload: map[string]interface{}{"session_id": "j3"}}, Chain: []string{"j1", "j2", "j3"}},
+		{sessionEvent: sessionEvent{Payload: map[string]interface{}{"session_id": "w1"}}},
+	}
+	markTakeovers(rows, map[string][]takeover.Record{
+		"j2": {{SessionID: "j2", Direction: takeover.ToHuman, By: "overseer"}},
+	})
+	if rows[0].Takeover != "human (overseer)" || rows[0].badge() != "×3 ⇄" {
+		t.Errorf("chain row = %q, badge %q", rows[0].Takeover, rows[0].badge())
+	}
+	if rows[1].Takeover != "" || rows[1].badge() != "" {
+		t.Errorf("plain row = %q, badge %q", rows[1].Takeover, rows[1].badge())
+	}
+}
+
+func TestMarkSessionInfo(t *testing.T) {
+	rows := []seanceRow{
+		{sessionEvent: sessionEvent{Payload: map[string]interface{}{"session_id": "j3"}}, Chain: []string{"j1", "j2", "j3"}},
+		{sessionEvent: sessionEvent{Payload: map[string]interface{}{"session_id": "w1"}}},
+	}
+	markSessionInfo(rows, []claude.SessionInfo{
+		{ID: "j3", ContextTokens: 190_000, CompactionCount: 5, State: claude.StateCrashed},
+		{ID: "w1", ContextTokens: 20_000, State: claude.StateIdle},
+	}, claude.Limits{})
+	if got := rows[0].badge(); got != "×3 ✗◕↻" {
+		t.Errorf("risky row badge = %q", got)
+	}
+	if got := rows[1].badge(); got != "" {
+		t.Errorf("healthy row badge = %q", got)
+	}
+	if legend := seanceWarningLegend(rows); !strings.Contains(legend, "◕ context nearly full") ||
+		!strings.Contains(legend, "↻ compacted often") || !strings.Contains(legend, "✗ crashed mid-task") {
+		t.Errorf("legend = %q", legend)
+	}
+}
+
+func TestSortSeanceRows(t *testing.T) {
+	row := func(id string) seanceRow {
+		return seanceRow{sessionEvent: sessionEvent{Payload: map[string]interface{}{"session_id": id}}}
+	}
+	rows := []seanceRow{row("new"), row("gone"), row("mid"), row("old")}
+	sortSeanceRows(rows, []claude.SessionInfo{{ID: "old"}, {ID: "new"}, {ID: "mid"}})
+	var ids []string
+	for _, r := range rows {
+		ids = append(ids, getPayloadString(r.Payload, "session_id"))
+	}
+	if got := strings.Join(ids, " "); got != "old new mid gone" {
+		t.Errorf("sorted rows = %s, want old new mid gone", got)
+	}
+}
+
+func TestMergeResumeChains(t *testing.T) {
+	row := func(id, ts string, chain ...string) seanceRow {
+		return seanceRow{sessionEvent: sessionEvent{Timestamp: ts, Payload: map[string]interface{}{"session_id": id}}, Chain: chain}
+	}
+	// r2 was resumed from r1 outside gt, so its events don't link them
+	rows := []seanceRow{
+		row("r2", "2025-01-01T02:00:00Z"),
+		row("x1", "2025-01-01T01:30:00Z"),
+		row("r1", "2025-01-01T01:00:00Z", "h0", "r1"),
+	}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	transcripts := []claude.SessionInfo{{
+		ID:         "r2",
+		StartTime:  start.Add(2 * time.Hour),
+		EndTime:    start.Add(3 * time.Hour),
+		Chain:      []string{"r1", "r2"},
+		ChainStart: start.Add(time.Hour),
+	}, {
+		ID: "h0", StartTime: start, EndTime: start.Add(30 * time.Minute),
+	}}
+
+	rows = mergeResumeChains(rows, transcripts)
+	if len(rows) != 2 {
+		t.Fatalf("merged rows = %d, want 2", len(rows))
+	}
+	if got := strings.Join(rows[0].Chain, " "); got != "h0 r1 r2" || rows[0].badge() != "×3" {
+		t.Errorf("merged chain = %s, badge %q", got, rows[0].badge())
+	}
+	markSessionInfo(rows, transcripts, claude.Limits{})
+	if rows[0].Span != 3*time.Hour {
+		t.Errorf("span = %s, want 3h (from h0's start)", rows[0].Span)
+	}
+	if rows[1].Span != 0 {
+		t.Errorf("row without a transcript has span %s", rows[1].Span)
+	}
+}
+
+func TestFilterRowsByBranch(t *testing.T) {
+	row := func(id string, chain ...string) seanceRow {
+		return seanceRow{sessionEvent: sessionEvent{Payload: map[string]interface{}{"session_id": id}}, Chain: chain}
+	}
+	rows := []seanceRow{row("a2", "a1", "a2"), row("b1"), row("c1")}
+	transcripts := []claude.SessionInfo{
+		{ID: "a1", GitBranches: []string{"main", "feat/login"}},
+		{ID: "a2", GitBranches: []string{"main"}},
+		{ID: "b1", GitBranches: []string{"polecat/toast"}},
+		{ID: "c1", GitBranches: []string{"main"}},
+	}
+
+	var ids []string
+	for _, r := range filterRowsByBranch(rows, transcripts, "feat/login") {
+		ids = append(ids, getPayloadString(r.Payload, "session_id"))
+	}
+	if got := strings.Join(ids, " "); got != "a2" {
+		t.Errorf("feat/login rows = %q, want a2 (its chain worked on the branch)", got)
+	}
+	if got := filterRowsByBranch(rows, transcripts, "polecat/*"); len(got) != 1 || getPayloadString(got[0].Payload, "session_id") != "b1" {
+		t.Errorf("polecat/* rows = %+v, want b1", got)
+	}
+}
+
+func TestFilterRowsByOutcome(t *testing.T) {
+	row := func(id string, chain ...string) seanceRow {
+		return seanceRow{sessionEvent: sessionEvent{Payload: map[string]interface{}{"session_id": id}}, Chain: chain}
+	}
+	rows := []seanceRow{row("a2", "a1", "a2"), row("b1"), row("c1")}
+	transcripts := []claude.SessionInfo{
+		{ID: "a1", Outcome: claude.OutcomeBlocked},
+		{ID: "a2", Outcome: claude.OutcomeCompleted},
+		{ID: "b1", Outcome: claude.OutcomeBlocked},
+		{ID: "c1"},
+	}
+
+	got := filterRowsByOutcome(rows, transcripts, claude.OutcomeBlocked)
+	if len(got) != 1 || getPayloadString(got[0].Payload, "session_id") != "b1" {
+		t.Errorf("blocked rows = %+v, want b1 (a2 continued a1's blocked work and completed it)", got)
+	}
+}
+
+func TestFilterRowsByTagAndLabels(t *testing.T) {
+	row := func(id string) seanceRow {
+		return seanceRow{sessionEvent: sessionEvent{Payload: map[string]interface{}{"session_id": id}}}
+	}
+	rows := []seanceRow{row("a1"), row("b1")}
+	transcripts := []claude.SessionInfo{
+		{ID: "a1", Tags: []string{"good-handoff", "review"}, Note: "resume from step 3"},
+		{ID: "b1", Tags: []string{"do-not-resume"}},
+	}
+
+	kept := filterRowsByTag(rows, transcripts, "good-handoff")
+	if len(kept) != 1 || getPayloadString(kept[0].Payload, "session_id") != "a1" {
+		t.Fatalf("good-handoff rows = %+v, want a1", kept)
+	}
+
+	markSessionInfo(rows, transcripts, claude.Limits{})
+	if got := rows[0].labels(); got != "#good-handoff #review  resume from step 3" {
+		t.Errorf("a1 labels = %q", got)
+	}
+	if got := rows[1].labels(); got != "#do-not-resume" {
+		t.Errorf("b1 labels = %q", got)
+	}
+	if got := (seanceRow{Note: "just a note"}).labels(); got != "just a note" {
+		t.Errorf("note-only labels = %q", got)
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// runSeanceWatch prints new sessions and their turns as they are written,
+// until interrupted. With --json it streams the raw session events.
+func runSeanceWatch() error {
+	rig := seanceRig
+	if rig == "" && !seanceGlobal {
+		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+			rig = seanceScopeRig(townRoot)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	events, err := claude.WatchSessions(ctx, claude.SessionFilter{GasTownOnly: true, Role: seanceRole, Rig: rig})
+	if err != nil {
+		return fmt.Errorf("watching sessions: %w", err)
+	}
+
+	if seanceJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for ev := range events {
+			if err := enc.Encode(ev); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	fmt.Printf("\n%s\n\n", style.Dim.Render("Watching for session activity (Ctrl+C to stop)..."))
+	// Bytes consumed per transcript; partial lines wait for the next event
+	consumed := make(map[string]int64)
+	for ev := range events {
+		s := ev.Session
+		from, ok := consumed[s.Path]
+		if !ok || ev.Offset < from {
+			from = ev.Offset
+		}
+		if ev.Type == claude.SessionCreated {
+			fmt.Printf("%s %s %s  %s\n\n", style.Bold.Render("+"), style.Bold.Render(s.ShortID()), s.Role, style.Dim.Render(s.Topic))
+		}
+
+		turns, next, err := readTurnsFrom(s.Path, from)
+		if err != nil {
+			continue
+		}
+		consumed[s.Path] = next
+		for _, t := range turns {
+			fmt.Printf("%s %s\n", style.Bold.Render(s.ShortID()), style.Dim.Render(s.Role))
+			printSeanceTurnIndented(t, "  ", false)
+		}
+	}
+	return nil
+}
+
+// readTurnsFrom parses the complete lines of a transcript from offset on.
+// It returns the turns and the offset after the last complete line.
+func readTurnsFrom(path string, offset int64) ([]claude.Turn, int64, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil, offset, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, offset, err
+	}
+
+	end := bytes.LastIndexByte(data, '\n') + 1
+	var turns []claude.Turn
+	for _, line := range bytes.Split(data[:end], []byte("\n")) {
+		if turn, ok := claude.ParseTurn(line); ok {
+			turns = append(turns, turn)
+		}
+	}
+	return turns, offset + int64(end), nil
+}
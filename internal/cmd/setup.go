@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	setupYes    bool
+	setupPath   string
+	setupDaemon bool
+)
+
+var setupCmd = &cobra.Command{
+	Use:     "setup",
+	GroupID: GroupWorkspace,
+	Short:   "Interactive first-run setup wizard",
+	Long: `Walk through first-run setup of Gas Town.
+
+The wizard:
+  1. Detects Claude Code and reports its version
+  2. Creates the HQ (via gt install) if you're not in one
+  3. Installs Claude Code hooks for the mayor and deacon
+  4. Proposes rigs for git repos you've recently used Claude Code in
+  5. Optionally installs the daemon as a user service (systemd/launchd)
+
+Every step asks before changing anything. Use --yes to accept all
+proposals (the daemon service still requires --daemon).
+
+Examples:
+  gt setup                      # Interactive
+  gt setup --path ~/gt          # Create the HQ at ~/gt if needed
+  gt setup --yes --daemon       # Unattended, including the service`,
+	RunE: runSetup,
+}
+
+func init() {
+	setupCmd.Flags().BoolVarP(&setupYes, "yes", "y", false, "Accept all proposals without prompting")
+	setupCmd.Flags().StringVar(&setupPath, "path", "~/gt", "Where to create the HQ if not already in one")
+	setupCmd.Flags().BoolVar(&setupDaemon, "daemon", false, "Install the daemon user service (with --yes)")
+	rootCmd.AddCommand(setupCmd)
+}
+
+// setupRigCandidate is a git repo seen in recent Claude Code sessions.
+type setupRigCandidate struct {
+	Name   string
+	Path   string
+	GitURL string
+}
+
+func runSetup(cmd *cobra.Command, args []string) error {
+	fmt.Printf("%s Gas Town setup\n\n", style.Bold.Render("🏭"))
+
+	// One reader for every prompt: piped answers buffered by one read
+	// must still be there for the next
+	in := bufio.NewReader(os.Stdin)
+
+	// Step 1: Claude Code
+	fmt.Printf("%s\n", style.Bold.Render("1. Claude Code"))
+	if version, err := detectClaudeVersion(); err != nil {
+		fmt.Printf("   %s Claude Code not found: %v\n", style.WarningPrefix, err)
+		fmt.Printf("   %s\n", style.Dim.Render("Install it from https://docs.anthropic.com/en/docs/claude-code"))
+	} else {
+		fmt.Printf("   %s %s\n", style.SuccessPrefix, version)
+	}
+	fmt.Println()
+
+	// Step 2: HQ
+	fmt.Printf("%s\n", style.Bold.Render("2. Town HQ"))
+	townRoot, _ := workspace.FindFromCwd()
+	if townRoot != "" {
+		fmt.Printf("   %s Using HQ at %s\n", style.SuccessPrefix, townRoot)
+	} else {
+		path := expandSetupPath(setupPath)
+		if !setupConfirm(in, fmt.Sprintf("   Create HQ at %s?", path)) {
+			fmt.Println("   Skipped. Run 'gt install <path>' when ready.")
+			return nil
+		}
+		if err := runInstall(cmd, []string{path}); err != nil {
+			return fmt.Errorf("creating HQ: %w", err)
+		}
+		if err := os.Chdir(path); err != nil {
+			return fmt.Errorf("entering HQ: %w", err)
+		}
+		townRoot = path
+	}
+	fmt.Println()
+
+	// Step 3: hooks
+	fmt.Printf("%s\n", style.Bold.Render("3. Claude Code hooks"))
+	setupHooks(townRoot, in)
+	fmt.Println()
+
+	// Step 4: rigs
+	fmt.Printf("%s\n", style.Bold.Render("4. Rigs"))
+	setupRigs(townRoot, in)
+	fmt.Println()
+
+	// Step 5: daemon service
+	fmt.Printf("%s\n", style.Bold.Render("5. Daemon service"))
+	setupDaemonService(townRoot, in)
+	fmt.Println()
+
+	fmt.Printf("%s Setup complete. Next: %s\n", style.SuccessPrefix, style.Dim.Render("gt mayor attach"))
+	return nil
+}
+
+// setupConfirm asks a yes/no question, honoring --yes. Answers are read
+// from in, which runSetup shares across every prompt.
+func setupConfirm(in *bufio.Reader, question string) bool {
+	if setupYes {
+		fmt.Printf("%s [y/N]: y\n", question)
+		return true
+	}
+	fmt.Printf("%s [y/N]: ", question)
+	answer, _ := in.ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// detectClaudeVersion returns the installed Claude Code version string.
+func detectClaudeVersion() (string, error) {
+	path, err := exec.LookPath("claude")
+	if err != nil {
+		return "", fmt.Errorf("'claude' not in PATH")
+	}
+	out, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("running claude --version: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func expandSetupPath(path string) string {
+	if strings.HasPrefix(path, "~") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// setupHooks installs Claude Code settings for the town-level roles.
+func setupHooks(townRoot string, in *bufio.Reader) {
+	var missing []string
+	for _, role := range []string{"mayor", "deacon"} {
+		settingsPath := filepath.Join(townRoot, role, ".claude", "settings.json")
+		if _, err := os.Stat(settingsPath); os.IsNotExist(err) {
+			missing = append(missing, role)
+		}
+	}
+
+	if len(missing) == 0 {
+		fmt.Printf("   %s Hooks installed for mayor and deacon\n", style.SuccessPrefix)
+		return
+	}
+
+	if !setupConfirm(in, fmt.Sprintf("   Install hooks for %s?", strings.Join(missing, ", "))) {
+		fmt.Println("   Skipped.")
+		return
+	}
+	for _, role := range missing {
+		if err := claude.EnsureSettingsForRole(filepath.Join(townRoot, role), role); err != nil {
+			fmt.Printf("   %s %s: %v\n", style.WarningPrefix, role, err)
+			continue
+		}
+		fmt.Printf("   %s Installed %s/.claude/settings.json\n", style.SuccessPrefix, role)
+	}
+}
+
+// setupRigs proposes rigs for git repos found in recent Claude Code sessions.
+func setupRigs(townRoot string, in *bufio.Reader) {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{Limit: 200})
+	if err != nil {
+		fmt.Printf("   %s Could not scan Claude Code sessions: %v\n", style.WarningPrefix, err)
+		return
+	}
+
+	candidates := findSetupRigCandidates(townRoot, sessions, rigsConfig)
+	if len(candidates) == 0 {
+		fmt.Printf("   %s No unregistered repos found in recent sessions\n", style.SuccessPrefix)
+		fmt.Printf("   %s\n", style.Dim.Render("Add rigs with: gt rig add <name> <git-url>"))
+		return
+	}
+
+	gtPath, err := os.Executable()
+	if err != nil {
+		fmt.Printf("   %s Could not find gt executable: %v\n", style.WarningPrefix, err)
+		return
+	}
+
+	for _, c := range candidates {
+		if !setupConfirm(in, fmt.Sprintf("   Register %s as rig %q (%s)?", c.Path, c.Name, c.GitURL)) {
+			continue
+		}
+		addCmd := exec.Command(gtPath, "rig", "add", c.Name, c.GitURL, "--local-repo", c.Path) //nolint:gosec // G204: args are from local git config
+		addCmd.Dir = townRoot
+		addCmd.Stdout = os.Stdout
+		addCmd.Stderr = os.Stderr
+		if err := addCmd.Run(); err != nil {
+			fmt.Printf("   %s Could not add rig %s: %v\n", style.WarningPrefix, c.Name, err)
+		}
+	}
+}
+
+// findSetupRigCandidates returns git repos from session project paths that
+// have an origin remote and aren't already registered as rigs.
+func findSetupRigCandidates(townRoot string, sessions []claude.SessionInfo, rigsConfig *config.RigsConfig) []setupRigCandidate {
+	known := make(map[string]bool)
+	for name, entry := range rigsConfig.Rigs {
+		known[name] = true
+		if entry.GitURL != "" {
+			known[entry.GitURL] = true
+		}
+		if entry.LocalRepo != "" {
+			known[entry.LocalRepo] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	var candidates []setupRigCandidate
+	for _, s := range sessions {
+		path := s.ProjectPath
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		// Sessions inside the town are agents' own worktrees, not new rigs
+		if townRoot != "" && strings.HasPrefix(path, townRoot) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+			continue
+		}
+
+		url, err := git.NewGit(path).RemoteURL("origin")
+		if err != nil || url == "" {
+			continue
+		}
+
+		name := strings.ReplaceAll(filepath.Base(path), "-", "_")
+		if known[name] || known[url] || known[path] {
+			continue
+		}
+		known[url] = true
+
+		candidates = append(candidates, setupRigCandidate{Name: name, Path: path, GitURL: url})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates
+}
+
+// setupDaemonService writes a user service definition that runs the daemon.
+func setupDaemonService(townRoot string, in *bufio.Reader) {
+	if setupYes && !setupDaemon {
+		fmt.Println("   Skipped (use --daemon to install the service).")
+		return
+	}
+
+	gtPath, err := os.Executable()
+	if err != nil {
+		fmt.Printf("   %s Could not find gt executable: %v\n", style.WarningPrefix, err)
+		return
+	}
+
+	servicePath, content, enable := daemonServiceDefinition(runtime.GOOS, gtPath, townRoot)
+	if servicePath == "" {
+		fmt.Printf("   %s No service manager support on %s; use 'gt daemon start'\n", style.WarningPrefix, runtime.GOOS)
+		return
+	}
+
+	if !setupConfirm(in, fmt.Sprintf("   Install daemon service at %s?", servicePath)) {
+		fmt.Println("   Skipped. Start manually with 'gt daemon start'.")
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(servicePath), 0755); err != nil {
+		fmt.Printf("   %s %v\n", style.WarningPrefix, err)
+		return
+	}
+	if err := os.WriteFile(servicePath, []byte(content), 0644); err != nil { //nolint:gosec // G306: service definition is not secret
+		fmt.Printf("   %s %v\n", style.WarningPrefix, err)
+		return
+	}
+	fmt.Printf("   %s Wrote %s\n", style.SuccessPrefix, servicePath)
+	fmt.Printf("   Enable with: %s\n", style.Dim.Render(enable))
+}
+
+// daemonServiceDefinition returns the service file path, contents, and the
+// command that enables it for the given OS. Returns an empty path when the
+// platform has no supported user service manager.
+func daemonServiceDefinition(goos, gtPath, townRoot string) (path, content, enable string) {
+	home, _ := os.UserHomeDir()
+	switch goos {
+	case "linux":
+		path = filepath.Join(home, ".config", "systemd", "user", "gastown-daemon.service")
+		content = fmt.Sprintf(`[Unit]
+Description=Gas Town daemon
+
+[Service]
+ExecStart=%s daemon run
+WorkingDirectory=%s
+Restart=on-failure
+
+[Install]
+WantedBy=default.target
+`, gtPath, townRoot)
+		enable = "systemctl --user enable --now gastown-daemon"
+	case "darwin":
+		path = filepath.Join(home, "Library", "LaunchAgents", "com.gastown.daemon.plist")
+		content = fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+  <key>Label</key><string>com.gastown.daemon</string>
+  <key>ProgramArguments</key>
+  <array><string>%s</string><string>daemon</string><string>run</string></array>
+  <key>WorkingDirectory</key><string>%s</string>
+  <key>RunAtLoad</key><true/>
+  <key>KeepAlive</key><true/>
+</dict>
+</plist>
+`, gtPath, townRoot)
+		enable = "launchctl load " + path
+	}
+	return path, content, enable
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestDaemonServiceDefinition(t *testing.T) {
+	path, content, enable := daemonServiceDefinition("linux", "/usr/bin/gt", "/home/u/gt")
+	if !strings.HasSuffix(path, "gastown-daemon.service") {
+		t.Errorf("linux path = %q", path)
+	}
+	if !strings.Contains(content, "ExecStart=/usr/bin/gt daemon run") || !strings.Contains(content, "WorkingDirectory=/home/u/gt") {
+		t.Errorf("linux content missing ExecStart/WorkingDirectory:\n%s", content)
+	}
+	if !strings.Contains(enable, "systemctl --user") {
+		t.Errorf("linux enable = %q", enable)
+	}
+
+	path, content, _ = daemonServiceDefinition("darwin", "/usr/bin/gt", "/Users/u/gt")
+	if !strings.HasSuffix(path, "com.gastown.daemon.plist") || !strings.Contains(content, "<string>/usr/bin/gt</string>") {
+		t.Errorf("darwin definition = %q\n%s", path, content)
+	}
+
+	if path, _, _ := daemonServiceDefinition("plan9", "/bin/gt", "/gt"); path != "" {
+		t.Errorf("unsupported OS path = %q, want empty", path)
+	}
+}
+
+func TestFindSetupRigCandidatesSkipsTownAndNonRepos(t *testing.T) {
+	townRoot := t.TempDir()
+	sessions := []claude.SessionInfo{
+		{ProjectPath: townRoot + "/gastown/crew/joe"},
+		{ProjectPath: t.TempDir()}, // not a git repo
+		{ProjectPath: ""},
+	}
+	rigs := &config.RigsConfig{Rigs: map[string]config.RigEntry{}}
+	if got := findSetupRigCandidates(townRoot, sessions, rigs); len(got) != 0 {
+		t.Errorf("got %d candidates, want 0: %+v", len(got), got)
+	}
+}
+
+func TestSetupConfirmSharesReader(t *testing.T) {
+	// Piped answers arrive in one read; every prompt must still get its own
+	in := bufio.NewReader(strings.NewReader("y\nno\nYES\n"))
+	var got []bool
+	for i := 0; i < 4; i++ {
+		got = append(got, setupConfirm(in, "ok?"))
+	}
+	want := []bool{true, false, true, false}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("answers = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/site"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/takeover"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// siteHotspots is how many most-edited files the reports page lists.
+const siteHotspots = 25
+
+var (
+	siteOut   string
+	siteSince string
+	siteRig   string
+)
+
+var siteCmd = &cobra.Command{
+	Use:     "site",
+	GroupID: GroupDiag,
+	Short:   "Publish town activity as a static HTML site",
+	RunE:    requireSubcommand,
+}
+
+var siteBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Generate a read-only HTML report of agent activity",
+	Long: `Generate a static HTML mini-site for browsing agent activity without gt.
+
+The site has a searchable session list, a page per session transcript,
+and a reports page with daily session and cost graphs, outcome rates,
+and the most-edited files. Search runs in the browser, so the directory
+works opened straight from disk or published to any static file host.
+
+Transcript text is passed through the "secrets" redaction rules (see
+gt seance redact) before it is written. Review the output before
+publishing it anyway: transcripts can contain anything an agent saw.
+
+Examples:
+  gt site build
+  gt site build --out public/ --since 7d
+  gt site build --rig gastown --out /var/www/gastown`,
+	RunE: runSiteBuild,
+}
+
+func init() {
+	siteBuildCmd.Flags().StringVarP(&siteOut, "out", "o", "public", "Output directory")
+	siteBuildCmd.Flags().StringVar(&siteSince, "since", "30d", "Only include sessions started within this window")
+	siteBuildCmd.Flags().StringVar(&siteRig, "rig", "", "Only include sessions from this rig")
+
+	siteCmd.AddCommand(siteBuildCmd)
+	rootCmd.AddCommand(siteCmd)
+}
+
+func runSiteBuild(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	window, err := parseDuration(siteSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	since := time.Now().Add(-window)
+
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{GasTownOnly: true, Rig: siteRig})
+	if err != nil {
+		return fmt.Errorf("discovering sessions: %w", err)
+	}
+	records, err := outcome.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	takeovers, err := takeover.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	outcomes := make(map[string]outcome.Record, len(records))
+	for _, rec := range records {
+		outcomes[rec.SessionID] = rec
+	}
+
+	townName, _ := workspace.GetTownName(townRoot)
+	if townName == "" {
+		townName = filepath.Base(townRoot)
+	}
+	data := &site.Data{Town: townName, Generated: time.Now(), Since: siteSince}
+	prices := loadTownSettingsQuiet(townRoot).PriceTable()
+
+	var edits []sessionEdits
+	var siteRecords []outcome.Record
+	for _, s := range sessions {
+		if s.StartTime.Before(since) {
+			continue
+		}
+		turns, err := claude.ReadTurns(s.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s skipping %s: %v\n", style.WarningPrefix, s.ShortID(), err)
+			continue
+		}
+		page := site.Session{
+			ID:       s.ID,
+			Role:     s.Role,
+			Topic:    s.Topic,
+			Summary:  s.Summary,
+			Start:    s.StartTime,
+			Outcome:  string(outcomes[s.ID].Status),
+			Takeover: takeover.Describe(takeovers[s.ID]),
+			Turns:    turns,
+		}
+		if usage, err := claude.ReadUsage(s.Path); err == nil {
+			page.End = usage.LastTimestamp
+			page.Tokens = usage.TotalTokens()
+			page.Cost = claude.EstimateCost(*usage, prices)
+		}
+		data.Sessions = append(data.Sessions, page)
+
+		if e, err := claude.ReadFileEdits(s.Path, since); err == nil && len(e) > 0 {
+			edits = append(edits, sessionEdits{Session: s, Edits: e})
+		}
+		if rec, ok := outcomes[s.ID]; ok {
+			siteRecords = append(siteRecords, rec)
+		}
+	}
+
+	data.Outcomes = outcome.Summarize(siteRecords, takeover.Blended(takeovers))
+	for i, h := range rankHotspots(edits, false) {
+		if i == siteHotspots {
+			break
+		}
+		data.Hotspots = append(data.Hotspots, site.Hotspot{Path: h.Path, Edits: h.Edits, Sessions: h.Sessions})
+	}
+
+	n, err := site.Build(siteOut, data)
+	if err != nil {
+		return fmt.Errorf("building site: %w", err)
+	}
+	fmt.Printf("%s Wrote %d files for %d sessions to %s\n", style.SuccessPrefix, n, len(data.Sessions), siteOut)
+	fmt.Printf("  Open %s\n", style.Dim.Render(filepath.Join(siteOut, "index.html")))
+	return nil
+}
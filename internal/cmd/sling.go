@@ -100,6 +100,13 @@ Natural Language Args:
 The --args string is stored in the bead and shown via gt prime. Since the
 executor is an LLM, it interprets these instructions naturally.
 
+Task Specs:
+  gt sling gt-abc gastown --task auth.task.yaml
+
+--task validates a task spec (see 'gt task') and hands it to the executor
+in place of --args, pointing it at the rendered goal, constraints, and
+acceptance checks.
+
 Formula Slinging:
   gt sling mol-release mayor/           # Cook + wisp + attach + nudge
   gt sling towers-of-hanoi --var disks=3
@@ -131,6 +138,8 @@ var (
 	slingOnTarget string   // --on flag: target bead when slinging a formula
 	slingVars     []string // --var flag: formula variables (key=value)
 	slingArgs     string   // --args flag: natural language instructions for executor
+	slingTask     string   // --task flag: task spec handed to the executor via args
+	slingTaskPath string   // absolute path of the --task spec, stored in the bead
 
 	// Flags migrated for polecat spawning (used by sling for work assignment
 	slingNaked    bool   // --naked: no-tmux mode (skip session creation)
@@ -148,6 +157,7 @@ func init() {
 	slingCmd.Flags().StringVar(&slingOnTarget, "on", "", "Apply formula to existing bead (implies wisp scaffolding)")
 	slingCmd.Flags().StringArrayVar(&slingVars, "var", nil, "Formula variable (key=value), can be repeated")
 	slingCmd.Flags().StringVarP(&slingArgs, "args", "a", "", "Natural language instructions for the executor (e.g., 'patch release')")
+	slingCmd.Flags().StringVar(&slingTask, "task", "", "Task spec (task.yaml) describing the work; see 'gt task'")
 
 	// Flags for polecat spawning (when target is a rig)
 	slingCmd.Flags().BoolVar(&slingNaked, "naked", false, "No-tmux mode: assign work but skip session creation (manual start)")
@@ -179,6 +189,19 @@ func runSling(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("--var cannot be used with --on (formula-on-bead mode doesn't support variables)")
 	}
 
+	// A task spec reaches the executor the way --args does
+	if slingTask != "" {
+		if slingArgs != "" {
+			return fmt.Errorf("--task and --args cannot be combined (the task spec becomes the args)")
+		}
+		spec, path, err := loadTaskRef(slingTask)
+		if err != nil {
+			return err
+		}
+		slingArgs = taskArgs(spec, path)
+		slingTaskPath = path
+	}
+
 	// Batch mode detection: multiple beads with rig target
 	// Pattern: gt sling gt-abc gt-def gt-ghi gastown
 	// When len(args) > 2 and last arg is a rig, sling each bead to its own polecat
@@ -230,6 +253,30 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Check if bead is already pinned or being worked (guard against
+	// accidental re-sling) before spawning anything
+	info, err := getBeadInfo(beadID)
+	if err != nil {
+		return fmt.Errorf("checking bead status: %w", err)
+	}
+	if info.Status == "pinned" && !slingForce {
+		assignee := info.Assignee
+		if assignee == "" {
+			assignee = "(unknown)"
+		}
+		return fmt.Errorf("bead %s is already pinned to %s\nUse --force to re-sling", beadID, assignee)
+	}
+	slingTarget := ""
+	if len(args) > 1 {
+		slingTarget = args[1]
+	}
+	if err := checkDuplicateAssignment(townRoot, beadID, info, slingTarget); err != nil {
+		return err
+	}
+	if err := checkSlingFreeze(townRoot, slingTarget); err != nil {
+		return err
+	}
+
 	// Determine target agent (self or specified)
 	var targetAgent string
 	var targetPane string
@@ -331,19 +378,6 @@ func runSling(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Slinging %s to %s...\n", style.Bold.Render("🎯"), beadID, targetAgent)
 	}
 
-	// Check if bead is already pinned (guard against accidental re-sling)
-	info, err := getBeadInfo(beadID)
-	if err != nil {
-		return fmt.Errorf("checking bead status: %w", err)
-	}
-	if info.Status == "pinned" && !slingForce {
-		assignee := info.Assignee
-		if assignee == "" {
-			assignee = "(unknown)"
-		}
-		return fmt.Errorf("bead %s is already pinned to %s\nUse --force to re-sling", beadID, assignee)
-	}
-
 	// Auto-convoy: check if issue is already tracked by a convoy
 	// If not, create one for dashboard visibility (unless --no-convoy is set)
 	if !slingNoConvoy && formulaName == "" {
@@ -480,7 +514,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	// Store args in bead description (no-tmux mode: beads as data plane)
 	if slingArgs != "" {
-		if err := storeArgsInBead(beadID, slingArgs); err != nil {
+		if err := storeArgsInBead(beadID, slingArgs, slingTaskPath); err != nil {
 			// Warn but don't fail - args will still be in the nudge prompt
 			fmt.Printf("%s Could not store args in bead: %v\n", style.Dim.Render("Warning:"), err)
 		} else {
@@ -516,7 +550,8 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 // storeArgsInBead stores args in the bead's description using attached_args field.
 // This enables no-tmux mode where agents discover args via gt prime / bd show.
-func storeArgsInBead(beadID, args string) error {
+// A non-empty taskPath is stored as attached_task for gt check run.
+func storeArgsInBead(beadID, args, taskPath string) error {
 	// Get the bead to preserve existing description content
 	showCmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
 	out, err := showCmd.Output()
@@ -542,6 +577,9 @@ func storeArgsInBead(beadID, args string) error {
 
 	// Set the args
 	fields.AttachedArgs = args
+	if taskPath != "" {
+		fields.AttachedTask = taskPath
+	}
 
 	// Update the description
 	newDesc := beads.SetAttachmentFields(issue, fields)
@@ -1017,7 +1055,7 @@ func runSlingFormula(args []string) error {
 
 	// Store args in wisp bead if provided (no-tmux mode: beads as data plane)
 	if slingArgs != "" {
-		if err := storeArgsInBead(wispRootID, slingArgs); err != nil {
+		if err := storeArgsInBead(wispRootID, slingArgs, slingTaskPath); err != nil {
 			fmt.Printf("%s Could not store args in bead: %v\n", style.Dim.Render("Warning:"), err)
 		} else {
 			fmt.Printf("%s Args stored in bead (durable)\n", style.Bold.Render("✓"))
@@ -1447,6 +1485,12 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			continue
 		}
 
+		if err := checkDuplicateAssignment(filepath.Dir(townBeadsDir), beadID, info, ""); err != nil {
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: "already assigned"})
+			fmt.Printf("  %s %v\n", style.Dim.Render("✗"), err)
+			continue
+		}
+
 		// Spawn a fresh polecat
 		spawnOpts := SlingSpawnOptions{
 			Force:    slingForce,
@@ -1503,7 +1547,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		// Store args if provided
 		if slingArgs != "" {
-			if err := storeArgsInBead(beadID, slingArgs); err != nil {
+			if err := storeArgsInBead(beadID, slingArgs, slingTaskPath); err != nil {
 				fmt.Printf("  %s Could not store args: %v\n", style.Dim.Render("Warning:"), err)
 			}
 		}
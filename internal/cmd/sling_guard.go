@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// activeAssignmentStatuses are bead statuses meaning an agent is on the work.
+var activeAssignmentStatuses = map[string]bool{
+	"hooked":      true,
+	"in_progress": true,
+}
+
+// checkDuplicateAssignment guards against slinging an issue that another
+// agent is already working. Depending on the town's assign_guard setting it
+// returns an error (block), prints a warning (warn), or does nothing (off).
+// Re-slinging to the current assignee and --force always pass.
+func checkDuplicateAssignment(townRoot, beadID string, info *beadInfo, target string) error {
+	if slingForce || info == nil || info.Assignee == "" || !activeAssignmentStatuses[info.Status] {
+		return nil
+	}
+	if target != "" && target == info.Assignee {
+		return nil
+	}
+
+	mode := loadTownSettingsQuiet(townRoot).AssignGuardMode()
+	if mode == config.AssignGuardOff {
+		return nil
+	}
+
+	hint := ""
+	if sessionID := latestSessionFor(info.Assignee); sessionID != "" {
+		hint = fmt.Sprintf("\n  Existing session: gt seance tail %s", sessionID)
+	}
+
+	if mode == config.AssignGuardWarn {
+		fmt.Printf("%s %s is already %s by %s%s\n",
+			style.WarningPrefix, beadID, info.Status, info.Assignee, hint)
+		return nil
+	}
+	return fmt.Errorf("%s is already %s by %s%s\nUse --force to assign anyway, or 'gt config assign-guard warn' to only warn",
+		beadID, info.Status, info.Assignee, hint)
+}
+
+// latestSessionFor returns the short ID of the most recent Claude session
+// whose beacon names the given agent address, or "" if none is found.
+func latestSessionFor(address string) string {
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{GasTownOnly: true, Role: address})
+	if err != nil {
+		return ""
+	}
+	for _, s := range sessions {
+		if s.Role == address {
+			return s.ShortID()
+		}
+	}
+	return ""
+}
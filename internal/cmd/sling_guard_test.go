@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestCheckDuplicateAssignment(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	townRoot := t.TempDir()
+	info := &beadInfo{Status: "hooked", Assignee: "gastown/polecats/toast"}
+
+	err := checkDuplicateAssignment(townRoot, "gt-abc", info, "gastown")
+	if err == nil || !strings.Contains(err.Error(), "gastown/polecats/toast") {
+		t.Fatalf("expected block naming the assignee, got %v", err)
+	}
+
+	// Re-slinging to the current assignee is allowed
+	if err := checkDuplicateAssignment(townRoot, "gt-abc", info, "gastown/polecats/toast"); err != nil {
+		t.Errorf("same-assignee sling blocked: %v", err)
+	}
+
+	// Open issues are never guarded
+	if err := checkDuplicateAssignment(townRoot, "gt-abc", &beadInfo{Status: "open", Assignee: "x"}, ""); err != nil {
+		t.Errorf("open issue blocked: %v", err)
+	}
+
+	// Warn mode proceeds
+	settings := config.NewTownSettings()
+	settings.AssignGuard = config.AssignGuardWarn
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkDuplicateAssignment(townRoot, "gt-abc", info, "gastown"); err != nil {
+		t.Errorf("warn mode blocked: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/takeover"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	takeoverReverse bool
+	takeoverBy      string
+	takeoverNote    string
+	takeoverClear   bool
+)
+
+var takeoverCmd = &cobra.Command{
+	Use:     "takeover <session-id>",
+	GroupID: GroupDiag,
+	Short:   "Record that a human continued an agent's work, or the reverse",
+	Long: `Mark a session as blended: worked on by both an agent and a human.
+
+By default this records that a human took over from the agent; use
+--reverse when an agent picked up a human's work. Blended sessions are
+flagged in 'gt seance' (⇄) and post-mortems, and their outcomes are
+counted apart in 'gt outcome report' and 'gt trends', so agents aren't
+credited for human fixes or blamed for human mistakes.
+
+Takeovers are stored in <town>/.runtime/takeovers.jsonl.
+
+Examples:
+  gt takeover abc123
+  gt takeover abc123 --note "fixed the migration by hand"
+  gt takeover abc123 --reverse --by gastown/polecats/toast
+  gt takeover abc123 --clear`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTakeover,
+}
+
+func init() {
+	takeoverCmd.Flags().BoolVar(&takeoverReverse, "reverse", false, "An agent continued a human's work")
+	takeoverCmd.Flags().StringVar(&takeoverBy, "by", "", "Who took over (default: detected identity)")
+	takeoverCmd.Flags().StringVar(&takeoverNote, "note", "", "What the takeover changed")
+	takeoverCmd.Flags().BoolVar(&takeoverClear, "clear", false, "Withdraw the session's takeover records")
+
+	rootCmd.AddCommand(takeoverCmd)
+}
+
+func runTakeover(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rec := takeover.Record{
+		SessionID: args[0],
+		Direction: takeover.ToHuman,
+		By:        takeoverBy,
+		Note:      takeoverNote,
+		Cleared:   takeoverClear,
+	}
+	if takeoverReverse {
+		rec.Direction = takeover.ToAgent
+	}
+
+	// Resolve the full ID and role from the transcript when available
+	if s, err := claude.FindSession(args[0]); err == nil {
+		rec.SessionID = s.ID
+		if s.Role != "" {
+			role, rig, _ := parseRoleString(s.Role)
+			rec.Role = string(role)
+			rec.Rig = rig
+		}
+	}
+
+	if takeoverClear {
+		rec.Direction = ""
+		prev, _ := takeover.Load(townRoot)
+		if err := takeover.Append(townRoot, rec); err != nil {
+			return fmt.Errorf("recording takeover: %w", err)
+		}
+		if err := logTakeoverChange(rec, prev[rec.SessionID]); err != nil {
+			return err
+		}
+		fmt.Printf("%s Cleared takeovers for %s\n", style.SuccessPrefix, shortSessionID(rec.SessionID))
+		return nil
+	}
+
+	if rec.By == "" {
+		rec.By = detectSender()
+	}
+	if err := takeover.Append(townRoot, rec); err != nil {
+		return fmt.Errorf("recording takeover: %w", err)
+	}
+	if err := logTakeoverChange(rec, nil); err != nil {
+		return err
+	}
+
+	who := "a human"
+	if rec.Direction == takeover.ToAgent {
+		who = "an agent"
+	}
+	fmt.Printf("%s Recorded that %s (%s) took over %s\n", style.SuccessPrefix, who, rec.By, shortSessionID(rec.SessionID))
+	return nil
+}
+
+// logTakeoverChange adds a takeover, or the withdrawal of the earlier ones
+// in cleared, to the session metadata history (gt seance index history).
+func logTakeoverChange(rec takeover.Record, cleared []takeover.Record) error {
+	change := claude.OverlayChange{Session: rec.SessionID, Kind: claude.OverlayTakeover, Op: claude.OverlayAdd}
+	if rec.Cleared {
+		change.Op = claude.OverlayClear
+		change.Old = takeover.Describe(cleared)
+	} else {
+		change.Value = takeover.Describe([]takeover.Record{rec})
+		if rec.Note != "" {
+			change.Value += ": " + rec.Note
+		}
+	}
+	if err := claude.RecordOverlayChange(change); err != nil {
+		return fmt.Errorf("logging takeover change: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/policy"
+	"github.com/steveyegge/gastown/internal/task"
+)
+
+var (
+	taskLintFix  bool
+	taskLintJSON bool
+	taskShowJSON bool
+)
+
+// defaultTaskFile is the task spec used when none is named.
+const defaultTaskFile = "task.yaml"
+
+var taskCmd = &cobra.Command{
+	Use:     "task",
+	GroupID: GroupWork,
+	Short:   "Check and render task specs",
+	Long: `Work with task specs: the structured way to describe a unit of work.
+
+A task spec (task.yaml, or <name>.task.yaml) says what done looks like
+instead of leaving it to a free-text prompt:
+
+  goal: Retry mail delivery when the recipient's inbox is locked
+  constraints:
+    - Don't change the mailbox file format
+  acceptance:
+    - "run: go test ./internal/mail/..."
+    - A locked inbox delays delivery instead of dropping the message
+  files_hint:
+    - internal/mail/
+  budget:
+    cost: 5.00
+    duration: 2h
+    tokens: 2M
+
+Only goal is required. The goal may span lines as a "|" or ">" block.
+
+Acceptance items prefixed with run:, exists:, or missing: are checked
+automatically by gt check run; the rest are left to a reviewer.
+
+Specs are used by:
+  gt sling <bead> <target> --task <spec>    # Hand the spec to the agent
+  gt convoy create <name> ... --task <spec> # Record the convoy's spec
+  gt mayor estimate <plan>                  # Plan tasks may set task: <spec>
+  gt check run --session <id>               # Run a session's acceptance checks`,
+	RunE: requireSubcommand,
+}
+
+var taskLintCmd = &cobra.Command{
+	Use:   "lint [file...]",
+	Short: "Validate task specs",
+	Long: `Check task specs against the schema, reporting file:line:col for:
+
+  - unknown fields and budget fields, with the closest known name
+  - a missing goal, and list items outside their list
+  - budget values that aren't positive amounts, durations, or counts
+  - specs with no acceptance checks (a warning)
+
+With no arguments, lints ./task.yaml. --fix renames misspelled fields
+and indents flush list items, then re-checks.
+
+Exits non-zero when errors remain.
+
+Examples:
+  gt task lint
+  gt task lint tasks/*.task.yaml --fix`,
+	RunE: runTaskLint,
+}
+
+var taskShowCmd = &cobra.Command{
+	Use:   "show [file]",
+	Short: "Render a task spec as the prompt agents are given",
+	Long: `Render a task spec the way agents see it, from the template library's
+task message. With no argument, renders ./task.yaml.
+
+Examples:
+  gt task show
+  gt task show auth.task.yaml --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTaskShow,
+}
+
+func init() {
+	taskLintCmd.Flags().BoolVar(&taskLintFix, "fix", false, "Apply fixes for common mistakes")
+	taskLintCmd.Flags().BoolVar(&taskLintJSON, "json", false, "Output as JSON")
+	taskShowCmd.Flags().BoolVar(&taskShowJSON, "json", false, "Output the parsed spec as JSON")
+
+	taskCmd.AddCommand(taskLintCmd)
+	taskCmd.AddCommand(taskShowCmd)
+	rootCmd.AddCommand(taskCmd)
+}
+
+func runTaskLint(cmd *cobra.Command, args []string) error {
+	files := args
+	if len(files) == 0 {
+		files = []string{defaultTaskFile}
+	}
+
+	results := make([]*policy.Result, 0, len(files))
+	fixed := make(map[string]int)
+	errorCount := 0
+	for _, path := range files {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: user-specified task file
+		if err != nil {
+			return err
+		}
+		result := policy.Lint(path, policy.KindTask, data)
+		if taskLintFix && result.Fixable() > 0 {
+			if result, fixed[path], err = fixPolicyFile(result); err != nil {
+				return err
+			}
+		}
+		errorCount += result.Errors()
+		results = append(results, result)
+	}
+
+	if taskLintJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printPolicyResults(results, fixed, taskLintFix)
+	}
+
+	if errorCount > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runTaskShow(cmd *cobra.Command, args []string) error {
+	path := defaultTaskFile
+	if len(args) > 0 {
+		path = args[0]
+	}
+	spec, err := task.Load(path)
+	if err != nil {
+		return err
+	}
+
+	if taskShowJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(spec)
+	}
+	prompt, err := spec.Render()
+	if err != nil {
+		return err
+	}
+	fmt.Print(prompt)
+	return nil
+}
+
+// loadTaskRef loads the task spec at path for gt sling and gt convoy
+// create, returning it with its absolute path so agents working
+// elsewhere in the town can find it.
+func loadTaskRef(path string) (*task.Spec, string, error) {
+	spec, err := task.Load(path)
+	if err != nil {
+		return nil, "", err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("resolving %s: %w", path, err)
+	}
+	return spec, abs, nil
+}
+
+// taskArgs is the sling --args text that hands an agent a task spec.
+func taskArgs(spec *task.Spec, path string) string {
+	return fmt.Sprintf("Task: %s. Read the full task spec with `gt task show %s` and meet its acceptance checks.", spec.Headline(), path)
+}
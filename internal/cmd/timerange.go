@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timeBoundLayouts are the absolute formats parseTimeBound accepts, in
+// local time unless the layout carries a zone.
+var timeBoundLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTimeBound parses a --since/--until value relative to now:
+//
+//	"" (no bound), "now", "today", "yesterday"
+//	the start of this "week" (Monday), "month", "quarter", or "year"
+//	a duration ago: "90m", "6h", "2d", "1w"
+//	a date or time: "2025-01-02", "2025-01-02 15:04", RFC 3339
+//
+// Days ("today", "yesterday", a bare date) mean local midnight at their
+// start.
+func parseTimeBound(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	midnight := func(t time.Time) time.Time {
+		t = t.Local()
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	}
+
+	switch strings.ToLower(s) {
+	case "":
+		return time.Time{}, nil
+	case "now":
+		return now, nil
+	case "today":
+		return midnight(now), nil
+	case "yesterday":
+		return midnight(now).AddDate(0, 0, -1), nil
+	case "week":
+		day := midnight(now)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7), nil
+	case "month", "quarter", "year":
+		t := now.Local()
+		month := t.Month()
+		switch strings.ToLower(s) {
+		case "quarter":
+			month -= (month - 1) % 3
+		case "year":
+			month = time.January
+		}
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, time.Local), nil
+	}
+
+	for _, layout := range timeBoundLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	ago := s
+	if weeks, ok := strings.CutSuffix(ago, "w"); ok {
+		var n int
+		if _, err := fmt.Sscanf(weeks, "%d", &n); err == nil {
+			ago = fmt.Sprintf("%dd", n*7)
+		}
+	}
+	d, err := parseDuration(ago)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid time %q (use e.g. 2d, 6h, yesterday, or 2025-01-02)", s)
+	}
+	return now.Add(-d), nil
+}
+
+// parseTimeRange parses --since and --until values, checking that the
+// window is not empty.
+func parseTimeRange(since, until string, now time.Time) (time.Time, time.Time, error) {
+	from, err := parseTimeBound(since, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --since: %w", err)
+	}
+	to, err := parseTimeBound(until, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --until: %w", err)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--since %s is not before --until %s", since, until)
+	}
+	return from, to, nil
+}
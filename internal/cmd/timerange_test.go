@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.Local)
+	midnight := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+	tests := map[string]time.Time{
+		"":                     {},
+		"now":                  now,
+		"today":                midnight,
+		"Yesterday":            midnight.AddDate(0, 0, -1),
+		"week":                 time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local),
+		"month":                time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local),
+		"quarter":              time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local),
+		"year":                 time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local),
+		"2d":                   now.Add(-48 * time.Hour),
+		"6h":                   now.Add(-6 * time.Hour),
+		"1w":                   now.Add(-7 * 24 * time.Hour),
+		"2025-03-01":           time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local),
+		"2025-03-01 09:15":     time.Date(2025, 3, 1, 9, 15, 0, 0, time.Local),
+		"2025-03-01T09:15:00Z": time.Date(2025, 3, 1, 9, 15, 0, 0, time.UTC),
+	}
+	for in, want := range tests {
+		got, err := parseTimeBound(in, now)
+		if err != nil {
+			t.Errorf("parseTimeBound(%q): %v", in, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("parseTimeBound(%q) = %v, want %v", in, got, want)
+		}
+	}
+	for _, bad := range []string{"soon", "-2d", "2025-13-01"} {
+		if _, err := parseTimeBound(bad, now); err == nil {
+			t.Errorf("parseTimeBound(%q) succeeded", bad)
+		}
+	}
+
+	if _, _, err := parseTimeRange("yesterday", "2d", now); err == nil {
+		t.Error("parseTimeRange accepted an empty window")
+	}
+	if from, to, err := parseTimeRange("2d", "yesterday", now); err != nil || !from.Before(to) {
+		t.Errorf("parseTimeRange(2d, yesterday) = %v, %v, %v", from, to, err)
+	}
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/takeover"
+	"github.com/steveyegge/gastown/internal/trends"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	trendsRole   string
+	trendsRig    string
+	trendsModel  string
+	trendsMetric string
+	trendsWindow string
+	trendsJSON   bool
+)
+
+var trendsCmd = &cobra.Command{
+	Use:     "trends",
+	GroupID: GroupDiag,
+	Short:   "Show long-term weekly trends per role",
+	Long: `Show how a role's sessions trend week over week.
+
+Use this to check whether prompt or tooling changes actually improved a
+role over months, rather than judging from the last few sessions.
+
+Metrics:
+  cost      Total estimated cost of the week's sessions
+  duration  Median session duration
+  success   Share of sessions with a recorded outcome that succeeded
+            (see 'gt outcome set')
+
+With --role, prints a per-week table. Without it, prints one sparkline
+per role.
+
+Examples:
+  gt trends
+  gt trends --role crew --metric cost --window 12w
+  gt trends --role polecat --metric success --window 26w --rig gastown
+  gt trends --role crew --metric cost --model opus   # Only Opus sessions`,
+	RunE: runTrends,
+}
+
+func init() {
+	trendsCmd.Flags().StringVar(&trendsRole, "role", "", "Role type (crew, polecat, witness, ...)")
+	trendsCmd.Flags().StringVar(&trendsRig, "rig", "", "Only include sessions from this rig")
+	trendsCmd.Flags().StringVar(&trendsModel, "model", "", "Only include sessions whose model contains this (e.g., opus, sonnet-4-5)")
+	trendsCmd.Flags().StringVar(&trendsMetric, "metric", "cost", "Metric: cost, duration, or success")
+	trendsCmd.Flags().StringVar(&trendsWindow, "window", "12w", "How far back to look (e.g., 12w, 90d)")
+	trendsCmd.Flags().BoolVar(&trendsJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(trendsCmd)
+}
+
+// trendLine is one role's weekly trend.
+type trendLine struct {
+	Role   string        `json:"role"`
+	Metric trends.Metric `json:"metric"`
+	Weeks  []trends.Week `json:"weeks"`
+}
+
+func runTrends(cmd *cobra.Command, args []string) error {
+	metric, err := trends.ParseMetric(trendsMetric)
+	if err != nil {
+		return err
+	}
+	weeks, err := parseTrendWindow(trendsWindow)
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	now := time.Now()
+	cutoff := trends.WeekStart(now).AddDate(0, 0, -7*(weeks-1))
+	samples, err := collectTrendSamples(townRoot, metric, cutoff)
+	if err != nil {
+		return err
+	}
+
+	byRole := make(map[string][]trends.Sample)
+	for _, s := range samples {
+		byRole[s.Role] = append(byRole[s.Role], s)
+	}
+	roles := make([]string, 0, len(byRole))
+	for role := range byRole {
+		if trendsRole == "" || role == trendsRole {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	if trendsRole != "" && len(roles) == 0 {
+		roles = []string{trendsRole} // Show an empty trend rather than nothing
+	}
+
+	lines := make([]trendLine, 0, len(roles))
+	for _, role := range roles {
+		lines = append(lines, trendLine{Role: role, Metric: metric, Weeks: trends.Weekly(byRole[role], metric, weeks, now)})
+	}
+
+	if trendsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(lines)
+	}
+
+	if len(lines) == 0 {
+		fmt.Println(style.Dim.Render("No Gas Town sessions in this window."))
+		return nil
+	}
+
+	if trendsRole != "" {
+		printTrendTable(lines[0])
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Weekly %s, last %d weeks", metric, weeks)))
+	for _, line := range lines {
+		first, last := "-", "-"
+		for _, w := range line.Weeks {
+			if w.HasValue {
+				if first == "-" {
+					first = formatTrendValue(metric, w.Value)
+				}
+				last = formatTrendValue(metric, w.Value)
+			}
+		}
+		fmt.Printf("  %-10s  %s  %s → %s\n", line.Role, trends.Sparkline(line.Weeks), first, last)
+	}
+	return nil
+}
+
+// printTrendTable prints one role's trend as a sparkline and weekly table.
+func printTrendTable(line trendLine) {
+	fmt.Printf("%s  %s\n\n", style.Bold.Render(fmt.Sprintf("%s · %s", line.Role, line.Metric)), trends.Sparkline(line.Weeks))
+	fmt.Printf("  %-10s  %8s  %10s\n", "WEEK", "SESSIONS", strings.ToUpper(string(line.Metric)))
+	for _, w := range line.Weeks {
+		value := "-"
+		if w.HasValue {
+			value = formatTrendValue(line.Metric, w.Value)
+		}
+		fmt.Printf("  %-10s  %8d  %10s\n", w.Start.Format("2006-01-02"), w.Sessions, value)
+	}
+}
+
+// collectTrendSamples gathers Gas Town sessions started since cutoff.
+// Transcripts are only scanned for usage when the metric needs it.
+func collectTrendSamples(townRoot string, metric trends.Metric, cutoff time.Time) ([]trends.Sample, error) {
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{GasTownOnly: true, Rig: trendsRig, Model: trendsModel})
+	if err != nil {
+		return nil, fmt.Errorf("discovering sessions: %w", err)
+	}
+
+	outcomes := make(map[string]outcome.Status)
+	if metric == trends.MetricSuccess {
+		records, err := outcome.Load(townRoot)
+		if err != nil {
+			return nil, err
+		}
+		takeovers, err := takeover.Load(townRoot)
+		if err != nil {
+			return nil, err
+		}
+		for _, rec := range records {
+			// Blended sessions aren't the agent's success or failure alone
+			if len(takeovers[rec.SessionID]) == 0 {
+				outcomes[rec.SessionID] = rec.Status
+			}
+		}
+	}
+	prices := loadTownSettingsQuiet(townRoot).PriceTable()
+
+	var samples []trends.Sample
+	for _, s := range sessions {
+		if s.StartTime.Before(cutoff) {
+			continue
+		}
+		role, rig, _ := parseRoleString(s.Role)
+		sample := trends.Sample{
+			Start:   s.StartTime,
+			Role:    string(role),
+			Rig:     rig,
+			Outcome: outcomes[s.ID],
+		}
+		if metric != trends.MetricSuccess {
+			if usage, err := claude.ReadUsage(s.Path); err == nil {
+				sample.Cost = claude.EstimateCost(*usage, prices)
+				sample.Duration = usage.Duration
+			}
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// parseTrendWindow converts a window like "12w" or "90d" to whole weeks.
+func parseTrendWindow(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if n, ok := strings.CutSuffix(s, "w"); ok {
+		weeks, err := strconv.Atoi(n)
+		if err != nil || weeks <= 0 {
+			return 0, fmt.Errorf("invalid --window %q", s)
+		}
+		return weeks, nil
+	}
+	d, err := parseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid --window %q (use e.g. 12w or 90d)", s)
+	}
+	week := 7 * 24 * time.Hour
+	return int((d + week - 1) / week), nil
+}
+
+// formatTrendValue formats a metric value for display.
+func formatTrendValue(metric trends.Metric, v float64) string {
+	switch metric {
+	case trends.MetricCost:
+		return fmt.Sprintf("$%.2f", v)
+	case trends.MetricDuration:
+		return fmt.Sprintf("%.0fm", v)
+	case trends.MetricSuccess:
+		return fmt.Sprintf("%.0f%%", 100*v)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	verifyRolesSince string
+	verifyRolesRig   string
+	verifyRolesJSON  bool
+)
+
+var verifyCmd = &cobra.Command{
+	Use:     "verify",
+	GroupID: GroupDiag,
+	Short:   "Check agent sessions against the town's records",
+	RunE:    requireSubcommand,
+}
+
+var verifyRolesCmd = &cobra.Command{
+	Use:   "roles",
+	Short: "Flag sessions whose beacon role doesn't match their workspace",
+	Long: `Check that every Gas Town session announced the role its workspace
+belongs to.
+
+A session's [GAS TOWN] beacon says who it is ("gastown/polecats/toast").
+Its working directory says who it should be: <rig>/polecats/<name>,
+<rig>/crew/<name>, <rig>/witness, and so on. A session is flagged when:
+  - its beacon role differs from its workspace's role, rig, or name
+  - its beacon names a rig that isn't in the rig registry
+
+A copy-pasted startup prompt can have a polecat announce itself as the
+witness; reviews keyed on the role then go to the wrong place or not at
+all. Sessions started outside the town, or in a rig root that belongs to
+no role, can't be checked and are counted as unverified.
+
+Exits non-zero if any session is flagged.
+
+Examples:
+  gt verify roles
+  gt verify roles --since 30d --rig gastown
+  gt verify roles --json`,
+	Args: cobra.NoArgs,
+	RunE: runVerifyRoles,
+}
+
+func init() {
+	verifyRolesCmd.Flags().StringVar(&verifyRolesSince, "since", "7d", "Only sessions started at or after this time (e.g., 7d, today, 2025-01-02)")
+	verifyRolesCmd.Flags().StringVar(&verifyRolesRig, "rig", "", "Only sessions in this rig")
+	verifyRolesCmd.Flags().BoolVar(&verifyRolesJSON, "json", false, "Output as JSON")
+
+	verifyCmd.AddCommand(verifyRolesCmd)
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// roleMismatch is a session whose beacon role its workspace doesn't back.
+type roleMismatch struct {
+	SessionID string    `json:"session_id"`
+	StartTime time.Time `json:"start_time"`
+	Claimed   string    `json:"claimed"`             // beacon role
+	Workspace string    `json:"workspace,omitempty"` // role of the working directory
+	Path      string    `json:"path"`                // working directory
+	Problem   string    `json:"problem"`
+}
+
+// roleVerification is the result of gt verify roles.
+type roleVerification struct {
+	Checked    int            `json:"checked"`
+	Unverified int            `json:"unverified"`
+	Mismatches []roleMismatch `json:"mismatches"`
+}
+
+func runVerifyRoles(cmd *cobra.Command, args []string) error {
+	since, _, err := parseTimeRange(verifyRolesSince, "", time.Now())
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	sessions, err := discoverClaudeSessions(claude.SessionFilter{
+		GasTownOnly: true,
+		Rig:         verifyRolesRig,
+		Since:       since,
+	})
+	if err != nil {
+		return fmt.Errorf("discovering sessions: %w", err)
+	}
+
+	// Without a registry only workspaces are checked
+	var rigs map[string]config.RigEntry
+	if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		rigs = rigsConfig.Rigs
+	}
+
+	result := verifySessionRoles(sessions, townRoot, rigs)
+	if verifyRolesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printRoleVerification(result)
+	}
+
+	if len(result.Mismatches) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// verifySessionRoles checks each session's beacon role against its
+// workspace and, when rigs is non-nil, the rig registry.
+func verifySessionRoles(sessions []claude.SessionInfo, townRoot string, rigs map[string]config.RigEntry) roleVerification {
+	if resolved, err := filepath.EvalSymlinks(townRoot); err == nil {
+		townRoot = resolved
+	}
+	result := roleVerification{Mismatches: []roleMismatch{}}
+	for _, s := range sessions {
+		if s.Role == "" {
+			continue
+		}
+		workspace, problem, ok := checkSessionRole(s, townRoot, rigs)
+		if !ok {
+			result.Unverified++
+			continue
+		}
+		result.Checked++
+		if problem != "" {
+			result.Mismatches = append(result.Mismatches, roleMismatch{
+				SessionID: s.ID,
+				StartTime: s.StartTime,
+				Claimed:   s.Role,
+				Workspace: workspace,
+				Path:      s.ProjectPath,
+				Problem:   problem,
+			})
+		}
+	}
+	return result
+}
+
+// checkSessionRole compares s's beacon role with the role of the
+// workspace it ran in. It returns the workspace's role and what is wrong,
+// if anything; ok is false when the workspace belongs to no role.
+func checkSessionRole(s claude.SessionInfo, townRoot string, rigs map[string]config.RigEntry) (workspace, problem string, ok bool) {
+	dir := s.ProjectPath
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	if rel, err := filepath.Rel(townRoot, dir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", "", false
+	}
+	actual := detectRole(dir, townRoot)
+	if actual.Role == RoleUnknown {
+		return "", "", false
+	}
+	workspace = actual.ActorString()
+
+	claimed, rig, name := parseRoleString(s.Role)
+	switch {
+	case claimed != actual.Role:
+		return workspace, fmt.Sprintf("claims to be a %s but ran in a %s workspace", claimed, actual.Role), true
+	case rig != "" && rigs != nil && !rigRegistered(rigs, rig):
+		return workspace, fmt.Sprintf("rig %q is not in the rig registry", rig), true
+	case rig != "" && actual.Rig != "" && !strings.EqualFold(rig, actual.Rig):
+		return workspace, fmt.Sprintf("claims rig %s but ran in rig %s", rig, actual.Rig), true
+	case name != "" && actual.Polecat != "" && name != actual.Polecat:
+		return workspace, fmt.Sprintf("claims to be %s but ran in %s's workspace", name, actual.Polecat), true
+	}
+	return workspace, "", true
+}
+
+// rigRegistered reports whether rig is in the registry, ignoring case as
+// beacon matching does.
+func rigRegistered(rigs map[string]config.RigEntry, rig string) bool {
+	for name := range rigs {
+		if strings.EqualFold(name, rig) {
+			return true
+		}
+	}
+	return false
+}
+
+func printRoleVerification(v roleVerification) {
+	summary := fmt.Sprintf("%d session(s) checked", v.Checked)
+	if v.Unverified > 0 {
+		summary += fmt.Sprintf(", %d outside any role's workspace", v.Unverified)
+	}
+	if len(v.Mismatches) == 0 {
+		fmt.Printf("%s Every session's role matches its workspace %s\n", style.SuccessPrefix, style.Dim.Render("("+summary+")"))
+		return
+	}
+
+	fmt.Printf("%s %d session(s) announced a role their workspace doesn't match %s\n\n",
+		style.ErrorPrefix, len(v.Mismatches), style.Dim.Render("("+summary+")"))
+	for _, m := range v.Mismatches {
+		id := m.SessionID
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		fmt.Printf("  %s  %s  %s\n", style.Bold.Render(id), m.StartTime.Local().Format("2006-01-02 15:04"), m.Problem)
+		fmt.Printf("    %s\n", style.Dim.Render("beacon: "+m.Claimed+"  workspace: "+m.Workspace+" ("+m.Path+")"))
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestVerifySessionRoles(t *testing.T) {
+	town := t.TempDir()
+	dir := func(rel string) string {
+		p := filepath.Join(town, filepath.FromSlash(rel))
+		if err := os.MkdirAll(p, 0755); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	rigs := map[string]config.RigEntry{"gastown": {}}
+	sessions := []claude.SessionInfo{
+		{ID: "ok-polecat", Role: "gastown/polecats/toast", ProjectPath: dir("gastown/polecats/toast")},
+		{ID: "ok-witness", Role: "gastown/witness", ProjectPath: dir("gastown/witness/rig")},
+		{ID: "ok-mayor", Role: "mayor", ProjectPath: dir("mayor")},
+		{ID: "impostor", Role: "gastown/witness", ProjectPath: dir("gastown/polecats/toast")},
+		{ID: "wrong-name", Role: "gastown/polecats/nux", ProjectPath: dir("gastown/polecats/toast")},
+		{ID: "unregistered", Role: "beads/crew/joe", ProjectPath: dir("beads/crew/joe")},
+		{ID: "rig-root", Role: "gastown/witness", ProjectPath: dir("gastown")},
+		{ID: "elsewhere", Role: "gastown/witness", ProjectPath: t.TempDir()},
+		{ID: "no-beacon", ProjectPath: dir("gastown/polecats/toast")},
+	}
+
+	v := verifySessionRoles(sessions, town, rigs)
+	if v.Checked != 6 || v.Unverified != 2 {
+		t.Errorf("checked %d, unverified %d; want 6, 2", v.Checked, v.Unverified)
+	}
+	problems := make(map[string]string)
+	for _, m := range v.Mismatches {
+		problems[m.SessionID] = m.Problem
+	}
+	for id, want := range map[string]string{
+		"impostor":     "claims to be a witness but ran in a polecat workspace",
+		"wrong-name":   "claims to be nux but ran in toast's workspace",
+		"unregistered": `rig "beads" is not in the rig registry`,
+	} {
+		if got := problems[id]; got != want {
+			t.Errorf("%s problem = %q, want %q", id, got, want)
+		}
+	}
+	if len(problems) != 3 {
+		t.Errorf("mismatches = %v, want 3", problems)
+	}
+	for _, m := range v.Mismatches {
+		if m.SessionID == "impostor" && m.Workspace != "gastown/polecats/toast" {
+			t.Errorf("impostor workspace = %q", m.Workspace)
+		}
+	}
+
+	// Without a registry, only workspaces are checked
+	v = verifySessionRoles(sessions, town, nil)
+	for _, m := range v.Mismatches {
+		if strings.Contains(m.Problem, "registry") {
+			t.Errorf("flagged %s without a registry: %s", m.SessionID, m.Problem)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package config
+
+// ClaudeRequirements lists what a rig needs from the installed Claude Code,
+// checked by 'gt claude info'.
+type ClaudeRequirements struct {
+	// MinVersion is the oldest Claude Code version the rig supports (e.g., "2.0.0").
+	MinVersion string `json:"min_version,omitempty"`
+
+	// MCPServers are MCP servers the rig's agents rely on. Each must be
+	// configured at user scope or for the rig's agent directories.
+	MCPServers []string `json:"mcp_servers,omitempty"`
+
+	// PermissionMode is the permission mode the rig's agents must run in
+	// (default, acceptEdits, plan, or bypassPermissions).
+	PermissionMode string `json:"permission_mode,omitempty"`
+}
@@ -0,0 +1,24 @@
+package config
+
+import "path/filepath"
+
+// ClaudeRootDirs returns the town's extra Claude data directories
+// ("claude_roots"), with ~ expanded and relative paths taken from
+// townRoot. Nil settings yield none.
+func (s *TownSettings) ClaudeRootDirs(townRoot string) []string {
+	if s == nil {
+		return nil
+	}
+	dirs := make([]string, 0, len(s.ClaudeRoots))
+	for _, dir := range s.ClaudeRoots {
+		if dir == "" {
+			continue
+		}
+		dir = expandPath(dir)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(townRoot, dir)
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
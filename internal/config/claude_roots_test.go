@@ -0,0 +1,29 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClaudeRootDirs(t *testing.T) {
+	if dirs := (*TownSettings)(nil).ClaudeRootDirs("/town"); dirs != nil {
+		t.Errorf("nil settings = %v, want none", dirs)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	s := &TownSettings{ClaudeRoots: []string{"/srv/ci/.claude", "~/other", "mounts/box", ""}}
+	got := s.ClaudeRootDirs("/town")
+	want := []string{"/srv/ci/.claude", filepath.Join(home, "other"), filepath.Join("/town", "mounts", "box")}
+	if len(got) != len(want) {
+		t.Fatalf("ClaudeRootDirs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ClaudeRootDirs[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+// CleanConfig sets the disk quota for a rig's agent worktrees, enforced by
+// 'gt clean'.
+type CleanConfig struct {
+	// Quota is how large a worktree may grow before gt clean removes its
+	// artifacts (e.g., "5GB"). Default "2GB".
+	Quota string `json:"quota,omitempty"`
+
+	// CacheAge is how long a cache must go untouched before gt clean
+	// treats it as stale (e.g., "7d", "48h"). Default "7d".
+	CacheAge string `json:"cache_age,omitempty"`
+
+	// Build lists extra directory names to treat as build artifacts
+	// (e.g., "coverage", "out").
+	Build []string `json:"build,omitempty"`
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// UserAliasesFile is the name of the per-user command alias file, kept in
+// the user's gastown config directory.
+const UserAliasesFile = "aliases.json"
+
+// LoadCommandAliases reads a command alias file: a JSON object mapping
+// alias names to the command lines they expand to. A missing file holds
+// no aliases.
+func LoadCommandAliases(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from trusted config location
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("reading aliases: %w", err)
+	}
+	aliases := map[string]string{}
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("parsing aliases %s: %w", path, err)
+	}
+	return aliases, nil
+}
+
+// SaveCommandAliases writes a command alias file after validating it.
+func SaveCommandAliases(path string, aliases map[string]string) error {
+	if err := ValidateCommandAliases(aliases); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	data, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding aliases: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: aliases are not secret
+		return fmt.Errorf("writing aliases: %w", err)
+	}
+	return nil
+}
+
+// ValidateCommandAliases checks alias names and expansions: names are
+// single words of letters, digits, '-', '_' and '.', not starting with
+// '-', and each expansion must split into a command line that starts with
+// a command rather than a flag.
+func ValidateCommandAliases(aliases map[string]string) error {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !validCommandAliasName(name) {
+			return fmt.Errorf("command_aliases: invalid alias name %q", name)
+		}
+		args, err := SplitCommandLine(aliases[name])
+		if err != nil {
+			return fmt.Errorf("command_aliases.%s: %w", name, err)
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("command_aliases.%s: empty expansion", name)
+		}
+		if strings.HasPrefix(args[0], "-") {
+			return fmt.Errorf("command_aliases.%s: expansion must start with a command, not %q", name, args[0])
+		}
+	}
+	return nil
+}
+
+func validCommandAliasName(name string) bool {
+	if name == "" || name[0] == '-' {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// SplitCommandLine splits an alias expansion into arguments the way a
+// POSIX shell would for plain words: whitespace separates arguments,
+// single quotes keep text literally, and double quotes keep whitespace
+// while allowing backslash escapes. Variables and globs are not expanded.
+func SplitCommandLine(s string) ([]string, error) {
+	var (
+		args    []string
+		cur     strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				args = append(args, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inWord {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
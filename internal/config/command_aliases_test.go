@@ -0,0 +1,77 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"seance --recent 10", []string{"seance", "--recent", "10"}},
+		{"  seance\t--since 1d ", []string{"seance", "--since", "1d"}},
+		{`mail send mayor -s "hello there"`, []string{"mail", "send", "mayor", "-s", "hello there"}},
+		{`note 'it''s' "a \"b\"" c\ d`, []string{"note", "its", `a "b"`, "c d"}},
+		{`x ""`, []string{"x", ""}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommandLine(tt.in)
+		if err != nil {
+			t.Errorf("SplitCommandLine(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitCommandLine(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{`seance "open`, `seance 'open`, `seance \`} {
+		if _, err := SplitCommandLine(bad); err == nil {
+			t.Errorf("SplitCommandLine(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestValidateCommandAliases(t *testing.T) {
+	t.Parallel()
+	if err := ValidateCommandAliases(map[string]string{"s": "seance --recent 10", "my.wip_2": "status"}); err != nil {
+		t.Errorf("valid aliases: %v", err)
+	}
+	for name, aliases := range map[string]map[string]string{
+		"flag name":     {"-s": "seance"},
+		"space in name": {"my s": "seance"},
+		"empty":         {"s": "  "},
+		"flag first":    {"s": "--recent 10"},
+		"unterminated":  {"s": `seance "x`},
+		"empty name":    {"": "seance"},
+	} {
+		if err := ValidateCommandAliases(aliases); err == nil {
+			t.Errorf("%s: validated %v", name, aliases)
+		}
+	}
+}
+
+func TestCommandAliasesFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "gastown", UserAliasesFile)
+
+	aliases, err := LoadCommandAliases(path)
+	if err != nil || len(aliases) != 0 {
+		t.Fatalf("missing file = %v, %v", aliases, err)
+	}
+	if err := SaveCommandAliases(path, map[string]string{"s": "seance --recent 10"}); err != nil {
+		t.Fatal(err)
+	}
+	aliases, err = LoadCommandAliases(path)
+	if err != nil || aliases["s"] != "seance --recent 10" {
+		t.Fatalf("reloaded = %v, %v", aliases, err)
+	}
+	if err := SaveCommandAliases(path, map[string]string{"s": "--bad"}); err == nil {
+		t.Error("saved an invalid alias")
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Ingest defaults.
+const (
+	DefaultIngestInterval = "5s"
+	DefaultIngestQuiet    = "2s"
+	DefaultIngestMaxDelay = "30s"
+	DefaultIngestMaxBatch = 50
+)
+
+// IngestConfig controls how the daemon keeps the session index up to date
+// as agents write transcripts. Changed transcripts are queued, left until
+// they go quiet, and indexed in batches, so commands read a fresh index
+// instead of each re-parsing every active transcript.
+type IngestConfig struct {
+	// Disabled turns daemon ingest off; commands then index transcripts
+	// themselves as they find them changed.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Interval is how often queued transcripts are indexed. Default "5s".
+	Interval string `json:"interval,omitempty"`
+
+	// Quiet is how long a transcript must go unwritten before it is
+	// indexed. Default "2s".
+	Quiet string `json:"quiet,omitempty"`
+
+	// MaxDelay indexes a transcript that never goes quiet at most this
+	// long after it was queued. Default "30s".
+	MaxDelay string `json:"max_delay,omitempty"`
+
+	// MaxBatch caps how many transcripts one batch indexes; the rest wait
+	// for the next interval. Default 50.
+	MaxBatch int `json:"max_batch,omitempty"`
+}
+
+// IngestSettings returns the town's ingest settings with defaults filled
+// in.
+func (s *TownSettings) IngestSettings() IngestConfig {
+	var c IngestConfig
+	if s != nil && s.Ingest != nil {
+		c = *s.Ingest
+	}
+	if c.Interval == "" {
+		c.Interval = DefaultIngestInterval
+	}
+	if c.Quiet == "" {
+		c.Quiet = DefaultIngestQuiet
+	}
+	if c.MaxDelay == "" {
+		c.MaxDelay = DefaultIngestMaxDelay
+	}
+	if c.MaxBatch <= 0 {
+		c.MaxBatch = DefaultIngestMaxBatch
+	}
+	return c
+}
+
+// IntervalDuration returns Interval as a duration, or the default.
+func (c IngestConfig) IntervalDuration() time.Duration {
+	return ingestDuration(c.Interval, DefaultIngestInterval)
+}
+
+// QuietDuration returns Quiet as a duration, or the default.
+func (c IngestConfig) QuietDuration() time.Duration {
+	return ingestDuration(c.Quiet, DefaultIngestQuiet)
+}
+
+// MaxDelayDuration returns MaxDelay as a duration, or the default.
+func (c IngestConfig) MaxDelayDuration() time.Duration {
+	return ingestDuration(c.MaxDelay, DefaultIngestMaxDelay)
+}
+
+func ingestDuration(s, def string) time.Duration {
+	if d, err := parsePolicyDuration(s); err == nil && d > 0 {
+		return d
+	}
+	d, _ := time.ParseDuration(def)
+	return d
+}
+
+// Validate checks the durations and batch size.
+func (c IngestConfig) Validate() error {
+	for _, f := range []struct{ name, value string }{
+		{"interval", c.Interval},
+		{"quiet", c.Quiet},
+		{"max_delay", c.MaxDelay},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if d, err := parsePolicyDuration(f.value); err != nil || d <= 0 {
+			return fmt.Errorf("ingest.%s: invalid duration %q", f.name, f.value)
+		}
+	}
+	if c.MaxBatch < 0 {
+		return fmt.Errorf("ingest.max_batch: must not be negative")
+	}
+	if c.MaxDelayDuration() < c.QuietDuration() {
+		return fmt.Errorf("ingest.max_delay: must not be shorter than quiet")
+	}
+	return nil
+}
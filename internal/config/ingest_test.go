@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIngestSettings(t *testing.T) {
+	var s *TownSettings
+	c := s.IngestSettings()
+	if c.IntervalDuration() != 5*time.Second || c.QuietDuration() != 2*time.Second ||
+		c.MaxDelayDuration() != 30*time.Second || c.MaxBatch != DefaultIngestMaxBatch {
+		t.Errorf("defaults = %+v", c)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("default config invalid: %v", err)
+	}
+
+	s = &TownSettings{Ingest: &IngestConfig{Interval: "1m", MaxBatch: 10}}
+	c = s.IngestSettings()
+	if c.IntervalDuration() != time.Minute || c.MaxBatch != 10 || c.QuietDuration() != 2*time.Second {
+		t.Errorf("IngestSettings() = %+v", c)
+	}
+
+	for _, bad := range []IngestConfig{
+		{Interval: "soon"},
+		{Quiet: "-1s"},
+		{MaxDelay: "0s"},
+		{MaxBatch: -1},
+		{Quiet: "1m", MaxDelay: "30s"},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", bad)
+		}
+	}
+}
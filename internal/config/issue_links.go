@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultIssueLinkIdleAfter is how long a session's transcript must be idle
+// before it counts as ended for issue comments.
+const DefaultIssueLinkIdleAfter = 30 * time.Minute
+
+// IssueLinksConfig controls session-to-issue back-linking.
+type IssueLinksConfig struct {
+	// Comment posts the session summary on each linked bead once the
+	// session ends.
+	Comment bool `json:"comment,omitempty"`
+
+	// GitHubComments also comments on GitHub issues (#123) using the gh
+	// CLI, run in the session's working directory.
+	GitHubComments bool `json:"github_comments,omitempty"`
+
+	// IdleAfter is how long a transcript must go unwritten before the
+	// session counts as ended. Default "30m".
+	IdleAfter string `json:"idle_after,omitempty"`
+}
+
+// IssueLinkSettings returns the town's issue link settings, or defaults.
+func (s *TownSettings) IssueLinkSettings() IssueLinksConfig {
+	if s == nil || s.IssueLinks == nil {
+		return IssueLinksConfig{}
+	}
+	return *s.IssueLinks
+}
+
+// IdleDuration returns IdleAfter as a duration, or the default.
+func (c IssueLinksConfig) IdleDuration() time.Duration {
+	if d, err := parsePolicyDuration(c.IdleAfter); err == nil && d > 0 {
+		return d
+	}
+	return DefaultIssueLinkIdleAfter
+}
+
+// Validate checks that IdleAfter, when set, is a positive duration.
+func (c IssueLinksConfig) Validate() error {
+	if c.IdleAfter == "" {
+		return nil
+	}
+	if d, err := parsePolicyDuration(c.IdleAfter); err != nil || d <= 0 {
+		return fmt.Errorf("issue_links.idle_after: invalid duration %q", c.IdleAfter)
+	}
+	return nil
+}
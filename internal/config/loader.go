@@ -217,6 +217,11 @@ func validateRigSettings(c *RigSettings) error {
 // ErrInvalidOnConflict indicates an invalid on_conflict strategy.
 var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 
+// Validate checks merge queue settings the way LoadRigSettings does.
+func (c *MergeQueueConfig) Validate() error {
+	return validateMergeQueueConfig(c)
+}
+
 // validateMergeQueueConfig validates a MergeQueueConfig.
 func validateMergeQueueConfig(c *MergeQueueConfig) error {
 	// Validate on_conflict strategy
@@ -655,6 +660,10 @@ func validateMessagingConfig(c *MessagingConfig) error {
 		c.NudgeChannels = make(map[string][]string)
 	}
 
+	if err := c.Delivery.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrMissingField, err)
+	}
+
 	// Validate lists have at least one recipient
 	for name, recipients := range c.Lists {
 		if len(recipients) == 0 {
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default delivery policy values for direct mail.
+const (
+	DefaultMailRetryInterval    = 5 * time.Minute
+	DefaultMailMaxRetryInterval = time.Hour
+	DefaultMailExpireAfter      = 7 * 24 * time.Hour
+)
+
+// DeliveryPolicy controls at-least-once delivery of direct mail: how often
+// messages whose recipient has no active session are retried, and when
+// undelivered messages expire. Durations accept Go syntax plus a "d" suffix
+// for days (e.g., "90s", "30m", "2d").
+type DeliveryPolicy struct {
+	// RetryInterval is the delay before the first retry; each later retry
+	// doubles it, up to MaxRetryInterval. Default "5m".
+	RetryInterval string `json:"retry_interval,omitempty"`
+
+	// MaxRetryInterval caps the retry backoff. Default "1h".
+	MaxRetryInterval string `json:"max_retry_interval,omitempty"`
+
+	// ExpireAfter is how long a message may stay undelivered before it
+	// expires and its sender is told. Default "7d".
+	ExpireAfter string `json:"expire_after,omitempty"`
+
+	// ExpireByPriority overrides ExpireAfter per message priority.
+	// Example: {"low": "2d", "urgent": "30d"}
+	ExpireByPriority map[string]string `json:"expire_by_priority,omitempty"`
+}
+
+// RetryDelay returns how long to wait after the given number of failed
+// attempts (1 = first retry).
+func (p *DeliveryPolicy) RetryDelay(attempts int) time.Duration {
+	base, maxDelay := DefaultMailRetryInterval, DefaultMailMaxRetryInterval
+	if p != nil {
+		if d, err := parsePolicyDuration(p.RetryInterval); err == nil && d > 0 {
+			base = d
+		}
+		if d, err := parsePolicyDuration(p.MaxRetryInterval); err == nil && d > 0 {
+			maxDelay = d
+		}
+	}
+	delay := base
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// Expiry returns how long a message with the given priority may stay
+// undelivered.
+func (p *DeliveryPolicy) Expiry(priority string) time.Duration {
+	if p == nil {
+		return DefaultMailExpireAfter
+	}
+	if d, err := parsePolicyDuration(p.ExpireByPriority[priority]); err == nil && d > 0 {
+		return d
+	}
+	if d, err := parsePolicyDuration(p.ExpireAfter); err == nil && d > 0 {
+		return d
+	}
+	return DefaultMailExpireAfter
+}
+
+// Validate checks that every duration in the policy parses.
+func (p *DeliveryPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	fields := map[string]string{
+		"retry_interval":     p.RetryInterval,
+		"max_retry_interval": p.MaxRetryInterval,
+		"expire_after":       p.ExpireAfter,
+	}
+	for priority, v := range p.ExpireByPriority {
+		fields["expire_by_priority."+priority] = v
+	}
+	for name, v := range fields {
+		if v == "" {
+			continue
+		}
+		if d, err := parsePolicyDuration(v); err != nil || d <= 0 {
+			return fmt.Errorf("delivery.%s: invalid duration %q", name, v)
+		}
+	}
+	return nil
+}
+
+// parsePolicyDuration parses a Go duration or a whole number of days ("2d").
+func parsePolicyDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
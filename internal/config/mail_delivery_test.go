@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeliveryPolicyRetryDelay(t *testing.T) {
+	var defaults *DeliveryPolicy
+	if got := defaults.RetryDelay(1); got != DefaultMailRetryInterval {
+		t.Errorf("default first retry = %v", got)
+	}
+	if got := defaults.RetryDelay(50); got != DefaultMailMaxRetryInterval {
+		t.Errorf("default capped retry = %v", got)
+	}
+
+	p := &DeliveryPolicy{RetryInterval: "1m", MaxRetryInterval: "5m"}
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute}
+	for i, w := range want {
+		if got := p.RetryDelay(i + 1); got != w {
+			t.Errorf("RetryDelay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestDeliveryPolicyExpiry(t *testing.T) {
+	p := &DeliveryPolicy{ExpireAfter: "2d", ExpireByPriority: map[string]string{"urgent": "30d"}}
+	if got := p.Expiry("normal"); got != 48*time.Hour {
+		t.Errorf("Expiry(normal) = %v", got)
+	}
+	if got := p.Expiry("urgent"); got != 30*24*time.Hour {
+		t.Errorf("Expiry(urgent) = %v", got)
+	}
+	var defaults *DeliveryPolicy
+	if got := defaults.Expiry("low"); got != DefaultMailExpireAfter {
+		t.Errorf("default Expiry = %v", got)
+	}
+}
+
+func TestDeliveryPolicyValidate(t *testing.T) {
+	if err := (&DeliveryPolicy{RetryInterval: "90s", ExpireAfter: "7d"}).Validate(); err != nil {
+		t.Errorf("valid policy: %v", err)
+	}
+	if err := (&DeliveryPolicy{ExpireByPriority: map[string]string{"low": "soon"}}).Validate(); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+	if err := (&DeliveryPolicy{RetryInterval: "-1m"}).Validate(); err == nil {
+		t.Error("expected error for negative duration")
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Nightshift defaults.
+const (
+	DefaultNightshiftWindow        = "22:00-06:00"
+	DefaultNightshiftMaxConcurrent = 2
+	DefaultNightshiftNotify        = "overseer"
+)
+
+// NightshiftConfig controls the off-hours batch runner: tasks queued with
+// 'gt nightshift queue' are slung by the daemon during the window.
+type NightshiftConfig struct {
+	// Window is the local time range in which queued tasks may launch,
+	// "HH:MM-HH:MM". It may wrap midnight. Default "22:00-06:00".
+	Window string `json:"window,omitempty"`
+
+	// MaxConcurrent caps how many nightshift tasks run at once. Default 2.
+	MaxConcurrent int `json:"max_concurrent,omitempty"`
+
+	// BudgetUSD stops launching tasks once Gas Town sessions have spent
+	// this much (estimated) since the window opened. 0 means no limit.
+	BudgetUSD float64 `json:"budget_usd,omitempty"`
+
+	// Notify is the mail address that gets the morning report.
+	// Default "overseer".
+	Notify string `json:"notify,omitempty"`
+}
+
+// NightshiftSettings returns the town's nightshift settings with defaults
+// filled in.
+func (s *TownSettings) NightshiftSettings() NightshiftConfig {
+	var c NightshiftConfig
+	if s != nil && s.Nightshift != nil {
+		c = *s.Nightshift
+	}
+	if c.Window == "" {
+		c.Window = DefaultNightshiftWindow
+	}
+	if c.MaxConcurrent <= 0 {
+		c.MaxConcurrent = DefaultNightshiftMaxConcurrent
+	}
+	if c.Notify == "" {
+		c.Notify = DefaultNightshiftNotify
+	}
+	return c
+}
+
+// Validate checks the window and limits.
+func (c NightshiftConfig) Validate() error {
+	if c.Window != "" {
+		if _, err := ParseWindow(c.Window); err != nil {
+			return fmt.Errorf("nightshift.window: %w", err)
+		}
+	}
+	if c.MaxConcurrent < 0 {
+		return fmt.Errorf("nightshift.max_concurrent: must not be negative")
+	}
+	if c.BudgetUSD < 0 {
+		return fmt.Errorf("nightshift.budget_usd: must not be negative")
+	}
+	return nil
+}
+
+// Window is a daily local time range. End before Start wraps midnight.
+type Window struct {
+	Start time.Duration // offset from midnight
+	End   time.Duration
+}
+
+// ParseWindow parses "HH:MM-HH:MM".
+func ParseWindow(s string) (Window, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return Window{}, fmt.Errorf("invalid window %q (want HH:MM-HH:MM)", s)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid window %q: %w", s, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid window %q: %w", s, err)
+	}
+	if start == end {
+		return Window{}, fmt.Errorf("invalid window %q: empty range", s)
+	}
+	return Window{Start: start, End: end}, nil
+}
+
+// parseClock parses "HH:MM" as an offset from midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Opened returns when the window containing t opened, and whether t is
+// inside the window at all.
+func (w Window) Opened(t time.Time) (time.Time, bool) {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	if w.Start < w.End {
+		if offset >= w.Start && offset < w.End {
+			return midnight.Add(w.Start), true
+		}
+		return time.Time{}, false
+	}
+	// Wraps midnight: after Start today, or before End (opened yesterday)
+	if offset >= w.Start {
+		return midnight.Add(w.Start), true
+	}
+	if offset < w.End {
+		return midnight.AddDate(0, 0, -1).Add(w.Start), true
+	}
+	return time.Time{}, false
+}
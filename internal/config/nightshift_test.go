@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowOpened(t *testing.T) {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2025, 3, day, hour, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		window string
+		now    time.Time
+		opened time.Time
+		in     bool
+	}{
+		{"22:00-06:00", at(10, 23, 0), at(10, 22, 0), true},
+		{"22:00-06:00", at(11, 5, 59), at(10, 22, 0), true},
+		{"22:00-06:00", at(11, 6, 0), time.Time{}, false},
+		{"22:00-06:00", at(11, 14, 0), time.Time{}, false},
+		{"01:00-05:30", at(11, 3, 0), at(11, 1, 0), true},
+		{"01:00-05:30", at(11, 0, 30), time.Time{}, false},
+	}
+	for _, tt := range tests {
+		w, err := ParseWindow(tt.window)
+		if err != nil {
+			t.Fatalf("ParseWindow(%q): %v", tt.window, err)
+		}
+		opened, in := w.Opened(tt.now)
+		if in != tt.in || !opened.Equal(tt.opened) {
+			t.Errorf("%s at %s: got (%s, %v), want (%s, %v)", tt.window, tt.now.Format("15:04"), opened, in, tt.opened, tt.in)
+		}
+	}
+}
+
+func TestNightshiftValidate(t *testing.T) {
+	for _, bad := range []NightshiftConfig{
+		{Window: "22:00"},
+		{Window: "25:00-06:00"},
+		{Window: "06:00-06:00"},
+		{MaxConcurrent: -1},
+		{BudgetUSD: -5},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", bad)
+		}
+	}
+
+	var s *TownSettings
+	c := s.NightshiftSettings()
+	if c.Window != DefaultNightshiftWindow || c.MaxConcurrent != DefaultNightshiftMaxConcurrent || c.Notify != DefaultNightshiftNotify {
+		t.Errorf("defaults = %+v", c)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("default config invalid: %v", err)
+	}
+}
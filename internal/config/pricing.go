@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ModelPrice is the price of one model's tokens, in USD per million tokens.
+type ModelPrice struct {
+	// Model is matched case-insensitively as a substring of the model ID,
+	// so "opus" prices every Opus model and "claude-opus-4-1" just that one.
+	Model string `json:"model"`
+
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheWrite float64 `json:"cache_write"`
+	CacheRead  float64 `json:"cache_read"`
+
+	// EffectiveFrom is the first day (YYYY-MM-DD, UTC) the price applies.
+	// Empty means it has always applied.
+	EffectiveFrom string `json:"effective_from,omitempty"`
+}
+
+// Effective returns the parsed EffectiveFrom date (zero if unset).
+func (p ModelPrice) Effective() (time.Time, error) {
+	if p.EffectiveFrom == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", p.EffectiveFrom)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid effective_from %q for %s: %w", p.EffectiveFrom, p.Model, err)
+	}
+	return t, nil
+}
+
+// DefaultModel prices usage from models no pricing entry matches.
+const DefaultModel = "sonnet"
+
+// DefaultPricing are list prices by model family.
+var DefaultPricing = []ModelPrice{
+	{Model: "opus", Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.50},
+	{Model: "sonnet", Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.30},
+	{Model: "haiku", Input: 0.80, Output: 4, CacheWrite: 1, CacheRead: 0.08},
+}
+
+// PriceTable resolves model prices. Custom entries from town settings take
+// precedence over the built-in list prices.
+type PriceTable struct {
+	Custom  []ModelPrice
+	Builtin []ModelPrice
+}
+
+// PriceTable returns the town's pricing: the "pricing" entries from
+// settings/config.json layered over DefaultPricing. Nil settings yield the
+// defaults alone.
+func (s *TownSettings) PriceTable() PriceTable {
+	table := PriceTable{Builtin: DefaultPricing}
+	if s != nil {
+		table.Custom = s.Pricing
+	}
+	return table
+}
+
+// Lookup returns the price for model at time at, and whether it came from
+// a custom entry. Among matching entries in effect at that time, the most
+// specific model wins, then the latest effective date. A zero at means now.
+// Models nothing matches are priced as DefaultModel.
+func (t PriceTable) Lookup(model string, at time.Time) (price ModelPrice, custom bool) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if p, ok := lookupPrice(t.Custom, model, at); ok {
+		return p, true
+	}
+	if p, ok := lookupPrice(t.Builtin, model, at); ok {
+		return p, false
+	}
+	if p, ok := lookupPrice(t.Custom, DefaultModel, at); ok {
+		return p, true
+	}
+	p, _ := lookupPrice(t.Builtin, DefaultModel, at)
+	return p, false
+}
+
+// Cost returns the USD cost of the given token counts for model at time at.
+func (t PriceTable) Cost(model string, at time.Time, input, output, cacheWrite, cacheRead int64) float64 {
+	p, _ := t.Lookup(model, at)
+	const perMillion = 1_000_000
+	return (float64(input)*p.Input +
+		float64(output)*p.Output +
+		float64(cacheWrite)*p.CacheWrite +
+		float64(cacheRead)*p.CacheRead) / perMillion
+}
+
+// Validate reports entries with a missing model, negative rates, or an
+// unparseable effective date.
+func (t PriceTable) Validate() error {
+	for _, p := range t.Custom {
+		if strings.TrimSpace(p.Model) == "" {
+			return fmt.Errorf("pricing entry with no model")
+		}
+		if p.Input < 0 || p.Output < 0 || p.CacheWrite < 0 || p.CacheRead < 0 {
+			return fmt.Errorf("negative price for %s", p.Model)
+		}
+		if _, err := p.Effective(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupPrice finds the best entry in prices for model at time at.
+func lookupPrice(prices []ModelPrice, model string, at time.Time) (ModelPrice, bool) {
+	model = strings.ToLower(model)
+	var best ModelPrice
+	var bestKey string
+	var bestFrom time.Time
+	found := false
+	for _, p := range prices {
+		key := strings.ToLower(strings.TrimSpace(p.Model))
+		if key == "" || !strings.Contains(model, key) {
+			continue
+		}
+		from, err := p.Effective()
+		if err != nil || from.After(at) {
+			continue
+		}
+		if !found || len(key) > len(bestKey) || (len(key) == len(bestKey) && from.After(bestFrom)) {
+			best, bestKey, bestFrom, found = p, key, from, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPriceTableLookup(t *testing.T) {
+	settings := &TownSettings{Pricing: []ModelPrice{
+		{Model: "opus", Input: 12, Output: 60},
+		{Model: "opus", Input: 10, Output: 50, EffectiveFrom: "2025-06-01"},
+		{Model: "claude-opus-4-1", Input: 20, Output: 80},
+	}}
+	table := settings.PriceTable()
+	may := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	july := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		model      string
+		at         time.Time
+		wantInput  float64
+		wantCustom bool
+	}{
+		{"custom before price change", "claude-opus-4", may, 12, true},
+		{"custom after price change", "claude-opus-4", july, 10, true},
+		{"most specific model wins", "claude-opus-4-1-20250805", july, 20, true},
+		{"builtin when no custom match", "claude-haiku-3-5", july, 0.80, false},
+		{"unknown model priced as default", "mystery", july, 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, custom := table.Lookup(tt.model, tt.at)
+			if p.Input != tt.wantInput || custom != tt.wantCustom {
+				t.Errorf("Lookup(%q) = input %v custom %v, want %v %v", tt.model, p.Input, custom, tt.wantInput, tt.wantCustom)
+			}
+		})
+	}
+}
+
+func TestPriceTableCost(t *testing.T) {
+	table := (*TownSettings)(nil).PriceTable()
+	got := table.Cost("claude-sonnet-4", time.Time{}, 1_000_000, 1_000_000, 1_000_000, 1_000_000)
+	if want := 3 + 15 + 3.75 + 0.30; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Cost = %v, want %v", got, want)
+	}
+}
+
+func TestPriceTableValidate(t *testing.T) {
+	bad := []ModelPrice{
+		{Model: "", Input: 1},
+		{Model: "opus", Input: -1},
+		{Model: "opus", EffectiveFrom: "June 1"},
+	}
+	for _, p := range bad {
+		table := (&TownSettings{Pricing: []ModelPrice{p}}).PriceTable()
+		if err := table.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", p)
+		}
+	}
+	if err := (&TownSettings{Pricing: []ModelPrice{{Model: "opus", EffectiveFrom: "2025-06-01"}}}).PriceTable().Validate(); err != nil {
+		t.Errorf("Validate(valid) = %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ResolveRigAlias maps a rig alias back to its full rig name.
+// Names that are not aliases (including full rig names) are returned unchanged.
+func (s *TownSettings) ResolveRigAlias(name string) string {
+	if s == nil || name == "" {
+		return name
+	}
+	if _, ok := s.RigAliases[name]; ok {
+		return name
+	}
+	for rig, alias := range s.RigAliases {
+		if alias == name {
+			return rig
+		}
+	}
+	return name
+}
+
+// RigAlias returns the compact display name for a rig.
+// Rigs without an alias are returned unchanged.
+func (s *TownSettings) RigAlias(rig string) string {
+	if s == nil {
+		return rig
+	}
+	if alias, ok := s.RigAliases[rig]; ok && alias != "" {
+		return alias
+	}
+	return rig
+}
+
+// CompactAddress replaces the rig component of an agent address
+// (e.g., "gastown/crew/joe" -> "gt/crew/joe") with its alias.
+func (s *TownSettings) CompactAddress(address string) string {
+	rig, rest, found := strings.Cut(address, "/")
+	if !found {
+		return s.RigAlias(address)
+	}
+	return s.RigAlias(rig) + "/" + rest
+}
+
+// CheckRigAlias reports why alias can't name rig: it contains '/', is
+// already another rig's alias, or is the name of an existing rig, which
+// the alias would hide from --rig flags. rigs lists the town's rigs.
+func (s *TownSettings) CheckRigAlias(rig, alias string, rigs []string) error {
+	if alias == "" {
+		return fmt.Errorf("alias cannot be empty")
+	}
+	if strings.Contains(alias, "/") {
+		return fmt.Errorf("alias '%s' cannot contain '/'", alias)
+	}
+	if alias != rig && slices.Contains(rigs, alias) {
+		return fmt.Errorf("alias '%s' is the name of an existing rig", alias)
+	}
+	if s != nil {
+		for other, existing := range s.RigAliases {
+			if existing == alias && other != rig {
+				return fmt.Errorf("alias '%s' is already used by rig '%s'", alias, other)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import "testing"
+
+func TestRigAliases(t *testing.T) {
+	t.Parallel()
+	s := &TownSettings{
+		RigAliases: map[string]string{
+			"gastown":  "gt",
+			"payments": "pay",
+		},
+	}
+
+	tests := []struct {
+		in, resolved, alias string
+	}{
+		{"gt", "gastown", "gt"},
+		{"gastown", "gastown", "gt"},
+		{"pay", "payments", "pay"},
+		{"beads", "beads", "beads"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := s.ResolveRigAlias(tt.in); got != tt.resolved {
+			t.Errorf("ResolveRigAlias(%q) = %q, want %q", tt.in, got, tt.resolved)
+		}
+		if got := s.RigAlias(tt.resolved); got != tt.alias {
+			t.Errorf("RigAlias(%q) = %q, want %q", tt.resolved, got, tt.alias)
+		}
+	}
+
+	if got := s.CompactAddress("gastown/crew/joe"); got != "gt/crew/joe" {
+		t.Errorf("CompactAddress = %q, want %q", got, "gt/crew/joe")
+	}
+	if got := s.CompactAddress("mayor"); got != "mayor" {
+		t.Errorf("CompactAddress = %q, want %q", got, "mayor")
+	}
+
+	var nilSettings *TownSettings
+	if got := nilSettings.ResolveRigAlias("gt"); got != "gt" {
+		t.Errorf("nil ResolveRigAlias = %q, want %q", got, "gt")
+	}
+}
+
+func TestAssignGuardMode(t *testing.T) {
+	var nilSettings *TownSettings
+	if got := nilSettings.AssignGuardMode(); got != AssignGuardBlock {
+		t.Errorf("nil settings mode = %q, want block", got)
+	}
+	for value, want := range map[string]string{
+		"":      AssignGuardBlock,
+		"warn":  AssignGuardWarn,
+		"off":   AssignGuardOff,
+		"bogus": AssignGuardBlock,
+	} {
+		s := &TownSettings{AssignGuard: value}
+		if got := s.AssignGuardMode(); got != want {
+			t.Errorf("AssignGuardMode(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestCheckRigAlias(t *testing.T) {
+	t.Parallel()
+	s := &TownSettings{RigAliases: map[string]string{"gastown": "gt"}}
+	rigs := []string{"gastown", "payments", "beads"}
+
+	tests := []struct {
+		rig, alias string
+		ok         bool
+	}{
+		{"payments", "pay", true},
+		{"gastown", "gt", true},      // re-setting its own alias
+		{"payments", "gt", false},    // another rig's alias
+		{"payments", "beads", false}, // would hide the beads rig
+		{"payments", "a/b", false},   // not a single path component
+		{"payments", "", false},
+		{"payments", "payments", true}, // a rig may alias itself
+	}
+	for _, tt := range tests {
+		err := s.CheckRigAlias(tt.rig, tt.alias, rigs)
+		if (err == nil) != tt.ok {
+			t.Errorf("CheckRigAlias(%q, %q) = %v, want ok=%v", tt.rig, tt.alias, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import "os"
+
+// ServerConfig controls access to gt's HTTP server when it is exposed
+// beyond the local machine.
+type ServerConfig struct {
+	// Tokens are the bearer tokens the server accepts. With none, the
+	// server is open, as it is for local use.
+	Tokens []ServerToken `json:"tokens,omitempty"`
+}
+
+// ServerToken is one API token.
+type ServerToken struct {
+	// Name identifies the token's holder in the audit log.
+	Name string `json:"name"`
+
+	// Token is the secret itself, or TokenEnv names the environment
+	// variable holding it, which keeps it out of the settings file.
+	Token    string `json:"token,omitempty"`
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// Role is "viewer" (the default), which may read, or "operator",
+	// which may also take actions such as spawning or killing sessions
+	// and sending mail.
+	Role string `json:"role,omitempty"`
+}
+
+// Secret returns the token's secret, read from TokenEnv when set.
+func (t ServerToken) Secret() string {
+	if t.TokenEnv != "" {
+		return os.Getenv(t.TokenEnv)
+	}
+	return t.Token
+}
+
+// ServerTokens returns the town's server tokens. Nil settings yield none.
+func (s *TownSettings) ServerTokens() []ServerToken {
+	if s == nil || s.Server == nil {
+		return nil
+	}
+	return s.Server.Tokens
+}
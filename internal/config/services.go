@@ -0,0 +1,15 @@
+package config
+
+// ServiceConfig is a service a rig's agents depend on, such as a database
+// or dev server. Set Address, URL, or both.
+type ServiceConfig struct {
+	Name string `json:"name"`
+
+	// Address is a host:port that must accept TCP connections
+	// (e.g., "localhost:5432").
+	Address string `json:"address,omitempty"`
+
+	// URL is an HTTP(S) endpoint that must answer without a server error
+	// (e.g., "http://localhost:3000/health").
+	URL string `json:"url,omitempty"`
+}
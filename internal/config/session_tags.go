@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SessionTagRule tags sessions automatically. A rule applies when every
+// condition it sets matches; at least one of Path or Topic is required.
+type SessionTagRule struct {
+	// Path is a glob matched against the session's working directory.
+	// "*" matches any run of characters, including "/", so
+	// "*/experiments/*" matches every session under an experiments
+	// directory. "?" matches one character.
+	Path string `json:"path,omitempty"`
+
+	// Topic is a regular expression matched against the session's beacon
+	// topic, or its summary when it has no beacon topic.
+	Topic string `json:"topic,omitempty"`
+
+	// Tag is the label applied to matching sessions.
+	Tag string `json:"tag"`
+}
+
+// SessionTagger applies a town's compiled session tag rules.
+type SessionTagger struct {
+	rules []compiledTagRule
+}
+
+type compiledTagRule struct {
+	path  *regexp.Regexp
+	topic *regexp.Regexp
+	tag   string
+}
+
+// SessionTagger compiles the town's "session_tags" rules. Nil settings
+// yield a tagger that tags nothing.
+func (s *TownSettings) SessionTagger() (SessionTagger, error) {
+	if s == nil {
+		return SessionTagger{}, nil
+	}
+	return CompileSessionTags(s.SessionTags)
+}
+
+// CompileSessionTags validates and compiles tag rules.
+func CompileSessionTags(rules []SessionTagRule) (SessionTagger, error) {
+	var tagger SessionTagger
+	for i, rule := range rules {
+		tag := strings.TrimSpace(rule.Tag)
+		if tag == "" {
+			return SessionTagger{}, fmt.Errorf("session_tags[%d]: tag is required", i)
+		}
+		if rule.Path == "" && rule.Topic == "" {
+			return SessionTagger{}, fmt.Errorf("session_tags[%d] (%s): path or topic is required", i, tag)
+		}
+		compiled := compiledTagRule{tag: tag}
+		if rule.Path != "" {
+			compiled.path = globToRegexp(rule.Path)
+		}
+		if rule.Topic != "" {
+			re, err := regexp.Compile(rule.Topic)
+			if err != nil {
+				return SessionTagger{}, fmt.Errorf("session_tags[%d] (%s): invalid topic: %w", i, tag, err)
+			}
+			compiled.topic = re
+		}
+		tagger.rules = append(tagger.rules, compiled)
+	}
+	return tagger, nil
+}
+
+// Tags returns the sorted, de-duplicated tags for a session with the given
+// working directory and topic.
+func (t SessionTagger) Tags(path, topic string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, rule := range t.rules {
+		if seen[rule.tag] {
+			continue
+		}
+		if rule.path != nil && !rule.path.MatchString(path) && !rule.path.MatchString(path+"/") {
+			continue
+		}
+		if rule.topic != nil && (topic == "" || !rule.topic.MatchString(topic)) {
+			continue
+		}
+		seen[rule.tag] = true
+		tags = append(tags, rule.tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// globToRegexp converts a path glob to an anchored regular expression.
+func globToRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSessionTaggerTags(t *testing.T) {
+	settings := &TownSettings{SessionTags: []SessionTagRule{
+		{Path: "*/experiments/*", Tag: "experiment"},
+		{Path: "/home/u/gt/gastown/*", Topic: "(?i)spike", Tag: "spike"},
+		{Topic: "handoff", Tag: "handoff"},
+	}}
+	tagger, err := settings.SessionTagger()
+	if err != nil {
+		t.Fatalf("SessionTagger: %v", err)
+	}
+
+	tests := []struct {
+		path, topic string
+		want        []string
+	}{
+		{"/home/u/experiments/parser", "", []string{"experiment"}},
+		{"/home/u/experiments", "", []string{"experiment"}},
+		{"/home/u/experiment-log", "", nil},
+		{"/home/u/gt/gastown/crew/joe", "SPIKE: new cache", []string{"spike"}},
+		{"/home/u/other", "spike", nil},
+		{"/home/u/experiments/x", "handoff", []string{"experiment", "handoff"}},
+	}
+	for _, tt := range tests {
+		if got := tagger.Tags(tt.path, tt.topic); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tags(%q, %q) = %v, want %v", tt.path, tt.topic, got, tt.want)
+		}
+	}
+
+	var none *TownSettings
+	if tagger, err := none.SessionTagger(); err != nil || tagger.Tags("/x/experiments/y", "") != nil {
+		t.Errorf("nil settings should tag nothing")
+	}
+}
+
+func TestCompileSessionTagsInvalid(t *testing.T) {
+	bad := [][]SessionTagRule{
+		{{Path: "*/x/*"}},
+		{{Tag: "empty"}},
+		{{Topic: "(", Tag: "broken"}},
+	}
+	for _, rules := range bad {
+		if _, err := CompileSessionTags(rules); err == nil {
+			t.Errorf("CompileSessionTags(%+v) succeeded, want error", rules)
+		}
+	}
+}
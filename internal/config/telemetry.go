@@ -0,0 +1,13 @@
+package config
+
+// TelemetryConfig controls the local command timing log.
+type TelemetryConfig struct {
+	// Enabled records each gt command's latency and work counters to
+	// .runtime/perf.jsonl.
+	Enabled bool `json:"enabled"`
+}
+
+// TelemetryEnabled reports whether the town opted in to command timing.
+func (s *TownSettings) TelemetryEnabled() bool {
+	return s != nil && s.Telemetry != nil && s.Telemetry.Enabled
+}
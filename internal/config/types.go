@@ -49,6 +49,87 @@ type TownSettings struct {
 	// Values override or extend the built-in presets.
 	// Example: {"gemini": {"command": "/custom/path/to/gemini"}}
 	Agents map[string]*RuntimeConfig `json:"agents,omitempty"`
+
+	// RigAliases maps full rig names to short aliases.
+	// Aliases are accepted anywhere a --rig flag is, and tables display
+	// the compact form so long rig names don't blow out column widths.
+	// Example: {"gastown": "gt", "payments": "pay"}
+	RigAliases map[string]string `json:"rig_aliases,omitempty"`
+
+	// CommandAliases defines gt subcommand shortcuts shared by the town.
+	// An alias expands to the command line it maps to, with any further
+	// arguments appended. Built-in commands always win over an alias of
+	// the same name; each user's ~/.config/gastown/aliases.json overrides
+	// the town's.
+	// Example: {"s": "seance --recent 10", "wip": "seance --rig gastown --since 1d"}
+	CommandAliases map[string]string `json:"command_aliases,omitempty"`
+
+	// AssignGuard controls what happens when work is slung to an issue that
+	// already has an active assignee: "block" (default) refuses unless
+	// --force is given, "warn" prints a warning and proceeds, "off" skips
+	// the check.
+	AssignGuard string `json:"assign_guard,omitempty"`
+
+	// Pricing overrides model token prices used for cost estimates, e.g.
+	// negotiated discounts or price changes. Entries are effective-dated;
+	// models without a matching entry use DefaultPricing.
+	// Example: [{"model": "opus", "input": 12, "output": 60,
+	//            "cache_write": 15, "cache_read": 1.2, "effective_from": "2025-06-01"}]
+	Pricing []ModelPrice `json:"pricing,omitempty"`
+
+	// SessionTags labels sessions automatically by working directory or
+	// topic, so they can be filtered without manual tagging.
+	// Example: [{"path": "*/experiments/*", "tag": "experiment"},
+	//           {"topic": "(?i)spike", "tag": "spike"}]
+	SessionTags []SessionTagRule `json:"session_tags,omitempty"`
+
+	// ClaudeRoots lists more Claude data directories for session discovery
+	// to scan alongside this user's, e.g. other OS users' homes or homes
+	// mounted from other machines. A home directory stands for its .claude.
+	// Example: ["/home/agents/.claude", "/mnt/buildbox/home/ci"]
+	ClaudeRoots []string `json:"claude_roots,omitempty"`
+
+	// IssueLinks controls linking sessions to the issues their beacon or
+	// prompt mentions. Links are always recorded; comments are opt-in.
+	// Example: {"comment": true, "github_comments": true, "idle_after": "30m"}
+	IssueLinks *IssueLinksConfig `json:"issue_links,omitempty"`
+
+	// Telemetry enables the local command timing log read by gt perf.
+	// Nothing leaves the machine. Off unless set.
+	// Example: {"enabled": true}
+	Telemetry *TelemetryConfig `json:"telemetry,omitempty"`
+
+	// Nightshift controls when and how queued off-hours tasks launch.
+	// Example: {"window": "22:00-06:00", "max_concurrent": 2, "budget_usd": 25}
+	Nightshift *NightshiftConfig `json:"nightshift,omitempty"`
+
+	// Ingest controls how the daemon batches session index updates.
+	// Example: {"interval": "5s", "quiet": "2s", "max_delay": "30s", "max_batch": 50}
+	Ingest *IngestConfig `json:"ingest,omitempty"`
+
+	// Server controls who may use gt's HTTP server and what they may do.
+	// Example: {"tokens": [{"name": "alice", "token_env": "GT_TOKEN_ALICE", "role": "operator"}]}
+	Server *ServerConfig `json:"server,omitempty"`
+}
+
+// Assign guard modes for TownSettings.AssignGuard.
+const (
+	AssignGuardBlock = "block"
+	AssignGuardWarn  = "warn"
+	AssignGuardOff   = "off"
+)
+
+// AssignGuardMode returns the effective assign guard mode.
+// Nil settings and unknown values fall back to AssignGuardBlock.
+func (s *TownSettings) AssignGuardMode() string {
+	if s == nil {
+		return AssignGuardBlock
+	}
+	switch s.AssignGuard {
+	case AssignGuardWarn, AssignGuardOff:
+		return s.AssignGuard
+	}
+	return AssignGuardBlock
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -209,6 +290,18 @@ type RigSettings struct {
 	// Similar to TownSettings.Agents but applies to this rig only.
 	// Allows per-rig custom agents for polecats and crew members.
 	Agents map[string]*RuntimeConfig `json:"agents,omitempty"`
+
+	// Claude lists requirements on the installed Claude Code, checked by
+	// 'gt claude info'.
+	Claude *ClaudeRequirements `json:"claude,omitempty"`
+
+	// Clean sets the disk quota for agent worktrees, enforced by
+	// 'gt clean'.
+	Clean *CleanConfig `json:"clean,omitempty"`
+
+	// Services are what the rig's agents need running, checked before
+	// 'gt seance resume'.
+	Services []ServiceConfig `json:"services,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -709,7 +802,7 @@ const CurrentAccountsVersion = 1
 // DefaultAccountsConfigDir returns the default base directory for account configs.
 func DefaultAccountsConfigDir() string {
 	home, _ := os.UserHomeDir()
-	return home + "/.claude-accounts"
+	return filepath.Join(home, ".claude-accounts")
 }
 
 // MessagingConfig represents the messaging configuration (config/messaging.json).
@@ -737,6 +830,10 @@ type MessagingConfig struct {
 	// Like mailing lists but for tmux send-keys instead of durable mail.
 	// Example: {"workers": ["gastown/polecats/*", "gastown/crew/*"], "witnesses": ["*/witness"]}
 	NudgeChannels map[string][]string `json:"nudge_channels,omitempty"`
+
+	// Delivery controls retries and expiry for direct mail.
+	// Example: {"retry_interval": "5m", "expire_after": "7d"}
+	Delivery *DeliveryPolicy `json:"delivery,omitempty"`
 }
 
 // QueueConfig represents a work queue configuration.
@@ -2,7 +2,10 @@
 // Centralizing these magic strings improves maintainability and consistency.
 package constants
 
-import "time"
+import (
+	"path/filepath"
+	"time"
+)
 
 // Timing constants for session management and tmux operations.
 const (
@@ -189,55 +192,55 @@ var SupportedShells = []string{"bash", "zsh", "sh", "fish", "tcsh", "ksh"}
 
 // MayorRigsPath returns the path to rigs.json within a town root.
 func MayorRigsPath(townRoot string) string {
-	return townRoot + "/" + DirMayor + "/" + FileRigsJSON
+	return filepath.Join(townRoot, DirMayor, FileRigsJSON)
 }
 
 // MayorTownPath returns the path to town.json within a town root.
 func MayorTownPath(townRoot string) string {
-	return townRoot + "/" + DirMayor + "/" + FileTownJSON
+	return filepath.Join(townRoot, DirMayor, FileTownJSON)
 }
 
 // RigMayorPath returns the path to mayor/rig within a rig.
 func RigMayorPath(rigPath string) string {
-	return rigPath + "/" + DirMayor + "/" + DirRig
+	return filepath.Join(rigPath, DirMayor, DirRig)
 }
 
 // RigBeadsPath returns the path to mayor/rig/.beads within a rig.
 func RigBeadsPath(rigPath string) string {
-	return rigPath + "/" + DirMayor + "/" + DirRig + "/" + DirBeads
+	return filepath.Join(rigPath, DirMayor, DirRig, DirBeads)
 }
 
 // RigPolecatsPath returns the path to polecats/ within a rig.
 func RigPolecatsPath(rigPath string) string {
-	return rigPath + "/" + DirPolecats
+	return filepath.Join(rigPath, DirPolecats)
 }
 
 // RigCrewPath returns the path to crew/ within a rig.
 func RigCrewPath(rigPath string) string {
-	return rigPath + "/" + DirCrew
+	return filepath.Join(rigPath, DirCrew)
 }
 
 // MayorConfigPath returns the path to mayor/config.json within a town root.
 func MayorConfigPath(townRoot string) string {
-	return townRoot + "/" + DirMayor + "/" + FileConfigJSON
+	return filepath.Join(townRoot, DirMayor, FileConfigJSON)
 }
 
 // TownRuntimePath returns the path to .runtime/ at the town root.
 func TownRuntimePath(townRoot string) string {
-	return townRoot + "/" + DirRuntime
+	return filepath.Join(townRoot, DirRuntime)
 }
 
 // RigRuntimePath returns the path to .runtime/ within a rig.
 func RigRuntimePath(rigPath string) string {
-	return rigPath + "/" + DirRuntime
+	return filepath.Join(rigPath, DirRuntime)
 }
 
 // RigSettingsPath returns the path to settings/ within a rig.
 func RigSettingsPath(rigPath string) string {
-	return rigPath + "/" + DirSettings
+	return filepath.Join(rigPath, DirSettings)
 }
 
 // MayorAccountsPath returns the path to mayor/accounts.json within a town root.
 func MayorAccountsPath(townRoot string) string {
-	return townRoot + "/" + DirMayor + "/" + FileAccountsJSON
+	return filepath.Join(townRoot, DirMayor, FileAccountsJSON)
 }
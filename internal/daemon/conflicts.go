@@ -0,0 +1,153 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// conflictWindow is how close together two agents' edits to the same file
+// must be to count as a conflict. Sessions idle for longer are ignored.
+const conflictWindow = 30 * time.Minute
+
+// sessionEdits is the recent edit activity of one agent session.
+type sessionEdits struct {
+	SessionID string
+	Agent     string // beacon address, e.g. "gastown/polecats/toast"
+	Rig       string
+	Edits     []claude.FileEdit
+}
+
+// editConflict is a file edited by two agents in the same rig within
+// conflictWindow of each other.
+type editConflict struct {
+	Rig      string
+	File     string
+	Agents   [2]string
+	Sessions [2]string
+}
+
+// key identifies a conflict independent of agent order, for deduplication.
+func (c editConflict) key() string {
+	agents := []string{c.Agents[0], c.Agents[1]}
+	sort.Strings(agents)
+	return c.Rig + "|" + c.File + "|" + strings.Join(agents, "|")
+}
+
+// checkEditConflicts warns when two active agents in a rig edit the same
+// file close together, before the overlap turns into a merge conflict.
+// Each conflict is reported once per conflictWindow.
+func (d *Daemon) checkEditConflicts() {
+	since := time.Now().Add(-conflictWindow)
+
+	sessions, err := claude.DiscoverSessions(claude.SessionFilter{GasTownOnly: true})
+	if err != nil {
+		d.logger.Printf("Conflict check: discovering sessions: %v", err)
+		return
+	}
+
+	var active []sessionEdits
+	for _, s := range sessions {
+		rig, _, ok := strings.Cut(s.Role, "/")
+		if !ok {
+			continue // Town-level agents (mayor, deacon) have no rig
+		}
+		if stat, err := os.Stat(s.Path); err != nil || stat.ModTime().Before(since) {
+			continue
+		}
+		edits, err := claude.ReadFileEdits(s.Path, since)
+		if err != nil || len(edits) == 0 {
+			continue
+		}
+		active = append(active, sessionEdits{SessionID: s.ID, Agent: s.Role, Rig: rig, Edits: edits})
+	}
+
+	if d.reportedConflicts == nil {
+		d.reportedConflicts = make(map[string]time.Time)
+	}
+	for key, at := range d.reportedConflicts {
+		if at.Before(since) {
+			delete(d.reportedConflicts, key)
+		}
+	}
+
+	for _, c := range detectEditConflicts(active, conflictWindow) {
+		key := c.key()
+		if _, seen := d.reportedConflicts[key]; seen {
+			continue
+		}
+		d.reportedConflicts[key] = time.Now()
+		d.reportEditConflict(c)
+	}
+}
+
+// detectEditConflicts finds files edited by two different agents of the
+// same rig within window of each other.
+func detectEditConflicts(sessions []sessionEdits, window time.Duration) []editConflict {
+	var conflicts []editConflict
+	seen := make(map[string]bool)
+
+	for i := 0; i < len(sessions); i++ {
+		for j := i + 1; j < len(sessions); j++ {
+			a, b := sessions[i], sessions[j]
+			if a.Rig != b.Rig || a.Agent == b.Agent {
+				continue
+			}
+			for _, ea := range a.Edits {
+				for _, eb := range b.Edits {
+					if ea.RelPath != eb.RelPath {
+						continue
+					}
+					gap := ea.Timestamp.Sub(eb.Timestamp)
+					if gap < 0 {
+						gap = -gap
+					}
+					if gap > window {
+						continue
+					}
+					c := editConflict{
+						Rig:      a.Rig,
+						File:     ea.RelPath,
+						Agents:   [2]string{a.Agent, b.Agent},
+						Sessions: [2]string{a.SessionID, b.SessionID},
+					}
+					if !seen[c.key()] {
+						seen[c.key()] = true
+						conflicts = append(conflicts, c)
+					}
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// reportEditConflict emits a feed event and mails both agents.
+func (d *Daemon) reportEditConflict(c editConflict) {
+	d.logger.Printf("EDIT CONFLICT in %s: %s edited by %s and %s", c.Rig, c.File, c.Agents[0], c.Agents[1])
+
+	_ = events.LogFeed(events.TypeEditConflict, "daemon",
+		events.EditConflictPayload(c.Rig, c.File, c.Agents[:], c.Sessions[:]))
+
+	for i, agent := range c.Agents {
+		other := c.Agents[1-i]
+		subject := fmt.Sprintf("EDIT_CONFLICT: %s also being edited by %s", c.File, other)
+		body := fmt.Sprintf(`You and %s both edited %s in the last %s.
+
+Coordinate before continuing to avoid a merge conflict:
+  gt mail send %s -s "re: %s"`,
+			other, c.File, conflictWindow, other, c.File)
+
+		cmd := exec.Command("gt", "mail", "send", agent, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = d.config.TownRoot
+		if err := cmd.Run(); err != nil {
+			d.logger.Printf("Warning: failed to notify %s of edit conflict: %v", agent, err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+func TestDetectEditConflicts(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	edit := func(rel string, offset time.Duration) claude.FileEdit {
+		return claude.FileEdit{RelPath: rel, Timestamp: base.Add(offset)}
+	}
+
+	sessions := []sessionEdits{
+		{SessionID: "s1", Agent: "gastown/polecats/toast", Rig: "gastown",
+			Edits: []claude.FileEdit{edit("internal/a.go", 0), edit("internal/b.go", 0)}},
+		{SessionID: "s2", Agent: "gastown/polecats/nux", Rig: "gastown",
+			Edits: []claude.FileEdit{edit("internal/a.go", 10*time.Minute), edit("internal/b.go", 2*time.Hour)}},
+		// Same file, different rig: not a conflict
+		{SessionID: "s3", Agent: "beads/crew/joe", Rig: "beads",
+			Edits: []claude.FileEdit{edit("internal/a.go", 0)}},
+		// Same agent in a second session: not a conflict with itself
+		{SessionID: "s4", Agent: "gastown/polecats/toast", Rig: "gastown",
+			Edits: []claude.FileEdit{edit("internal/a.go", 0)}},
+	}
+
+	conflicts := detectEditConflicts(sessions, 30*time.Minute)
+	if len(conflicts) != 1 {
+		t.Fatalf("got %d conflicts, want 1: %+v", len(conflicts), conflicts)
+	}
+	c := conflicts[0]
+	if c.File != "internal/a.go" || c.Rig != "gastown" {
+		t.Errorf("conflict = %+v", c)
+	}
+
+	swapped := editConflict{Rig: c.Rig, File: c.File, Agents: [2]string{c.Agents[1], c.Agents[0]}}
+	if swapped.key() != c.key() {
+		t.Error("conflict key should not depend on agent order")
+	}
+}
@@ -0,0 +1,51 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// crashLookback is how far back checkCrashedSessions looks. Sessions
+// quiet for longer are abandoned rather than crashed anyway.
+const crashLookback = 24 * time.Hour
+
+// checkCrashedSessions reports agent sessions that died mid-task: their
+// transcript ends on an unanswered prompt or tool call and no Claude
+// process is left to finish it. Each is reported once, as a feed event,
+// so the Deacon and humans watching the feed can pick the work back up.
+func (d *Daemon) checkCrashedSessions() {
+	sessions, err := claude.DiscoverSessions(claude.SessionFilter{
+		GasTownOnly: true,
+		Since:       time.Now().Add(-crashLookback),
+		State:       claude.StateCrashed,
+	})
+	if err != nil {
+		d.logger.Printf("Crash check: discovering sessions: %v", err)
+		return
+	}
+
+	if d.reportedCrashes == nil {
+		d.reportedCrashes = make(map[string]bool)
+	}
+	current := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		current[s.ID] = true
+		if d.reportedCrashes[s.ID] {
+			continue
+		}
+		d.reportedCrashes[s.ID] = true
+		d.logger.Printf("SESSION CRASHED: %s (%s) stopped mid-task at %s", s.Role, s.ShortID(), s.EndTime.Format(time.RFC3339))
+		_ = events.LogFeed(events.TypeSessionCrashed, "daemon",
+			events.SessionCrashedPayload(s.ID, s.Role, s.Topic, s.EndTime.Format(time.RFC3339)))
+	}
+
+	// Forget sessions that were resumed or aged out, so a second crash
+	// of a resumed session is reported again.
+	for id := range d.reportedCrashes {
+		if !current[id] {
+			delete(d.reportedCrashes, id)
+		}
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/gofrs/flock"
@@ -28,6 +27,7 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/wisp"
 	"github.com/steveyegge/gastown/internal/witness"
 )
@@ -47,6 +47,14 @@ type Daemon struct {
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
 	recentDeaths []sessionDeath
+
+	// Edit conflict detection: conflicts already reported, by key.
+	// Only touched from the heartbeat goroutine.
+	reportedConflicts map[string]time.Time
+
+	// Crashed session detection: sessions already reported, by ID.
+	// Only touched from the heartbeat goroutine.
+	reportedCrashes map[string]bool
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -126,7 +134,7 @@ func (d *Daemon) Run() error {
 
 	// Handle signals
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1)
+	signal.Notify(sigChan, daemonSignals...)
 
 	// Fixed recovery-focused heartbeat (no activity-based backoff)
 	// Normal wake is handled by feed subscription (bd activity --follow)
@@ -143,6 +151,9 @@ func (d *Daemon) Run() error {
 		d.logger.Println("Feed curator started")
 	}
 
+	// Start session ingest goroutine
+	d.startSessionIngest()
+
 	// Initial heartbeat
 	d.heartbeat(state)
 
@@ -153,7 +164,7 @@ func (d *Daemon) Run() error {
 			return d.shutdown(state)
 
 		case sig := <-sigChan:
-			if sig == syscall.SIGUSR1 {
+			if isLifecycleSignal(sig) {
 				// SIGUSR1: immediate lifecycle processing (from gt handoff)
 				d.logger.Println("Received SIGUSR1, processing lifecycle requests immediately")
 				d.processLifecycleRequests()
@@ -224,6 +235,24 @@ func (d *Daemon) heartbeat(state *State) {
 	// This validates tmux sessions are still alive for polecats with work-on-hook
 	d.checkPolecatSessionHealth()
 
+	// 12. Check for concurrent edits to the same file (early merge conflict warning)
+	d.checkEditConflicts()
+
+	// 13. Retry queued mail for recipients that now have a session, and
+	// expire mail that has waited too long
+	d.retryMailDelivery()
+
+	// 14. Link sessions to the issues they referenced, and comment on
+	// those issues once the session ends
+	d.linkSessionIssues()
+
+	// 15. Launch queued nightshift tasks inside the off-hours window, and
+	// report their results once it closes
+	d.runNightshift()
+
+	// 16. Report agent sessions that died mid-task
+	d.checkCrashedSessions()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -613,14 +642,7 @@ func IsRunning(townRoot string) (bool, int, error) {
 	}
 
 	// Check if process is running
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false, 0, nil
-	}
-
-	// On Unix, FindProcess always succeeds. Send signal 0 to check if alive.
-	err = process.Signal(syscall.Signal(0))
-	if err != nil {
+	if !util.ProcessAlive(pid) {
 		// Process not running, clean up stale PID file
 		_ = os.Remove(pidFile)
 		return false, 0, nil
@@ -646,18 +668,18 @@ func StopDaemon(townRoot string) error {
 		return fmt.Errorf("finding process: %w", err)
 	}
 
-	// Send SIGTERM for graceful shutdown
-	if err := process.Signal(syscall.SIGTERM); err != nil {
-		return fmt.Errorf("sending SIGTERM: %w", err)
+	// Ask it to shut down gracefully
+	if err := terminate(process); err != nil {
+		return fmt.Errorf("stopping daemon: %w", err)
 	}
 
 	// Wait a bit for graceful shutdown
 	time.Sleep(constants.ShutdownNotifyDelay)
 
 	// Check if still running
-	if err := process.Signal(syscall.Signal(0)); err == nil {
+	if util.ProcessAlive(pid) {
 		// Still running, force kill
-		_ = process.Signal(syscall.SIGKILL)
+		_ = process.Kill()
 	}
 
 	// Clean up PID file
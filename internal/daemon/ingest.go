@@ -0,0 +1,31 @@
+package daemon
+
+import (
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// startSessionIngest indexes agent transcripts in the background as they
+// are written, in debounced batches, unless the town turned it off.
+func (d *Daemon) startSessionIngest() {
+	settings, _ := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	cfg := settings.IngestSettings()
+	if cfg.Disabled {
+		d.logger.Println("Session ingest disabled")
+		return
+	}
+
+	ingester := claude.NewIngester(claude.IngestOptions{
+		Interval: cfg.IntervalDuration(),
+		Quiet:    cfg.QuietDuration(),
+		MaxDelay: cfg.MaxDelayDuration(),
+		MaxBatch: cfg.MaxBatch,
+		Logf:     d.logger.Printf,
+	})
+	go func() {
+		if err := ingester.Run(d.ctx); err != nil {
+			d.logger.Printf("Session ingest stopped: %v", err)
+		}
+	}()
+	d.logger.Printf("Session ingest started (every %v, up to %d transcripts per batch)", cfg.IntervalDuration(), cfg.MaxBatch)
+}
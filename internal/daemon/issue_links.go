@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/issuelink"
+)
+
+// issueLinkWindow is how far back the daemon looks for sessions to link.
+// Older sessions were linked (and commented on) by earlier passes.
+const issueLinkWindow = 7 * 24 * time.Hour
+
+// linkSessionIssues records the issues recent sessions referenced and,
+// when issue_links.comment is enabled, comments on them once the session
+// has ended.
+func (d *Daemon) linkSessionIssues() {
+	sessions, err := claude.DiscoverSessions(claude.SessionFilter{GasTownOnly: true})
+	if err != nil {
+		d.logger.Printf("Issue links: discovering sessions: %v", err)
+		return
+	}
+
+	// Unreadable settings fall back to the defaults: link, but don't comment
+	settings, _ := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	cfg := settings.IssueLinkSettings()
+
+	result, err := issuelink.Scan(d.config.TownRoot, sessions, issuelink.ScanOptions{
+		Since:     time.Now().Add(-issueLinkWindow),
+		IdleAfter: cfg.IdleDuration(),
+		Comment:   cfg.Comment,
+		GitHub:    cfg.GitHubComments,
+		Prefixes:  issuelink.Prefixes(d.config.TownRoot),
+	})
+	if err != nil {
+		d.logger.Printf("Issue links: %v", err)
+		return
+	}
+	if len(result.Linked)+len(result.Commented) > 0 {
+		d.logger.Printf("Issue links: %d new link(s), %d comment(s) posted",
+			len(result.Linked), len(result.Commented))
+	}
+	for _, e := range result.Errors {
+		d.logger.Printf("Issue links: comment failed: %s", e)
+	}
+}
@@ -0,0 +1,22 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// retryMailDelivery runs a mail delivery retry pass, so messages sent to
+// agents without an active session are announced once the agent starts.
+func (d *Daemon) retryMailDelivery() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	result, err := router.RetryDeliveries(time.Now())
+	if err != nil {
+		d.logger.Printf("Mail delivery: %v", err)
+		return
+	}
+	if result.Stored+result.Delivered+result.Expired > 0 {
+		d.logger.Printf("Mail delivery: %d stored, %d delivered, %d expired, %d still queued",
+			result.Stored, result.Delivered, result.Expired, result.Pending)
+	}
+}
@@ -0,0 +1,52 @@
+package daemon
+
+import (
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/nightshift"
+)
+
+// runNightshift launches queued off-hours tasks while the nightshift
+// window is open, and mails the night's results once it closes.
+func (d *Daemon) runNightshift() {
+	tasks, err := nightshift.Load(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Nightshift: %v", err)
+		return
+	}
+	if len(tasks) == 0 {
+		return
+	}
+
+	settings, _ := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	cfg := settings.NightshiftSettings()
+	result, err := nightshift.Run(d.config.TownRoot, nightshift.RunOptions{
+		Config: cfg,
+		Launch: nightshift.Sling(d.config.TownRoot),
+		Status: nightshift.BeadStatus(d.config.TownRoot),
+		Spent:  nightshift.SessionSpend(settings.PriceTable()),
+		Notify: nightshift.Mail(d.config.TownRoot, cfg.Notify),
+	})
+	if err != nil {
+		d.logger.Printf("Nightshift: %v", err)
+	}
+	if result == nil {
+		return
+	}
+
+	for _, t := range result.Launched {
+		d.logger.Printf("Nightshift: launched %s (%s)", t.Bead, t.ID)
+		_ = events.LogFeed(events.TypeNightshift, "daemon", map[string]interface{}{
+			"task":   t.ID,
+			"bead":   t.Bead,
+			"target": t.Target,
+			"status": string(t.Status),
+		})
+	}
+	for _, t := range result.Failed {
+		d.logger.Printf("Nightshift: launching %s failed: %s", t.Bead, t.Error)
+	}
+	if len(result.Reported) > 0 {
+		d.logger.Printf("Nightshift: reported %d task(s) to %s", len(result.Reported), cfg.Notify)
+	}
+}
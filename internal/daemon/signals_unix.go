@@ -0,0 +1,24 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+// daemonSignals are the signals the daemon handles: SIGINT and SIGTERM
+// shut it down; SIGUSR1 (from gt handoff) processes lifecycle requests
+// immediately.
+var daemonSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1}
+
+// isLifecycleSignal reports whether sig asks for immediate lifecycle
+// processing rather than shutdown.
+func isLifecycleSignal(sig os.Signal) bool {
+	return sig == syscall.SIGUSR1
+}
+
+// terminate asks process to shut down gracefully with SIGTERM.
+func terminate(process *os.Process) error {
+	return process.Signal(syscall.SIGTERM)
+}
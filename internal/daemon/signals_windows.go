@@ -0,0 +1,23 @@
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+// daemonSignals are the signals the daemon handles. Windows has no
+// SIGUSR1, so lifecycle requests wait for the next heartbeat.
+var daemonSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// isLifecycleSignal reports whether sig asks for immediate lifecycle
+// processing rather than shutdown.
+func isLifecycleSignal(os.Signal) bool {
+	return false
+}
+
+// terminate stops process. Windows can't deliver SIGTERM to another
+// process, so it is killed outright; the daemon's state is saved on each
+// heartbeat.
+func terminate(process *os.Process) error {
+	return process.Kill()
+}
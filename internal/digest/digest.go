@@ -0,0 +1,234 @@
+// Package digest composes the "while you were away" briefing an agent
+// reads when its session starts: mail received, assignments that changed,
+// and commits that landed in its rig since its previous session.
+package digest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Filename is the digest written into the agent's workspace.
+const Filename = "DIGEST.md"
+
+// stateFilename records what the previous session knew, under .runtime.
+const stateFilename = "digest-state.json"
+
+// Assignment change kinds.
+const (
+	ChangeNew     = "new"
+	ChangeStatus  = "status"
+	ChangeRemoved = "removed"
+)
+
+// State is what the previous session knew, kept to diff against.
+type State struct {
+	// LastSession is when the previous session started.
+	LastSession time.Time `json:"last_session"`
+
+	// Assignments maps the bead IDs assigned at that time to their status.
+	Assignments map[string]string `json:"assignments"`
+}
+
+// Mail is a message received since the previous session.
+type Mail struct {
+	ID      string    `json:"id"`
+	From    string    `json:"from"`
+	Subject string    `json:"subject"`
+	Time    time.Time `json:"time"`
+	Read    bool      `json:"read"`
+}
+
+// Assignment is a bead on the agent's hook or in progress. Change is
+// empty when it is unchanged since the previous session.
+type Assignment struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`
+	Change     string `json:"change,omitempty"`
+	PrevStatus string `json:"prev_status,omitempty"`
+}
+
+// Digest is what changed for an agent since its previous session.
+type Digest struct {
+	Identity    string         `json:"identity"`
+	Since       time.Time      `json:"since"`
+	Generated   time.Time      `json:"generated"`
+	Mail        []Mail         `json:"mail"`
+	Assignments []Assignment   `json:"assignments"` // current, then removed
+	Commits     []git.LogEntry `json:"commits"`
+	Warnings    []string       `json:"warnings,omitempty"` // sources that couldn't be read
+}
+
+// AssignmentChanges counts the assignments that are new, changed status,
+// or were removed.
+func (d *Digest) AssignmentChanges() int {
+	n := 0
+	for _, a := range d.Assignments {
+		if a.Change != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Changes counts the mail, assignment changes, and commits in the digest.
+func (d *Digest) Changes() int {
+	return len(d.Mail) + d.AssignmentChanges() + len(d.Commits)
+}
+
+// Path returns the digest path in an agent's workspace.
+func Path(workDir string) string {
+	return filepath.Join(workDir, Filename)
+}
+
+// statePath returns the state path in an agent's workspace.
+func statePath(workDir string) string {
+	return filepath.Join(workDir, constants.DirRuntime, stateFilename)
+}
+
+// ReadState loads the previous session's state. Returns nil, nil if no
+// session has recorded one.
+func ReadState(workDir string) (*State, error) {
+	data, err := os.ReadFile(statePath(workDir)) //nolint:gosec // G304: path is constructed from the agent's workspace
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading digest state: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing digest state: %w", err)
+	}
+	return &s, nil
+}
+
+// WriteState records the current session's state for the next digest.
+func WriteState(workDir string, s *State) error {
+	path := statePath(workDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	return util.AtomicWriteJSON(path, s)
+}
+
+// DiffAssignments marks each current assignment new or status-changed
+// against prev (bead ID to status), and appends the ones no longer
+// assigned as removed.
+func DiffAssignments(prev map[string]string, current []Assignment) []Assignment {
+	out := make([]Assignment, 0, len(current))
+	seen := make(map[string]bool, len(current))
+	for _, a := range current {
+		seen[a.ID] = true
+		switch status, ok := prev[a.ID]; {
+		case !ok:
+			a.Change = ChangeNew
+		case status != a.Status:
+			a.Change, a.PrevStatus = ChangeStatus, status
+		}
+		out = append(out, a)
+	}
+
+	var removed []string
+	for id := range prev {
+		if !seen[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+	for _, id := range removed {
+		out = append(out, Assignment{ID: id, Change: ChangeRemoved, PrevStatus: prev[id]})
+	}
+	return out
+}
+
+// Markdown renders the digest for the agent to read.
+func (d *Digest) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Digest for %s\n\n", d.Identity)
+	fmt.Fprintf(&b, "What changed since your previous session started (%s). Generated %s by `gt prime`; it is rewritten every session.\n",
+		d.Since.Local().Format("2006-01-02 15:04"), d.Generated.Local().Format("2006-01-02 15:04"))
+
+	b.WriteString("\n## Mail\n")
+	if len(d.Mail) == 0 {
+		b.WriteString("No new mail.\n")
+	}
+	for _, m := range d.Mail {
+		status := "unread"
+		if m.Read {
+			status = "read"
+		}
+		fmt.Fprintf(&b, "- %s from %s: %s (%s, %s)\n", m.Time.Local().Format("01-02 15:04"), m.From, m.Subject, m.ID, status)
+	}
+	if len(d.Mail) > 0 {
+		b.WriteString("\nRead with `gt mail read <id>`.\n")
+	}
+
+	b.WriteString("\n## Assignments\n")
+	if len(d.Assignments) == 0 {
+		b.WriteString("Nothing hooked or in progress.\n")
+	}
+	for _, a := range d.Assignments {
+		switch a.Change {
+		case ChangeNew:
+			fmt.Fprintf(&b, "- **new** %s [%s] %s\n", a.ID, a.Status, a.Title)
+		case ChangeStatus:
+			fmt.Fprintf(&b, "- **%s → %s** %s %s\n", a.PrevStatus, a.Status, a.ID, a.Title)
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "- **no longer assigned** %s (was %s)\n", a.ID, a.PrevStatus)
+		default:
+			fmt.Fprintf(&b, "- %s [%s] %s\n", a.ID, a.Status, a.Title)
+		}
+	}
+
+	b.WriteString("\n## Commits\n")
+	if len(d.Commits) == 0 {
+		b.WriteString("No new commits in the rig.\n")
+	}
+	for _, c := range d.Commits {
+		fmt.Fprintf(&b, "- %s %s (%s, %s)\n", c.SHA, c.Subject, c.Author, c.Time.Local().Format("01-02 15:04"))
+	}
+
+	if len(d.Warnings) > 0 {
+		b.WriteString("\n## Incomplete\n")
+		for _, w := range d.Warnings {
+			fmt.Fprintf(&b, "- %s\n", w)
+		}
+	}
+	return b.String()
+}
+
+// Write saves the digest as DIGEST.md in the agent's workspace. In a git
+// worktree the file is added to info/exclude so agents don't commit it.
+func Write(workDir string, d *Digest) error {
+	if err := util.AtomicWriteFile(Path(workDir), []byte(d.Markdown()), 0644); err != nil {
+		return err
+	}
+	g := git.NewGit(workDir)
+	if !g.IsRepo() {
+		return nil
+	}
+	top, err := g.TopLevel()
+	if err != nil {
+		return err
+	}
+	dir, err := filepath.EvalSymlinks(workDir)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(top, filepath.Join(dir, Filename))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil // workspace is outside its repository's tree
+	}
+	return g.Exclude("/" + filepath.ToSlash(rel))
+}
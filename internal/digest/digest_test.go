@@ -0,0 +1,115 @@
+package digest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestDiffAssignments(t *testing.T) {
+	prev := map[string]string{"gt-1": "hooked", "gt-2": "open", "gt-3": "in_progress"}
+	current := []Assignment{
+		{ID: "gt-1", Status: "hooked"},
+		{ID: "gt-2", Status: "in_progress"},
+		{ID: "gt-4", Status: "hooked"},
+	}
+
+	got := DiffAssignments(prev, current)
+	want := []Assignment{
+		{ID: "gt-1", Status: "hooked"},
+		{ID: "gt-2", Status: "in_progress", Change: ChangeStatus, PrevStatus: "open"},
+		{ID: "gt-4", Status: "hooked", Change: ChangeNew},
+		{ID: "gt-3", Change: ChangeRemoved, PrevStatus: "in_progress"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d assignments, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("assignment %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	d := &Digest{Assignments: got}
+	if n := d.AssignmentChanges(); n != 3 {
+		t.Errorf("AssignmentChanges() = %d, want 3", n)
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if s, err := ReadState(dir); s != nil || err != nil {
+		t.Fatalf("ReadState on empty workspace = %v, %v; want nil, nil", s, err)
+	}
+
+	want := &State{LastSession: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Assignments: map[string]string{"gt-1": "hooked"}}
+	if err := WriteState(dir, want); err != nil {
+		t.Fatalf("WriteState: %v", err)
+	}
+	got, err := ReadState(dir)
+	if err != nil {
+		t.Fatalf("ReadState: %v", err)
+	}
+	if !got.LastSession.Equal(want.LastSession) || got.Assignments["gt-1"] != "hooked" {
+		t.Errorf("ReadState = %+v, want %+v", got, want)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	d := &Digest{
+		Identity:  "gastown/polecats/toast",
+		Since:     time.Now().Add(-time.Hour),
+		Generated: time.Now(),
+		Mail:      []Mail{{ID: "hq-1", From: "mayor/", Subject: "Rebase onto main", Time: time.Now()}},
+		Assignments: []Assignment{
+			{ID: "gt-2", Title: "Fix login", Status: "in_progress", Change: ChangeStatus, PrevStatus: "hooked"},
+			{ID: "gt-3", Change: ChangeRemoved, PrevStatus: "hooked"},
+		},
+		Warnings: []string{"rig commits unavailable: no remote"},
+	}
+
+	md := d.Markdown()
+	for _, want := range []string{
+		"# Digest for gastown/polecats/toast",
+		"from mayor/: Rebase onto main (hq-1, unread)",
+		"**hooked → in_progress** gt-2 Fix login",
+		"**no longer assigned** gt-3 (was hooked)",
+		"No new commits in the rig.",
+		"## Incomplete",
+		"rig commits unavailable: no remote",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestWriteExcludesFromGit(t *testing.T) {
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	workDir := filepath.Join(repo, "crew", "max")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Write(workDir, &Digest{Identity: "gastown/crew/max"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := os.Stat(Path(workDir)); err != nil {
+		t.Fatalf("digest not written: %v", err)
+	}
+	ignored, err := git.NewGit(workDir).IgnoredPaths(Filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ignored) != 1 {
+		t.Errorf("expected %s to be excluded from git", Filename)
+	}
+}
@@ -67,8 +67,10 @@ func (d *Doctor) Fix(ctx *CheckContext) *Report {
 			result.Category = cg.Category()
 		}
 
-		// Attempt fix if check failed and is fixable
-		if result.Status != StatusOK && check.CanFix() {
+		// Attempt fix if check failed, is fixable, and is approved
+		if result.Status != StatusOK && check.CanFix() && !approveFix(ctx, check, result) {
+			result.Details = append(result.Details, "Fix skipped (rerun with --fix --yes to apply)")
+		} else if result.Status != StatusOK && check.CanFix() {
 			err := check.Fix(ctx)
 			if err == nil {
 				// Re-run check to verify fix worked
@@ -96,6 +98,15 @@ func (d *Doctor) Fix(ctx *CheckContext) *Report {
 	return report
 }
 
+// approveFix reports whether a failed check's fix may be applied.
+func approveFix(ctx *CheckContext, check Check, result *CheckResult) bool {
+	if ctx.ConfirmFix != nil {
+		return ctx.ConfirmFix(check, result)
+	}
+	a, ok := check.(ApprovalRequired)
+	return !ok || !a.RequiresApproval()
+}
+
 // BaseCheck provides a base implementation for checks that don't support auto-fix.
 // Embed this in custom checks to get default CanFix() and Fix() implementations.
 type BaseCheck struct {
@@ -139,3 +150,14 @@ type FixableCheck struct {
 func (f *FixableCheck) CanFix() bool {
 	return true
 }
+
+// RemediationCheck provides a base implementation for fixable checks whose
+// fixes require approval before they run.
+type RemediationCheck struct {
+	FixableCheck
+}
+
+// RequiresApproval returns true for remediation checks.
+func (r *RemediationCheck) RequiresApproval() bool {
+	return true
+}
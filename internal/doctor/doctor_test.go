@@ -330,6 +330,50 @@ func TestDoctor_Fix(t *testing.T) {
 	}
 }
 
+// approvalCheck is a mock check whose fix requires approval.
+type approvalCheck struct {
+	*mockCheck
+}
+
+func (a approvalCheck) RequiresApproval() bool {
+	return true
+}
+
+func TestDoctor_FixApproval(t *testing.T) {
+	plain := newMockCheck("plain", StatusError)
+	plain.fixable = true
+	remediation := newMockCheck("remediation", StatusWarning)
+	remediation.fixable = true
+
+	d := NewDoctor()
+	d.Register(plain)
+	d.Register(approvalCheck{remediation})
+
+	// Without a confirmer, only fixes that don't need approval run.
+	report := d.Fix(&CheckContext{TownRoot: "/test"})
+	if plain.fixCount != 1 || remediation.fixCount != 0 {
+		t.Fatalf("fix counts = %d, %d; want 1, 0", plain.fixCount, remediation.fixCount)
+	}
+	if report.Checks[1].Status != StatusWarning || len(report.Checks[1].Details) == 0 {
+		t.Errorf("skipped fix should stay a warning with a note: %+v", report.Checks[1])
+	}
+
+	// A confirmer decides for every fix.
+	plain.status = StatusError
+	var asked []string
+	ctx := &CheckContext{TownRoot: "/test", ConfirmFix: func(c Check, _ *CheckResult) bool {
+		asked = append(asked, c.Name())
+		return c.Name() == "remediation"
+	}}
+	d.Fix(ctx)
+	if len(asked) != 2 {
+		t.Errorf("asked about %v, want both checks", asked)
+	}
+	if plain.fixCount != 1 || remediation.fixCount != 1 {
+		t.Errorf("fix counts = %d, %d; want 1, 1", plain.fixCount, remediation.fixCount)
+	}
+}
+
 func TestBaseCheck(t *testing.T) {
 	b := &BaseCheck{
 		CheckName:        "test",
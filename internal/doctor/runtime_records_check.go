@@ -0,0 +1,158 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// RuntimeRecordsCheck detects corrupt entries in the town's record logs
+// (.events.jsonl, .runtime/outcomes.jsonl) and heartbeat files. A torn
+// write from a killed process leaves a line every reader has to skip.
+type RuntimeRecordsCheck struct {
+	RemediationCheck
+	corruptLogs       map[string][]int // log path -> 1-based corrupt line numbers
+	corruptHeartbeats []string
+}
+
+// NewRuntimeRecordsCheck creates a new runtime records check.
+func NewRuntimeRecordsCheck() *RuntimeRecordsCheck {
+	return &RuntimeRecordsCheck{
+		RemediationCheck: RemediationCheck{
+			FixableCheck: FixableCheck{
+				BaseCheck: BaseCheck{
+					CheckName:        "runtime-records",
+					CheckDescription: "Detect corrupt event, outcome, and heartbeat records",
+					CheckCategory:    CategoryCleanup,
+				},
+			},
+		},
+	}
+}
+
+// recordLogs returns the JSONL logs the check scans.
+func recordLogs(townRoot string) []string {
+	return []string{
+		filepath.Join(townRoot, events.EventsFile),
+		outcome.Path(townRoot),
+	}
+}
+
+// Run scans record logs and heartbeats for entries that don't parse.
+func (c *RuntimeRecordsCheck) Run(ctx *CheckContext) *CheckResult {
+	c.corruptLogs = make(map[string][]int)
+	c.corruptHeartbeats = nil
+
+	var details []string
+	for _, path := range recordLogs(ctx.TownRoot) {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+		if err != nil {
+			continue
+		}
+		_, bad := splitRecordLines(data)
+		if len(bad) == 0 {
+			continue
+		}
+		c.corruptLogs[path] = bad
+		rel, _ := filepath.Rel(ctx.TownRoot, path)
+		details = append(details, fmt.Sprintf("%s: %d corrupt line(s) (first at line %d)", rel, len(bad), bad[0]))
+	}
+
+	hbDir := filepath.Join(ctx.TownRoot, events.HeartbeatsDir)
+	entries, _ := os.ReadDir(hbDir)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(hbDir, e.Name())
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+		if err != nil || !json.Valid(data) {
+			c.corruptHeartbeats = append(c.corruptHeartbeats, path)
+			details = append(details, fmt.Sprintf("%s/%s: unreadable heartbeat", events.HeartbeatsDir, e.Name()))
+		}
+	}
+
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "Event, outcome, and heartbeat records are intact",
+		}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d record file(s) have corrupt entries", len(c.corruptLogs)+len(c.corruptHeartbeats)),
+		Details: details,
+		FixHint: "Run 'gt doctor --fix' to move corrupt lines to <file>.corrupt and reset bad heartbeats",
+	}
+}
+
+// Fix rewrites each log without its corrupt lines, keeping them in a
+// .corrupt sidecar for inspection, and removes unreadable heartbeats
+// (they are rewritten on the agent's next tool call).
+func (c *RuntimeRecordsCheck) Fix(ctx *CheckContext) error {
+	for path := range c.corruptLogs {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		good, bad := splitRecordLines(data)
+		if len(bad) == 0 {
+			continue
+		}
+
+		lines := strings.Split(string(data), "\n")
+		var corrupt strings.Builder
+		for _, n := range bad {
+			corrupt.WriteString(lines[n-1] + "\n")
+		}
+		f, err := os.OpenFile(path+".corrupt", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G302: records are not secret
+		if err != nil {
+			return fmt.Errorf("saving corrupt lines: %w", err)
+		}
+		_, err = f.WriteString(corrupt.String())
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("saving corrupt lines: %w", err)
+		}
+
+		if err := util.AtomicWriteFile(path, good, 0644); err != nil {
+			return fmt.Errorf("rewriting %s: %w", path, err)
+		}
+	}
+
+	for _, path := range c.corruptHeartbeats {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// splitRecordLines returns the valid JSONL lines of data and the 1-based
+// numbers of lines that aren't valid JSON. Blank lines are dropped.
+func splitRecordLines(data []byte) (good []byte, bad []int) {
+	var b bytes.Buffer
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) == 0 {
+			continue
+		}
+		if !json.Valid(trimmed) {
+			bad = append(bad, i+1)
+			continue
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), bad
+}
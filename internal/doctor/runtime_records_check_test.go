@@ -0,0 +1,60 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/outcome"
+)
+
+func TestRuntimeRecordsCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	eventsPath := filepath.Join(townRoot, events.EventsFile)
+	if err := os.WriteFile(eventsPath, []byte("{\"type\":\"a\"}\n{\"type\":\"b\n\n{\"type\":\"c\"}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(outcome.Path(townRoot)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(outcome.Path(townRoot), []byte("{\"session_id\":\"s1\"}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hbDir := filepath.Join(townRoot, events.HeartbeatsDir)
+	if err := os.MkdirAll(hbDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	badHeartbeat := filepath.Join(hbDir, "gastown-witness.json")
+	if err := os.WriteFile(badHeartbeat, []byte("{\"actor\":"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewRuntimeRecordsCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning || len(result.Details) != 2 {
+		t.Fatalf("Run = %+v, want warning with 2 details", result)
+	}
+	if !check.RequiresApproval() {
+		t.Error("runtime-records fixes should require approval")
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	data, _ := os.ReadFile(eventsPath)
+	if string(data) != "{\"type\":\"a\"}\n{\"type\":\"c\"}\n" {
+		t.Errorf("events after fix = %q", data)
+	}
+	corrupt, _ := os.ReadFile(eventsPath + ".corrupt")
+	if string(corrupt) != "{\"type\":\"b\n" {
+		t.Errorf("corrupt sidecar = %q", corrupt)
+	}
+	if _, err := os.Stat(badHeartbeat); !os.IsNotExist(err) {
+		t.Error("bad heartbeat should be removed")
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after fix: %+v", result)
+	}
+}
@@ -0,0 +1,172 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// sessionRigsWindow is how far back SessionRigsCheck looks for sessions.
+const sessionRigsWindow = 30 * 24 * time.Hour
+
+// SessionRigsCheck finds rigs that agents worked in recently but that are
+// missing from mayor/rigs.json, e.g. after the registry was restored from
+// an old copy or an entry was removed while its directory was unmounted.
+type SessionRigsCheck struct {
+	RemediationCheck
+	unregistered []unregisteredRig
+}
+
+type unregisteredRig struct {
+	name     string
+	config   *rig.RigConfig
+	sessions int
+	lastSeen time.Time
+}
+
+// NewSessionRigsCheck creates a new check for rigs seen in recent sessions.
+func NewSessionRigsCheck() *SessionRigsCheck {
+	return &SessionRigsCheck{
+		RemediationCheck: RemediationCheck{
+			FixableCheck: FixableCheck{
+				BaseCheck: BaseCheck{
+					CheckName:        "rigs-from-sessions",
+					CheckDescription: "Detect rigs used by recent sessions but missing from the registry",
+					CheckCategory:    CategoryCore,
+				},
+			},
+		},
+	}
+}
+
+// Run compares rigs named in recent session beacons with the registry.
+func (c *SessionRigsCheck) Run(ctx *CheckContext) *CheckResult {
+	c.unregistered = nil
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(ctx.TownRoot))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return &CheckResult{
+				Name:    c.Name(),
+				Status:  StatusOK,
+				Message: "No rigs.json (skipping)",
+			}
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "rigs.json unreadable (see rigs-registry-valid)",
+		}
+	}
+
+	sessions, err := claude.DiscoverSessions(claude.SessionFilter{GasTownOnly: true})
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not scan sessions",
+			Details: []string{err.Error()},
+		}
+	}
+
+	c.unregistered = findUnregisteredRigs(ctx.TownRoot, rigsConfig, sessions, time.Now().Add(-sessionRigsWindow))
+	if len(c.unregistered) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "All rigs used by recent sessions are registered",
+		}
+	}
+
+	var details []string
+	for _, r := range c.unregistered {
+		details = append(details, fmt.Sprintf("%s: %d session(s), last %s", r.name, r.sessions, r.lastSeen.Local().Format("2006-01-02")))
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d rig(s) used by recent sessions are not registered", len(c.unregistered)),
+		Details: details,
+		FixHint: "Run 'gt doctor --fix' to re-register them from their config.json",
+	}
+}
+
+// Fix re-registers each rig from its own config.json.
+func (c *SessionRigsCheck) Fix(ctx *CheckContext) error {
+	if len(c.unregistered) == 0 {
+		return nil
+	}
+
+	rigsPath := constants.MayorRigsPath(ctx.TownRoot)
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		return fmt.Errorf("loading rigs.json: %w", err)
+	}
+
+	for _, r := range c.unregistered {
+		entry := config.RigEntry{
+			GitURL:    r.config.GitURL,
+			LocalRepo: r.config.LocalRepo,
+			AddedAt:   time.Now(),
+		}
+		if r.config.Beads != nil {
+			entry.BeadsConfig = &config.BeadsConfig{Prefix: r.config.Beads.Prefix}
+		}
+		rigsConfig.Rigs[r.name] = entry
+	}
+
+	return config.SaveRigsConfig(rigsPath, rigsConfig)
+}
+
+// findUnregisteredRigs returns rigs named by sessions started since cutoff
+// that have a rig config on disk but no registry entry, sorted by name.
+func findUnregisteredRigs(townRoot string, rigsConfig *config.RigsConfig, sessions []claude.SessionInfo, cutoff time.Time) []unregisteredRig {
+	seen := make(map[string]*unregisteredRig)
+	for _, s := range sessions {
+		if s.StartTime.Before(cutoff) {
+			continue
+		}
+		name, _, ok := strings.Cut(s.Role, "/")
+		if !ok || name == "" {
+			continue // Town-level role (mayor, deacon, ...)
+		}
+		if _, registered := rigsConfig.Rigs[name]; registered {
+			continue
+		}
+
+		r, ok := seen[name]
+		if !ok {
+			cfg, err := rig.LoadRigConfig(filepath.Join(townRoot, name))
+			if err != nil || cfg.GitURL == "" {
+				seen[name] = nil // Not a rig on disk; don't retry
+				continue
+			}
+			r = &unregisteredRig{name: name, config: cfg}
+			seen[name] = r
+		}
+		if r == nil {
+			continue
+		}
+		r.sessions++
+		if s.StartTime.After(r.lastSeen) {
+			r.lastSeen = s.StartTime
+		}
+	}
+
+	var rigs []unregisteredRig
+	for _, r := range seen {
+		if r != nil {
+			rigs = append(rigs, *r)
+		}
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].name < rigs[j].name })
+	return rigs
+}
@@ -0,0 +1,65 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+func TestSessionRigsCheckFix(t *testing.T) {
+	townRoot := t.TempDir()
+	rigsPath := constants.MayorRigsPath(townRoot)
+	if err := config.SaveRigsConfig(rigsPath, &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{
+		"gastown": {GitURL: "https://example.com/gastown.git"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	// beads has a rig config on disk but no registry entry; scratch doesn't.
+	if err := os.MkdirAll(filepath.Join(townRoot, "beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigConfig := `{"type":"rig","name":"beads","git_url":"https://example.com/beads.git","beads":{"prefix":"bd"}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "beads", "config.json"), []byte(rigConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	sessions := []claude.SessionInfo{
+		{Role: "gastown/crew/joe", StartTime: now},
+		{Role: "beads/polecats/toast", StartTime: now.Add(-time.Hour)},
+		{Role: "beads/witness", StartTime: now},
+		{Role: "beads/crew/old", StartTime: now.Add(-60 * 24 * time.Hour)},
+		{Role: "scratch/crew/max", StartTime: now},
+		{Role: "mayor", StartTime: now},
+	}
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := findUnregisteredRigs(townRoot, rigsConfig, sessions, now.Add(-sessionRigsWindow))
+	if len(found) != 1 || found[0].name != "beads" || found[0].sessions != 2 {
+		t.Fatalf("findUnregisteredRigs = %+v, want beads with 2 sessions", found)
+	}
+
+	check := NewSessionRigsCheck()
+	check.unregistered = found
+	if err := check.Fix(&CheckContext{TownRoot: townRoot}); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	rigsConfig, err = config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := rigsConfig.Rigs["beads"]
+	if !ok || entry.GitURL != "https://example.com/beads.git" || entry.BeadsConfig == nil || entry.BeadsConfig.Prefix != "bd" {
+		t.Errorf("beads entry = %+v", entry)
+	}
+	if _, ok := rigsConfig.Rigs["gastown"]; !ok {
+		t.Error("existing rigs should be kept")
+	}
+}
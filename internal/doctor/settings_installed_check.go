@@ -0,0 +1,126 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// SettingsInstalledCheck detects agent directories whose Claude settings
+// (and therefore their Gas Town hooks) are missing entirely. Agents started
+// there run without gt prime, mail injection, or heartbeats.
+// ClaudeSettingsCheck covers settings that exist but are stale.
+type SettingsInstalledCheck struct {
+	RemediationCheck
+	missing []missingSettings
+}
+
+type missingSettings struct {
+	workDir string
+	role    string
+}
+
+// NewSettingsInstalledCheck creates a new missing settings check.
+func NewSettingsInstalledCheck() *SettingsInstalledCheck {
+	return &SettingsInstalledCheck{
+		RemediationCheck: RemediationCheck{
+			FixableCheck: FixableCheck{
+				BaseCheck: BaseCheck{
+					CheckName:        "hooks-installed",
+					CheckDescription: "Detect agent directories missing Claude settings and hooks",
+					CheckCategory:    CategoryHooks,
+				},
+			},
+		},
+	}
+}
+
+// Run checks every agent directory for .claude/settings.json.
+func (c *SettingsInstalledCheck) Run(ctx *CheckContext) *CheckResult {
+	c.missing = nil
+
+	// Other runtimes keep their hooks elsewhere
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot)); err == nil &&
+		settings.DefaultAgent != "" && settings.DefaultAgent != "claude" {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Default agent is %s (skipping)", settings.DefaultAgent),
+		}
+	}
+
+	c.missing = findMissingSettings(ctx.TownRoot)
+	if len(c.missing) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "All agent directories have Claude hooks installed",
+		}
+	}
+
+	var details []string
+	for _, m := range c.missing {
+		rel, _ := filepath.Rel(ctx.TownRoot, m.workDir)
+		details = append(details, fmt.Sprintf("%s/.claude/settings.json (%s)", rel, m.role))
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusError,
+		Message: fmt.Sprintf("%d agent director(ies) missing Claude hooks", len(c.missing)),
+		Details: details,
+		FixHint: "Run 'gt doctor --fix' to reinstall settings from templates, then restart affected agents",
+	}
+}
+
+// Fix installs the role's settings template in each directory.
+func (c *SettingsInstalledCheck) Fix(ctx *CheckContext) error {
+	for _, m := range c.missing {
+		if err := claude.EnsureSettingsForRole(m.workDir, m.role); err != nil {
+			return fmt.Errorf("installing settings in %s: %w", m.workDir, err)
+		}
+	}
+	return nil
+}
+
+// findMissingSettings returns agent directories that exist but have no
+// .claude/settings.json: mayor/, deacon/, and each registered rig's
+// witness/, refinery/, crew/, and polecats/.
+func findMissingSettings(townRoot string) []missingSettings {
+	candidates := []missingSettings{
+		{filepath.Join(townRoot, "mayor"), "mayor"},
+		{filepath.Join(townRoot, "deacon"), "deacon"},
+	}
+
+	if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		rigs := make([]string, 0, len(rigsConfig.Rigs))
+		for name := range rigsConfig.Rigs {
+			rigs = append(rigs, name)
+		}
+		sort.Strings(rigs)
+		for _, name := range rigs {
+			rigPath := filepath.Join(townRoot, name)
+			candidates = append(candidates,
+				missingSettings{filepath.Join(rigPath, "witness"), "witness"},
+				missingSettings{filepath.Join(rigPath, "refinery"), "refinery"},
+				missingSettings{filepath.Join(rigPath, "crew"), "crew"},
+				missingSettings{filepath.Join(rigPath, "polecats"), "polecat"},
+			)
+		}
+	}
+
+	var missing []missingSettings
+	for _, m := range candidates {
+		if !dirExists(m.workDir) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(m.workDir, ".claude", "settings.json")); os.IsNotExist(err) {
+			missing = append(missing, m)
+		}
+	}
+	return missing
+}
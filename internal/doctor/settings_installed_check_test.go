@@ -0,0 +1,50 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+func TestSettingsInstalledCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := config.SaveRigsConfig(constants.MayorRigsPath(townRoot), &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{
+		"gastown": {},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{
+		filepath.Join("mayor", ".claude"),
+		filepath.Join("gastown", "witness"),
+		filepath.Join("gastown", "crew"),
+	} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", ".claude", "settings.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewSettingsInstalledCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	result := check.Run(ctx)
+	if result.Status != StatusError || len(result.Details) != 2 {
+		t.Fatalf("Run = %+v, want error for witness and crew", result)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	for _, dir := range []string{"witness", "crew"} {
+		if _, err := os.Stat(filepath.Join(townRoot, "gastown", dir, ".claude", "settings.json")); err != nil {
+			t.Errorf("%s settings not installed: %v", dir, err)
+		}
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after fix: %+v", result)
+	}
+}
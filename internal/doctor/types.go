@@ -4,6 +4,7 @@ package doctor
 import (
 	"fmt"
 	"io"
+	"path/filepath"
 	"slices"
 	"time"
 
@@ -64,6 +65,10 @@ type CheckContext struct {
 	RigName         string // Rig name (empty for town-level checks)
 	Verbose         bool   // Enable verbose output
 	RestartSessions bool   // Restart patrol sessions when fixing (requires explicit --restart-sessions flag)
+
+	// ConfirmFix approves each fix before Doctor.Fix applies it. When nil,
+	// fixes are applied unless the check requires approval.
+	ConfirmFix func(check Check, result *CheckResult) bool
 }
 
 // RigPath returns the full path to the rig directory.
@@ -72,7 +77,7 @@ func (ctx *CheckContext) RigPath() string {
 	if ctx.RigName == "" {
 		return ""
 	}
-	return ctx.TownRoot + "/" + ctx.RigName
+	return filepath.Join(ctx.TownRoot, ctx.RigName)
 }
 
 // CheckResult represents the outcome of a health check.
@@ -104,6 +109,13 @@ type Check interface {
 	CanFix() bool
 }
 
+// ApprovalRequired is implemented by checks whose fixes register, rewrite,
+// or delete town state. Doctor.Fix only applies them when
+// CheckContext.ConfirmFix approves (an interactive prompt or --yes).
+type ApprovalRequired interface {
+	RequiresApproval() bool
+}
+
 // ReportSummary summarizes the results of all checks.
 type ReportSummary struct {
 	Total    int
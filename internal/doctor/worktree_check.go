@@ -0,0 +1,119 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// OrphanedWorktreeCheck detects git worktree registrations whose
+// directories no longer exist, e.g. a polecat directory deleted with rm -rf
+// instead of 'gt polecat nuke'. Orphaned entries keep their branch checked
+// out, so the branch can't be reused or deleted.
+type OrphanedWorktreeCheck struct {
+	RemediationCheck
+	repos []string // repo bases with orphaned worktrees
+}
+
+// NewOrphanedWorktreeCheck creates a new orphaned worktree check.
+func NewOrphanedWorktreeCheck() *OrphanedWorktreeCheck {
+	return &OrphanedWorktreeCheck{
+		RemediationCheck: RemediationCheck{
+			FixableCheck: FixableCheck{
+				BaseCheck: BaseCheck{
+					CheckName:        "orphaned-worktrees",
+					CheckDescription: "Detect worktree registrations whose directories were deleted",
+					CheckCategory:    CategoryCleanup,
+				},
+			},
+		},
+	}
+}
+
+// Run lists the worktrees of each registered rig's repo base.
+func (c *OrphanedWorktreeCheck) Run(ctx *CheckContext) *CheckResult {
+	c.repos = nil
+
+	var details []string
+	for _, dir := range rigRepoBases(ctx.TownRoot) {
+		worktrees, err := repoBaseAt(dir).WorktreeList()
+		if err != nil {
+			continue
+		}
+		orphaned := 0
+		for _, wt := range worktrees {
+			if _, err := os.Stat(wt.Path); os.IsNotExist(err) {
+				rel, _ := filepath.Rel(ctx.TownRoot, wt.Path)
+				details = append(details, fmt.Sprintf("%s (branch %s)", rel, wt.Branch))
+				orphaned++
+			}
+		}
+		if orphaned > 0 {
+			c.repos = append(c.repos, dir)
+		}
+	}
+
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No orphaned worktrees",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d orphaned worktree(s)", len(details)),
+		Details: details,
+		FixHint: "Run 'gt doctor --fix' to prune them (git worktree prune)",
+	}
+}
+
+// Fix prunes orphaned worktree registrations. Branches are kept.
+func (c *OrphanedWorktreeCheck) Fix(ctx *CheckContext) error {
+	for _, dir := range c.repos {
+		if err := repoBaseAt(dir).WorktreePrune(); err != nil {
+			return fmt.Errorf("pruning worktrees in %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// rigRepoBases returns the repo each registered rig creates worktrees from:
+// the shared bare repo (.repo.git) or, in older rigs, mayor/rig.
+func rigRepoBases(townRoot string) []string {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var repos []string
+	for _, name := range names {
+		rigPath := filepath.Join(townRoot, name)
+		for _, dir := range []string{filepath.Join(rigPath, ".repo.git"), filepath.Join(rigPath, "mayor", "rig")} {
+			if dirExists(dir) {
+				repos = append(repos, dir)
+				break
+			}
+		}
+	}
+	return repos
+}
+
+// repoBaseAt returns a Git wrapper for a bare repo or clone directory.
+func repoBaseAt(dir string) *git.Git {
+	if filepath.Base(dir) == ".repo.git" {
+		return git.NewGitWithDir(dir, "")
+	}
+	return git.NewGit(dir)
+}
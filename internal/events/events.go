@@ -49,10 +49,16 @@ const (
 	// Session events (for seance discovery)
 	TypeSessionStart = "session_start"
 	TypeSessionEnd   = "session_end"
+	TypeHeartbeat    = "heartbeat" // Periodic liveness signal from a running agent
 
 	// Session death events (for crash investigation)
-	TypeSessionDeath = "session_death" // Feed-visible session termination
-	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
+	TypeSessionDeath   = "session_death"   // Feed-visible session termination
+	TypeMassDeath      = "mass_death"      // Multiple sessions died in short window
+	TypeSessionCrashed = "session_crashed" // A Claude session died mid-turn (see claude.StateCrashed)
+
+	// Coordination events (emitted by daemon)
+	TypeEditConflict = "edit_conflict" // Two agents edited the same file within a window
+	TypeNightshift   = "nightshift"    // A queued off-hours task was launched
 
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
@@ -292,6 +298,23 @@ func SessionDeathPayload(session, agent, reason, caller string) map[string]inter
 	}
 }
 
+// SessionCrashedPayload creates a payload for session crashed events.
+// sessionID: Claude session ID of the transcript that ended mid-turn
+// agent: Gas Town agent identity (e.g., "gastown/polecats/Toast")
+// topic: the session's beacon topic, if any
+// lastActivity: RFC3339 time of the transcript's last entry
+func SessionCrashedPayload(sessionID, agent, topic, lastActivity string) map[string]interface{} {
+	p := map[string]interface{}{
+		"session_id":    sessionID,
+		"agent":         agent,
+		"last_activity": lastActivity,
+	}
+	if topic != "" {
+		p["topic"] = topic
+	}
+	return p
+}
+
 // MassDeathPayload creates a payload for mass death events.
 // count: number of sessions that died
 // window: time window in which deaths occurred (e.g., "5s")
@@ -328,3 +351,31 @@ func SessionPayload(sessionID, role, topic, cwd string) map[string]interface{} {
 	}
 	return p
 }
+
+// HeartbeatPayload creates a payload for heartbeat events.
+// sessionID: Claude Code session UUID (may be empty)
+// topic: What the agent is currently working on (may be empty)
+func HeartbeatPayload(sessionID, topic string) map[string]interface{} {
+	p := map[string]interface{}{}
+	if sessionID != "" {
+		p["session_id"] = sessionID
+	}
+	if topic != "" {
+		p["topic"] = topic
+	}
+	return p
+}
+
+// EditConflictPayload creates a payload for edit conflict events.
+// rig: rig both agents work in
+// file: repo-relative path both agents edited
+// agents: addresses of the agents involved
+// sessions: Claude session IDs, parallel to agents
+func EditConflictPayload(rig, file string, agents, sessions []string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":      rig,
+		"file":     file,
+		"agents":   agents,
+		"sessions": sessions,
+	}
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HeartbeatsDir holds the latest heartbeat per agent, relative to the town
+// root. One file per agent avoids write contention between sessions.
+const HeartbeatsDir = ".runtime/heartbeats"
+
+// Heartbeat is an agent's most recent liveness signal.
+type Heartbeat struct {
+	Actor     string    `json:"actor"`
+	SessionID string    `json:"session_id,omitempty"`
+	Topic     string    `json:"topic,omitempty"`
+	Timestamp time.Time `json:"ts"`
+}
+
+// heartbeatPath returns the state file for an agent's heartbeat.
+func heartbeatPath(townRoot, actor string) string {
+	safe := strings.NewReplacer("/", "--", string(filepath.Separator), "--").Replace(actor)
+	return filepath.Join(townRoot, HeartbeatsDir, safe+".json")
+}
+
+// RecordHeartbeat stores hb as the agent's latest heartbeat and appends a
+// heartbeat event, unless the previous heartbeat is younger than every and
+// the topic is unchanged. Reports whether a heartbeat was recorded.
+func RecordHeartbeat(townRoot string, hb Heartbeat, every time.Duration) (bool, error) {
+	if hb.Actor == "" {
+		return false, fmt.Errorf("heartbeat actor is required")
+	}
+	if hb.Timestamp.IsZero() {
+		hb.Timestamp = time.Now().UTC()
+	}
+
+	path := heartbeatPath(townRoot, hb.Actor)
+	if data, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: path is constructed internally
+		var prev Heartbeat
+		if json.Unmarshal(data, &prev) == nil &&
+			hb.Timestamp.Sub(prev.Timestamp) < every &&
+			(hb.Topic == "" || hb.Topic == prev.Topic) &&
+			hb.SessionID == prev.SessionID {
+			return false, nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, fmt.Errorf("creating heartbeats directory: %w", err)
+	}
+	data, err := json.Marshal(hb)
+	if err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: liveness data is not sensitive
+		return false, fmt.Errorf("writing heartbeat: %w", err)
+	}
+
+	_ = LogAudit(TypeHeartbeat, hb.Actor, HeartbeatPayload(hb.SessionID, hb.Topic))
+	return true, nil
+}
+
+// LoadHeartbeats returns the latest heartbeat for every agent, by actor.
+// A town with no heartbeats yields an empty map.
+func LoadHeartbeats(townRoot string) (map[string]Heartbeat, error) {
+	result := make(map[string]Heartbeat)
+	dir := filepath.Join(townRoot, HeartbeatsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name())) //nolint:gosec // G304: path is within the heartbeats directory
+		if err != nil {
+			continue
+		}
+		var hb Heartbeat
+		if err := json.Unmarshal(data, &hb); err != nil || hb.Actor == "" {
+			continue
+		}
+		result[hb.Actor] = hb
+	}
+	return result, nil
+}
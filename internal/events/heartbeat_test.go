@@ -0,0 +1,63 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordHeartbeatThrottles(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Chdir(townRoot) // Keep heartbeat audit events out of the source tree
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	actor := "gastown/polecats/toast"
+
+	record := func(offset time.Duration, topic string) bool {
+		t.Helper()
+		ok, err := RecordHeartbeat(townRoot, Heartbeat{Actor: actor, Topic: topic, Timestamp: base.Add(offset)}, 5*time.Minute)
+		if err != nil {
+			t.Fatalf("RecordHeartbeat: %v", err)
+		}
+		return ok
+	}
+
+	if !record(0, "building") {
+		t.Error("first heartbeat should be recorded")
+	}
+	if record(time.Minute, "") {
+		t.Error("heartbeat within interval should be throttled")
+	}
+	if !record(2*time.Minute, "testing") {
+		t.Error("topic change should be recorded immediately")
+	}
+	if !record(8*time.Minute, "") {
+		t.Error("heartbeat after interval should be recorded")
+	}
+
+	heartbeats, err := LoadHeartbeats(townRoot)
+	if err != nil {
+		t.Fatalf("LoadHeartbeats: %v", err)
+	}
+	hb, ok := heartbeats[actor]
+	if !ok {
+		t.Fatalf("no heartbeat for %s: %v", actor, heartbeats)
+	}
+	if !hb.Timestamp.Equal(base.Add(8 * time.Minute)) {
+		t.Errorf("Timestamp = %v, want %v", hb.Timestamp, base.Add(8*time.Minute))
+	}
+}
+
+func TestLoadHeartbeatsEmpty(t *testing.T) {
+	heartbeats, err := LoadHeartbeats(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadHeartbeats: %v", err)
+	}
+	if len(heartbeats) != 0 {
+		t.Errorf("got %d heartbeats, want 0", len(heartbeats))
+	}
+}
+
+func TestRecordHeartbeatRequiresActor(t *testing.T) {
+	if _, err := RecordHeartbeat(t.TempDir(), Heartbeat{}, time.Minute); err == nil {
+		t.Error("expected error for missing actor")
+	}
+}
@@ -376,6 +376,16 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		}
 		return "Session terminated"
 
+	case events.TypeSessionCrashed:
+		agent, _ := event.Payload["agent"].(string)
+		if topic, _ := event.Payload["topic"].(string); agent != "" && topic != "" {
+			return fmt.Sprintf("%s crashed mid-task (%s)", agent, topic)
+		}
+		if agent != "" {
+			return fmt.Sprintf("%s crashed mid-task", agent)
+		}
+		return "Session crashed mid-task"
+
 	case events.TypeMassDeath:
 		count, _ := event.Payload["count"].(float64) // JSON numbers are float64
 		possibleCause, _ := event.Payload["possible_cause"].(string)
@@ -7,7 +7,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // GitError contains raw output from a git command for agent observation.
@@ -590,6 +592,151 @@ func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	return true, nil
 }
 
+// TopLevel returns the root of the working tree containing the work dir.
+func (g *Git) TopLevel() (string, error) {
+	return g.run("rev-parse", "--show-toplevel")
+}
+
+// Exclude adds pattern to the repository's info/exclude file unless it is
+// already listed, keeping local files out of git status without touching
+// .gitignore. Worktrees share their repository's exclude file.
+func (g *Git) Exclude(pattern string) error {
+	path, err := g.run("rev-parse", "--git-path", "info/exclude")
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(g.workDir, path)
+	}
+
+	content, err := os.ReadFile(path) //nolint:gosec // G304: path is from git rev-parse
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: exclude files are not secret
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		pattern = "\n" + pattern
+	}
+	_, err = f.WriteString(pattern + "\n")
+	return err
+}
+
+// LogEntry is one commit from the log.
+type LogEntry struct {
+	SHA     string    `json:"sha"` // abbreviated
+	Author  string    `json:"author"`
+	Time    time.Time `json:"time"`
+	Subject string    `json:"subject"`
+}
+
+// CommitsSince returns up to limit commits reachable from ref and
+// committed after since, newest first.
+func (g *Git) CommitsSince(ref string, since time.Time, limit int) ([]LogEntry, error) {
+	out, err := g.run("log", ref, "--no-merges", fmt.Sprintf("--max-count=%d", limit),
+		"--since="+since.UTC().Format(time.RFC3339), "--format=%h%x1f%an%x1f%cI%x1f%s")
+	if err != nil {
+		return nil, err
+	}
+	var entries []LogEntry
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\x1f", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		t, _ := time.Parse(time.RFC3339, parts[2])
+		entries = append(entries, LogEntry{SHA: parts[0], Author: parts[1], Time: t, Subject: parts[3]})
+	}
+	return entries, nil
+}
+
+// BlameLine is the commit that last changed one line of a file.
+type BlameLine struct {
+	Line    int       `json:"line"`   // 1-based
+	Commit  string    `json:"commit"` // full SHA; all zeros for uncommitted lines
+	Author  string    `json:"author"`
+	Time    time.Time `json:"time"` // committer time
+	Summary string    `json:"summary"`
+}
+
+// Uncommitted reports whether the line has changes not yet committed.
+func (b BlameLine) Uncommitted() bool {
+	return strings.Trim(b.Commit, "0") == ""
+}
+
+// Blame returns the commit that last changed each line of path (relative
+// to the work dir, or absolute), in line order.
+func (g *Git) Blame(path string) ([]BlameLine, error) {
+	out, err := g.run("blame", "--line-porcelain", "--", path)
+	if err != nil {
+		return nil, err
+	}
+	return parseBlamePorcelain(out), nil
+}
+
+// parseBlamePorcelain parses git blame --line-porcelain output, where
+// every line carries its commit's full header.
+func parseBlamePorcelain(out string) []BlameLine {
+	var lines []BlameLine
+	var cur BlameLine
+	for _, text := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(text, "\t"):
+			lines = append(lines, cur)
+			cur = BlameLine{}
+		case strings.HasPrefix(text, "author "):
+			cur.Author = strings.TrimPrefix(text, "author ")
+		case strings.HasPrefix(text, "committer-time "):
+			if secs, err := strconv.ParseInt(strings.TrimPrefix(text, "committer-time "), 10, 64); err == nil {
+				cur.Time = time.Unix(secs, 0)
+			}
+		case strings.HasPrefix(text, "summary "):
+			cur.Summary = strings.TrimPrefix(text, "summary ")
+		default:
+			// "<sha> <orig-line> <final-line> [<group-size>]" starts an entry
+			fields := strings.Fields(text)
+			if len(fields) >= 3 && (len(fields[0]) == 40 || len(fields[0]) == 64) {
+				cur.Commit = fields[0]
+				cur.Line, _ = strconv.Atoi(fields[2])
+			}
+		}
+	}
+	if cur.Commit != "" {
+		// run trims the tab off a blank last line
+		lines = append(lines, cur)
+	}
+	return lines
+}
+
+// IgnoredPaths returns which of paths (relative to the work dir) are
+// excluded by .gitignore rules. Tracked paths are never reported.
+func (g *Git) IgnoredPaths(paths ...string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	out, err := g.run(append([]string{"check-ignore", "--"}, paths...)...)
+	if err != nil {
+		// Exit code 1 means none are ignored, not an error
+		if strings.Contains(err.Error(), "exit status 1") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
 // WorktreeAdd creates a new worktree at the given path with a new branch.
 // The new branch is created from the current HEAD.
 // Sparse checkout is enabled to exclude .claude/ from source repos.
@@ -4,7 +4,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func initTestRepo(t *testing.T) string {
@@ -395,3 +397,126 @@ func TestCheckConflicts_WithConflict(t *testing.T) {
 		t.Error("expected clean working directory after CheckConflicts")
 	}
 }
+
+func TestIgnoredPaths(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("node_modules/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, sub := range []string{"node_modules", "build"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ignored, err := g.IgnoredPaths("node_modules", "build")
+	if err != nil {
+		t.Fatalf("IgnoredPaths: %v", err)
+	}
+	if len(ignored) != 1 || ignored[0] != "node_modules" {
+		t.Errorf("ignored = %v, want [node_modules]", ignored)
+	}
+
+	ignored, err = g.IgnoredPaths("build")
+	if err != nil || len(ignored) != 0 {
+		t.Errorf("IgnoredPaths(build) = %v, %v; want none", ignored, err)
+	}
+}
+
+func TestExclude(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	if err := os.WriteFile(filepath.Join(dir, "DIGEST.md"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := g.Exclude("/DIGEST.md"); err != nil {
+			t.Fatalf("Exclude: %v", err)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".git", "info", "exclude"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "/DIGEST.md\n"); n != 1 {
+		t.Errorf("exclude lists the pattern %d times, want 1:\n%s", n, data)
+	}
+
+	status, err := g.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Clean {
+		t.Errorf("expected excluded file to leave status clean, got %+v", status)
+	}
+}
+
+func TestCommitsSince(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	since := time.Now().Add(-time.Hour)
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Commit("add a"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := g.CommitsSince("HEAD", since, 10)
+	if err != nil {
+		t.Fatalf("CommitsSince: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Subject != "add a" || entries[1].Subject != "initial" {
+		t.Fatalf("entries = %+v, want [add a, initial]", entries)
+	}
+	if entries[0].Author != "Test User" || entries[0].SHA == "" || entries[0].Time.IsZero() {
+		t.Errorf("entry fields not parsed: %+v", entries[0])
+	}
+
+	if entries, _ := g.CommitsSince("HEAD", since, 1); len(entries) != 1 {
+		t.Errorf("limit 1 returned %d entries", len(entries))
+	}
+	if entries, _ := g.CommitsSince("HEAD", time.Now().Add(time.Hour), 10); len(entries) != 0 {
+		t.Errorf("future since returned %d entries", len(entries))
+	}
+}
+
+func TestBlame(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test\nmore\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Commit("add a line"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test\nmore\nwip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, err := g.Blame("README.md")
+	if err != nil {
+		t.Fatalf("Blame: %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("lines = %+v", lines)
+	}
+	if lines[0].Summary != "initial" || lines[1].Summary != "add a line" || lines[1].Line != 2 {
+		t.Errorf("committed lines = %+v", lines[:2])
+	}
+	if lines[0].Author != "Test User" || lines[0].Time.IsZero() || lines[0].Uncommitted() {
+		t.Errorf("line 1 = %+v", lines[0])
+	}
+	if !lines[2].Uncommitted() {
+		t.Errorf("line 3 = %+v, want uncommitted", lines[2])
+	}
+}
@@ -0,0 +1,183 @@
+// Package issuelink records which agent sessions worked on which issues.
+// References to beads (gt-123) and GitHub issues (#456) in a session's
+// beacon or opening prompts are appended to <town>/.runtime/issue-links.jsonl;
+// the most recent record for a session and issue wins, so a link can be
+// marked as commented by appending it again.
+package issuelink
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filename is the links log within the town .runtime directory.
+const Filename = "issue-links.jsonl"
+
+// Kind is the tracker an issue reference points at.
+type Kind string
+
+// Issue kinds.
+const (
+	KindBead   Kind = "bead"
+	KindGitHub Kind = "github"
+)
+
+// Ref is an issue reference found in session text.
+type Ref struct {
+	Kind Kind   `json:"kind"`
+	ID   string `json:"id"` // bead ID, or "#123" for GitHub
+}
+
+var (
+	// beadRefPattern matches prefix-id bead IDs whose id part contains a
+	// digit, so hyphenated words like "follow-up" don't match.
+	beadRefPattern = regexp.MustCompile(`\b([a-z][a-z0-9]{1,9})-([a-z0-9]*[0-9][a-z0-9]*(?:\.[0-9]+)*)\b`)
+
+	// githubRefPattern matches #123 not preceded by a word character.
+	githubRefPattern = regexp.MustCompile(`(?:^|[^\w&])#([0-9]{1,7})\b`)
+)
+
+// Extract returns the issue references in text, in order of first
+// appearance. Bead references must use one of prefixes (e.g., "gt", "hq");
+// with no prefixes, any prefix is accepted.
+func Extract(text string, prefixes []string) []Ref {
+	allowed := make(map[string]bool, len(prefixes))
+	for _, p := range prefixes {
+		allowed[strings.TrimSuffix(p, "-")] = true
+	}
+
+	type found struct {
+		ref Ref
+		pos int
+	}
+	var hits []found
+	for _, m := range beadRefPattern.FindAllStringSubmatchIndex(text, -1) {
+		prefix := text[m[2]:m[3]]
+		if len(allowed) > 0 && !allowed[prefix] {
+			continue
+		}
+		hits = append(hits, found{Ref{KindBead, text[m[0]:m[1]]}, m[0]})
+	}
+	for _, m := range githubRefPattern.FindAllStringSubmatchIndex(text, -1) {
+		hits = append(hits, found{Ref{KindGitHub, "#" + text[m[2]:m[3]]}, m[2]})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].pos < hits[j].pos })
+
+	seen := make(map[Ref]bool)
+	var refs []Ref
+	for _, h := range hits {
+		if !seen[h.ref] {
+			seen[h.ref] = true
+			refs = append(refs, h.ref)
+		}
+	}
+	return refs
+}
+
+// Record links a session to an issue.
+type Record struct {
+	SessionID   string    `json:"session_id"`
+	Issue       string    `json:"issue"`
+	Kind        Kind      `json:"kind"`
+	Role        string    `json:"role,omitempty"` // beacon role of the session
+	Source      string    `json:"source"`         // "beacon" or "prompt"
+	Commented   bool      `json:"commented,omitempty"`
+	CommentedAt time.Time `json:"commented_at,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Path returns the links log path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", Filename)
+}
+
+// Append adds records to the town's links log.
+func Append(townRoot string, records ...Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	var buf []byte
+	for _, rec := range records {
+		if rec.Timestamp.IsZero() {
+			rec.Timestamp = time.Now().UTC()
+		}
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G302: links are not secret
+	if err != nil {
+		return fmt.Errorf("opening issue links log: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(buf)
+	return err
+}
+
+// Load returns the latest record per session and issue, oldest first.
+// A missing log yields no records.
+func Load(townRoot string) ([]Record, error) {
+	f, err := os.Open(Path(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening issue links log: %w", err)
+	}
+	defer f.Close()
+
+	type key struct{ session, issue string }
+	latest := make(map[key]Record)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.SessionID == "" || rec.Issue == "" {
+			continue
+		}
+		k := key{rec.SessionID, rec.Issue}
+		if prev, ok := latest[k]; !ok || !rec.Timestamp.Before(prev.Timestamp) {
+			latest[k] = rec
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(latest))
+	for _, rec := range latest {
+		records = append(records, rec)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].Timestamp.Equal(records[j].Timestamp) {
+			return records[i].Timestamp.Before(records[j].Timestamp)
+		}
+		return records[i].Issue < records[j].Issue
+	})
+	return records, nil
+}
+
+// Filter returns the records for a session ID (or ID prefix) or an issue.
+func Filter(records []Record, query string) []Record {
+	var out []Record
+	for _, rec := range records {
+		if rec.Issue == query || strings.HasPrefix(rec.SessionID, query) {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
@@ -0,0 +1,135 @@
+package issuelink
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+func TestExtract(t *testing.T) {
+	text := "[GAS TOWN] gastown/polecats/toast <- mayor • work on gt-123 and hq-ab1, see #456; follow-up in gt-123 (PR#7 ignored, x-99 ignored)"
+	got := Extract(text, []string{"gt", "hq-"})
+	want := []Ref{
+		{KindBead, "gt-123"},
+		{KindBead, "hq-ab1"},
+		{KindGitHub, "#456"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract = %+v, want %+v", got, want)
+	}
+
+	got = Extract("fix ab-99.2", nil)
+	if len(got) != 1 || got[0].ID != "ab-99.2" {
+		t.Errorf("Extract with no prefixes = %+v", got)
+	}
+}
+
+func TestAppendLoadLatestWins(t *testing.T) {
+	town := t.TempDir()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	recs := []Record{
+		{SessionID: "s1", Issue: "gt-1", Kind: KindBead, Timestamp: base},
+		{SessionID: "s2", Issue: "gt-1", Kind: KindBead, Timestamp: base.Add(time.Minute)},
+		{SessionID: "s1", Issue: "gt-1", Kind: KindBead, Commented: true, Timestamp: base.Add(2 * time.Minute)},
+	}
+	if err := Append(town, recs...); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	got, err := Load(town)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d records, want 2", len(got))
+	}
+	if got[1].SessionID != "s1" || !got[1].Commented {
+		t.Errorf("latest s1 = %+v", got[1])
+	}
+
+	if f := Filter(got, "gt-1"); len(f) != 2 {
+		t.Errorf("Filter(issue) = %d records, want 2", len(f))
+	}
+	if f := Filter(got, "s2"); len(f) != 1 || f[0].SessionID != "s2" {
+		t.Errorf("Filter(session) = %+v", f)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	got, err := Load(t.TempDir())
+	if err != nil || got != nil {
+		t.Errorf("Load on empty town = %v, %v", got, err)
+	}
+}
+
+func TestScanLinksAndCommentsOnce(t *testing.T) {
+	town := t.TempDir()
+	path := filepath.Join(t.TempDir(), "s1.jsonl")
+	transcript := strings.Join([]string{
+		`{"type":"user","timestamp":"2025-01-01T10:00:00Z","message":{"role":"user","content":"[GAS TOWN] gastown/polecats/toast <- mayor • gt-42"}}`,
+		`{"type":"user","timestamp":"2025-01-01T10:01:00Z","message":{"role":"user","content":"also see #9"}}`,
+		`{"type":"assistant","timestamp":"2025-01-01T10:45:00Z","message":{"role":"assistant","content":[{"type":"text","text":"Fixed the parser."}]}}`,
+	}, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(transcript), 0644); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	sessions := []claude.SessionInfo{{
+		ID: "s1-uuid", Path: path, IsGasTown: true, StartTime: start,
+		Role: "gastown/polecats/toast", Topic: "gt-42",
+	}}
+
+	var posted []string
+	var body string
+	opts := ScanOptions{
+		Now:      time.Now(),
+		Prefixes: []string{"gt"},
+		Commenter: func(_ string, _ claude.SessionInfo, rec Record, b string) error {
+			posted = append(posted, rec.Issue)
+			body = b
+			return nil
+		},
+	}
+
+	// Transcript was just written: link, but don't comment yet.
+	opts.Comment = true
+	result, err := Scan(town, sessions, opts)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(result.Linked) != 2 || len(posted) != 0 {
+		t.Fatalf("first scan linked %d, posted %v", len(result.Linked), posted)
+	}
+	if result.Linked[0].Source != "beacon" || result.Linked[1].Source != "prompt" {
+		t.Errorf("sources = %q, %q", result.Linked[0].Source, result.Linked[1].Source)
+	}
+
+	// Once idle, comment on the bead only (GitHub comments are off).
+	opts.Now = opts.Now.Add(time.Hour)
+	result, err = Scan(town, sessions, opts)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(result.Linked) != 0 || !reflect.DeepEqual(posted, []string{"gt-42"}) {
+		t.Fatalf("second scan linked %d, posted %v", len(result.Linked), posted)
+	}
+	for _, want := range []string{"s1-uuid", "gastown/polecats/toast", "45m", "Fixed the parser.", "gt seance show s1-uuid"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("comment body missing %q:\n%s", want, body)
+		}
+	}
+
+	// Already commented: nothing more to post.
+	if _, err := Scan(town, sessions, opts); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(posted) != 1 {
+		t.Errorf("posted again: %v", posted)
+	}
+}
@@ -0,0 +1,258 @@
+package issuelink
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// promptTurns is how many user turns, beacon included, are searched for
+// issue references. Later turns tend to mention issues in passing.
+const promptTurns = 3
+
+// maxSummaryLen caps the session summary quoted in issue comments.
+const maxSummaryLen = 600
+
+// Commenter posts a comment on a linked issue.
+type Commenter func(townRoot string, session claude.SessionInfo, rec Record, body string) error
+
+// ScanOptions controls a linking pass.
+type ScanOptions struct {
+	// Since skips sessions whose transcript was last written before it.
+	Since time.Time
+
+	// Now is the time of the pass (default time.Now()).
+	Now time.Time
+
+	// IdleAfter is how long a transcript must go unwritten before the
+	// session counts as ended and its issues are commented on.
+	IdleAfter time.Duration
+
+	// Comment posts a summary on linked beads of ended sessions.
+	Comment bool
+
+	// GitHub also comments on linked GitHub issues.
+	GitHub bool
+
+	// Prefixes restricts bead references (see Extract).
+	Prefixes []string
+
+	// Commenter posts comments (default PostComment).
+	Commenter Commenter
+}
+
+// ScanResult summarizes a linking pass.
+type ScanResult struct {
+	Linked    []Record `json:"linked,omitempty"`
+	Commented []Record `json:"commented,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// Scan records issue links for Gas Town sessions and, when enabled,
+// comments on the issues of sessions that have ended. Links already in the
+// log are not recorded again, and each link is commented on at most once.
+func Scan(townRoot string, sessions []claude.SessionInfo, opts ScanOptions) (*ScanResult, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.IdleAfter <= 0 {
+		opts.IdleAfter = config.DefaultIssueLinkIdleAfter
+	}
+	if opts.Commenter == nil {
+		opts.Commenter = PostComment
+	}
+
+	existing, err := Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	type key struct{ session, issue string }
+	known := make(map[key]Record, len(existing))
+	for _, rec := range existing {
+		known[key{rec.SessionID, rec.Issue}] = rec
+	}
+
+	result := &ScanResult{}
+	for _, s := range sessions {
+		if !s.IsGasTown {
+			continue
+		}
+		stat, err := os.Stat(s.Path)
+		if err != nil || stat.ModTime().Before(opts.Since) {
+			continue
+		}
+
+		var links []Record
+		for _, ref := range sessionRefs(s, opts.Prefixes) {
+			rec, ok := known[key{s.ID, ref.Issue}]
+			if !ok {
+				rec = ref
+				rec.Timestamp = opts.Now.UTC()
+				if err := Append(townRoot, rec); err != nil {
+					return result, err
+				}
+				known[key{s.ID, rec.Issue}] = rec
+				result.Linked = append(result.Linked, rec)
+			}
+			links = append(links, rec)
+		}
+
+		if !opts.Comment || opts.Now.Sub(stat.ModTime()) < opts.IdleAfter {
+			continue
+		}
+		var body string
+		for _, rec := range links {
+			if rec.Commented || (rec.Kind == KindGitHub && !opts.GitHub) {
+				continue
+			}
+			if body == "" {
+				body = CommentBody(s)
+			}
+			if err := opts.Commenter(townRoot, s, rec, body); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s on %s: %v", s.ShortID(), rec.Issue, err))
+				continue
+			}
+			rec.Commented = true
+			rec.CommentedAt = opts.Now.UTC()
+			rec.Timestamp = opts.Now.UTC()
+			if err := Append(townRoot, rec); err != nil {
+				return result, err
+			}
+			result.Commented = append(result.Commented, rec)
+		}
+	}
+	return result, nil
+}
+
+// sessionRefs returns new link records for the issues referenced by a
+// session's beacon topic and opening prompts.
+func sessionRefs(s claude.SessionInfo, prefixes []string) []Record {
+	var refs []Record
+	seen := make(map[string]bool)
+	add := func(text, source string) {
+		for _, ref := range Extract(text, prefixes) {
+			if seen[ref.ID] {
+				continue
+			}
+			seen[ref.ID] = true
+			refs = append(refs, Record{
+				SessionID: s.ID,
+				Issue:     ref.ID,
+				Kind:      ref.Kind,
+				Role:      s.Role,
+				Source:    source,
+			})
+		}
+	}
+
+	add(s.Topic, "beacon")
+	for i, text := range openingPrompts(s.Path, promptTurns) {
+		source := "prompt"
+		if i == 0 && strings.Contains(text, "[GAS TOWN]") {
+			source = "beacon"
+		}
+		add(text, source)
+	}
+	return refs
+}
+
+// openingPrompts returns the text of the first n user turns of a
+// transcript without reading the rest of it.
+func openingPrompts(path string, n int) []string {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a session transcript
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var prompts []string
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+	for scanner.Scan() && len(prompts) < n {
+		if turn, ok := claude.ParseTurn(scanner.Bytes()); ok && turn.Role == "user" && turn.Text != "" {
+			prompts = append(prompts, turn.Text)
+		}
+	}
+	return prompts
+}
+
+// CommentBody formats the comment posted on an issue when a linked session
+// ends: who worked on it, for how long, what happened, and how to see more.
+func CommentBody(s claude.SessionInfo) string {
+	var b strings.Builder
+	role := s.Role
+	if role == "" {
+		role = "agent"
+	}
+	fmt.Fprintf(&b, "Session %s (%s) ended", s.ShortID(), role)
+
+	turns, _ := claude.ReadTurns(s.Path)
+	if len(turns) > 0 && !s.StartTime.IsZero() {
+		if d := turns[len(turns)-1].Timestamp.Sub(s.StartTime); d > 0 {
+			fmt.Fprintf(&b, " after %s", d.Round(time.Minute))
+		}
+	}
+	b.WriteString(".\n")
+
+	summary := s.Summary
+	if summary == "" {
+		for i := len(turns) - 1; i >= 0; i-- {
+			if turns[i].Role == "assistant" && turns[i].Text != "" {
+				summary = turns[i].Text
+				break
+			}
+		}
+	}
+	if summary != "" {
+		if r := []rune(summary); len(r) > maxSummaryLen {
+			summary = strings.TrimSpace(string(r[:maxSummaryLen])) + "…"
+		}
+		fmt.Fprintf(&b, "\n%s\n", summary)
+	}
+	fmt.Fprintf(&b, "\nTranscript: gt seance show %s\n", s.ShortID())
+	return b.String()
+}
+
+// PostComment comments on a bead with bd, or on a GitHub issue with gh in
+// the session's working directory.
+func PostComment(townRoot string, s claude.SessionInfo, rec Record, body string) error {
+	var cmd *exec.Cmd
+	switch rec.Kind {
+	case KindBead:
+		cmd = exec.Command("bd", "comment", rec.Issue, body) //nolint:gosec // G204: issue ID from link log
+		cmd.Dir = townRoot
+	case KindGitHub:
+		cmd = exec.Command("gh", "issue", "comment", strings.TrimPrefix(rec.Issue, "#"), "--body", body) //nolint:gosec // G204: issue number from link log
+		cmd.Dir = s.ProjectPath
+	default:
+		return fmt.Errorf("unknown issue kind %q", rec.Kind)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Prefixes returns the bead prefixes known to a town: "hq" for town beads
+// plus each rig's configured prefix.
+func Prefixes(townRoot string) []string {
+	prefixes := []string{"hq"}
+	rigs, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return prefixes
+	}
+	for _, entry := range rigs.Rigs {
+		if entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" {
+			prefixes = append(prefixes, strings.TrimSuffix(entry.BeadsConfig.Prefix, "-"))
+		}
+	}
+	return prefixes
+}
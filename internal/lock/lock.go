@@ -16,8 +16,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"syscall"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 // Common errors
@@ -195,19 +196,7 @@ func (l *Lock) write(sessionID string) error {
 
 // processExists checks if a process with the given PID exists and is alive.
 func processExists(pid int) bool {
-	if pid <= 0 {
-		return false
-	}
-
-	// On Unix, sending signal 0 checks if process exists without affecting it
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-
-	// Try to send signal 0 - this will fail if process doesn't exist
-	err = process.Signal(syscall.Signal(0))
-	return err == nil
+	return util.ProcessAlive(pid)
 }
 
 // FindAllLocks scans a directory tree for agent.lock files.
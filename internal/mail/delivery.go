@@ -0,0 +1,440 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DeliveryState is the delivery state of a direct message.
+type DeliveryState string
+
+const (
+	// DeliveryQueued means the message is waiting for its recipient: either
+	// it couldn't be stored yet, or the recipient had no active session.
+	DeliveryQueued DeliveryState = "queued"
+
+	// DeliveryDelivered means the recipient was notified in a live session.
+	DeliveryDelivered DeliveryState = "delivered"
+
+	// DeliveryRead means the recipient has read the message.
+	DeliveryRead DeliveryState = "read"
+
+	// DeliveryExpired means the message stayed undelivered past its expiry
+	// and its sender was told.
+	DeliveryExpired DeliveryState = "expired"
+)
+
+// deliveryDir holds one record per direct message, relative to the town root.
+const deliveryDir = ".runtime/mail-delivery"
+
+// deliveryRetention is how long read and expired records are kept.
+const deliveryRetention = 7 * 24 * time.Hour
+
+// PostmasterAddress is the sender of delivery failure notices.
+const PostmasterAddress = "postmaster/"
+
+// DeliveryRecord tracks one direct message until its recipient reads it.
+type DeliveryRecord struct {
+	ID          string        `json:"id"`
+	BeadID      string        `json:"bead_id,omitempty"`
+	Stored      bool          `json:"stored"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Subject     string        `json:"subject"`
+	Priority    Priority      `json:"priority,omitempty"`
+	State       DeliveryState `json:"state"`
+	Attempts    int           `json:"attempts"`
+	QueuedAt    time.Time     `json:"queued_at"`
+	NextAttempt time.Time     `json:"next_attempt,omitempty"`
+	DeliveredAt time.Time     `json:"delivered_at,omitempty"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+	LastError   string        `json:"last_error,omitempty"`
+
+	// Message is the full message, kept only until it has been stored in
+	// beads so a failed store can be retried.
+	Message *Message `json:"message,omitempty"`
+}
+
+// Pending returns true if the record still needs delivery work.
+func (d *DeliveryRecord) Pending() bool {
+	return d.State == DeliveryQueued
+}
+
+// Due returns true if a queued record should be retried at now.
+func (d *DeliveryRecord) Due(now time.Time) bool {
+	return d.Pending() && !now.Before(d.NextAttempt)
+}
+
+// Expired returns true if a queued record has outlived its expiry.
+func (d *DeliveryRecord) Expired(now time.Time) bool {
+	return d.Pending() && !d.ExpiresAt.IsZero() && now.After(d.ExpiresAt)
+}
+
+// deferRetry records a failed attempt and schedules the next one.
+func (d *DeliveryRecord) deferRetry(policy *config.DeliveryPolicy, now time.Time, reason string) {
+	d.Attempts++
+	d.LastError = reason
+	d.NextAttempt = now.Add(policy.RetryDelay(d.Attempts))
+	d.UpdatedAt = now
+}
+
+// markState moves the record to a new state.
+func (d *DeliveryRecord) markState(state DeliveryState, now time.Time) {
+	d.State = state
+	d.UpdatedAt = now
+	d.NextAttempt = time.Time{}
+	if state == DeliveryDelivered && d.DeliveredAt.IsZero() {
+		d.DeliveredAt = now
+	}
+	if state != DeliveryQueued {
+		d.LastError = ""
+	}
+}
+
+// DeliveryLedger stores delivery records as one JSON file per message under
+// the town's runtime directory. Each record is written atomically; retry
+// passes are serialized with a file lock so concurrent senders, the daemon,
+// and 'gt mail retry' never notify a recipient twice for one pass.
+type DeliveryLedger struct {
+	dir string
+}
+
+// NewDeliveryLedger returns the delivery ledger for a town.
+func NewDeliveryLedger(townRoot string) *DeliveryLedger {
+	return &DeliveryLedger{dir: filepath.Join(townRoot, deliveryDir)}
+}
+
+func (l *DeliveryLedger) path(id string) string {
+	return filepath.Join(l.dir, id+".json")
+}
+
+// Save writes a record.
+func (l *DeliveryLedger) Save(rec *DeliveryRecord) error {
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return fmt.Errorf("creating delivery directory: %w", err)
+	}
+	return util.AtomicWriteJSON(l.path(rec.ID), rec)
+}
+
+// Load reads a record by ID.
+func (l *DeliveryLedger) Load(id string) (*DeliveryRecord, error) {
+	data, err := os.ReadFile(l.path(id)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, err
+	}
+	var rec DeliveryRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("parsing delivery record %s: %w", id, err)
+	}
+	return &rec, nil
+}
+
+// Remove deletes a record.
+func (l *DeliveryLedger) Remove(id string) error {
+	if err := os.Remove(l.path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List returns all records, oldest first. Unreadable records are skipped.
+func (l *DeliveryLedger) List() ([]*DeliveryRecord, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading delivery directory: %w", err)
+	}
+	var records []*DeliveryRecord
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		rec, err := l.Load(strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue
+		}
+		records = append(records, rec)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].QueuedAt.Before(records[j].QueuedAt) })
+	return records, nil
+}
+
+// Find returns the record for a delivery ID or bead message ID.
+func (l *DeliveryLedger) Find(id string) (*DeliveryRecord, error) {
+	if rec, err := l.Load(id); err == nil {
+		return rec, nil
+	}
+	records, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		if rec.BeadID == id {
+			return rec, nil
+		}
+	}
+	return nil, ErrMessageNotFound
+}
+
+// tryLock takes the ledger's retry lock without blocking. The returned
+// function releases it. ok is false if another process holds the lock.
+func (l *DeliveryLedger) tryLock() (unlock func(), ok bool, err error) {
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return nil, false, fmt.Errorf("creating delivery directory: %w", err)
+	}
+	lock := flock.New(filepath.Join(l.dir, ".lock"))
+	locked, err := lock.TryLock()
+	if err != nil {
+		return nil, false, fmt.Errorf("locking delivery ledger: %w", err)
+	}
+	if !locked {
+		return nil, false, nil
+	}
+	return func() { _ = lock.Unlock() }, true, nil
+}
+
+// RetryResult summarizes a retry pass.
+type RetryResult struct {
+	Stored    int // Messages stored in beads after an earlier failure
+	Delivered int // Queued messages whose recipient was notified
+	Read      int // Messages found read since the last pass
+	Expired   int // Messages that expired undelivered
+	Pending   int // Messages still queued after the pass
+	Pruned    int // Old read/expired records removed
+	Skipped   bool
+}
+
+// deliveryPolicy loads the town's delivery policy, or nil for defaults.
+func (r *Router) deliveryPolicy() *config.DeliveryPolicy {
+	if r.townRoot == "" {
+		return nil
+	}
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot))
+	if err != nil || cfg == nil {
+		return nil
+	}
+	return cfg.Delivery
+}
+
+// ledger returns the router's delivery ledger, or nil without a town root.
+func (r *Router) ledger() *DeliveryLedger {
+	if r.townRoot == "" {
+		return nil
+	}
+	return NewDeliveryLedger(r.townRoot)
+}
+
+// DeliveryStatus returns the delivery record for a delivery or message ID.
+func (r *Router) DeliveryStatus(id string) (*DeliveryRecord, error) {
+	ledger := r.ledger()
+	if ledger == nil {
+		return nil, ErrMessageNotFound
+	}
+	return ledger.Find(id)
+}
+
+// ListDeliveries returns all delivery records, oldest first.
+func (r *Router) ListDeliveries() ([]*DeliveryRecord, error) {
+	ledger := r.ledger()
+	if ledger == nil {
+		return nil, nil
+	}
+	return ledger.List()
+}
+
+// MarkDelivered marks every stored, queued message for address as
+// delivered. Called when the recipient checks its inbox from a live
+// session, which covers mail sent while it had no session at all.
+func (r *Router) MarkDelivered(address string) (int, error) {
+	ledger := r.ledger()
+	if ledger == nil {
+		return 0, nil
+	}
+	records, err := ledger.List()
+	if err != nil {
+		return 0, err
+	}
+	identity := addressToIdentity(address)
+	now := time.Now()
+	count := 0
+	for _, rec := range records {
+		if !rec.Pending() || !rec.Stored || addressToIdentity(rec.To) != identity {
+			continue
+		}
+		rec.markState(DeliveryDelivered, now)
+		if err := ledger.Save(rec); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// RetryDeliveries runs one retry pass over the ledger:
+//   - messages that failed to store are stored again
+//   - queued messages whose recipient now has a session are announced
+//   - delivered messages the recipient has read move to read
+//   - queued messages past expiry move to expired and their sender is told
+//   - read and expired records older than a week are pruned
+//
+// If another process is already running a pass, the result has Skipped set.
+func (r *Router) RetryDeliveries(now time.Time) (*RetryResult, error) {
+	ledger := r.ledger()
+	if ledger == nil {
+		return &RetryResult{}, nil
+	}
+	unlock, ok, err := ledger.tryLock()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &RetryResult{Skipped: true}, nil
+	}
+	defer unlock()
+
+	records, err := ledger.List()
+	if err != nil {
+		return nil, err
+	}
+	policy := r.deliveryPolicy()
+	result := &RetryResult{}
+	for _, rec := range records {
+		before := *rec
+		r.retryRecord(rec, policy, now, result)
+		if rec.State == DeliveryQueued {
+			result.Pending++
+		}
+		if (rec.State == DeliveryRead || rec.State == DeliveryExpired) && now.Sub(rec.UpdatedAt) > deliveryRetention {
+			if err := ledger.Remove(rec.ID); err == nil {
+				result.Pruned++
+			}
+			continue
+		}
+		if rec.UpdatedAt != before.UpdatedAt {
+			if err := ledger.Save(rec); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// retryRecord advances one record and tallies the outcome in result.
+func (r *Router) retryRecord(rec *DeliveryRecord, policy *config.DeliveryPolicy, now time.Time, result *RetryResult) {
+	switch rec.State {
+	case DeliveryRead, DeliveryExpired:
+		return
+	case DeliveryDelivered:
+		if r.messageRead(rec) {
+			rec.markState(DeliveryRead, now)
+			result.Read++
+		}
+		return
+	}
+
+	if rec.Stored && r.messageRead(rec) {
+		rec.markState(DeliveryRead, now)
+		result.Read++
+		return
+	}
+	if rec.Expired(now) {
+		rec.markState(DeliveryExpired, now)
+		result.Expired++
+		r.bounce(rec)
+		return
+	}
+	if !rec.Due(now) {
+		return
+	}
+
+	if !rec.Stored {
+		if err := r.storeRecord(rec); err != nil {
+			rec.deferRetry(policy, now, err.Error())
+			return
+		}
+		rec.UpdatedAt = now
+		result.Stored++
+	}
+	if isSelfMail(rec.From, rec.To) {
+		// Handoffs wait for the next session, which marks them delivered
+		// when it checks its inbox.
+		rec.NextAttempt = time.Time{}
+		return
+	}
+	if notified, err := r.notifyRecipient(&Message{From: rec.From, To: rec.To, Subject: rec.Subject}); notified {
+		rec.markState(DeliveryDelivered, now)
+		result.Delivered++
+	} else {
+		reason := "recipient has no active session"
+		if err != nil {
+			reason = err.Error()
+		}
+		rec.deferRetry(policy, now, reason)
+	}
+}
+
+// messageRead returns true if the recipient has read the stored message.
+// Errors are treated as unread so the record is checked again next pass.
+func (r *Router) messageRead(rec *DeliveryRecord) bool {
+	if rec.BeadID == "" {
+		return false
+	}
+	mailbox, err := r.GetMailbox(rec.To)
+	if err != nil {
+		return false
+	}
+	msg, err := mailbox.Get(rec.BeadID)
+	if err != nil {
+		return false
+	}
+	return msg.Read
+}
+
+// bounce tells the sender that a message expired undelivered.
+// Best-effort: bounce failures are not retried.
+func (r *Router) bounce(rec *DeliveryRecord) {
+	if isSelfMail(rec.From, rec.To) || rec.From == PostmasterAddress || rec.From == "" {
+		return
+	}
+	body := fmt.Sprintf("Your message to %s was not delivered before it expired.\n\nSubject: %s\nQueued: %s\nAttempts: %d",
+		rec.To, rec.Subject, rec.QueuedAt.Local().Format("2006-01-02 15:04"), rec.Attempts)
+	if rec.LastError != "" {
+		body += "\nLast error: " + rec.LastError
+	}
+	if rec.Stored {
+		body += "\n\nThe message is still in the recipient's inbox."
+	}
+	notice := NewMessage(PostmasterAddress, rec.From, "Undelivered: "+rec.Subject, body)
+	_, _ = r.store(notice)
+}
+
+// storeRecord stores a queued record's message in beads.
+func (r *Router) storeRecord(rec *DeliveryRecord) error {
+	if rec.Message == nil {
+		return fmt.Errorf("delivery record %s has no message", rec.ID)
+	}
+	beadID, err := r.store(rec.Message)
+	if err != nil {
+		return err
+	}
+	rec.Stored = true
+	rec.BeadID = beadID
+	rec.Message = nil
+	return nil
+}
@@ -0,0 +1,142 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// installFakeBd puts a bd on PATH that logs its arguments and fails
+// "create" while the returned fail file exists.
+func installFakeBd(t *testing.T) (failFile, logFile string) {
+	t.Helper()
+	binDir := t.TempDir()
+	failFile = filepath.Join(binDir, "fail")
+	logFile = filepath.Join(binDir, "log")
+	script := `#!/usr/bin/env bash
+echo "$@" >> "` + logFile + `"
+case "$1" in
+  create)
+    if [[ -e "` + failFile + `" ]]; then echo "database locked" >&2; exit 1; fi
+    echo '{"id":"hq-1"}' ;;
+  show) echo '[]' ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return failFile, logFile
+}
+
+func TestDeliveryRetryAndExpiry(t *testing.T) {
+	townRoot := t.TempDir()
+	failFile, logFile := installFakeBd(t)
+	if err := os.WriteFile(failFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	router := NewRouterWithTownRoot(townRoot, townRoot)
+	msg := NewMessage("mayor/", "gastown/crew/nobody", "Review needed", "Please review")
+	if err := router.Send(msg); err != nil {
+		t.Fatalf("Send with failing store should queue, got %v", err)
+	}
+
+	rec := router.LastDelivery()
+	if rec == nil || rec.State != DeliveryQueued || rec.Stored || rec.Attempts != 1 {
+		t.Fatalf("after failed store: %+v", rec)
+	}
+	if !strings.Contains(rec.LastError, "database locked") {
+		t.Errorf("LastError = %q", rec.LastError)
+	}
+
+	// Store succeeds on retry, but the recipient has no session.
+	if err := os.Remove(failFile); err != nil {
+		t.Fatal(err)
+	}
+	result, err := router.RetryDeliveries(rec.NextAttempt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Stored != 1 || result.Pending != 1 || result.Delivered != 0 {
+		t.Errorf("retry result = %+v", result)
+	}
+	rec, err = router.DeliveryStatus("hq-1")
+	if err != nil {
+		t.Fatalf("DeliveryStatus by bead ID: %v", err)
+	}
+	if !rec.Stored || rec.Message != nil || rec.State != DeliveryQueued {
+		t.Errorf("after stored retry: %+v", rec)
+	}
+
+	// Past expiry the message expires and the sender is told.
+	result, err = router.RetryDeliveries(rec.ExpiresAt.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Expired != 1 || result.Pending != 0 {
+		t.Errorf("expiry result = %+v", result)
+	}
+	log, _ := os.ReadFile(logFile)
+	if !strings.Contains(string(log), "Undelivered: Review needed") || !strings.Contains(string(log), "--assignee mayor/") {
+		t.Errorf("no bounce sent to sender; bd log:\n%s", log)
+	}
+
+	// Expired records are pruned after the retention period.
+	result, err = router.RetryDeliveries(rec.ExpiresAt.Add(deliveryRetention + time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Pruned != 1 {
+		t.Errorf("prune result = %+v", result)
+	}
+}
+
+func TestMarkDelivered(t *testing.T) {
+	townRoot := t.TempDir()
+	ledger := NewDeliveryLedger(townRoot)
+	now := time.Now()
+	for _, rec := range []*DeliveryRecord{
+		{ID: "a", To: "gastown/crew/max", Stored: true, State: DeliveryQueued, QueuedAt: now},
+		{ID: "b", To: "gastown/crew/max", Stored: false, State: DeliveryQueued, QueuedAt: now},
+		{ID: "c", To: "gastown/crew/joe", Stored: true, State: DeliveryQueued, QueuedAt: now},
+	} {
+		if err := ledger.Save(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := NewRouterWithTownRoot(townRoot, townRoot).MarkDelivered("gastown/crew/max")
+	if err != nil || n != 1 {
+		t.Fatalf("MarkDelivered = %d, %v; want 1", n, err)
+	}
+	for id, want := range map[string]DeliveryState{"a": DeliveryDelivered, "b": DeliveryQueued, "c": DeliveryQueued} {
+		rec, err := ledger.Load(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.State != want {
+			t.Errorf("%s: state = %s, want %s", id, rec.State, want)
+		}
+	}
+}
+
+func TestDeliveryRecordDue(t *testing.T) {
+	now := time.Now()
+	rec := &DeliveryRecord{State: DeliveryQueued, NextAttempt: now.Add(time.Minute), ExpiresAt: now.Add(time.Hour)}
+	if rec.Due(now) || rec.Expired(now) {
+		t.Error("record should not be due or expired yet")
+	}
+	if !rec.Due(now.Add(time.Minute)) {
+		t.Error("record should be due at NextAttempt")
+	}
+	if !rec.Expired(now.Add(2 * time.Hour)) {
+		t.Error("record should be expired after ExpiresAt")
+	}
+	rec.State = DeliveryDelivered
+	if rec.Due(now.Add(time.Hour)) || rec.Expired(now.Add(2*time.Hour)) {
+		t.Error("delivered record is never due or expired")
+	}
+}
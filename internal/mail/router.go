@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/session"
@@ -30,6 +31,8 @@ type Router struct {
 	workDir  string // fallback directory to run bd commands in
 	townRoot string // town root directory (e.g., ~/gt)
 	tmux     *tmux.Tmux
+
+	lastDelivery *DeliveryRecord // most recent direct send, for callers reporting status
 }
 
 // NewRouter creates a new mail router.
@@ -561,6 +564,71 @@ func (r *Router) sendToGroup(msg *Message) error {
 
 // sendToSingle sends a message to a single recipient.
 func (r *Router) sendToSingle(msg *Message) error {
+	ledger := r.ledger()
+	if ledger == nil {
+		// No town: nowhere to queue, so deliver directly or fail.
+		if _, err := r.store(msg); err != nil {
+			return err
+		}
+		if !isSelfMail(msg.From, msg.To) {
+			_, _ = r.notifyRecipient(msg)
+		}
+		return nil
+	}
+
+	// Record the message before storing it, so a failed store is retried
+	// rather than lost. The first retry is scheduled up front so a
+	// concurrent retry pass doesn't store it a second time.
+	policy := r.deliveryPolicy()
+	now := time.Now()
+	rec := &DeliveryRecord{
+		ID:          generateID(),
+		From:        msg.From,
+		To:          msg.To,
+		Subject:     msg.Subject,
+		Priority:    msg.Priority,
+		State:       DeliveryQueued,
+		QueuedAt:    now,
+		NextAttempt: now.Add(policy.RetryDelay(1)),
+		UpdatedAt:   now,
+		ExpiresAt:   now.Add(policy.Expiry(string(msg.Priority))),
+		Message:     msg,
+	}
+	if err := ledger.Save(rec); err != nil {
+		return fmt.Errorf("queueing message: %w", err)
+	}
+	r.lastDelivery = rec
+
+	if err := r.storeRecord(rec); err != nil {
+		rec.deferRetry(policy, now, err.Error())
+		return ledger.Save(rec)
+	}
+
+	// Notify recipient if they have an active session. Without one the
+	// message stays queued and is announced on a later retry pass.
+	// Self-mail (handoffs to future-self) waits for the next session.
+	if isSelfMail(msg.From, msg.To) {
+		rec.NextAttempt = time.Time{}
+	} else if notified, err := r.notifyRecipient(msg); notified {
+		rec.markState(DeliveryDelivered, now)
+	} else {
+		reason := "recipient has no active session"
+		if err != nil {
+			reason = err.Error()
+		}
+		rec.deferRetry(policy, now, reason)
+	}
+	return ledger.Save(rec)
+}
+
+// LastDelivery returns the delivery record of the most recent direct
+// message sent through this router, or nil.
+func (r *Router) LastDelivery() *DeliveryRecord {
+	return r.lastDelivery
+}
+
+// store creates the message bead and returns its ID.
+func (r *Router) store(msg *Message) (string, error) {
 	// Convert addresses to beads identities
 	toIdentity := addressToIdentity(msg.To)
 
@@ -602,20 +670,20 @@ func (r *Router) sendToSingle(msg *Message) error {
 	if r.shouldBeWisp(msg) {
 		args = append(args, "--ephemeral")
 	}
+	args = append(args, "--json")
 
 	beadsDir := r.resolveBeadsDir(msg.To)
-	_, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	out, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
 	if err != nil {
-		return fmt.Errorf("sending message: %w", err)
+		return "", fmt.Errorf("sending message: %w", err)
 	}
 
-	// Notify recipient if they have an active session (best-effort notification)
-	// Skip notification for self-mail (handoffs to future-self don't need present-self notified)
-	if !isSelfMail(msg.From, msg.To) {
-		_ = r.notifyRecipient(msg)
+	// The bead ID is only used to track read state; a missing ID is not fatal.
+	var created struct {
+		ID string `json:"id"`
 	}
-
-	return nil
+	_ = json.Unmarshal(out, &created)
+	return created.ID, nil
 }
 
 // sendToList expands a mailing list and sends individual copies to each recipient.
@@ -865,20 +933,24 @@ func (r *Router) GetMailbox(address string) (*Mailbox, error) {
 // notifyRecipient sends a notification to a recipient's tmux session.
 // Uses send-keys to echo a visible banner to ensure notification is seen.
 // Supports mayor/, rig/polecat, and rig/refinery addresses.
-func (r *Router) notifyRecipient(msg *Message) error {
+// Returns true if the banner was shown in a live session.
+func (r *Router) notifyRecipient(msg *Message) (bool, error) {
 	sessionID := addressToSessionID(msg.To)
 	if sessionID == "" {
-		return nil // Unable to determine session ID
+		return false, nil // Unable to determine session ID
 	}
 
 	// Check if session exists
 	hasSession, err := r.tmux.HasSession(sessionID)
 	if err != nil || !hasSession {
-		return nil // No active session, skip notification
+		return false, nil // No active session, skip notification
 	}
 
 	// Send visible notification banner to the terminal
-	return r.tmux.SendNotificationBanner(sessionID, msg.From, msg.Subject)
+	if err := r.tmux.SendNotificationBanner(sessionID, msg.From, msg.Subject); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // addressToSessionID converts a mail address to a tmux session ID.
@@ -903,6 +975,15 @@ func addressToSessionID(address string) string {
 	rig := parts[0]
 	target := parts[1]
 
+	// Crew: gt-rig-crew-name
+	if name, ok := strings.CutPrefix(target, "crew/"); ok && name != "" {
+		return session.CrewSessionName(rig, name)
+	}
+	// Explicit polecat path: rig/polecats/name
+	if name, ok := strings.CutPrefix(target, "polecats/"); ok && name != "" {
+		return session.PolecatSessionName(rig, name)
+	}
+
 	// Polecat: gt-rig-polecat
 	// Refinery: gt-rig-refinery (if refinery has its own session)
 	return fmt.Sprintf("gt-%s-%s", rig, target)
@@ -97,6 +97,8 @@ func TestAddressToSessionID(t *testing.T) {
 		{"gastown/refinery", "gt-gastown-refinery"},
 		{"gastown/Toast", "gt-gastown-Toast"},
 		{"beads/witness", "gt-beads-witness"},
+		{"gastown/crew/max", "gt-gastown-crew-max"},
+		{"gastown/polecats/Toast", "gt-gastown-Toast"},
 		{"gastown/", ""},   // Empty target
 		{"gastown", ""},    // No slash
 		{"", ""},           // Empty address
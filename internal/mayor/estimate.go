@@ -0,0 +1,372 @@
+package mayor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/task"
+)
+
+// Plan is a list of tasks the Mayor intends to dispatch.
+type Plan struct {
+	Name  string     `json:"name,omitempty"`
+	Tasks []PlanTask `json:"tasks"`
+}
+
+// PlanTask is one unit of work in a plan.
+type PlanTask struct {
+	ID    string `json:"id"`
+	Role  string `json:"role"`            // role type: polecat, crew, witness, ...
+	Rig   string `json:"rig,omitempty"`   // target rig
+	Topic string `json:"topic,omitempty"` // short description matched against history
+
+	// Task is a task spec (see package task) describing the work, relative
+	// to the plan file. Its goal is the topic when none is given, and its
+	// budget is checked against the estimate.
+	Task   string       `json:"task,omitempty"`
+	Budget *task.Budget `json:"budget,omitempty"`
+}
+
+// LoadPlan reads a plan from a YAML or JSON file.
+//
+// YAML plans use a flat subset: an optional top-level "name" and a "tasks"
+// list whose items are maps of scalar fields.
+//
+//	name: auth rewrite
+//	tasks:
+//	  - id: t1
+//	    role: polecat
+//	    rig: gastown
+//	    topic: refactor session auth
+//	  - id: t2
+//	    role: polecat
+//	    task: tasks/session-tests.task.yaml
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: user-specified plan file
+	if err != nil {
+		return nil, fmt.Errorf("reading plan: %w", err)
+	}
+
+	var plan *Plan
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		plan = &Plan{}
+		if err := json.Unmarshal(data, plan); err != nil {
+			return nil, fmt.Errorf("parsing plan: %w", err)
+		}
+	} else {
+		plan, err = parsePlanYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing plan: %w", err)
+		}
+	}
+
+	if len(plan.Tasks) == 0 {
+		return nil, fmt.Errorf("plan %s has no tasks", path)
+	}
+	for i := range plan.Tasks {
+		t := &plan.Tasks[i]
+		if t.ID == "" {
+			t.ID = fmt.Sprintf("task-%d", i+1)
+		}
+		if t.Role == "" {
+			return nil, fmt.Errorf("task %s: role is required", t.ID)
+		}
+		if t.Task != "" {
+			specPath := t.Task
+			if !filepath.IsAbs(specPath) {
+				specPath = filepath.Join(filepath.Dir(path), specPath)
+			}
+			spec, err := task.Load(specPath)
+			if err != nil {
+				return nil, fmt.Errorf("task %s: %w", t.ID, err)
+			}
+			if t.Topic == "" {
+				t.Topic = spec.Headline()
+			}
+			if !spec.Budget.IsZero() {
+				t.Budget = &spec.Budget
+			}
+		}
+	}
+	return plan, nil
+}
+
+// parsePlanYAML parses the flat YAML subset described on LoadPlan.
+func parsePlanYAML(data []byte) (*Plan, error) {
+	plan := &Plan{}
+	var current *PlanTask
+	inTasks := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		indented := raw[0] == ' ' || raw[0] == '\t'
+
+		if !indented {
+			key, value, ok := splitYAMLField(line)
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key: value", lineNum)
+			}
+			switch key {
+			case "name":
+				plan.Name = value
+			case "tasks":
+				inTasks = true
+			default:
+				return nil, fmt.Errorf("line %d: unknown field %q", lineNum, key)
+			}
+			continue
+		}
+
+		if !inTasks {
+			return nil, fmt.Errorf("line %d: unexpected indentation", lineNum)
+		}
+		if rest, ok := strings.CutPrefix(line, "-"); ok {
+			plan.Tasks = append(plan.Tasks, PlanTask{})
+			current = &plan.Tasks[len(plan.Tasks)-1]
+			line = strings.TrimSpace(rest)
+			if line == "" {
+				continue
+			}
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: task fields must follow '-'", lineNum)
+		}
+		key, value, ok := splitYAMLField(line)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value", lineNum)
+		}
+		switch key {
+		case "id":
+			current.ID = value
+		case "role":
+			current.Role = value
+		case "rig":
+			current.Rig = value
+		case "topic":
+			current.Topic = value
+		case "task":
+			current.Task = value
+		default:
+			return nil, fmt.Errorf("line %d: unknown task field %q", lineNum, key)
+		}
+	}
+	return plan, scanner.Err()
+}
+
+// splitYAMLField splits "key: value", unquoting the value.
+func splitYAMLField(line string) (key, value string, ok bool) {
+	key, value, ok = strings.Cut(line, ":")
+	if !ok {
+		return "", "", false
+	}
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	} else if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return strings.TrimSpace(key), value, true
+}
+
+// HistorySample is a past session used as evidence for an estimate.
+type HistorySample struct {
+	Role     string // role type (polecat, crew, ...)
+	Rig      string
+	Topic    string
+	Tokens   int64
+	Cost     float64
+	Duration time.Duration
+}
+
+// Range summarizes a metric across matching samples.
+type Range struct {
+	Low    float64 `json:"low"`    // 25th percentile
+	Median float64 `json:"median"` // 50th percentile
+	High   float64 `json:"high"`   // 75th percentile
+}
+
+// Basis values describe which samples an estimate was drawn from.
+const (
+	BasisTopic = "role+topic" // same role with a similar topic
+	BasisRole  = "role"       // same role, topic ignored
+	BasisNone  = "none"       // no history for this role
+)
+
+// minTopicSamples is how many topic matches are needed before the
+// estimator stops falling back to role-wide history.
+const minTopicSamples = 3
+
+// topicSimilarity is the minimum word overlap for a topic match.
+const topicSimilarity = 0.25
+
+// TaskEstimate is the predicted cost of one plan task.
+type TaskEstimate struct {
+	Task    PlanTask `json:"task"`
+	Basis   string   `json:"basis"`
+	Samples int      `json:"samples"`
+	Tokens  Range    `json:"tokens"`
+	CostUSD Range    `json:"cost_usd"`
+	Minutes Range    `json:"minutes"`
+
+	// OverBudget lists the caps of the task's budget that its median
+	// estimate exceeds: "cost", "duration", or "tokens".
+	OverBudget []string `json:"over_budget,omitempty"`
+}
+
+// PlanEstimate is the predicted cost of a whole plan. Totals sum the
+// per-task ranges, so they assume tasks run sequentially.
+type PlanEstimate struct {
+	Plan    string         `json:"plan,omitempty"`
+	Tasks   []TaskEstimate `json:"tasks"`
+	Tokens  Range          `json:"tokens"`
+	CostUSD Range          `json:"cost_usd"`
+	Minutes Range          `json:"minutes"`
+}
+
+// EstimatePlan predicts token, cost, and time ranges for each task from
+// historical sessions with the same role and a similar topic.
+func EstimatePlan(plan *Plan, history []HistorySample) *PlanEstimate {
+	est := &PlanEstimate{Plan: plan.Name}
+	for _, task := range plan.Tasks {
+		te := estimateTask(task, history)
+		est.Tasks = append(est.Tasks, te)
+		est.Tokens = addRange(est.Tokens, te.Tokens)
+		est.CostUSD = addRange(est.CostUSD, te.CostUSD)
+		est.Minutes = addRange(est.Minutes, te.Minutes)
+	}
+	return est
+}
+
+func estimateTask(task PlanTask, history []HistorySample) TaskEstimate {
+	role := strings.ToLower(task.Role)
+	var byRole, byTopic []HistorySample
+	for _, h := range history {
+		if strings.ToLower(h.Role) != role {
+			continue
+		}
+		if task.Rig != "" && h.Rig != "" && h.Rig != task.Rig {
+			continue
+		}
+		byRole = append(byRole, h)
+		if task.Topic != "" && wordOverlap(task.Topic, h.Topic) >= topicSimilarity {
+			byTopic = append(byTopic, h)
+		}
+	}
+
+	te := TaskEstimate{Task: task}
+	samples := byTopic
+	switch {
+	case len(byTopic) >= minTopicSamples:
+		te.Basis = BasisTopic
+	case len(byRole) > 0:
+		te.Basis = BasisRole
+		samples = byRole
+	default:
+		te.Basis = BasisNone
+		return te
+	}
+
+	te.Samples = len(samples)
+	tokens := make([]float64, len(samples))
+	costs := make([]float64, len(samples))
+	minutes := make([]float64, len(samples))
+	for i, s := range samples {
+		tokens[i] = float64(s.Tokens)
+		costs[i] = s.Cost
+		minutes[i] = s.Duration.Minutes()
+	}
+	te.Tokens = quartiles(tokens)
+	te.CostUSD = quartiles(costs)
+	te.Minutes = quartiles(minutes)
+	te.OverBudget = overBudget(task.Budget, te)
+	return te
+}
+
+// overBudget returns the caps of budget that te's median exceeds.
+func overBudget(budget *task.Budget, te TaskEstimate) []string {
+	if budget == nil {
+		return nil
+	}
+	var over []string
+	if budget.Cost > 0 && te.CostUSD.Median > budget.Cost {
+		over = append(over, "cost")
+	}
+	if budget.Duration > 0 && te.Minutes.Median > budget.Duration.Minutes() {
+		over = append(over, "duration")
+	}
+	if budget.Tokens > 0 && te.Tokens.Median > float64(budget.Tokens) {
+		over = append(over, "tokens")
+	}
+	return over
+}
+
+// quartiles returns the 25th, 50th, and 75th percentiles of values.
+func quartiles(values []float64) Range {
+	if len(values) == 0 {
+		return Range{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return Range{
+		Low:    percentile(sorted, 0.25),
+		Median: percentile(sorted, 0.50),
+		High:   percentile(sorted, 0.75),
+	}
+}
+
+// percentile linearly interpolates the p-th percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	pos := p * float64(len(sorted)-1)
+	lo := int(pos)
+	if lo >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(lo)
+	return sorted[lo] + frac*(sorted[lo+1]-sorted[lo])
+}
+
+func addRange(a, b Range) Range {
+	return Range{Low: a.Low + b.Low, Median: a.Median + b.Median, High: a.High + b.High}
+}
+
+// wordOverlap returns the Jaccard similarity of the words in a and b.
+func wordOverlap(a, b string) float64 {
+	wa, wb := topicWords(a), topicWords(b)
+	if len(wa) == 0 || len(wb) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range wa {
+		if wb[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wa)+len(wb)-shared)
+}
+
+func topicWords(s string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}) {
+		if len(w) > 2 {
+			words[w] = true
+		}
+	}
+	return words
+}
@@ -0,0 +1,119 @@
+package mayor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadPlanYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.yaml")
+	content := `# sprint plan
+name: auth rewrite
+tasks:
+  - id: t1
+    role: polecat
+    rig: gastown
+    topic: "refactor session auth"
+  - role: crew # unnamed task
+    topic: review docs
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := LoadPlan(path)
+	if err != nil {
+		t.Fatalf("LoadPlan: %v", err)
+	}
+	if plan.Name != "auth rewrite" || len(plan.Tasks) != 2 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if got := plan.Tasks[0]; got.ID != "t1" || got.Rig != "gastown" || got.Topic != "refactor session auth" {
+		t.Errorf("task 0 = %+v", got)
+	}
+	if got := plan.Tasks[1]; got.ID != "task-2" || got.Role != "crew" {
+		t.Errorf("task 1 = %+v", got)
+	}
+}
+
+func TestLoadPlanRequiresRole(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.yaml")
+	if err := os.WriteFile(path, []byte("tasks:\n  - id: t1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPlan(path); err == nil {
+		t.Error("expected error for task without role")
+	}
+}
+
+func TestLoadPlanTaskSpec(t *testing.T) {
+	dir := t.TempDir()
+	spec := "goal: Fix the auth bug\nacceptance:\n  - tests pass\nbudget:\n  cost: 1.50\n  duration: 15m\n"
+	if err := os.MkdirAll(filepath.Join(dir, "tasks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tasks", "auth.task.yaml"), []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "plan.yaml")
+	if err := os.WriteFile(path, []byte("tasks:\n  - id: t1\n    role: polecat\n    task: tasks/auth.task.yaml\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := LoadPlan(path)
+	if err != nil {
+		t.Fatalf("LoadPlan: %v", err)
+	}
+	got := plan.Tasks[0]
+	if got.Topic != "Fix the auth bug" || got.Budget == nil || got.Budget.Cost != 1.5 {
+		t.Fatalf("task = %+v", got)
+	}
+
+	est := EstimatePlan(plan, []HistorySample{
+		{Role: "polecat", Topic: "fix auth bug", Tokens: 100, Cost: 2, Duration: 10 * time.Minute},
+	})
+	if over := est.Tasks[0].OverBudget; len(over) != 1 || over[0] != "cost" {
+		t.Errorf("OverBudget = %v, want [cost]", over)
+	}
+
+	if err := os.WriteFile(path, []byte("tasks:\n  - role: polecat\n    task: missing.task.yaml\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPlan(path); err == nil {
+		t.Error("LoadPlan accepted a missing task spec")
+	}
+}
+
+func TestEstimatePlan(t *testing.T) {
+	history := []HistorySample{
+		{Role: "polecat", Topic: "fix auth bug", Tokens: 100, Cost: 1, Duration: 10 * time.Minute},
+		{Role: "polecat", Topic: "auth token refresh", Tokens: 200, Cost: 2, Duration: 20 * time.Minute},
+		{Role: "polecat", Topic: "auth session bug", Tokens: 300, Cost: 3, Duration: 30 * time.Minute},
+		{Role: "polecat", Topic: "update readme", Tokens: 5000, Cost: 50, Duration: 5 * time.Minute},
+		{Role: "crew", Topic: "fix auth bug", Tokens: 9999, Cost: 99},
+	}
+	plan := &Plan{Tasks: []PlanTask{
+		{ID: "a", Role: "polecat", Topic: "auth bug"},
+		{ID: "b", Role: "polecat", Topic: "something unrelated"},
+		{ID: "c", Role: "witness"},
+	}}
+
+	est := EstimatePlan(plan, history)
+
+	a := est.Tasks[0]
+	if a.Basis != BasisTopic || a.Samples != 3 || a.Tokens.Median != 200 {
+		t.Errorf("task a = %+v", a)
+	}
+	b := est.Tasks[1]
+	if b.Basis != BasisRole || b.Samples != 4 {
+		t.Errorf("task b = %+v", b)
+	}
+	if c := est.Tasks[2]; c.Basis != BasisNone || c.Samples != 0 {
+		t.Errorf("task c = %+v", c)
+	}
+	if est.Tokens.Median != a.Tokens.Median+b.Tokens.Median {
+		t.Errorf("total median = %v", est.Tokens.Median)
+	}
+}
@@ -0,0 +1,79 @@
+package nightshift
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Sling returns a Launcher that runs 'gt sling' from the town root.
+func Sling(townRoot string) Launcher {
+	return func(t Task) error {
+		args := []string{"sling", t.Bead}
+		if t.Target != "" {
+			args = append(args, t.Target)
+		}
+		if t.Args != "" {
+			args = append(args, "--args", t.Args)
+		}
+		cmd := exec.Command("gt", args...) //nolint:gosec // G204: args come from the nightshift queue
+		cmd.Dir = townRoot
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+}
+
+// BeadStatus returns a StatusChecker that looks beads up with bd.
+func BeadStatus(townRoot string) StatusChecker {
+	b := beads.New(townRoot)
+	return func(bead string) (string, error) {
+		issue, err := b.Show(bead)
+		if err != nil {
+			return "", err
+		}
+		return issue.Status, nil
+	}
+}
+
+// SessionSpend returns a SpendMeter that prices the usage Gas Town
+// sessions recorded at or after since.
+func SessionSpend(prices config.PriceTable) SpendMeter {
+	return func(since time.Time) float64 {
+		sessions, err := claude.DiscoverSessions(claude.SessionFilter{GasTownOnly: true})
+		if err != nil {
+			return 0
+		}
+		total := 0.0
+		for _, s := range sessions {
+			if stat, err := os.Stat(s.Path); err != nil || stat.ModTime().Before(since) {
+				continue
+			}
+			usage, err := claude.ReadUsageSince(s.Path, since)
+			if err != nil {
+				continue
+			}
+			total += claude.EstimateCost(*usage, prices)
+		}
+		return total
+	}
+}
+
+// Mail returns a Notifier that sends the report with 'gt mail send'.
+func Mail(townRoot, address string) Notifier {
+	return func(subject, body string) error {
+		cmd := exec.Command("gt", "mail", "send", address, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = townRoot
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,168 @@
+// Package nightshift queues low-priority work during the day and launches
+// it off-hours. The daemon slings queued tasks during the configured
+// window, within concurrency and budget limits, and mails a report of the
+// night's results once the window closes.
+//
+// The queue is an append-only log at <town>/.runtime/nightshift.jsonl;
+// each line is a full snapshot of one task, and the latest wins.
+package nightshift
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filename is the queue log within the town .runtime directory.
+const Filename = "nightshift.jsonl"
+
+// DefaultPriority is the priority of tasks queued without one (beads
+// priorities: 0 is highest, 4 lowest).
+const DefaultPriority = 3
+
+// Status is a task's place in its lifecycle.
+type Status string
+
+// Task statuses.
+const (
+	StatusQueued   Status = "queued"
+	StatusLaunched Status = "launched" // slung; its bead is not yet closed
+	StatusDone     Status = "done"     // its bead was closed
+	StatusFailed   Status = "failed"   // the sling failed
+	StatusCanceled Status = "canceled"
+)
+
+// Task is one queued unit of work: a bead to sling to a target.
+type Task struct {
+	ID       string `json:"id"`
+	Bead     string `json:"bead"`
+	Target   string `json:"target,omitempty"` // sling target, e.g. a rig
+	Args     string `json:"args,omitempty"`   // natural-language instructions
+	Priority int    `json:"priority"`
+	Status   Status `json:"status"`
+	QueuedBy string `json:"queued_by,omitempty"`
+	Error    string `json:"error,omitempty"`
+
+	QueuedAt   time.Time `json:"queued_at"`
+	LaunchedAt time.Time `json:"launched_at,omitempty"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+
+	// Reported is set once the task's result went out in a morning report.
+	Reported bool `json:"reported,omitempty"`
+}
+
+// Finished reports whether the task has reached a final status.
+func (t Task) Finished() bool {
+	return t.Status == StatusDone || t.Status == StatusFailed || t.Status == StatusCanceled
+}
+
+// Path returns the queue log path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", Filename)
+}
+
+// Queue adds a task to the queue, filling in its ID, status, and queue
+// time.
+func Queue(townRoot string, t Task) (Task, error) {
+	t.ID = generateID()
+	t.Status = StatusQueued
+	if t.QueuedAt.IsZero() {
+		t.QueuedAt = time.Now().UTC()
+	}
+	return t, Save(townRoot, t)
+}
+
+// Save appends a snapshot of t to the queue log.
+func Save(townRoot string, t Task) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G302: queue is not secret
+	if err != nil {
+		return fmt.Errorf("opening nightshift queue: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Load returns the latest snapshot of every task, in queue order. A
+// missing log yields no tasks.
+func Load(townRoot string) ([]Task, error) {
+	f, err := os.Open(Path(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening nightshift queue: %w", err)
+	}
+	defer f.Close()
+
+	latest := make(map[string]Task)
+	var order []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var t Task
+		if err := json.Unmarshal(scanner.Bytes(), &t); err != nil || t.ID == "" {
+			continue
+		}
+		if _, seen := latest[t.ID]; !seen {
+			order = append(order, t.ID)
+		}
+		latest[t.ID] = t
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]Task, 0, len(order))
+	for _, id := range order {
+		tasks = append(tasks, latest[id])
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].QueuedAt.Before(tasks[j].QueuedAt)
+	})
+	return tasks, nil
+}
+
+// Find returns the task whose ID is id or uniquely starts with it.
+func Find(tasks []Task, id string) (Task, error) {
+	var match []Task
+	for _, t := range tasks {
+		if t.ID == id {
+			return t, nil
+		}
+		if strings.HasPrefix(t.ID, id) {
+			match = append(match, t)
+		}
+	}
+	switch len(match) {
+	case 0:
+		return Task{}, fmt.Errorf("no nightshift task %q", id)
+	case 1:
+		return match[0], nil
+	default:
+		return Task{}, fmt.Errorf("nightshift task %q is ambiguous (%d matches)", id, len(match))
+	}
+}
+
+// generateID creates a short task ID.
+func generateID() string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return "ns-" + hex.EncodeToString(b)
+}
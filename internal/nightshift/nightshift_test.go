@@ -0,0 +1,122 @@
+package nightshift
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestQueueLoadFind(t *testing.T) {
+	town := t.TempDir()
+	a, err := Queue(town, Task{Bead: "gt-a", Target: "gastown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Queue(town, Task{Bead: "gt-b", QueuedAt: a.QueuedAt.Add(time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Status = StatusCanceled
+	if err := Save(town, b); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != a.ID || tasks[1].Status != StatusCanceled {
+		t.Fatalf("tasks = %+v", tasks)
+	}
+	if got, err := Find(tasks, a.ID); err != nil || got.Bead != "gt-a" {
+		t.Errorf("Find(%s) = %+v, %v", a.ID, got, err)
+	}
+	if _, err := Find(tasks, "ns-"); err == nil {
+		t.Error("Find accepted an ambiguous prefix")
+	}
+}
+
+func TestRun(t *testing.T) {
+	town := t.TempDir()
+	base := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	for i, bead := range []string{"gt-low", "gt-high", "gt-mid", "gt-bad"} {
+		prio := []int{4, 0, 2, 1}[i]
+		if _, err := Queue(town, Task{Bead: bead, Priority: prio, QueuedAt: base.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	closed := map[string]bool{}
+	var launched []string
+	var reports []string
+	opts := RunOptions{
+		Config: config.NightshiftConfig{Window: "22:00-06:00", MaxConcurrent: 2, BudgetUSD: 10},
+		Launch: func(t Task) error {
+			if t.Bead == "gt-bad" {
+				return errors.New("no such bead")
+			}
+			launched = append(launched, t.Bead)
+			return nil
+		},
+		Status: func(bead string) (string, error) {
+			if closed[bead] {
+				return "closed", nil
+			}
+			return "in_progress", nil
+		},
+		Spent:  func(time.Time) float64 { return 0 },
+		Notify: func(subject, body string) error { reports = append(reports, subject+"\n"+body); return nil },
+	}
+
+	// Daytime: nothing launches, nothing to report
+	opts.Now = base.Add(time.Hour)
+	res, err := Run(town, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(launched) != 0 || res.Queued != 4 || !strings.Contains(res.Held, "outside window") {
+		t.Fatalf("daytime pass launched %v, result %+v", launched, res)
+	}
+
+	// Night: highest priority first; the failed launch doesn't use a slot
+	opts.Now = base.Add(11 * time.Hour)
+	res, err = Run(town, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(launched, ",") != "gt-high,gt-mid" || len(res.Failed) != 1 || res.Queued != 1 || res.Running != 2 {
+		t.Fatalf("night pass launched %v, result %+v", launched, res)
+	}
+
+	// Budget reached: gt-high finishes but gt-low is held
+	closed["gt-high"] = true
+	opts.Spent = func(time.Time) float64 { return 12 }
+	opts.Now = base.Add(12 * time.Hour)
+	res, err = Run(town, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Finished) != 1 || len(launched) != 2 || !strings.Contains(res.Held, "budget") {
+		t.Fatalf("budget pass launched %v, result %+v", launched, res)
+	}
+
+	// Morning: report finished tasks once
+	opts.Now = base.Add(20 * time.Hour)
+	if _, err := Run(town, opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(town, opts); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("sent %d reports, want 1", len(reports))
+	}
+	for _, want := range []string{"1 done, 1 failed", "done gt-high", "failed gt-bad: no such bead", "Still running: 1", "Still queued: 1"} {
+		if !strings.Contains(reports[0], want) {
+			t.Errorf("report missing %q:\n%s", want, reports[0])
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package nightshift
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Launcher slings a task's bead to its target.
+type Launcher func(t Task) error
+
+// StatusChecker returns a bead's status (open, in_progress, closed, ...).
+type StatusChecker func(bead string) (string, error)
+
+// SpendMeter returns the estimated USD cost of Gas Town sessions started
+// at or after since.
+type SpendMeter func(since time.Time) float64
+
+// Notifier delivers the morning report.
+type Notifier func(subject, body string) error
+
+// RunOptions controls a nightshift pass.
+type RunOptions struct {
+	// Now is the time of the pass (default time.Now()).
+	Now time.Time
+
+	// Config holds the window and limits; see TownSettings.NightshiftSettings.
+	Config config.NightshiftConfig
+
+	// Force launches tasks even outside the window. Limits still apply.
+	Force bool
+
+	Launch Launcher
+	Status StatusChecker
+	Spent  SpendMeter // may be nil when the config has no budget
+	Notify Notifier   // may be nil to skip the report
+}
+
+// RunResult summarizes a nightshift pass.
+type RunResult struct {
+	Launched []Task  `json:"launched,omitempty"`
+	Finished []Task  `json:"finished,omitempty"` // tasks whose bead closed since the last pass
+	Failed   []Task  `json:"failed,omitempty"`   // launches that failed this pass
+	Reported []Task  `json:"reported,omitempty"` // tasks included in a morning report
+	Running  int     `json:"running"`
+	Queued   int     `json:"queued"`
+	Spent    float64 `json:"spent_usd,omitempty"`
+
+	// Held says why queued tasks were not launched, if they weren't.
+	Held string `json:"held,omitempty"`
+}
+
+// Run makes one nightshift pass: it marks launched tasks whose bead has
+// closed as done, launches queued tasks by priority while inside the
+// window and under the limits, and once the window has closed sends a
+// report of the tasks that finished overnight.
+func Run(townRoot string, opts RunOptions) (*RunResult, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	window, err := config.ParseWindow(opts.Config.Window)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	result := &RunResult{}
+
+	// Retire launched tasks whose work is done
+	var queued []int
+	for i := range tasks {
+		t := &tasks[i]
+		switch t.Status {
+		case StatusQueued:
+			queued = append(queued, i)
+		case StatusLaunched:
+			status, err := opts.Status(t.Bead)
+			if err != nil || status != "closed" {
+				result.Running++
+				continue
+			}
+			t.Status = StatusDone
+			t.FinishedAt = opts.Now.UTC()
+			if err := Save(townRoot, *t); err != nil {
+				return nil, err
+			}
+			result.Finished = append(result.Finished, *t)
+		}
+	}
+	result.Queued = len(queued)
+
+	opened, inWindow := window.Opened(opts.Now)
+	if !inWindow && !opts.Force {
+		result.Held = "outside window " + opts.Config.Window
+		if opts.Notify != nil {
+			if err := report(townRoot, tasks, result, opts.Notify); err != nil {
+				return result, err
+			}
+		}
+		return result, nil
+	}
+	if !inWindow {
+		opened = opts.Now.Add(-24 * time.Hour)
+	}
+
+	sort.SliceStable(queued, func(a, b int) bool {
+		return tasks[queued[a]].Priority < tasks[queued[b]].Priority
+	})
+	for _, i := range queued {
+		if result.Running >= opts.Config.MaxConcurrent {
+			result.Held = fmt.Sprintf("%d task(s) running (max_concurrent %d)", result.Running, opts.Config.MaxConcurrent)
+			break
+		}
+		if opts.Config.BudgetUSD > 0 && opts.Spent != nil {
+			result.Spent = opts.Spent(opened)
+			if result.Spent >= opts.Config.BudgetUSD {
+				result.Held = fmt.Sprintf("budget reached ($%.2f of $%.2f)", result.Spent, opts.Config.BudgetUSD)
+				break
+			}
+		}
+
+		t := &tasks[i]
+		t.LaunchedAt = opts.Now.UTC()
+		if err := opts.Launch(*t); err != nil {
+			t.Status = StatusFailed
+			t.Error = err.Error()
+			t.FinishedAt = t.LaunchedAt
+			result.Failed = append(result.Failed, *t)
+		} else {
+			t.Status = StatusLaunched
+			result.Running++
+			result.Launched = append(result.Launched, *t)
+		}
+		result.Queued--
+		if err := Save(townRoot, *t); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// report mails a summary of the tasks that finished since the last report,
+// and marks them reported. Nothing is sent if none did.
+func report(townRoot string, tasks []Task, result *RunResult, notify Notifier) error {
+	var done []Task
+	var running []Task
+	for _, t := range tasks {
+		switch {
+		case t.Status == StatusLaunched:
+			running = append(running, t)
+		case t.Finished() && t.Status != StatusCanceled && !t.Reported:
+			done = append(done, t)
+		}
+	}
+	if len(done) == 0 {
+		return nil
+	}
+
+	subject, body := Report(done, running, result.Queued)
+	if err := notify(subject, body); err != nil {
+		return fmt.Errorf("sending nightshift report: %w", err)
+	}
+	for _, t := range done {
+		t.Reported = true
+		if err := Save(townRoot, t); err != nil {
+			return err
+		}
+		result.Reported = append(result.Reported, t)
+	}
+	return nil
+}
+
+// Report formats the morning report for finished tasks, noting any still
+// running and how many remain queued.
+func Report(finished, running []Task, queued int) (subject, body string) {
+	var ok, failed int
+	for _, t := range finished {
+		if t.Status == StatusDone {
+			ok++
+		} else {
+			failed++
+		}
+	}
+	subject = fmt.Sprintf("Nightshift: %d done, %d failed", ok, failed)
+
+	var b strings.Builder
+	for _, t := range finished {
+		line := fmt.Sprintf("- %s %s", t.Status, t.label())
+		if t.Status == StatusDone && !t.LaunchedAt.IsZero() {
+			line += fmt.Sprintf(" (%s)", t.FinishedAt.Sub(t.LaunchedAt).Round(time.Minute))
+		}
+		if t.Error != "" {
+			line += ": " + t.Error
+		}
+		b.WriteString(line + "\n")
+	}
+	if len(running) > 0 {
+		fmt.Fprintf(&b, "\nStill running: %d\n", len(running))
+		for _, t := range running {
+			fmt.Fprintf(&b, "- %s\n", t.label())
+		}
+	}
+	if queued > 0 {
+		fmt.Fprintf(&b, "\nStill queued: %d (see gt nightshift list)\n", queued)
+	}
+	return subject, b.String()
+}
+
+// label names a task's bead and target, e.g. "gt-abc → gastown".
+func (t Task) label() string {
+	if t.Target == "" {
+		return t.Bead
+	}
+	return t.Bead + " → " + t.Target
+}
@@ -0,0 +1,196 @@
+// Package outcome records whether agent sessions achieved their task.
+// Outcomes are appended to <town>/.runtime/outcomes.jsonl; the most recent
+// record for a session wins, so an outcome can be corrected by setting it
+// again.
+package outcome
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Filename is the outcomes log within the town .runtime directory.
+const Filename = "outcomes.jsonl"
+
+// Status is the result of a session's task.
+type Status string
+
+// Outcome statuses.
+const (
+	Success Status = "success"
+	Failure Status = "failure"
+	Partial Status = "partial"
+)
+
+// ParseStatus validates a status string.
+func ParseStatus(s string) (Status, error) {
+	switch Status(s) {
+	case Success, Failure, Partial:
+		return Status(s), nil
+	}
+	return "", fmt.Errorf("invalid outcome %q (want success, failure, or partial)", s)
+}
+
+// Record is a single outcome entry.
+type Record struct {
+	SessionID     string    `json:"session_id"`
+	Status        Status    `json:"status"`
+	Reason        string    `json:"reason,omitempty"`
+	Role          string    `json:"role,omitempty"`           // role type (polecat, crew, ...)
+	Rig           string    `json:"rig,omitempty"`            // rig the session worked in
+	PromptVersion string    `json:"prompt_version,omitempty"` // role template hash
+	SetBy         string    `json:"set_by,omitempty"`         // who recorded it (human or hook)
+	Checks        []Check   `json:"checks,omitempty"`         // acceptance checks behind the status
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// Check is the result of one acceptance check run by gt check run.
+type Check struct {
+	Check  string `json:"check"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"` // failure output or reason
+}
+
+// Path returns the outcomes log path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", Filename)
+}
+
+// Append adds a record to the town's outcomes log.
+func Append(townRoot string, rec Record) error {
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now().UTC()
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G302: outcomes are not secret
+	if err != nil {
+		return fmt.Errorf("opening outcomes log: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Load returns the latest record per session, newest first.
+// A missing log yields no records.
+func Load(townRoot string) ([]Record, error) {
+	f, err := os.Open(Path(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening outcomes log: %w", err)
+	}
+	defer f.Close()
+
+	latest := make(map[string]Record)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.SessionID == "" {
+			continue
+		}
+		if prev, ok := latest[rec.SessionID]; !ok || !rec.Timestamp.Before(prev.Timestamp) {
+			latest[rec.SessionID] = rec
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(latest))
+	for _, rec := range latest {
+		records = append(records, rec)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Timestamp.After(records[j].Timestamp)
+	})
+	return records, nil
+}
+
+// Get returns the latest record for a session, or nil if none.
+func Get(townRoot, sessionID string) (*Record, error) {
+	records, err := Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if records[i].SessionID == sessionID {
+			return &records[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Stats aggregates outcomes for one group.
+type Stats struct {
+	Role          string  `json:"role"`
+	PromptVersion string  `json:"prompt_version,omitempty"`
+	Total         int     `json:"total"`
+	Success       int     `json:"success"`
+	Partial       int     `json:"partial"`
+	Failure       int     `json:"failure"`
+	SuccessRate   float64 `json:"success_rate"` // success / total
+
+	// Blended counts sessions a human and an agent both worked on (see
+	// gt takeover). They are not included in Total or the rates.
+	Blended int `json:"blended,omitempty"`
+}
+
+// Summarize groups records by role and prompt version. Sessions in
+// blended are counted apart, so the rates only reflect agent-only work.
+// Results are sorted by role, then prompt version.
+func Summarize(records []Record, blended map[string]bool) []Stats {
+	type key struct{ role, version string }
+	groups := make(map[key]*Stats)
+	for _, rec := range records {
+		k := key{rec.Role, rec.PromptVersion}
+		s, ok := groups[k]
+		if !ok {
+			s = &Stats{Role: rec.Role, PromptVersion: rec.PromptVersion}
+			groups[k] = s
+		}
+		if blended[rec.SessionID] {
+			s.Blended++
+			continue
+		}
+		s.Total++
+		switch rec.Status {
+		case Success:
+			s.Success++
+		case Partial:
+			s.Partial++
+		case Failure:
+			s.Failure++
+		}
+	}
+
+	stats := make([]Stats, 0, len(groups))
+	for _, s := range groups {
+		if s.Total > 0 {
+			s.SuccessRate = float64(s.Success) / float64(s.Total)
+		}
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Role != stats[j].Role {
+			return stats[i].Role < stats[j].Role
+		}
+		return stats[i].PromptVersion < stats[j].PromptVersion
+	})
+	return stats
+}
@@ -0,0 +1,73 @@
+package outcome
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppendLoadLatestWins(t *testing.T) {
+	town := t.TempDir()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	recs := []Record{
+		{SessionID: "s1", Status: Failure, Role: "polecat", Timestamp: base},
+		{SessionID: "s2", Status: Success, Role: "polecat", Timestamp: base.Add(time.Minute)},
+		{SessionID: "s1", Status: Success, Role: "polecat", Reason: "retried", Timestamp: base.Add(2 * time.Minute)},
+	}
+	for _, r := range recs {
+		if err := Append(town, r); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	got, err := Load(town)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d records, want 2", len(got))
+	}
+	if got[0].SessionID != "s1" || got[0].Status != Success || got[0].Reason != "retried" {
+		t.Errorf("latest s1 = %+v", got[0])
+	}
+
+	rec, err := Get(town, "s2")
+	if err != nil || rec == nil || rec.Status != Success {
+		t.Errorf("Get(s2) = %+v, %v", rec, err)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	got, err := Load(t.TempDir())
+	if err != nil || got != nil {
+		t.Errorf("Load on empty town = %v, %v", got, err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	stats := Summarize([]Record{
+		{SessionID: "a", Status: Success, Role: "polecat", PromptVersion: "v1"},
+		{SessionID: "b", Status: Failure, Role: "polecat", PromptVersion: "v1"},
+		{SessionID: "c", Status: Partial, Role: "polecat", PromptVersion: "v2"},
+		{SessionID: "d", Status: Success, Role: "crew"},
+		{SessionID: "e", Status: Success, Role: "polecat", PromptVersion: "v1"},
+	}, map[string]bool{"e": true})
+	if len(stats) != 3 {
+		t.Fatalf("got %d groups, want 3", len(stats))
+	}
+	if stats[0].Role != "crew" || stats[0].SuccessRate != 1 {
+		t.Errorf("crew stats = %+v", stats[0])
+	}
+	if s := stats[1]; s.PromptVersion != "v1" || s.Total != 2 || s.SuccessRate != 0.5 || s.Blended != 1 {
+		t.Errorf("polecat v1 stats = %+v", s)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	if _, err := ParseStatus("success"); err != nil {
+		t.Error(err)
+	}
+	if _, err := ParseStatus("great"); err == nil {
+		t.Error("expected error for invalid status")
+	}
+}
@@ -0,0 +1,143 @@
+// Package pause lets a human freeze agents without killing their sessions.
+//
+// Pauses are stored in <town>/.runtime/pauses.json. A PreToolUse hook
+// ('gt pause-gate') checks the file before every tool call and blocks while
+// a pause matches the calling agent, so paused agents stop at their next
+// tool call and continue where they left off once resumed.
+package pause
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Filename is the pause state file within the town .runtime directory.
+const Filename = "pauses.json"
+
+// TargetAll pauses every agent in the town.
+const TargetAll = "all"
+
+// Pause is a single active pause.
+type Pause struct {
+	// Target selects the agents to pause: "all", a role type ("polecat"),
+	// a rig ("gastown"), an address ("gastown/crew/joe"), or a session ID
+	// prefix.
+	Target string    `json:"target"`
+	Reason string    `json:"reason,omitempty"`
+	By     string    `json:"by,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+// Agent identifies the caller being checked against pauses.
+type Agent struct {
+	Address   string // e.g., "gastown/polecats/toast" or "mayor"
+	RoleType  string // e.g., "polecat"
+	Rig       string // e.g., "gastown"
+	SessionID string // Claude session UUID
+}
+
+// Path returns the pause state path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", Filename)
+}
+
+// Load returns the active pauses. A missing file means nothing is paused.
+func Load(townRoot string) ([]Pause, error) {
+	data, err := os.ReadFile(Path(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading pauses: %w", err)
+	}
+	var pauses []Pause
+	if err := json.Unmarshal(data, &pauses); err != nil {
+		return nil, fmt.Errorf("parsing pauses: %w", err)
+	}
+	return pauses, nil
+}
+
+func save(townRoot string, pauses []Pause) error {
+	path := Path(townRoot)
+	if len(pauses) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	return util.AtomicWriteJSON(path, pauses)
+}
+
+// Add records a pause, replacing any existing pause for the same target.
+func Add(townRoot string, p Pause) error {
+	if p.Target == "" {
+		return fmt.Errorf("pause target is required")
+	}
+	if p.Since.IsZero() {
+		p.Since = time.Now().UTC()
+	}
+	pauses, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	kept := pauses[:0]
+	for _, existing := range pauses {
+		if existing.Target != p.Target {
+			kept = append(kept, existing)
+		}
+	}
+	return save(townRoot, append(kept, p))
+}
+
+// Remove lifts the pause for target. Reports whether a pause was removed.
+func Remove(townRoot, target string) (bool, error) {
+	pauses, err := Load(townRoot)
+	if err != nil {
+		return false, err
+	}
+	kept := pauses[:0]
+	for _, p := range pauses {
+		if p.Target != target {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) == len(pauses) {
+		return false, nil
+	}
+	return true, save(townRoot, kept)
+}
+
+// Match returns the first pause that applies to agent, or nil.
+func Match(pauses []Pause, agent Agent) *Pause {
+	for i := range pauses {
+		if pauses[i].matches(agent) {
+			return &pauses[i]
+		}
+	}
+	return nil
+}
+
+func (p Pause) matches(a Agent) bool {
+	switch {
+	case p.Target == TargetAll:
+		return true
+	case a.Address != "" && p.Target == a.Address:
+		return true
+	case a.RoleType != "" && p.Target == a.RoleType:
+		return true
+	case a.Rig != "" && p.Target == a.Rig:
+		return true
+	case a.SessionID != "" && len(p.Target) >= 6 && strings.HasPrefix(a.SessionID, p.Target):
+		return true
+	}
+	return false
+}
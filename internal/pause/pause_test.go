@@ -0,0 +1,65 @@
+package pause
+
+import (
+	"os"
+	"testing"
+)
+
+func TestAddRemove(t *testing.T) {
+	town := t.TempDir()
+
+	if err := Add(town, Pause{Target: "polecat", Reason: "incident"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := Add(town, Pause{Target: "polecat", Reason: "updated"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	pauses, err := Load(town)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(pauses) != 1 || pauses[0].Reason != "updated" || pauses[0].Since.IsZero() {
+		t.Fatalf("pauses = %+v", pauses)
+	}
+
+	removed, err := Remove(town, "polecat")
+	if err != nil || !removed {
+		t.Fatalf("Remove: removed=%v err=%v", removed, err)
+	}
+	if _, err := os.Stat(Path(town)); !os.IsNotExist(err) {
+		t.Error("expected pause file to be removed when empty")
+	}
+	removed, err = Remove(town, "polecat")
+	if err != nil || removed {
+		t.Errorf("second Remove: removed=%v err=%v", removed, err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	agent := Agent{
+		Address:   "gastown/polecats/toast",
+		RoleType:  "polecat",
+		Rig:       "gastown",
+		SessionID: "0123456789abcdef",
+	}
+
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{"all", true},
+		{"gastown/polecats/toast", true},
+		{"polecat", true},
+		{"gastown", true},
+		{"012345", true},
+		{"0123", false}, // session prefixes must be at least 6 chars
+		{"crew", false},
+		{"beads", false},
+	}
+	for _, tt := range tests {
+		got := Match([]Pause{{Target: tt.target}}, agent) != nil
+		if got != tt.want {
+			t.Errorf("Match(target=%q) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Package perf measures gt itself. Code that scans or caches adds to
+// process-wide counters; when the town opts in to telemetry, each command's
+// latency and final counters are appended to <town>/.runtime/perf.jsonl
+// for gt perf report. Nothing is sent anywhere.
+package perf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Filename is the telemetry log within the town .runtime directory.
+const Filename = "perf.jsonl"
+
+// maxLogSize is the log size past which Append drops the oldest half.
+const maxLogSize = 4 << 20
+
+// Counter names.
+const (
+	ScanNanos   = "scan_ns"      // time spent discovering sessions
+	FilesParsed = "files_parsed" // transcripts opened during discovery
+	CacheHits   = "cache_hits"   // lookups answered from a cache
+	CacheMisses = "cache_misses" // lookups that had to do the work
+)
+
+var (
+	mu       sync.Mutex
+	counters = make(map[string]int64)
+)
+
+// Add adds n to a counter.
+func Add(name string, n int64) {
+	mu.Lock()
+	counters[name] += n
+	mu.Unlock()
+}
+
+// AddSince adds the time elapsed since start, in nanoseconds, to a counter.
+func AddSince(name string, start time.Time) {
+	Add(name, int64(time.Since(start)))
+}
+
+// Hit records a cache lookup as a hit or a miss.
+func Hit(hit bool) {
+	if hit {
+		Add(CacheHits, 1)
+	} else {
+		Add(CacheMisses, 1)
+	}
+}
+
+// Snapshot returns the current counters.
+func Snapshot() map[string]int64 {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make(map[string]int64, len(counters))
+	for k, v := range counters {
+		out[k] = v
+	}
+	return out
+}
+
+// Reset clears the counters.
+func Reset() {
+	mu.Lock()
+	counters = make(map[string]int64)
+	mu.Unlock()
+}
+
+// Record is one command run.
+type Record struct {
+	Command  string           `json:"command"` // e.g. "gt seance"
+	Start    time.Time        `json:"start"`
+	Duration time.Duration    `json:"duration_ns"`
+	ExitCode int              `json:"exit_code"`
+	Counters map[string]int64 `json:"counters,omitempty"`
+}
+
+// Path returns the telemetry log path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", Filename)
+}
+
+// Append adds a record to the town's telemetry log, trimming the oldest
+// records once the log grows past maxLogSize.
+func Append(townRoot string, rec Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	if stat, err := os.Stat(path); err == nil && stat.Size() > maxLogSize {
+		trim(path)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G302: timings are not secret
+	if err != nil {
+		return fmt.Errorf("opening telemetry log: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// trim keeps the newest half of the log.
+func trim(path string) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return
+	}
+	// Cut at the first line break at or after the midpoint.
+	mid := len(data)/2 - 1
+	if mid < 0 {
+		return
+	}
+	var keep []byte
+	if i := bytes.IndexByte(data[mid:], '\n'); i >= 0 {
+		keep = data[mid+i+1:]
+	}
+	tmp := path + ".tmp"
+	if os.WriteFile(tmp, keep, 0644) == nil { //nolint:gosec // G306: timings are not secret
+		_ = os.Rename(tmp, path)
+	}
+}
+
+// Load returns the records started at or after since, oldest first.
+// A missing log yields no records.
+func Load(townRoot string, since time.Time) ([]Record, error) {
+	f, err := os.Open(Path(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening telemetry log: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.Command == "" {
+			continue
+		}
+		if rec.Start.Before(since) {
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records, scanner.Err()
+}
+
+// CommandStats summarizes the runs of one command.
+type CommandStats struct {
+	Command     string        `json:"command"`
+	Runs        int           `json:"runs"`
+	Failures    int           `json:"failures"`
+	P50         time.Duration `json:"p50_ns"`
+	P95         time.Duration `json:"p95_ns"`
+	Max         time.Duration `json:"max_ns"`
+	AvgScan     time.Duration `json:"avg_scan_ns"`
+	AvgParsed   float64       `json:"avg_files_parsed"`
+	CacheHits   int64         `json:"cache_hits"`
+	CacheMisses int64         `json:"cache_misses"`
+}
+
+// HitRate returns the fraction of cache lookups that hit, and false if
+// the command made none.
+func (s CommandStats) HitRate() (float64, bool) {
+	total := s.CacheHits + s.CacheMisses
+	if total == 0 {
+		return 0, false
+	}
+	return float64(s.CacheHits) / float64(total), true
+}
+
+// Summarize groups records by command, slowest p95 first.
+func Summarize(records []Record) []CommandStats {
+	durations := make(map[string][]time.Duration)
+	stats := make(map[string]*CommandStats)
+	scan := make(map[string]int64)
+	parsed := make(map[string]int64)
+	for _, rec := range records {
+		s := stats[rec.Command]
+		if s == nil {
+			s = &CommandStats{Command: rec.Command}
+			stats[rec.Command] = s
+		}
+		s.Runs++
+		if rec.ExitCode != 0 {
+			s.Failures++
+		}
+		durations[rec.Command] = append(durations[rec.Command], rec.Duration)
+		scan[rec.Command] += rec.Counters[ScanNanos]
+		parsed[rec.Command] += rec.Counters[FilesParsed]
+		s.CacheHits += rec.Counters[CacheHits]
+		s.CacheMisses += rec.Counters[CacheMisses]
+	}
+
+	out := make([]CommandStats, 0, len(stats))
+	for cmd, s := range stats {
+		d := durations[cmd]
+		sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+		s.P50 = percentile(d, 50)
+		s.P95 = percentile(d, 95)
+		s.Max = d[len(d)-1]
+		s.AvgScan = time.Duration(scan[cmd] / int64(s.Runs))
+		s.AvgParsed = float64(parsed[cmd]) / float64(s.Runs)
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].P95 != out[j].P95 {
+			return out[i].P95 > out[j].P95
+		}
+		return out[i].Command < out[j].Command
+	})
+	return out
+}
+
+// percentile returns the p-th percentile of sorted durations (nearest rank).
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,94 @@
+package perf
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCounters(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	Add(FilesParsed, 3)
+	Add(FilesParsed, 2)
+	Hit(true)
+	Hit(false)
+	Hit(true)
+
+	got := Snapshot()
+	if got[FilesParsed] != 5 || got[CacheHits] != 2 || got[CacheMisses] != 1 {
+		t.Errorf("Snapshot = %v", got)
+	}
+}
+
+func TestAppendLoadSummarize(t *testing.T) {
+	town := t.TempDir()
+	base := time.Now().Add(-time.Hour).UTC()
+
+	runs := []Record{
+		{Command: "gt seance", Start: base.Add(-48 * time.Hour), Duration: time.Minute}, // too old
+		{Command: "gt seance", Start: base, Duration: 100 * time.Millisecond,
+			Counters: map[string]int64{ScanNanos: int64(80 * time.Millisecond), FilesParsed: 40, CacheMisses: 1}},
+		{Command: "gt seance", Start: base.Add(time.Minute), Duration: 300 * time.Millisecond,
+			Counters: map[string]int64{ScanNanos: int64(20 * time.Millisecond), FilesParsed: 10, CacheHits: 3}},
+		{Command: "gt mail inbox", Start: base.Add(2 * time.Minute), Duration: 50 * time.Millisecond, ExitCode: 1},
+	}
+	for _, r := range runs {
+		if err := Append(town, r); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	records, err := Load(town, base.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+
+	stats := Summarize(records)
+	if len(stats) != 2 || stats[0].Command != "gt seance" {
+		t.Fatalf("Summarize = %+v", stats)
+	}
+	s := stats[0]
+	if s.Runs != 2 || s.P50 != 100*time.Millisecond || s.P95 != 300*time.Millisecond || s.Max != 300*time.Millisecond {
+		t.Errorf("seance latencies = %+v", s)
+	}
+	if s.AvgScan != 50*time.Millisecond || s.AvgParsed != 25 {
+		t.Errorf("seance scan = %v, parsed = %v", s.AvgScan, s.AvgParsed)
+	}
+	if rate, ok := s.HitRate(); !ok || rate != 0.75 {
+		t.Errorf("HitRate = %v, %v; want 0.75", rate, ok)
+	}
+	if stats[1].Failures != 1 {
+		t.Errorf("mail failures = %d, want 1", stats[1].Failures)
+	}
+	if _, ok := stats[1].HitRate(); ok {
+		t.Error("HitRate without lookups should report false")
+	}
+}
+
+func TestTrimKeepsWholeLines(t *testing.T) {
+	town := t.TempDir()
+	if err := Append(town, Record{Command: "gt status", Start: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	path := Path(town)
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, []byte(strings.Repeat(string(data), 4)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	trim(path)
+	trimmed, _ := os.ReadFile(path)
+	if got := strings.Count(string(trimmed), "\n"); got != 2 {
+		t.Errorf("trimmed log has %d lines, want 2", got)
+	}
+	records, err := Load(town, time.Time{})
+	if err != nil || len(records) != 2 {
+		t.Errorf("Load after trim = %d records, %v", len(records), err)
+	}
+}
@@ -0,0 +1,130 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// checkTownSettings applies the town settings' value rules.
+func (w *jsonWalker) checkTownSettings(s *config.TownSettings) {
+	w.checkHeader("town-settings", s.Type, s.Version, config.CurrentTownSettingsVersion)
+	w.checkEnum("assign_guard", s.AssignGuard, config.AssignGuardBlock, config.AssignGuardWarn, config.AssignGuardOff)
+
+	if err := s.PriceTable().Validate(); err != nil {
+		w.report("pricing", SeverityError, "pricing: "+err.Error(), nil)
+	}
+	for i, rule := range s.SessionTags {
+		if _, err := config.CompileSessionTags([]config.SessionTagRule{rule}); err != nil {
+			msg := strings.Replace(err.Error(), "session_tags[0]", fmt.Sprintf("session_tags[%d]", i), 1)
+			w.report(fmt.Sprintf("session_tags[%d]", i), SeverityError, msg, nil)
+		}
+	}
+	if err := config.ValidateCommandAliases(s.CommandAliases); err != nil {
+		path, _, _ := strings.Cut(err.Error(), ":")
+		w.report(path, SeverityError, err.Error(), nil)
+	}
+	if err := s.IssueLinkSettings().Validate(); err != nil {
+		w.report("issue_links.idle_after", SeverityError, err.Error(), nil)
+	}
+	if s.Nightshift != nil {
+		if err := s.Nightshift.Validate(); err != nil {
+			path, _, _ := strings.Cut(err.Error(), ":")
+			w.report(path, SeverityError, err.Error(), nil)
+		}
+	}
+	if s.Ingest != nil {
+		if err := s.Ingest.Validate(); err != nil {
+			path, _, _ := strings.Cut(err.Error(), ":")
+			w.report(path, SeverityError, err.Error(), nil)
+		}
+	}
+}
+
+// checkRigSettings applies the rig settings' value rules.
+func (w *jsonWalker) checkRigSettings(s *config.RigSettings) {
+	w.checkHeader("rig-settings", s.Type, s.Version, config.CurrentRigSettingsVersion)
+	if s.MergeQueue != nil {
+		w.checkEnum("merge_queue.on_conflict", s.MergeQueue.OnConflict,
+			config.OnConflictAssignBack, config.OnConflictAutoRebase)
+		mq := *s.MergeQueue
+		mq.OnConflict = "" // reported above, with a fix
+		if err := mq.Validate(); err != nil {
+			w.report("merge_queue", SeverityError, "merge_queue: "+err.Error(), nil)
+		}
+	}
+	if s.Claude != nil {
+		w.checkEnum("claude.permission_mode", s.Claude.PermissionMode,
+			claude.PermissionDefault, claude.PermissionAcceptEdits, claude.PermissionPlan, claude.PermissionBypass)
+	}
+}
+
+// checkMessaging applies the messaging config's value rules.
+func (w *jsonWalker) checkMessaging(c *config.MessagingConfig) {
+	w.checkHeader("messaging", c.Type, c.Version, config.CurrentMessagingVersion)
+	if err := c.Delivery.Validate(); err != nil {
+		path, _, _ := strings.Cut(err.Error(), ":")
+		w.report(path, SeverityError, err.Error(), nil)
+	}
+
+	check := func(section string, names map[string]int, what string) {
+		for name, n := range names {
+			if n == 0 {
+				w.report(section+"."+name, SeverityError,
+					fmt.Sprintf("%s %q has no %s", strings.TrimSuffix(section, "s"), name, what), nil)
+			}
+		}
+	}
+	lists := make(map[string]int)
+	for name, recipients := range c.Lists {
+		lists[name] = len(recipients)
+	}
+	check("lists", lists, "recipients")
+	queues := make(map[string]int)
+	for name, q := range c.Queues {
+		queues[name] = len(q.Workers)
+		if q.MaxClaims < 0 {
+			w.report("queues."+name+".max_claims", SeverityError,
+				fmt.Sprintf("queue %q max_claims must be non-negative", name), nil)
+		}
+	}
+	check("queues", queues, "workers")
+	announces := make(map[string]int)
+	for name, a := range c.Announces {
+		announces[name] = len(a.Readers)
+		if a.RetainCount < 0 {
+			w.report("announces."+name+".retain_count", SeverityError,
+				fmt.Sprintf("announce %q retain_count must be non-negative", name), nil)
+		}
+	}
+	check("announces", announces, "readers")
+	channels := make(map[string]int)
+	for name, recipients := range c.NudgeChannels {
+		channels[name] = len(recipients)
+	}
+	check("nudge_channels", channels, "recipients")
+}
+
+// checkPlan applies a JSON plan's rules; see mayor.LoadPlan.
+func (w *jsonWalker) checkPlan(p *planSchema) {
+	if len(p.Tasks) == 0 {
+		w.report("tasks", SeverityError, "plan has no tasks", nil)
+	}
+	for i, t := range p.Tasks {
+		if t.Role == "" {
+			w.report(fmt.Sprintf("tasks[%d]", i), SeverityError, fmt.Sprintf("task %d: role is required", i+1), nil)
+		}
+	}
+}
+
+// checkHeader checks a config's type and schema version.
+func (w *jsonWalker) checkHeader(want, typ string, version, maxVersion int) {
+	if typ != "" && typ != want {
+		w.report("type", SeverityError, fmt.Sprintf("type is %q, want %q", typ, want), nil)
+	}
+	if version > maxVersion {
+		w.report("version", SeverityError, fmt.Sprintf("version %d is newer than this gt supports (%d)", version, maxVersion), nil)
+	}
+}
@@ -0,0 +1,348 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// schemas maps each JSON kind to the type its loader decodes into.
+var schemas = map[Kind]reflect.Type{
+	KindTownSettings: reflect.TypeOf(config.TownSettings{}),
+	KindRigSettings:  reflect.TypeOf(config.RigSettings{}),
+	KindMessaging:    reflect.TypeOf(config.MessagingConfig{}),
+	KindPlan:         reflect.TypeOf(planSchema{}),
+}
+
+// planSchema mirrors mayor.Plan's JSON form.
+type planSchema struct {
+	Name  string `json:"name"`
+	Tasks []struct {
+		ID    string `json:"id"`
+		Role  string `json:"role"`
+		Rig   string `json:"rig"`
+		Topic string `json:"topic"`
+		Task  string `json:"task"`
+	} `json:"tasks"`
+}
+
+// span is a byte range in the linted file.
+type span struct {
+	start, end int
+}
+
+// jsonNode records where a field's key and value appear.
+type jsonNode struct {
+	key   span
+	value span
+}
+
+// jsonWalker walks a JSON document alongside the Go type it decodes into,
+// recording field positions and flagging keys the decoder would drop.
+type jsonWalker struct {
+	data   []byte
+	dec    *json.Decoder
+	nodes  map[string]jsonNode
+	issues []Issue
+}
+
+// lintJSON checks a JSON config: syntax, unknown and duplicate keys, type
+// mismatches, then the kind's semantic rules.
+func lintJSON(kind Kind, data []byte) []Issue {
+	w := &jsonWalker{
+		data:  data,
+		dec:   json.NewDecoder(bytes.NewReader(data)),
+		nodes: make(map[string]jsonNode),
+	}
+	if err := w.walk(schemas[kind], ""); err != nil {
+		w.syntaxError(err)
+		return w.issues
+	}
+
+	target := reflect.New(schemas[kind]).Interface()
+	if err := json.Unmarshal(data, target); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			w.add(int(typeErr.Offset), typeErr.Field, SeverityError,
+				fmt.Sprintf("%s must be %s, not %s", typeErr.Field, typeErr.Type, typeErr.Value), nil)
+		} else {
+			w.add(0, "", SeverityError, err.Error(), nil)
+		}
+		return w.issues
+	}
+
+	switch v := target.(type) {
+	case *config.TownSettings:
+		w.checkTownSettings(v)
+	case *config.RigSettings:
+		w.checkRigSettings(v)
+	case *config.MessagingConfig:
+		w.checkMessaging(v)
+	case *planSchema:
+		w.checkPlan(v)
+	}
+	return w.issues
+}
+
+// walk consumes one JSON value of type t at path. A nil t accepts any
+// value without checking its keys.
+func (w *jsonWalker) walk(t reflect.Type, path string) error {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	tok, err := w.dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		var fields map[string]reflect.Type
+		if t != nil && t.Kind() == reflect.Struct {
+			fields = jsonFields(t)
+		}
+		seen := make(map[string]bool)
+		for w.dec.More() {
+			tok, err := w.dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := tok.(string)
+			end := int(w.dec.InputOffset())
+			keySpan := span{w.keyStart(end), end}
+			child := joinPath(path, key)
+
+			if seen[key] {
+				w.add(keySpan.start, child, SeverityWarning,
+					fmt.Sprintf("duplicate key %q; the last value wins", key), nil)
+			}
+			seen[key] = true
+
+			var elem reflect.Type
+			switch {
+			case fields != nil:
+				var name string
+				elem, name = w.checkKey(fields, key, keySpan, path)
+				child = joinPath(path, name)
+			case t != nil && t.Kind() == reflect.Map:
+				elem = t.Elem()
+			}
+
+			valueStart := w.skipSpace(end)
+			if err := w.walk(elem, child); err != nil {
+				return err
+			}
+			w.nodes[child] = jsonNode{key: keySpan, value: span{valueStart, int(w.dec.InputOffset())}}
+		}
+	case '[':
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		for i := 0; w.dec.More(); i++ {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			start := w.skipSpace(int(w.dec.InputOffset()))
+			if err := w.walk(elem, child); err != nil {
+				return err
+			}
+			w.nodes[child] = jsonNode{key: span{start, start}, value: span{start, int(w.dec.InputOffset())}}
+		}
+	default:
+		return nil
+	}
+	_, err = w.dec.Token() // closing delimiter
+	return err
+}
+
+// checkKey reports a key its struct doesn't declare, and returns the
+// field type and name the decoder would use for it.
+func (w *jsonWalker) checkKey(fields map[string]reflect.Type, key string, keySpan span, path string) (reflect.Type, string) {
+	if ft, ok := fields[key]; ok {
+		return ft, key
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// encoding/json matches keys case-insensitively, so the value is used,
+	// but the key won't survive a save and reads as a typo.
+	for _, name := range names {
+		if strings.EqualFold(name, key) {
+			w.add(keySpan.start, joinPath(path, key), SeverityWarning,
+				fmt.Sprintf("key %q should be spelled %q", key, name), renameKey(keySpan, name))
+			return fields[name], name
+		}
+	}
+
+	msg := fmt.Sprintf("unknown field %q is ignored", key)
+	var fix *Fix
+	if name := suggest(key, names); name != "" {
+		msg += fmt.Sprintf("; did you mean %q?", name)
+		fix = renameKey(keySpan, name)
+	}
+	w.add(keySpan.start, joinPath(path, key), SeverityError, msg, fix)
+	return nil, key
+}
+
+// renameKey builds a fix replacing the quoted key at s with name.
+func renameKey(s span, name string) *Fix {
+	quoted, _ := json.Marshal(name)
+	return &Fix{
+		Offset:      s.start,
+		Length:      s.end - s.start,
+		Replacement: string(quoted),
+		Description: "rename to " + string(quoted),
+	}
+}
+
+// keyStart finds the opening quote of the string key ending at end.
+func (w *jsonWalker) keyStart(end int) int {
+	for i := end - 2; i >= 0; i-- {
+		if w.data[i] == '"' && (i == 0 || w.data[i-1] != '\\') {
+			return i
+		}
+	}
+	return 0
+}
+
+// skipSpace returns the offset of the next value at or after i, skipping
+// whitespace and the separators the decoder hasn't consumed yet.
+func (w *jsonWalker) skipSpace(i int) int {
+	for i < len(w.data) && strings.IndexByte(" \t\r\n:,", w.data[i]) >= 0 {
+		i++
+	}
+	return i
+}
+
+// syntaxError reports a parse failure at its offset.
+func (w *jsonWalker) syntaxError(err error) {
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &syntaxErr):
+		w.add(int(syntaxErr.Offset), "", SeverityError, "invalid JSON: "+syntaxErr.Error(), nil)
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		w.add(len(w.data), "", SeverityError, "invalid JSON: unexpected end of file", nil)
+	default:
+		w.add(int(w.dec.InputOffset()), "", SeverityError, "invalid JSON: "+err.Error(), nil)
+	}
+}
+
+// add records an issue at a byte offset.
+func (w *jsonWalker) add(offset int, path, severity, msg string, fix *Fix) {
+	line, col := position(w.data, offset)
+	w.issues = append(w.issues, Issue{Line: line, Col: col, Path: path, Severity: severity, Message: msg, Fix: fix})
+}
+
+// report records an issue at the key for path, or its nearest recorded
+// parent when path itself isn't in the file.
+func (w *jsonWalker) report(path, severity, msg string, fix *Fix) {
+	for p := path; ; p = parentPath(p) {
+		if node, ok := w.nodes[p]; ok {
+			w.add(node.key.start, path, severity, msg, fix)
+			return
+		}
+		if p == "" {
+			w.add(w.skipSpace(0), path, severity, msg, fix)
+			return
+		}
+	}
+}
+
+// checkEnum reports a string field whose value isn't one of allowed,
+// fixing values that only differ in case.
+func (w *jsonWalker) checkEnum(path, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	msg := fmt.Sprintf("%s %q is not one of %s", path, value, strings.Join(allowed, ", "))
+	var fix *Fix
+	for _, a := range allowed {
+		if strings.EqualFold(value, a) {
+			if node, ok := w.nodes[path]; ok {
+				quoted, _ := json.Marshal(a)
+				fix = &Fix{
+					Offset:      node.value.start,
+					Length:      node.value.end - node.value.start,
+					Replacement: string(quoted),
+					Description: "use " + string(quoted),
+				}
+			}
+			break
+		}
+	}
+	w.report(path, SeverityError, msg, fix)
+}
+
+// jsonFields returns the JSON field names of struct t, including promoted
+// fields of embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range jsonFields(ft) {
+					fields[k] = v
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// joinPath appends a key to a dotted field path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// parentPath strips the last key or index from a field path.
+func parentPath(path string) string {
+	if strings.HasSuffix(path, "]") {
+		if i := strings.LastIndexByte(path, '['); i >= 0 {
+			return path[:i]
+		}
+	}
+	if i := strings.LastIndexByte(path, '.'); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Plan fields accepted by mayor.LoadPlan's YAML parser.
+var (
+	planFields     = []string{"name", "tasks"}
+	planTaskFields = []string{"id", "role", "rig", "topic", "task"}
+)
+
+// lintPlan checks a YAML plan against the subset mayor.LoadPlan parses,
+// reporting every problem rather than stopping at the first.
+func lintPlan(data []byte) []Issue {
+	var issues []Issue
+	add := func(line, col int, severity, msg string, fix *Fix) {
+		issues = append(issues, Issue{Line: line, Col: col, Severity: severity, Message: msg, Fix: fix})
+	}
+
+	inTasks := false
+	tasks := 0
+	taskLine := 0
+	taskHasRole := false
+	endTask := func() {
+		if tasks > 0 && !taskHasRole {
+			add(taskLine, 1, SeverityError, fmt.Sprintf("task %d: role is required", tasks), nil)
+		}
+	}
+
+	offset := 0
+	for lineNum, raw := range strings.SplitAfter(string(data), "\n") {
+		lineNum++
+		lineStart := offset
+		offset += len(raw)
+		raw = strings.TrimRight(raw, "\r\n")
+
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " \t"))
+		col := indent + 1
+
+		if indent == 0 && strings.HasPrefix(line, "-") {
+			// A task list item written flush with "tasks:".
+			msg := "task list items must be indented under tasks:"
+			if !inTasks {
+				msg = "list item outside tasks:"
+			}
+			add(lineNum, 1, SeverityError, msg, &Fix{
+				Offset: lineStart, Replacement: "  ", Description: "indent two spaces",
+			})
+		} else if indent == 0 {
+			key, _, ok := strings.Cut(line, ":")
+			if !ok {
+				add(lineNum, col, SeverityError, "expected key: value", nil)
+				continue
+			}
+			key = strings.TrimSpace(key)
+			switch key {
+			case "name":
+			case "tasks":
+				inTasks = true
+			default:
+				issues = append(issues, unknownYAMLKey(lineNum, col, lineStart+indent, key, "field", planFields))
+			}
+			continue
+		} else if !inTasks {
+			add(lineNum, col, SeverityError, "unexpected indentation", nil)
+			continue
+		}
+
+		field := line
+		fieldOffset := lineStart + indent
+		if rest, ok := strings.CutPrefix(line, "-"); ok {
+			endTask()
+			tasks++
+			taskLine, taskHasRole = lineNum, false
+			field = strings.TrimSpace(rest)
+			fieldOffset += len(line) - len(field)
+			if field == "" {
+				continue
+			}
+		} else if tasks == 0 {
+			add(lineNum, col, SeverityError, "task fields must follow '-'", nil)
+			continue
+		}
+
+		key, value, ok := strings.Cut(field, ":")
+		if !ok {
+			add(lineNum, col, SeverityError, "expected key: value", nil)
+			continue
+		}
+		key = strings.TrimSpace(key)
+		switch key {
+		case "role":
+			taskHasRole = strings.TrimSpace(value) != ""
+		case "id", "rig", "topic", "task":
+		default:
+			fieldCol := fieldOffset - lineStart + 1
+			issue := unknownYAMLKey(lineNum, fieldCol, fieldOffset, key, "task field", planTaskFields)
+			if issue.Fix != nil && issue.Fix.Replacement == "role" {
+				taskHasRole = strings.TrimSpace(value) != ""
+			}
+			issues = append(issues, issue)
+		}
+	}
+	endTask()
+
+	if tasks == 0 {
+		line := 1 + bytes.Count(bytes.TrimRight(data, "\n"), []byte("\n"))
+		issues = append(issues, Issue{Line: line, Col: 1, Severity: SeverityError, Message: "plan has no tasks"})
+	}
+	return issues
+}
+
+// unknownYAMLKey reports a key the plan parser rejects, with a rename fix
+// when it is close to a known one.
+func unknownYAMLKey(line, col, offset int, key, what string, known []string) Issue {
+	issue := Issue{
+		Line:     line,
+		Col:      col,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("unknown %s %q", what, key),
+	}
+	if name := suggest(key, known); name != "" {
+		issue.Message += fmt.Sprintf("; did you mean %q?", name)
+		issue.Fix = &Fix{Offset: offset, Length: len(key), Replacement: name, Description: "rename to " + name}
+	}
+	return issue
+}
@@ -0,0 +1,243 @@
+// Package policy statically checks the files that configure Gas Town's
+// guard rails: town and rig settings, messaging delivery and retention,
+// mayor plans, and task specs.
+//
+// The loaders are lenient by design (unknown JSON keys are dropped and
+// keys match case-insensitively), so a misspelled guard rail is silently
+// ignored at runtime. Lint reports those mistakes with line and column,
+// and offers mechanical fixes for the common ones.
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Kind identifies which kind of file is being linted.
+type Kind string
+
+// Kinds of files Lint understands.
+const (
+	KindTownSettings Kind = "town-settings"
+	KindRigSettings  Kind = "rig-settings"
+	KindMessaging    Kind = "messaging"
+	KindPlan         Kind = "plan"
+	KindTask         Kind = "task"
+)
+
+// Severity levels for issues.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is a problem found in a file.
+type Issue struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Col      int    `json:"col"`
+	Path     string `json:"path,omitempty"` // field path, e.g. "delivery.retry_interval"
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Fix      *Fix   `json:"fix,omitempty"`
+}
+
+// Fix is a mechanical edit that resolves an issue: replace Length bytes
+// at Offset with Replacement.
+type Fix struct {
+	Offset      int    `json:"offset"`
+	Length      int    `json:"length"`
+	Replacement string `json:"replacement"`
+	Description string `json:"description"`
+}
+
+// Result is the outcome of linting one file.
+type Result struct {
+	File   string  `json:"file"`
+	Kind   Kind    `json:"kind"`
+	Issues []Issue `json:"issues"`
+}
+
+// Errors returns the number of error-severity issues.
+func (r *Result) Errors() int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// Fixable returns the number of issues with a fix.
+func (r *Result) Fixable() int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Fix != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// LintFile reads and lints a file, detecting its kind.
+func LintFile(path string) (*Result, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: user-specified config file
+	if err != nil {
+		return nil, err
+	}
+	kind, err := DetectKind(path, data)
+	if err != nil {
+		return nil, err
+	}
+	return Lint(path, kind, data), nil
+}
+
+// Lint checks data as a file of the given kind.
+func Lint(path string, kind Kind, data []byte) *Result {
+	var issues []Issue
+	switch kind {
+	case KindPlan:
+		issues = lintPlan(data)
+	case KindTask:
+		issues = lintTask(data)
+	default:
+		issues = lintJSON(kind, data)
+	}
+	if issues == nil {
+		issues = []Issue{}
+	}
+	for i := range issues {
+		issues[i].File = path
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Col < issues[j].Col
+	})
+	return &Result{File: path, Kind: kind, Issues: issues}
+}
+
+// DetectKind infers a file's kind from its extension, its "type" field,
+// or its name. YAML files are plans unless named task.yaml or
+// <name>.task.yaml.
+func DetectKind(path string, data []byte) (Kind, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		stem := strings.TrimSuffix(strings.ToLower(filepath.Base(path)), ext)
+		if stem == "task" || strings.HasSuffix(stem, ".task") {
+			return KindTask, nil
+		}
+		return KindPlan, nil
+	}
+
+	var header struct {
+		Type  string          `json:"type"`
+		Tasks json.RawMessage `json:"tasks"`
+	}
+	_ = json.Unmarshal(data, &header)
+	switch header.Type {
+	case string(KindTownSettings), string(KindRigSettings), string(KindMessaging):
+		return Kind(header.Type), nil
+	}
+	if header.Tasks != nil {
+		return KindPlan, nil
+	}
+	if filepath.Base(path) == "messaging.json" {
+		return KindMessaging, nil
+	}
+	return "", fmt.Errorf("%s: cannot tell which config this is (set \"type\" to town-settings, rig-settings, or messaging)", path)
+}
+
+// ApplyFixes applies the fixes in issues to data. Fixes that overlap an
+// earlier one are skipped. It returns the edited data and the number of
+// fixes applied.
+func ApplyFixes(data []byte, issues []Issue) ([]byte, int) {
+	var fixes []Fix
+	for _, issue := range issues {
+		if issue.Fix != nil {
+			fixes = append(fixes, *issue.Fix)
+		}
+	}
+	sort.SliceStable(fixes, func(i, j int) bool { return fixes[i].Offset < fixes[j].Offset })
+
+	var out bytes.Buffer
+	pos, applied := 0, 0
+	for _, f := range fixes {
+		if f.Offset < pos || f.Offset+f.Length > len(data) {
+			continue
+		}
+		out.Write(data[pos:f.Offset])
+		out.WriteString(f.Replacement)
+		pos = f.Offset + f.Length
+		applied++
+	}
+	out.Write(data[pos:])
+	return out.Bytes(), applied
+}
+
+// position converts a byte offset in data to a 1-based line and column.
+func position(data []byte, offset int) (line, col int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	line = 1 + bytes.Count(data[:offset], []byte("\n"))
+	col = offset - bytes.LastIndexByte(data[:offset], '\n')
+	return line, col
+}
+
+// suggest returns the candidate closest to name by edit distance, if it is
+// close enough to be a likely typo.
+func suggest(name string, candidates []string) string {
+	best, bestDist, tied := "", 0, false
+	for _, c := range candidates {
+		d := editDistance(strings.ToLower(name), strings.ToLower(c))
+		switch {
+		case best == "" || d < bestDist:
+			best, bestDist, tied = c, d, false
+		case d == bestDist:
+			tied = true
+		}
+	}
+	limit := 2
+	if len(name) <= 4 {
+		limit = 1
+	}
+	if best == "" || tied || bestDist > limit {
+		return ""
+	}
+	return best
+}
+
+// editDistance is the Damerau-Levenshtein (optimal string alignment)
+// distance between a and b, so transposed letters count as one edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
@@ -0,0 +1,223 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintTownSettingsUnknownKey(t *testing.T) {
+	data := []byte(`{
+  "type": "town-settings",
+  "version": 1,
+  "asign_guard": "off",
+  "Telemetry": {"enabled": true}
+}
+`)
+	r := Lint("config.json", KindTownSettings, data)
+	if len(r.Issues) != 2 {
+		t.Fatalf("issues = %+v", r.Issues)
+	}
+
+	typo := r.Issues[0]
+	if typo.Line != 4 || typo.Col != 3 || typo.Severity != SeverityError || !strings.Contains(typo.Message, `did you mean "assign_guard"`) {
+		t.Errorf("typo issue = %+v", typo)
+	}
+	caseIssue := r.Issues[1]
+	if caseIssue.Line != 5 || caseIssue.Severity != SeverityWarning {
+		t.Errorf("case issue = %+v", caseIssue)
+	}
+
+	fixed, n := ApplyFixes(data, r.Issues)
+	if n != 2 {
+		t.Fatalf("applied %d fixes, want 2", n)
+	}
+	if !strings.Contains(string(fixed), `"assign_guard": "off"`) || !strings.Contains(string(fixed), `"telemetry":`) {
+		t.Errorf("fixed = %s", fixed)
+	}
+	if again := Lint("config.json", KindTownSettings, fixed); len(again.Issues) != 0 {
+		t.Errorf("issues after fix = %+v", again.Issues)
+	}
+}
+
+func TestLintTownSettingsValues(t *testing.T) {
+	data := []byte(`{
+  "type": "town-settings",
+  "assign_guard": "Warn",
+  "session_tags": [{"tag": "spike", "topic": "("}],
+  "issue_links": {"idle_after": "soon"}
+}`)
+	r := Lint("config.json", KindTownSettings, data)
+	if r.Errors() != 3 {
+		t.Fatalf("errors = %d, issues = %+v", r.Errors(), r.Issues)
+	}
+	if r.Issues[0].Line != 3 || r.Issues[0].Fix == nil || r.Issues[0].Fix.Replacement != `"warn"` {
+		t.Errorf("assign_guard issue = %+v", r.Issues[0])
+	}
+	if r.Issues[1].Line != 4 || !strings.Contains(r.Issues[1].Message, "session_tags[0]") {
+		t.Errorf("session_tags issue = %+v", r.Issues[1])
+	}
+	if r.Issues[2].Line != 5 || r.Issues[2].Path != "issue_links.idle_after" {
+		t.Errorf("idle_after issue = %+v", r.Issues[2])
+	}
+}
+
+func TestLintMessaging(t *testing.T) {
+	data := []byte(`{
+  "type": "messaging",
+  "lists": {"oncall": []},
+  "announces": {"alerts": {"readers": ["@town"], "retain_count": -1}},
+  "delivery": {"retry_interval": "5 minutes"}
+}`)
+	r := Lint("messaging.json", KindMessaging, data)
+	var lines []int
+	for _, issue := range r.Issues {
+		lines = append(lines, issue.Line)
+	}
+	if len(lines) != 3 || lines[0] != 3 || lines[1] != 4 || lines[2] != 5 {
+		t.Errorf("issue lines = %v, issues = %+v", lines, r.Issues)
+	}
+}
+
+func TestLintRigSettings(t *testing.T) {
+	data := []byte(`{"type": "rig-settings", "merge_queue": {"on_conflict": "rebase"}, "claude": {"permission_mode": "acceptedits"}}`)
+	r := Lint("config.json", KindRigSettings, data)
+	if r.Errors() != 2 {
+		t.Fatalf("issues = %+v", r.Issues)
+	}
+	if r.Fixable() != 1 {
+		t.Errorf("fixable = %d, want 1 (permission mode case)", r.Fixable())
+	}
+}
+
+func TestLintSyntaxAndTypeErrors(t *testing.T) {
+	r := Lint("config.json", KindTownSettings, []byte("{\n  \"assign_guard\": \"off\",\n}"))
+	if len(r.Issues) != 1 || r.Issues[0].Line != 2 || !strings.Contains(r.Issues[0].Message, "invalid JSON") {
+		t.Errorf("syntax issues = %+v", r.Issues)
+	}
+
+	r = Lint("config.json", KindTownSettings, []byte("{\n  \"telemetry\": {\"enabled\": \"yes\"}\n}"))
+	if len(r.Issues) != 1 || r.Issues[0].Line != 2 || !strings.Contains(r.Issues[0].Message, "must be bool") {
+		t.Errorf("type issues = %+v", r.Issues)
+	}
+}
+
+func TestLintPlan(t *testing.T) {
+	data := []byte(`name: auth rewrite
+tasks:
+- id: t1
+  role: polecat
+  - id: t2
+    rol: crew
+  - id: t3
+    topic: docs
+`)
+	r := Lint("plan.yaml", KindPlan, data)
+	if len(r.Issues) != 3 {
+		t.Fatalf("issues = %+v", r.Issues)
+	}
+	if r.Issues[0].Line != 3 || r.Issues[0].Fix == nil {
+		t.Errorf("flush task issue = %+v", r.Issues[0])
+	}
+	if r.Issues[1].Line != 6 || r.Issues[1].Col != 5 || r.Issues[1].Fix == nil || r.Issues[1].Fix.Replacement != "role" {
+		t.Errorf("typo issue = %+v", r.Issues[1])
+	}
+	if r.Issues[2].Line != 7 || !strings.Contains(r.Issues[2].Message, "task 3: role is required") {
+		t.Errorf("missing role issue = %+v", r.Issues[2])
+	}
+
+	fixed, _ := ApplyFixes(data, r.Issues)
+	again := Lint("plan.yaml", KindPlan, fixed)
+	if len(again.Issues) != 1 {
+		t.Errorf("issues after fix = %+v", again.Issues)
+	}
+}
+
+func TestLintTask(t *testing.T) {
+	data := []byte(`goal: |
+  Retry mail delivery
+constraints:
+- keep the format
+acceptence:
+  - tests pass
+budget:
+  cost: lots
+  tokns: 2M
+owner: me
+`)
+	r := Lint("task.yaml", KindTask, data)
+	if len(r.Issues) != 5 {
+		t.Fatalf("issues = %+v", r.Issues)
+	}
+	if r.Issues[0].Line != 4 || r.Issues[0].Fix == nil {
+		t.Errorf("flush item issue = %+v", r.Issues[0])
+	}
+	if r.Issues[1].Line != 5 || r.Issues[1].Fix == nil || r.Issues[1].Fix.Replacement != "acceptance" {
+		t.Errorf("typo issue = %+v", r.Issues[1])
+	}
+	if r.Issues[2].Line != 8 || r.Issues[2].Col != 9 || !strings.Contains(r.Issues[2].Message, "invalid cost") {
+		t.Errorf("cost issue = %+v", r.Issues[2])
+	}
+	if r.Issues[3].Line != 9 || r.Issues[3].Fix == nil || r.Issues[3].Fix.Replacement != "tokens" {
+		t.Errorf("budget typo issue = %+v", r.Issues[3])
+	}
+	if r.Issues[4].Line != 10 || r.Issues[4].Fix != nil {
+		t.Errorf("unknown field issue = %+v", r.Issues[4])
+	}
+
+	fixed, _ := ApplyFixes(data, r.Issues)
+	again := Lint("task.yaml", KindTask, fixed)
+	if len(again.Issues) != 2 {
+		t.Errorf("issues after fix = %+v", again.Issues)
+	}
+
+	bare := Lint("task.yaml", KindTask, []byte("constraints:\n  - none\n"))
+	if len(bare.Issues) != 2 || bare.Issues[0].Message != "goal is required" || bare.Issues[1].Severity != SeverityWarning {
+		t.Errorf("bare spec issues = %+v", bare.Issues)
+	}
+
+	empty := Lint("task.yaml", KindTask, []byte("goal: g\nacceptance:\n  - \"run:\"\n  - exists: done.txt\n"))
+	if len(empty.Issues) != 1 || empty.Issues[0].Line != 3 || empty.Issues[0].Message != "run: check needs a command" {
+		t.Errorf("empty check issues = %+v", empty.Issues)
+	}
+}
+
+func TestDetectKind(t *testing.T) {
+	tests := []struct {
+		path string
+		data string
+		want Kind
+	}{
+		{"plan.yaml", "", KindPlan},
+		{"settings/config.json", `{"type": "town-settings"}`, KindTownSettings},
+		{"gastown/settings/config.json", `{"type": "rig-settings"}`, KindRigSettings},
+		{"config/messaging.json", `{}`, KindMessaging},
+		{"plan.json", `{"tasks": []}`, KindPlan},
+		{"task.yaml", "", KindTask},
+		{"tasks/auth.task.yml", "", KindTask},
+		{"tasks.yaml", "", KindPlan},
+	}
+	for _, tt := range tests {
+		got, err := DetectKind(tt.path, []byte(tt.data))
+		if err != nil || got != tt.want {
+			t.Errorf("DetectKind(%q) = %q, %v; want %q", tt.path, got, err, tt.want)
+		}
+	}
+	if _, err := DetectKind("other.json", []byte(`{}`)); err == nil {
+		t.Error("DetectKind accepted an unrecognized file")
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	known := []string{"assign_guard", "pricing", "session_tags", "telemetry"}
+	for name, want := range map[string]string{
+		"asign_guard":  "assign_guard",
+		"pricnig":      "pricing",
+		"telemtry":     "telemetry",
+		"session_tag":  "session_tags",
+		"rig_aliases2": "",
+	} {
+		if got := suggest(name, known); got != want {
+			t.Errorf("suggest(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
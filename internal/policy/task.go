@@ -0,0 +1,188 @@
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/task"
+)
+
+// lintTask checks a task spec against the subset task.Parse accepts,
+// reporting every problem rather than stopping at the first.
+func lintTask(data []byte) []Issue {
+	var issues []Issue
+	add := func(line, col int, severity, msg string, fix *Fix) {
+		issues = append(issues, Issue{Line: line, Col: col, Severity: severity, Message: msg, Fix: fix})
+	}
+
+	section := "" // field whose indented items follow; "?" for an unknown one
+	inBlock := false
+	seen := make(map[string]bool)
+	hasGoal, checks := false, 0
+
+	offset := 0
+	lines := strings.SplitAfter(string(data), "\n")
+	for lineNum, raw := range lines {
+		lineNum++
+		lineStart := offset
+		offset += len(raw)
+		raw = strings.TrimRight(raw, "\r\n")
+
+		line := strings.TrimSpace(raw)
+		indent := len(raw) - len(strings.TrimLeft(raw, " \t"))
+		col := indent + 1
+		if inBlock {
+			if indent > 0 || line == "" {
+				hasGoal = hasGoal || line != ""
+				continue
+			}
+			inBlock = false
+		}
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+
+		if indent == 0 && strings.HasPrefix(line, "-") {
+			if slices.Contains(task.ListFields, section) {
+				add(lineNum, 1, SeverityError, "list items must be indented under "+section+":", &Fix{
+					Offset: lineStart, Replacement: "  ", Description: "indent two spaces",
+				})
+				checks += countCheck(section, line)
+			} else {
+				add(lineNum, 1, SeverityError, "list item outside a list field", nil)
+			}
+			continue
+		}
+
+		if indent == 0 {
+			key, value, ok := task.SplitField(line)
+			if !ok {
+				add(lineNum, col, SeverityError, "expected key: value", nil)
+				section = "?"
+				continue
+			}
+			section = "?"
+			if !slices.Contains(task.Fields, key) {
+				issue := unknownYAMLKey(lineNum, col, lineStart, key, "field", task.Fields)
+				issues = append(issues, issue)
+				if issue.Fix == nil {
+					continue
+				}
+				key = issue.Fix.Replacement
+			}
+			if seen[key] {
+				add(lineNum, col, SeverityError, fmt.Sprintf("duplicate field %q", key), nil)
+			}
+			seen[key] = true
+
+			if key == "goal" {
+				if value == "|" || value == ">" {
+					inBlock = true
+				} else {
+					hasGoal = value != ""
+				}
+				continue
+			}
+			if value != "" {
+				add(lineNum, col, SeverityError, fmt.Sprintf("%s takes indented items, not a value", key), nil)
+			}
+			section = key
+			continue
+		}
+
+		switch section {
+		case "?":
+		case "":
+			add(lineNum, col, SeverityError, "unexpected indentation", nil)
+		case "budget":
+			key, value, ok := task.SplitField(line)
+			if !ok {
+				add(lineNum, col, SeverityError, "expected key: value", nil)
+				continue
+			}
+			if !slices.Contains(task.BudgetFields, key) {
+				issue := unknownYAMLKey(lineNum, col, lineStart+indent, key, "budget field", task.BudgetFields)
+				issues = append(issues, issue)
+				if issue.Fix == nil {
+					continue
+				}
+				key = issue.Fix.Replacement
+			}
+			if msg := checkBudgetValue(key, value); msg != "" {
+				valueCol := col + strings.Index(line, ":") + 2
+				add(lineNum, valueCol, SeverityError, msg, nil)
+			}
+		default:
+			item, ok := strings.CutPrefix(line, "-")
+			if !ok {
+				add(lineNum, col, SeverityError, section+" items must start with '-'", nil)
+				continue
+			}
+			if strings.TrimSpace(item) == "" {
+				add(lineNum, col, SeverityError, "empty "+section+" item", nil)
+				continue
+			}
+			checks += countCheck(section, line)
+			if c := task.ParseCheck(strings.Trim(strings.TrimSpace(item), `"'`)); section == "acceptance" && c.Automated() && c.Arg == "" {
+				add(lineNum, col, SeverityError, fmt.Sprintf("%s: check needs a %s", c.Kind, checkArgName(c.Kind)), nil)
+			}
+		}
+	}
+
+	if !hasGoal {
+		add(1, 1, SeverityError, "goal is required", nil)
+	}
+	if checks == 0 {
+		add(1, 1, SeverityWarning, "no acceptance checks; nothing says when the task is done", nil)
+	}
+	return issues
+}
+
+// countCheck returns 1 if line is a non-empty acceptance item.
+func countCheck(section, line string) int {
+	if section == "acceptance" && strings.TrimSpace(strings.TrimPrefix(line, "-")) != "" {
+		return 1
+	}
+	return 0
+}
+
+// checkArgName names what an automated check of kind needs.
+func checkArgName(kind task.CheckKind) string {
+	if kind == task.CheckRun {
+		return "command"
+	}
+	return "path"
+}
+
+// checkBudgetValue returns what is wrong with a budget value, or "".
+func checkBudgetValue(key, value string) string {
+	switch key {
+	case "cost":
+		cost, err := task.ParseCost(value)
+		if err != nil {
+			return err.Error()
+		}
+		if cost <= 0 {
+			return "cost must be positive"
+		}
+	case "duration":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Sprintf("invalid duration %q (e.g. 90m, 2h)", value)
+		}
+		if d <= 0 {
+			return "duration must be positive"
+		}
+	case "tokens":
+		n, err := task.ParseTokens(value)
+		if err != nil {
+			return err.Error()
+		}
+		if n <= 0 {
+			return "tokens must be positive"
+		}
+	}
+	return ""
+}
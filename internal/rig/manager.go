@@ -1020,6 +1020,29 @@ func (m *Manager) createPatrolHooks(workspacePath string, runtimeConfig *config.
           }
         ]
       }
+    ],
+    "PreToolUse": [
+      {
+        "matcher": "",
+        "hooks": [
+          {
+            "type": "command",
+            "command": "gt pause-gate",
+            "timeout": 86400
+          }
+        ]
+      }
+    ],
+    "PostToolUse": [
+      {
+        "matcher": "",
+        "hooks": [
+          {
+            "type": "command",
+            "command": "gt beacon heartbeat"
+          }
+        ]
+      }
     ]
   }
 }
@@ -2,9 +2,11 @@
 package session
 
 import (
-	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -31,12 +33,16 @@ type StartupNudgeConfig struct {
 // The message becomes the session title in Claude Code's /resume picker,
 // enabling workers to find predecessor sessions.
 //
-// Format: [GAS TOWN] <recipient> <- <sender> • <timestamp> • <topic[:mol-id]>
+// Format (beacon v2): [GAS TOWN] role=<recipient> from=<sender> [rig=<rig>] agent=<name> topic=<topic> [bead=<mol-id>] ts=<timestamp>
 //
 // Examples:
-//   - [GAS TOWN] gastown/crew/gus <- deacon • 2025-12-30T15:42 • assigned:gt-abc12
-//   - [GAS TOWN] deacon <- mayor • 2025-12-30T08:00 • cold-start
-//   - [GAS TOWN] gastown/witness <- self • 2025-12-30T14:00 • handoff
+//   - [GAS TOWN] role=gastown/crew/gus from=deacon rig=gastown agent=gus topic=assigned bead=gt-abc12 ts=2025-12-30T15:42:00-08:00
+//   - [GAS TOWN] role=deacon from=mayor agent=deacon topic=cold-start ts=2025-12-30T08:00:00-08:00
+//   - [GAS TOWN] role=gastown/witness from=self rig=gastown agent=witness topic=handoff ts=2025-12-30T14:00:00-08:00
+//
+// Values containing spaces are quoted. Session discovery also still reads
+// the v1 format, "[GAS TOWN] <recipient> <- <sender> • <timestamp> • <topic[:mol-id]>",
+// that older sessions carry.
 //
 // The message content doesn't trigger GUPP - CLAUDE.md and hooks handle that.
 // The metadata makes sessions identifiable in /resume.
@@ -48,22 +54,22 @@ func StartupNudge(t *tmux.Tmux, session string, cfg StartupNudgeConfig) error {
 // FormatStartupNudge builds the formatted startup nudge message.
 // Separated from StartupNudge for testing and reuse.
 func FormatStartupNudge(cfg StartupNudgeConfig) string {
-	// Use local time in compact format
-	timestamp := time.Now().Format("2006-01-02T15:04")
-
-	// Build topic string - append mol-id if provided
 	topic := cfg.Topic
-	if cfg.MolID != "" && cfg.Topic != "" {
-		topic = fmt.Sprintf("%s:%s", cfg.Topic, cfg.MolID)
-	} else if cfg.MolID != "" {
-		topic = cfg.MolID
-	} else if topic == "" {
+	if topic == "" && cfg.MolID == "" {
 		topic = "ready"
 	}
+	rig, agent := claude.BeaconRoleParts(cfg.Recipient)
 
-	// Build the beacon: [GAS TOWN] recipient <- sender • timestamp • topic
-	beacon := fmt.Sprintf("[GAS TOWN] %s <- %s • %s • %s",
-		cfg.Recipient, cfg.Sender, timestamp, topic)
+	// Build the beacon: [GAS TOWN] role=... from=... topic=... ts=...
+	pairs := []string{"role", cfg.Recipient, "from", cfg.Sender, "rig", rig, "agent", agent,
+		"topic", topic, "bead", cfg.MolID, "ts", time.Now().Format(time.RFC3339)}
+	fields := []string{"[GAS TOWN]"}
+	for i := 0; i < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			fields = append(fields, pairs[i]+"="+beaconValue(pairs[i+1]))
+		}
+	}
+	beacon := strings.Join(fields, " ")
 
 	// For handoff, add explicit instructions so the agent knows what to do
 	// even if hooks haven't loaded CLAUDE.md yet
@@ -83,3 +89,12 @@ func FormatStartupNudge(cfg StartupNudgeConfig) string {
 
 	return beacon
 }
+
+// beaconValue quotes a beacon value when it would otherwise be read as
+// more than one token.
+func beaconValue(v string) string {
+	if strings.ContainsAny(v, " \t\"=") {
+		return strconv.Quote(v)
+	}
+	return v
+}
@@ -22,9 +22,12 @@ func TestFormatStartupNudge(t *testing.T) {
 			},
 			wantSub: []string{
 				"[GAS TOWN]",
-				"gastown/crew/gus",
-				"<- deacon",
-				"assigned:gt-abc12",
+				"role=gastown/crew/gus",
+				"from=deacon",
+				"rig=gastown",
+				"agent=gus",
+				"topic=assigned",
+				"bead=gt-abc12",
 				"Work is on your hook", // assigned includes actionable instructions
 				"gt hook",
 			},
@@ -38,11 +41,11 @@ func TestFormatStartupNudge(t *testing.T) {
 			},
 			wantSub: []string{
 				"[GAS TOWN]",
-				"deacon",
-				"<- mayor",
-				"cold-start",
+				"role=deacon",
+				"from=mayor",
+				"topic=cold-start",
 			},
-			// No wantNot - timestamp contains ":"
+			wantNot: []string{"rig="}, // town-level agents have no rig
 		},
 		{
 			name: "handoff self",
@@ -53,9 +56,9 @@ func TestFormatStartupNudge(t *testing.T) {
 			},
 			wantSub: []string{
 				"[GAS TOWN]",
-				"gastown/witness",
-				"<- self",
-				"handoff",
+				"role=gastown/witness",
+				"from=self",
+				"topic=handoff",
 				"Check your hook and mail", // handoff includes explicit instructions
 				"gt hook",
 				"gt mail inbox",
@@ -70,9 +73,10 @@ func TestFormatStartupNudge(t *testing.T) {
 			},
 			wantSub: []string{
 				"[GAS TOWN]",
-				"gastown/polecats/Toast",
-				"<- witness",
-				"gt-xyz99",
+				"role=gastown/polecats/Toast",
+				"from=witness",
+				"agent=Toast",
+				"bead=gt-xyz99",
 			},
 		},
 		{
@@ -83,7 +87,7 @@ func TestFormatStartupNudge(t *testing.T) {
 			},
 			wantSub: []string{
 				"[GAS TOWN]",
-				"ready",
+				"topic=ready",
 			},
 		},
 	}
@@ -106,3 +110,10 @@ func TestFormatStartupNudge(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatStartupNudgeQuotesValues(t *testing.T) {
+	got := FormatStartupNudge(StartupNudgeConfig{Recipient: "gastown/crew/gus", Sender: "gt canary", Topic: "canary"})
+	if !strings.Contains(got, `from="gt canary"`) {
+		t.Errorf("FormatStartupNudge() = %q, want the sender quoted", got)
+	}
+}
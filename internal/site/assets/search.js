@@ -0,0 +1,25 @@
+// Client-side session search over window.GT_INDEX (assets/index.js).
+(function () {
+    var input = document.getElementById("search");
+    var count = document.getElementById("search-count");
+    var rows = document.querySelectorAll("#sessions tbody tr[data-url]");
+    var byURL = {};
+    (window.GT_INDEX || []).forEach(function (e) {
+        byURL[e.url] = (e.title + " " + e.role + " " + e.text).toLowerCase();
+    });
+
+    function filter() {
+        var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+        var shown = 0;
+        rows.forEach(function (row) {
+            var text = byURL[row.dataset.url] || "";
+            var match = terms.every(function (t) { return text.indexOf(t) >= 0; });
+            row.style.display = match ? "" : "none";
+            if (match) shown++;
+        });
+        count.textContent = terms.length ? shown + " of " + rows.length + " sessions" : "";
+    }
+
+    input.addEventListener("input", filter);
+    filter();
+})();
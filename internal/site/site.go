@@ -0,0 +1,301 @@
+// Package site renders a read-only static HTML report of town activity:
+// a searchable session list, one page per transcript, and summary reports
+// with graphs. The output needs no server; open index.html in a browser or
+// publish the directory anywhere that serves static files.
+package site
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/outcome"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+//go:embed assets/*
+var assetFS embed.FS
+
+// searchTextLen caps how much transcript text each session contributes to
+// the client-side search index, keeping the index small enough to load
+// up front.
+const searchTextLen = 4000
+
+// Data is everything the site shows.
+type Data struct {
+	Town      string
+	Generated time.Time
+	Since     string // window label, e.g. "30d"
+	Sessions  []Session
+	Outcomes  []outcome.Stats
+	Hotspots  []Hotspot
+}
+
+// Session is one session and its transcript.
+type Session struct {
+	ID       string
+	Role     string
+	Topic    string
+	Summary  string
+	Start    time.Time
+	End      time.Time
+	Tokens   int64
+	Cost     float64
+	Outcome  string // success, failure, partial, or empty
+	Takeover string // see takeover.Describe
+	Turns    []claude.Turn
+}
+
+// Hotspot is a frequently edited file.
+type Hotspot struct {
+	Path     string
+	Edits    int
+	Sessions int
+}
+
+// Day is one bar of the activity graphs.
+type Day struct {
+	Date      time.Time
+	Sessions  int
+	Cost      float64
+	SessionsH int // bar height, percent of the busiest day
+	CostH     int
+}
+
+// Title returns the session's display title.
+func (s Session) Title() string {
+	switch {
+	case s.Summary != "":
+		return s.Summary
+	case s.Topic != "":
+		return s.Topic
+	default:
+		return "session " + claude.SessionInfo{ID: s.ID}.ShortID()
+	}
+}
+
+// ShortID returns the session's short ID.
+func (s Session) ShortID() string {
+	return claude.SessionInfo{ID: s.ID}.ShortID()
+}
+
+// Page returns the session page's path relative to the site root.
+func (s Session) Page() string {
+	return "sessions/" + filepath.Base(s.ID) + ".html"
+}
+
+// page is what each template renders.
+type page struct {
+	*Data
+	Title   string
+	Root    string // relative path back to the site root
+	Session *Session
+	Days    []Day
+}
+
+// Build writes the site into dir, creating it if needed. Transcript text
+// is passed through the "secrets" redaction rules first. It returns the
+// number of files written.
+func Build(dir string, data *Data) (int, error) {
+	rules, err := claude.RedactRules("secrets")
+	if err != nil {
+		return 0, err
+	}
+	redactData(data, rules)
+	sort.SliceStable(data.Sessions, func(i, j int) bool {
+		return data.Sessions[i].Start.After(data.Sessions[j].Start)
+	})
+
+	if err := os.MkdirAll(filepath.Join(dir, "sessions"), 0755); err != nil {
+		return 0, fmt.Errorf("creating output directory: %w", err)
+	}
+	written := 0
+
+	// Static assets
+	assets, err := fs.Sub(assetFS, "assets")
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "assets"), 0755); err != nil {
+		return 0, fmt.Errorf("creating output directory: %w", err)
+	}
+	entries, err := fs.ReadDir(assets, ".")
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range entries {
+		content, err := fs.ReadFile(assets, e.Name())
+		if err != nil {
+			return written, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, "assets", e.Name()), content, 0644); err != nil { //nolint:gosec // G306: published report
+			return written, err
+		}
+		written++
+	}
+
+	// Search index, loaded as a script so search works from file:// URLs
+	index, err := searchIndex(data.Sessions)
+	if err != nil {
+		return written, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "assets", "index.js"), index, 0644); err != nil { //nolint:gosec // G306: published report
+		return written, err
+	}
+	written++
+
+	render := func(name, out string, p page) error {
+		tmpl, err := template.New("").Funcs(funcMap).ParseFS(templateFS, "templates/layout.html", "templates/"+name)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(filepath.Join(dir, out)) //nolint:gosec // G304: output path chosen by the user
+		if err != nil {
+			return err
+		}
+		if err := tmpl.ExecuteTemplate(f, name, p); err != nil {
+			f.Close()
+			return fmt.Errorf("rendering %s: %w", out, err)
+		}
+		written++
+		return f.Close()
+	}
+
+	if err := render("index.html", "index.html", page{Data: data, Title: "Sessions", Root: "."}); err != nil {
+		return written, err
+	}
+	if err := render("reports.html", "reports.html", page{Data: data, Title: "Reports", Root: ".", Days: days(data.Sessions)}); err != nil {
+		return written, err
+	}
+	for i := range data.Sessions {
+		s := &data.Sessions[i]
+		if err := render("session.html", s.Page(), page{Data: data, Title: s.Title(), Root: "..", Session: s}); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// funcMap holds the template helpers.
+var funcMap = template.FuncMap{
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Local().Format("2006-01-02 15:04")
+	},
+	"duration": func(s Session) string {
+		if s.Start.IsZero() || s.End.Before(s.Start) {
+			return "-"
+		}
+		return s.End.Sub(s.Start).Round(time.Minute).String()
+	},
+	"cost":    func(c float64) string { return fmt.Sprintf("$%.2f", c) },
+	"percent": func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+	"join":    strings.Join,
+}
+
+// searchEntry is one session in the client-side search index.
+type searchEntry struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Role  string `json:"role"`
+	Text  string `json:"text"`
+}
+
+// searchIndex renders the search index script.
+func searchIndex(sessions []Session) ([]byte, error) {
+	entries := make([]searchEntry, 0, len(sessions))
+	for _, s := range sessions {
+		var text strings.Builder
+		text.WriteString(s.Topic)
+		for _, t := range s.Turns {
+			if text.Len() >= searchTextLen {
+				break
+			}
+			text.WriteString(" ")
+			text.WriteString(t.Text)
+		}
+		body := text.String()
+		if len(body) > searchTextLen {
+			body = strings.ToValidUTF8(body[:searchTextLen], "")
+		}
+		entries = append(entries, searchEntry{
+			URL:   s.Page(),
+			Title: s.Title(),
+			Role:  s.Role,
+			Text:  strings.ToLower(body),
+		})
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+	return []byte("window.GT_INDEX = " + string(data) + ";\n"), nil
+}
+
+// days buckets sessions by local start date, oldest first, for the graphs.
+func days(sessions []Session) []Day {
+	byDate := make(map[string]*Day)
+	for _, s := range sessions {
+		if s.Start.IsZero() {
+			continue
+		}
+		t := s.Start.Local()
+		date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		key := date.Format("2006-01-02")
+		d := byDate[key]
+		if d == nil {
+			d = &Day{Date: date}
+			byDate[key] = d
+		}
+		d.Sessions++
+		d.Cost += s.Cost
+	}
+
+	var out []Day
+	maxSessions, maxCost := 0, 0.0
+	for _, d := range byDate {
+		out = append(out, *d)
+		maxSessions = max(maxSessions, d.Sessions)
+		maxCost = max(maxCost, d.Cost)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	for i := range out {
+		if maxSessions > 0 {
+			out[i].SessionsH = out[i].Sessions * 100 / maxSessions
+		}
+		if maxCost > 0 {
+			out[i].CostH = int(out[i].Cost * 100 / maxCost)
+		}
+	}
+	return out
+}
+
+// redactData replaces secrets in every transcript-derived string.
+func redactData(data *Data, rules []claude.RedactRule) {
+	redact := func(s string) string {
+		for _, r := range rules {
+			s = r.Pattern.ReplaceAllString(s, "[REDACTED:"+r.Name+"]")
+		}
+		return s
+	}
+	for i := range data.Sessions {
+		s := &data.Sessions[i]
+		s.Summary = redact(s.Summary)
+		s.Topic = redact(s.Topic)
+		for j := range s.Turns {
+			s.Turns[j].Text = redact(s.Turns[j].Text)
+		}
+	}
+}
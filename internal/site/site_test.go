@@ -0,0 +1,77 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/outcome"
+)
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	data := &Data{
+		Town:      "testtown",
+		Generated: start.Add(48 * time.Hour),
+		Since:     "30d",
+		Sessions: []Session{
+			{ID: "aaaa1111-0000", Role: "gastown/polecats/toast", Summary: "Fix <auth> bug", Start: start,
+				End: start.Add(time.Hour), Cost: 1.5, Outcome: "success", Takeover: "human (alice)",
+				Turns: []claude.Turn{
+					{Role: "user", Text: "token is sk-ant-REDACTED"},
+					{Role: "assistant", Text: "Patched the handler", Tools: []string{"Edit"}},
+				}},
+			{ID: "bbbb2222-0000", Topic: "docs", Start: start.Add(24 * time.Hour)},
+		},
+		Outcomes: []outcome.Stats{{Role: "polecat", Total: 1, Success: 1, SuccessRate: 1}},
+		Hotspots: []Hotspot{{Path: "internal/auth.go", Edits: 3, Sessions: 1}},
+	}
+
+	n, err := Build(dir, data)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if n != 7 { // site.css, search.js, index.js, index, reports, 2 sessions
+		t.Errorf("wrote %d files, want 7", n)
+	}
+
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("reading %s: %v", name, err)
+		}
+		return string(b)
+	}
+
+	index := read("index.html")
+	if !strings.Contains(index, "Fix &lt;auth&gt; bug") || !strings.Contains(index, `href="sessions/aaaa1111-0000.html"`) {
+		t.Errorf("index.html missing session row:\n%s", index)
+	}
+	if strings.Index(index, "bbbb2222") > strings.Index(index, "aaaa1111") {
+		t.Error("sessions not listed newest first")
+	}
+
+	page := read("sessions/aaaa1111-0000.html")
+	if strings.Contains(page, "sk-ant-") || !strings.Contains(page, "[REDACTED:anthropic-key]") {
+		t.Errorf("transcript not redacted:\n%s", page)
+	}
+	if !strings.Contains(page, `href="../assets/site.css"`) || !strings.Contains(page, "human (alice)") {
+		t.Errorf("session page incomplete:\n%s", page)
+	}
+
+	search := read("assets/index.js")
+	if !strings.HasPrefix(search, "window.GT_INDEX = [") || !strings.Contains(search, "patched the handler") || strings.Contains(search, "sk-ant-") {
+		t.Errorf("search index = %s", search)
+	}
+
+	reports := read("reports.html")
+	for _, want := range []string{"internal/auth.go", "100%", "height: 100%"} {
+		if !strings.Contains(reports, want) {
+			t.Errorf("reports.html missing %q", want)
+		}
+	}
+}
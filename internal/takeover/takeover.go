@@ -0,0 +1,137 @@
+// Package takeover records hand-overs of a session's work between an agent
+// and a human: a human finishing what an agent started, or an agent
+// picking up a human's work. Such blended sessions are kept out of agent
+// productivity stats, so neither side is credited for the other's fixes.
+//
+// Records are appended to <town>/.runtime/takeovers.jsonl.
+package takeover
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Filename is the takeover log within the town .runtime directory.
+const Filename = "takeovers.jsonl"
+
+// Direction says who continued the work.
+type Direction string
+
+// Takeover directions.
+const (
+	ToHuman Direction = "human" // a human continued an agent's work
+	ToAgent Direction = "agent" // an agent continued a human's work
+)
+
+// Record is a single takeover entry.
+type Record struct {
+	SessionID string    `json:"session_id"`
+	Direction Direction `json:"direction"`
+	By        string    `json:"by,omitempty"`   // who took over
+	Role      string    `json:"role,omitempty"` // session's role type (polecat, crew, ...)
+	Rig       string    `json:"rig,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Cleared withdraws the session's earlier takeover records, e.g. when
+	// one was recorded against the wrong session.
+	Cleared bool `json:"cleared,omitempty"`
+}
+
+// Path returns the takeover log path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", Filename)
+}
+
+// Append adds a record to the town's takeover log.
+func Append(townRoot string, rec Record) error {
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now().UTC()
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G302: takeovers are not secret
+	if err != nil {
+		return fmt.Errorf("opening takeover log: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Load returns each session's takeovers, oldest first, omitting sessions
+// whose takeovers were cleared. A missing log yields no records.
+func Load(townRoot string) (map[string][]Record, error) {
+	f, err := os.Open(Path(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string][]Record{}, nil
+		}
+		return nil, fmt.Errorf("opening takeover log: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.SessionID == "" {
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp.Before(records[j].Timestamp)
+	})
+
+	bySession := make(map[string][]Record)
+	for _, rec := range records {
+		if rec.Cleared {
+			delete(bySession, rec.SessionID)
+			continue
+		}
+		bySession[rec.SessionID] = append(bySession[rec.SessionID], rec)
+	}
+	return bySession, nil
+}
+
+// Blended returns the set of sessions with at least one takeover.
+func Blended(bySession map[string][]Record) map[string]bool {
+	blended := make(map[string]bool, len(bySession))
+	for id := range bySession {
+		blended[id] = true
+	}
+	return blended
+}
+
+// Describe summarizes a session's takeovers, e.g. "human (alice)" or
+// "human (alice) → agent".
+func Describe(records []Record) string {
+	s := ""
+	for i, rec := range records {
+		if i > 0 {
+			s += " → "
+		}
+		s += string(rec.Direction)
+		if rec.By != "" {
+			s += " (" + rec.By + ")"
+		}
+	}
+	return s
+}
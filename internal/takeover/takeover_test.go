@@ -0,0 +1,45 @@
+package takeover
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppendLoad(t *testing.T) {
+	town := t.TempDir()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	recs := []Record{
+		{SessionID: "s2", Direction: ToHuman, By: "overseer", Timestamp: base.Add(3 * time.Minute)},
+		{SessionID: "s1", Direction: ToHuman, By: "overseer", Timestamp: base},
+		{SessionID: "s1", Direction: ToAgent, By: "gastown/polecats/toast", Timestamp: base.Add(time.Minute)},
+		{SessionID: "s3", Direction: ToHuman, Timestamp: base},
+		{SessionID: "s3", Cleared: true, Timestamp: base.Add(time.Minute)},
+	}
+	for _, r := range recs {
+		if err := Append(town, r); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	got, err := Load(town)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(got) != 2 || len(got["s3"]) != 0 {
+		t.Fatalf("sessions = %+v, want s1 and s2 (s3 cleared)", got)
+	}
+	if d := Describe(got["s1"]); d != "human (overseer) → agent (gastown/polecats/toast)" {
+		t.Errorf("Describe(s1) = %q", d)
+	}
+	if blended := Blended(got); !blended["s1"] || !blended["s2"] || blended["s3"] {
+		t.Errorf("Blended = %v", blended)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	got, err := Load(t.TempDir())
+	if err != nil || len(got) != 0 {
+		t.Errorf("Load on empty town = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,153 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// CheckKind says how an acceptance check is decided.
+type CheckKind string
+
+// Check kinds. An acceptance item is automated when it starts with one of
+// the prefixes "run:", "exists:", or "missing:"; anything else is a manual
+// check left to a reviewer.
+const (
+	CheckRun     CheckKind = "run"     // shell command that must exit 0
+	CheckExists  CheckKind = "exists"  // path that must exist
+	CheckMissing CheckKind = "missing" // path that must not exist
+	CheckManual  CheckKind = "manual"  // free text for a reviewer
+)
+
+// DefaultCheckTimeout bounds a single "run:" check.
+const DefaultCheckTimeout = 10 * time.Minute
+
+// maxCheckOutput caps how much failure output a result keeps.
+const maxCheckOutput = 2000
+
+// Check is a parsed acceptance item.
+type Check struct {
+	Kind CheckKind `json:"kind"`
+	Arg  string    `json:"arg,omitempty"` // command or path
+	Text string    `json:"text"`          // the item as written
+}
+
+// CheckResult is the outcome of running a check.
+type CheckResult struct {
+	Check
+	Passed   bool          `json:"passed"`
+	Skipped  bool          `json:"skipped,omitempty"` // manual checks aren't run
+	Detail   string        `json:"detail,omitempty"`  // failure output or reason
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// ParseCheck parses an acceptance item.
+func ParseCheck(item string) Check {
+	c := Check{Kind: CheckManual, Text: item}
+	prefix, arg, ok := strings.Cut(item, ":")
+	if !ok {
+		return c
+	}
+	switch kind := CheckKind(strings.TrimSpace(prefix)); kind {
+	case CheckRun, CheckExists, CheckMissing:
+		c.Kind, c.Arg = kind, strings.TrimSpace(arg)
+	}
+	return c
+}
+
+// Checks returns the spec's acceptance items as checks.
+func (s *Spec) Checks() []Check {
+	checks := make([]Check, 0, len(s.Acceptance))
+	for _, item := range s.Acceptance {
+		checks = append(checks, ParseCheck(item))
+	}
+	return checks
+}
+
+// Automated reports whether the check can be run without a reviewer.
+func (c Check) Automated() bool {
+	return c.Kind != CheckManual
+}
+
+// RunChecks runs each check in dir and returns a result per check, in
+// order. Paths are relative to dir; commands run in dir under the
+// platform shell, each bounded by timeout (DefaultCheckTimeout if zero).
+func RunChecks(ctx context.Context, checks []Check, dir string, timeout time.Duration) []CheckResult {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	results := make([]CheckResult, 0, len(checks))
+	for _, c := range checks {
+		start := time.Now()
+		r := CheckResult{Check: c}
+		switch c.Kind {
+		case CheckRun:
+			r.Passed, r.Detail = runCommand(ctx, c.Arg, dir, timeout)
+		case CheckExists, CheckMissing:
+			path := c.Arg
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			_, err := os.Stat(path)
+			exists := err == nil
+			r.Passed = exists == (c.Kind == CheckExists)
+			if !r.Passed {
+				if exists {
+					r.Detail = c.Arg + " exists"
+				} else {
+					r.Detail = c.Arg + " does not exist"
+				}
+			}
+		default:
+			r.Skipped = true
+			r.Detail = "manual check"
+		}
+		r.Duration = time.Since(start)
+		results = append(results, r)
+	}
+	return results
+}
+
+// runCommand runs a "run:" check, returning whether it exited 0 and, if
+// not, the tail of its output.
+func runCommand(ctx context.Context, command, dir string, timeout time.Duration) (bool, string) {
+	if command == "" {
+		return false, "no command given"
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command) //nolint:gosec // G204: command comes from the task spec
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: command comes from the task spec
+	}
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	if err == nil {
+		return true, ""
+	}
+	detail := strings.TrimSpace(out.String())
+	if len(detail) > maxCheckOutput {
+		detail = "..." + detail[len(detail)-maxCheckOutput:]
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		detail = strings.TrimSpace(fmt.Sprintf("timed out after %s\n%s", timeout, detail))
+	case detail == "":
+		detail = err.Error()
+	}
+	return false, detail
+}
@@ -0,0 +1,63 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParseCheck(t *testing.T) {
+	tests := []struct {
+		item string
+		want Check
+	}{
+		{"run: go test ./...", Check{Kind: CheckRun, Arg: "go test ./...", Text: "run: go test ./..."}},
+		{"exists: docs/retry.md", Check{Kind: CheckExists, Arg: "docs/retry.md", Text: "exists: docs/retry.md"}},
+		{"missing:old.go", Check{Kind: CheckMissing, Arg: "old.go", Text: "missing:old.go"}},
+		{"Reviewer can see retries in the log", Check{Kind: CheckManual, Text: "Reviewer can see retries in the log"}},
+		{"Priority: no lost mail", Check{Kind: CheckManual, Text: "Priority: no lost mail"}},
+	}
+	for _, tt := range tests {
+		if got := ParseCheck(tt.item); got != tt.want {
+			t.Errorf("ParseCheck(%q) = %+v, want %+v", tt.item, got, tt.want)
+		}
+	}
+}
+
+func TestRunChecks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh commands")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "done.txt"), []byte("ok"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := &Spec{Goal: "g", Acceptance: []string{
+		"exists: done.txt",
+		"missing: done.txt",
+		"run: test -f done.txt",
+		"run: echo broken >&2; exit 3",
+		"Looks right to a reviewer",
+	}}
+	results := RunChecks(context.Background(), spec.Checks(), dir, 0)
+	if len(results) != 5 {
+		t.Fatalf("got %d results, want 5", len(results))
+	}
+
+	want := []struct{ passed, skipped bool }{{true, false}, {false, false}, {true, false}, {false, false}, {false, true}}
+	for i, w := range want {
+		if results[i].Passed != w.passed || results[i].Skipped != w.skipped {
+			t.Errorf("result %d (%s) = passed %v skipped %v, want %v %v",
+				i, results[i].Text, results[i].Passed, results[i].Skipped, w.passed, w.skipped)
+		}
+	}
+	if results[3].Detail != "broken" {
+		t.Errorf("failed command detail = %q, want its output", results[3].Detail)
+	}
+	if results[1].Detail != "done.txt exists" {
+		t.Errorf("missing check detail = %q", results[1].Detail)
+	}
+}
@@ -0,0 +1,309 @@
+// Package task defines task specs: the structured description of a unit
+// of work (goal, constraints, acceptance checks, file hints, and budget)
+// that gt sling, convoys, and mayor plans hand to agents in place of a
+// free-text prompt.
+package task
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/templates"
+)
+
+// Spec describes a unit of work.
+type Spec struct {
+	// Goal is what the work must achieve. Required.
+	Goal string `json:"goal"`
+
+	// Constraints are rules the work must follow ("don't change the
+	// mail file format").
+	Constraints []string `json:"constraints,omitempty"`
+
+	// Acceptance lists the checks that decide whether the work is done,
+	// e.g. a command that must pass or a behavior a reviewer can see.
+	Acceptance []string `json:"acceptance,omitempty"`
+
+	// FilesHint points at files or directories likely to need changes.
+	FilesHint []string `json:"files_hint,omitempty"`
+
+	// Budget caps what the work may spend.
+	Budget Budget `json:"budget"`
+}
+
+// Budget caps what a task may spend. Zero fields are uncapped.
+type Budget struct {
+	Cost     float64       `json:"cost,omitempty"`     // US dollars
+	Duration time.Duration `json:"duration,omitempty"` // wall-clock time
+	Tokens   int64         `json:"tokens,omitempty"`   // total tokens
+}
+
+// Fields a spec accepts, in the order they are documented.
+var (
+	Fields       = []string{"goal", "constraints", "acceptance", "files_hint", "budget"}
+	ListFields   = []string{"constraints", "acceptance", "files_hint"}
+	BudgetFields = []string{"cost", "duration", "tokens"}
+)
+
+// Load reads and validates a task spec.
+//
+// Specs are YAML, in a subset: scalar fields, lists of scalars, and the
+// budget map. The goal may be a "|" or ">" block.
+//
+//	goal: Retry mail delivery when the recipient's inbox is locked
+//	constraints:
+//	  - Don't change the mailbox file format
+//	acceptance:
+//	  - go test ./internal/mail/... passes
+//	files_hint:
+//	  - internal/mail/
+//	budget:
+//	  cost: 5.00
+//	  duration: 2h
+//	  tokens: 2M
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: user-specified task file
+	if err != nil {
+		return nil, fmt.Errorf("reading task: %w", err)
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing task %s: %w", path, err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("task %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// Parse parses a task spec in the YAML subset described on Load. It
+// doesn't validate the result; see Validate.
+func Parse(data []byte) (*Spec, error) {
+	spec := &Spec{}
+	section := ""      // list or map field whose items follow
+	var block []string // lines of a goal block scalar
+	folded, inBlock := false, false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		raw := strings.TrimRight(scanner.Text(), "\r")
+		line := strings.TrimSpace(raw)
+		indented := raw != "" && (raw[0] == ' ' || raw[0] == '\t')
+
+		if inBlock {
+			if indented || line == "" {
+				block = append(block, line)
+				continue
+			}
+			spec.Goal = joinBlock(block, folded)
+			inBlock = false
+		}
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+
+		if !indented {
+			key, value, ok := SplitField(line)
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key: value", lineNum)
+			}
+			section = ""
+			switch key {
+			case "goal":
+				if value == "|" || value == ">" {
+					inBlock, folded, block = true, value == ">", nil
+				} else {
+					spec.Goal = value
+				}
+			case "constraints", "acceptance", "files_hint", "budget":
+				if value != "" {
+					return nil, fmt.Errorf("line %d: %s takes indented items, not a value", lineNum, key)
+				}
+				section = key
+			default:
+				return nil, fmt.Errorf("line %d: unknown field %q", lineNum, key)
+			}
+			continue
+		}
+
+		switch section {
+		case "":
+			return nil, fmt.Errorf("line %d: unexpected indentation", lineNum)
+		case "budget":
+			key, value, ok := SplitField(line)
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key: value", lineNum)
+			}
+			if err := spec.Budget.set(key, value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+		default:
+			item, ok := strings.CutPrefix(line, "-")
+			if !ok {
+				return nil, fmt.Errorf("line %d: %s items must start with '-'", lineNum, section)
+			}
+			item = scalar(item)
+			switch section {
+			case "constraints":
+				spec.Constraints = append(spec.Constraints, item)
+			case "acceptance":
+				spec.Acceptance = append(spec.Acceptance, item)
+			case "files_hint":
+				spec.FilesHint = append(spec.FilesHint, item)
+			}
+		}
+	}
+	if inBlock {
+		spec.Goal = joinBlock(block, folded)
+	}
+	return spec, scanner.Err()
+}
+
+// Validate reports the first problem that makes the spec unusable.
+func (s *Spec) Validate() error {
+	if strings.TrimSpace(s.Goal) == "" {
+		return fmt.Errorf("goal is required")
+	}
+	for _, list := range [][]string{s.Constraints, s.Acceptance, s.FilesHint} {
+		for _, item := range list {
+			if strings.TrimSpace(item) == "" {
+				return fmt.Errorf("list items must not be empty")
+			}
+		}
+	}
+	if s.Budget.Cost < 0 || s.Budget.Duration < 0 || s.Budget.Tokens < 0 {
+		return fmt.Errorf("budget must not be negative")
+	}
+	return nil
+}
+
+// Headline returns the goal's first line, for one-line listings.
+func (s *Spec) Headline() string {
+	headline, _, _ := strings.Cut(strings.TrimSpace(s.Goal), "\n")
+	return headline
+}
+
+// Render renders the spec as the prompt agents are given, using the
+// template library's task message.
+func (s *Spec) Render() (string, error) {
+	tmpl, err := templates.New()
+	if err != nil {
+		return "", err
+	}
+	return tmpl.RenderMessage("task", s)
+}
+
+// set sets a budget field from its YAML value.
+func (b *Budget) set(key, value string) error {
+	var err error
+	switch key {
+	case "cost":
+		b.Cost, err = ParseCost(value)
+	case "duration":
+		b.Duration, err = time.ParseDuration(value)
+	case "tokens":
+		b.Tokens, err = ParseTokens(value)
+	default:
+		return fmt.Errorf("unknown budget field %q", key)
+	}
+	if err != nil {
+		return fmt.Errorf("budget %s: %w", key, err)
+	}
+	return nil
+}
+
+// IsZero reports whether the budget caps nothing.
+func (b Budget) IsZero() bool {
+	return b.Cost == 0 && b.Duration == 0 && b.Tokens == 0
+}
+
+// Limits describes each cap, e.g. "$5.00", "2h0m0s", "2000000 tokens".
+func (b Budget) Limits() []string {
+	var limits []string
+	if b.Cost > 0 {
+		limits = append(limits, fmt.Sprintf("$%.2f", b.Cost))
+	}
+	if b.Duration > 0 {
+		limits = append(limits, b.Duration.String())
+	}
+	if b.Tokens > 0 {
+		limits = append(limits, fmt.Sprintf("%d tokens", b.Tokens))
+	}
+	return limits
+}
+
+// ParseCost parses a dollar amount such as "5", "5.00", or "$5".
+func ParseCost(s string) (float64, error) {
+	cost, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(s), "$"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cost %q", s)
+	}
+	return cost, nil
+}
+
+// ParseTokens parses a token count such as "200000", "500k", or "2M".
+func ParseTokens(s string) (int64, error) {
+	num := strings.TrimSpace(s)
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(num, "k"), strings.HasSuffix(num, "K"):
+		mult, num = 1e3, num[:len(num)-1]
+	case strings.HasSuffix(num, "m"), strings.HasSuffix(num, "M"):
+		mult, num = 1e6, num[:len(num)-1]
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token count %q", s)
+	}
+	return int64(n * mult), nil
+}
+
+// SplitField splits "key: value", unquoting the value and dropping a
+// trailing comment.
+func SplitField(line string) (key, value string, ok bool) {
+	key, value, ok = strings.Cut(line, ":")
+	if !ok {
+		return "", "", false
+	}
+	return strings.TrimSpace(key), scalar(value), true
+}
+
+// scalar returns a YAML scalar's value: unquoted if quoted, else with any
+// trailing comment dropped.
+func scalar(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+// joinBlock joins the lines of a block scalar: kept as lines for "|",
+// folded into paragraphs for ">".
+func joinBlock(lines []string, folded bool) string {
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if !folded {
+		return strings.Join(lines, "\n")
+	}
+	var out strings.Builder
+	for i, line := range lines {
+		switch {
+		case line == "":
+			out.WriteString("\n")
+		case i > 0 && lines[i-1] != "":
+			out.WriteString(" ")
+		}
+		out.WriteString(line)
+	}
+	return out.String()
+}
@@ -0,0 +1,139 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleSpec = `# Mail retries
+goal: >
+  Retry mail delivery when the
+  recipient's inbox is locked.
+
+  Keep the first attempt fast.
+constraints:
+  - Don't change the mailbox file format
+  - "Keep #1 priority: no lost mail"
+acceptance:
+  - go test ./internal/mail/... passes
+files_hint:
+  - internal/mail/   # delivery lives here
+budget:
+  cost: $5
+  duration: 2h
+  tokens: 1.5M
+`
+
+func TestParse(t *testing.T) {
+	spec, err := Parse([]byte(sampleSpec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if want := "Retry mail delivery when the recipient's inbox is locked.\nKeep the first attempt fast."; spec.Goal != want {
+		t.Errorf("Goal = %q, want %q", spec.Goal, want)
+	}
+	if want := []string{"Don't change the mailbox file format", "Keep #1 priority: no lost mail"}; !reflect.DeepEqual(spec.Constraints, want) {
+		t.Errorf("Constraints = %q", spec.Constraints)
+	}
+	if len(spec.Acceptance) != 1 || !reflect.DeepEqual(spec.FilesHint, []string{"internal/mail/"}) {
+		t.Errorf("Acceptance = %q, FilesHint = %q", spec.Acceptance, spec.FilesHint)
+	}
+	if want := (Budget{Cost: 5, Duration: 2 * time.Hour, Tokens: 1_500_000}); spec.Budget != want {
+		t.Errorf("Budget = %+v, want %+v", spec.Budget, want)
+	}
+	if err := spec.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+	if got := spec.Headline(); got != "Retry mail delivery when the recipient's inbox is locked." {
+		t.Errorf("Headline = %q", got)
+	}
+}
+
+func TestParseLiteralGoal(t *testing.T) {
+	spec, err := Parse([]byte("goal: |\n  line one\n  line two\nacceptance:\n  - done\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if spec.Goal != "line one\nline two" || len(spec.Acceptance) != 1 {
+		t.Errorf("spec = %+v", spec)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown field":     "goal: x\nowner: me\n",
+		"list with value":   "goal: x\nacceptance: tests pass\n",
+		"item without dash": "goal: x\nacceptance:\n  tests pass\n",
+		"bad budget":        "goal: x\nbudget:\n  cost: lots\n",
+		"unknown budget":    "goal: x\nbudget:\n  dollars: 5\n",
+		"stray indent":      "  goal: x\n",
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Spec{}).Validate(); err == nil || !strings.Contains(err.Error(), "goal") {
+		t.Errorf("empty spec: %v", err)
+	}
+	if err := (&Spec{Goal: "x", Acceptance: []string{" "}}).Validate(); err == nil {
+		t.Error("blank acceptance item: want error")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "task.yaml")
+	if err := os.WriteFile(path, []byte("constraints:\n  - none\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "goal is required") {
+		t.Errorf("Load without goal: %v", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	spec, err := Parse([]byte(sampleSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := spec.Render()
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	for _, want := range []string{
+		"## Goal", "Keep the first attempt fast.",
+		"## Constraints", "- Don't change the mailbox file format",
+		"- [ ] go test ./internal/mail/... passes",
+		"- `internal/mail/`",
+		"Stay within $5.00, 2h0m0s, 1500000 tokens.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered task missing %q:\n%s", want, out)
+		}
+	}
+
+	bare, err := (&Spec{Goal: "Just do it"}).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(bare, "## Budget") || strings.Contains(bare, "## Constraints") {
+		t.Errorf("empty sections rendered:\n%s", bare)
+	}
+}
+
+func TestParseTokens(t *testing.T) {
+	for in, want := range map[string]int64{"200000": 200_000, "500k": 500_000, "2M": 2_000_000} {
+		if got, err := ParseTokens(in); err != nil || got != want {
+			t.Errorf("ParseTokens(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	if _, err := ParseTokens("lots"); err == nil {
+		t.Error("ParseTokens(lots): want error")
+	}
+}
@@ -3,7 +3,9 @@ package templates
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -133,7 +135,7 @@ func (t *Templates) RoleNames() []string {
 
 // MessageNames returns the list of available message templates.
 func (t *Templates) MessageNames() []string {
-	return []string{"spawn", "nudge", "escalation", "handoff"}
+	return []string{"spawn", "nudge", "escalation", "handoff", "task"}
 }
 
 // CreateMayorCLAUDEmd creates the Mayor's CLAUDE.md file at the specified directory.
@@ -184,6 +186,18 @@ func GetAllRoleTemplates() (map[string][]byte, error) {
 	return result, nil
 }
 
+// RoleVersion returns a short content hash of a role template, used to
+// attribute session outcomes to the prompt that produced them.
+// Returns "" for unknown roles.
+func RoleVersion(role string) string {
+	content, err := templateFS.ReadFile("roles/" + role + ".md.tmpl")
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])[:8]
+}
+
 // ProvisionCommands creates the .claude/commands/ directory with standard slash commands.
 // This ensures crew/polecat workspaces have the handoff command and other utilities
 // even if the source repo doesn't have them tracked.
@@ -295,3 +295,16 @@ func TestGetAllRoleTemplates_ContentValidity(t *testing.T) {
 		}
 	}
 }
+
+func TestRoleVersion(t *testing.T) {
+	v := RoleVersion("polecat")
+	if len(v) != 8 {
+		t.Errorf("RoleVersion(polecat) = %q, want 8-char hash", v)
+	}
+	if RoleVersion("polecat") != v {
+		t.Error("RoleVersion should be stable")
+	}
+	if RoleVersion("nonexistent") != "" {
+		t.Error("RoleVersion of unknown role should be empty")
+	}
+}
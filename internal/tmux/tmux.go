@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/actions"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 )
@@ -83,7 +84,9 @@ func (t *Tmux) NewSession(name, workDir string) error {
 // or the command arrives before the shell prompt. The command runs directly as the
 // initial process of the pane.
 // See: https://github.com/anthropics/gastown/issues/280
+// The launch is recorded in the action log.
 func (t *Tmux) NewSessionWithCommand(name, workDir, command string) error {
+	done := actions.Track(actions.KindOf(command), actions.OpNewSession, name, workDir, command)
 	args := []string{"new-session", "-d", "-s", name}
 	if workDir != "" {
 		args = append(args, "-c", workDir)
@@ -91,6 +94,7 @@ func (t *Tmux) NewSessionWithCommand(name, workDir, command string) error {
 	// Add the command as the last argument - tmux runs it as the pane's initial process
 	args = append(args, command)
 	_, err := t.run(args...)
+	done(err)
 	return err
 }
 
@@ -130,7 +134,9 @@ func (t *Tmux) EnsureSessionFresh(name, workDir string) error {
 
 // KillSession terminates a tmux session.
 func (t *Tmux) KillSession(name string) error {
+	done := actions.Track(actions.KindKill, actions.OpKillSession, name, "", "")
 	_, err := t.run("kill-session", "-t", name)
+	done(err)
 	return err
 }
 
@@ -962,7 +968,9 @@ func (t *Tmux) SetMailClickBinding(session string) error {
 // This is used for "hot reload" of agent sessions - instantly restart in place.
 // The pane parameter should be a pane ID (e.g., "%0") or session:window.pane format.
 func (t *Tmux) RespawnPane(pane, command string) error {
+	done := actions.Track(actions.KindOf(command), actions.OpRespawnPane, pane, "", command)
 	_, err := t.run("respawn-pane", "-k", "-t", pane, command)
+	done(err)
 	return err
 }
 
@@ -0,0 +1,171 @@
+// Package trends aggregates agent session history into weekly trend lines,
+// to show whether prompt and tooling changes improve a role over time.
+package trends
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/outcome"
+)
+
+// Metric is a per-week measure of a role's sessions.
+type Metric string
+
+const (
+	// MetricCost is the total estimated cost of the week's sessions.
+	MetricCost Metric = "cost"
+	// MetricDuration is the median session duration, in minutes.
+	MetricDuration Metric = "duration"
+	// MetricSuccess is the share of sessions with a recorded outcome that
+	// succeeded.
+	MetricSuccess Metric = "success"
+)
+
+// ParseMetric validates a metric name.
+func ParseMetric(s string) (Metric, error) {
+	switch m := Metric(strings.ToLower(strings.TrimSpace(s))); m {
+	case MetricCost, MetricDuration, MetricSuccess:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid metric %q (want cost, duration, or success)", s)
+}
+
+// Sample is one session's contribution to a trend.
+type Sample struct {
+	Start    time.Time
+	Role     string // role type (crew, polecat, ...)
+	Rig      string
+	Cost     float64
+	Duration time.Duration
+	Outcome  outcome.Status // empty when no outcome was recorded
+}
+
+// Week is one point on a trend line.
+type Week struct {
+	Start    time.Time `json:"start"`
+	Sessions int       `json:"sessions"`
+	Value    float64   `json:"value"`
+	HasValue bool      `json:"has_value"` // false when the metric is undefined (no sessions or outcomes)
+}
+
+// WeekStart returns midnight UTC on the Monday of t's week.
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7 // Monday = 0
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// Weekly buckets samples into the weeks weeks ending with now's week,
+// oldest first, and computes metric for each.
+func Weekly(samples []Sample, metric Metric, weeks int, now time.Time) []Week {
+	if weeks <= 0 {
+		return nil
+	}
+	first := WeekStart(now).AddDate(0, 0, -7*(weeks-1))
+	buckets := make([][]Sample, weeks)
+	for _, s := range samples {
+		if s.Start.Before(first) {
+			continue
+		}
+		i := int(WeekStart(s.Start).Sub(first).Hours() / (24 * 7))
+		if i >= 0 && i < weeks {
+			buckets[i] = append(buckets[i], s)
+		}
+	}
+
+	result := make([]Week, weeks)
+	for i, bucket := range buckets {
+		w := Week{Start: first.AddDate(0, 0, 7*i), Sessions: len(bucket)}
+		w.Value, w.HasValue = measure(bucket, metric)
+		result[i] = w
+	}
+	return result
+}
+
+// measure computes metric over one week's samples.
+func measure(samples []Sample, metric Metric) (float64, bool) {
+	if len(samples) == 0 {
+		return 0, false
+	}
+	switch metric {
+	case MetricCost:
+		total := 0.0
+		for _, s := range samples {
+			total += s.Cost
+		}
+		return total, true
+
+	case MetricDuration:
+		minutes := make([]float64, 0, len(samples))
+		for _, s := range samples {
+			if s.Duration > 0 {
+				minutes = append(minutes, s.Duration.Minutes())
+			}
+		}
+		if len(minutes) == 0 {
+			return 0, false
+		}
+		sort.Float64s(minutes)
+		mid := len(minutes) / 2
+		if len(minutes)%2 == 0 {
+			return (minutes[mid-1] + minutes[mid]) / 2, true
+		}
+		return minutes[mid], true
+
+	case MetricSuccess:
+		judged, succeeded := 0, 0
+		for _, s := range samples {
+			if s.Outcome == "" {
+				continue
+			}
+			judged++
+			if s.Outcome == outcome.Success {
+				succeeded++
+			}
+		}
+		if judged == 0 {
+			return 0, false
+		}
+		return float64(succeeded) / float64(judged), true
+	}
+	return 0, false
+}
+
+// sparkBars are the glyphs used by Sparkline, lowest to highest.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders weeks as a one-line bar chart scaled between the
+// lowest and highest values. Weeks without a value are shown as spaces.
+func Sparkline(weeks []Week) string {
+	lo, hi := 0.0, 0.0
+	found := false
+	for _, w := range weeks {
+		if !w.HasValue {
+			continue
+		}
+		if !found || w.Value < lo {
+			lo = w.Value
+		}
+		if !found || w.Value > hi {
+			hi = w.Value
+		}
+		found = true
+	}
+
+	var b strings.Builder
+	for _, w := range weeks {
+		switch {
+		case !w.HasValue:
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(sparkBars[len(sparkBars)/2])
+		default:
+			i := int((w.Value - lo) / (hi - lo) * float64(len(sparkBars)-1))
+			b.WriteRune(sparkBars[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package trends
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/outcome"
+)
+
+func TestWeekStart(t *testing.T) {
+	// 2025-01-08 is a Wednesday; its week starts Monday 2025-01-06.
+	got := WeekStart(time.Date(2025, 1, 8, 15, 30, 0, 0, time.UTC))
+	want := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("WeekStart = %v, want %v", got, want)
+	}
+	// Sunday belongs to the week that started the previous Monday.
+	got = WeekStart(time.Date(2025, 1, 12, 23, 0, 0, 0, time.UTC))
+	if !got.Equal(want) {
+		t.Errorf("WeekStart(Sunday) = %v, want %v", got, want)
+	}
+}
+
+func TestWeekly(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC) // week of Jan 13
+	samples := []Sample{
+		{Start: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), Cost: 100}, // outside window
+		{Start: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), Cost: 2, Duration: 10 * time.Minute, Outcome: outcome.Success},
+		{Start: time.Date(2025, 1, 7, 9, 0, 0, 0, time.UTC), Cost: 3, Duration: 30 * time.Minute, Outcome: outcome.Failure},
+		{Start: time.Date(2025, 1, 14, 9, 0, 0, 0, time.UTC), Cost: 1, Duration: 20 * time.Minute},
+	}
+
+	cost := Weekly(samples, MetricCost, 3, now)
+	if len(cost) != 3 {
+		t.Fatalf("got %d weeks, want 3", len(cost))
+	}
+	if cost[0].HasValue || cost[1].Value != 5 || cost[1].Sessions != 2 || cost[2].Value != 1 {
+		t.Errorf("cost weeks = %+v", cost)
+	}
+
+	duration := Weekly(samples, MetricDuration, 3, now)
+	if duration[1].Value != 20 {
+		t.Errorf("median duration = %v, want 20", duration[1].Value)
+	}
+
+	success := Weekly(samples, MetricSuccess, 3, now)
+	if success[1].Value != 0.5 || !success[1].HasValue {
+		t.Errorf("success week 2 = %+v, want 0.5", success[1])
+	}
+	if success[2].HasValue {
+		t.Errorf("week without outcomes should have no value: %+v", success[2])
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	weeks := []Week{
+		{Value: 0, HasValue: true},
+		{HasValue: false},
+		{Value: 7, HasValue: true},
+		{Value: 14, HasValue: true},
+	}
+	if got := Sparkline(weeks); got != "▁ ▄█" {
+		t.Errorf("Sparkline = %q, want %q", got, "▁ ▄█")
+	}
+	if got := Sparkline([]Week{{Value: 3, HasValue: true}, {Value: 3, HasValue: true}}); got != "▅▅" {
+		t.Errorf("flat Sparkline = %q", got)
+	}
+}
+
+func TestParseMetric(t *testing.T) {
+	if m, err := ParseMetric("Cost"); err != nil || m != MetricCost {
+		t.Errorf("ParseMetric(Cost) = %v, %v", m, err)
+	}
+	if _, err := ParseMetric("vibes"); err == nil {
+		t.Error("expected error for unknown metric")
+	}
+}
@@ -0,0 +1,195 @@
+package feed
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Action is a quick action on the agent selected in the tree.
+type Action string
+
+const (
+	ActionTranscript Action = "transcript" // open the agent's latest transcript
+	ActionMail       Action = "mail"       // send the agent mail
+	ActionTag        Action = "tag"        // tag the agent's latest session
+	ActionOutcome    Action = "outcome"    // record how the latest session went
+	ActionKill       Action = "kill"       // stop the agent's session
+	ActionResume     Action = "resume"     // resume the latest session in tmux
+)
+
+// ActionFunc builds the command that performs action on agent. input is
+// the text the dashboard prompted for, if the action takes any. The
+// dashboard suspends while the command runs, so it may be interactive.
+type ActionFunc func(action Action, agent Agent, input string) (*exec.Cmd, error)
+
+// SetActionFunc enables quick actions on the selected agent.
+func (m *Model) SetActionFunc(fn ActionFunc) {
+	m.actionFunc = fn
+}
+
+// actionPrompt is an action waiting for input or confirmation.
+type actionPrompt struct {
+	action  Action
+	agent   Agent
+	label   string
+	input   string
+	confirm bool // a y/N question rather than free text
+}
+
+// actionDoneMsg reports that an action's command finished.
+type actionDoneMsg struct {
+	action Action
+	agent  string
+	err    error
+}
+
+// treeRoleOrder is the order roles are listed in within a rig.
+var treeRoleOrder = []string{"mayor", "witness", "refinery", "deacon", "crew", "polecat"}
+
+// treeAgents returns the agents in the order the tree shows them.
+func (m *Model) treeAgents() []*Agent {
+	rigNames := make([]string, 0, len(m.rigs))
+	for name := range m.rigs {
+		rigNames = append(rigNames, name)
+	}
+	sort.Strings(rigNames)
+
+	var agents []*Agent
+	for _, rigName := range rigNames {
+		byRole := m.groupAgentsByRole(m.rigs[rigName].Agents)
+		for _, role := range treeRoleOrder {
+			agents = append(agents, byRole[role]...)
+		}
+	}
+	return agents
+}
+
+// selectedAgent returns the agent selected in the tree, or nil.
+func (m *Model) selectedAgent() *Agent {
+	for _, a := range m.treeAgents() {
+		if a.ID == m.selected {
+			return a
+		}
+	}
+	return nil
+}
+
+// moveSelection selects the agent delta places from the selected one.
+func (m *Model) moveSelection(delta int) {
+	agents := m.treeAgents()
+	if len(agents) == 0 {
+		return
+	}
+	i := -1
+	for j, a := range agents {
+		if a.ID == m.selected {
+			i = j
+			break
+		}
+	}
+	switch {
+	case i < 0:
+		i = 0
+	default:
+		i = min(max(i+delta, 0), len(agents)-1)
+	}
+	m.selected = agents[i].ID
+	m.updateViewContent()
+
+	// Keep the selection in view
+	if line := m.selectedLine; line < m.treeViewport.YOffset {
+		m.treeViewport.SetYOffset(line)
+	} else if line >= m.treeViewport.YOffset+m.treeViewport.Height {
+		m.treeViewport.SetYOffset(line - m.treeViewport.Height + 1)
+	}
+}
+
+// startAction begins action on the selected agent: it prompts for what
+// the action needs, or runs it straight away.
+func (m *Model) startAction(action Action) tea.Cmd {
+	if m.actionFunc == nil {
+		m.status = "Quick actions are unavailable"
+		return nil
+	}
+	agent := m.selectedAgent()
+	if agent == nil {
+		m.status = "Select an agent in the tree first (1, then j/k)"
+		return nil
+	}
+
+	p := &actionPrompt{action: action, agent: *agent}
+	switch action {
+	case ActionMail:
+		p.label = "Mail to " + agent.ID + ": "
+	case ActionTag:
+		p.label = "Tag " + agent.ID + "'s latest session: "
+	case ActionOutcome:
+		p.label = "Outcome of " + agent.ID + "'s latest session (success/failure/partial): "
+	case ActionKill:
+		p.label = "Kill " + agent.ID + "? (y/N) "
+		p.confirm = true
+	default:
+		return m.runAction(action, *agent, "")
+	}
+	m.prompt = p
+	m.status = ""
+	return nil
+}
+
+// handlePromptKey edits the open prompt, running its action on enter.
+func (m *Model) handlePromptKey(msg tea.KeyMsg) tea.Cmd {
+	p := m.prompt
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.prompt = nil
+		m.status = "Cancelled"
+	case tea.KeyEnter:
+		m.prompt = nil
+		input := strings.TrimSpace(p.input)
+		if p.confirm {
+			if !strings.EqualFold(input, "y") && !strings.EqualFold(input, "yes") {
+				m.status = "Cancelled"
+				return nil
+			}
+			input = ""
+		} else if input == "" {
+			m.status = "Cancelled"
+			return nil
+		}
+		return m.runAction(p.action, p.agent, input)
+	case tea.KeyBackspace:
+		if r := []rune(p.input); len(r) > 0 {
+			p.input = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		p.input += " "
+	case tea.KeyRunes:
+		p.input += string(msg.Runes)
+	}
+	return nil
+}
+
+// runAction suspends the dashboard and runs action's command.
+func (m *Model) runAction(action Action, agent Agent, input string) tea.Cmd {
+	cmd, err := m.actionFunc(action, agent, input)
+	if err != nil {
+		m.status = fmt.Sprintf("%s %s: %v", action, agent.ID, err)
+		return nil
+	}
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return actionDoneMsg{action: action, agent: agent.ID, err: err}
+	})
+}
+
+// finishAction records how an action went in the status bar.
+func (m *Model) finishAction(msg actionDoneMsg) {
+	if msg.err != nil {
+		m.status = fmt.Sprintf("✗ %s %s: %v", msg.action, msg.agent, msg.err)
+		return
+	}
+	m.status = fmt.Sprintf("✓ %s %s", msg.action, msg.agent)
+}
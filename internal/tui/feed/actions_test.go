@@ -0,0 +1,102 @@
+package feed
+
+import (
+	"os/exec"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newActionTestModel() *Model {
+	m := NewModel()
+	m.rigs["gastown"] = &Rig{Name: "gastown", Agents: map[string]*Agent{
+		"polecats/toast": {ID: "gastown/polecats/toast", Name: "toast", Role: "polecat", Rig: "gastown"},
+		"witness":        {ID: "gastown/witness", Name: "witness", Role: "witness", Rig: "gastown"},
+		"crew/max":       {ID: "gastown/crew/max", Name: "max", Role: "crew", Rig: "gastown"},
+	}}
+	return m
+}
+
+func typeKeys(m *Model, s string) {
+	for _, r := range s {
+		m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestMoveSelectionFollowsTree(t *testing.T) {
+	m := newActionTestModel()
+
+	want := []string{"gastown/witness", "gastown/crew/max", "gastown/polecats/toast", "gastown/polecats/toast"}
+	for i, id := range want {
+		m.moveSelection(1)
+		if m.selected != id {
+			t.Fatalf("step %d: selected %q, want %q", i, m.selected, id)
+		}
+	}
+	m.moveSelection(-5)
+	if m.selected != "gastown/witness" {
+		t.Errorf("selected %q after moving past the top, want gastown/witness", m.selected)
+	}
+}
+
+func TestActionPromptPassesInput(t *testing.T) {
+	m := newActionTestModel()
+	var gotAction Action
+	var gotAgent, gotInput string
+	m.SetActionFunc(func(action Action, agent Agent, input string) (*exec.Cmd, error) {
+		gotAction, gotAgent, gotInput = action, agent.ID, input
+		return exec.Command("true"), nil
+	})
+	m.moveSelection(1)
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	if m.prompt == nil {
+		t.Fatal("mail did not prompt for a message")
+	}
+	typeKeys(m, "hi")
+	m.handleKey(tea.KeyMsg{Type: tea.KeySpace})
+	typeKeys(m, "there")
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter did not run the action")
+	}
+	if gotAction != ActionMail || gotAgent != "gastown/witness" || gotInput != "hi there" {
+		t.Errorf("action func got (%s, %s, %q)", gotAction, gotAgent, gotInput)
+	}
+}
+
+func TestKillNeedsConfirmation(t *testing.T) {
+	m := newActionTestModel()
+	calls := 0
+	m.SetActionFunc(func(Action, Agent, string) (*exec.Cmd, error) {
+		calls++
+		return exec.Command("true"), nil
+	})
+	m.moveSelection(1)
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+	typeKeys(m, "n")
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if calls != 0 || m.status != "Cancelled" {
+		t.Fatalf("kill ran without a yes (calls=%d, status=%q)", calls, m.status)
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+	typeKeys(m, "y")
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if calls != 1 {
+		t.Errorf("kill ran %d times after a yes, want 1", calls)
+	}
+}
+
+func TestActionWithoutSelection(t *testing.T) {
+	m := newActionTestModel()
+	m.SetActionFunc(func(Action, Agent, string) (*exec.Cmd, error) {
+		t.Fatal("action ran with nothing selected")
+		return nil, nil
+	})
+
+	if cmd := m.startAction(ActionTranscript); cmd != nil || m.status == "" {
+		t.Errorf("startAction with no selection: cmd=%v status=%q", cmd, m.status)
+	}
+}
@@ -24,6 +24,14 @@ type KeyMap struct {
 	Expand  key.Binding
 	Refresh key.Binding
 
+	// Actions on the selected agent
+	Transcript key.Binding
+	Mail       key.Binding
+	Tag        key.Binding
+	Outcome    key.Binding
+	Kill       key.Binding
+	Resume     key.Binding
+
 	// Search/Filter
 	Search      key.Binding
 	Filter      key.Binding
@@ -93,6 +101,30 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("r"),
 			key.WithHelp("r", "refresh"),
 		),
+		Transcript: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "view transcript"),
+		),
+		Mail: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "send mail"),
+		),
+		Tag: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "tag session"),
+		),
+		Outcome: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "mark outcome"),
+		),
+		Kill: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "kill"),
+		),
+		Resume: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "resume in tmux"),
+		),
 		Search: key.NewBinding(
 			key.WithKeys("/"),
 			key.WithHelp("/", "search"),
@@ -126,6 +158,7 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.PageUp, k.PageDown, k.Top, k.Bottom},
 		{k.Tab, k.FocusTree, k.FocusConvoy, k.FocusFeed, k.Enter, k.Expand},
+		{k.Transcript, k.Mail, k.Tag, k.Outcome, k.Kill, k.Resume},
 		{k.Search, k.Filter, k.ClearFilter, k.Refresh},
 		{k.Help, k.Quit},
 	}
@@ -75,6 +75,14 @@ type Model struct {
 	showHelp bool
 	filter   string
 
+	// Quick actions: the agent selected in the tree (by ID), its line in
+	// the tree, the prompt an action is waiting on, and the last result
+	selected     string
+	selectedLine int
+	actionFunc   ActionFunc
+	prompt       *actionPrompt
+	status       string
+
 	// Event source
 	eventChan <-chan Event
 	done      chan struct{}
@@ -201,6 +209,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tickMsg:
 		cmds = append(cmds, tick())
+
+	case actionDoneMsg:
+		m.finishAction(msg)
+		m.updateViewContent()
 	}
 
 	// Update viewports
@@ -220,6 +232,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKey processes key presses
 func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.prompt != nil {
+		return m, m.handlePromptKey(msg)
+	}
+
 	switch {
 	case key.Matches(msg, m.keys.Quit):
 		m.closeOnce.Do(func() { close(m.done) })
@@ -257,6 +273,27 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Refresh):
 		m.updateViewContent()
 		return m, nil
+
+	case m.focusedPanel == PanelTree && key.Matches(msg, m.keys.Up):
+		m.moveSelection(-1)
+		return m, nil
+
+	case m.focusedPanel == PanelTree && key.Matches(msg, m.keys.Down):
+		m.moveSelection(1)
+		return m, nil
+
+	case key.Matches(msg, m.keys.Transcript):
+		return m, m.startAction(ActionTranscript)
+	case key.Matches(msg, m.keys.Mail):
+		return m, m.startAction(ActionMail)
+	case key.Matches(msg, m.keys.Tag):
+		return m, m.startAction(ActionTag)
+	case key.Matches(msg, m.keys.Outcome):
+		return m, m.startAction(ActionOutcome)
+	case key.Matches(msg, m.keys.Kill):
+		return m, m.startAction(ActionKill)
+	case key.Matches(msg, m.keys.Resume):
+		return m, m.startAction(ActionResume)
 	}
 
 	// Pass to focused viewport
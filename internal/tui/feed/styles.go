@@ -55,6 +55,10 @@ var (
 	AgentIdleStyle = lipgloss.NewStyle().
 			Foreground(colorDim)
 
+	AgentSelectedStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(colorHighlight)
+
 	// Event stream styles
 	StreamPanelStyle = lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
@@ -88,6 +88,7 @@ func (m *Model) renderTree() string {
 	}
 
 	var lines []string
+	m.selectedLine = 0
 
 	// Sort rigs by name
 	rigNames := make([]string, 0, len(m.rigs))
@@ -107,8 +108,7 @@ func (m *Model) renderTree() string {
 		byRole := m.groupAgentsByRole(rig.Agents)
 
 		// Render each role group
-		roleOrder := []string{"mayor", "witness", "refinery", "deacon", "crew", "polecat"}
-		for _, role := range roleOrder {
+		for _, role := range treeRoleOrder {
 			agents, ok := byRole[role]
 			if !ok || len(agents) == 0 {
 				continue
@@ -121,10 +121,19 @@ func (m *Model) renderTree() string {
 
 			// For crew and polecats, show as expandable group
 			if role == "crew" || role == "polecat" {
-				lines = append(lines, m.renderAgentGroup(icon, role, agents))
+				group := m.renderAgentGroup(icon, role, agents)
+				for i, agent := range agents {
+					if agent.ID == m.selected {
+						m.selectedLine = len(lines) + 1 + i // after the group header
+					}
+				}
+				lines = append(lines, group)
 			} else {
 				// Single agents (mayor, witness, refinery)
 				for _, agent := range agents {
+					if agent.ID == m.selected {
+						m.selectedLine = len(lines)
+					}
 					lines = append(lines, m.renderAgent(icon, agent, 2))
 				}
 			}
@@ -197,6 +206,10 @@ func (m *Model) renderAgent(icon string, agent *Agent, indent int) string {
 		nameStyle = AgentActiveStyle
 		statusIndicator = " →"
 	}
+	if agent.ID == m.selected {
+		nameStyle = AgentSelectedStyle
+		prefix = "▸" + strings.TrimPrefix(prefix, " ")
+	}
 
 	// Last activity
 	activity := ""
@@ -319,6 +332,15 @@ func (m *Model) renderStatusBar() string {
 	// Short help
 	help := m.renderShortHelp()
 
+	// An action's prompt takes over the bar; its last result shows
+	// in place of the event count
+	if m.prompt != nil {
+		return StatusBarStyle.Width(m.width).Render(m.prompt.label + m.prompt.input + "█")
+	}
+	if m.status != "" {
+		count = m.status
+	}
+
 	// Combine
 	left := panel + " " + count
 	gap := m.width - lipgloss.Width(left) - lipgloss.Width(help) - 4
@@ -2,8 +2,10 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os/exec"
+	"runtime"
 	"strings"
 )
 
@@ -47,3 +49,18 @@ func ExecRun(workDir, cmd string, args ...string) error {
 
 	return nil
 }
+
+// ShellCommand returns a command that runs script in the platform's
+// shell: sh -c, or cmd /C on Windows.
+func ShellCommand(ctx context.Context, script string) *exec.Cmd {
+	name, args := shellArgs(runtime.GOOS, script)
+	return exec.CommandContext(ctx, name, args...) //nolint:gosec // G204: callers run trusted scripts
+}
+
+// shellArgs returns the shell invocation that runs script on goos.
+func shellArgs(goos, script string) (string, []string) {
+	if goos == "windows" {
+		return "cmd", []string{"/C", script}
+	}
+	return "sh", []string{"-c", script}
+}
@@ -65,3 +65,12 @@ func TestExecWithOutput_StderrInError(t *testing.T) {
 		t.Errorf("expected error to contain stderr, got %q", err.Error())
 	}
 }
+
+func TestShellArgs(t *testing.T) {
+	if name, args := shellArgs("linux", "make test"); name != "sh" || strings.Join(args, " ") != "-c make test" {
+		t.Errorf("linux: %s %v", name, args)
+	}
+	if name, args := shellArgs("windows", "make test"); name != "cmd" || strings.Join(args, " ") != "/C make test" {
+		t.Errorf("windows: %s %v", name, args)
+	}
+}
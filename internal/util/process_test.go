@@ -0,0 +1,15 @@
+package util
+
+import (
+	"os"
+	"testing"
+)
+
+func TestProcessAlive(t *testing.T) {
+	if !ProcessAlive(os.Getpid()) {
+		t.Error("ProcessAlive(self) = false, want true")
+	}
+	if ProcessAlive(-1) {
+		t.Error("ProcessAlive(-1) = true, want false")
+	}
+}
@@ -0,0 +1,30 @@
+package util
+
+import "syscall"
+
+// processQueryLimitedInformation is the least access right that allows
+// GetExitCodeProcess.
+const processQueryLimitedInformation = 0x1000
+
+// stillActive is the exit code Windows reports for running processes.
+const stillActive = 259
+
+// ProcessAlive reports whether a process with the given PID is running.
+// Windows can't signal a process to probe it, so this opens the process
+// and checks that it hasn't exited.
+func ProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		// Access denied means it exists but belongs to another user
+		return err == syscall.ERROR_ACCESS_DENIED
+	}
+	defer syscall.CloseHandle(h) //nolint:errcheck // nothing to do on failure
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime/debug"
 	"strings"
 )
@@ -117,13 +118,13 @@ func GetRepoRoot() (string, error) {
 	}
 
 	// Try common development paths relative to home
-	home := os.Getenv("HOME")
+	home, _ := os.UserHomeDir()
 	if home != "" {
 		candidates := []string{
-			home + "/gt/gastown",
-			home + "/gastown",
-			home + "/src/gastown",
-			home + "/dev/gastown",
+			filepath.Join(home, "gt", "gastown"),
+			filepath.Join(home, "gastown"),
+			filepath.Join(home, "src", "gastown"),
+			filepath.Join(home, "dev", "gastown"),
 		}
 		for _, candidate := range candidates {
 			if isGitRepo(candidate) && hasGastownMarker(candidate) {
@@ -154,8 +155,8 @@ func isGitRepo(dir string) bool {
 // hasGastownMarker checks if a directory looks like the gastown repo.
 func hasGastownMarker(dir string) bool {
 	// Check for cmd/gt directory which is unique to gastown
-	cmd := exec.Command("test", "-d", dir+"/cmd/gt")
-	return cmd.Run() == nil
+	info, err := os.Stat(filepath.Join(dir, "cmd", "gt"))
+	return err == nil && info.IsDir()
 }
 
 // SetCommit allows the cmd package to pass in the build-time commit.
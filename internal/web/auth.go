@@ -0,0 +1,186 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Roles a token can grant. Operators can do everything viewers can.
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+)
+
+// Token is an API token and the role it grants.
+type Token struct {
+	Name   string // who holds it, for the audit log
+	Secret string
+	Role   string // RoleViewer or RoleOperator
+}
+
+// AuditEntry is one line of the API audit log.
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	Token  string    `json:"token,omitempty"` // token name; empty when none matched
+	Role   string    `json:"role,omitempty"`
+	Method string    `json:"method"`
+	Path   string    `json:"path"`
+	Remote string    `json:"remote"`
+	Status int       `json:"status"`
+}
+
+// TokenAuth wraps a handler with bearer-token authentication and role
+// checks. Reads (GET, HEAD, OPTIONS) need a viewer token; anything else,
+// such as spawning or killing a session or sending mail, needs an
+// operator token. The token goes in an "Authorization: Bearer" header or,
+// so browsers can prompt for it, as the basic-auth password.
+//
+// Every request that changes something, and every refused request, is
+// appended to the audit log.
+type TokenAuth struct {
+	next      http.Handler
+	tokens    []Token
+	auditPath string
+	mu        sync.Mutex
+}
+
+// NewTokenAuth returns next behind token auth, auditing to auditPath (a
+// JSONL file; "" disables auditing). Tokens with an empty secret are
+// ignored, so an unset token_env can't open the server.
+func NewTokenAuth(next http.Handler, tokens []Token, auditPath string) (*TokenAuth, error) {
+	a := &TokenAuth{next: next, auditPath: auditPath}
+	for _, t := range tokens {
+		if t.Secret == "" {
+			continue
+		}
+		switch t.Role {
+		case "":
+			t.Role = RoleViewer
+		case RoleViewer, RoleOperator:
+		default:
+			return nil, fmt.Errorf("token %q: unknown role %q (want %s or %s)", t.Name, t.Role, RoleViewer, RoleOperator)
+		}
+		a.tokens = append(a.tokens, t)
+	}
+	if len(a.tokens) == 0 {
+		return nil, fmt.Errorf("no usable tokens (is each token or token_env set?)")
+	}
+	return a, nil
+}
+
+// AuditLogPath returns the API audit log path for a town.
+func AuditLogPath(townRoot string) string {
+	return filepath.Join(townRoot, "logs", "api-audit.jsonl")
+}
+
+// ServeHTTP authenticates and authorizes r before passing it on.
+func (a *TokenAuth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	entry := AuditEntry{Time: time.Now(), Method: r.Method, Path: r.URL.Path, Remote: r.RemoteAddr}
+
+	token, ok := a.lookup(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Gas Town"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		entry.Status = http.StatusUnauthorized
+		a.audit(entry)
+		return
+	}
+	entry.Token, entry.Role = token.Name, token.Role
+
+	if requiredRole(r.Method) == RoleOperator && token.Role != RoleOperator {
+		http.Error(w, "Forbidden: this action needs an operator token", http.StatusForbidden)
+		entry.Status = http.StatusForbidden
+		a.audit(entry)
+		return
+	}
+
+	if !isRead(r.Method) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		a.next.ServeHTTP(rec, r)
+		entry.Status = rec.status
+		a.audit(entry)
+		return
+	}
+	a.next.ServeHTTP(w, r)
+}
+
+// lookup returns the token r presents, if it is one of ours.
+func (a *TokenAuth) lookup(r *http.Request) (Token, bool) {
+	secret := ""
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		secret = strings.TrimPrefix(auth, "Bearer ")
+	} else if _, password, ok := r.BasicAuth(); ok {
+		secret = password
+	}
+	if secret == "" {
+		return Token{}, false
+	}
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(t.Secret)) == 1 {
+			return t, true
+		}
+	}
+	return Token{}, false
+}
+
+// isRead reports whether method only reads.
+func isRead(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// requiredRole is the least role allowed to make a request with method.
+func requiredRole(method string) string {
+	if isRead(method) {
+		return RoleViewer
+	}
+	return RoleOperator
+}
+
+// audit appends e to the audit log. Failures are reported on stderr but
+// don't fail the request, which has already been answered.
+func (a *TokenAuth) audit(e AuditEntry) {
+	if a.auditPath == "" {
+		return
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(a.auditPath), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "audit log: %v\n", err)
+		return
+	}
+	f, err := os.OpenFile(a.auditPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "audit log: %v\n", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		fmt.Fprintf(os.Stderr, "audit log: %v\n", err)
+	}
+}
+
+// statusRecorder captures the status code a handler writes.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
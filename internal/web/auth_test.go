@@ -0,0 +1,91 @@
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTokenAuth(t *testing.T) {
+	auditPath := filepath.Join(t.TempDir(), "logs", "api-audit.jsonl")
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	auth, err := NewTokenAuth(next, []Token{
+		{Name: "alice", Secret: "op-secret", Role: RoleOperator},
+		{Name: "team", Secret: "view-secret"}, // role defaults to viewer
+		{Name: "unset", Secret: ""},           // ignored
+	}, auditPath)
+	if err != nil {
+		t.Fatalf("NewTokenAuth: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		bearer string
+		basic  string
+		want   int
+	}{
+		{"no token", http.MethodGet, "", "", http.StatusUnauthorized},
+		{"wrong token", http.MethodGet, "nope", "", http.StatusUnauthorized},
+		{"viewer reads", http.MethodGet, "view-secret", "", http.StatusNoContent},
+		{"viewer via basic auth", http.MethodGet, "", "view-secret", http.StatusNoContent},
+		{"viewer can't act", http.MethodPost, "view-secret", "", http.StatusForbidden},
+		{"operator acts", http.MethodPost, "op-secret", "", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/api/spawn", nil)
+		if tt.bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.bearer)
+		}
+		if tt.basic != "" {
+			req.SetBasicAuth("anyone", tt.basic)
+		}
+		rec := httptest.NewRecorder()
+		auth.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+
+	// Refusals and actions are audited; successful reads are not
+	f, err := os.Open(auditPath)
+	if err != nil {
+		t.Fatalf("opening audit log: %v", err)
+	}
+	defer f.Close()
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("bad audit line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("got %d audit entries, want 4: %+v", len(entries), entries)
+	}
+	last := entries[3]
+	if last.Token != "alice" || last.Role != RoleOperator || last.Method != http.MethodPost || last.Status != http.StatusNoContent {
+		t.Errorf("operator audit entry = %+v", last)
+	}
+	if entries[2].Token != "team" || entries[2].Status != http.StatusForbidden {
+		t.Errorf("forbidden audit entry = %+v", entries[2])
+	}
+}
+
+func TestNewTokenAuthErrors(t *testing.T) {
+	next := http.NotFoundHandler()
+	if _, err := NewTokenAuth(next, []Token{{Name: "unset"}}, ""); err == nil {
+		t.Error("NewTokenAuth accepted only empty secrets")
+	}
+	if _, err := NewTokenAuth(next, []Token{{Name: "x", Secret: "s", Role: "admin"}}, ""); err == nil {
+		t.Error("NewTokenAuth accepted an unknown role")
+	}
+}
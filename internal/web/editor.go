@@ -0,0 +1,254 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+// ErrNotFound is wrapped by EditorSource errors for things that don't
+// exist, such as an unknown session, so the handler can answer 404.
+var ErrNotFound = errors.New("not found")
+
+// EditorSource defines the interface for the data editor plugins ask for.
+type EditorSource interface {
+	// FileSessions returns the sessions that read or wrote file, most
+	// recently first, at most limit of them (0 = all).
+	FileSessions(file string, limit int) (*FileSessions, error)
+
+	// FileBlame attributes each line of file to the commit, and where
+	// known the session, that last changed it.
+	FileBlame(file string) (*FileBlame, error)
+
+	// TranscriptAt returns up to count turns of a session's transcript
+	// starting at turn.
+	TranscriptAt(session string, turn, count int) (*TranscriptPage, error)
+
+	// SendMail sends mail to an agent address such as "gastown/witness".
+	SendMail(req MailRequest) (*MailResult, error)
+}
+
+// FileSessions is the response to GET /api/sessions.
+type FileSessions struct {
+	File     string               `json:"file"` // path matched against the sessions' files
+	Sessions []claude.FileSession `json:"sessions"`
+}
+
+// BlameHunk is a run of lines last changed by the same commit.
+type BlameHunk struct {
+	Start   int       `json:"start"` // first line, 1-based
+	End     int       `json:"end"`   // last line, inclusive
+	Commit  string    `json:"commit,omitempty"`
+	Author  string    `json:"author,omitempty"`
+	Time    time.Time `json:"time,omitempty"`
+	Summary string    `json:"summary,omitempty"`
+
+	// Uncommitted is set for lines changed in the working tree.
+	Uncommitted bool `json:"uncommitted,omitempty"`
+
+	// SessionID and Role identify the agent session that made the
+	// commit, when one can be found.
+	SessionID string `json:"session_id,omitempty"`
+	Role      string `json:"role,omitempty"`
+}
+
+// FileBlame is the response to GET /api/blame.
+type FileBlame struct {
+	File  string      `json:"file"`
+	Hunks []BlameHunk `json:"hunks"`
+}
+
+// TranscriptPage is the response to GET /api/transcript.
+type TranscriptPage struct {
+	Session claude.SessionInfo      `json:"session"`
+	Start   int                     `json:"start"` // index of the first turn returned
+	Total   int                     `json:"total"` // turns in the transcript
+	Turns   []claude.TranscriptTurn `json:"turns"`
+}
+
+// MailRequest is the body of POST /api/mail.
+type MailRequest struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body,omitempty"`
+}
+
+// MailResult is the response to POST /api/mail.
+type MailResult struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Queued bool   `json:"queued,omitempty"` // accepted but not yet delivered
+}
+
+// Default and maximum page sizes for GET /api/transcript.
+const (
+	defaultTranscriptTurns = 20
+	maxTranscriptTurns     = 200
+)
+
+// EditorHandler serves a JSON API for editor plugins (VS Code, Neovim):
+//
+//	GET  /api/sessions?file=PATH[&limit=N]        sessions that touched a file
+//	GET  /api/blame?file=PATH                     blame by commit and session
+//	GET  /api/transcript?session=ID[&turn=N&count=N]  transcript from a turn
+//	POST /api/mail {"to","subject","body"}        send mail to an agent
+//	GET  /api/health                              liveness check
+//
+// Errors are JSON objects with an "error" field. Mail must be sent as
+// application/json, and not from a page on another host.
+type EditorHandler struct {
+	source EditorSource
+	mux    *http.ServeMux
+}
+
+// NewEditorHandler creates an editor API handler over source.
+func NewEditorHandler(source EditorSource) *EditorHandler {
+	h := &EditorHandler{source: source, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /api/health", h.health)
+	h.mux.HandleFunc("GET /api/sessions", h.sessions)
+	h.mux.HandleFunc("GET /api/blame", h.blame)
+	h.mux.HandleFunc("GET /api/transcript", h.transcript)
+	h.mux.HandleFunc("POST /api/mail", h.mail)
+	return h
+}
+
+// ServeHTTP routes r to the matching endpoint.
+func (h *EditorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *EditorHandler) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+func (h *EditorHandler) sessions(w http.ResponseWriter, r *http.Request) {
+	file := r.URL.Query().Get("file")
+	if file == "" {
+		writeError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	limit, ok := intParam(w, r, "limit", 0)
+	if !ok {
+		return
+	}
+	result, err := h.source.FileSessions(file, limit)
+	h.respond(w, result, err)
+}
+
+func (h *EditorHandler) blame(w http.ResponseWriter, r *http.Request) {
+	file := r.URL.Query().Get("file")
+	if file == "" {
+		writeError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	result, err := h.source.FileBlame(file)
+	h.respond(w, result, err)
+}
+
+func (h *EditorHandler) transcript(w http.ResponseWriter, r *http.Request) {
+	session := r.URL.Query().Get("session")
+	if session == "" {
+		writeError(w, http.StatusBadRequest, "session is required")
+		return
+	}
+	turn, ok := intParam(w, r, "turn", 0)
+	if !ok {
+		return
+	}
+	count, ok := intParam(w, r, "count", defaultTranscriptTurns)
+	if !ok {
+		return
+	}
+	if count == 0 || count > maxTranscriptTurns {
+		count = maxTranscriptTurns
+	}
+	result, err := h.source.TranscriptAt(session, turn, count)
+	h.respond(w, result, err)
+}
+
+func (h *EditorHandler) mail(w http.ResponseWriter, r *http.Request) {
+	// Mail is instructions to an agent, so web pages mustn't be able to
+	// send it. A JSON body can't be posted cross-origin without a CORS
+	// preflight, which this API never grants, and browsers name the page
+	// that sent a request in Origin (editor plugins send none).
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" && !isLoopbackOrigin(origin) {
+		writeError(w, http.StatusForbidden, "cross-origin mail is not allowed")
+		return
+	}
+
+	var req MailRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if req.To == "" || req.Subject == "" {
+		writeError(w, http.StatusBadRequest, "to and subject are required")
+		return
+	}
+	result, err := h.source.SendMail(req)
+	h.respond(w, result, err)
+}
+
+// isLoopbackOrigin reports whether origin is a page served from this
+// machine's loopback interface.
+func isLoopbackOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// respond writes result, or err as a 404 for ErrNotFound and a 500
+// otherwise.
+func (h *EditorHandler) respond(w http.ResponseWriter, result any, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// intParam reads a non-negative integer query parameter, answering 400
+// and reporting false when it is malformed.
+func intParam(w http.ResponseWriter, r *http.Request, name string, def int) (int, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		writeError(w, http.StatusBadRequest, name+" must be a non-negative integer")
+		return 0, false
+	}
+	return n, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
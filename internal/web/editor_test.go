@@ -0,0 +1,140 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+// mockEditorSource records what the handler asked for.
+type mockEditorSource struct {
+	file    string
+	limit   int
+	session string
+	turn    int
+	count   int
+	mail    MailRequest
+}
+
+func (m *mockEditorSource) FileSessions(file string, limit int) (*FileSessions, error) {
+	m.file, m.limit = file, limit
+	return &FileSessions{File: file, Sessions: []claude.FileSession{{Session: claude.SessionInfo{ID: "aaaa1111"}, Count: 2}}}, nil
+}
+
+func (m *mockEditorSource) FileBlame(file string) (*FileBlame, error) {
+	m.file = file
+	return nil, errFetchFailed
+}
+
+func (m *mockEditorSource) TranscriptAt(session string, turn, count int) (*TranscriptPage, error) {
+	m.session, m.turn, m.count = session, turn, count
+	if session == "missing" {
+		return nil, fmt.Errorf("session %s: %w", session, ErrNotFound)
+	}
+	return &TranscriptPage{Start: turn, Total: 50}, nil
+}
+
+func (m *mockEditorSource) SendMail(req MailRequest) (*MailResult, error) {
+	m.mail = req
+	return &MailResult{From: "overseer", To: req.To}, nil
+}
+
+func TestEditorHandler(t *testing.T) {
+	source := &mockEditorSource{}
+	h := NewEditorHandler(source)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   int
+	}{
+		{"health", http.MethodGet, "/api/health", "", http.StatusOK},
+		{"sessions", http.MethodGet, "/api/sessions?file=main.go&limit=5", "", http.StatusOK},
+		{"sessions without file", http.MethodGet, "/api/sessions", "", http.StatusBadRequest},
+		{"bad limit", http.MethodGet, "/api/sessions?file=main.go&limit=-1", "", http.StatusBadRequest},
+		{"blame error", http.MethodGet, "/api/blame?file=main.go", "", http.StatusInternalServerError},
+		{"transcript", http.MethodGet, "/api/transcript?session=aaaa&turn=7", "", http.StatusOK},
+		{"unknown session", http.MethodGet, "/api/transcript?session=missing", "", http.StatusNotFound},
+		{"mail", http.MethodPost, "/api/mail", `{"to":"gastown/witness","subject":"hi"}`, http.StatusOK},
+		{"mail without subject", http.MethodPost, "/api/mail", `{"to":"gastown/witness"}`, http.StatusBadRequest},
+		{"mail by GET", http.MethodGet, "/api/mail", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+
+	if source.file != "main.go" || source.limit != 5 {
+		t.Errorf("FileSessions got file=%q limit=%d", source.file, source.limit)
+	}
+	if source.mail.To != "gastown/witness" || source.mail.Subject != "hi" {
+		t.Errorf("SendMail got %+v", source.mail)
+	}
+	if source.turn != 0 || source.count != defaultTranscriptTurns {
+		t.Errorf("TranscriptAt defaults: turn=%d count=%d", source.turn, source.count)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/transcript?session=missing", nil))
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || !strings.Contains(body["error"], "not found") {
+		t.Errorf("error body = %s, %v", rec.Body, err)
+	}
+}
+
+func TestEditorMailRejectsBrowserForgery(t *testing.T) {
+	source := &mockEditorSource{}
+	h := NewEditorHandler(source)
+	body := `{"to":"gastown/witness","subject":"rm -rf"}`
+
+	tests := []struct {
+		name        string
+		contentType string
+		origin      string
+		want        int
+	}{
+		{"form post", "text/plain", "", http.StatusUnsupportedMediaType},
+		{"no content type", "", "", http.StatusUnsupportedMediaType},
+		{"other site", "application/json", "https://evil.example", http.StatusForbidden},
+		{"rebound hostname", "application/json", "http://evil.example:7878", http.StatusForbidden},
+		{"local page", "application/json; charset=utf-8", "http://localhost:7878", http.StatusOK},
+		{"loopback page", "application/json", "http://127.0.0.1:7878", http.StatusOK},
+		{"editor plugin", "application/json", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source.mail = MailRequest{}
+			req := httptest.NewRequest(http.MethodPost, "/api/mail", strings.NewReader(body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body)
+			}
+			if sent := source.mail.To != ""; sent != (tt.want == http.StatusOK) {
+				t.Errorf("mail sent = %v", sent)
+			}
+		})
+	}
+}
@@ -10,11 +10,14 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/activity"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // LiveConvoyFetcher fetches convoy data from beads.
 type LiveConvoyFetcher struct {
+	townRoot  string
 	townBeads string
 }
 
@@ -26,10 +29,32 @@ func NewLiveConvoyFetcher() (*LiveConvoyFetcher, error) {
 	}
 
 	return &LiveConvoyFetcher{
+		townRoot:  townRoot,
 		townBeads: filepath.Join(townRoot, ".beads"),
 	}, nil
 }
 
+// lastSeen returns the later of a tmux activity time and the agent's most
+// recent heartbeat, so agents busy in long tool calls still show as alive.
+func (f *LiveConvoyFetcher) lastSeen(actor string, tmuxActivity time.Time, heartbeats map[string]events.Heartbeat) time.Time {
+	if hb, ok := heartbeats[actor]; ok && hb.Timestamp.After(tmuxActivity) {
+		return hb.Timestamp
+	}
+	return tmuxActivity
+}
+
+// loadHeartbeats returns agent heartbeats, or nil if they can't be read.
+func (f *LiveConvoyFetcher) loadHeartbeats() map[string]events.Heartbeat {
+	if f.townRoot == "" {
+		return nil
+	}
+	heartbeats, err := events.LoadHeartbeats(f.townRoot)
+	if err != nil {
+		return nil
+	}
+	return heartbeats
+}
+
 
 // FetchConvoys fetches all open convoys with their activity data.
 func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
@@ -300,9 +325,14 @@ func (f *LiveConvoyFetcher) getWorkersFromAssignees(details map[string]*issueDet
 		return result
 	}
 
-	// For each unique assignee, look up tmux session activity
+	// For each unique assignee, look up tmux session activity and heartbeats
+	heartbeats := f.loadHeartbeats()
 	for assignee, issueIDs := range assigneeToIssues {
 		activity := f.getSessionActivityForAssignee(assignee)
+		if hb, ok := heartbeats[assignee]; ok && (activity == nil || hb.Timestamp.After(*activity)) {
+			ts := hb.Timestamp
+			activity = &ts
+		}
 		if activity == nil {
 			continue
 		}
@@ -605,6 +635,7 @@ func (f *LiveConvoyFetcher) FetchPolecats() ([]PolecatRow, error) {
 
 	// Pre-fetch merge queue count to determine refinery idle status
 	mergeQueueCount := f.getMergeQueueCount()
+	heartbeats := f.loadHeartbeats()
 
 	var polecats []PolecatRow
 	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
@@ -646,6 +677,11 @@ func (f *LiveConvoyFetcher) FetchPolecats() ([]PolecatRow, error) {
 			continue
 		}
 		activityTime := time.Unix(activityUnix, 0)
+		actor := rig + "/polecats/" + polecat
+		if polecat == "refinery" {
+			actor = rig + "/refinery"
+		}
+		activityTime = f.lastSeen(actor, activityTime, heartbeats)
 
 		// Get status hint - special handling for refinery
 		var statusHint string
@@ -667,6 +703,42 @@ func (f *LiveConvoyFetcher) FetchPolecats() ([]PolecatRow, error) {
 	return polecats, nil
 }
 
+// overlapWindow is how recently sessions must have been active for the
+// dashboard to compare them for duplicate work.
+const overlapWindow = 24 * time.Hour
+
+// FetchOverlaps flags concurrently active sessions in different rigs that
+// edited the same files or share a topic (see claude.FindOverlaps).
+func (f *LiveConvoyFetcher) FetchOverlaps() ([]OverlapRow, error) {
+	sessions, err := claude.DiscoverSessions(claude.SessionFilter{
+		GasTownOnly: true,
+		Since:       time.Now().Add(-2 * overlapWindow),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []OverlapRow
+	for _, o := range claude.FindOverlaps(sessions, claude.OverlapOptions{Window: overlapWindow}) {
+		rows = append(rows, OverlapRow{
+			A:           overlapSession(o.A),
+			B:           overlapSession(o.B),
+			SharedFiles: o.SharedFiles,
+			Similarity:  int(o.TopicSimilarity * 100),
+		})
+	}
+	return rows, nil
+}
+
+// overlapSession describes one side of an overlap.
+func overlapSession(s claude.SessionInfo) OverlapSession {
+	topic := s.Summary
+	if topic == "" {
+		topic = s.Topic
+	}
+	return OverlapSession{Role: s.Role, SessionID: s.ShortID(), Topic: topic}
+}
+
 // getPolecatStatusHint captures the last non-empty line from a polecat's pane.
 func (f *LiveConvoyFetcher) getPolecatStatusHint(sessionName string) string {
 	cmd := exec.Command("tmux", "capture-pane", "-t", sessionName, "-p", "-J")
@@ -10,6 +10,7 @@ type ConvoyFetcher interface {
 	FetchConvoys() ([]ConvoyRow, error)
 	FetchMergeQueue() ([]MergeQueueRow, error)
 	FetchPolecats() ([]PolecatRow, error)
+	FetchOverlaps() ([]OverlapRow, error)
 }
 
 // ConvoyHandler handles HTTP requests for the convoy dashboard.
@@ -51,10 +52,17 @@ func (h *ConvoyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		polecats = nil
 	}
 
+	overlaps, err := h.fetcher.FetchOverlaps()
+	if err != nil {
+		// Non-fatal: the duplicate-work check is advisory
+		overlaps = nil
+	}
+
 	data := ConvoyData{
 		Convoys:    convoys,
 		MergeQueue: mergeQueue,
 		Polecats:   polecats,
+		Overlaps:   overlaps,
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -20,6 +20,7 @@ type MockConvoyFetcher struct {
 	Convoys    []ConvoyRow
 	MergeQueue []MergeQueueRow
 	Polecats   []PolecatRow
+	Overlaps   []OverlapRow
 	Error      error
 }
 
@@ -35,6 +36,10 @@ func (m *MockConvoyFetcher) FetchPolecats() ([]PolecatRow, error) {
 	return m.Polecats, nil
 }
 
+func (m *MockConvoyFetcher) FetchOverlaps() ([]OverlapRow, error) {
+	return m.Overlaps, nil
+}
+
 func TestConvoyHandler_RendersTemplate(t *testing.T) {
 	mock := &MockConvoyFetcher{
 		Convoys: []ConvoyRow{
@@ -386,6 +391,61 @@ func TestConvoyHandler_PolecatWorkersRendering(t *testing.T) {
 
 // Integration tests for work status rendering
 
+func TestConvoyHandler_OverlapsRendering(t *testing.T) {
+	mock := &MockConvoyFetcher{
+		Convoys: []ConvoyRow{},
+		Overlaps: []OverlapRow{
+			{
+				A:           OverlapSession{Role: "gastown/crew/joe", SessionID: "aaaa1111", Topic: "Rewrite retry library"},
+				B:           OverlapSession{Role: "beads/crew/max", SessionID: "bbbb2222", Topic: "Add jitter to backoff"},
+				SharedFiles: []string{"lib/retry/backoff.go", "lib/retry/retry.go"},
+			},
+			{
+				A:          OverlapSession{Role: "gastown/crew/joe", SessionID: "aaaa1111"},
+				B:          OverlapSession{Role: "wyvern/crew/sam", SessionID: "cccc3333"},
+				Similarity: 60,
+			},
+		},
+	}
+
+	handler, err := NewConvoyHandler(mock)
+	if err != nil {
+		t.Fatalf("NewConvoyHandler() error = %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	for _, want := range []string{
+		"Possible Duplicate Work",
+		"beads/crew/max",
+		"2 shared files",
+		"lib/retry/backoff.go",
+		"similar topics (60%)",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Response should contain %q", want)
+		}
+	}
+}
+
+func TestConvoyHandler_NoOverlapsSection(t *testing.T) {
+	handler, err := NewConvoyHandler(&MockConvoyFetcher{})
+	if err != nil {
+		t.Fatalf("NewConvoyHandler() error = %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if strings.Contains(w.Body.String(), "Possible Duplicate Work") {
+		t.Error("Response should omit the duplicate work section when there are no overlaps")
+	}
+}
+
 func TestConvoyHandler_WorkStatusRendering(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -964,6 +1024,10 @@ func (m *MockConvoyFetcherWithErrors) FetchPolecats() ([]PolecatRow, error) {
 	return nil, m.PolecatsError
 }
 
+func (m *MockConvoyFetcherWithErrors) FetchOverlaps() ([]OverlapRow, error) {
+	return nil, errFetchFailed
+}
+
 func TestConvoyHandler_NonFatalErrors(t *testing.T) {
 	mock := &MockConvoyFetcherWithErrors{
 		Convoys: []ConvoyRow{
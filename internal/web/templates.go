@@ -17,6 +17,22 @@ type ConvoyData struct {
 	Convoys    []ConvoyRow
 	MergeQueue []MergeQueueRow
 	Polecats   []PolecatRow
+	Overlaps   []OverlapRow
+}
+
+// OverlapRow is a pair of sessions in different rigs that look like they
+// are doing the same work.
+type OverlapRow struct {
+	A, B        OverlapSession
+	SharedFiles []string // files both edited, relative to their worktrees
+	Similarity  int      // topic similarity, percent
+}
+
+// OverlapSession is one side of an OverlapRow.
+type OverlapSession struct {
+	Role      string // e.g., "gastown/crew/joe"
+	SessionID string // short session ID
+	Topic     string // summary or beacon topic
 }
 
 // PolecatRow represents a polecat worker in the dashboard.